@@ -13,6 +13,7 @@ import (
 	"lectura-backend/internal/config"
 	"lectura-backend/internal/database"
 	"lectura-backend/internal/handlers"
+	"lectura-backend/internal/metrics"
 	"lectura-backend/internal/middleware"
 	"lectura-backend/internal/repository"
 	"lectura-backend/internal/router"
@@ -61,6 +62,38 @@ func main() {
 	studySessionRepo := repository.NewStudySessionRepo(pool)
 	chatMessageRepo := repository.NewChatMessageRepo(pool)
 	folderRepo := repository.NewFolderRepo(pool)
+	trashRepo := repository.NewTrashRepo(pool)
+	exportRepo := repository.NewExportRepo(pool)
+	apiKeyRepo := repository.NewAPIKeyRepo(pool)
+	studyPlanRepo := repository.NewStudyPlanRepo(pool)
+	usageRepo := repository.NewUsageRepo(pool)
+	summaryChunkRepo := repository.NewSummaryChunkRepo(pool)
+	summaryVersionRepo := repository.NewSummaryVersionRepo(pool)
+	libraryRepo := repository.NewLibraryRepo(pool)
+
+	textCipher, err := repository.NewTextCipher(cfg.ContentEncryptionKey, cfg.ContentEncryptionOldKeys...)
+	if err != nil {
+		log.Fatalf(" Content encryption setup failed: %v", err)
+	}
+	if textCipher != nil {
+		contentRepo.SetTextCipher(textCipher)
+		summaryRepo.SetTextCipher(textCipher)
+		exportRepo.SetTextCipher(textCipher)
+		summaryChunkRepo.SetTextCipher(textCipher)
+		summaryVersionRepo.SetTextCipher(textCipher)
+		log.Println(" Content encryption at rest enabled")
+		go func() {
+			n, err := summaryRepo.EncryptPlaintext(context.Background(), 200)
+			if err != nil {
+				log.Printf("WARNING: failed to encrypt existing summaries: %v", err)
+			}
+			if n > 0 {
+				log.Printf(" Encrypted %d summary rows stored before encryption was enabled", n)
+			}
+		}()
+	} else {
+		log.Println("WARNING: CONTENT_ENCRYPTION_KEY is not set; original transcripts will not be retained for users who ask for it")
+	}
 
 	// ──── Step 5: Initialize Gemini Client ────
 	geminiService, err := services.NewGeminiService(
@@ -80,13 +113,49 @@ func main() {
 		log.Fatalf("✗ Gemini client initialization failed: %v", err)
 	}
 	defer geminiService.Close()
-	log.Println("✓ Gemini Flash client initialized")
+	geminiService.SetQuizDedupThreshold(cfg.QuizDedupThreshold)
+	geminiService.SetMaxTranscriptChars(cfg.MaxTranscriptChars)
+	geminiService.SetSummaryChunkChars(cfg.SummaryChunkChars)
+	geminiService.SetDebugLogging(cfg.LogLevel == "debug")
+	geminiService.SetUsageRecorder(usageRepo)
+	if err := geminiService.SetModelConfig(services.GeminiModelConfig{
+		Model:                 cfg.GeminiModel,
+		Temperature:           float32(cfg.GeminiTemperature),
+		TopP:                  float32(cfg.GeminiTopP),
+		ProModel:              cfg.GeminiProModel,
+		ProMinTranscriptChars: cfg.GeminiProTranscriptChars,
+		ProPlans:              cfg.GeminiProPlans,
+		EmbeddingModel:        cfg.GeminiEmbeddingModel,
+	}); err != nil {
+		log.Fatalf("✗ Gemini model configuration invalid: %v", err)
+	}
+	log.Printf("✓ Gemini client initialized (%s)", cfg.GeminiModel)
 
 	// ──── Initialize Services ────
-	jwtAuth := middleware.NewJWTAuth(cfg.JWTSecret)
+	jwtAuth := middleware.NewJWTAuth(cfg.JWTSecret).WithAPIKeys(services.NewAPIKeyService(apiKeyRepo))
 	emailService := services.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, cfg.SMTPFrom, cfg.FrontendURL)
-	youtubeService := services.NewYouTubeService(cfg.SupadataAPIKey)
+	youtubeService := services.NewYouTubeService(cfg.SupadataAPIKey).WithCache(
+		redisClients.Queue,
+		cfg.YouTubeMetadataCacheTTL,
+		cfg.YouTubeTranscriptCacheTTL,
+		cfg.YouTubeNoCaptionsCacheTTL,
+	)
 	fileExtractService := services.NewFileExtractService()
+	if !fileExtractService.CanRenderPDFPages() {
+		log.Printf("WARNING: %v", services.ErrPDFRendererMissing)
+	}
+	uploadStorage, err := services.NewStorage(cfg.StorageType, cfg.StoragePath, services.S3Config{
+		Bucket:          cfg.S3Bucket,
+		Region:          cfg.S3Region,
+		Endpoint:        cfg.S3Endpoint,
+		AccessKeyID:     cfg.S3AccessKeyID,
+		SecretAccessKey: cfg.S3SecretAccessKey,
+		PathStyle:       cfg.S3ForcePathStyle,
+	})
+	if err != nil {
+		log.Fatalf("✗ Upload storage setup failed: %v", err)
+	}
+	log.Printf("✓ Upload storage initialized (%s)", cfg.StorageType)
 	authService := services.NewAuthService(
 		userRepo,
 		redisClients.Queue,
@@ -98,25 +167,69 @@ func main() {
 	)
 	stripeService := services.NewStripeService()
 
-	quotaService := services.NewQuotaService(pool)
+	quotaService := services.NewQuotaService(pool).WithPlanLimits(services.NewPlanCreditLimits(cfg.PlanMonthlyCredits))
 
 	// ──── Initialize Handlers ────
+	// Every handler pushes to the same queue client, so they share its breaker.
+	queueBreaker := handlers.NewQueueBreaker()
 	authHandler := handlers.NewAuthHandler(authService, cfg.FrontendURL, cfg.Env == "production")
 	wsTicketHandler := handlers.NewWSTicketHandler(redisClients.Queue)
-	contentHandler := handlers.NewContentHandler(contentRepo, jobRepo, redisClients.Queue, cfg.StoragePath, youtubeService)
-	summaryHandler := handlers.NewSummaryHandler(summaryRepo, contentRepo, jobRepo, redisClients.Queue, quotaService, userRepo)
+	uploadPolicy := services.NewUploadPolicy(int64(cfg.UploadMaxSizeMB)*1024*1024, cfg.UploadAllowedExtensions)
+	contentHandler := handlers.NewContentHandler(contentRepo, jobRepo, userRepo, redisClients.Queue, uploadStorage, youtubeService, uploadPolicy, services.NewDownloadSigner(cfg.JWTSecret, cfg.DownloadURLTTL))
+	contentHandler.SetQueueBreaker(queueBreaker)
+	contentHandler.SetPlaylistLimit(cfg.YouTubePlaylistMaxVideos)
+	summaryHandler := handlers.NewSummaryHandler(summaryRepo, contentRepo, jobRepo, redisClients.Queue, quotaService, userRepo, geminiService)
+	summaryHandler.SetQueueBreaker(queueBreaker)
+	summaryHandler.SetVersionHistory(summaryVersionRepo, cfg.SummaryVersionLimit)
 	presentationHandler := handlers.NewPresentationHandler(presentationRepo, contentRepo, jobRepo, redisClients.Queue, quotaService, userRepo)
+	presentationHandler.SetQueueBreaker(queueBreaker)
 	quizHandler := handlers.NewQuizHandler(quizRepo, summaryRepo, jobRepo, redisClients.Queue, quotaService, userRepo)
-	flashcardHandler := handlers.NewFlashcardHandler(flashcardRepo, summaryRepo, jobRepo, redisClients.Queue, quotaService, userRepo)
+	quizHandler.SetQueueBreaker(queueBreaker)
+	quizHandler.SetQuestionReports(quizRepo)
+	quizHandler.SetEssayGrader(geminiService)
+	quizQuestionHandler := handlers.NewQuizQuestionHandler(quizRepo)
+	flashcardHandler := handlers.NewFlashcardHandler(flashcardRepo, summaryRepo, contentRepo, jobRepo, redisClients.Queue, quotaService, userRepo)
+	flashcardHandler.SetQueueBreaker(queueBreaker)
 	studySessionHandler := handlers.NewStudySessionHandler(studySessionRepo)
 	dashboardHandler := handlers.NewDashboardHandler(pool, userRepo)
-	libraryHandler := handlers.NewLibraryHandler(pool)
+	libraryHandler := handlers.NewLibraryHandler(pool, libraryRepo)
 	userHandler := handlers.NewUserHandler(userRepo, quotaService, cfg.JWTSecret)
+	userHandler.SetDataExports(jobRepo, uploadStorage)
 	jobHandler := handlers.NewJobHandler(jobRepo, summaryRepo, quizRepo, flashcardRepo, presentationRepo)
+	jobHandler.SetProgressStore(redisClients.Queue)
 	screenOCRService := services.NewScreenOCRService(contentRepo, youtubeService, geminiService)
+	summarySearchService := services.NewSummarySearchService(summaryChunkRepo, geminiService, contentRepo)
 	chatHandler := handlers.NewChatHandler(summaryRepo, chatMessageRepo, geminiService, contentRepo, screenOCRService)
+	chatHandler.SetSummarySearch(summarySearchService)
 	billingHandler := handlers.NewBillingHandler(stripeService, userRepo)
 	folderHandler := handlers.NewFolderHandler(folderRepo)
+	trashHandler := handlers.NewTrashHandler(trashRepo)
+	exportHandler := handlers.NewExportHandler(exportRepo, jobRepo, redisClients.Queue, uploadStorage, cfg.DataExportSyncMaxRows)
+	exportHandler.SetQueueBreaker(queueBreaker)
+	outlineHandler := handlers.NewOutlineHandler(summaryRepo, geminiService)
+	summaryHTMLHandler := handlers.NewSummaryHTMLHandler(summaryRepo)
+	summarySearchHandler := handlers.NewSummarySearchHandler(summaryRepo, summarySearchService)
+	summaryRelatedHandler := handlers.NewSummaryRelatedHandler(summaryRepo)
+	summaryVersionHandler := handlers.NewSummaryVersionHandler(summaryRepo, summaryVersionRepo, cfg.SummaryVersionLimit)
+	adminHandler := handlers.NewAdminHandler(jobRepo, userRepo, redisClients.Queue, geminiService, cfg.AdminEmails, cfg.StuckJobThreshold)
+	adminHandler.SetQueueBreaker(queueBreaker)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	sessionHandler := handlers.NewSessionHandler(authService)
+	shareHandler := handlers.NewShareHandler(flashcardRepo, quizRepo)
+	studyPlanHandler := handlers.NewStudyPlanHandler(studyPlanRepo, geminiService)
+	usageHandler := handlers.NewUsageHandler(usageRepo)
+	healthHandler := handlers.NewHealthHandler(cfg.HealthCheckTimeout,
+		handlers.HealthCheck{Name: "postgres", Check: pool.Ping},
+		handlers.HealthCheck{Name: "redis", Check: func(ctx context.Context) error {
+			return redisClients.Queue.Ping(ctx).Err()
+		}},
+		handlers.HealthCheck{Name: "redis_pubsub", Check: func(ctx context.Context) error {
+			return redisClients.PubSub.Ping(ctx).Err()
+		}},
+		handlers.HealthCheck{Name: "gemini", Check: func(context.Context) error {
+			return geminiService.Ready()
+		}},
+	)
 
 	// ──── Step 6: Start Job Worker Pool ────
 	workerPool := worker.NewPool(
@@ -132,14 +245,36 @@ func main() {
 		presentationRepo,
 		quizRepo,
 		flashcardRepo,
-		cfg.StoragePath,
-		5,
+		exportRepo,
+		uploadStorage,
+		uploadPolicy,
+		cfg.WorkerCount,
+		worker.NewQueueMinimums(cfg.WorkerQueueMinimums),
 		cfg.ContentReadyTimeout,
-	)
+		cfg.StuckJobThreshold,
+		worker.NewRetryPolicies(worker.RetryPolicy{MaxRetries: cfg.JobMaxRetries, BaseBackoff: cfg.JobRetryBackoff, MaxBackoff: cfg.JobRetryMaxBackoff}, cfg.JobRetryPolicies),
+	).WithQuota(quotaService).WithJobTimeouts(worker.NewJobTimeouts(cfg.JobTimeout, cfg.JobTimeouts, cfg.StuckJobThreshold))
 	workerPool.Start()
-	log.Println("✓ Worker pool started (5 goroutines)")
+	log.Println("✓ Worker pool started")
+
+	metrics.RegisterGauge("lectura_queue_length", "Jobs waiting in each Redis queue", func(ctx context.Context) []metrics.Sample {
+		var samples []metrics.Sample
+		for queue, n := range worker.QueueLengths(ctx, redisClients.Queue) {
+			samples = append(samples, metrics.Sample{Labels: [][2]string{{"queue", queue}}, Value: float64(n)})
+		}
+		return samples
+	})
+	metrics.RegisterGauge("lectura_gemini_rate_slots", "Gemini concurrency slots by state", func(context.Context) []metrics.Sample {
+		stats := geminiService.RateStats()
+		return []metrics.Sample{
+			{Labels: [][2]string{{"state", "capacity"}}, Value: float64(stats.Capacity)},
+			{Labels: [][2]string{{"state", "in_use"}}, Value: float64(stats.InUse)},
+			{Labels: [][2]string{{"state", "available"}}, Value: float64(stats.Available)},
+			{Labels: [][2]string{{"state", "waiting"}}, Value: float64(stats.Waiting)},
+		}
+	})
 
-	notificationScheduler := services.NewNotificationScheduler(userRepo, emailService)
+	notificationScheduler := services.NewNotificationScheduler(userRepo, emailService).WithTrashPurge(trashRepo)
 	notificationScheduler.Start()
 	log.Println("✓ Notification scheduler started")
 
@@ -156,6 +291,7 @@ func main() {
 		summaryHandler,
 		presentationHandler,
 		quizHandler,
+		quizQuestionHandler,
 		flashcardHandler,
 		studySessionHandler,
 		dashboardHandler,
@@ -165,6 +301,20 @@ func main() {
 		chatHandler,
 		billingHandler,
 		folderHandler,
+		trashHandler,
+		exportHandler,
+		outlineHandler,
+		adminHandler,
+		apiKeyHandler,
+		sessionHandler,
+		shareHandler,
+		healthHandler,
+		studyPlanHandler,
+		usageHandler,
+		summaryHTMLHandler,
+		summarySearchHandler,
+		summaryRelatedHandler,
+		summaryVersionHandler,
 		wsHub,
 		cfg.FrontendURL,
 		cfg.TrustedProxyCIDRs,
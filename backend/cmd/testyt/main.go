@@ -19,7 +19,7 @@ func main() {
 
 	for _, id := range testIDs {
 		fmt.Printf("\n=== Testing video: %s ===\n", id)
-		transcript, err := yt.GetTranscript(context.Background(), id)
+		transcript, err := yt.GetTranscript(context.Background(), id, "")
 		if err != nil {
 			fmt.Printf("ERROR: %v\n", err)
 		} else {
@@ -12,8 +12,9 @@ import (
 
 type Config struct {
 	// Server
-	Port string
-	Env  string
+	Port     string
+	Env      string
+	LogLevel string // "debug" also logs raw model output that fails to parse
 
 	// Database
 	DatabaseURL string
@@ -24,6 +25,10 @@ type Config struct {
 	// JWT
 	JWTSecret string
 
+	// Encryption at rest for transcripts and summary bodies; empty disables it
+	ContentEncryptionKey     string
+	ContentEncryptionOldKeys []string
+
 	// Gemini AI
 	GeminiAPIKey         string
 	SupadataAPIKey       string
@@ -31,11 +36,85 @@ type Config struct {
 	GeminiTokensPerMin   int
 	GeminiConcurrentReqs int
 
+	// Gemini model: name and sampling settings for every generation, plus a
+	// more capable model for long transcripts or listed plans' summaries, and
+	// the model that embeds summary chunks
+	GeminiModel              string
+	GeminiTemperature        float64
+	GeminiTopP               float64
+	GeminiProModel           string
+	GeminiProTranscriptChars int
+	GeminiProPlans           []string
+	GeminiEmbeddingModel     string
+
+	// YouTube cache
+	YouTubeMetadataCacheTTL   time.Duration
+	YouTubeTranscriptCacheTTL time.Duration
+	YouTubeNoCaptionsCacheTTL time.Duration
+	YouTubePlaylistMaxVideos  int
+
 	// Storage
 	StorageType         string
 	StoragePath         string
 	ContentReadyTimeout time.Duration
 
+	// S3-compatible bucket for uploads when StorageType is "s3"; the endpoint
+	// is only needed for services other than AWS (MinIO, R2, ...)
+	S3Bucket          string
+	S3Region          string
+	S3Endpoint        string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3ForcePathStyle  bool
+
+	// Uploads: size cap and allowed file extensions (empty means all supported types)
+	UploadMaxSizeMB         int
+	UploadAllowedExtensions []string
+
+	// Signed links to uploaded files stay valid this long
+	DownloadURLTTL time.Duration
+
+	// Data export: accounts with more rows than this are exported in the background
+	DataExportSyncMaxRows int
+
+	// Admin: extra admin accounts (besides the "admin" plan) and how long a job
+	// may sit in 'processing' before it is considered stuck
+	AdminEmails       []string
+	StuckJobThreshold time.Duration
+
+	// Job workers: pool size and workers reserved per queue ("type:workers")
+	WorkerCount         int
+	WorkerQueueMinimums []string
+
+	// Job retries: default attempts/backoff, the longest wait between
+	// attempts, plus per-type overrides ("type:max_retries[:base_backoff_seconds]")
+	JobMaxRetries      int
+	JobRetryBackoff    time.Duration
+	JobRetryMaxBackoff time.Duration
+	JobRetryPolicies   []string
+
+	// Job deadlines: how long one attempt may run, plus per-type overrides
+	// ("type:seconds"); keep them below StuckJobThreshold
+	JobTimeout  time.Duration
+	JobTimeouts []string
+
+	// Monthly generation credits per plan ("plan:credits"), on top of the
+	// built-in free/pro/ultra limits
+	PlanMonthlyCredits []string
+
+	// Quiz generation: similarity (0-1] at which a question repeats one
+	// generated earlier for the same summary
+	QuizDedupThreshold float64
+
+	// Summary generation: transcripts longer than this many characters are
+	// summarized from their beginning and the summary is flagged as partial
+	MaxTranscriptChars int
+	// Transcripts longer than this many characters are summarized in parts
+	// whose notes are then merged into one summary
+	SummaryChunkChars int
+	// Earlier versions kept per summary for rolling back a regeneration
+	SummaryVersionLimit int
+
 	// SMTP
 	SMTPHost string
 	SMTPPort string
@@ -50,6 +129,9 @@ type Config struct {
 	// Proxy trust (for forwarded headers)
 	TrustedProxyCIDRs []string
 
+	// Readiness probe: per-dependency check timeout
+	HealthCheckTimeout time.Duration
+
 	// Google OAuth
 	GoogleClientID     string
 	GoogleClientSecret string
@@ -61,30 +143,70 @@ func Load() *Config {
 	godotenv.Load()
 
 	cfg := &Config{
-		Port:                 getEnvOrDefault("PORT", "8080"),
-		Env:                  getEnvOrDefault("ENV", "development"),
-		DatabaseURL:          mustGetEnv("DATABASE_URL"),
-		RedisURL:             mustGetEnv("REDIS_URL"),
-		JWTSecret:            mustGetEnv("JWT_SECRET"),
-		GeminiAPIKey:         mustGetEnv("GEMINI_API_KEY"),
-		SupadataAPIKey:       os.Getenv("SUPADATA_API_KEY"),
-		GeminiRequestsPerMin: getEnvAsIntOrDefault("GEMINI_REQUESTS_PER_MINUTE", 60),
-		GeminiTokensPerMin:   getEnvAsIntOrDefault("GEMINI_TOKENS_PER_MINUTE", 1000000),
-		GeminiConcurrentReqs: getEnvAsIntOrDefault("GEMINI_CONCURRENT_REQUESTS", 5),
-		StorageType:          getEnvOrDefault("STORAGE_TYPE", "local"),
-		StoragePath:          getEnvOrDefault("STORAGE_PATH", "./uploads"),
-		ContentReadyTimeout:  time.Duration(getEnvAsIntOrDefault("CONTENT_READY_TIMEOUT_SECONDS", 120)) * time.Second,
-		SMTPHost:             getEnvOrDefault("SMTP_HOST", ""),
-		SMTPPort:             getEnvOrDefault("SMTP_PORT", "587"),
-		SMTPUser:             getEnvOrDefault("SMTP_USER", ""),
-		SMTPPass:             getEnvOrDefault("SMTP_PASS", ""),
-		SMTPFrom:             getEnvOrDefault("SMTP_FROM", "noreply@lectura.app"),
-		FrontendURL:          getEnvOrDefault("FRONTEND_URL", "http://localhost:5173"),
-		UnsplashAccessKey:    os.Getenv("UNSPLASH_ACCESS_KEY"),
-		TrustedProxyCIDRs:    getEnvAsCSV("TRUSTED_PROXY_CIDRS"),
-		GoogleClientID:       getEnvOrDefault("GOOGLE_CLIENT_ID", ""),
-		GoogleClientSecret:   getEnvOrDefault("GOOGLE_CLIENT_SECRET", ""),
-		GoogleRedirectURI:    getEnvOrDefault("GOOGLE_REDIRECT_URI", ""),
+		Port:                      getEnvOrDefault("PORT", "8080"),
+		Env:                       getEnvOrDefault("ENV", "development"),
+		LogLevel:                  getEnvOrDefault("LOG_LEVEL", "info"),
+		DatabaseURL:               mustGetEnv("DATABASE_URL"),
+		RedisURL:                  mustGetEnv("REDIS_URL"),
+		JWTSecret:                 mustGetEnv("JWT_SECRET"),
+		ContentEncryptionKey:      os.Getenv("CONTENT_ENCRYPTION_KEY"),
+		ContentEncryptionOldKeys:  getEnvAsCSV("CONTENT_ENCRYPTION_PREVIOUS_KEYS"),
+		GeminiAPIKey:              mustGetEnv("GEMINI_API_KEY"),
+		SupadataAPIKey:            os.Getenv("SUPADATA_API_KEY"),
+		GeminiRequestsPerMin:      getEnvAsIntOrDefault("GEMINI_REQUESTS_PER_MINUTE", 60),
+		GeminiTokensPerMin:        getEnvAsIntOrDefault("GEMINI_TOKENS_PER_MINUTE", 1000000),
+		GeminiConcurrentReqs:      getEnvAsIntOrDefault("GEMINI_CONCURRENT_REQUESTS", 5),
+		GeminiModel:               getEnvOrDefault("GEMINI_MODEL", "gemini-3-flash-preview"),
+		GeminiTemperature:         getEnvAsFloatOrDefault("GEMINI_TEMPERATURE", 0.3),
+		GeminiTopP:                getEnvAsFloatOrDefault("GEMINI_TOP_P", 0.95),
+		GeminiProModel:            getEnvOrDefault("GEMINI_PRO_MODEL", ""),
+		GeminiProTranscriptChars:  getEnvAsIntOrDefault("GEMINI_PRO_MIN_TRANSCRIPT_CHARS", 0),
+		GeminiProPlans:            getEnvAsCSV("GEMINI_PRO_PLANS"),
+		GeminiEmbeddingModel:      getEnvOrDefault("GEMINI_EMBEDDING_MODEL", "text-embedding-004"),
+		YouTubeMetadataCacheTTL:   time.Duration(getEnvAsIntOrDefault("YOUTUBE_METADATA_CACHE_TTL_SECONDS", 3600)) * time.Second,
+		YouTubeTranscriptCacheTTL: time.Duration(getEnvAsIntOrDefault("YOUTUBE_TRANSCRIPT_CACHE_TTL_SECONDS", 7*24*3600)) * time.Second,
+		YouTubeNoCaptionsCacheTTL: time.Duration(getEnvAsIntOrDefault("YOUTUBE_NO_CAPTIONS_CACHE_TTL_SECONDS", 1800)) * time.Second,
+		YouTubePlaylistMaxVideos:  getEnvAsIntOrDefault("YOUTUBE_PLAYLIST_MAX_VIDEOS", 25),
+		StorageType:               getEnvOrDefault("STORAGE_TYPE", "local"),
+		StoragePath:               getEnvOrDefault("STORAGE_PATH", "./uploads"),
+		ContentReadyTimeout:       time.Duration(getEnvAsIntOrDefault("CONTENT_READY_TIMEOUT_SECONDS", 120)) * time.Second,
+		S3Bucket:                  os.Getenv("S3_BUCKET"),
+		S3Region:                  getEnvOrDefault("S3_REGION", "us-east-1"),
+		S3Endpoint:                os.Getenv("S3_ENDPOINT"),
+		S3AccessKeyID:             os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey:         os.Getenv("S3_SECRET_ACCESS_KEY"),
+		S3ForcePathStyle:          getEnvAsBoolOrDefault("S3_FORCE_PATH_STYLE", false),
+		UploadMaxSizeMB:           getEnvAsIntOrDefault("UPLOAD_MAX_SIZE_MB", 100),
+		UploadAllowedExtensions:   getEnvAsCSV("UPLOAD_ALLOWED_EXTENSIONS"),
+		DownloadURLTTL:            time.Duration(getEnvAsIntOrDefault("DOWNLOAD_URL_TTL_SECONDS", 300)) * time.Second,
+		DataExportSyncMaxRows:     getEnvAsIntOrDefault("DATA_EXPORT_SYNC_MAX_ROWS", 2000),
+		AdminEmails:               getEnvAsCSV("ADMIN_EMAILS"),
+		StuckJobThreshold:         time.Duration(getEnvAsIntOrDefault("STUCK_JOB_THRESHOLD_SECONDS", 900)) * time.Second,
+		WorkerCount:               getEnvAsIntOrDefault("WORKER_COUNT", 5),
+		WorkerQueueMinimums:       getEnvAsCSV("WORKER_QUEUE_MINIMUMS"),
+		JobMaxRetries:             getEnvAsIntOrDefault("JOB_MAX_RETRIES", 3),
+		JobRetryBackoff:           time.Duration(getEnvAsIntOrDefault("JOB_RETRY_BACKOFF_SECONDS", 1)) * time.Second,
+		JobRetryMaxBackoff:        time.Duration(getEnvAsIntOrDefault("JOB_RETRY_MAX_BACKOFF_SECONDS", 60)) * time.Second,
+		JobRetryPolicies:          getEnvAsCSV("JOB_RETRY_POLICIES"),
+		JobTimeout:                time.Duration(getEnvAsIntOrDefault("JOB_TIMEOUT_SECONDS", 600)) * time.Second,
+		JobTimeouts:               getEnvAsCSV("JOB_TIMEOUTS"),
+		PlanMonthlyCredits:        getEnvAsCSV("PLAN_MONTHLY_CREDITS"),
+		QuizDedupThreshold:        getEnvAsFloatOrDefault("QUIZ_DEDUP_SIMILARITY_THRESHOLD", 0.8),
+		MaxTranscriptChars:        getEnvAsIntOrDefault("MAX_TRANSCRIPT_CHARS", 400000),
+		SummaryChunkChars:         getEnvAsIntOrDefault("GEMINI_MAX_TRANSCRIPT_CHARS", 120000),
+		SummaryVersionLimit:       getEnvAsIntOrDefault("SUMMARY_VERSION_LIMIT", 5),
+		SMTPHost:                  getEnvOrDefault("SMTP_HOST", ""),
+		SMTPPort:                  getEnvOrDefault("SMTP_PORT", "587"),
+		SMTPUser:                  getEnvOrDefault("SMTP_USER", ""),
+		SMTPPass:                  getEnvOrDefault("SMTP_PASS", ""),
+		SMTPFrom:                  getEnvOrDefault("SMTP_FROM", "noreply@lectura.app"),
+		FrontendURL:               getEnvOrDefault("FRONTEND_URL", "http://localhost:5173"),
+		UnsplashAccessKey:         os.Getenv("UNSPLASH_ACCESS_KEY"),
+		TrustedProxyCIDRs:         getEnvAsCSV("TRUSTED_PROXY_CIDRS"),
+		HealthCheckTimeout:        time.Duration(getEnvAsIntOrDefault("HEALTH_CHECK_TIMEOUT_SECONDS", 2)) * time.Second,
+		GoogleClientID:            getEnvOrDefault("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:        getEnvOrDefault("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURI:         getEnvOrDefault("GOOGLE_REDIRECT_URI", ""),
 	}
 
 	return cfg
@@ -118,6 +240,30 @@ func getEnvAsIntOrDefault(key string, defaultVal int) int {
 	return n
 }
 
+func getEnvAsFloatOrDefault(key string, defaultVal float64) float64 {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return f
+}
+
+func getEnvAsBoolOrDefault(key string, defaultVal bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return defaultVal
+	}
+	return b
+}
+
 func getEnvAsCSV(key string) []string {
 	val := strings.TrimSpace(os.Getenv(key))
 	if val == "" {
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+	"lectura-backend/internal/worker"
+)
+
+const (
+	adminPlan          = "admin"
+	maxStuckJobsListed = 200
+)
+
+type adminJobRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error)
+	ListStuck(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Job, error)
+	ResetToPending(ctx context.Context, id uuid.UUID, fromStatuses ...string) (bool, error)
+}
+
+type adminUserRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+}
+
+type adminJobQueue interface {
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+type adminGeminiRate interface {
+	RateStats() services.GeminiRateStats
+	ResizeRateSlots(slots int) error
+}
+
+type AdminHandler struct {
+	jobRepo      adminJobRepository
+	userRepo     adminUserRepository
+	redis        adminJobQueue
+	queueBreaker *QueueBreaker
+	gemini       adminGeminiRate
+	adminEmails  map[string]bool
+	stuckAfter   time.Duration
+}
+
+func NewAdminHandler(jobRepo adminJobRepository, userRepo adminUserRepository, redisClient adminJobQueue, gemini adminGeminiRate, adminEmails []string, stuckAfter time.Duration) *AdminHandler {
+	emails := make(map[string]bool, len(adminEmails))
+	for _, e := range adminEmails {
+		emails[strings.ToLower(strings.TrimSpace(e))] = true
+	}
+	return &AdminHandler{
+		jobRepo:     jobRepo,
+		userRepo:    userRepo,
+		redis:       redisClient,
+		gemini:      gemini,
+		adminEmails: emails,
+		stuckAfter:  stuckAfter,
+	}
+}
+
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *AdminHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
+// RequireAdmin only lets through users on the admin plan or listed in
+// ADMIN_EMAILS. Must run after the JWT middleware.
+func (h *AdminHandler) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := middleware.GetUserID(r.Context())
+		user, err := h.userRepo.GetByID(r.Context(), userID)
+		if err != nil || !h.isAdmin(user) {
+			writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Admin access required", r))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *AdminHandler) isAdmin(user *models.User) bool {
+	if user == nil {
+		return false
+	}
+	return user.Plan == adminPlan || h.adminEmails[strings.ToLower(user.Email)]
+}
+
+// ListStuckJobs lists jobs that have been 'processing' for longer than the
+// configured threshold, oldest first.
+func (h *AdminHandler) ListStuckJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.jobRepo.ListStuck(r.Context(), h.stuckAfter, maxStuckJobsListed)
+	if err != nil {
+		log.Printf("AdminHandler.ListStuckJobs: failed to list jobs: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to list stuck jobs", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs":              jobs,
+		"threshold_seconds": int(h.stuckAfter.Seconds()),
+	})
+}
+
+// RequeueJob resets a stuck or failed job to 'pending' and pushes it back onto
+// its queue. Jobs whose worker still holds the lock are left alone.
+func (h *AdminHandler) RequeueJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid job ID", r))
+		return
+	}
+
+	job, err := h.jobRepo.GetByID(r.Context(), jobID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Job not found", r))
+		return
+	}
+
+	if job.Status != "processing" && job.Status != "failed" {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Only processing or failed jobs can be requeued", r))
+		return
+	}
+
+	if h.redis == nil {
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	held, err := h.redis.Exists(r.Context(), worker.JobLockKey(job.ID)).Result()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to check job lock", r))
+		return
+	}
+	if held > 0 {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Job is still locked by a worker", r))
+		return
+	}
+
+	reset, err := h.jobRepo.ResetToPending(r.Context(), job.ID, "processing", "failed")
+	if err != nil {
+		log.Printf("AdminHandler.RequeueJob: failed to reset job %s: %v", job.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to requeue job", r))
+		return
+	}
+	if !reset {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Job status changed, try again", r))
+		return
+	}
+	job.Status = "pending"
+	job.StartedAt = nil
+	job.CompletedAt = nil
+
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, worker.JobQueueName(job.Type), job); err != nil {
+		log.Printf("AdminHandler.RequeueJob: failed to push job %s: %v", job.ID, err)
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	log.Printf("AdminHandler.RequeueJob: job %s (type: %s) requeued", job.ID, job.Type)
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"job_id": job.ID,
+		"status": job.Status,
+		"queue":  worker.JobQueueName(job.Type),
+	})
+}
+
+// GeminiRateStats reports the Gemini concurrency bucket: configured capacity,
+// slots in use and free, callers waiting, and lifetime acquire counters.
+func (h *AdminHandler) GeminiRateStats(w http.ResponseWriter, r *http.Request) {
+	if h.gemini == nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResp("SERVICE_UNAVAILABLE", "Gemini service is not configured", r))
+		return
+	}
+	writeJSON(w, http.StatusOK, h.gemini.RateStats())
+}
+
+// ResizeGeminiRate changes the number of concurrent Gemini calls without a
+// restart. Shrinking below the slots currently in use takes effect as those
+// calls finish.
+func (h *AdminHandler) ResizeGeminiRate(w http.ResponseWriter, r *http.Request) {
+	if h.gemini == nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResp("SERVICE_UNAVAILABLE", "Gemini service is not configured", r))
+		return
+	}
+
+	var req struct {
+		Slots int `json:"slots"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+	if req.Slots < 1 || req.Slots > services.MaxGeminiRateSlots {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"slots": fmt.Sprintf("Must be between 1 and %d", services.MaxGeminiRateSlots),
+		}, r))
+		return
+	}
+
+	if err := h.gemini.ResizeRateSlots(req.Slots); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", err.Error(), r))
+		return
+	}
+
+	log.Printf("AdminHandler.ResizeGeminiRate: Gemini rate slots set to %d", req.Slots)
+	writeJSON(w, http.StatusOK, h.gemini.RateStats())
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type stubAdminJobRepo struct {
+	job      *models.Job
+	resetIDs []uuid.UUID
+}
+
+func (s *stubAdminJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	if s.job == nil || s.job.ID != id {
+		return nil, errors.New("not found")
+	}
+	return s.job, nil
+}
+
+func (s *stubAdminJobRepo) ListStuck(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Job, error) {
+	if s.job == nil {
+		return []*models.Job{}, nil
+	}
+	return []*models.Job{s.job}, nil
+}
+
+func (s *stubAdminJobRepo) ResetToPending(ctx context.Context, id uuid.UUID, fromStatuses ...string) (bool, error) {
+	s.resetIDs = append(s.resetIDs, id)
+	return true, nil
+}
+
+type stubAdminUserRepo struct {
+	user *models.User
+}
+
+func (s *stubAdminUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if s.user == nil {
+		return nil, errors.New("not found")
+	}
+	return s.user, nil
+}
+
+type stubAdminQueue struct {
+	locked bool
+	pushed map[string][]string
+}
+
+func (s *stubAdminQueue) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	if s.pushed == nil {
+		s.pushed = map[string][]string{}
+	}
+	for _, v := range values {
+		s.pushed[key] = append(s.pushed[key], v.(string))
+	}
+	return redis.NewIntResult(int64(len(s.pushed[key])), nil)
+}
+
+func (s *stubAdminQueue) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	if s.locked {
+		return redis.NewIntResult(1, nil)
+	}
+	return redis.NewIntResult(0, nil)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name string
+		user *models.User
+		want int
+	}{
+		{"free plan", &models.User{Email: "student@example.com", Plan: "free"}, http.StatusForbidden},
+		{"admin plan", &models.User{Email: "ops@example.com", Plan: "admin"}, http.StatusOK},
+		{"allowlisted email", &models.User{Email: "Root@Example.com", Plan: "pro"}, http.StatusOK},
+		{"unknown user", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAdminHandler(&stubAdminJobRepo{}, &stubAdminUserRepo{user: tt.user}, &stubAdminQueue{}, nil, []string{"root@example.com"}, time.Minute)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/jobs/stuck", nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+			rr := httptest.NewRecorder()
+			h.RequireAdmin(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequeueJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		locked     bool
+		wantCode   int
+		wantPushed bool
+	}{
+		{"stuck processing job", "processing", false, http.StatusAccepted, true},
+		{"failed job", "failed", false, http.StatusAccepted, true},
+		{"completed job", "completed", false, http.StatusConflict, false},
+		{"lock still held", "processing", true, http.StatusConflict, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.Job{ID: uuid.New(), Type: "quiz-generation", Status: tt.status}
+			jobRepo := &stubAdminJobRepo{job: job}
+			queue := &stubAdminQueue{locked: tt.locked}
+			h := NewAdminHandler(jobRepo, &stubAdminUserRepo{}, queue, nil, nil, time.Minute)
+
+			rr := httptest.NewRecorder()
+			h.RequeueJob(rr, makeContentRequest(http.MethodPost, "/api/v1/admin/jobs/"+job.ID.String()+"/requeue", job.ID, uuid.New()))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			pushed := queue.pushed["queue:quiz-generation"]
+			if !tt.wantPushed {
+				if len(pushed) != 0 || len(jobRepo.resetIDs) != 0 {
+					t.Fatalf("expected job to be left alone, got pushed=%v reset=%v", pushed, jobRepo.resetIDs)
+				}
+				return
+			}
+			if len(pushed) != 1 {
+				t.Fatalf("expected job on quiz queue, got %v", queue.pushed)
+			}
+			var queued models.Job
+			if err := json.Unmarshal([]byte(pushed[0]), &queued); err != nil {
+				t.Fatalf("queued payload is not a job: %v", err)
+			}
+			if queued.ID != job.ID || queued.Status != "pending" {
+				t.Fatalf("unexpected queued job: %+v", queued)
+			}
+		})
+	}
+}
+
+func TestRequeueJob_UnknownJob_Returns404(t *testing.T) {
+	h := NewAdminHandler(&stubAdminJobRepo{}, &stubAdminUserRepo{}, &stubAdminQueue{}, nil, nil, time.Minute)
+	jobID := uuid.New()
+
+	rr := httptest.NewRecorder()
+	h.RequeueJob(rr, makeContentRequest(http.MethodPost, "/api/v1/admin/jobs/"+jobID.String()+"/requeue", jobID, uuid.New()))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+type stubAdminGemini struct {
+	slots   int
+	resized []int
+}
+
+func (s *stubAdminGemini) RateStats() services.GeminiRateStats {
+	return services.GeminiRateStats{Capacity: s.slots, Available: s.slots}
+}
+
+func (s *stubAdminGemini) ResizeRateSlots(slots int) error {
+	s.resized = append(s.resized, slots)
+	s.slots = slots
+	return nil
+}
+
+func TestResizeGeminiRate(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "valid", body: `{"slots":8}`, want: http.StatusOK},
+		{name: "zero", body: `{"slots":0}`, want: http.StatusBadRequest},
+		{name: "too many", body: `{"slots":100000}`, want: http.StatusBadRequest},
+		{name: "malformed", body: `{`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gemini := &stubAdminGemini{slots: 3}
+			h := NewAdminHandler(&stubAdminJobRepo{}, &stubAdminUserRepo{}, &stubAdminQueue{}, gemini, nil, time.Minute)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/gemini/rate", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h.ResizeGeminiRate(rr, req)
+
+			if rr.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				if len(gemini.resized) != 0 {
+					t.Fatalf("rejected request resized bucket: %v", gemini.resized)
+				}
+				return
+			}
+
+			var stats services.GeminiRateStats
+			if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if stats.Capacity != 8 {
+				t.Fatalf("expected capacity 8, got %+v", stats)
+			}
+		})
+	}
+}
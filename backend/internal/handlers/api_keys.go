@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// API key limits.
+const (
+	maxAPIKeysPerUser = 20
+	maxAPIKeyNameLen  = 100
+)
+
+type apiKeyRepository interface {
+	Create(ctx context.Context, k *models.APIKey, keyHash string) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) (bool, error)
+}
+
+type APIKeyHandler struct {
+	repo apiKeyRepository
+}
+
+func NewAPIKeyHandler(repo apiKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{repo: repo}
+}
+
+// requireSession rejects requests authenticated with an API key, so a leaked
+// key cannot be used to mint or revoke other keys.
+func requireSession(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetAPIKeyScope(r.Context()) != "" {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "API keys cannot manage API keys", r))
+		return false
+	}
+	return true
+}
+
+// Create issues a new personal access token. The token is only returned here.
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireSession(w, r) {
+		return
+	}
+	userID := middleware.GetUserID(r.Context())
+
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Scope = strings.ToLower(strings.TrimSpace(req.Scope))
+	if req.Scope == "" {
+		req.Scope = models.APIKeyScopeRead
+	}
+
+	fields := map[string]string{}
+	if req.Name == "" {
+		fields["name"] = "Name is required"
+	} else if len([]rune(req.Name)) > maxAPIKeyNameLen {
+		fields["name"] = fmt.Sprintf("Name must be at most %d characters", maxAPIKeyNameLen)
+	}
+	if req.Scope != models.APIKeyScopeRead && req.Scope != models.APIKeyScopeWrite {
+		fields["scope"] = "Scope must be read or write"
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	count, err := h.repo.CountByUser(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create API key", r))
+		return
+	}
+	if count >= maxAPIKeysPerUser {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", fmt.Sprintf("You can have at most %d API keys", maxAPIKeysPerUser), r))
+		return
+	}
+
+	token, prefix, keyHash, err := services.GenerateAPIKey()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create API key", r))
+		return
+	}
+
+	key := &models.APIKey{UserID: userID, Name: req.Name, Prefix: prefix, Scope: req.Scope}
+	if err := h.repo.Create(r.Context(), key, keyHash); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create API key", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"api_key": key,
+		"token":   token,
+	})
+}
+
+// List returns the user's keys by prefix; secrets are never included.
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !requireSession(w, r) {
+		return
+	}
+	userID := middleware.GetUserID(r.Context())
+
+	keys, err := h.repo.ListByUser(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to list API keys", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"api_keys": keys})
+}
+
+// Delete revokes one of the user's keys.
+func (h *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !requireSession(w, r) {
+		return
+	}
+	userID := middleware.GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid API key ID", r))
+		return
+	}
+
+	deleted, err := h.repo.Delete(r.Context(), id, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to delete API key", r))
+		return
+	}
+	if !deleted {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "API key not found", r))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubAPIKeyRepo struct {
+	keys   []models.APIKey
+	hashes map[uuid.UUID]string
+}
+
+func (s *stubAPIKeyRepo) Create(_ context.Context, k *models.APIKey, keyHash string) error {
+	k.ID = uuid.New()
+	k.CreatedAt = time.Now()
+	if s.hashes == nil {
+		s.hashes = map[uuid.UUID]string{}
+	}
+	s.hashes[k.ID] = keyHash
+	s.keys = append(s.keys, *k)
+	return nil
+}
+
+func (s *stubAPIKeyRepo) ListByUser(_ context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	for _, k := range s.keys {
+		if k.UserID == userID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s *stubAPIKeyRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	keys, _ := s.ListByUser(ctx, userID)
+	return len(keys), nil
+}
+
+func (s *stubAPIKeyRepo) Delete(_ context.Context, id, userID uuid.UUID) (bool, error) {
+	for i, k := range s.keys {
+		if k.ID == id && k.UserID == userID {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func apiKeyRequest(method, target, body string, userID uuid.UUID, scope string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+	if scope != "" {
+		ctx = context.WithValue(ctx, middleware.APIKeyScopeKey, scope)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestAPIKeyCreate_ReturnsTokenOnceAndStoresHash(t *testing.T) {
+	repo := &stubAPIKeyRepo{}
+	h := NewAPIKeyHandler(repo)
+	userID := uuid.New()
+
+	rr := httptest.NewRecorder()
+	h.Create(rr, apiKeyRequest(http.MethodPost, "/api/v1/user/api-keys", `{"name":"CI script","scope":"write"}`, userID, ""))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		APIKey map[string]interface{} `json:"api_key"`
+		Token  string                 `json:"token"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.HasPrefix(resp.Token, middleware.APIKeyPrefix) {
+		t.Fatalf("token %q missing %q prefix", resp.Token, middleware.APIKeyPrefix)
+	}
+	prefix, _ := resp.APIKey["prefix"].(string)
+	if prefix == "" || !strings.HasPrefix(resp.Token, prefix) || prefix == resp.Token {
+		t.Fatalf("prefix %q should be a strict prefix of the token", prefix)
+	}
+	if resp.APIKey["scope"] != "write" {
+		t.Fatalf("scope = %v, want write", resp.APIKey["scope"])
+	}
+
+	stored := repo.hashes[repo.keys[0].ID]
+	if stored == "" || strings.Contains(stored, resp.Token) {
+		t.Fatalf("stored hash %q must not contain the token", stored)
+	}
+
+	rr = httptest.NewRecorder()
+	h.List(rr, apiKeyRequest(http.MethodGet, "/api/v1/user/api-keys", "", userID, ""))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list status = %d, want 200", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), resp.Token) {
+		t.Fatalf("list response leaks the token: %s", rr.Body.String())
+	}
+}
+
+func TestAPIKeyCreate_DefaultsToReadScope(t *testing.T) {
+	repo := &stubAPIKeyRepo{}
+	h := NewAPIKeyHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Create(rr, apiKeyRequest(http.MethodPost, "/api/v1/user/api-keys", `{"name":"Reporting"}`, uuid.New(), ""))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", rr.Code)
+	}
+	if repo.keys[0].Scope != models.APIKeyScopeRead {
+		t.Fatalf("scope = %q, want read", repo.keys[0].Scope)
+	}
+}
+
+func TestAPIKeyCreate_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing name", body: `{"scope":"read"}`},
+		{name: "unknown scope", body: `{"name":"x","scope":"admin"}`},
+		{name: "long name", body: `{"name":"` + strings.Repeat("a", maxAPIKeyNameLen+1) + `"}`},
+		{name: "bad json", body: `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAPIKeyHandler(&stubAPIKeyRepo{})
+			rr := httptest.NewRecorder()
+			h.Create(rr, apiKeyRequest(http.MethodPost, "/api/v1/user/api-keys", tt.body, uuid.New(), ""))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rr.Code)
+			}
+			if code := errorCodeFromBody(t, rr); code != "VALIDATION_ERROR" {
+				t.Fatalf("code = %q, want VALIDATION_ERROR", code)
+			}
+		})
+	}
+}
+
+func TestAPIKeyCreate_EnforcesLimit(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubAPIKeyRepo{}
+	for i := 0; i < maxAPIKeysPerUser; i++ {
+		repo.keys = append(repo.keys, models.APIKey{ID: uuid.New(), UserID: userID})
+	}
+	h := NewAPIKeyHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Create(rr, apiKeyRequest(http.MethodPost, "/api/v1/user/api-keys", `{"name":"one more"}`, userID, ""))
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", rr.Code)
+	}
+}
+
+func TestAPIKeyHandlers_RejectAPIKeyAuth(t *testing.T) {
+	h := NewAPIKeyHandler(&stubAPIKeyRepo{})
+	rr := httptest.NewRecorder()
+	h.Create(rr, apiKeyRequest(http.MethodPost, "/api/v1/user/api-keys", `{"name":"x"}`, uuid.New(), models.APIKeyScopeWrite))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", rr.Code)
+	}
+}
+
+func TestAPIKeyDelete(t *testing.T) {
+	owner := uuid.New()
+	keyID := uuid.New()
+	repo := &stubAPIKeyRepo{keys: []models.APIKey{{ID: keyID, UserID: owner}}}
+	h := NewAPIKeyHandler(repo)
+
+	del := func(userID uuid.UUID, id string) *httptest.ResponseRecorder {
+		req := apiKeyRequest(http.MethodDelete, "/api/v1/user/api-keys/"+id, "", userID, "")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+		h.Delete(rr, req)
+		return rr
+	}
+
+	if rr := del(uuid.New(), keyID.String()); rr.Code != http.StatusNotFound {
+		t.Fatalf("other user's delete status = %d, want 404", rr.Code)
+	}
+	if rr := del(owner, "not-a-uuid"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("bad id status = %d, want 400", rr.Code)
+	}
+	if rr := del(owner, keyID.String()); rr.Code != http.StatusNoContent {
+		t.Fatalf("owner delete status = %d, want 204", rr.Code)
+	}
+	if len(repo.keys) != 0 {
+		t.Fatalf("key was not deleted")
+	}
+}
@@ -7,9 +7,13 @@ import (
 	"io"
 	"net/http"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
 	"lectura-backend/internal/models"
 	"lectura-backend/internal/services"
 )
@@ -17,6 +21,9 @@ import (
 const (
 	refreshTokenCookieName = "refresh_token"
 	refreshTokenCookiePath = "/api/v1/auth/refresh"
+
+	// maxGenerateRequestBytes caps JSON bodies for generation endpoints.
+	maxGenerateRequestBytes = 64 * 1024
 )
 
 type authService interface {
@@ -25,10 +32,13 @@ type authService interface {
 	Login(ctx context.Context, req models.LoginRequest) (*models.AuthTokens, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*models.AuthTokens, error)
 	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
 	GoogleLogin(ctx context.Context, idToken string) (*models.AuthTokens, error)
 	GoogleCodeLogin(ctx context.Context, code string) (*models.AuthTokens, error)
 	GoogleOAuthConfig() (clientID string, redirectURI string, configured bool)
-	ResendVerification(ctx context.Context, email string) error
+	ResendVerification(ctx context.Context, email string) (time.Duration, error)
+	RequestPasswordReset(ctx context.Context, email string) (time.Duration, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
 }
 
 type AuthHandler struct {
@@ -67,7 +77,7 @@ func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.VerifyEmail(r.Context(), token)
+	tokens, err := h.authService.VerifyEmail(withSessionClient(r), token)
 	if err != nil {
 		handleServiceError(w, r, err)
 		return
@@ -84,7 +94,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.Login(r.Context(), req)
+	tokens, err := h.authService.Login(withSessionClient(r), req)
 	if err != nil {
 		handleServiceError(w, r, err)
 		return
@@ -105,7 +115,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.RefreshToken(r.Context(), refreshToken)
+	tokens, err := h.authService.RefreshToken(withSessionClient(r), refreshToken)
 	if err != nil {
 		handleServiceError(w, r, err)
 		return
@@ -129,6 +139,19 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
 }
 
+// LogoutAll signs the user out on every device, including this one.
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	if !requireSession(w, r) {
+		return
+	}
+	if err := h.authService.LogoutAll(r.Context(), middleware.GetUserID(r.Context())); err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+	clearRefreshTokenCookie(w, shouldUseSecureCookie(r, h.isProduction))
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Logged out of all devices"})
+}
+
 func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 	var req models.GoogleLoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -141,7 +164,7 @@ func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.GoogleLogin(r.Context(), req.IDToken)
+	tokens, err := h.authService.GoogleLogin(withSessionClient(r), req.IDToken)
 	if err != nil {
 		handleServiceError(w, r, err)
 		return
@@ -163,7 +186,7 @@ func (h *AuthHandler) GoogleCodeLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.GoogleCodeLogin(r.Context(), req.Code)
+	tokens, err := h.authService.GoogleCodeLogin(withSessionClient(r), req.Code)
 	if err != nil {
 		handleServiceError(w, r, err)
 		return
@@ -192,13 +215,64 @@ func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.authService.ResendVerification(r.Context(), req.Email)
+	cooldown, err := h.authService.ResendVerification(r.Context(), req.Email)
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":             "If that email is registered and unverified, a new verification email has been sent.",
+		"retry_after_seconds": retryAfterSeconds(cooldown),
+	})
+}
+
+// ForgotPassword emails a password reset link. It answers 200 whether or not
+// the address is registered.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	cooldown, err := h.authService.RequestPasswordReset(r.Context(), req.Email)
 	if err != nil {
 		handleServiceError(w, r, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]string{"message": "If that email is registered and unverified, a new verification email has been sent."})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":             "If that email is registered, a password reset email has been sent.",
+		"retry_after_seconds": retryAfterSeconds(cooldown),
+	})
+}
+
+// ResetPassword sets a new password from a reset link's token and signs out
+// every session of the account.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+	if strings.TrimSpace(req.Token) == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Token is required", r))
+		return
+	}
+
+	if err := h.authService.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w, shouldUseSecureCookie(r, h.isProduction))
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Your password has been reset. Please sign in with your new password."})
 }
 
 // Shared helpers
@@ -243,12 +317,22 @@ func handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
 	case *services.ForbiddenError:
 		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", e.Message, r))
 	case *services.RateLimitError:
-		writeJSON(w, http.StatusTooManyRequests, errorResp("RATE_LIMITED", e.Message, r))
+		resp := errorResp("RATE_LIMITED", e.Message, r)
+		if e.RetryAfter > 0 {
+			resp.Error.RetryAfterSeconds = retryAfterSeconds(e.RetryAfter)
+			w.Header().Set("Retry-After", strconv.Itoa(resp.Error.RetryAfterSeconds))
+		}
+		writeJSON(w, http.StatusTooManyRequests, resp)
 	default:
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "An unexpected error occurred", r))
 	}
 }
 
+// retryAfterSeconds rounds a wait up to whole seconds for clients.
+func retryAfterSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
 func writeAuthResponse(w http.ResponseWriter, status int, tokens *models.AuthTokens) {
 	writeJSON(w, status, map[string]interface{}{
 		"access_token": tokens.AccessToken,
@@ -303,6 +387,17 @@ func readRefreshTokenFromRequest(r *http.Request) (string, error) {
 	return strings.TrimSpace(req.RefreshToken), nil
 }
 
+// withSessionClient tags the request context with the caller's device, so a
+// refresh token issued for the request records it. chi's RealIP middleware
+// has already put the client address in RemoteAddr.
+func withSessionClient(r *http.Request) context.Context {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return services.WithSessionClient(r.Context(), r.UserAgent(), ip)
+}
+
 func shouldUseSecureCookie(r *http.Request, isProduction bool) bool {
 	if !isProduction {
 		return false
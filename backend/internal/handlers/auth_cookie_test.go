@@ -7,6 +7,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
 
 	"lectura-backend/internal/models"
 )
@@ -16,6 +19,12 @@ type stubAuthServiceForCookies struct {
 	refreshTokens       *models.AuthTokens
 	lastRefreshTokenArg string
 	lastLogoutTokenArg  string
+	loggedOutAllUserID  uuid.UUID
+	resendCooldown      time.Duration
+	resendErr           error
+	resetCooldown       time.Duration
+	resetErr            error
+	lastResetTokenArg   string
 }
 
 func (s *stubAuthServiceForCookies) Register(ctx context.Context, req models.RegisterRequest) (*models.User, string, error) {
@@ -46,6 +55,11 @@ func (s *stubAuthServiceForCookies) Logout(ctx context.Context, refreshToken str
 	return nil
 }
 
+func (s *stubAuthServiceForCookies) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	s.loggedOutAllUserID = userID
+	return nil
+}
+
 func (s *stubAuthServiceForCookies) GoogleLogin(ctx context.Context, idToken string) (*models.AuthTokens, error) {
 	return &models.AuthTokens{}, nil
 }
@@ -58,8 +72,17 @@ func (s *stubAuthServiceForCookies) GoogleOAuthConfig() (clientID string, redire
 	return "", "", false
 }
 
-func (s *stubAuthServiceForCookies) ResendVerification(ctx context.Context, email string) error {
-	return nil
+func (s *stubAuthServiceForCookies) ResendVerification(ctx context.Context, email string) (time.Duration, error) {
+	return s.resendCooldown, s.resendErr
+}
+
+func (s *stubAuthServiceForCookies) RequestPasswordReset(ctx context.Context, email string) (time.Duration, error) {
+	return s.resetCooldown, s.resetErr
+}
+
+func (s *stubAuthServiceForCookies) ResetPassword(ctx context.Context, token, newPassword string) error {
+	s.lastResetTokenArg = token
+	return s.resetErr
 }
 
 func TestLogin_SetsRefreshTokenHttpOnlyCookie(t *testing.T) {
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lectura-backend/internal/services"
+)
+
+func TestForgotPassword_ReturnsOKWithCooldown(t *testing.T) {
+	h := &AuthHandler{authService: &stubAuthServiceForCookies{resetCooldown: time.Minute}}
+
+	rr := httptest.NewRecorder()
+	h.ForgotPassword(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/forgot-password", strings.NewReader(`{"email":"nobody@example.com"}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		RetryAfterSeconds int `json:"retry_after_seconds"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.RetryAfterSeconds != 60 {
+		t.Fatalf("retry_after_seconds = %d, want 60", body.RetryAfterSeconds)
+	}
+}
+
+func TestResetPassword_ClearsRefreshCookie(t *testing.T) {
+	stub := &stubAuthServiceForCookies{}
+	h := &AuthHandler{authService: stub}
+
+	rr := httptest.NewRecorder()
+	h.ResetPassword(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/reset-password", strings.NewReader(`{"token":"abc","password":"newpass123"}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if stub.lastResetTokenArg != "abc" {
+		t.Fatalf("token = %q, want abc", stub.lastResetTokenArg)
+	}
+	cleared := false
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == refreshTokenCookieName && c.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Fatalf("expected the refresh token cookie to be cleared")
+	}
+}
+
+func TestResetPassword_MissingToken_Returns400(t *testing.T) {
+	stub := &stubAuthServiceForCookies{}
+	h := &AuthHandler{authService: stub}
+
+	rr := httptest.NewRecorder()
+	h.ResetPassword(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/reset-password", strings.NewReader(`{"password":"newpass123"}`)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rr.Code)
+	}
+}
+
+func TestResetPassword_InvalidToken_Returns404(t *testing.T) {
+	h := &AuthHandler{authService: &stubAuthServiceForCookies{
+		resetErr: &services.NotFoundError{Message: "Invalid or expired reset token"},
+	}}
+
+	rr := httptest.NewRecorder()
+	h.ResetPassword(rr, httptest.NewRequest(http.MethodPost, "/api/v1/auth/reset-password", strings.NewReader(`{"token":"stale","password":"newpass123"}`)))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rr.Code)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+func resendRequest() *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/api/v1/auth/resend-verification", strings.NewReader(`{"email":"ada@example.com"}`))
+}
+
+func TestResendVerification_ReturnsCooldown(t *testing.T) {
+	h := &AuthHandler{authService: &stubAuthServiceForCookies{resendCooldown: 5 * time.Minute}}
+
+	rr := httptest.NewRecorder()
+	h.ResendVerification(rr, resendRequest())
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		RetryAfterSeconds int `json:"retry_after_seconds"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.RetryAfterSeconds != 300 {
+		t.Fatalf("retry_after_seconds = %d, want 300", body.RetryAfterSeconds)
+	}
+}
+
+func TestResendVerification_RateLimitedReportsRemainingWait(t *testing.T) {
+	h := &AuthHandler{authService: &stubAuthServiceForCookies{
+		resendErr: &services.RateLimitError{Message: "Please wait", RetryAfter: 41500 * time.Millisecond},
+	}}
+
+	rr := httptest.NewRecorder()
+	h.ResendVerification(rr, resendRequest())
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "42" {
+		t.Fatalf("Retry-After = %q, want 42", got)
+	}
+	var body models.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error.Code != "RATE_LIMITED" || body.Error.RetryAfterSeconds != 42 {
+		t.Fatalf("error = %+v", body.Error)
+	}
+}
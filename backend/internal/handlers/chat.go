@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -22,6 +24,13 @@ const (
 	maxChatHistoryItems  = 20
 	maxChatHistoryBytes  = 32000
 	maxChatBodyBytes     = 64 * 1024
+
+	// Suggestions are best-effort on the chat path, so they get a short budget.
+	chatSuggestionsTimeout = 8 * time.Second
+
+	// Source passages added to a chat prompt when semantic search is on.
+	chatContextPassages  = 5
+	chatRetrievalTimeout = 10 * time.Second
 )
 
 // When frame OCR fails but the user asked about a timestamp, steer the model away from generic
@@ -32,20 +41,28 @@ const chatAssistantOCRMissHint = `
 
 type chatService interface {
 	ChatWithSummary(ctx context.Context, summaryContent, userMessage string, history []models.ChatMessage) (string, error)
+	SuggestFollowups(ctx context.Context, summaryContent string, lastExchange []models.ChatMessage) ([]string, error)
+	ExplainSelection(ctx context.Context, summaryContent, selection string) (string, error)
 }
 
 type chatHistoryRepository interface {
 	GetBySummaryAndUser(ctx context.Context, summaryID, userID uuid.UUID) ([]models.ChatHistoryMessage, error)
+	GetRecentBySummaryAndUser(ctx context.Context, summaryID, userID uuid.UUID, limit int) ([]models.ChatHistoryMessage, error)
 	Create(ctx context.Context, summaryID, userID uuid.UUID, role, content string) (*models.ChatHistoryMessage, error)
 	DeleteBySummaryAndUser(ctx context.Context, summaryID, userID uuid.UUID) error
 }
 
+type summarySearcher interface {
+	Search(ctx context.Context, summary *models.Summary, query string, limit int) ([]models.SummarySearchResult, error)
+}
+
 type ChatHandler struct {
 	summaryRepo   summaryRepository
 	chatRepo      chatHistoryRepository
 	geminiService chatService
 	contentRepo   *repository.ContentRepo
 	screenOCR     *services.ScreenOCRService
+	search        summarySearcher
 }
 
 func NewChatHandler(
@@ -64,6 +81,12 @@ func NewChatHandler(
 	}
 }
 
+// SetSummarySearch grounds chat answers in the source passages most relevant
+// to each question instead of the whole summary.
+func (h *ChatHandler) SetSummarySearch(search *services.SummarySearchService) {
+	h.search = search
+}
+
 func (h *ChatHandler) getOwnedSummary(r *http.Request, summaryID uuid.UUID) (*models.Summary, bool) {
 	summary, err := h.summaryRepo.GetByID(r.Context(), summaryID)
 	if err != nil {
@@ -262,8 +285,6 @@ func (h *ChatHandler) AskQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	history := trimChatHistory(req.History)
-
 	// Load summary and verify ownership
 	summary, ok := h.getOwnedSummary(r, summaryID)
 	if !ok {
@@ -271,29 +292,26 @@ func (h *ChatHandler) AskQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build summary context text
-	var summaryContent string
-	if summary.ContentRaw != nil && *summary.ContentRaw != "" {
-		summaryContent = *summary.ContentRaw
-	} else if summary.CornellCues != nil || summary.CornellNotes != nil || summary.CornellSummary != nil {
-		var parts []string
-		if summary.CornellCues != nil {
-			parts = append(parts, "CUES:\n"+*summary.CornellCues)
-		}
-		if summary.CornellNotes != nil {
-			parts = append(parts, "NOTES:\n"+*summary.CornellNotes)
-		}
-		if summary.CornellSummary != nil {
-			parts = append(parts, "SUMMARY:\n"+*summary.CornellSummary)
-		}
-		summaryContent = strings.Join(parts, "\n\n")
+	userID := middleware.GetUserID(r.Context())
+	history, err := h.conversationHistory(r.Context(), summaryID, userID, req.History)
+	if err != nil {
+		log.Printf("ChatHandler.AskQuestion: summary %s: load history: %v", summaryID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch chat history", r))
+		return
 	}
 
+	// Build summary context text
+	summaryContent := summaryPlainText(summary)
+
 	if summaryContent == "" {
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Summary has no content to chat about", r))
 		return
 	}
 
+	if passages := h.relevantPassages(r.Context(), summary, req.Message); len(passages) > 0 {
+		summaryContent = services.GroundedChatContext(summaryContent, passages)
+	}
+
 	// If user asked about a specific time (e.g. "5:00" / "5 minutes"),
 	// attempt on-demand OCR of the video frame at that timestamp and append
 	// it to the context. This is intentionally invisible in UI.
@@ -316,11 +334,181 @@ func (h *ChatHandler) AskQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Call Gemini chat
-	reply, err := h.geminiService.ChatWithSummary(r.Context(), summaryContent, req.Message, history)
+	usageCtx := services.WithUsageUser(r.Context(), userID)
+	reply, err := h.geminiService.ChatWithSummary(usageCtx, summaryContent, req.Message, history)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("AI_ERROR", "Failed to get AI response", r))
 		return
 	}
+	h.saveExchange(r.Context(), summaryID, userID, req.Message, reply)
+
+	suggestions := h.suggestFollowups(usageCtx, summary.ID, summaryPlainText(summary), []models.ChatMessage{
+		{Role: "user", Content: req.Message},
+		{Role: "assistant", Content: reply},
+	})
+
+	writeJSON(w, http.StatusOK, models.ChatResponse{Reply: reply, ScreenOcrHint: screenOcrHint, Suggestions: suggestions})
+}
+
+// conversationHistory returns the earlier turns sent with a question: the
+// latest stored messages, or the client's history when chat storage is not
+// configured.
+func (h *ChatHandler) conversationHistory(ctx context.Context, summaryID, userID uuid.UUID, clientHistory []models.ChatMessage) ([]models.ChatMessage, error) {
+	if h.chatRepo == nil {
+		return trimChatHistory(clientHistory), nil
+	}
+
+	stored, err := h.chatRepo.GetRecentBySummaryAndUser(ctx, summaryID, userID, maxChatHistoryItems)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]models.ChatMessage, len(stored))
+	for i, msg := range stored {
+		history[i] = models.ChatMessage{Role: msg.Role, Content: msg.Content}
+	}
+	return trimChatHistory(history), nil
+}
+
+// saveExchange stores a question and its answer. The answer has already been
+// generated, so a storage failure is logged rather than returned.
+func (h *ChatHandler) saveExchange(ctx context.Context, summaryID, userID uuid.UUID, question, reply string) {
+	if h.chatRepo == nil {
+		return
+	}
+	for _, msg := range []models.ChatMessage{
+		{Role: "user", Content: strings.TrimSpace(question)},
+		{Role: "assistant", Content: reply},
+	} {
+		if _, err := h.chatRepo.Create(ctx, summaryID, userID, msg.Role, msg.Content); err != nil {
+			log.Printf("ChatHandler.AskQuestion: summary %s: save %s message: %v", summaryID, msg.Role, err)
+			return
+		}
+	}
+}
+
+// ExplainSelection explains a passage the user highlighted in the summary,
+// without them having to phrase a question.
+func (h *ChatHandler) ExplainSelection(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxChatBodyBytes)
+
+	summaryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	var req models.ExplainRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Request body too large", r))
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	selection := strings.TrimSpace(req.Selection)
+	if selection == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Selection cannot be empty", r))
+		return
+	}
+	if len([]rune(selection)) > services.MaxExplainSelectionLength {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", fmt.Sprintf("Selection exceeds maximum length of %d characters", services.MaxExplainSelectionLength), r))
+		return
+	}
 
-	writeJSON(w, http.StatusOK, models.ChatResponse{Reply: reply, ScreenOcrHint: screenOcrHint})
+	summary, ok := h.getOwnedSummary(r, summaryID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	summaryContent := summaryPlainText(summary)
+	if summaryContent == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Summary has no content to explain", r))
+		return
+	}
+
+	usageCtx := services.WithUsageUser(r.Context(), middleware.GetUserID(r.Context()))
+	explanation, err := h.geminiService.ExplainSelection(usageCtx, summaryContent, selection)
+	if err != nil {
+		log.Printf("ChatHandler.ExplainSelection: failed for summary %s: %v", summary.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("AI_ERROR", "Failed to explain selection", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.ExplainResponse{Explanation: explanation})
+}
+
+// GetSuggestions returns questions to start a conversation about a summary.
+func (h *ChatHandler) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	summaryID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	summary, ok := h.getOwnedSummary(r, summaryID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	summaryContent := summaryPlainText(summary)
+	if summaryContent == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Summary has no content to chat about", r))
+		return
+	}
+
+	usageCtx := services.WithUsageUser(r.Context(), middleware.GetUserID(r.Context()))
+	suggestions, err := h.geminiService.SuggestFollowups(usageCtx, summaryContent, nil)
+	if err != nil {
+		log.Printf("ChatHandler.GetSuggestions: failed for summary %s: %v", summary.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("AI_ERROR", "Failed to generate suggestions", r))
+		return
+	}
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"suggestions": suggestions})
+}
+
+// suggestFollowups generates follow-up questions for the chat reply. Failures
+// are logged and yield no suggestions rather than failing the answer.
+func (h *ChatHandler) suggestFollowups(ctx context.Context, summaryID uuid.UUID, summaryContent string, lastExchange []models.ChatMessage) []string {
+	ctx, cancel := context.WithTimeout(ctx, chatSuggestionsTimeout)
+	defer cancel()
+
+	suggestions, err := h.geminiService.SuggestFollowups(ctx, summaryContent, lastExchange)
+	if err != nil {
+		log.Printf("ChatHandler: follow-up suggestions failed for summary %s: %v", summaryID, err)
+		return []string{}
+	}
+	if suggestions == nil {
+		return []string{}
+	}
+	return suggestions
+}
+
+// relevantPassages finds the source passages closest to the question. Chat
+// falls back to the whole summary when there are none or search fails.
+func (h *ChatHandler) relevantPassages(ctx context.Context, summary *models.Summary, question string) []models.SummarySearchResult {
+	if h.search == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, chatRetrievalTimeout)
+	defer cancel()
+
+	passages, err := h.search.Search(ctx, summary, question, chatContextPassages)
+	if err != nil {
+		if !errors.Is(err, services.ErrSearchUnavailable) {
+			log.Printf("ChatHandler: passage retrieval failed for summary %s: %v", summary.ID, err)
+		}
+		return nil
+	}
+	return passages
 }
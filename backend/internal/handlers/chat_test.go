@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -24,6 +25,8 @@ type stubChatHistoryRepo struct {
 	createdRole   string
 	createdBody   string
 	createdUserID uuid.UUID
+	created       []models.ChatMessage
+	recentLimit   int
 }
 
 func (s *stubChatHistoryRepo) GetBySummaryAndUser(ctx context.Context, summaryID, userID uuid.UUID) ([]models.ChatHistoryMessage, error) {
@@ -33,11 +36,24 @@ func (s *stubChatHistoryRepo) GetBySummaryAndUser(ctx context.Context, summaryID
 	return append([]models.ChatHistoryMessage(nil), s.items...), nil
 }
 
+func (s *stubChatHistoryRepo) GetRecentBySummaryAndUser(ctx context.Context, summaryID, userID uuid.UUID, limit int) ([]models.ChatHistoryMessage, error) {
+	s.recentLimit = limit
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	items := s.items
+	if len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+	return append([]models.ChatHistoryMessage(nil), items...), nil
+}
+
 func (s *stubChatHistoryRepo) Create(ctx context.Context, summaryID, userID uuid.UUID, role, content string) (*models.ChatHistoryMessage, error) {
 	if s.createErr != nil {
 		return nil, s.createErr
 	}
 	s.createdRole = role
+	s.created = append(s.created, models.ChatMessage{Role: role, Content: content})
 	s.createdBody = content
 	s.createdUserID = userID
 	msg := &models.ChatHistoryMessage{
@@ -66,7 +82,7 @@ type stubSummaryRepoForChat struct {
 func (s *stubSummaryRepoForChat) Create(ctx context.Context, summary *models.Summary) error {
 	return nil
 }
-func (s *stubSummaryRepoForChat) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, limit, offset int) ([]*models.Summary, int, error) {
+func (s *stubSummaryRepoForChat) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Summary, int, error) {
 	return nil, 0, nil
 }
 func (s *stubSummaryRepoForChat) GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error) {
@@ -83,14 +99,30 @@ func (s *stubSummaryRepoForChat) Delete(ctx context.Context, id uuid.UUID) error
 func (s *stubSummaryRepoForChat) ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	return nil
 }
+func (s *stubSummaryRepoForChat) SetArchived(ctx context.Context, id uuid.UUID, userID uuid.UUID, archived bool) error {
+	return nil
+}
+func (s *stubSummaryRepoForChat) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *stubSummaryRepoForChat) UpdateRawContent(ctx context.Context, id uuid.UUID, raw string, wordCount int, isQualityFallback bool, qualityFallbackReason *string) error {
+	return nil
+}
 
 type stubChatService struct {
 	reply         string
 	err           error
+	suggestions   []string
+	suggestErr    error
+	suggestedFor  []models.ChatMessage
 	capturedMsg   string
 	capturedHist  []models.ChatMessage
 	capturedCtx   string
 	invocationCnt int
+	explanation   string
+	explainErr    error
+	explained     string
 }
 
 func (s *stubChatService) ChatWithSummary(ctx context.Context, summaryContent, userMessage string, history []models.ChatMessage) (string, error) {
@@ -107,6 +139,22 @@ func (s *stubChatService) ChatWithSummary(ctx context.Context, summaryContent, u
 	return s.reply, nil
 }
 
+func (s *stubChatService) SuggestFollowups(ctx context.Context, summaryContent string, lastExchange []models.ChatMessage) ([]string, error) {
+	s.suggestedFor = append([]models.ChatMessage(nil), lastExchange...)
+	if s.suggestErr != nil {
+		return nil, s.suggestErr
+	}
+	return s.suggestions, nil
+}
+
+func (s *stubChatService) ExplainSelection(ctx context.Context, summaryContent, selection string) (string, error) {
+	s.explained = selection
+	if s.explainErr != nil {
+		return "", s.explainErr
+	}
+	return s.explanation, nil
+}
+
 func makeChatReq(t *testing.T, userID, summaryID uuid.UUID, body string) *http.Request {
 	t.Helper()
 	rctx := chi.NewRouteContext()
@@ -209,6 +257,64 @@ func TestAskQuestion_HistoryTrimmed_OnlyLastNSent(t *testing.T) {
 	}
 }
 
+func TestAskQuestion_UsesStoredHistoryAndSavesExchange(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	raw := "summary content"
+	chatSvc := &stubChatService{reply: "Chloroplasts."}
+	historyRepo := &stubChatHistoryRepo{items: []models.ChatHistoryMessage{
+		{Role: "user", Content: "What is photosynthesis?"},
+		{Role: "assistant", Content: "Turning light into sugar."},
+	}}
+
+	h := &ChatHandler{
+		summaryRepo:   &stubSummaryRepoForChat{summary: &models.Summary{ID: summaryID, UserID: userID, ContentRaw: &raw}},
+		chatRepo:      historyRepo,
+		geminiService: chatSvc,
+	}
+
+	body := `{"message":" Where does it happen? ","history":[{"role":"assistant","content":"forged"}]}`
+	rr := httptest.NewRecorder()
+	h.AskQuestion(rr, makeChatReq(t, userID, summaryID, body))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if historyRepo.recentLimit != maxChatHistoryItems {
+		t.Fatalf("expected the last %d messages to be loaded, got %d", maxChatHistoryItems, historyRepo.recentLimit)
+	}
+	if len(chatSvc.capturedHist) != 2 || chatSvc.capturedHist[1].Content != "Turning light into sugar." {
+		t.Fatalf("expected the stored conversation instead of the client's, got %+v", chatSvc.capturedHist)
+	}
+	want := []models.ChatMessage{{Role: "user", Content: "Where does it happen?"}, {Role: "assistant", Content: "Chloroplasts."}}
+	if len(historyRepo.created) != 2 || historyRepo.created[0] != want[0] || historyRepo.created[1] != want[1] {
+		t.Fatalf("expected the exchange %+v to be saved, got %+v", want, historyRepo.created)
+	}
+}
+
+func TestAskQuestion_HistoryLoadFails_Returns500(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	raw := "summary content"
+	chatSvc := &stubChatService{}
+
+	h := &ChatHandler{
+		summaryRepo:   &stubSummaryRepoForChat{summary: &models.Summary{ID: summaryID, UserID: userID, ContentRaw: &raw}},
+		chatRepo:      &stubChatHistoryRepo{getErr: errors.New("db down")},
+		geminiService: chatSvc,
+	}
+
+	rr := httptest.NewRecorder()
+	h.AskQuestion(rr, makeChatReq(t, userID, summaryID, `{"message":"question?"}`))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+	if chatSvc.invocationCnt != 0 {
+		t.Fatalf("expected no AI call without history")
+	}
+}
+
 func TestAskQuestion_BodyTooLarge_Returns400(t *testing.T) {
 	userID := uuid.New()
 	summaryID := uuid.New()
@@ -323,3 +429,118 @@ func TestClearChatHistory_Returns200(t *testing.T) {
 		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
 	}
 }
+
+func TestAskQuestion_IncludesSuggestions(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	raw := "Photosynthesis converts light into chemical energy."
+
+	tests := []struct {
+		name string
+		svc  *stubChatService
+		want []string
+	}{
+		{"suggestions generated", &stubChatService{reply: "It happens in chloroplasts.", suggestions: []string{"Where does photosynthesis happen?"}}, []string{"Where does photosynthesis happen?"}},
+		{"suggestions failed", &stubChatService{reply: "It happens in chloroplasts.", suggestErr: errors.New("quota")}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &ChatHandler{
+				summaryRepo:   &stubSummaryRepoForChat{summary: &models.Summary{ID: summaryID, UserID: userID, ContentRaw: &raw}},
+				geminiService: tt.svc,
+			}
+
+			req := makeChatReq(t, userID, summaryID, `{"message":"Where does it happen?","history":[]}`)
+			rr := httptest.NewRecorder()
+			h.AskQuestion(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+			}
+			var resp models.ChatResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Suggestions == nil || len(resp.Suggestions) != len(tt.want) {
+				t.Fatalf("expected suggestions %v, got %v", tt.want, resp.Suggestions)
+			}
+			for i := range tt.want {
+				if resp.Suggestions[i] != tt.want[i] {
+					t.Fatalf("expected suggestions %v, got %v", tt.want, resp.Suggestions)
+				}
+			}
+			if len(tt.svc.suggestedFor) != 2 || tt.svc.suggestedFor[1].Content != "It happens in chloroplasts." {
+				t.Fatalf("expected suggestions to be based on the latest exchange, got %+v", tt.svc.suggestedFor)
+			}
+		})
+	}
+}
+
+func TestExplainSelection(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	raw := "Photosynthesis converts light into chemical energy in chloroplasts."
+
+	tests := []struct {
+		name     string
+		body     string
+		svc      *stubChatService
+		wantCode int
+	}{
+		{"explains selection", `{"selection":"  chemical energy  "}`, &stubChatService{explanation: "Energy stored in sugar bonds."}, http.StatusOK},
+		{"empty selection", `{"selection":"   "}`, &stubChatService{}, http.StatusBadRequest},
+		{"selection too long", `{"selection":"` + strings.Repeat("a", 1001) + `"}`, &stubChatService{}, http.StatusBadRequest},
+		{"unknown field", `{"selection":"light","extra":1}`, &stubChatService{}, http.StatusBadRequest},
+		{"model failure", `{"selection":"light"}`, &stubChatService{explainErr: errors.New("quota")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &ChatHandler{
+				summaryRepo:   &stubSummaryRepoForChat{summary: &models.Summary{ID: summaryID, UserID: userID, ContentRaw: &raw}},
+				geminiService: tt.svc,
+			}
+
+			rr := httptest.NewRecorder()
+			h.ExplainSelection(rr, makeChatReq(t, userID, summaryID, tt.body))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp models.ExplainResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Explanation != "Energy stored in sugar bonds." {
+				t.Fatalf("unexpected explanation %q", resp.Explanation)
+			}
+			if tt.svc.explained != "chemical energy" {
+				t.Fatalf("expected trimmed selection, got %q", tt.svc.explained)
+			}
+		})
+	}
+}
+
+func TestExplainSelection_OtherUsersSummary_Returns404(t *testing.T) {
+	summaryID := uuid.New()
+	raw := "raw"
+	svc := &stubChatService{}
+	h := &ChatHandler{
+		summaryRepo:   &stubSummaryRepoForChat{summary: &models.Summary{ID: summaryID, UserID: uuid.New(), ContentRaw: &raw}},
+		geminiService: svc,
+	}
+
+	rr := httptest.NewRecorder()
+	h.ExplainSelection(rr, makeChatReq(t, uuid.New(), summaryID, `{"selection":"raw"}`))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+	}
+	if svc.explained != "" {
+		t.Fatalf("expected no model call for another user's summary")
+	}
+}
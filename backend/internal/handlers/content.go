@@ -1,20 +1,25 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 
 	"lectura-backend/internal/middleware"
@@ -24,36 +29,71 @@ import (
 )
 
 type ContentHandler struct {
-	contentRepo contentStore
-	jobRepo     jobStore
-	redis       *redis.Client
-	storagePath string
-	youtube     *services.YouTubeService
+	contentRepo  contentStore
+	jobRepo      jobStore
+	settingsRepo contentSettingsStore
+	redis        *redis.Client
+	queueBreaker *QueueBreaker
+	storage      services.Storage
+	youtube      *services.YouTubeService
+	uploads      services.UploadPolicy
+	downloads    *services.DownloadSigner
+	playlists    playlistFetcher
+	playlistMax  int
 }
 
 type contentStore interface {
 	Create(ctx context.Context, c *models.Content) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error)
+	RefreshMetadata(ctx context.Context, id uuid.UUID, title string, durationSeconds int, metadataJSON json.RawMessage) error
+	ResetForReprocessing(ctx context.Context, id uuid.UUID) error
+	FindCompletedByHash(ctx context.Context, userID uuid.UUID, hash string) (*models.Content, error)
+}
+
+type contentSettingsStore interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error)
 }
 
 type jobStore interface {
 	Create(ctx context.Context, j *models.Job) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	GetLatestByReference(ctx context.Context, referenceID uuid.UUID, jobType string) (*models.Job, error)
 }
 
-func NewContentHandler(contentRepo *repository.ContentRepo, jobRepo *repository.JobRepo, redisClient *redis.Client, storagePath string, youtube *services.YouTubeService) *ContentHandler {
+const reprocessLockTTL = 30 * time.Second
+
+func NewContentHandler(contentRepo *repository.ContentRepo, jobRepo *repository.JobRepo, userRepo *repository.UserRepo, redisClient *redis.Client, storage services.Storage, youtube *services.YouTubeService, uploads services.UploadPolicy, downloads *services.DownloadSigner) *ContentHandler {
 	if redisClient == nil {
 		log.Println("CRITICAL: NewContentHandler received nil redisClient")
 	} else {
 		log.Printf("DEBUG: NewContentHandler initialized with redisClient: %v", redisClient)
 	}
-	return &ContentHandler{
-		contentRepo: contentRepo,
-		jobRepo:     jobRepo,
-		redis:       redisClient,
-		storagePath: storagePath,
-		youtube:     youtube,
+	h := &ContentHandler{
+		contentRepo:  contentRepo,
+		jobRepo:      jobRepo,
+		settingsRepo: userRepo,
+		redis:        redisClient,
+		storage:      storage,
+		youtube:      youtube,
+		uploads:      uploads,
+		downloads:    downloads,
+	}
+	if youtube != nil {
+		h.playlists = youtube
+	}
+	return h
+}
+
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *ContentHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
+func (h *ContentHandler) uploadPolicy() services.UploadPolicy {
+	if h.uploads.MaxBytes <= 0 || len(h.uploads.Formats) == 0 {
+		return services.DefaultUploadPolicy()
 	}
+	return h.uploads
 }
 
 var youtubeRegex = regexp.MustCompile(`(?:youtube\.com/(?:watch\?v=|embed/|shorts/)|youtu\.be/)([\w-]{11})`)
@@ -74,76 +114,223 @@ func (h *ContentHandler) ValidateYouTube(w http.ResponseWriter, r *http.Request)
 	videoID := matches[1]
 	userID := middleware.GetUserID(r.Context())
 
-	content := &models.Content{
-		UserID:    userID,
-		Type:      "youtube",
-		Status:    "pending",
-		SourceURL: &req.URL,
-		Title:     "YouTube Video: " + videoID,
+	captionLanguage, ok := h.requestedCaptionLanguage(w, r, userID, req.CaptionLanguage)
+	if !ok {
+		return
 	}
 
-	thumbnailURL := "https://img.youtube.com/vi/" + videoID + "/maxresdefault.jpg"
-
-	metadata := models.YouTubeMetadata{
-		VideoID:      videoID,
-		Title:        content.Title,
-		ChannelName:  "YouTube Channel",
-		ThumbnailURL: thumbnailURL,
-		Duration:     0,
+	contentHash := youtubeContentHash(videoID, captionLanguage)
+	if existing := h.findDuplicate(r, userID, contentHash); existing != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"content_id":   existing.ID,
+			"video_id":     videoID,
+			"metadata":     existing.MetadataJSON,
+			"valid":        true,
+			"deduplicated": true,
+		})
+		return
 	}
-	metaBytes, _ := json.Marshal(metadata)
-	content.MetadataJSON = metaBytes
-	content.Title = metadata.Title
 
-	if err := h.contentRepo.Create(r.Context(), content); err != nil {
+	metadata, _ := h.youtubeVideoMetadata(r.Context(), videoID)
+	metadata.CaptionLanguage = captionLanguage
+	content, err := h.createYouTubeContent(r.Context(), userID, req.URL, contentHash, metadata)
+	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create content record", r))
 		return
 	}
 
 	// Trigger async transcript extraction
-	jobID := uuid.New()
+	job, ok := h.queueContentProcessing(w, r, userID, content.ID)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content_id":   content.ID,
+		"job_id":       job.ID,
+		"video_id":     videoID,
+		"metadata":     metadata,
+		"valid":        true,
+		"deduplicated": false,
+	})
+}
+
+// requestedCaptionLanguage validates the caption language of a YouTube
+// request, defaulting to the account language. On an invalid code it writes
+// the error response and returns false.
+func (h *ContentHandler) requestedCaptionLanguage(w http.ResponseWriter, r *http.Request, userID uuid.UUID, requested string) (string, bool) {
+	if strings.TrimSpace(requested) == "" {
+		return h.defaultCaptionLanguage(r.Context(), userID), true
+	}
+	lang, ok := services.NormalizeCaptionLanguage(requested)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"caption_language": "Must be a language code such as en or pt-BR, or auto",
+		}, r))
+		return "", false
+	}
+	return lang, true
+}
+
+// youtubeVideoMetadata is the metadata a new YouTube content record starts
+// with: the cached scrape when there is one, otherwise placeholders that
+// content processing fills in. cached reports which.
+func (h *ContentHandler) youtubeVideoMetadata(ctx context.Context, videoID string) (metadata models.YouTubeMetadata, cached bool) {
+	if h.youtube != nil {
+		if scraped, ok := h.youtube.CachedVideoMetadata(ctx, videoID); ok {
+			return *scraped, true
+		}
+	}
+	return models.YouTubeMetadata{
+		VideoID:      videoID,
+		Title:        "YouTube Video: " + videoID,
+		ChannelName:  "YouTube Channel",
+		ThumbnailURL: "https://img.youtube.com/vi/" + videoID + "/maxresdefault.jpg",
+	}, false
+}
+
+// createYouTubeContent stores a pending content record for a YouTube video.
+func (h *ContentHandler) createYouTubeContent(ctx context.Context, userID uuid.UUID, sourceURL, contentHash string, metadata models.YouTubeMetadata) (*models.Content, error) {
+	metaBytes, _ := json.Marshal(metadata)
+	content := &models.Content{
+		UserID:       userID,
+		Type:         "youtube",
+		Status:       "pending",
+		SourceURL:    &sourceURL,
+		Title:        metadata.Title,
+		MetadataJSON: metaBytes,
+		ContentHash:  &contentHash,
+	}
+	if metadata.Duration > 0 {
+		content.DurationSeconds = &metadata.Duration
+	}
+	if err := h.contentRepo.Create(ctx, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// errContentQueueUnavailable means a content-processing job was created but
+// could not be queued; the job has been marked failed.
+var errContentQueueUnavailable = errors.New("content-processing queue unavailable")
+
+// queueContentProcessing creates the content-processing job that extracts a
+// transcript for new content, pushes it onto the worker queue, and tells the
+// user's open sessions it is queued. On failure it writes the error response
+// and returns false; a job that could not be queued is marked failed.
+func (h *ContentHandler) queueContentProcessing(w http.ResponseWriter, r *http.Request, userID, contentID uuid.UUID) (*models.Job, bool) {
+	job, err := h.enqueueContentProcessing(r.Context(), userID, contentID)
+	switch {
+	case errors.Is(err, errContentQueueUnavailable):
+		writeQueueUnavailable(w, r)
+		return nil, false
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create processing job", r))
+		return nil, false
+	}
+	return job, true
+}
+
+// enqueueContentProcessing does the work of queueContentProcessing without
+// writing a response, for handlers that queue several jobs in one request.
+func (h *ContentHandler) enqueueContentProcessing(ctx context.Context, userID, contentID uuid.UUID) (*models.Job, error) {
 	job := &models.Job{
-		ID:          jobID,
 		UserID:      userID,
 		Type:        "content-processing",
-		Status:      "queued",
-		ReferenceID: content.ID,
-		CreatedAt:   time.Now(),
+		ReferenceID: contentID,
 	}
 
-	if err := h.jobRepo.Create(r.Context(), job); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create processing job", r))
-		return
+	if err := h.jobRepo.Create(ctx, job); err != nil {
+		log.Printf("failed to create content-processing job for content %s: %v", contentID, err)
+		return nil, err
 	}
 
 	if h.redis == nil {
-		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue processing job", r))
-		return
+		_ = h.jobRepo.UpdateStatus(ctx, job.ID, "failed")
+		return nil, errContentQueueUnavailable
 	}
 
-	jobBytes, _ := json.Marshal(job)
-	if err := h.redis.LPush(r.Context(), "queue:content-processing", string(jobBytes)).Err(); err != nil {
-		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue processing job", r))
-		return
+	if err := pushJob(ctx, h.queueBreaker, h.redis, "queue:content-processing", job); err != nil {
+		log.Printf("failed to enqueue content-processing job %s: %v", job.ID, err)
+		_ = h.jobRepo.UpdateStatus(ctx, job.ID, "failed")
+		return nil, errContentQueueUnavailable
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"content_id": content.ID,
-		"video_id":   videoID,
-		"metadata":   metadata,
-		"valid":      true,
+	update, _ := json.Marshal(models.WSMessage{
+		Type: "status_update",
+		Payload: models.StatusUpdate{
+			JobID:    job.ID,
+			StepName: "Queued for processing",
+		},
 	})
+	if err := h.redis.Publish(ctx, "user_updates:"+userID.String(), string(update)).Err(); err != nil {
+		log.Printf("failed to publish queued status for job %s: %v", job.ID, err)
+	}
+
+	return job, nil
+}
+
+// youtubeContentHash fingerprints a YouTube source. The caption language is
+// part of the key because it decides which transcript gets extracted.
+func youtubeContentHash(videoID, captionLanguage string) string {
+	return "youtube:" + videoID + ":" + captionLanguage
+}
+
+// findDuplicate returns the caller's already processed content with the same
+// fingerprint, or nil when there is none or ?force=true asks for a fresh run.
+// Lookup failures only cost the saving, so they are logged and ignored.
+func (h *ContentHandler) findDuplicate(r *http.Request, userID uuid.UUID, hash string) *models.Content {
+	if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); force {
+		return nil
+	}
+	existing, err := h.contentRepo.FindCompletedByHash(r.Context(), userID, hash)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("content dedup lookup failed for user %s: %v", userID, err)
+		}
+		return nil
+	}
+	// Reusing content processed under a different redaction setting would
+	// either expose personal data the user now wants masked or hand back a
+	// masked transcript they no longer asked for.
+	if existing.Redacted != h.redactsTranscripts(r.Context(), userID) {
+		return nil
+	}
+	return existing
+}
+
+func (h *ContentHandler) redactsTranscripts(ctx context.Context, userID uuid.UUID) bool {
+	if h.settingsRepo == nil {
+		return false
+	}
+	settings, err := h.settingsRepo.GetSettings(ctx, userID)
+	return err == nil && settings != nil && settings.RedactTranscripts
+}
+
+// defaultCaptionLanguage is the caller's account language, or "auto" when
+// settings are unavailable or hold something that isn't a language code.
+func (h *ContentHandler) defaultCaptionLanguage(ctx context.Context, userID uuid.UUID) string {
+	if h.settingsRepo == nil {
+		return services.CaptionLanguageAny
+	}
+	settings, err := h.settingsRepo.GetSettings(ctx, userID)
+	if err != nil || settings == nil {
+		return services.CaptionLanguageAny
+	}
+	if lang, ok := services.NormalizeCaptionLanguage(settings.Language); ok {
+		return lang
+	}
+	return services.CaptionLanguageAny
 }
 
 func (h *ContentHandler) Upload(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, 100*1024*1024)
+	policy := h.uploadPolicy()
+	r.Body = http.MaxBytesReader(w, r.Body, policy.MaxBytes)
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
 		if strings.Contains(err.Error(), "http: request body too large") {
-			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "File exceeds maximum allowed size", r))
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResp("FILE_TOO_LARGE", fmt.Sprintf("File exceeds the maximum upload size of %d MB", policy.MaxMegabytes()), r))
 			return
 		}
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "No file provided", r))
@@ -157,7 +344,7 @@ func (h *ContentHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	buf = buf[:n]
 
 	mimeType := http.DetectContentType(buf)
-	if !isAllowedMimeType(mimeType, header.Filename) {
+	if !isAllowedMimeType(policy, mimeType, header.Filename) {
 		writeJSON(w, http.StatusUnsupportedMediaType, errorResp("UNSUPPORTED_FORMAT", "File type not supported", r))
 		return
 	}
@@ -166,100 +353,298 @@ func (h *ContentHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Reset file reader
+	// Hash the whole upload so an identical file that was already processed
+	// can be reused instead of extracted again.
 	file.Seek(0, io.SeekStart)
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to read uploaded file", r))
+		return
+	}
+	contentHash := "sha256:" + hex.EncodeToString(hasher.Sum(nil))
 
 	userID := middleware.GetUserID(r.Context())
+	if existing := h.findDuplicate(r, userID, contentHash); existing != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"content_id":   existing.ID,
+			"filename":     header.Filename,
+			"mime_type":    mimeType,
+			"size_bytes":   fmt.Sprintf("%d", size),
+			"deduplicated": true,
+		})
+		return
+	}
+
+	// Reset file reader
+	file.Seek(0, io.SeekStart)
+
 	fileID := uuid.New().String()
 	ext := getExtension(header.Filename)
 	storagePath := "users/" + userID.String() + "/uploads/" + fileID + ext
 
 	content := &models.Content{
-		UserID:   userID,
-		Type:     "file",
-		Status:   "pending",
-		FilePath: &storagePath,
-		Title:    header.Filename,
-	}
-
-	absPath := filepath.Join(h.storagePath, storagePath)
-	if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create upload directory", r))
-		return
+		UserID:      userID,
+		Type:        "file",
+		Status:      "pending",
+		FilePath:    &storagePath,
+		Title:       header.Filename,
+		ContentHash: &contentHash,
 	}
 
-	dst, err := os.Create(absPath)
-	if err != nil {
+	if err := h.storage.Put(r.Context(), storagePath, file, size); err != nil {
+		log.Printf("ContentHandler.Upload: store %s: %v", storagePath, err)
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to store uploaded file", r))
 		return
 	}
-	defer dst.Close()
-
-	written, err := io.Copy(dst, file)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to save uploaded file", r))
-		return
-	}
 
 	meta := map[string]interface{}{
 		"filename":   header.Filename,
 		"mime_type":  mimeType,
-		"size_bytes": written,
+		"size_bytes": size,
 	}
 	metaBytes, _ := json.Marshal(meta)
 	content.MetadataJSON = metaBytes
 
 	if err := h.contentRepo.Create(r.Context(), content); err != nil {
-		_ = os.Remove(absPath)
+		_ = h.storage.Delete(r.Context(), storagePath)
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create content record", r))
 		return
 	}
 
-	job := &models.Job{
-		UserID:      userID,
-		Type:        "content-processing",
-		ReferenceID: content.ID,
+	job, ok := h.queueContentProcessing(w, r, userID, content.ID)
+	if !ok {
+		return
 	}
 
-	if err := h.jobRepo.Create(r.Context(), job); err != nil {
-		log.Printf("failed to create file content-processing job for content %s: %v", content.ID, err)
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create processing job", r))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content_id":   content.ID,
+		"job_id":       job.ID,
+		"filename":     header.Filename,
+		"mime_type":    mimeType,
+		"size_bytes":   fmt.Sprintf("%d", size),
+		"deduplicated": false,
+	})
+}
+
+func (h *ContentHandler) SupportedFormats(w http.ResponseWriter, r *http.Request) {
+	policy := h.uploadPolicy()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"formats":        policy.Formats,
+		"max_size_bytes": policy.MaxBytes,
+	})
+}
+
+func (h *ContentHandler) GetContent(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid content ID", r))
 		return
 	}
 
-	if h.redis == nil {
-		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue processing job", r))
+	content, err := h.contentRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
 		return
 	}
 
-	jobBytes, _ := json.Marshal(job)
-	if err := h.redis.LPush(r.Context(), "queue:content-processing", string(jobBytes)).Err(); err != nil {
-		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue processing job", r))
+	userID := middleware.GetUserID(r.Context())
+	if content.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"content_id": content.ID,
-		"filename":   header.Filename,
-		"mime_type":  mimeType,
-		"size_bytes": fmt.Sprintf("%d", written),
-	})
+	if content.Status == "failed" {
+		if job, err := h.jobRepo.GetLatestByReference(r.Context(), content.ID, "content-processing"); err == nil {
+			content.ErrorMessage = job.ErrorMessage
+			content.ErrorCode = job.ErrorCode
+		}
+	}
+	if content.Type == "youtube" {
+		var meta models.YouTubeMetadata
+		if json.Unmarshal(content.MetadataJSON, &meta) == nil {
+			content.Chapters = meta.Chapters
+		}
+	}
+
+	writeJSON(w, http.StatusOK, content)
 }
 
-func (h *ContentHandler) SupportedFormats(w http.ResponseWriter, r *http.Request) {
+// Download streams the original uploaded file to its owner.
+func (h *ContentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid content ID", r))
+		return
+	}
+
+	content, err := h.contentRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if content.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	h.serveUploadedFile(w, r, content)
+}
+
+// DownloadURL issues a short-lived signed link to the original uploaded file
+// that works without the JWT, for direct browser downloads.
+func (h *ContentHandler) DownloadURL(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid content ID", r))
+		return
+	}
+
+	if h.downloads == nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResp("SERVICE_UNAVAILABLE", "Signed downloads are not configured", r))
+		return
+	}
+
+	content, err := h.contentRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if content.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if content.Type != "file" || content.FilePath == nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content has no uploaded file", r))
+		return
+	}
+
+	expires, sig := h.downloads.Sign(content.ID, time.Now())
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"formats": []map[string]string{
-			{"extension": ".pdf", "mime_type": "application/pdf", "description": "PDF Document"},
-			{"extension": ".docx", "mime_type": "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "description": "Word Document"},
-		},
+		"path":       fmt.Sprintf("/content/%s/file?expires=%d&sig=%s", content.ID, expires, sig),
+		"expires_at": time.Unix(expires, 0).UTC(),
 	})
 }
 
-func (h *ContentHandler) GetContent(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := uuid.Parse(idStr)
+// SignedDownload serves an uploaded file to anyone holding a valid, unexpired
+// link from DownloadURL. It is mounted outside the JWT middleware.
+func (h *ContentHandler) SignedDownload(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid content ID", r))
+		return
+	}
+
+	if h.downloads == nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
+		return
+	}
+
+	q := r.URL.Query()
+	if err := h.downloads.Verify(id, q.Get("expires"), q.Get("sig"), time.Now()); err != nil {
+		msg := "Download link is invalid"
+		if errors.Is(err, services.ErrDownloadLinkExpired) {
+			msg = "Download link has expired"
+		}
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", msg, r))
+		return
+	}
+
+	content, err := h.contentRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
+		return
+	}
+
+	h.serveUploadedFile(w, r, content)
+}
+
+// serveUploadedFile streams a content item's stored upload. Callers must have
+// authorized access already.
+func (h *ContentHandler) serveUploadedFile(w http.ResponseWriter, r *http.Request, content *models.Content) {
+	if content.Type != "file" || content.FilePath == nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content has no uploaded file", r))
+		return
+	}
+
+	body, err := h.storage.Get(r.Context(), *content.FilePath)
+	if errors.Is(err, services.ErrInvalidStorageKey) {
+		log.Printf("ContentHandler: refusing to serve content %s, file path escapes storage", content.ID)
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Uploaded file is no longer available", r))
+		return
+	}
+	if errors.Is(err, services.ErrStorageObjectNotFound) {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Uploaded file is no longer available", r))
+		return
+	}
+	if err != nil {
+		log.Printf("ContentHandler: read upload of content %s: %v", content.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to read uploaded file", r))
+		return
+	}
+	defer body.Close()
+
+	var meta struct {
+		Filename string `json:"filename"`
+		MimeType string `json:"mime_type"`
+	}
+	_ = json.Unmarshal(content.MetadataJSON, &meta)
+
+	filename := meta.Filename
+	if filename == "" {
+		filename = content.Title
+	}
+	filename = downloadFileName(filename, getExtension(*content.FilePath))
+
+	mimeType := meta.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// Files on local disk support range requests; objects streamed from
+	// remote storage are sent whole.
+	if f, ok := body.(*os.File); ok {
+		if stat, err := f.Stat(); err == nil {
+			http.ServeContent(w, r, "", stat.ModTime(), f)
+			return
+		}
+	}
+	io.Copy(w, body)
+}
+
+// downloadFileName makes a user-supplied upload name safe for a
+// Content-Disposition header, keeping the stored extension.
+func downloadFileName(name, ext string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' || r == '\\' || r == '/' || r > 0x7e {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "upload"
+	}
+	if ext != "" && !strings.HasSuffix(strings.ToLower(name), strings.ToLower(ext)) {
+		name += ext
+	}
+	return name
+}
+
+// Reprocess re-runs content processing for the same upload or video, e.g.
+// after the original job exhausted its retries.
+func (h *ContentHandler) Reprocess(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid content ID", r))
 		return
@@ -277,21 +662,166 @@ func (h *ContentHandler) GetContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, content)
+	if content.Status == "pending" || content.Status == "processing" {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Content is already being processed", r))
+		return
+	}
+
+	switch content.Type {
+	case "file":
+		if content.FilePath == nil || *content.FilePath == "" {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Uploaded file is no longer available", r))
+			return
+		}
+		body, err := h.storage.Get(r.Context(), *content.FilePath)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Uploaded file is no longer available", r))
+			return
+		}
+		body.Close()
+	case "youtube":
+		var videoID string
+		if content.SourceURL != nil {
+			if matches := youtubeRegex.FindStringSubmatch(*content.SourceURL); len(matches) >= 2 {
+				videoID = matches[1]
+			}
+		}
+		if videoID == "" {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Content has no YouTube video ID", r))
+			return
+		}
+		if h.youtube != nil {
+			if _, _, _, _, _, err := h.youtube.RefreshVideoMetadata(videoID); err != nil {
+				log.Printf("reprocess: video %s for content %s is unavailable: %v", videoID, content.ID, err)
+				writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "YouTube video is no longer available", r))
+				return
+			}
+		}
+	}
+
+	if h.redis == nil {
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	// Same SetNX lock the worker uses per job, keyed by content so two
+	// concurrent reprocess requests cannot both reset and enqueue.
+	lockKey := fmt.Sprintf("content_reprocess_lock:%s", content.ID.String())
+	locked, err := h.redis.SetNX(r.Context(), lockKey, "1", reprocessLockTTL).Result()
+	if err != nil {
+		writeQueueUnavailable(w, r)
+		return
+	}
+	if !locked {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Content is already being reprocessed", r))
+		return
+	}
+	defer h.redis.Del(context.Background(), lockKey)
+
+	if err := h.contentRepo.ResetForReprocessing(r.Context(), content.ID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to reset content", r))
+		return
+	}
+
+	job, ok := h.queueContentProcessing(w, r, userID, content.ID)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"content_id": content.ID,
+		"job_id":     job.ID,
+		"status":     "pending",
+	})
 }
 
-func isAllowedMimeType(mime, filename string) bool {
-	allowed := map[string]bool{
-		"application/pdf":                                                             true,
-		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-		"application/octet-stream":                                                    true,
+// RefreshMetadata re-scrapes title, channel and duration for YouTube content and
+// merges them into the stored metadata (other cached keys are preserved).
+func (h *ContentHandler) RefreshMetadata(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid content ID", r))
+		return
+	}
+
+	content, err := h.contentRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if content.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if content.Type != "youtube" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Metadata refresh is only available for YouTube content", r))
+		return
+	}
+
+	stored := map[string]interface{}{}
+	if len(content.MetadataJSON) > 0 {
+		_ = json.Unmarshal(content.MetadataJSON, &stored)
 	}
-	if allowed[mime] {
-		return true
+
+	videoID, _ := stored["video_id"].(string)
+	if videoID == "" && content.SourceURL != nil {
+		if matches := youtubeRegex.FindStringSubmatch(*content.SourceURL); len(matches) >= 2 {
+			videoID = matches[1]
+		}
+	}
+	if videoID == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Content has no YouTube video ID", r))
+		return
 	}
-	// Check by extension as fallback
-	lower := strings.ToLower(filename)
-	return strings.HasSuffix(lower, ".pdf") || strings.HasSuffix(lower, ".docx")
+
+	if h.youtube == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "YouTube service is unavailable", r))
+		return
+	}
+
+	fetched, err := h.youtube.VideoMetadata(r.Context(), videoID, true)
+	if err != nil {
+		log.Printf("failed to refresh metadata for content %s (video %s): %v", content.ID, videoID, err)
+		writeJSON(w, http.StatusBadGateway, errorResp("UPSTREAM_ERROR", "Failed to fetch video metadata from YouTube", r))
+		return
+	}
+
+	metadata := *fetched
+	if metadata.Title == "" {
+		metadata.Title = content.Title
+	}
+	if metadata.ChannelName == "" {
+		metadata.ChannelName, _ = stored["channel_name"].(string)
+	}
+
+	stored["video_id"] = metadata.VideoID
+	stored["title"] = metadata.Title
+	stored["channel_name"] = metadata.ChannelName
+	stored["thumbnail_url"] = metadata.ThumbnailURL
+	stored["duration_seconds"] = metadata.Duration
+	if len(metadata.Chapters) > 0 {
+		stored["chapters"] = metadata.Chapters
+	} else {
+		delete(stored, "chapters")
+	}
+
+	metaBytes, _ := json.Marshal(stored)
+	if err := h.contentRepo.RefreshMetadata(r.Context(), content.ID, metadata.Title, metadata.Duration, metaBytes); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update content metadata", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content_id": content.ID,
+		"metadata":   metadata,
+	})
+}
+
+func isAllowedMimeType(policy services.UploadPolicy, mime, filename string) bool {
+	return policy.Allows(mime, filename)
 }
 
 func validateMagicBytes(data []byte, mimeType, filename string) bool {
@@ -311,9 +841,44 @@ func validateMagicBytes(data []byte, mimeType, filename string) bool {
 		return data[0] == 0x25 && data[1] == 0x50 && data[2] == 0x44 && data[3] == 0x46
 	}
 
+	if strings.HasSuffix(lowerName, ".png") || mimeType == "image/png" {
+		if len(data) < 8 {
+			return false
+		}
+		return data[0] == 0x89 && data[1] == 0x50 && data[2] == 0x4E && data[3] == 0x47 &&
+			data[4] == 0x0D && data[5] == 0x0A && data[6] == 0x1A && data[7] == 0x0A
+	}
+
+	if strings.HasSuffix(lowerName, ".jpg") || strings.HasSuffix(lowerName, ".jpeg") || mimeType == "image/jpeg" {
+		if len(data) < 3 {
+			return false
+		}
+		return data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF
+	}
+
+	if strings.HasSuffix(lowerName, ".vtt") || mimeType == "text/vtt" {
+		text := bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+		return looksLikeText(text) && bytes.HasPrefix(text, []byte("WEBVTT"))
+	}
+
+	if strings.HasSuffix(lowerName, ".srt") || mimeType == "application/x-subrip" {
+		return looksLikeText(data) && bytes.Contains(data, []byte("-->"))
+	}
+
+	if strings.HasSuffix(lowerName, ".txt") {
+		return looksLikeText(data)
+	}
+
 	return false
 }
 
+// looksLikeText reports whether the start of an upload is text rather than a
+// binary renamed to .txt, .srt or .vtt. The sniffed bytes may end
+// mid-character, so only NUL bytes are treated as binary.
+func looksLikeText(data []byte) bool {
+	return len(data) > 0 && bytes.IndexByte(data, 0) < 0
+}
+
 func getExtension(filename string) string {
 	idx := strings.LastIndex(filename, ".")
 	if idx < 0 {
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+func newDownloadTestHandler(t *testing.T, userID uuid.UUID, relPath string) (*ContentHandler, *models.Content) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, filepath.Dir(relPath)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, relPath), []byte("%PDF-1.4 lecture notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	content := &models.Content{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Type:         "file",
+		FilePath:     &relPath,
+		Title:        "Week 1.pdf",
+		MetadataJSON: json.RawMessage(`{"filename":"Week \"1\".pdf","mime_type":"application/pdf"}`),
+	}
+	h := &ContentHandler{
+		contentRepo: &stubContentRepoForContentHandler{content: content},
+		storage:     services.NewLocalStorage(root),
+		downloads:   services.NewDownloadSigner("secret", time.Minute),
+	}
+	return h, content
+}
+
+func TestDownload_OwnerGetsFile(t *testing.T) {
+	userID := uuid.New()
+	h, content := newDownloadTestHandler(t, userID, "users/"+userID.String()+"/uploads/a.pdf")
+
+	rr := httptest.NewRecorder()
+	h.Download(rr, makeContentRequest(http.MethodGet, "/api/v1/content/"+content.ID.String()+"/download", content.ID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Fatalf("unexpected content type %q", got)
+	}
+	if got := rr.Header().Get("Content-Disposition"); got != `attachment; filename="Week _1_.pdf"` {
+		t.Fatalf("unexpected content disposition %q", got)
+	}
+	if !strings.HasPrefix(rr.Body.String(), "%PDF") {
+		t.Fatalf("unexpected body %q", rr.Body.String())
+	}
+}
+
+func TestDownload_ForeignContent_Returns403(t *testing.T) {
+	owner := uuid.New()
+	h, content := newDownloadTestHandler(t, owner, "users/"+owner.String()+"/uploads/a.pdf")
+
+	rr := httptest.NewRecorder()
+	h.Download(rr, makeContentRequest(http.MethodGet, "/api/v1/content/"+content.ID.String()+"/download", content.ID, uuid.New()))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestDownload_PathOutsideStorage_Returns404(t *testing.T) {
+	userID := uuid.New()
+	h, content := newDownloadTestHandler(t, userID, "users/"+userID.String()+"/uploads/a.pdf")
+	escape := "../outside.pdf"
+	content.FilePath = &escape
+
+	rr := httptest.NewRecorder()
+	h.Download(rr, makeContentRequest(http.MethodGet, "/api/v1/content/"+content.ID.String()+"/download", content.ID, userID))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestDownloadURL_ThenSignedDownload(t *testing.T) {
+	userID := uuid.New()
+	h, content := newDownloadTestHandler(t, userID, "users/"+userID.String()+"/uploads/a.pdf")
+
+	rr := httptest.NewRecorder()
+	h.DownloadURL(rr, makeContentRequest(http.MethodGet, "/api/v1/content/"+content.ID.String()+"/download-url", content.ID, userID))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var body struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.Contains(body.Path, *content.FilePath) {
+		t.Fatalf("signed path leaks storage location: %s", body.Path)
+	}
+
+	// The public endpoint needs no user in the context.
+	rr = httptest.NewRecorder()
+	h.SignedDownload(rr, makeContentRequest(http.MethodGet, "/api/v1"+body.Path, content.ID, uuid.Nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 from signed link, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestSignedDownload_RejectsBadLinks(t *testing.T) {
+	userID := uuid.New()
+	h, content := newDownloadTestHandler(t, userID, "users/"+userID.String()+"/uploads/a.pdf")
+	expires, sig := h.downloads.Sign(content.ID, time.Now())
+	expired, expiredSig := h.downloads.Sign(content.ID, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"missing signature", "expires=" + strconv.FormatInt(expires, 10)},
+		{"tampered expiry", "expires=" + strconv.FormatInt(expires+600, 10) + "&sig=" + sig},
+		{"expired", "expires=" + strconv.FormatInt(expired, 10) + "&sig=" + expiredSig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			path := "/api/v1/content/" + content.ID.String() + "/file?" + tt.query
+			h.SignedDownload(rr, makeContentRequest(http.MethodGet, path, content.ID, uuid.Nil))
+			if rr.Code != http.StatusForbidden {
+				t.Fatalf("expected 403, got %d", rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// defaultPlaylistMaxVideos caps how many videos one playlist import creates
+// when no limit is configured.
+const defaultPlaylistMaxVideos = 25
+
+var youtubePlaylistRegex = regexp.MustCompile(`(?:youtube\.com|youtu\.be)/\S*[?&]list=([\w-]+)`)
+
+type playlistFetcher interface {
+	GetPlaylist(ctx context.Context, playlistID string) (*services.YouTubePlaylist, error)
+}
+
+// SetPlaylistLimit caps how many videos a playlist import creates. A limit
+// of zero or less keeps the default.
+func (h *ContentHandler) SetPlaylistLimit(max int) {
+	h.playlistMax = max
+}
+
+func (h *ContentHandler) playlistLimit() int {
+	if h.playlistMax <= 0 {
+		return defaultPlaylistMaxVideos
+	}
+	return h.playlistMax
+}
+
+// ValidatePlaylist imports a YouTube playlist: each video becomes its own
+// content record with a content-processing job, as if it had been sent to
+// ValidateYouTube. Private and deleted videos, and those past the playlist
+// limit, are skipped and reported as warnings.
+func (h *ContentHandler) ValidatePlaylist(w http.ResponseWriter, r *http.Request) {
+	var req models.ValidateYouTubeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	matches := youtubePlaylistRegex.FindStringSubmatch(req.URL)
+	if len(matches) < 2 {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid YouTube playlist URL", r))
+		return
+	}
+	playlistID := matches[1]
+	userID := middleware.GetUserID(r.Context())
+
+	captionLanguage, ok := h.requestedCaptionLanguage(w, r, userID, req.CaptionLanguage)
+	if !ok {
+		return
+	}
+
+	if h.playlists == nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "YouTube service is unavailable", r))
+		return
+	}
+	playlist, err := h.playlists.GetPlaylist(r.Context(), playlistID)
+	if err != nil {
+		log.Printf("ContentHandler.ValidatePlaylist: playlist %s: %v", playlistID, err)
+		writeJSON(w, http.StatusBadGateway, errorResp("UPSTREAM_ERROR", "Failed to fetch the playlist from YouTube; it may be private or deleted", r))
+		return
+	}
+
+	videos := []models.PlaylistVideoResult{}
+	contentIDs := []string{}
+	warnings := []models.PlaylistWarning{}
+	seen := map[string]bool{}
+	limit := h.playlistLimit()
+	truncated := false
+
+	for i, entry := range playlist.Videos {
+		if entry.Unavailable {
+			warnings = append(warnings, models.PlaylistWarning{VideoID: entry.VideoID, Title: entry.Title, Reason: "unavailable"})
+			continue
+		}
+		if seen[entry.VideoID] {
+			continue
+		}
+		if len(videos) == limit {
+			truncated = true
+			warnings = append(warnings, skippedPlaylistVideos(playlist.Videos[i:], seen, "playlist_limit")...)
+			break
+		}
+		seen[entry.VideoID] = true
+
+		result, err := h.importPlaylistVideo(r, userID, entry, captionLanguage)
+		if err != nil {
+			log.Printf("ContentHandler.ValidatePlaylist: playlist %s video %s: %v", playlistID, entry.VideoID, err)
+			if len(videos) == 0 {
+				if errors.Is(err, errContentQueueUnavailable) {
+					writeQueueUnavailable(w, r)
+				} else {
+					writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to import playlist", r))
+				}
+				return
+			}
+			// What was queued stays queued; the rest is reported so the
+			// import can be retried later.
+			reason := "import_failed"
+			if errors.Is(err, errContentQueueUnavailable) {
+				reason = "queue_unavailable"
+			}
+			delete(seen, entry.VideoID)
+			warnings = append(warnings, skippedPlaylistVideos(playlist.Videos[i:], seen, reason)...)
+			break
+		}
+		videos = append(videos, *result)
+		contentIDs = append(contentIDs, result.ContentID.String())
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"playlist_id": playlistID,
+		"title":       playlist.Title,
+		"content_ids": contentIDs,
+		"videos":      videos,
+		"warnings":    warnings,
+		"truncated":   truncated,
+		"max_videos":  limit,
+	})
+}
+
+// importPlaylistVideo creates and queues the content of one playlist video,
+// or reuses already processed content of the same video.
+func (h *ContentHandler) importPlaylistVideo(r *http.Request, userID uuid.UUID, entry services.YouTubePlaylistVideo, captionLanguage string) (*models.PlaylistVideoResult, error) {
+	result := &models.PlaylistVideoResult{VideoID: entry.VideoID, Title: entry.Title}
+
+	contentHash := youtubeContentHash(entry.VideoID, captionLanguage)
+	if existing := h.findDuplicate(r, userID, contentHash); existing != nil {
+		result.ContentID = existing.ID
+		result.Deduplicated = true
+		return result, nil
+	}
+
+	metadata, cached := h.youtubeVideoMetadata(r.Context(), entry.VideoID)
+	if !cached {
+		// Not scraped yet, but the playlist already knows the title and length.
+		if entry.Title != "" {
+			metadata.Title = entry.Title
+		}
+		metadata.Duration = entry.DurationSec
+	}
+	metadata.CaptionLanguage = captionLanguage
+
+	content, err := h.createYouTubeContent(r.Context(), userID, "https://www.youtube.com/watch?v="+entry.VideoID, contentHash, metadata)
+	if err != nil {
+		return nil, err
+	}
+	job, err := h.enqueueContentProcessing(r.Context(), userID, content.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Title = metadata.Title
+	result.ContentID = content.ID
+	result.JobID = &job.ID
+	return result, nil
+}
+
+// skippedPlaylistVideos reports the available videos of entries that were not
+// imported, once each.
+func skippedPlaylistVideos(entries []services.YouTubePlaylistVideo, imported map[string]bool, reason string) []models.PlaylistWarning {
+	var warnings []models.PlaylistWarning
+	reported := map[string]bool{}
+	for _, entry := range entries {
+		if entry.Unavailable {
+			warnings = append(warnings, models.PlaylistWarning{VideoID: entry.VideoID, Title: entry.Title, Reason: "unavailable"})
+			continue
+		}
+		if imported[entry.VideoID] || reported[entry.VideoID] {
+			continue
+		}
+		reported[entry.VideoID] = true
+		warnings = append(warnings, models.PlaylistWarning{VideoID: entry.VideoID, Title: entry.Title, Reason: reason})
+	}
+	return warnings
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type stubPlaylistFetcher struct {
+	playlist  *services.YouTubePlaylist
+	requested string
+}
+
+func (s *stubPlaylistFetcher) GetPlaylist(ctx context.Context, playlistID string) (*services.YouTubePlaylist, error) {
+	s.requested = playlistID
+	return s.playlist, nil
+}
+
+func makePlaylistRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/validate-playlist", strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+}
+
+func TestValidatePlaylist_QueuesEachVideoAndSkipsUnavailable(t *testing.T) {
+	contentRepo := &stubContentRepoForContentHandler{}
+	jobRepo := &stubJobRepoForContentHandler{}
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	redisClient.AddHook(&recordingRedisHook{})
+	defer redisClient.Close()
+
+	fetcher := &stubPlaylistFetcher{playlist: &services.YouTubePlaylist{ID: "PLcourse", Title: "Course", Videos: []services.YouTubePlaylistVideo{
+		{VideoID: "aaaaaaaaaaa", Title: "Lecture 1", DurationSec: 600},
+		{VideoID: "bbbbbbbbbbb", Title: "[Private video]", Unavailable: true},
+		{VideoID: "aaaaaaaaaaa", Title: "Lecture 1"},
+		{VideoID: "ccccccccccc", Title: "Lecture 2"},
+		{VideoID: "ddddddddddd", Title: "Lecture 3"},
+	}}}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, redis: redisClient, playlists: fetcher, playlistMax: 2}
+
+	res := httptest.NewRecorder()
+	h.ValidatePlaylist(res, makePlaylistRequest(`{"url":"https://www.youtube.com/watch?v=aaaaaaaaaaa&list=PLcourse&index=1","caption_language":"en"}`))
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+	if fetcher.requested != "PLcourse" {
+		t.Fatalf("fetched playlist %q, want PLcourse", fetcher.requested)
+	}
+	if len(contentRepo.created) != 2 || len(jobRepo.createdJobs) != 2 {
+		t.Fatalf("expected two content records and jobs, got %d and %d", len(contentRepo.created), len(jobRepo.createdJobs))
+	}
+	if contentRepo.created[0].Title != "Lecture 1" || *contentRepo.created[0].SourceURL != "https://www.youtube.com/watch?v=aaaaaaaaaaa" {
+		t.Fatalf("unexpected first content %+v", contentRepo.created[0])
+	}
+
+	var payload struct {
+		ContentIDs []string                 `json:"content_ids"`
+		Warnings   []models.PlaylistWarning `json:"warnings"`
+		Truncated  bool                     `json:"truncated"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(payload.ContentIDs) != 2 || payload.ContentIDs[1] != contentRepo.created[1].ID.String() {
+		t.Fatalf("content_ids = %v, want the two created records", payload.ContentIDs)
+	}
+	if !payload.Truncated || len(payload.Warnings) != 2 {
+		t.Fatalf("warnings = %+v truncated = %v, want the private video and the one past the limit", payload.Warnings, payload.Truncated)
+	}
+	if payload.Warnings[0].Reason != "unavailable" || payload.Warnings[1].VideoID != "ddddddddddd" || payload.Warnings[1].Reason != "playlist_limit" {
+		t.Fatalf("unexpected warnings %+v", payload.Warnings)
+	}
+}
+
+func TestValidatePlaylist_SingleVideoURL_Returns400(t *testing.T) {
+	fetcher := &stubPlaylistFetcher{}
+	h := &ContentHandler{contentRepo: &stubContentRepoForContentHandler{}, jobRepo: &stubJobRepoForContentHandler{}, playlists: fetcher}
+
+	res := httptest.NewRecorder()
+	h.ValidatePlaylist(res, makePlaylistRequest(`{"url":"https://youtu.be/dQw4w9WgXcQ"}`))
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, res.Code)
+	}
+	if fetcher.requested != "" {
+		t.Fatalf("expected the playlist not to be fetched")
+	}
+}
+
+func TestValidatePlaylist_QueueFailure_Returns503(t *testing.T) {
+	jobRepo := &stubJobRepoForContentHandler{}
+	fetcher := &stubPlaylistFetcher{playlist: &services.YouTubePlaylist{Videos: []services.YouTubePlaylistVideo{{VideoID: "aaaaaaaaaaa", Title: "Lecture 1"}}}}
+	h := &ContentHandler{contentRepo: &stubContentRepoForContentHandler{}, jobRepo: jobRepo, playlists: fetcher}
+
+	res := httptest.NewRecorder()
+	h.ValidatePlaylist(res, makePlaylistRequest(`{"url":"https://www.youtube.com/playlist?list=PLcourse"}`))
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, res.Code)
+	}
+	if len(jobRepo.updatedStatuses) != 1 || jobRepo.updatedStatuses[0] != "failed" {
+		t.Fatalf("expected the job to be marked failed, got %v", jobRepo.updatedStatuses)
+	}
+}
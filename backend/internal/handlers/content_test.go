@@ -2,22 +2,33 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 
 	"lectura-backend/internal/middleware"
 	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
 )
 
 type stubContentRepoForContentHandler struct {
-	created []*models.Content
+	created    []*models.Content
+	content    *models.Content
+	refreshed  bool
+	reset      bool
+	duplicate  *models.Content
+	lookedUpBy []string
 }
 
 func (s *stubContentRepoForContentHandler) Create(ctx context.Context, c *models.Content) error {
@@ -29,13 +40,35 @@ func (s *stubContentRepoForContentHandler) Create(ctx context.Context, c *models
 }
 
 func (s *stubContentRepoForContentHandler) GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error) {
-	return nil, nil
+	if s.content == nil {
+		return nil, context.Canceled
+	}
+	return s.content, nil
+}
+
+func (s *stubContentRepoForContentHandler) RefreshMetadata(ctx context.Context, id uuid.UUID, title string, durationSeconds int, metadataJSON json.RawMessage) error {
+	s.refreshed = true
+	return nil
+}
+
+func (s *stubContentRepoForContentHandler) ResetForReprocessing(ctx context.Context, id uuid.UUID) error {
+	s.reset = true
+	return nil
+}
+
+func (s *stubContentRepoForContentHandler) FindCompletedByHash(ctx context.Context, userID uuid.UUID, hash string) (*models.Content, error) {
+	s.lookedUpBy = append(s.lookedUpBy, hash)
+	if s.duplicate == nil || s.duplicate.ContentHash == nil || *s.duplicate.ContentHash != hash {
+		return nil, pgx.ErrNoRows
+	}
+	return s.duplicate, nil
 }
 
 type stubJobRepoForContentHandler struct {
 	createdJobs      []*models.Job
 	updatedStatuses  []string
 	updatedStatusIDs []uuid.UUID
+	latestJob        *models.Job
 }
 
 func (s *stubJobRepoForContentHandler) Create(ctx context.Context, j *models.Job) error {
@@ -47,6 +80,13 @@ func (s *stubJobRepoForContentHandler) Create(ctx context.Context, j *models.Job
 	return nil
 }
 
+func (s *stubJobRepoForContentHandler) GetLatestByReference(ctx context.Context, referenceID uuid.UUID, jobType string) (*models.Job, error) {
+	if s.latestJob == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return s.latestJob, nil
+}
+
 func (s *stubJobRepoForContentHandler) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
 	s.updatedStatusIDs = append(s.updatedStatusIDs, id)
 	s.updatedStatuses = append(s.updatedStatuses, status)
@@ -68,8 +108,8 @@ func TestValidateYouTube_QueueFailure_MarksJobFailed(t *testing.T) {
 
 	h.ValidateYouTube(res, req)
 
-	if res.Code != http.StatusInternalServerError {
-		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, res.Code)
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, res.Code)
 	}
 
 	if len(jobRepo.updatedStatuses) == 0 || jobRepo.updatedStatuses[len(jobRepo.updatedStatuses)-1] != "failed" {
@@ -102,8 +142,8 @@ func TestValidateYouTube_ReturnsQuicklyWithoutUpstreamDependency(t *testing.T) {
 		t.Fatalf("expected ValidateYouTube to return quickly without upstream requests")
 	}
 
-	if res.Code != http.StatusInternalServerError {
-		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, res.Code)
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, res.Code)
 	}
 
 	var payload map[string]any
@@ -131,7 +171,7 @@ func TestUpload_QueueFailure_MarksJobFailed(t *testing.T) {
 	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
 	defer redisClient.Close()
 
-	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, redis: redisClient, storagePath: t.TempDir()}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, redis: redisClient, storage: services.NewLocalStorage(t.TempDir())}
 
 	data := "--boundary\r\n" +
 		"Content-Disposition: form-data; name=\"file\"; filename=\"note.txt\"\r\n" +
@@ -145,8 +185,8 @@ func TestUpload_QueueFailure_MarksJobFailed(t *testing.T) {
 
 	h.Upload(res, req)
 
-	if res.Code != http.StatusInternalServerError {
-		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, res.Code)
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, res.Code)
 	}
 
 	if len(jobRepo.updatedStatuses) == 0 || jobRepo.updatedStatuses[len(jobRepo.updatedStatuses)-1] != "failed" {
@@ -163,3 +203,537 @@ func TestUpload_QueueFailure_MarksJobFailed(t *testing.T) {
 	}
 }
 
+func TestUpload_TextFile_IsStoredAndExtractable(t *testing.T) {
+	contentRepo := &stubContentRepoForContentHandler{}
+	jobRepo := &stubJobRepoForContentHandler{}
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	hook := &recordingRedisHook{}
+	redisClient.AddHook(hook)
+	defer redisClient.Close()
+
+	storage := services.NewLocalStorage(t.TempDir())
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, redis: redisClient, storage: storage}
+
+	text := "Photosynthesis turns light into chemical energy.\n\nThe Calvin cycle fixes carbon."
+	data := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"notes.txt\"\r\n" +
+		"Content-Type: text/plain\r\n\r\n" +
+		text + "\r\n" +
+		"--boundary--\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/upload", strings.NewReader(data))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+
+	h.Upload(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+	if len(contentRepo.created) != 1 || len(jobRepo.createdJobs) != 1 || len(hook.commands) == 0 {
+		t.Fatalf("expected one content record with a queued job")
+	}
+	if job := jobRepo.createdJobs[0]; job.Type != "content-processing" || job.ReferenceID != contentRepo.created[0].ID {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	path, ok := storage.LocalPath(*contentRepo.created[0].FilePath)
+	if !ok {
+		t.Fatalf("stored path %q is not a valid storage key", *contentRepo.created[0].FilePath)
+	}
+	extracted, err := services.NewFileExtractService().ExtractTextFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to extract stored upload: %v", err)
+	}
+	if extracted != text {
+		t.Fatalf("extracted %q, want %q", extracted, text)
+	}
+}
+
+
+func TestValidateMagicBytes_AcceptsImagesForOCR(t *testing.T) {
+	png := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00}
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00}
+
+	if !isAllowedMimeType(services.DefaultUploadPolicy(), "image/png", "slide.png") || !validateMagicBytes(png, "image/png", "slide.png") {
+		t.Fatalf("expected png upload to be accepted")
+	}
+	if !isAllowedMimeType(services.DefaultUploadPolicy(), "image/jpeg", "board.jpg") || !validateMagicBytes(jpeg, "image/jpeg", "board.jpg") {
+		t.Fatalf("expected jpeg upload to be accepted")
+	}
+	if validateMagicBytes(jpeg, "image/png", "slide.png") {
+		t.Fatalf("expected jpeg bytes declared as png to be rejected")
+	}
+}
+
+func TestValidateMagicBytes_AcceptsSubtitles(t *testing.T) {
+	srt := []byte("1\r\n00:00:01,000 --> 00:00:03,500\r\nWelcome to the lecture.\r\n")
+	vtt := []byte("\xEF\xBB\xBFWEBVTT\n\n00:01.000 --> 00:03.500\nWelcome to the lecture.\n")
+	mimeType := http.DetectContentType(srt)
+
+	if !isAllowedMimeType(services.DefaultUploadPolicy(), mimeType, "lecture.srt") || !validateMagicBytes(srt, mimeType, "lecture.srt") {
+		t.Fatalf("expected srt upload to be accepted")
+	}
+	if !isAllowedMimeType(services.DefaultUploadPolicy(), mimeType, "lecture.vtt") || !validateMagicBytes(vtt, mimeType, "lecture.vtt") {
+		t.Fatalf("expected vtt upload to be accepted")
+	}
+	if validateMagicBytes(srt, mimeType, "lecture.vtt") {
+		t.Fatalf("expected vtt without a WEBVTT header to be rejected")
+	}
+	if validateMagicBytes([]byte{0x50, 0x4B, 0x03, 0x04, 0x00, '-', '-', '>'}, "application/zip", "lecture.srt") {
+		t.Fatalf("expected binary data renamed to .srt to be rejected")
+	}
+}
+
+func TestRefreshMetadata_RejectsNonYouTubeContent(t *testing.T) {
+	userID := uuid.New()
+	contentID := uuid.New()
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: userID, Type: "file"}}
+	h := &ContentHandler{contentRepo: contentRepo}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", contentID.String())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/"+contentID.String()+"/refresh-metadata", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	res := httptest.NewRecorder()
+
+	h.RefreshMetadata(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, res.Code)
+	}
+	if contentRepo.refreshed {
+		t.Fatalf("metadata should not be refreshed for file content")
+	}
+}
+
+func TestRefreshMetadata_NonOwnerForbidden(t *testing.T) {
+	contentID := uuid.New()
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: uuid.New(), Type: "youtube"}}
+	h := &ContentHandler{contentRepo: contentRepo}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", contentID.String())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/"+contentID.String()+"/refresh-metadata", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+
+	h.RefreshMetadata(res, req)
+
+	if res.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, res.Code)
+	}
+}
+
+func TestUpload_OverConfiguredLimit_Returns413(t *testing.T) {
+	h := &ContentHandler{uploads: services.NewUploadPolicy(1024*1024, nil)}
+
+	data := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"big.pdf\"\r\n" +
+		"Content-Type: application/pdf\r\n\r\n" +
+		"%PDF-" + strings.Repeat("a", 2*1024*1024) + "\r\n" +
+		"--boundary--\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/upload", strings.NewReader(data))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+
+	h.Upload(res, req)
+
+	if res.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d", http.StatusRequestEntityTooLarge, res.Code)
+	}
+	if !strings.Contains(res.Body.String(), "1 MB") {
+		t.Fatalf("expected configured limit in error message, got %s", res.Body.String())
+	}
+}
+
+func TestIsAllowedMimeType_RespectsConfiguredExtensions(t *testing.T) {
+	policy := services.NewUploadPolicy(0, []string{"pdf"})
+
+	if !isAllowedMimeType(policy, "application/pdf", "notes.pdf") {
+		t.Fatalf("expected pdf upload to be accepted")
+	}
+	if isAllowedMimeType(policy, "image/png", "slide.png") {
+		t.Fatalf("expected png upload to be rejected when not configured")
+	}
+}
+
+func makeContentRequest(method, path string, contentID, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", contentID.String())
+	req := httptest.NewRequest(method, path, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestReprocess_MissingFile_Returns404(t *testing.T) {
+	userID := uuid.New()
+	contentID := uuid.New()
+	path := "users/" + userID.String() + "/uploads/gone.pdf"
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: userID, Type: "file", Status: "failed", FilePath: &path}}
+	jobRepo := &stubJobRepoForContentHandler{}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, storage: services.NewLocalStorage(t.TempDir())}
+
+	res := httptest.NewRecorder()
+	h.Reprocess(res, makeContentRequest(http.MethodPost, "/api/v1/content/"+contentID.String()+"/reprocess", contentID, userID))
+
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, res.Code)
+	}
+	if contentRepo.reset || len(jobRepo.createdJobs) != 0 {
+		t.Fatalf("expected no reset or job creation for a missing file")
+	}
+}
+
+func TestReprocess_InProgress_Returns409(t *testing.T) {
+	userID := uuid.New()
+	contentID := uuid.New()
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: userID, Type: "youtube", Status: "processing"}}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: &stubJobRepoForContentHandler{}}
+
+	res := httptest.NewRecorder()
+	h.Reprocess(res, makeContentRequest(http.MethodPost, "/api/v1/content/"+contentID.String()+"/reprocess", contentID, userID))
+
+	if res.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, res.Code)
+	}
+}
+
+func TestReprocess_NonOwnerForbidden(t *testing.T) {
+	contentID := uuid.New()
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: uuid.New(), Type: "youtube", Status: "failed"}}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: &stubJobRepoForContentHandler{}}
+
+	res := httptest.NewRecorder()
+	h.Reprocess(res, makeContentRequest(http.MethodPost, "/api/v1/content/"+contentID.String()+"/reprocess", contentID, uuid.New()))
+
+	if res.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, res.Code)
+	}
+}
+
+func TestGetContent_Failed_IncludesJobError(t *testing.T) {
+	userID := uuid.New()
+	contentID := uuid.New()
+	errMsg := "failed to extract file text"
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: userID, Type: "file", Status: "failed"}}
+	jobRepo := &stubJobRepoForContentHandler{latestJob: &models.Job{ErrorMessage: &errMsg}}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo}
+
+	res := httptest.NewRecorder()
+	h.GetContent(res, makeContentRequest(http.MethodGet, "/api/v1/content/"+contentID.String(), contentID, userID))
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, res.Code)
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload["error_message"] != errMsg {
+		t.Fatalf("expected error_message %q, got %v", errMsg, payload["error_message"])
+	}
+}
+
+func TestGetContent_BlockedBySafety_IncludesErrorCode(t *testing.T) {
+	userID := uuid.New()
+	contentID := uuid.New()
+	errMsg := "this content couldn't be processed due to safety filters [prompt blocked: SAFETY]"
+	errCode := "blocked_by_safety"
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: userID, Type: "youtube", Status: "failed"}}
+	jobRepo := &stubJobRepoForContentHandler{latestJob: &models.Job{ErrorMessage: &errMsg, ErrorCode: &errCode}}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo}
+
+	res := httptest.NewRecorder()
+	h.GetContent(res, makeContentRequest(http.MethodGet, "/api/v1/content/"+contentID.String(), contentID, userID))
+
+	var payload map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload["error_code"] != errCode {
+		t.Fatalf("expected error_code %q, got %v", errCode, payload["error_code"])
+	}
+}
+
+func TestGetContent_YouTube_IncludesChapters(t *testing.T) {
+	userID := uuid.New()
+	contentID := uuid.New()
+	meta := json.RawMessage(`{"video_id":"dQw4w9WgXcQ","chapters":[{"title":"Intro","start_seconds":0},{"title":"Enzymes","start_seconds":245}]}`)
+	contentRepo := &stubContentRepoForContentHandler{content: &models.Content{ID: contentID, UserID: userID, Type: "youtube", Status: "completed", MetadataJSON: meta}}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: &stubJobRepoForContentHandler{}}
+
+	res := httptest.NewRecorder()
+	h.GetContent(res, makeContentRequest(http.MethodGet, "/api/v1/content/"+contentID.String(), contentID, userID))
+
+	var payload struct {
+		Chapters []models.VideoChapter `json:"chapters"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(payload.Chapters) != 2 || payload.Chapters[1].Title != "Enzymes" || payload.Chapters[1].StartSeconds != 245 {
+		t.Fatalf("chapters = %+v, want the two stored chapters", payload.Chapters)
+	}
+}
+
+type stubSettingsRepoForContentHandler struct {
+	language string
+	redact   bool
+}
+
+func (s *stubSettingsRepoForContentHandler) GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error) {
+	return &models.UserSettings{UserID: userID, Language: s.language, RedactTranscripts: s.redact}, nil
+}
+
+func TestValidateYouTube_CaptionLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		settings string
+		want     string
+	}{
+		{"explicit language", `{"url":"https://youtu.be/dQw4w9WgXcQ","caption_language":"ES"}`, "en", "es"},
+		{"defaults to account language", `{"url":"https://youtu.be/dQw4w9WgXcQ"}`, "de", "de"},
+		{"explicit auto", `{"url":"https://youtu.be/dQw4w9WgXcQ","caption_language":"auto"}`, "en", services.CaptionLanguageAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentRepo := &stubContentRepoForContentHandler{}
+			h := &ContentHandler{
+				contentRepo:  contentRepo,
+				jobRepo:      &stubJobRepoForContentHandler{},
+				settingsRepo: &stubSettingsRepoForContentHandler{language: tt.settings},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/content/validate-youtube", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+			h.ValidateYouTube(httptest.NewRecorder(), req)
+
+			if len(contentRepo.created) != 1 {
+				t.Fatalf("expected content to be created")
+			}
+			var meta models.YouTubeMetadata
+			if err := json.Unmarshal(contentRepo.created[0].MetadataJSON, &meta); err != nil {
+				t.Fatalf("invalid metadata: %v", err)
+			}
+			if meta.CaptionLanguage != tt.want {
+				t.Fatalf("expected caption language %q, got %q", tt.want, meta.CaptionLanguage)
+			}
+		})
+	}
+}
+
+func TestValidateYouTube_InvalidCaptionLanguage_Returns400(t *testing.T) {
+	contentRepo := &stubContentRepoForContentHandler{}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: &stubJobRepoForContentHandler{}}
+
+	body := `{"url":"https://youtu.be/dQw4w9WgXcQ","caption_language":"english please"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/validate-youtube", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+	h.ValidateYouTube(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", res.Code)
+	}
+	if len(contentRepo.created) != 0 {
+		t.Fatalf("expected no content to be created")
+	}
+}
+
+func TestValidateYouTube_Duplicate_ReturnsExistingContent(t *testing.T) {
+	hash := youtubeContentHash("dQw4w9WgXcQ", "en")
+	existing := &models.Content{ID: uuid.New(), Status: "completed", ContentHash: &hash, MetadataJSON: json.RawMessage(`{"video_id":"dQw4w9WgXcQ"}`)}
+	contentRepo := &stubContentRepoForContentHandler{duplicate: existing}
+	jobRepo := &stubJobRepoForContentHandler{}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo}
+
+	body := `{"url":"https://www.youtube.com/watch?v=dQw4w9WgXcQ","caption_language":"en"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/validate-youtube", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+
+	h.ValidateYouTube(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload["content_id"] != existing.ID.String() || payload["deduplicated"] != true {
+		t.Fatalf("expected existing content to be returned, got %v", payload)
+	}
+	if len(contentRepo.created) != 0 || len(jobRepo.createdJobs) != 0 {
+		t.Fatalf("expected no new content or job, got %d content and %d jobs", len(contentRepo.created), len(jobRepo.createdJobs))
+	}
+}
+
+func TestValidateYouTube_Force_SkipsDeduplication(t *testing.T) {
+	hash := youtubeContentHash("dQw4w9WgXcQ", "en")
+	existing := &models.Content{ID: uuid.New(), Status: "completed", ContentHash: &hash}
+	contentRepo := &stubContentRepoForContentHandler{duplicate: existing}
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: &stubJobRepoForContentHandler{}}
+
+	body := `{"url":"https://www.youtube.com/watch?v=dQw4w9WgXcQ","caption_language":"en"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/validate-youtube?force=true", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+
+	h.ValidateYouTube(res, req)
+
+	if len(contentRepo.lookedUpBy) != 0 {
+		t.Fatalf("expected force to skip the duplicate lookup")
+	}
+	if len(contentRepo.created) != 1 {
+		t.Fatalf("expected a new content record, got %d", len(contentRepo.created))
+	}
+	if got := contentRepo.created[0].ContentHash; got == nil || *got != hash {
+		t.Fatalf("expected content hash %q to be stored, got %v", hash, got)
+	}
+}
+
+func TestValidateYouTube_RedactionSettingChanged_SkipsDeduplication(t *testing.T) {
+	hash := youtubeContentHash("dQw4w9WgXcQ", "en")
+	existing := &models.Content{ID: uuid.New(), Status: "completed", ContentHash: &hash}
+	contentRepo := &stubContentRepoForContentHandler{duplicate: existing}
+	h := &ContentHandler{
+		contentRepo:  contentRepo,
+		jobRepo:      &stubJobRepoForContentHandler{},
+		settingsRepo: &stubSettingsRepoForContentHandler{redact: true},
+	}
+
+	body := `{"url":"https://www.youtube.com/watch?v=dQw4w9WgXcQ","caption_language":"en"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/validate-youtube", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	h.ValidateYouTube(httptest.NewRecorder(), req)
+
+	if len(contentRepo.created) != 1 {
+		t.Fatalf("expected unredacted content not to be reused once redaction is on, got %d new records", len(contentRepo.created))
+	}
+}
+
+func TestUpload_Duplicate_ReturnsExistingContent(t *testing.T) {
+	sum := sha256.Sum256([]byte("%PDF-1.4 lecture notes"))
+	hash := "sha256:" + hex.EncodeToString(sum[:])
+	existing := &models.Content{ID: uuid.New(), Status: "completed", ContentHash: &hash}
+	contentRepo := &stubContentRepoForContentHandler{duplicate: existing}
+	jobRepo := &stubJobRepoForContentHandler{}
+	storage := t.TempDir()
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, storage: services.NewLocalStorage(storage)}
+
+	data := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"notes.pdf\"\r\n" +
+		"Content-Type: application/pdf\r\n\r\n" +
+		"%PDF-1.4 lecture notes\r\n" +
+		"--boundary--\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/upload", strings.NewReader(data))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	res := httptest.NewRecorder()
+
+	h.Upload(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload["content_id"] != existing.ID.String() || payload["deduplicated"] != true {
+		t.Fatalf("expected existing content to be returned, got %v", payload)
+	}
+	if len(contentRepo.created) != 0 || len(jobRepo.createdJobs) != 0 {
+		t.Fatalf("expected no new content or job, got %d content and %d jobs", len(contentRepo.created), len(jobRepo.createdJobs))
+	}
+	if entries, _ := os.ReadDir(storage); len(entries) != 0 {
+		t.Fatalf("expected duplicate upload not to be stored, found %d entries", len(entries))
+	}
+}
+
+// recordingRedisHook answers every command itself, so handlers can be tested
+// against a redis.Client without a server.
+type recordingRedisHook struct {
+	commands [][]interface{}
+}
+
+func (h *recordingRedisHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *recordingRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.commands = append(h.commands, cmd.Args())
+		return nil
+	}
+}
+
+func (h *recordingRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (h *recordingRedisHook) find(name string) []interface{} {
+	for _, args := range h.commands {
+		if len(args) > 0 && args[0] == name {
+			return args
+		}
+	}
+	return nil
+}
+
+func TestUpload_QueuesContentProcessingJob(t *testing.T) {
+	contentRepo := &stubContentRepoForContentHandler{}
+	jobRepo := &stubJobRepoForContentHandler{}
+	hook := &recordingRedisHook{}
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	redisClient.AddHook(hook)
+	defer redisClient.Close()
+
+	userID := uuid.New()
+	h := &ContentHandler{contentRepo: contentRepo, jobRepo: jobRepo, redis: redisClient, storage: services.NewLocalStorage(t.TempDir())}
+
+	data := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"notes.pdf\"\r\n" +
+		"Content-Type: application/pdf\r\n\r\n" +
+		"%PDF-1.4 lecture notes\r\n" +
+		"--boundary--\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/content/upload", strings.NewReader(data))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	res := httptest.NewRecorder()
+
+	h.Upload(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, res.Code, res.Body.String())
+	}
+	if len(contentRepo.created) != 1 || len(jobRepo.createdJobs) != 1 {
+		t.Fatalf("expected one content record and one job, got %d and %d", len(contentRepo.created), len(jobRepo.createdJobs))
+	}
+	job := jobRepo.createdJobs[0]
+	if job.Type != "content-processing" || job.ReferenceID != contentRepo.created[0].ID || job.UserID != userID {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	push := hook.find("lpush")
+	if push == nil || push[1] != "queue:content-processing" || !strings.Contains(push[2].(string), job.ID.String()) {
+		t.Fatalf("expected job to be pushed onto the content-processing queue, got %v", hook.commands)
+	}
+	publish := hook.find("publish")
+	if publish == nil || publish[1] != "user_updates:"+userID.String() || !strings.Contains(publish[2].(string), "status_update") {
+		t.Fatalf("expected a queued status update, got %v", hook.commands)
+	}
+
+	var payload map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload["job_id"] != job.ID.String() {
+		t.Fatalf("expected job_id %s in response, got %v", job.ID, payload["job_id"])
+	}
+}
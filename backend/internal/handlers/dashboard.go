@@ -6,6 +6,7 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/sync/errgroup"
 
@@ -27,8 +29,13 @@ type DashboardHandler struct {
 	pool          *pgxpool.Pool
 	userRepo      *repository.UserRepo
 	recentFetcher func(ctx context.Context, userID uuid.UUID, limit int) ([]dashboardRecentItem, error)
+	todayFetcher  func(ctx context.Context, userID uuid.UUID, dayStart time.Time) (*dashboardToday, error)
 }
 
+// dashboardRecentLimit is how many items the recent list and the today
+// payload return.
+const dashboardRecentLimit = 12
+
 func NewDashboardHandler(pool *pgxpool.Pool, userRepo *repository.UserRepo) *DashboardHandler {
 	return &DashboardHandler{pool: pool, userRepo: userRepo}
 }
@@ -53,6 +60,7 @@ const recentActivityQuery = `
 		FROM summaries s
 		WHERE s.user_id = $1
 		  AND s.is_archived = FALSE
+		  AND s.deleted_at IS NULL
 
 		UNION ALL
 
@@ -73,6 +81,7 @@ const recentActivityQuery = `
 			LIMIT 1
 		) qa ON true
 		WHERE q.user_id = $1
+		  AND q.deleted_at IS NULL
 
 		UNION ALL
 
@@ -84,6 +93,7 @@ const recentActivityQuery = `
 			0::float8 AS progress
 		FROM flashcard_decks f
 		WHERE f.user_id = $1
+		  AND f.deleted_at IS NULL
 
 		UNION ALL
 
@@ -101,7 +111,13 @@ const recentActivityQuery = `
 `
 
 func (h *DashboardHandler) fetchRecentFromDB(ctx context.Context, userID uuid.UUID, limit int) ([]dashboardRecentItem, error) {
-	rows, err := h.pool.Query(ctx, recentActivityQuery, userID, limit)
+	return queryRecentItems(ctx, h.pool, userID, limit)
+}
+
+func queryRecentItems(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, userID uuid.UUID, limit int) ([]dashboardRecentItem, error) {
+	rows, err := q.Query(ctx, recentActivityQuery, userID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -133,15 +149,15 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	var weeklyGoalType string
 
 	g.Go(func() error {
-		return h.pool.QueryRow(gctx, "SELECT COUNT(*) FROM summaries WHERE user_id = $1", userID).Scan(&summaryCount)
+		return h.pool.QueryRow(gctx, "SELECT COUNT(*) FROM summaries WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&summaryCount)
 	})
 
 	g.Go(func() error {
-		return h.pool.QueryRow(gctx, "SELECT COUNT(*) FROM quizzes WHERE user_id = $1", userID).Scan(&quizCount)
+		return h.pool.QueryRow(gctx, "SELECT COUNT(*) FROM quizzes WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&quizCount)
 	})
 
 	g.Go(func() error {
-		return h.pool.QueryRow(gctx, "SELECT COUNT(*) FROM flashcard_decks WHERE user_id = $1", userID).Scan(&flashcardCount)
+		return h.pool.QueryRow(gctx, "SELECT COUNT(*) FROM flashcard_decks WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&flashcardCount)
 	})
 
 	g.Go(func() error {
@@ -153,6 +169,7 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 			SELECT COUNT(*)
 			FROM summaries
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND is_archived = FALSE
 			  AND created_at >= NOW() - INTERVAL '7 days'
 		`, userID).Scan(&weeklySummaryCount)
@@ -163,6 +180,7 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 			SELECT COUNT(*)
 			FROM quizzes
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND created_at >= NOW() - INTERVAL '7 days'
 		`, userID).Scan(&weeklyQuizCount)
 	})
@@ -172,6 +190,7 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 			SELECT COUNT(*)
 			FROM flashcard_decks
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND created_at >= NOW() - INTERVAL '7 days'
 		`, userID).Scan(&weeklyFlashcardCount)
 	})
@@ -190,6 +209,7 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 			SELECT COUNT(*)
 			FROM summaries
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND is_archived = FALSE
 			  AND created_at >= NOW() - INTERVAL '14 days'
 			  AND created_at < NOW() - INTERVAL '7 days'
@@ -201,6 +221,7 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 			SELECT COUNT(*)
 			FROM quizzes
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND created_at >= NOW() - INTERVAL '14 days'
 			  AND created_at < NOW() - INTERVAL '7 days'
 		`, userID).Scan(&prevWeeklyQuizCount)
@@ -211,6 +232,7 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 			SELECT COUNT(*)
 			FROM flashcard_decks
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND created_at >= NOW() - INTERVAL '14 days'
 			  AND created_at < NOW() - INTERVAL '7 days'
 		`, userID).Scan(&prevWeeklyFlashcardCount)
@@ -367,14 +389,13 @@ func (h *DashboardHandler) SetWeeklyGoal(w http.ResponseWriter, r *http.Request)
 func (h *DashboardHandler) Recent(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	ctx := r.Context()
-	const recentLimit = 12
 
 	fetchRecent := h.recentFetcher
 	if fetchRecent == nil {
 		fetchRecent = h.fetchRecentFromDB
 	}
 
-	items, err := fetchRecent(ctx, userID, recentLimit)
+	items, err := fetchRecent(ctx, userID, dashboardRecentLimit)
 	if err != nil {
 		log.Printf("Recent: query failed for user %s: %v", userID, err)
 		writeJSON(w, http.StatusInternalServerError, errorResp("DB_ERROR", "Failed to retrieve recent items", r))
@@ -388,35 +409,7 @@ func (h *DashboardHandler) Streak(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	ctx := r.Context()
 
-	var streak int
-	err := h.pool.QueryRow(ctx, `
-		WITH RECURSIVE activity_days AS (
-			SELECT DISTINCT DATE(created_at) AS d FROM summaries WHERE user_id = $1
-			UNION
-			SELECT DISTINCT DATE(started_at) FROM quiz_attempts WHERE user_id = $1
-			UNION
-			SELECT DISTINCT DATE(last_reviewed_at) FROM flashcard_cards fc
-			JOIN flashcard_decks fd ON fc.deck_id = fd.id
-			WHERE fd.user_id = $1 AND fc.last_reviewed_at IS NOT NULL
-			UNION
-			SELECT DISTINCT DATE(created_at) FROM presentations WHERE user_id = $1 AND status = 'completed'
-		),
-		start_day AS (
-			SELECT CASE
-				WHEN EXISTS (SELECT 1 FROM activity_days WHERE d = CURRENT_DATE) THEN CURRENT_DATE
-				WHEN EXISTS (SELECT 1 FROM activity_days WHERE d = CURRENT_DATE - INTERVAL '1 day') THEN (CURRENT_DATE - INTERVAL '1 day')::date
-				ELSE NULL::date
-			END AS d
-		),
-		streak_days AS (
-			SELECT d FROM start_day WHERE d IS NOT NULL
-			UNION ALL
-			SELECT (sd.d - INTERVAL '1 day')::date
-			FROM streak_days sd
-			JOIN activity_days a ON a.d = (sd.d - INTERVAL '1 day')::date
-		)
-		SELECT COUNT(*) FROM streak_days
-	`, userID).Scan(&streak)
+	streak, err := h.userRepo.GetCurrentStreak(ctx, userID)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load streak", r))
 		return
@@ -476,6 +469,7 @@ func (h *DashboardHandler) Activity(w http.ResponseWriter, r *http.Request) {
 			SELECT EXTRACT(DOW FROM created_at)::int AS dow, COUNT(*)
 			FROM summaries
 			WHERE user_id = $1
+			  AND deleted_at IS NULL
 			  AND created_at >= date_trunc('week', CURRENT_DATE::timestamp)
 			  AND created_at < date_trunc('week', CURRENT_DATE::timestamp) + INTERVAL '7 days'
 			GROUP BY dow
@@ -499,14 +493,230 @@ func (h *DashboardHandler) Activity(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type dashboardWeeklyGoal struct {
+	Type      string `json:"type"`
+	Target    int    `json:"target"`
+	Done      int    `json:"done"`
+	Remaining int    `json:"remaining"`
+}
+
+type dashboardToday struct {
+	Date              string                `json:"date"`
+	Streak            int                   `json:"current_streak"`
+	WeeklyGoal        dashboardWeeklyGoal   `json:"weekly_goal"`
+	DueFlashcards     int                   `json:"due_flashcards"`
+	TodayStudyMinutes float64               `json:"today_study_minutes"`
+	Recent            []dashboardRecentItem `json:"recent"`
+}
+
+// fetchTodayFromDB reads the today payload in one read-only repeatable-read
+// transaction, so every part of it comes from the same snapshot. dayStart is
+// midnight of the user's current day.
+func (h *DashboardHandler) fetchTodayFromDB(ctx context.Context, userID uuid.UUID, dayStart time.Time) (*dashboardToday, error) {
+	tx, err := h.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Due cards are compared by date, so pass the local date itself.
+	localDate := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), 0, 0, 0, 0, time.UTC)
+	brief, err := h.userRepo.GetDailyBriefStatsTx(ctx, tx, userID, localDate)
+	if err != nil {
+		return nil, err
+	}
+
+	today := &dashboardToday{
+		Streak: brief.Streak,
+		WeeklyGoal: dashboardWeeklyGoal{
+			Type:      brief.WeeklyGoalType,
+			Target:    brief.WeeklyGoalTarget,
+			Done:      brief.WeeklyGoalDone,
+			Remaining: brief.GoalRemaining(),
+		},
+		DueFlashcards: brief.CardsDue,
+	}
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(duration_seconds), 0)::float8 / 60.0
+		FROM study_sessions
+		WHERE user_id = $1
+		  AND started_at >= $2
+	`, userID, dayStart).Scan(&today.TodayStudyMinutes); err != nil {
+		return nil, err
+	}
+	if today.Recent, err = queryRecentItems(ctx, tx, userID, dashboardRecentLimit); err != nil {
+		return nil, err
+	}
+
+	return today, tx.Commit(ctx)
+}
+
+// Today returns the home screen's dashboard in one call: the streak, weekly
+// goal progress, due flashcards, today's study minutes and recent items. The
+// optional tz query parameter (an IANA zone, UTC by default) sets where the
+// user's day starts. The individual dashboard endpoints remain for older
+// clients.
+func (h *DashboardHandler) Today(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	loc := time.UTC
+	if tz := strings.TrimSpace(r.URL.Query().Get("tz")); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Unknown timezone", r))
+			return
+		}
+	}
+	now := time.Now().In(loc)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	fetchToday := h.todayFetcher
+	if fetchToday == nil {
+		fetchToday = h.fetchTodayFromDB
+	}
+
+	today, err := fetchToday(r.Context(), userID, dayStart)
+	if err != nil {
+		log.Printf("Today: query failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("DB_ERROR", "Failed to retrieve dashboard", r))
+		return
+	}
+	today.Date = dayStart.Format("2006-01-02")
+	if today.TodayStudyMinutes < 0 {
+		today.TodayStudyMinutes = 0
+	}
+
+	writeJSON(w, http.StatusOK, today)
+}
+
+const (
+	defaultScoreTrendWeeks = 12
+	maxScoreTrendWeeks     = 52
+)
+
+type scoreTrendBucket struct {
+	WeekStart    time.Time `json:"week_start"`
+	AverageScore float64   `json:"average_score"`
+	Attempts     int       `json:"attempts"`
+}
+
+// parseScoreTrendWeeks reads the ?weeks= parameter, defaulting when empty and
+// clamping to maxScoreTrendWeeks.
+func parseScoreTrendWeeks(raw string) (int, error) {
+	if strings.TrimSpace(raw) == "" {
+		return defaultScoreTrendWeeks, nil
+	}
+	weeks, err := strconv.Atoi(raw)
+	if err != nil || weeks < 1 {
+		return 0, errors.New("weeks must be a positive integer")
+	}
+	if weeks > maxScoreTrendWeeks {
+		weeks = maxScoreTrendWeeks
+	}
+	return weeks, nil
+}
+
+// ScoreTrend returns the average completed-quiz score per week for the last
+// N weeks (empty weeks are zero-filled) plus the best and worst score in range.
+func (h *DashboardHandler) ScoreTrend(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	ctx := r.Context()
+
+	weeks, err := parseScoreTrendWeeks(r.URL.Query().Get("weeks"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{"weeks": err.Error()}, r))
+		return
+	}
+
+	rows, err := h.pool.Query(ctx, `
+		WITH week_buckets AS (
+			SELECT generate_series(
+				date_trunc('week', CURRENT_DATE::timestamp) - make_interval(weeks => $2 - 1),
+				date_trunc('week', CURRENT_DATE::timestamp),
+				INTERVAL '1 week'
+			)::date AS week_start
+		),
+		weekly_scores AS (
+			SELECT
+				date_trunc('week', completed_at)::date AS week_start,
+				AVG(score_percent)::float8 AS avg_score,
+				COUNT(*)::int AS attempts
+			FROM quiz_attempts
+			WHERE user_id = $1
+			  AND completed_at IS NOT NULL
+			  AND score_percent IS NOT NULL
+			  AND completed_at >= date_trunc('week', CURRENT_DATE::timestamp) - make_interval(weeks => $2 - 1)
+			GROUP BY 1
+		)
+		SELECT wb.week_start, COALESCE(ws.avg_score, 0), COALESCE(ws.attempts, 0)
+		FROM week_buckets wb
+		LEFT JOIN weekly_scores ws ON ws.week_start = wb.week_start
+		ORDER BY wb.week_start ASC
+	`, userID, weeks)
+	if err != nil {
+		log.Printf("ScoreTrend: query failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load score trend", r))
+		return
+	}
+
+	trend := make([]scoreTrendBucket, 0, weeks)
+	for rows.Next() {
+		var bucket scoreTrendBucket
+		if err := rows.Scan(&bucket.WeekStart, &bucket.AverageScore, &bucket.Attempts); err != nil {
+			rows.Close()
+			writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to parse score trend", r))
+			return
+		}
+		trend = append(trend, bucket)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load score trend", r))
+		return
+	}
+
+	var best, worst *float64
+	err = h.pool.QueryRow(ctx, `
+		SELECT MAX(score_percent)::float8, MIN(score_percent)::float8
+		FROM quiz_attempts
+		WHERE user_id = $1
+		  AND completed_at IS NOT NULL
+		  AND score_percent IS NOT NULL
+		  AND completed_at >= date_trunc('week', CURRENT_DATE::timestamp) - make_interval(weeks => $2 - 1)
+	`, userID, weeks).Scan(&best, &worst)
+	if err != nil {
+		log.Printf("ScoreTrend: best/worst query failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load score trend", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"weeks":       weeks,
+		"trend":       trend,
+		"best_score":  best,
+		"worst_score": worst,
+	})
+}
+
 // Library handler
 
 type LibraryHandler struct {
 	pool *pgxpool.Pool
+	bulk libraryBulkRepository
 }
 
-func NewLibraryHandler(pool *pgxpool.Pool) *LibraryHandler {
-	return &LibraryHandler{pool: pool}
+func NewLibraryHandler(pool *pgxpool.Pool, libraryRepo *repository.LibraryRepo) *LibraryHandler {
+	return &LibraryHandler{pool: pool, bulk: libraryRepo}
+}
+
+type libraryItem struct {
+	ID         uuid.UUID  `json:"id"`
+	Type       string     `json:"type"`
+	Title      string     `json:"title"`
+	Tags       []string   `json:"tags,omitempty"`
+	IsFavorite bool       `json:"is_favorite"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FolderID   *uuid.UUID `json:"folder_id,omitempty"`
 }
 
 func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
@@ -516,20 +726,10 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 	searchQuery := strings.TrimSpace(r.URL.Query().Get("search"))
 	searchLike := "%" + strings.ToLower(searchQuery) + "%"
 
-	type LibraryItem struct {
-		ID         uuid.UUID  `json:"id"`
-		Type       string     `json:"type"`
-		Title      string     `json:"title"`
-		Tags       []string   `json:"tags,omitempty"`
-		IsFavorite bool       `json:"is_favorite"`
-		CreatedAt  time.Time  `json:"created_at"`
-		FolderID   *uuid.UUID `json:"folder_id,omitempty"`
-	}
-
-	var items []LibraryItem
+	var items []libraryItem
 
 	if typeFilter == "" || typeFilter == "summary" {
-		query := "SELECT id, title, tags, is_favorite, created_at, folder_id FROM summaries WHERE user_id = $1 AND is_archived = FALSE"
+		query := "SELECT id, title, tags, is_favorite, created_at, folder_id FROM summaries WHERE user_id = $1 AND is_archived = FALSE AND deleted_at IS NULL"
 		args := []interface{}{userID}
 		if searchQuery != "" {
 			query += " AND LOWER(title) LIKE $2"
@@ -544,7 +744,7 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for rows.Next() {
-			item := LibraryItem{Type: "summary"}
+			item := libraryItem{Type: "summary"}
 			if err := rows.Scan(&item.ID, &item.Title, &item.Tags, &item.IsFavorite, &item.CreatedAt, &item.FolderID); err != nil {
 				rows.Close()
 				log.Printf("LibraryHandler.List: failed to scan summary row for user %s: %v", userID, err)
@@ -563,7 +763,7 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if typeFilter == "" || typeFilter == "quiz" {
-		query := "SELECT id, title, is_favorite, created_at, folder_id FROM quizzes WHERE user_id = $1"
+		query := "SELECT id, title, is_favorite, created_at, folder_id FROM quizzes WHERE user_id = $1 AND is_archived = FALSE AND deleted_at IS NULL"
 		args := []interface{}{userID}
 		if searchQuery != "" {
 			query += " AND LOWER(title) LIKE $2"
@@ -578,7 +778,7 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for rows.Next() {
-			item := LibraryItem{Type: "quiz"}
+			item := libraryItem{Type: "quiz"}
 			if err := rows.Scan(&item.ID, &item.Title, &item.IsFavorite, &item.CreatedAt, &item.FolderID); err != nil {
 				rows.Close()
 				log.Printf("LibraryHandler.List: failed to scan quiz row for user %s: %v", userID, err)
@@ -597,7 +797,7 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if typeFilter == "" || typeFilter == "flashcard" || typeFilter == "flashcards" {
-		query := "SELECT id, title, is_favorite, created_at, folder_id FROM flashcard_decks WHERE user_id = $1"
+		query := "SELECT id, title, is_favorite, created_at, folder_id FROM flashcard_decks WHERE user_id = $1 AND is_archived = FALSE AND deleted_at IS NULL"
 		args := []interface{}{userID}
 		if searchQuery != "" {
 			query += " AND LOWER(title) LIKE $2"
@@ -612,7 +812,7 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for rows.Next() {
-			item := LibraryItem{Type: "flashcard"}
+			item := libraryItem{Type: "flashcard"}
 			if err := rows.Scan(&item.ID, &item.Title, &item.IsFavorite, &item.CreatedAt, &item.FolderID); err != nil {
 				rows.Close()
 				log.Printf("LibraryHandler.List: failed to scan flashcard row for user %s: %v", userID, err)
@@ -646,7 +846,7 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for rows.Next() {
-			item := LibraryItem{Type: "presentation"}
+			item := libraryItem{Type: "presentation"}
 			if err := rows.Scan(&item.ID, &item.Title, &item.IsFavorite, &item.CreatedAt, &item.FolderID); err != nil {
 				rows.Close()
 				log.Printf("LibraryHandler.List: failed to scan presentation row for user %s: %v", userID, err)
@@ -667,12 +867,77 @@ func (h *LibraryHandler) List(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
 }
 
+// Favorites lists the caller's favorited summaries, quizzes and decks in one
+// newest-first page.
+func (h *LibraryHandler) Favorites(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	ctx := r.Context()
+	limit, offset := parseListPage(r)
+
+	const favorites = `
+		SELECT id, 'summary' AS type, title, tags, created_at, folder_id
+		FROM summaries
+		WHERE user_id = $1 AND is_favorite = TRUE AND is_archived = FALSE AND deleted_at IS NULL
+		UNION ALL
+		SELECT id, 'quiz' AS type, title, NULL::text[] AS tags, created_at, folder_id
+		FROM quizzes
+		WHERE user_id = $1 AND is_favorite = TRUE AND is_archived = FALSE AND deleted_at IS NULL
+		UNION ALL
+		SELECT id, 'flashcard' AS type, title, NULL::text[] AS tags, created_at, folder_id
+		FROM flashcard_decks
+		WHERE user_id = $1 AND is_favorite = TRUE AND is_archived = FALSE AND deleted_at IS NULL`
+
+	var total int
+	if err := h.pool.QueryRow(ctx, `SELECT COUNT(*) FROM (`+favorites+`) f`, userID).Scan(&total); err != nil {
+		log.Printf("LibraryHandler.Favorites: failed to count favorites for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("DB_ERROR", "Failed to retrieve favorites", r))
+		return
+	}
+
+	rows, err := h.pool.Query(ctx, favorites+` ORDER BY created_at DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		log.Printf("LibraryHandler.Favorites: failed to query favorites for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("DB_ERROR", "Failed to retrieve favorites", r))
+		return
+	}
+	defer rows.Close()
+
+	items := make([]libraryItem, 0)
+	for rows.Next() {
+		item := libraryItem{IsFavorite: true}
+		if err := rows.Scan(&item.ID, &item.Type, &item.Title, &item.Tags, &item.CreatedAt, &item.FolderID); err != nil {
+			log.Printf("LibraryHandler.Favorites: failed to scan favorite row for user %s: %v", userID, err)
+			writeJSON(w, http.StatusInternalServerError, errorResp("DB_ERROR", "Failed to retrieve favorites", r))
+			return
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("LibraryHandler.Favorites: favorite rows iteration failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("DB_ERROR", "Failed to retrieve favorites", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
 // User & Settings handler
 
 type UserHandler struct {
 	userRepo      userSettingsRepo
 	quotaService  *services.QuotaService
 	encryptionKey string
+	exportJobs    userExportJobLister
+	exportStorage services.Storage
+}
+
+type userExportJobLister interface {
+	ListByReference(ctx context.Context, userID, referenceID uuid.UUID, jobTypes ...string) ([]*models.Job, error)
 }
 
 type userSettingsRepo interface {
@@ -683,12 +948,16 @@ type userSettingsRepo interface {
 	GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error)
 	UpdateSettings(ctx context.Context, settings *models.UserSettings) error
 	SetNotificationSetting(ctx context.Context, userID uuid.UUID, key string, enabled bool) error
+	GetNotificationSchedule(ctx context.Context, userID uuid.UUID) (models.NotificationSchedule, error)
+	SetNotificationSchedule(ctx context.Context, userID uuid.UUID, schedule models.NotificationSchedule) error
+	GetAccountSummary(ctx context.Context, userID uuid.UUID) (*models.AccountSummary, error)
 }
 
 var allowedNotificationKeys = map[string]struct{}{
 	"processing_complete": {},
 	"weekly_digest":       {},
 	"study_reminders":     {},
+	"daily_brief":         {},
 }
 
 func defaultNotificationPreferences() map[string]bool {
@@ -696,6 +965,7 @@ func defaultNotificationPreferences() map[string]bool {
 		"processing_complete": true,
 		"weekly_digest":       false,
 		"study_reminders":     false,
+		"daily_brief":         false,
 	}
 }
 
@@ -725,7 +995,7 @@ func mergeNotificationPreferences(raw json.RawMessage) map[string]bool {
 func defaultSettings(userID uuid.UUID) *models.UserSettings {
 	notificationsJSON, err := json.Marshal(defaultNotificationPreferences())
 	if err != nil {
-		notificationsJSON = []byte(`{"processing_complete":true,"weekly_digest":false,"study_reminders":false}`)
+		notificationsJSON = []byte(`{"processing_complete":true,"weekly_digest":false,"study_reminders":false,"daily_brief":false}`)
 	}
 
 	return &models.UserSettings{
@@ -743,6 +1013,13 @@ func NewUserHandler(userRepo userSettingsRepo, quotaService *services.QuotaServi
 	return &UserHandler{userRepo: userRepo, quotaService: quotaService, encryptionKey: encryptionKey}
 }
 
+// SetDataExports lets DeleteMe remove the user's export archives, which live
+// in storage rather than the database.
+func (h *UserHandler) SetDataExports(jobs userExportJobLister, storage services.Storage) {
+	h.exportJobs = jobs
+	h.exportStorage = storage
+}
+
 func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	user, err := h.userRepo.GetByID(r.Context(), userID)
@@ -763,6 +1040,24 @@ func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// GetAccountSummary returns the counts, study time and storage the settings
+// page shows in one response.
+func (h *UserHandler) GetAccountSummary(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	summary, err := h.userRepo.GetAccountSummary(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "User not found", r))
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load account summary", r))
+		return
+	}
+	summary.AccountAgeDays = int(time.Since(summary.CreatedAt).Hours() / 24)
+
+	writeJSON(w, http.StatusOK, summary)
+}
+
 func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	user, err := h.userRepo.GetByID(r.Context(), userID)
@@ -785,7 +1080,9 @@ func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if update.FullName != "" {
-		user.FullName = strings.TrimSpace(update.FullName)
+		if fullName := services.SanitizeLine(update.FullName); fullName != "" {
+			user.FullName = fullName
+		}
 	}
 	if update.Email != "" {
 		user.Email = strings.ToLower(strings.TrimSpace(update.Email))
@@ -794,8 +1091,8 @@ func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 		user.AvatarURL = update.Avatar
 	}
 	if update.Bio != nil {
-		trimmedBio := strings.TrimSpace(*update.Bio)
-		if len(trimmedBio) > 300 {
+		trimmedBio := services.StripMarkup(*update.Bio)
+		if len([]rune(trimmedBio)) > services.MaxBioLength {
 			writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
 				"bio": "Bio must be 300 characters or fewer",
 			}, r))
@@ -939,6 +1236,11 @@ func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 func (h *UserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
+	if err := h.deleteDataExports(r.Context(), userID); err != nil {
+		log.Printf("UserHandler.DeleteMe: failed to delete data exports for %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to delete account", r))
+		return
+	}
 	if err := h.userRepo.Delete(r.Context(), userID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to delete account", r))
 		return
@@ -964,7 +1266,18 @@ func (h *UserHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
-	var s models.UserSettings
+	// Decode over the stored settings so a partial update leaves the other
+	// preferences untouched.
+	current, err := h.userRepo.GetSettings(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		current = defaultSettings(userID)
+	} else if err != nil {
+		log.Printf("UpdateSettings: DB error for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update settings", r))
+		return
+	}
+	s := *current
+
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&s); err != nil {
@@ -973,6 +1286,11 @@ func (h *UserHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 	}
 	s.UserID = userID
 
+	if fields := validateSettingsCounts(&s); len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
 	if err := h.userRepo.UpdateSettings(r.Context(), &s); err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update settings", r))
 		return
@@ -1021,6 +1339,64 @@ func (h *UserHandler) UpdateNotificationSetting(w http.ResponseWriter, r *http.R
 	})
 }
 
+func (h *UserHandler) GetNotificationSchedule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	schedule, err := h.userRepo.GetNotificationSchedule(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load notification schedule", r))
+		return
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// UpdateNotificationSchedule sets the timezone and quiet hours scheduled
+// emails respect. Quiet hours are given together or not at all.
+func (h *UserHandler) UpdateNotificationSchedule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req models.NotificationSchedule
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	req.Timezone = strings.TrimSpace(req.Timezone)
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Unknown timezone", r))
+		return
+	}
+
+	if (req.QuietHoursStart == nil) != (req.QuietHoursEnd == nil) {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Quiet hours need both a start and an end", r))
+		return
+	}
+	if req.QuietHoursStart != nil {
+		start, end := *req.QuietHoursStart, *req.QuietHoursEnd
+		if start < 0 || start > 23 || end < 0 || end > 23 {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Quiet hours must be between 0 and 23", r))
+			return
+		}
+		if start == end {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Quiet hours must start and end at different hours", r))
+			return
+		}
+	}
+
+	if err := h.userRepo.SetNotificationSchedule(r.Context(), userID, req); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update notification schedule", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, req)
+}
+
 // Job handler
 
 type JobHandler struct {
@@ -1029,6 +1405,7 @@ type JobHandler struct {
 	quizRepo         *repository.QuizRepo
 	flashcardRepo    *repository.FlashcardRepo
 	presentationRepo *repository.PresentationRepo
+	progress         *redis.Client
 }
 
 func NewJobHandler(jobRepo *repository.JobRepo, summaryRepo *repository.SummaryRepo, quizRepo *repository.QuizRepo, flashcardRepo *repository.FlashcardRepo, presentationRepo *repository.PresentationRepo) *JobHandler {
@@ -1068,6 +1445,83 @@ func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, job)
 }
 
+// SetProgressStore gives the handler the Redis client that status updates are
+// kept in, for GetJobProgress.
+func (h *JobHandler) SetProgressStore(redisClient *redis.Client) {
+	h.progress = redisClient
+}
+
+// GetJobProgress is a lightweight progress poll for clients that cannot keep
+// a WebSocket open: the job's status with the step, percentage and remaining
+// time of its last status update.
+func (h *JobHandler) GetJobProgress(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid job ID", r))
+		return
+	}
+
+	job, err := h.jobRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Job not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if job.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	progress, err := services.LoadJobProgress(r.Context(), h.progress, job)
+	if err != nil {
+		log.Printf("GetJobProgress: job %s: %v", id, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load job progress", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, progress)
+}
+
+// listableJobTypes are the job types ListJobs can filter by.
+var listableJobTypes = map[string]struct{}{
+	"content-processing":   {},
+	"summary-generation":   {},
+	"quiz-generation":      {},
+	"flashcard-generation": {},
+	"presentation":         {},
+}
+
+// ListJobs returns the caller's jobs for a summary, quiz, deck, or other
+// reference, newest first, so a client that lost a job ID can reattach to it.
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	referenceID, err := uuid.Parse(query.Get("reference_id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "A valid reference_id is required", r))
+		return
+	}
+
+	var types []string
+	if jobType := query.Get("type"); jobType != "" {
+		if _, ok := listableJobTypes[jobType]; !ok {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid job type", r))
+			return
+		}
+		types = append(types, jobType)
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	jobs, err := h.jobRepo.ListByReference(r.Context(), userID, referenceID, types...)
+	if err != nil {
+		log.Printf("ListJobs: query failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to list jobs", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"jobs": jobs})
+}
+
 func (h *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -1100,15 +1554,15 @@ func (h *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
 	if job.ReferenceID != uuid.Nil {
 		switch job.Type {
 		case "summary-generation":
-			if err := h.summaryRepo.Delete(r.Context(), job.ReferenceID); err != nil {
+			if err := h.summaryRepo.HardDelete(r.Context(), job.ReferenceID); err != nil {
 				log.Printf("CancelJob: failed to delete orphaned summary %s: %v", job.ReferenceID, err)
 			}
 		case "quiz-generation":
-			if err := h.quizRepo.Delete(r.Context(), job.ReferenceID); err != nil {
+			if err := h.quizRepo.HardDelete(r.Context(), job.ReferenceID); err != nil {
 				log.Printf("CancelJob: failed to delete orphaned quiz %s: %v", job.ReferenceID, err)
 			}
 		case "flashcard-generation":
-			if err := h.flashcardRepo.DeleteDeck(r.Context(), job.ReferenceID); err != nil {
+			if err := h.flashcardRepo.HardDeleteDeck(r.Context(), job.ReferenceID); err != nil {
 				log.Printf("CancelJob: failed to delete orphaned flashcard deck %s: %v", job.ReferenceID, err)
 			}
 		case "presentation":
@@ -1120,3 +1574,22 @@ func (h *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// deleteDataExports removes every export archive the user still has in
+// storage. It runs before the account row goes, since the job rows that name
+// the archives are deleted with it.
+func (h *UserHandler) deleteDataExports(ctx context.Context, userID uuid.UUID) error {
+	if h.exportJobs == nil || h.exportStorage == nil {
+		return nil
+	}
+	jobs, err := h.exportJobs.ListByReference(ctx, userID, userID, dataExportJobType)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := h.exportStorage.Delete(ctx, services.DataExportKey(userID, job.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,6 +19,10 @@ func TestDefaultNotificationPreferences(t *testing.T) {
 	if prefs["study_reminders"] != false {
 		t.Fatalf("expected study_reminders default false")
 	}
+
+	if prefs["daily_brief"] != false {
+		t.Fatalf("expected daily_brief default false")
+	}
 }
 
 func TestMergeNotificationPreferences_ValidValues(t *testing.T) {
@@ -38,8 +42,12 @@ func TestMergeNotificationPreferences_ValidValues(t *testing.T) {
 		t.Fatalf("expected study_reminders true after merge")
 	}
 
-	if len(prefs) != 3 {
-		t.Fatalf("expected exactly 3 notification keys, got %d", len(prefs))
+	if prefs["daily_brief"] != false {
+		t.Fatalf("expected daily_brief to keep its default false when missing")
+	}
+
+	if len(prefs) != 4 {
+		t.Fatalf("expected exactly 4 notification keys, got %d", len(prefs))
 	}
 }
 
@@ -60,3 +68,18 @@ func TestMergeNotificationPreferences_InvalidOrMissingValues(t *testing.T) {
 		t.Fatalf("expected study_reminders default false when missing")
 	}
 }
+
+func TestMergeNotificationPreferences_DailyBrief(t *testing.T) {
+	prefs := mergeNotificationPreferences(json.RawMessage(`{"daily_brief":true}`))
+	if prefs["daily_brief"] != true {
+		t.Fatalf("expected daily_brief true after merge")
+	}
+	if prefs["processing_complete"] != true {
+		t.Fatalf("expected other keys to keep their defaults")
+	}
+
+	prefs = mergeNotificationPreferences(json.RawMessage(`{"daily_brief":"yes"}`))
+	if prefs["daily_brief"] != false {
+		t.Fatalf("expected daily_brief to remain default false when value type is invalid")
+	}
+}
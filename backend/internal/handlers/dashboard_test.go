@@ -130,3 +130,112 @@ func TestStats_RaceCondition_DefaultsAppliedAfterWait(t *testing.T) {
 	// This named test exists to lock the regression intent for A-010.
 	t.Skip("race regression is validated with -race execution against Stats path")
 }
+
+func TestParseScoreTrendWeeks(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{raw: "", want: defaultScoreTrendWeeks},
+		{raw: "4", want: 4},
+		{raw: "500", want: maxScoreTrendWeeks},
+		{raw: "0", wantErr: true},
+		{raw: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseScoreTrendWeeks(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("parseScoreTrendWeeks(%q) expected error", tt.raw)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("parseScoreTrendWeeks(%q) = %d, %v; want %d", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestGetToday_ReturnsCompositePayload(t *testing.T) {
+	userID := uuid.New()
+	var gotDayStart time.Time
+
+	h := &DashboardHandler{
+		todayFetcher: func(ctx context.Context, uid uuid.UUID, dayStart time.Time) (*dashboardToday, error) {
+			if uid != userID {
+				t.Fatalf("unexpected user id: %s", uid)
+			}
+			gotDayStart = dayStart
+			return &dashboardToday{
+				Streak:            4,
+				WeeklyGoal:        dashboardWeeklyGoal{Type: "quiz", Target: 5, Done: 2, Remaining: 3},
+				DueFlashcards:     7,
+				TodayStudyMinutes: 25,
+				Recent:            []dashboardRecentItem{{ID: uuid.New(), Type: "summary", Title: "S1"}},
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/today?tz=Asia/Tokyo", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	rr := httptest.NewRecorder()
+
+	h.Today(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if gotDayStart.Location().String() != "Asia/Tokyo" || gotDayStart.Hour() != 0 || gotDayStart.Minute() != 0 {
+		t.Fatalf("expected local midnight in Asia/Tokyo, got %v", gotDayStart)
+	}
+
+	var payload struct {
+		Date              string  `json:"date"`
+		CurrentStreak     int     `json:"current_streak"`
+		DueFlashcards     int     `json:"due_flashcards"`
+		TodayStudyMinutes float64 `json:"today_study_minutes"`
+		WeeklyGoal        struct {
+			Type      string `json:"type"`
+			Remaining int    `json:"remaining"`
+		} `json:"weekly_goal"`
+		Recent []struct {
+			Type string `json:"type"`
+		} `json:"recent"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload.Date != gotDayStart.Format("2006-01-02") {
+		t.Fatalf("expected date %s, got %s", gotDayStart.Format("2006-01-02"), payload.Date)
+	}
+	if payload.CurrentStreak != 4 || payload.DueFlashcards != 7 || payload.TodayStudyMinutes != 25 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if payload.WeeklyGoal.Type != "quiz" || payload.WeeklyGoal.Remaining != 3 {
+		t.Fatalf("unexpected weekly goal: %+v", payload.WeeklyGoal)
+	}
+	if len(payload.Recent) != 1 || payload.Recent[0].Type != "summary" {
+		t.Fatalf("unexpected recent items: %+v", payload.Recent)
+	}
+}
+
+func TestGetToday_UnknownTimezone_Returns400(t *testing.T) {
+	h := &DashboardHandler{
+		todayFetcher: func(ctx context.Context, uid uuid.UUID, dayStart time.Time) (*dashboardToday, error) {
+			t.Fatalf("fetcher should not be called for an unknown timezone")
+			return nil, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/today?tz=Mars/Olympus", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	rr := httptest.NewRecorder()
+
+	h.Today(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+const dataExportJobType = "data-export"
+
+type exportRepository interface {
+	CountRows(ctx context.Context, userID uuid.UUID) (int, error)
+	CollectUserData(ctx context.Context, userID uuid.UUID) (*models.UserDataExport, error)
+}
+
+type exportJobRepository interface {
+	Create(ctx context.Context, j *models.Job) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+}
+
+type ExportHandler struct {
+	exportRepo   exportRepository
+	jobRepo      exportJobRepository
+	redis        queuePusher
+	queueBreaker *QueueBreaker
+	storage      services.Storage
+	syncMaxRows  int
+}
+
+func NewExportHandler(exportRepo exportRepository, jobRepo exportJobRepository, redisClient queuePusher, storage services.Storage, syncMaxRows int) *ExportHandler {
+	return &ExportHandler{
+		exportRepo:  exportRepo,
+		jobRepo:     jobRepo,
+		redis:       redisClient,
+		storage:     storage,
+		syncMaxRows: syncMaxRows,
+	}
+}
+
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *ExportHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
+// Export streams a ZIP of the caller's data. Accounts larger than syncMaxRows
+// (or requests with ?async=true) are exported by the worker instead, and the
+// user is emailed once the archive is ready.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	rowCount, err := h.exportRepo.CountRows(r.Context(), userID)
+	if err != nil {
+		log.Printf("ExportHandler.Export: failed to count rows for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to prepare export", r))
+		return
+	}
+
+	if rowCount > h.syncMaxRows || r.URL.Query().Get("async") == "true" {
+		h.enqueueExport(w, r, userID)
+		return
+	}
+
+	data, err := h.exportRepo.CollectUserData(r.Context(), userID)
+	if err != nil {
+		log.Printf("ExportHandler.Export: failed to collect data for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to prepare export", r))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, dataExportFileName(data.ExportedAt)))
+	w.WriteHeader(http.StatusOK)
+	if err := services.WriteDataExportZip(w, data); err != nil {
+		// Headers are already sent; the client sees a truncated archive.
+		log.Printf("ExportHandler.Export: failed to stream archive for user %s: %v", userID, err)
+	}
+}
+
+func (h *ExportHandler) enqueueExport(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	job := &models.Job{
+		UserID:      userID,
+		Type:        dataExportJobType,
+		ReferenceID: userID,
+		ConfigJSON:  json.RawMessage(`{}`),
+	}
+	if err := h.jobRepo.Create(r.Context(), job); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create job", r))
+		return
+	}
+
+	if h.redis == nil {
+		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:"+dataExportJobType, job); err != nil {
+		log.Printf("failed to enqueue data-export job %s: %v", job.ID, err)
+		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"job_id":  job.ID,
+		"message": "Your export is being prepared. We'll email you when it's ready to download.",
+	})
+}
+
+// Download serves an archive produced by a background export job, for
+// services.DataExportRetention after the job completed.
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid export ID", r))
+		return
+	}
+
+	job, err := h.jobRepo.GetByID(r.Context(), jobID)
+	if err != nil || job.Type != dataExportJobType {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Export not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if job.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if job.Status != "completed" {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Export is not ready yet", r))
+		return
+	}
+
+	if job.CompletedAt == nil || time.Since(*job.CompletedAt) > services.DataExportRetention {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Export file is no longer available", r))
+		return
+	}
+
+	archive, err := h.storage.Get(r.Context(), services.DataExportKey(userID, job.ID))
+	if err != nil {
+		if errors.Is(err, services.ErrStorageObjectNotFound) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Export file is no longer available", r))
+			return
+		}
+		log.Printf("ExportHandler.Download: failed to open export %s: %v", job.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to read export", r))
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, dataExportFileName(*job.CompletedAt)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, archive); err != nil {
+		log.Printf("ExportHandler.Download: failed to stream export %s: %v", job.ID, err)
+	}
+}
+
+func dataExportFileName(t time.Time) string {
+	return "lectura-export-" + t.UTC().Format("20060102") + ".zip"
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type stubExportRepo struct {
+	rows      int
+	collected bool
+	userID    uuid.UUID
+}
+
+func (s *stubExportRepo) CountRows(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.rows, nil
+}
+
+func (s *stubExportRepo) CollectUserData(ctx context.Context, userID uuid.UUID) (*models.UserDataExport, error) {
+	s.collected = true
+	s.userID = userID
+	return &models.UserDataExport{Profile: models.ExportProfile{Email: "user@example.com"}}, nil
+}
+
+type stubExportJobRepo struct {
+	created *models.Job
+}
+
+func (s *stubExportJobRepo) Create(ctx context.Context, j *models.Job) error {
+	j.ID = uuid.New()
+	s.created = j
+	return nil
+}
+
+func (s *stubExportJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	if s.created == nil || s.created.ID != id {
+		return nil, context.Canceled
+	}
+	return s.created, nil
+}
+
+func (s *stubExportJobRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	return nil
+}
+
+func newExportRequest(userID uuid.UUID, target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestExportHandler_SmallAccountStreamsZip(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubExportRepo{rows: 10}
+	h := NewExportHandler(repo, &stubExportJobRepo{}, &quizFakeQueuePusher{}, services.NewLocalStorage(t.TempDir()), 100)
+
+	rr := httptest.NewRecorder()
+	h.Export(rr, newExportRequest(userID, "/api/v1/user/export"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.userID != userID {
+		t.Fatalf("expected export scoped to %s, got %s", userID, repo.userID)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len())); err != nil {
+		t.Fatalf("expected a valid zip archive: %v", err)
+	}
+}
+
+func TestExportHandler_LargeAccountQueuesJob(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubExportRepo{rows: 5000}
+	jobs := &stubExportJobRepo{}
+	queue := &quizFakeQueuePusher{}
+	h := NewExportHandler(repo, jobs, queue, services.NewLocalStorage(t.TempDir()), 100)
+
+	rr := httptest.NewRecorder()
+	h.Export(rr, newExportRequest(userID, "/api/v1/user/export"))
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, rr.Code)
+	}
+	if repo.collected {
+		t.Fatalf("large exports should not be collected inline")
+	}
+	if jobs.created == nil || jobs.created.Type != "data-export" || jobs.created.UserID != userID {
+		t.Fatalf("expected data-export job for user, got %+v", jobs.created)
+	}
+	if queue.key != "queue:data-export" {
+		t.Fatalf("expected job pushed to queue:data-export, got %q", queue.key)
+	}
+}
+
+func newExportDownloadRequest(userID, jobID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", jobID.String())
+	req := newExportRequest(userID, "/api/v1/user/export/"+jobID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestExportHandler_DownloadServesStoredArchive(t *testing.T) {
+	userID := uuid.New()
+	storage := services.NewLocalStorage(t.TempDir())
+	completedAt := time.Now().Add(-time.Hour)
+	job := &models.Job{ID: uuid.New(), UserID: userID, Type: "data-export", Status: "completed", CompletedAt: &completedAt}
+	archive := []byte("zip bytes")
+	if err := storage.Put(context.Background(), services.DataExportKey(userID, job.ID), bytes.NewReader(archive), int64(len(archive))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	h := NewExportHandler(&stubExportRepo{}, &stubExportJobRepo{created: job}, &quizFakeQueuePusher{}, storage, 100)
+
+	rr := httptest.NewRecorder()
+	h.Download(rr, newExportDownloadRequest(userID, job.ID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if !bytes.Equal(rr.Body.Bytes(), archive) {
+		t.Fatalf("expected stored archive, got %q", rr.Body.String())
+	}
+}
+
+func TestExportHandler_DownloadRejectsExpiredArchive(t *testing.T) {
+	userID := uuid.New()
+	storage := services.NewLocalStorage(t.TempDir())
+	completedAt := time.Now().Add(-services.DataExportRetention - time.Hour)
+	job := &models.Job{ID: uuid.New(), UserID: userID, Type: "data-export", Status: "completed", CompletedAt: &completedAt}
+	archive := []byte("zip bytes")
+	if err := storage.Put(context.Background(), services.DataExportKey(userID, job.ID), bytes.NewReader(archive), int64(len(archive))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	h := NewExportHandler(&stubExportRepo{}, &stubExportJobRepo{created: job}, &quizFakeQueuePusher{}, storage, 100)
+
+	rr := httptest.NewRecorder()
+	h.Download(rr, newExportDownloadRequest(userID, job.ID))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
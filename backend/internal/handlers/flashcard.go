@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -22,16 +24,23 @@ import (
 type FlashcardHandler struct {
 	flashRepo    flashcardRepository
 	summaryRepo  flashcardSummaryRepository
+	contentRepo  flashcardContentRepository
 	jobRepo      flashcardJobRepository
 	redis        queuePusher
+	queueBreaker *QueueBreaker
 	quotaService *services.QuotaService
 	userRepo     *repository.UserRepo
+	settingsRepo generationSettingsStore
 }
 
 type flashcardSummaryRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
 }
 
+type flashcardContentRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error)
+}
+
 type flashcardJobRepository interface {
 	Create(ctx context.Context, j *models.Job) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
@@ -39,73 +48,121 @@ type flashcardJobRepository interface {
 
 type flashcardRepository interface {
 	CreateDeck(ctx context.Context, d *models.FlashcardDeck) error
-	ListDecksByUser(ctx context.Context, userID uuid.UUID) ([]*models.FlashcardDeck, error)
+	ListDecksByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.FlashcardDeck, int, error)
 	GetDeckByID(ctx context.Context, id uuid.UUID) (*models.FlashcardDeck, error)
-	GetCardsByDeck(ctx context.Context, deckID uuid.UUID) ([]models.FlashcardCard, error)
+	GetCardsByDeck(ctx context.Context, deckID uuid.UUID, includeSuspended bool) ([]models.FlashcardCard, error)
 	ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	DeleteDeck(ctx context.Context, id uuid.UUID) error
+	RestoreDeck(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error)
+	MergeDecks(ctx context.Context, userID uuid.UUID, req models.MergeDecksRequest) (*models.FlashcardDeck, error)
 	TouchLastAccessed(ctx context.Context, id uuid.UUID) (bool, error)
 	GetCardByID(ctx context.Context, id uuid.UUID) (*models.FlashcardCard, error)
 	RateCard(ctx context.Context, cardID uuid.UUID, rating int) error
+	SetCardSuspended(ctx context.Context, cardID uuid.UUID, suspended bool) error
 	GetDeckStats(ctx context.Context, deckID uuid.UUID) (*models.DeckStats, error)
+	SetSchedulingParams(ctx context.Context, deckID uuid.UUID, params models.SchedulingParams) error
+	RescheduleDeck(ctx context.Context, deckID uuid.UUID, params models.SchedulingParams, dryRun bool) ([]models.ScheduleChange, error)
+	GetDeckReviewHistory(ctx context.Context, deckID uuid.UUID, days int) (*models.DeckReviewHistory, error)
+	UndoLastReview(ctx context.Context, deckID uuid.UUID) (*models.CardReview, error)
+	ListDueCards(ctx context.Context, userID uuid.UUID, limit int) ([]models.DueCard, int, error)
+	GetLeechCards(ctx context.Context, deckID uuid.UUID) ([]models.FlashcardCard, error)
 }
 
-func NewFlashcardHandler(flashRepo *repository.FlashcardRepo, summaryRepo *repository.SummaryRepo, jobRepo *repository.JobRepo, redisClient *redis.Client, quotaService *services.QuotaService, userRepo *repository.UserRepo) *FlashcardHandler {
+// Bounds for per-deck SM-2 parameters.
+const (
+	maxFirstIntervalDays     = 30
+	maxSecondIntervalDays    = 365
+	minIntervalModifier      = 0.5
+	maxIntervalModifier      = 2.5
+	maxScheduleIntervalDays  = 36500
+	maxScheduleChangesListed = 200
+	minLeechThreshold        = 2
+	maxLeechThreshold        = 50
+
+	defaultReviewHistoryDays = 30
+	maxReviewHistoryDays     = 365
+
+	defaultReviewQueueLimit = 50
+	maxReviewQueueLimit     = 200
+)
+
+func NewFlashcardHandler(flashRepo *repository.FlashcardRepo, summaryRepo *repository.SummaryRepo, contentRepo *repository.ContentRepo, jobRepo *repository.JobRepo, redisClient *redis.Client, quotaService *services.QuotaService, userRepo *repository.UserRepo) *FlashcardHandler {
 	return &FlashcardHandler{
 		flashRepo:    flashRepo,
 		summaryRepo:  summaryRepo,
+		contentRepo:  contentRepo,
 		jobRepo:      jobRepo,
 		redis:        redisClient,
 		quotaService: quotaService,
 		userRepo:     userRepo,
+		settingsRepo: userRepo,
+	}
+}
+
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *FlashcardHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
+// normalizeFlashcardStrategy maps the empty strategy and the short aliases the
+// frontend used to send onto the supported strategy names.
+func normalizeFlashcardStrategy(strategy string) string {
+	switch strategy {
+	case "", "definitions":
+		return "term_definition"
+	case "qa":
+		return "question_answer"
 	}
+	return strategy
 }
 
 func (h *FlashcardHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxGenerateRequestBytes)
+
 	var req models.GenerateFlashcardsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
 		return
 	}
 
-	if req.SummaryID == uuid.Nil {
-		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "summary_id is required", r))
-		return
-	}
-
-	if req.NumCards <= 0 {
-		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "num_cards must be greater than 0", r))
-		return
-	}
-
 	req.Title = strings.TrimSpace(req.Title)
-	if req.Title == "" {
-		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "title is required", r))
-		return
-	}
 
-	if req.Strategy == "" {
-		req.Strategy = "term_definition"
-	}
-	if req.Strategy == "definitions" {
-		req.Strategy = "term_definition"
-	}
-	if req.Strategy == "qa" {
-		req.Strategy = "question_answer"
-	}
-	if req.Strategy != "term_definition" && req.Strategy != "question_answer" {
-		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "strategy must be term_definition or question_answer", r))
-		return
-	}
+	req.Strategy = normalizeFlashcardStrategy(req.Strategy)
 
 	userID := middleware.GetUserID(r.Context())
+	applyFlashcardDefaults(&req, loadGenerationSettings(r.Context(), h.settingsRepo, userID))
 
-	summary, err := h.summaryRepo.GetByID(r.Context(), req.SummaryID)
-	if err != nil || summary.UserID != userID {
-		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+	if fields := services.ValidateFlashcardConfig(req); len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
 		return
 	}
 
+	deck := &models.FlashcardDeck{
+		UserID:    userID,
+		Title:     req.Title,
+		CardCount: req.NumCards,
+	}
+	if req.ContentID != nil {
+		// Cards straight from the transcript, no summary needed.
+		content, err := h.contentRepo.GetByID(r.Context(), *req.ContentID)
+		if err != nil || content.UserID != userID {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Content not found", r))
+			return
+		}
+		if content.Status == "failed" {
+			writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Content processing failed, so there is no transcript to make flashcards from", r))
+			return
+		}
+		deck.ContentID = &content.ID
+	} else {
+		summary, err := h.summaryRepo.GetByID(r.Context(), req.SummaryID)
+		if err != nil || summary.UserID != userID {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+			return
+		}
+		deck.SummaryID = &summary.ID
+	}
+
 	// Quota Check
 	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
@@ -129,12 +186,6 @@ func (h *FlashcardHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	deck := &models.FlashcardDeck{
-		UserID:    userID,
-		SummaryID: &req.SummaryID,
-		Title:     req.Title,
-		CardCount: req.NumCards,
-	}
 	configBytes, _ := json.Marshal(req)
 	deck.ConfigJSON = configBytes
 
@@ -157,14 +208,14 @@ func (h *FlashcardHandler) Generate(w http.ResponseWriter, r *http.Request) {
 
 	if h.redis == nil {
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue generation job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
-	jobBytes, _ := json.Marshal(job)
-	if err := h.redis.LPush(r.Context(), "queue:flashcard-generation", string(jobBytes)).Err(); err != nil {
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:flashcard-generation", job); err != nil {
+		log.Printf("failed to enqueue flashcard-generation job %s: %v", job.ID, err)
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue generation job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
@@ -182,14 +233,23 @@ func (h *FlashcardHandler) Generate(w http.ResponseWriter, r *http.Request) {
 
 func (h *FlashcardHandler) ListDecks(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
+	search := r.URL.Query().Get("search")
+	sortBy := r.URL.Query().Get("sort")
+	favoriteOnly := r.URL.Query().Get("favorite") == "true"
+	limit, offset := parseListPage(r)
 
-	decks, err := h.flashRepo.ListDecksByUser(r.Context(), userID)
+	decks, total, err := h.flashRepo.ListDecksByUser(r.Context(), userID, search, sortBy, favoriteOnly, limit, offset)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch decks", r))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{"decks": decks})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"decks":  decks,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
 }
 
 func (h *FlashcardHandler) GetDeck(w http.ResponseWriter, r *http.Request) {
@@ -218,7 +278,10 @@ func (h *FlashcardHandler) GetDeck(w http.ResponseWriter, r *http.Request) {
 		}
 	}(deck.ID)
 
-	cards, err := h.flashRepo.GetCardsByDeck(r.Context(), id)
+	// Suspended cards stay out of study sessions; ?include_suspended=true
+	// lists them too so they can be reviewed and unsuspended.
+	includeSuspended := r.URL.Query().Get("include_suspended") == "true"
+	cards, err := h.flashRepo.GetCardsByDeck(r.Context(), id, includeSuspended)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch cards", r))
 		return
@@ -284,6 +347,92 @@ func (h *FlashcardHandler) DeleteDeck(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Deck deleted"})
 }
 
+// RestoreDeck brings a deck back from the trash while it is still inside the
+// recovery window.
+func (h *FlashcardHandler) RestoreDeck(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid deck ID", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	restored, err := h.flashRepo.RestoreDeck(r.Context(), id, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to restore deck", r))
+		return
+	}
+	if !restored {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Deck not found in trash or recovery window has expired", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Deck restored"})
+}
+
+// MergeDecks consolidates several of the caller's decks into a new one.
+func (h *FlashcardHandler) MergeDecks(w http.ResponseWriter, r *http.Request) {
+	var req models.MergeDecksRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGenerateRequestBytes)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	req.SourceIDs = uniqueUUIDs(req.SourceIDs)
+
+	fields := make(map[string]string)
+	if len(req.SourceIDs) < 2 {
+		fields["source_ids"] = "At least two distinct source decks are required"
+	} else if len(req.SourceIDs) > services.MaxMergeDecks {
+		fields["source_ids"] = fmt.Sprintf("At most %d decks can be merged at once", services.MaxMergeDecks)
+	}
+	if req.Title == "" {
+		fields["title"] = "title is required"
+	} else if len([]rune(req.Title)) > services.MaxTitleLength {
+		fields["title"] = fmt.Sprintf("title must be at most %d characters", services.MaxTitleLength)
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	for _, id := range req.SourceIDs {
+		deck, err := h.flashRepo.GetDeckByID(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Deck not found", r))
+			return
+		}
+		if deck.UserID != userID {
+			writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+			return
+		}
+	}
+
+	deck, err := h.flashRepo.MergeDecks(r.Context(), userID, req)
+	if err != nil {
+		log.Printf("FlashcardHandler.MergeDecks: merge failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to merge decks", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, deck)
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
 func (h *FlashcardHandler) RateCard(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 
@@ -337,6 +486,65 @@ func (h *FlashcardHandler) RateCard(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// SuspendCard takes a card out of reviews until it is unsuspended, typically
+// a leech the user wants to rewrite or drop.
+func (h *FlashcardHandler) SuspendCard(w http.ResponseWriter, r *http.Request) {
+	h.setCardSuspended(w, r, true)
+}
+
+// UnsuspendCard puts a suspended card back into reviews with its lapse count
+// cleared.
+func (h *FlashcardHandler) UnsuspendCard(w http.ResponseWriter, r *http.Request) {
+	h.setCardSuspended(w, r, false)
+}
+
+func (h *FlashcardHandler) setCardSuspended(w http.ResponseWriter, r *http.Request, suspended bool) {
+	userID := middleware.GetUserID(r.Context())
+
+	cardID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid card ID", r))
+		return
+	}
+
+	card, err := h.flashRepo.GetCardByID(r.Context(), cardID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Card not found", r))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch card", r))
+		return
+	}
+
+	deck, err := h.flashRepo.GetDeckByID(r.Context(), card.DeckID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Deck not found", r))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch deck", r))
+		return
+	}
+
+	if deck.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if err := h.flashRepo.SetCardSuspended(r.Context(), cardID, suspended); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update card", r))
+		return
+	}
+
+	updated, err := h.flashRepo.GetCardByID(r.Context(), cardID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch card", r))
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
 func (h *FlashcardHandler) GetDeckStats(w http.ResponseWriter, r *http.Request) {
 	deckID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -368,3 +576,248 @@ func (h *FlashcardHandler) GetDeckStats(w http.ResponseWriter, r *http.Request)
 
 	writeJSON(w, http.StatusOK, stats)
 }
+
+// GetDeckLeeches lists the deck's leeches, cards failed so many times in a
+// row that they are worth rewriting or suspending, most lapses first.
+func (h *FlashcardHandler) GetDeckLeeches(w http.ResponseWriter, r *http.Request) {
+	deckID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid deck ID", r))
+		return
+	}
+
+	if _, ok := h.ownedDeck(w, r, deckID); !ok {
+		return
+	}
+
+	cards, err := h.flashRepo.GetLeechCards(r.Context(), deckID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch leeches", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"deck_id": deckID,
+		"cards":   cards,
+	})
+}
+
+// ownedDeck loads a deck and checks it belongs to the caller, writing the
+// error response when it does not.
+func (h *FlashcardHandler) ownedDeck(w http.ResponseWriter, r *http.Request, deckID uuid.UUID) (*models.FlashcardDeck, bool) {
+	deck, err := h.flashRepo.GetDeckByID(r.Context(), deckID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Deck not found", r))
+			return nil, false
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch deck", r))
+		return nil, false
+	}
+
+	if deck.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return nil, false
+	}
+	return deck, true
+}
+
+// GetDeckHistory returns the deck's reviews per day and accuracy over the
+// last ?days= days (30 by default), with its most recent reviews.
+func (h *FlashcardHandler) GetDeckHistory(w http.ResponseWriter, r *http.Request) {
+	deckID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid deck ID", r))
+		return
+	}
+
+	days := defaultReviewHistoryDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		days, err = strconv.Atoi(raw)
+		if err != nil || days < 1 || days > maxReviewHistoryDays {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", fmt.Sprintf("days must be between 1 and %d", maxReviewHistoryDays), r))
+			return
+		}
+	}
+
+	if _, ok := h.ownedDeck(w, r, deckID); !ok {
+		return
+	}
+
+	history, err := h.flashRepo.GetDeckReviewHistory(r.Context(), deckID, days)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch review history", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// ReviewQueue returns the cards due for review across all of the user's
+// decks, most overdue first, up to ?limit= (50 by default), with the number
+// due in total.
+func (h *FlashcardHandler) ReviewQueue(w http.ResponseWriter, r *http.Request) {
+	limit := defaultReviewQueueLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxReviewQueueLimit {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", fmt.Sprintf("limit must be between 1 and %d", maxReviewQueueLimit), r))
+			return
+		}
+	}
+
+	cards, total, err := h.flashRepo.ListDueCards(r.Context(), middleware.GetUserID(r.Context()), limit)
+	if err != nil {
+		log.Printf("FlashcardHandler.ReviewQueue: failed to list due cards: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch review queue", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"cards":     cards,
+		"total_due": total,
+	})
+}
+
+// UndoLastReview reverts the most recent rating in the deck, restoring the
+// card's previous schedule, and returns the restored card.
+func (h *FlashcardHandler) UndoLastReview(w http.ResponseWriter, r *http.Request) {
+	deckID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid deck ID", r))
+		return
+	}
+
+	if _, ok := h.ownedDeck(w, r, deckID); !ok {
+		return
+	}
+
+	review, err := h.flashRepo.UndoLastReview(r.Context(), deckID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "No review to undo", r))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to undo review", r))
+		return
+	}
+
+	card, err := h.flashRepo.GetCardByID(r.Context(), review.CardID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch card", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"undone_review": review,
+		"card":          card,
+	})
+}
+
+// UpdateSchedule changes a deck's SM-2 parameters. Fields left out keep their
+// current values. With reschedule_existing the due dates of cards already
+// reviewed are recomputed too; dry_run reports those changes without saving.
+func (h *FlashcardHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	deckID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid deck ID", r))
+		return
+	}
+
+	var req models.UpdateDeckScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	deck, err := h.flashRepo.GetDeckByID(r.Context(), deckID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Deck not found", r))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch deck", r))
+		return
+	}
+	if deck.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	params := repository.DefaultSchedulingParams()
+	if deck.SchedulingParams != nil {
+		params = mergeSchedulingParams(params, *deck.SchedulingParams)
+	}
+	params = mergeSchedulingParams(params, req.SchedulingParams)
+	if fields := validateSchedulingParams(params); len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	changes := []models.ScheduleChange{}
+	switch {
+	case req.RescheduleExisting:
+		changes, err = h.flashRepo.RescheduleDeck(r.Context(), deckID, params, req.DryRun)
+	case !req.DryRun:
+		err = h.flashRepo.SetSchedulingParams(r.Context(), deckID, params)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update schedule", r))
+		return
+	}
+
+	affected := len(changes)
+	if len(changes) > maxScheduleChangesListed {
+		changes = changes[:maxScheduleChangesListed]
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"scheduling_params": params,
+		"dry_run":           req.DryRun,
+		"affected_cards":    affected,
+		"changes":           changes,
+	})
+}
+
+// mergeSchedulingParams overrides base with the fields set in override.
+func mergeSchedulingParams(base, override models.SchedulingParams) models.SchedulingParams {
+	if override.FirstIntervalDays != 0 {
+		base.FirstIntervalDays = override.FirstIntervalDays
+	}
+	if override.SecondIntervalDays != 0 {
+		base.SecondIntervalDays = override.SecondIntervalDays
+	}
+	if override.IntervalModifier != 0 {
+		base.IntervalModifier = override.IntervalModifier
+	}
+	if override.MaxIntervalDays != 0 {
+		base.MaxIntervalDays = override.MaxIntervalDays
+	}
+	if override.LeechThreshold != 0 {
+		base.LeechThreshold = override.LeechThreshold
+	}
+	if override.AutoSuspendLeeches != nil {
+		base.AutoSuspendLeeches = override.AutoSuspendLeeches
+	}
+	return base
+}
+
+func validateSchedulingParams(p models.SchedulingParams) map[string]string {
+	fields := make(map[string]string)
+	if p.FirstIntervalDays < 1 || p.FirstIntervalDays > maxFirstIntervalDays {
+		fields["first_interval_days"] = fmt.Sprintf("first_interval_days must be between 1 and %d", maxFirstIntervalDays)
+	}
+	if p.SecondIntervalDays < p.FirstIntervalDays || p.SecondIntervalDays > maxSecondIntervalDays {
+		fields["second_interval_days"] = fmt.Sprintf("second_interval_days must be between first_interval_days and %d", maxSecondIntervalDays)
+	}
+	if p.IntervalModifier < minIntervalModifier || p.IntervalModifier > maxIntervalModifier {
+		fields["interval_modifier"] = fmt.Sprintf("interval_modifier must be between %.1f and %.1f", minIntervalModifier, maxIntervalModifier)
+	}
+	if p.MaxIntervalDays < p.SecondIntervalDays || p.MaxIntervalDays > maxScheduleIntervalDays {
+		fields["max_interval_days"] = fmt.Sprintf("max_interval_days must be between second_interval_days and %d", maxScheduleIntervalDays)
+	}
+	if p.LeechThreshold < minLeechThreshold || p.LeechThreshold > maxLeechThreshold {
+		fields["leech_threshold"] = fmt.Sprintf("leech_threshold must be between %d and %d", minLeechThreshold, maxLeechThreshold)
+	}
+	return fields
+}
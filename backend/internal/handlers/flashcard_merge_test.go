@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+func makeMergeDecksRequest(userID uuid.UUID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/flashcards/decks/merge", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestMergeDecks_Owner_Returns201(t *testing.T) {
+	ownerID := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{
+		deck: &models.FlashcardDeck{ID: uuid.New(), UserID: ownerID},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	a, b := uuid.New(), uuid.New()
+	body := `{"source_ids":["` + a.String() + `","` + b.String() + `","` + a.String() + `"],"title":"  Biology  ","preserve_progress":true}`
+	rr := httptest.NewRecorder()
+	h.MergeDecks(rr, makeMergeDecksRequest(ownerID, body))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	if repo.mergeReq == nil {
+		t.Fatal("expected MergeDecks to be called")
+	}
+	if len(repo.mergeReq.SourceIDs) != 2 {
+		t.Fatalf("expected duplicate source IDs to be dropped, got %v", repo.mergeReq.SourceIDs)
+	}
+	if repo.mergeReq.Title != "Biology" || !repo.mergeReq.PreserveProgress {
+		t.Fatalf("unexpected merge request: %+v", repo.mergeReq)
+	}
+}
+
+func TestMergeDecks_NonOwner_Returns403(t *testing.T) {
+	repo := &stubFlashcardRepoForRateCard{
+		deck: &models.FlashcardDeck{ID: uuid.New(), UserID: uuid.New()},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	body := `{"source_ids":["` + uuid.NewString() + `","` + uuid.NewString() + `"],"title":"Merged"}`
+	rr := httptest.NewRecorder()
+	h.MergeDecks(rr, makeMergeDecksRequest(uuid.New(), body))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if repo.mergeReq != nil {
+		t.Fatal("expected MergeDecks not to be called for a foreign deck")
+	}
+}
+
+func TestMergeDecks_SingleSource_Returns400(t *testing.T) {
+	repo := &stubFlashcardRepoForRateCard{}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	id := uuid.NewString()
+	body := `{"source_ids":["` + id + `","` + id + `"],"title":"Merged"}`
+	rr := httptest.NewRecorder()
+	h.MergeDecks(rr, makeMergeDecksRequest(uuid.New(), body))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	if code := errorCodeFromBody(t, rr); code != "VALIDATION_ERROR" {
+		t.Fatalf("expected VALIDATION_ERROR, got %q", code)
+	}
+}
@@ -32,6 +32,26 @@ type stubFlashcardRepoForRateCard struct {
 	rated       bool
 	ratedCardID uuid.UUID
 	ratedValue  int
+
+	mergeReq *models.MergeDecksRequest
+
+	savedParams *models.SchedulingParams
+	rescheduled bool
+
+	includedSuspended bool
+	suspendedSet      *bool
+
+	historyDays int
+	undoErr     error
+	undone      bool
+
+	dueCards  []models.DueCard
+	dueTotal  int
+	dueUserID uuid.UUID
+	dueLimit  int
+
+	leeches     []models.FlashcardCard
+	leechDeckID uuid.UUID
 }
 
 func (s *stubFlashcardRepoForRateCard) CreateDeck(ctx context.Context, d *models.FlashcardDeck) error {
@@ -45,8 +65,8 @@ func (s *stubFlashcardRepoForRateCard) CreateDeck(ctx context.Context, d *models
 	return nil
 }
 
-func (s *stubFlashcardRepoForRateCard) ListDecksByUser(ctx context.Context, userID uuid.UUID) ([]*models.FlashcardDeck, error) {
-	return nil, nil
+func (s *stubFlashcardRepoForRateCard) ListDecksByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.FlashcardDeck, int, error) {
+	return nil, 0, nil
 }
 
 func (s *stubFlashcardRepoForRateCard) GetDeckByID(ctx context.Context, id uuid.UUID) (*models.FlashcardDeck, error) {
@@ -59,7 +79,8 @@ func (s *stubFlashcardRepoForRateCard) GetDeckByID(ctx context.Context, id uuid.
 	return s.deck, nil
 }
 
-func (s *stubFlashcardRepoForRateCard) GetCardsByDeck(ctx context.Context, deckID uuid.UUID) ([]models.FlashcardCard, error) {
+func (s *stubFlashcardRepoForRateCard) GetCardsByDeck(ctx context.Context, deckID uuid.UUID, includeSuspended bool) ([]models.FlashcardCard, error) {
+	s.includedSuspended = includeSuspended
 	if s.cardsErr != nil {
 		return nil, s.cardsErr
 	}
@@ -74,6 +95,15 @@ func (s *stubFlashcardRepoForRateCard) DeleteDeck(ctx context.Context, id uuid.U
 	return nil
 }
 
+func (s *stubFlashcardRepoForRateCard) RestoreDeck(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *stubFlashcardRepoForRateCard) MergeDecks(ctx context.Context, userID uuid.UUID, req models.MergeDecksRequest) (*models.FlashcardDeck, error) {
+	s.mergeReq = &req
+	return &models.FlashcardDeck{ID: uuid.New(), UserID: userID, Title: req.Title}, nil
+}
+
 func (s *stubFlashcardRepoForRateCard) TouchLastAccessed(ctx context.Context, id uuid.UUID) (bool, error) {
 	return true, nil
 }
@@ -95,10 +125,58 @@ func (s *stubFlashcardRepoForRateCard) RateCard(ctx context.Context, cardID uuid
 	return s.rateErr
 }
 
+func (s *stubFlashcardRepoForRateCard) SetCardSuspended(ctx context.Context, cardID uuid.UUID, suspended bool) error {
+	s.suspendedSet = &suspended
+	if s.card != nil {
+		s.card.Suspended = suspended
+	}
+	return nil
+}
+
 func (s *stubFlashcardRepoForRateCard) GetDeckStats(ctx context.Context, deckID uuid.UUID) (*models.DeckStats, error) {
 	return &models.DeckStats{}, nil
 }
 
+func (s *stubFlashcardRepoForRateCard) SetSchedulingParams(ctx context.Context, deckID uuid.UUID, params models.SchedulingParams) error {
+	s.savedParams = &params
+	return nil
+}
+
+func (s *stubFlashcardRepoForRateCard) RescheduleDeck(ctx context.Context, deckID uuid.UUID, params models.SchedulingParams, dryRun bool) ([]models.ScheduleChange, error) {
+	if !dryRun {
+		s.savedParams = &params
+	}
+	s.rescheduled = true
+	return []models.ScheduleChange{{CardID: uuid.New(), OldIntervalDays: 6, NewIntervalDays: 3}}, nil
+}
+
+func (s *stubFlashcardRepoForRateCard) GetDeckReviewHistory(ctx context.Context, deckID uuid.UUID, days int) (*models.DeckReviewHistory, error) {
+	s.historyDays = days
+	return &models.DeckReviewHistory{DeckID: deckID, Days: days}, nil
+}
+
+func (s *stubFlashcardRepoForRateCard) UndoLastReview(ctx context.Context, deckID uuid.UUID) (*models.CardReview, error) {
+	if s.undoErr != nil {
+		return nil, s.undoErr
+	}
+	if s.card == nil {
+		return nil, pgx.ErrNoRows
+	}
+	s.undone = true
+	return &models.CardReview{ID: uuid.New(), CardID: s.card.ID, Rating: 0}, nil
+}
+
+func (s *stubFlashcardRepoForRateCard) ListDueCards(ctx context.Context, userID uuid.UUID, limit int) ([]models.DueCard, int, error) {
+	s.dueUserID = userID
+	s.dueLimit = limit
+	return s.dueCards, s.dueTotal, nil
+}
+
+func (s *stubFlashcardRepoForRateCard) GetLeechCards(ctx context.Context, deckID uuid.UUID) ([]models.FlashcardCard, error) {
+	s.leechDeckID = deckID
+	return s.leeches, nil
+}
+
 type stubFlashcardSummaryRepo struct {
 	summary *models.Summary
 }
@@ -410,8 +488,8 @@ func TestFlashcardGenerate_QueueFailure_MarksJobFailed(t *testing.T) {
 
 	h.Generate(rr, req)
 
-	if rr.Code != http.StatusInternalServerError {
-		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
 	}
 	if code := errorCodeFromBody(t, rr); code != "QUEUE_ERROR" {
 		t.Fatalf("expected QUEUE_ERROR, got %q", code)
@@ -467,3 +545,470 @@ func TestFlashcardGenerate_QueueSuccess_Returns202(t *testing.T) {
 		t.Fatalf("expected deck_id in response")
 	}
 }
+
+type stubFlashcardContentRepo struct {
+	content *models.Content
+}
+
+func (s *stubFlashcardContentRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error) {
+	if s.content == nil || s.content.ID != id {
+		return nil, pgx.ErrNoRows
+	}
+	return s.content, nil
+}
+
+func flashcardGenerateRequest(body string, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/flashcards/generate", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestFlashcardGenerate_RequiresExactlyOneSource(t *testing.T) {
+	h := &FlashcardHandler{summaryRepo: &stubFlashcardSummaryRepo{}, contentRepo: &stubFlashcardContentRepo{}}
+
+	bodies := map[string]string{
+		"neither": `{"title":"Deck","num_cards":8,"strategy":"term_definition"}`,
+		"both":    `{"summary_id":"` + uuid.NewString() + `","content_id":"` + uuid.NewString() + `","title":"Deck","num_cards":8,"strategy":"term_definition"}`,
+	}
+	for name, body := range bodies {
+		rr := httptest.NewRecorder()
+		h.Generate(rr, flashcardGenerateRequest(body, uuid.New()))
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", name, http.StatusBadRequest, rr.Code)
+		}
+	}
+}
+
+func TestFlashcardGenerate_FromContentEnforcesOwnership(t *testing.T) {
+	content := &models.Content{ID: uuid.New(), UserID: uuid.New(), Status: "completed"}
+	flashRepo := &stubFlashcardRepoForRateCard{}
+	h := &FlashcardHandler{flashRepo: flashRepo, contentRepo: &stubFlashcardContentRepo{content: content}}
+
+	body := `{"content_id":"` + content.ID.String() + `","title":"Deck","num_cards":8,"strategy":"term_definition"}`
+	rr := httptest.NewRecorder()
+	h.Generate(rr, flashcardGenerateRequest(body, uuid.New()))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+	if len(flashRepo.createdDecks) != 0 {
+		t.Fatalf("expected no deck for someone else's content")
+	}
+}
+
+func TestFlashcardGenerate_FromFailedContent_Returns409(t *testing.T) {
+	userID := uuid.New()
+	content := &models.Content{ID: uuid.New(), UserID: userID, Status: "failed"}
+	h := &FlashcardHandler{flashRepo: &stubFlashcardRepoForRateCard{}, contentRepo: &stubFlashcardContentRepo{content: content}}
+
+	body := `{"content_id":"` + content.ID.String() + `","title":"Deck","num_cards":8,"strategy":"term_definition"}`
+	rr := httptest.NewRecorder()
+	h.Generate(rr, flashcardGenerateRequest(body, userID))
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+}
+
+func scheduleRequest(deckID uuid.UUID, body string, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/flashcards/decks/"+deckID.String()+"/schedule", strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", deckID.String())
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	return req.WithContext(context.WithValue(ctx, middleware.UserIDKey, userID))
+}
+
+func TestFlashcardUpdateSchedule_DryRunDoesNotSave(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{deck: &models.FlashcardDeck{ID: uuid.New(), UserID: userID}}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UpdateSchedule(rr, scheduleRequest(repo.deck.ID, `{"second_interval_days":3,"reschedule_existing":true,"dry_run":true}`, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !repo.rescheduled || repo.savedParams != nil {
+		t.Fatalf("expected a dry-run reschedule without saving, got rescheduled=%v saved=%v", repo.rescheduled, repo.savedParams)
+	}
+
+	var payload struct {
+		Params   models.SchedulingParams `json:"scheduling_params"`
+		Affected int                     `json:"affected_cards"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Affected != 1 || payload.Params.SecondIntervalDays != 3 || payload.Params.FirstIntervalDays != 1 {
+		t.Fatalf("unexpected payload: %s", rr.Body.String())
+	}
+}
+
+func TestFlashcardUpdateSchedule_SavesWithoutRescheduling(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{deck: &models.FlashcardDeck{
+		ID: uuid.New(), UserID: userID,
+		SchedulingParams: &models.SchedulingParams{FirstIntervalDays: 2},
+	}}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UpdateSchedule(rr, scheduleRequest(repo.deck.ID, `{"interval_modifier":1.5}`, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if repo.rescheduled || repo.savedParams == nil {
+		t.Fatalf("expected params saved without rescheduling")
+	}
+	if repo.savedParams.FirstIntervalDays != 2 || repo.savedParams.IntervalModifier != 1.5 {
+		t.Fatalf("expected existing params kept and modifier updated, got %+v", repo.savedParams)
+	}
+}
+
+func TestFlashcardUpdateSchedule_RejectsNonOwnerAndBadParams(t *testing.T) {
+	owner := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{deck: &models.FlashcardDeck{ID: uuid.New(), UserID: owner}}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UpdateSchedule(rr, scheduleRequest(repo.deck.ID, `{"interval_modifier":1.2}`, uuid.New()))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d for non-owner, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.UpdateSchedule(rr, scheduleRequest(repo.deck.ID, `{"first_interval_days":10,"second_interval_days":4}`, owner))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for second < first, got %d", http.StatusBadRequest, rr.Code)
+	}
+	if repo.savedParams != nil {
+		t.Fatalf("expected nothing saved on validation failure")
+	}
+}
+
+func TestSuspendCard_Owner_SuspendsCard(t *testing.T) {
+	ownerID := uuid.New()
+	deckID := uuid.New()
+	cardID := uuid.New()
+
+	repo := &stubFlashcardRepoForRateCard{
+		card: &models.FlashcardCard{ID: cardID, DeckID: deckID, Lapses: 9, IsLeech: true},
+		deck: &models.FlashcardDeck{ID: deckID, UserID: ownerID},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.SuspendCard(rr, makeRateCardRequest(t, ownerID, cardID, ""))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if repo.suspendedSet == nil || !*repo.suspendedSet {
+		t.Fatalf("expected card to be suspended")
+	}
+	var card models.FlashcardCard
+	if err := json.NewDecoder(rr.Body).Decode(&card); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !card.Suspended || !card.IsLeech {
+		t.Fatalf("expected suspended leech in response, got %+v", card)
+	}
+}
+
+func TestUnsuspendCard_NonOwner_Returns403(t *testing.T) {
+	deckID := uuid.New()
+	cardID := uuid.New()
+
+	repo := &stubFlashcardRepoForRateCard{
+		card: &models.FlashcardCard{ID: cardID, DeckID: deckID, Suspended: true},
+		deck: &models.FlashcardDeck{ID: deckID, UserID: uuid.New()},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UnsuspendCard(rr, makeRateCardRequest(t, uuid.New(), cardID, ""))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if repo.suspendedSet != nil {
+		t.Fatalf("expected card to stay untouched")
+	}
+}
+
+func TestGetDeck_IncludeSuspended(t *testing.T) {
+	userID := uuid.New()
+	deckID := uuid.New()
+
+	for _, tc := range []struct {
+		query string
+		want  bool
+	}{
+		{query: "", want: false},
+		{query: "?include_suspended=true", want: true},
+	} {
+		repo := &stubFlashcardRepoForRateCard{
+			deck:  &models.FlashcardDeck{ID: deckID, UserID: userID, Title: "Deck"},
+			cards: []models.FlashcardCard{},
+		}
+		h := &FlashcardHandler{flashRepo: repo}
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", deckID.String())
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/flashcards/decks/"+deckID.String()+tc.query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+		rr := httptest.NewRecorder()
+
+		h.GetDeck(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%q: expected status %d, got %d", tc.query, http.StatusOK, rr.Code)
+		}
+		if repo.includedSuspended != tc.want {
+			t.Fatalf("%q: includeSuspended = %v, want %v", tc.query, repo.includedSuspended, tc.want)
+		}
+	}
+}
+
+func makeDeckReq(t *testing.T, method, path string, userID, deckID uuid.UUID) *http.Request {
+	t.Helper()
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", deckID.String())
+	req := httptest.NewRequest(method, path, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestGetDeckHistory(t *testing.T) {
+	userID := uuid.New()
+	deckID := uuid.New()
+	base := "/api/v1/flashcards/decks/" + deckID.String() + "/history"
+
+	tests := []struct {
+		name     string
+		query    string
+		owner    uuid.UUID
+		wantCode int
+		wantDays int
+	}{
+		{"default range", "", userID, http.StatusOK, 30},
+		{"custom range", "?days=7", userID, http.StatusOK, 7},
+		{"range too long", "?days=400", userID, http.StatusBadRequest, 0},
+		{"not a number", "?days=week", userID, http.StatusBadRequest, 0},
+		{"other user's deck", "", uuid.New(), http.StatusForbidden, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubFlashcardRepoForRateCard{deck: &models.FlashcardDeck{ID: deckID, UserID: tt.owner}}
+			h := &FlashcardHandler{flashRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.GetDeckHistory(rr, makeDeckReq(t, http.MethodGet, base+tt.query, userID, deckID))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rr.Code)
+			}
+			if repo.historyDays != tt.wantDays {
+				t.Fatalf("expected history over %d days, got %d", tt.wantDays, repo.historyDays)
+			}
+		})
+	}
+}
+
+func TestUndoLastReview_RestoresCard(t *testing.T) {
+	userID := uuid.New()
+	deckID := uuid.New()
+	cardID := uuid.New()
+
+	repo := &stubFlashcardRepoForRateCard{
+		deck: &models.FlashcardDeck{ID: deckID, UserID: userID},
+		card: &models.FlashcardCard{ID: cardID, DeckID: deckID},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UndoLastReview(rr, makeDeckReq(t, http.MethodPost, "/api/v1/flashcards/decks/"+deckID.String()+"/undo-review", userID, deckID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if !repo.undone {
+		t.Fatalf("expected the last review to be undone")
+	}
+	var payload struct {
+		Card models.FlashcardCard `json:"card"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Card.ID != cardID {
+		t.Fatalf("expected restored card %s, got %s", cardID, payload.Card.ID)
+	}
+}
+
+func TestUndoLastReview_NothingToUndo_Returns404(t *testing.T) {
+	userID := uuid.New()
+	deckID := uuid.New()
+
+	repo := &stubFlashcardRepoForRateCard{deck: &models.FlashcardDeck{ID: deckID, UserID: userID}}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UndoLastReview(rr, makeDeckReq(t, http.MethodPost, "/api/v1/flashcards/decks/"+deckID.String()+"/undo-review", userID, deckID))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestUndoLastReview_NonOwner_Returns403(t *testing.T) {
+	deckID := uuid.New()
+
+	repo := &stubFlashcardRepoForRateCard{
+		deck: &models.FlashcardDeck{ID: deckID, UserID: uuid.New()},
+		card: &models.FlashcardCard{ID: uuid.New(), DeckID: deckID},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.UndoLastReview(rr, makeDeckReq(t, http.MethodPost, "/api/v1/flashcards/decks/"+deckID.String()+"/undo-review", uuid.New(), deckID))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if repo.undone {
+		t.Fatalf("expected no undo for another user's deck")
+	}
+}
+
+func makeReviewQueueRequest(userID uuid.UUID, query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/flashcards/review"+query, nil)
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestReviewQueue_ReturnsDueCardsWithDeckTitles(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{
+		dueCards: []models.DueCard{
+			{FlashcardCard: models.FlashcardCard{ID: uuid.New(), Front: "Q1"}, DeckTitle: "Biology"},
+			{FlashcardCard: models.FlashcardCard{ID: uuid.New(), Front: "Q2"}, DeckTitle: "History"},
+		},
+		dueTotal: 7,
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.ReviewQueue(rr, makeReviewQueueRequest(userID, "?limit=2"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if repo.dueUserID != userID || repo.dueLimit != 2 {
+		t.Fatalf("repo called with user %s limit %d, want %s and 2", repo.dueUserID, repo.dueLimit, userID)
+	}
+
+	var resp struct {
+		Cards []struct {
+			Front     string `json:"front"`
+			DeckTitle string `json:"deck_title"`
+		} `json:"cards"`
+		TotalDue int `json:"total_due"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.TotalDue != 7 || len(resp.Cards) != 2 {
+		t.Fatalf("got %d cards, total_due %d; want 2 and 7", len(resp.Cards), resp.TotalDue)
+	}
+	if resp.Cards[0].Front != "Q1" || resp.Cards[0].DeckTitle != "Biology" {
+		t.Fatalf("first card = %+v, want Q1 from Biology", resp.Cards[0])
+	}
+}
+
+func TestReviewQueue_Limit(t *testing.T) {
+	for _, tc := range []struct {
+		query     string
+		wantCode  int
+		wantLimit int
+	}{
+		{query: "", wantCode: http.StatusOK, wantLimit: defaultReviewQueueLimit},
+		{query: "?limit=200", wantCode: http.StatusOK, wantLimit: 200},
+		{query: "?limit=0", wantCode: http.StatusBadRequest},
+		{query: "?limit=201", wantCode: http.StatusBadRequest},
+		{query: "?limit=abc", wantCode: http.StatusBadRequest},
+	} {
+		repo := &stubFlashcardRepoForRateCard{}
+		h := &FlashcardHandler{flashRepo: repo}
+
+		rr := httptest.NewRecorder()
+		h.ReviewQueue(rr, makeReviewQueueRequest(uuid.New(), tc.query))
+
+		if rr.Code != tc.wantCode {
+			t.Fatalf("%q: expected status %d, got %d", tc.query, tc.wantCode, rr.Code)
+		}
+		if repo.dueLimit != tc.wantLimit {
+			t.Fatalf("%q: repo limit = %d, want %d", tc.query, repo.dueLimit, tc.wantLimit)
+		}
+	}
+}
+
+func makeDeckLeechesRequest(userID, deckID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", deckID.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/flashcards/decks/"+deckID.String()+"/leeches", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestGetDeckLeeches_Owner(t *testing.T) {
+	userID := uuid.New()
+	deckID := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{
+		deck: &models.FlashcardDeck{ID: deckID, UserID: userID},
+		leeches: []models.FlashcardCard{
+			{ID: uuid.New(), DeckID: deckID, Lapses: 9, IsLeech: true},
+			{ID: uuid.New(), DeckID: deckID, Lapses: 8, IsLeech: true, Suspended: true},
+		},
+	}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.GetDeckLeeches(rr, makeDeckLeechesRequest(userID, deckID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if repo.leechDeckID != deckID {
+		t.Fatalf("leeches listed for deck %s, want %s", repo.leechDeckID, deckID)
+	}
+	var resp struct {
+		Cards []models.FlashcardCard `json:"cards"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Cards) != 2 || resp.Cards[0].Lapses != 9 || !resp.Cards[1].Suspended {
+		t.Fatalf("cards = %+v, want both leeches in order", resp.Cards)
+	}
+}
+
+func TestGetDeckLeeches_NonOwner_Returns403(t *testing.T) {
+	deckID := uuid.New()
+	repo := &stubFlashcardRepoForRateCard{deck: &models.FlashcardDeck{ID: deckID, UserID: uuid.New()}}
+	h := &FlashcardHandler{flashRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.GetDeckLeeches(rr, makeDeckLeechesRequest(uuid.New(), deckID))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if repo.leechDeckID != uuid.Nil {
+		t.Fatalf("expected leeches not to be listed")
+	}
+}
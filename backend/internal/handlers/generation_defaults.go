@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// Global fallbacks for counts left out of both the request and the user's
+// settings; they match the defaults the generate forms start with.
+const (
+	defaultQuizQuestions = 10
+	defaultFlashcards    = 20
+)
+
+type generationSettingsStore interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error)
+}
+
+// loadGenerationSettings returns the caller's saved generation preferences,
+// or nil when there are none or they cannot be loaded.
+func loadGenerationSettings(ctx context.Context, store generationSettingsStore, userID uuid.UUID) *models.UserSettings {
+	if store == nil {
+		return nil
+	}
+	settings, err := store.GetSettings(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return settings
+}
+
+// settingOrEmpty returns value when it is one of allowed, so a stale or
+// hand-edited setting never turns a valid request into a rejected one.
+func settingOrEmpty(value string, allowed []string) string {
+	value = strings.TrimSpace(value)
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	return ""
+}
+
+// applySummaryDefaults fills format, length and language the request left
+// empty from the user's settings.
+func applySummaryDefaults(req *models.GenerateSummaryRequest, settings *models.UserSettings) {
+	if settings == nil {
+		return
+	}
+	if req.Format == "" {
+		req.Format = settingOrEmpty(settings.DefaultFormat, services.AllowedSummaryFormats)
+	}
+	if req.Length == "" {
+		req.Length = settingOrEmpty(settings.DefaultSummaryLength, services.AllowedSummaryLengths)
+	}
+	if req.Language == "" {
+		req.Language = strings.TrimSpace(settings.Language)
+	}
+}
+
+// applyQuizDefaults fills difficulty and question count the request left
+// empty from the user's settings, then from the global default. A difficulty
+// mix without a count asks for exactly the questions in the mix.
+func applyQuizDefaults(req *models.GenerateQuizRequest, settings *models.UserSettings) {
+	if req.Difficulty == "" && settings != nil {
+		req.Difficulty = settingOrEmpty(settings.DefaultDifficulty, services.AllowedQuizDifficulties)
+	}
+	if req.NumQuestions != 0 {
+		return
+	}
+	if len(req.DifficultyMix) > 0 {
+		for _, count := range req.DifficultyMix {
+			req.NumQuestions += count
+		}
+		return
+	}
+	req.NumQuestions = defaultQuizQuestions
+	if settings != nil && settings.DefaultNumQuestions != nil {
+		req.NumQuestions = *settings.DefaultNumQuestions
+	}
+}
+
+// applyFlashcardDefaults fills the card count the request left empty from the
+// user's settings, then from the global default.
+func applyFlashcardDefaults(req *models.GenerateFlashcardsRequest, settings *models.UserSettings) {
+	if req.NumCards != 0 {
+		return
+	}
+	req.NumCards = defaultFlashcards
+	if settings != nil && settings.DefaultNumCards != nil {
+		req.NumCards = *settings.DefaultNumCards
+	}
+}
+
+// prepareAutoGenerate fills the follow-up job configs of a summary request
+// from the user's settings and returns their field errors, keyed under
+// auto_generate. The summary id, and the deck title when left empty, are
+// filled in by the worker once the summary exists.
+func prepareAutoGenerate(opts *models.AutoGenerateOptions, settings *models.UserSettings) map[string]string {
+	fields := map[string]string{}
+	if opts == nil {
+		return fields
+	}
+
+	if opts.Quiz {
+		if opts.QuizConfig == nil {
+			opts.QuizConfig = &models.GenerateQuizRequest{}
+		}
+		applyQuizDefaults(opts.QuizConfig, settings)
+		quizFields := services.ValidateQuizConfig(*opts.QuizConfig)
+		delete(quizFields, "summary_id")
+		for field, msg := range quizFields {
+			fields["auto_generate.quiz_config."+field] = msg
+		}
+	} else {
+		opts.QuizConfig = nil
+	}
+
+	if opts.Flashcards {
+		if opts.FlashcardConfig == nil {
+			opts.FlashcardConfig = &models.GenerateFlashcardsRequest{}
+		}
+		config := opts.FlashcardConfig
+		config.ContentID = nil
+		config.Title = strings.TrimSpace(config.Title)
+		config.Strategy = normalizeFlashcardStrategy(config.Strategy)
+		applyFlashcardDefaults(config, settings)
+		cardFields := services.ValidateFlashcardConfig(*config)
+		delete(cardFields, "summary_id")
+		if config.Title == "" {
+			delete(cardFields, "title")
+		}
+		for field, msg := range cardFields {
+			fields["auto_generate.flashcard_config."+field] = msg
+		}
+	} else {
+		opts.FlashcardConfig = nil
+	}
+
+	return fields
+}
+
+// validateSettingsCounts checks the optional default counts against the same
+// limits generation requests are held to.
+func validateSettingsCounts(settings *models.UserSettings) map[string]string {
+	fields := map[string]string{}
+	if n := settings.DefaultNumQuestions; n != nil && (*n < services.MinQuizQuestions || *n > services.MaxQuizQuestions) {
+		fields["default_num_questions"] = fmt.Sprintf("default_num_questions must be between %d and %d", services.MinQuizQuestions, services.MaxQuizQuestions)
+	}
+	if n := settings.DefaultNumCards; n != nil && (*n < services.MinFlashcards || *n > services.MaxFlashcards) {
+		fields["default_num_cards"] = fmt.Sprintf("default_num_cards must be between %d and %d", services.MinFlashcards, services.MaxFlashcards)
+	}
+	return fields
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+type stubGenerationSettings struct {
+	settings *models.UserSettings
+	err      error
+}
+
+func (s stubGenerationSettings) GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error) {
+	return s.settings, s.err
+}
+
+func TestLoadGenerationSettings_IgnoresMissingStore(t *testing.T) {
+	if got := loadGenerationSettings(context.Background(), nil, uuid.New()); got != nil {
+		t.Fatalf("expected nil settings without a store, got %#v", got)
+	}
+	store := stubGenerationSettings{err: errors.New("db down")}
+	if got := loadGenerationSettings(context.Background(), store, uuid.New()); got != nil {
+		t.Fatalf("expected nil settings on error, got %#v", got)
+	}
+}
+
+func TestApplySummaryDefaults(t *testing.T) {
+	settings := &models.UserSettings{DefaultFormat: "bullets", DefaultSummaryLength: "epic", Language: "fr"}
+
+	req := models.GenerateSummaryRequest{Length: "concise"}
+	applySummaryDefaults(&req, settings)
+	if req.Format != "bullets" || req.Length != "concise" || req.Language != "fr" {
+		t.Fatalf("unexpected request after defaults: %#v", req)
+	}
+
+	req = models.GenerateSummaryRequest{}
+	applySummaryDefaults(&req, settings)
+	if req.Length != "" {
+		t.Fatalf("expected unknown setting to be ignored, got %q", req.Length)
+	}
+}
+
+func TestApplyQuizDefaults(t *testing.T) {
+	n := 25
+	settings := &models.UserSettings{DefaultDifficulty: "hard", DefaultNumQuestions: &n}
+
+	req := models.GenerateQuizRequest{}
+	applyQuizDefaults(&req, settings)
+	if req.Difficulty != "hard" || req.NumQuestions != 25 {
+		t.Fatalf("expected settings to fill the request, got %#v", req)
+	}
+
+	req = models.GenerateQuizRequest{Difficulty: "easy", NumQuestions: 5}
+	applyQuizDefaults(&req, settings)
+	if req.Difficulty != "easy" || req.NumQuestions != 5 {
+		t.Fatalf("expected explicit values to win, got %#v", req)
+	}
+
+	req = models.GenerateQuizRequest{DifficultyMix: map[string]int{"easy": 2, "hard": 4}}
+	applyQuizDefaults(&req, settings)
+	if req.NumQuestions != 6 {
+		t.Fatalf("expected count from difficulty mix, got %d", req.NumQuestions)
+	}
+
+	req = models.GenerateQuizRequest{}
+	applyQuizDefaults(&req, nil)
+	if req.NumQuestions != defaultQuizQuestions || req.Difficulty != "" {
+		t.Fatalf("expected global defaults, got %#v", req)
+	}
+}
+
+func TestApplyFlashcardDefaults(t *testing.T) {
+	n := 40
+	req := models.GenerateFlashcardsRequest{}
+	applyFlashcardDefaults(&req, &models.UserSettings{DefaultNumCards: &n})
+	if req.NumCards != 40 {
+		t.Fatalf("expected settings count, got %d", req.NumCards)
+	}
+
+	req = models.GenerateFlashcardsRequest{}
+	applyFlashcardDefaults(&req, &models.UserSettings{})
+	if req.NumCards != defaultFlashcards {
+		t.Fatalf("expected global default, got %d", req.NumCards)
+	}
+}
+
+func TestPrepareAutoGenerate(t *testing.T) {
+	n := 12
+	settings := &models.UserSettings{DefaultDifficulty: "easy", DefaultNumQuestions: &n}
+	opts := &models.AutoGenerateOptions{
+		Quiz:            true,
+		Flashcards:      true,
+		FlashcardConfig: &models.GenerateFlashcardsRequest{Strategy: "qa"},
+	}
+
+	if fields := prepareAutoGenerate(opts, settings); len(fields) > 0 {
+		t.Fatalf("unexpected field errors: %v", fields)
+	}
+	if opts.QuizConfig == nil || opts.QuizConfig.NumQuestions != 12 || opts.QuizConfig.Difficulty != "easy" {
+		t.Fatalf("expected quiz config from settings, got %#v", opts.QuizConfig)
+	}
+	if opts.FlashcardConfig.Strategy != "question_answer" || opts.FlashcardConfig.NumCards != defaultFlashcards {
+		t.Fatalf("expected normalized flashcard config, got %#v", opts.FlashcardConfig)
+	}
+}
+
+func TestPrepareAutoGenerate_InvalidConfig(t *testing.T) {
+	opts := &models.AutoGenerateOptions{
+		Quiz:       true,
+		QuizConfig: &models.GenerateQuizRequest{NumQuestions: 500},
+		// Not requested, so its config is dropped rather than validated.
+		FlashcardConfig: &models.GenerateFlashcardsRequest{NumCards: -1},
+	}
+
+	fields := prepareAutoGenerate(opts, nil)
+	if _, ok := fields["auto_generate.quiz_config.num_questions"]; !ok || len(fields) != 1 {
+		t.Fatalf("expected only the quiz count error, got %v", fields)
+	}
+	if opts.FlashcardConfig != nil {
+		t.Fatalf("expected unrequested flashcard config to be dropped")
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultHealthCheckTimeout bounds each dependency check when none is
+// configured, so a hung dependency fails the probe instead of hanging it.
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheck is one dependency the readiness probe verifies.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// DependencyStatus is the readiness result for one dependency.
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthHandler serves liveness and readiness probes.
+type HealthHandler struct {
+	checks  []HealthCheck
+	timeout time.Duration
+}
+
+func NewHealthHandler(timeout time.Duration, checks ...HealthCheck) *HealthHandler {
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	return &HealthHandler{checks: checks, timeout: timeout}
+}
+
+// Live reports that the process is up and serving. It never touches
+// dependencies, so an outage elsewhere does not get the instance restarted.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Ready checks every dependency in parallel and answers 503 with the
+// per-dependency results if any of them is unhealthy, so load balancers stop
+// routing to the instance until it recovers.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	results := make(map[string]DependencyStatus, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func(c HealthCheck) {
+			defer wg.Done()
+			status := h.run(r.Context(), c)
+			mu.Lock()
+			results[c.Name] = status
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	code, overall := http.StatusOK, "ok"
+	for _, s := range results {
+		if s.Status != "ok" {
+			code, overall = http.StatusServiceUnavailable, "unavailable"
+			break
+		}
+	}
+
+	writeJSON(w, code, map[string]interface{}{
+		"status": overall,
+		"checks": results,
+	})
+}
+
+func (h *HealthHandler) run(parent context.Context, c HealthCheck) DependencyStatus {
+	ctx, cancel := context.WithTimeout(parent, h.timeout)
+	defer cancel()
+
+	// Run the check in its own goroutine so one that ignores its context
+	// still cannot hold the probe past the timeout.
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- c.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	status := DependencyStatus{Status: "ok", LatencyMS: time.Since(start).Milliseconds()}
+	if err == nil {
+		return status
+	}
+
+	// Probes are public, so the response only says what kind of failure it
+	// was; the details go to the log.
+	log.Printf("health: %s check failed: %v", c.Name, err)
+	status.Status = "error"
+	status.Error = "unavailable"
+	if errors.Is(err, context.DeadlineExceeded) {
+		status.Error = "timed out"
+	}
+	return status
+}
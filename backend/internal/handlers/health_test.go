@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func readinessBody(t *testing.T, rr *httptest.ResponseRecorder) (string, map[string]DependencyStatus) {
+	t.Helper()
+	var body struct {
+		Status string                      `json:"status"`
+		Checks map[string]DependencyStatus `json:"checks"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return body.Status, body.Checks
+}
+
+func TestHealthReady_AllHealthy(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	h := NewHealthHandler(time.Second, HealthCheck{Name: "postgres", Check: ok}, HealthCheck{Name: "redis", Check: ok})
+
+	rr := httptest.NewRecorder()
+	h.Ready(rr, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	status, checks := readinessBody(t, rr)
+	if status != "ok" || checks["postgres"].Status != "ok" || checks["redis"].Status != "ok" {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestHealthReady_ReportsFailingAndHungDependencies(t *testing.T) {
+	h := NewHealthHandler(50*time.Millisecond,
+		HealthCheck{Name: "postgres", Check: func(context.Context) error { return nil }},
+		HealthCheck{Name: "redis", Check: func(context.Context) error { return errors.New("dial tcp 10.0.0.5:6379: connection refused") }},
+		HealthCheck{Name: "gemini", Check: func(context.Context) error {
+			time.Sleep(time.Second) // ignores its context
+			return nil
+		}},
+	)
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	h.Ready(rr, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("probe took %s; a hung dependency should not hang it", elapsed)
+	}
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rr.Code)
+	}
+	status, checks := readinessBody(t, rr)
+	if status != "unavailable" {
+		t.Fatalf("status = %q, want unavailable", status)
+	}
+	if checks["postgres"].Status != "ok" {
+		t.Fatalf("postgres = %+v, want ok", checks["postgres"])
+	}
+	if checks["redis"].Status != "error" || checks["redis"].Error != "unavailable" {
+		t.Fatalf("redis = %+v, want a generic error without connection details", checks["redis"])
+	}
+	if checks["gemini"].Error != "timed out" {
+		t.Fatalf("gemini = %+v, want timed out", checks["gemini"])
+	}
+}
+
+func TestHealthLive_IgnoresDependencies(t *testing.T) {
+	h := NewHealthHandler(time.Second, HealthCheck{Name: "postgres", Check: func(context.Context) error { return errors.New("down") }})
+
+	rr := httptest.NewRecorder()
+	h.Live(rr, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+)
+
+func TestJobHandler_ListJobs_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing reference", query: ""},
+		{name: "malformed reference", query: "?reference_id=not-a-uuid"},
+		{name: "unknown type", query: "?reference_id=" + uuid.NewString() + "&type=bogus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &JobHandler{}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+
+			rr := httptest.NewRecorder()
+			h.ListJobs(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+		})
+	}
+}
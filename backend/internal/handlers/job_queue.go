@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/models"
+)
+
+const (
+	// queueFailureThreshold consecutive push failures open the breaker.
+	queueFailureThreshold = 3
+	// queueCooldown is how long an open breaker fails pushes fast before it
+	// lets one through to probe Redis again.
+	queueCooldown = 15 * time.Second
+	// queuePushTimeout bounds a single push so an unreachable Redis does not
+	// hold the request for the client's full dial and write timeouts.
+	queuePushTimeout = 2 * time.Second
+)
+
+type queuePusher interface {
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+}
+
+// errQueueOpen is returned by pushJob while the queue's breaker is open.
+var errQueueOpen = errors.New("job queue unavailable: too many recent failures")
+
+// QueueBreaker is a consecutive-failure circuit breaker for the job queue.
+// Once open it fails pushes fast for queueCooldown, then admits a single
+// probe: its success closes the breaker and its failure reopens it. Handlers
+// that push to the same Redis client should share one breaker.
+type QueueBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func NewQueueBreaker() *QueueBreaker {
+	return &QueueBreaker{}
+}
+
+func (b *QueueBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.Before(b.openUntil) {
+		return false
+	}
+	if b.failures < queueFailureThreshold {
+		return true
+	}
+	// Half-open: one push probes Redis while the rest keep failing fast.
+	if b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *QueueBreaker) record(err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if b.failures >= queueFailureThreshold {
+		b.openUntil = now.Add(queueCooldown)
+	}
+}
+
+// release ends a push whose outcome says nothing about Redis, so a probe
+// slot it held goes to the next push.
+func (b *QueueBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// pushJob enqueues job on the named queue. With a breaker, after repeated
+// failures it fails fast with errQueueOpen until the cooldown passes, instead
+// of waiting on Redis for every request. A nil breaker pushes unguarded.
+func pushJob(ctx context.Context, b *QueueBreaker, q queuePusher, queue string, job *models.Job) error {
+	if b != nil && !b.allow(time.Now()) {
+		return errQueueOpen
+	}
+
+	jobBytes, err := json.Marshal(job)
+	if err != nil {
+		if b != nil {
+			b.release()
+		}
+		return err
+	}
+
+	pushCtx, cancel := context.WithTimeout(ctx, queuePushTimeout)
+	defer cancel()
+	err = q.LPush(pushCtx, queue, string(jobBytes)).Err()
+	if b != nil {
+		if ctx.Err() == nil {
+			b.record(err, time.Now())
+		} else {
+			// A request the client abandoned says nothing about Redis.
+			b.release()
+		}
+	}
+	return err
+}
+
+// writeQueueUnavailable is the response for a job that could not be queued.
+// Callers mark the job failed first.
+func writeQueueUnavailable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(queueCooldown/time.Second)))
+	writeJSON(w, http.StatusServiceUnavailable, errorResp("QUEUE_ERROR", "Job queue temporarily unavailable, please try again shortly", r))
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestPushJob_BreakerFailsFastAfterRepeatedFailures(t *testing.T) {
+	queue := &quizFakeQueuePusher{err: errors.New("redis down")}
+	breaker := NewQueueBreaker()
+	job := &models.Job{ID: uuid.New(), Type: "quiz-generation"}
+
+	for i := 0; i < queueFailureThreshold; i++ {
+		if err := pushJob(context.Background(), breaker, queue, "queue:quiz-generation", job); err == nil || errors.Is(err, errQueueOpen) {
+			t.Fatalf("push %d: expected the Redis error, got %v", i, err)
+		}
+	}
+
+	if err := pushJob(context.Background(), breaker, queue, "queue:quiz-generation", job); !errors.Is(err, errQueueOpen) {
+		t.Fatalf("expected the breaker to be open, got %v", err)
+	}
+	if len(queue.values) != queueFailureThreshold {
+		t.Fatalf("expected no push while open, got %d pushes", len(queue.values))
+	}
+
+	// Once the cooldown passes, a successful probe closes the breaker.
+	breaker.openUntil = time.Now().Add(-time.Second)
+	queue.err = nil
+	if err := pushJob(context.Background(), breaker, queue, "queue:quiz-generation", job); err != nil {
+		t.Fatalf("expected the probe to succeed, got %v", err)
+	}
+	if breaker.failures != 0 || !breaker.openUntil.IsZero() || breaker.probing {
+		t.Fatalf("expected the breaker to reset, got %+v", breaker)
+	}
+}
+
+func TestPushJob_IgnoresCanceledRequests(t *testing.T) {
+	queue := &quizFakeQueuePusher{err: context.Canceled}
+	breaker := NewQueueBreaker()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < queueFailureThreshold+1; i++ {
+		_ = pushJob(ctx, breaker, queue, "queue:quiz-generation", &models.Job{ID: uuid.New()})
+	}
+	if breaker.failures != 0 {
+		t.Fatalf("expected abandoned requests not to count, got %d failures", breaker.failures)
+	}
+}
+
+func TestQueueBreaker_AdmitsOneProbeAfterCooldown(t *testing.T) {
+	b := NewQueueBreaker()
+	now := time.Now()
+	for i := 0; i < queueFailureThreshold; i++ {
+		b.record(errors.New("redis down"), now)
+	}
+	if b.allow(now) {
+		t.Fatalf("expected the breaker to be open")
+	}
+
+	later := now.Add(queueCooldown)
+	if !b.allow(later) {
+		t.Fatalf("expected a probe once the cooldown passed")
+	}
+	if b.allow(later) {
+		t.Fatalf("expected a second push to fail fast while the probe is in flight")
+	}
+
+	// A failed probe reopens the breaker for another cooldown.
+	b.record(errors.New("redis down"), later)
+	if b.allow(later.Add(time.Second)) {
+		t.Fatalf("expected the breaker to reopen after a failed probe")
+	}
+
+	// An abandoned probe frees the slot without closing the breaker.
+	again := later.Add(queueCooldown)
+	if !b.allow(again) {
+		t.Fatalf("expected a probe after the second cooldown")
+	}
+	b.release()
+	if !b.allow(again) {
+		t.Fatalf("expected the next push to probe after an abandoned one")
+	}
+	b.record(nil, again)
+	if !b.allow(again) || !b.allow(again) {
+		t.Fatalf("expected a successful probe to close the breaker")
+	}
+}
+
+func TestPushJob_NilBreakerPushesUnguarded(t *testing.T) {
+	queue := &quizFakeQueuePusher{err: errors.New("redis down")}
+	for i := 0; i < queueFailureThreshold+1; i++ {
+		if err := pushJob(context.Background(), nil, queue, "queue:quiz-generation", &models.Job{ID: uuid.New()}); errors.Is(err, errQueueOpen) {
+			t.Fatalf("push %d: expected no breaker without one configured", i)
+		}
+	}
+}
+
+func TestWriteQueueUnavailable(t *testing.T) {
+	rr := httptest.NewRecorder()
+	writeQueueUnavailable(rr, httptest.NewRequest(http.MethodPost, "/api/v1/quizzes/generate", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "15" {
+		t.Fatalf("expected Retry-After 15, got %q", rr.Header().Get("Retry-After"))
+	}
+	if code := errorCodeFromBody(t, rr); code != "QUEUE_ERROR" {
+		t.Fatalf("expected QUEUE_ERROR, got %q", code)
+	}
+}
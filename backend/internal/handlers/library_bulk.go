@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+)
+
+// maxLibraryBulkItems caps the items one bulk library request can change.
+const maxLibraryBulkItems = 200
+
+type libraryBulkRepository interface {
+	BulkUpdate(ctx context.Context, userID uuid.UUID, itemType, action string, ids []uuid.UUID) ([]uuid.UUID, error)
+}
+
+// Bulk deletes, archives or unarchives many library items of one type at
+// once. Items the caller does not own, or that are already in the requested
+// state, are reported as skipped.
+func (h *LibraryHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req struct {
+		IDs    []uuid.UUID `json:"ids"`
+		Type   string      `json:"type"`
+		Action string      `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	ids := uniqueUUIDs(req.IDs)
+	fields := map[string]string{}
+	if req.Type != "summary" && req.Type != "quiz" && req.Type != "flashcard" {
+		fields["type"] = "Must be summary, quiz, or flashcard"
+	}
+	if req.Action != "delete" && req.Action != "archive" && req.Action != "unarchive" {
+		fields["action"] = "Must be delete, archive, or unarchive"
+	}
+	if len(ids) == 0 {
+		fields["ids"] = "At least one item is required"
+	} else if len(ids) > maxLibraryBulkItems {
+		fields["ids"] = fmt.Sprintf("At most %d items can be changed at once", maxLibraryBulkItems)
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	affected, err := h.bulk.BulkUpdate(r.Context(), userID, req.Type, req.Action, ids)
+	if err != nil {
+		log.Printf("LibraryHandler.Bulk: %s of %d %s items failed for user %s: %v", req.Action, len(ids), req.Type, userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update library items", r))
+		return
+	}
+
+	changed := make(map[uuid.UUID]bool, len(affected))
+	for _, id := range affected {
+		changed[id] = true
+	}
+	skipped := make([]uuid.UUID, 0, len(ids)-len(affected))
+	for _, id := range ids {
+		if !changed[id] {
+			skipped = append(skipped, id)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"affected":    len(affected),
+		"skipped":     len(skipped),
+		"skipped_ids": skipped,
+	})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+)
+
+type stubLibraryBulkRepo struct {
+	owned  map[uuid.UUID]bool
+	err    error
+	calls  int
+	userID uuid.UUID
+	typ    string
+	action string
+	ids    []uuid.UUID
+}
+
+func (s *stubLibraryBulkRepo) BulkUpdate(_ context.Context, userID uuid.UUID, itemType, action string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	s.calls++
+	s.userID, s.typ, s.action, s.ids = userID, itemType, action, ids
+	if s.err != nil {
+		return nil, s.err
+	}
+	var affected []uuid.UUID
+	for _, id := range ids {
+		if s.owned[id] {
+			affected = append(affected, id)
+		}
+	}
+	return affected, nil
+}
+
+func libraryBulkRequest(userID uuid.UUID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/library/bulk", strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestLibraryBulk_ReportsAffectedAndSkipped(t *testing.T) {
+	mine, theirs := uuid.New(), uuid.New()
+	repo := &stubLibraryBulkRepo{owned: map[uuid.UUID]bool{mine: true}}
+	h := &LibraryHandler{bulk: repo}
+	userID := uuid.New()
+
+	body := fmt.Sprintf(`{"ids":[%q,%q,%q],"type":"summary","action":"delete"}`, mine, theirs, mine)
+	rr := httptest.NewRecorder()
+	h.Bulk(rr, libraryBulkRequest(userID, body))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if repo.calls != 1 || repo.userID != userID || repo.typ != "summary" || repo.action != "delete" || len(repo.ids) != 2 {
+		t.Fatalf("unexpected repo call: %+v", repo)
+	}
+
+	var resp struct {
+		Affected   int         `json:"affected"`
+		Skipped    int         `json:"skipped"`
+		SkippedIDs []uuid.UUID `json:"skipped_ids"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Affected != 1 || resp.Skipped != 1 || len(resp.SkippedIDs) != 1 || resp.SkippedIDs[0] != theirs {
+		t.Fatalf("response = %+v", resp)
+	}
+}
+
+func TestLibraryBulk_ValidatesRequest(t *testing.T) {
+	id := uuid.New()
+	tooMany := make([]string, maxLibraryBulkItems+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", uuid.New())
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"unknown type", fmt.Sprintf(`{"ids":[%q],"type":"presentation","action":"delete"}`, id), "type"},
+		{"unknown action", fmt.Sprintf(`{"ids":[%q],"type":"quiz","action":"purge"}`, id), "action"},
+		{"no ids", `{"ids":[],"type":"quiz","action":"archive"}`, "ids"},
+		{"too many ids", `{"ids":[` + strings.Join(tooMany, ",") + `],"type":"flashcard","action":"archive"}`, "ids"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubLibraryBulkRepo{}
+			h := &LibraryHandler{bulk: repo}
+
+			rr := httptest.NewRecorder()
+			h.Bulk(rr, libraryBulkRequest(uuid.New(), tt.body))
+
+			if rr.Code != http.StatusBadRequest || repo.calls != 0 {
+				t.Fatalf("status = %d with %d repo calls, want 400 and none", rr.Code, repo.calls)
+			}
+			if !strings.Contains(rr.Body.String(), `"`+tt.field+`"`) {
+				t.Fatalf("expected a %s field error, got %s", tt.field, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestLibraryBulk_InvalidIDIs400(t *testing.T) {
+	h := &LibraryHandler{bulk: &stubLibraryBulkRepo{}}
+
+	rr := httptest.NewRecorder()
+	h.Bulk(rr, libraryBulkRequest(uuid.New(), `{"ids":["nope"],"type":"quiz","action":"delete"}`))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rr.Code)
+	}
+}
+
+func TestLibraryBulk_RepoErrorIs500(t *testing.T) {
+	h := &LibraryHandler{bulk: &stubLibraryBulkRepo{err: errors.New("db down")}}
+
+	rr := httptest.NewRecorder()
+	h.Bulk(rr, libraryBulkRequest(uuid.New(), fmt.Sprintf(`{"ids":[%q],"type":"quiz","action":"archive"}`, uuid.New())))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rr.Code)
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type outlineRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
+	GetOutline(ctx context.Context, id uuid.UUID) (json.RawMessage, error)
+	SaveOutline(ctx context.Context, id uuid.UUID, outline json.RawMessage) error
+}
+
+type outlineGenerator interface {
+	GenerateOutline(ctx context.Context, content, title string) (*models.OutlineNode, error)
+}
+
+type OutlineHandler struct {
+	summaryRepo outlineRepository
+	generator   outlineGenerator
+	inflight    singleflight.Group
+}
+
+func NewOutlineHandler(summaryRepo outlineRepository, geminiService *services.GeminiService) *OutlineHandler {
+	return &OutlineHandler{
+		summaryRepo: summaryRepo,
+		generator:   geminiService,
+	}
+}
+
+// Get returns the summary's mind-map outline, generating and caching it on
+// first access so normal summary generation never pays for it.
+func (h *OutlineHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	cached, err := h.summaryRepo.GetOutline(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load outline", r))
+		return
+	}
+	if len(cached) > 0 {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"summary_id": id,
+			"outline":    cached,
+		})
+		return
+	}
+
+	content := summaryPlainText(summary)
+	if content == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Summary has no content to outline", r))
+		return
+	}
+
+	// Concurrent first requests share one generation; it runs detached from any
+	// single request so one client disconnecting doesn't fail the others.
+	genCtx := context.WithoutCancel(r.Context())
+	result, err, _ := h.inflight.Do(id.String(), func() (interface{}, error) {
+		outline, err := h.generator.GenerateOutline(genCtx, content, summary.Title)
+		if err != nil {
+			return nil, err
+		}
+		outlineBytes, err := json.Marshal(outline)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.summaryRepo.SaveOutline(genCtx, id, outlineBytes); err != nil {
+			log.Printf("OutlineHandler.Get: failed to cache outline for summary %s: %v", id, err)
+		}
+		return json.RawMessage(outlineBytes), nil
+	})
+	if err != nil {
+		log.Printf("OutlineHandler.Get: failed to generate outline for summary %s: %v", id, err)
+		writeJSON(w, http.StatusBadGateway, errorResp("UPSTREAM_ERROR", "Failed to generate outline", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summary_id": id,
+		"outline":    result,
+	})
+}
+
+// summaryPlainText is the text of a summary used as model context: the raw
+// content, or the Cornell sections for Cornell summaries.
+func summaryPlainText(summary *models.Summary) string {
+	if summary.ContentRaw != nil && *summary.ContentRaw != "" {
+		return *summary.ContentRaw
+	}
+
+	var parts []string
+	if summary.CornellCues != nil {
+		parts = append(parts, "CUES:\n"+*summary.CornellCues)
+	}
+	if summary.CornellNotes != nil {
+		parts = append(parts, "NOTES:\n"+*summary.CornellNotes)
+	}
+	if summary.CornellSummary != nil {
+		parts = append(parts, "SUMMARY:\n"+*summary.CornellSummary)
+	}
+	return strings.Join(parts, "\n\n")
+}
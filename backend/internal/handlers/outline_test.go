@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubOutlineRepo struct {
+	summary *models.Summary
+	outline json.RawMessage
+	saved   json.RawMessage
+}
+
+func (s *stubOutlineRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error) {
+	if s.summary == nil {
+		return nil, context.Canceled
+	}
+	return s.summary, nil
+}
+
+func (s *stubOutlineRepo) GetOutline(ctx context.Context, id uuid.UUID) (json.RawMessage, error) {
+	return s.outline, nil
+}
+
+func (s *stubOutlineRepo) SaveOutline(ctx context.Context, id uuid.UUID, outline json.RawMessage) error {
+	s.saved = outline
+	return nil
+}
+
+type stubOutlineGenerator struct {
+	calls int
+}
+
+func (s *stubOutlineGenerator) GenerateOutline(ctx context.Context, content, title string) (*models.OutlineNode, error) {
+	s.calls++
+	return &models.OutlineNode{Title: title, Children: []models.OutlineNode{{Title: "Mitosis"}}}, nil
+}
+
+func makeOutlineRequest(summaryID, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/summaries/"+summaryID.String()+"/outline", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestOutlineGet_GeneratesAndCachesOnFirstAccess(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	content := "Cells divide by mitosis."
+	repo := &stubOutlineRepo{summary: &models.Summary{ID: summaryID, UserID: userID, Title: "Cells", ContentRaw: &content}}
+	gen := &stubOutlineGenerator{}
+	h := &OutlineHandler{summaryRepo: repo, generator: gen}
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeOutlineRequest(summaryID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if gen.calls != 1 {
+		t.Fatalf("expected one generation call, got %d", gen.calls)
+	}
+	if len(repo.saved) == 0 {
+		t.Fatal("expected generated outline to be cached")
+	}
+}
+
+func TestOutlineGet_UsesCachedOutline(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	repo := &stubOutlineRepo{
+		summary: &models.Summary{ID: summaryID, UserID: userID},
+		outline: json.RawMessage(`{"title":"Cells","children":[{"title":"Mitosis"}]}`),
+	}
+	gen := &stubOutlineGenerator{}
+	h := &OutlineHandler{summaryRepo: repo, generator: gen}
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeOutlineRequest(summaryID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if gen.calls != 0 {
+		t.Fatalf("expected no generation for a cached outline, got %d calls", gen.calls)
+	}
+}
+
+func TestOutlineGet_NonOwner_Returns403(t *testing.T) {
+	summaryID := uuid.New()
+	repo := &stubOutlineRepo{summary: &models.Summary{ID: summaryID, UserID: uuid.New()}}
+	h := &OutlineHandler{summaryRepo: repo, generator: &stubOutlineGenerator{}}
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeOutlineRequest(summaryID, uuid.New()))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -25,6 +26,7 @@ type PresentationHandler struct {
 	contentRepo      *repository.ContentRepo
 	jobRepo          presentationJobRepository
 	redis            *redis.Client
+	queueBreaker     *QueueBreaker
 	quotaService     *services.QuotaService
 	userRepo         *repository.UserRepo
 }
@@ -56,6 +58,11 @@ func NewPresentationHandler(presentationRepo *repository.PresentationRepo, conte
 	}
 }
 
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *PresentationHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
 func (h *PresentationHandler) CreatePresentation(w http.ResponseWriter, r *http.Request) {
 	var req models.GeneratePresentationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -166,14 +173,14 @@ func (h *PresentationHandler) CreatePresentation(w http.ResponseWriter, r *http.
 
 	if h.redis == nil {
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue generation job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
-	jobBytes, _ := json.Marshal(job)
-	if err := h.redis.LPush(r.Context(), "queue:presentation", string(jobBytes)).Err(); err != nil {
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:presentation", job); err != nil {
+		log.Printf("failed to enqueue presentation job %s: %v", job.ID, err)
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue generation job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
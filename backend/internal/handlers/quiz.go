@@ -1,15 +1,22 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 
 	"lectura-backend/internal/middleware"
@@ -23,14 +30,33 @@ type QuizHandler struct {
 	summaryRepo  quizSummaryRepository
 	jobRepo      quizJobRepository
 	redis        queuePusher
+	queueBreaker *QueueBreaker
 	quotaService *services.QuotaService
 	userRepo     *repository.UserRepo
+	settingsRepo generationSettingsStore
+	reports      quizReportLister
+	essayGrader  essayGrader
 }
 
-type queuePusher interface {
-	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+type quizReportLister interface {
+	ListReportedQuestions(ctx context.Context, quizID uuid.UUID) ([]int, error)
 }
 
+type essayGrader interface {
+	GradeEssay(ctx context.Context, question, rubric, answer string) (int, string, error)
+}
+
+const (
+	// essayPassScore is the grade at which an essay counts as correct.
+	essayPassScore = 60
+	// essayGradingConcurrency bounds the grading calls one submission makes
+	// at once.
+	essayGradingConcurrency = 4
+	// essayGradingBudget bounds how long a submission waits on AI grading;
+	// essays not graded by then are left ungraded.
+	essayGradingBudget = 90 * time.Second
+)
+
 type quizSummaryRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
 }
@@ -42,15 +68,21 @@ type quizJobRepository interface {
 
 type quizRepository interface {
 	Create(ctx context.Context, q *models.Quiz) error
-	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Quiz, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Quiz, int, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error)
 	ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	TouchLastAccessed(ctx context.Context, id uuid.UUID) (bool, error)
 	CreateAttempt(ctx context.Context, a *models.QuizAttempt) error
 	GetAttemptByID(ctx context.Context, id uuid.UUID) (*models.QuizAttempt, error)
+	GetActiveAttempt(ctx context.Context, quizID, userID uuid.UUID, since time.Time) (*models.QuizAttempt, error)
 	SaveProgress(ctx context.Context, attemptID uuid.UUID, answers json.RawMessage) error
-	SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage) error
+	SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage, essayGrades map[int]models.EssayGrade) error
+	SaveEssayAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, answer string) error
+	SaveMatchingAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, matches []int) error
+	RecordHintUsage(ctx context.Context, attemptID uuid.UUID, questionIndex int) error
+	ListAttemptsByUser(ctx context.Context, quizID, userID uuid.UUID) ([]*models.QuizAttempt, error)
 }
 
 func NewQuizHandler(quizRepo *repository.QuizRepo, summaryRepo *repository.SummaryRepo, jobRepo *repository.JobRepo, redisClient *redis.Client, quotaService *services.QuotaService, userRepo *repository.UserRepo) *QuizHandler {
@@ -61,11 +93,27 @@ func NewQuizHandler(quizRepo *repository.QuizRepo, summaryRepo *repository.Summa
 		redis:        redisClient,
 		quotaService: quotaService,
 		userRepo:     userRepo,
+		settingsRepo: userRepo,
 	}
 }
 
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *QuizHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
+// SetEssayGrader enables AI grading of essay answers for quizzes that opt in.
+func (h *QuizHandler) SetEssayGrader(grader essayGrader) {
+	h.essayGrader = grader
+}
+
+// SetQuestionReports lets attempt reviews flag questions with open reports.
+func (h *QuizHandler) SetQuestionReports(reports quizReportLister) {
+	h.reports = reports
+}
+
 func (h *QuizHandler) Generate(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxGenerateRequestBytes))
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
 		return
@@ -86,9 +134,18 @@ func (h *QuizHandler) Generate(w http.ResponseWriter, r *http.Request) {
 	if rawConfig.QuestionTypes != nil {
 		config.QuestionTypes = append([]string(nil), rawConfig.QuestionTypes...)
 	}
+	if fresh, err := strconv.ParseBool(r.URL.Query().Get("fresh")); err == nil && fresh {
+		config.Fresh = true
+	}
 	log.Printf("Saving quiz config question_types: %v", config.QuestionTypes)
 
 	userID := middleware.GetUserID(r.Context())
+	applyQuizDefaults(&config, loadGenerationSettings(r.Context(), h.settingsRepo, userID))
+
+	if fields := services.ValidateQuizConfig(config); len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
 
 	// Verify summary belongs to user
 	summary, err := h.summaryRepo.GetByID(r.Context(), req.SummaryID)
@@ -124,7 +181,7 @@ func (h *QuizHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		UserID:        userID,
 		SummaryID:     &req.SummaryID,
 		Title:         req.Title,
-		QuestionCount: req.NumQuestions,
+		QuestionCount: config.NumQuestions,
 	}
 	configBytes, _ := json.Marshal(config)
 	quiz.ConfigJSON = configBytes
@@ -149,14 +206,14 @@ func (h *QuizHandler) Generate(w http.ResponseWriter, r *http.Request) {
 
 	if h.redis == nil {
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue generation job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
-	jobBytes, _ := json.Marshal(job)
-	if err := h.redis.LPush(r.Context(), "queue:quiz-generation", string(jobBytes)).Err(); err != nil {
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:quiz-generation", job); err != nil {
+		log.Printf("failed to enqueue quiz-generation job %s: %v", job.ID, err)
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("QUEUE_ERROR", "Failed to queue generation job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
@@ -166,16 +223,50 @@ func (h *QuizHandler) Generate(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+const (
+	defaultListPageSize = 20
+	maxListPageSize     = 50
+)
+
+// activeAttemptWindow is how long an unfinished attempt stays resumable.
+const activeAttemptWindow = 24 * time.Hour
+
+// parseListPage reads limit/offset query params for the quiz and deck lists,
+// defaulting to defaultListPageSize and capping at maxListPageSize.
+func parseListPage(r *http.Request) (limit, offset int) {
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+	if limit <= 0 {
+		limit = defaultListPageSize
+	}
+	if limit > maxListPageSize {
+		limit = maxListPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
 func (h *QuizHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
+	search := r.URL.Query().Get("search")
+	sortBy := r.URL.Query().Get("sort")
+	favoriteOnly := r.URL.Query().Get("favorite") == "true"
+	limit, offset := parseListPage(r)
 
-	quizzes, err := h.quizRepo.ListByUser(r.Context(), userID)
+	quizzes, total, err := h.quizRepo.ListByUser(r.Context(), userID, search, sortBy, favoriteOnly, limit, offset)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch quizzes", r))
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{"quizzes": quizzes})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"quizzes": quizzes,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
 }
 
 func (h *QuizHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -207,6 +298,74 @@ func (h *QuizHandler) Get(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, quiz)
 }
 
+// Export renders the quiz as a printable PDF. ?key=true appends the answer key
+// for instructors; the default student copy leaves it out.
+func (h *QuizHandler) Export(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid quiz ID", r))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "pdf" && format != "csv" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Unsupported export format", r))
+		return
+	}
+	includeKey := r.URL.Query().Get("key") == "true"
+
+	quiz, err := h.quizRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if quiz.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	questions, err := services.ParseQuizQuestions(quiz)
+	if err != nil {
+		log.Printf("QuizHandler.Export: quiz %s has unreadable questions: %v", quiz.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to export quiz", r))
+		return
+	}
+	if len(questions) == 0 {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Quiz is still being generated", r))
+		return
+	}
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := services.WriteQuizCSV(&buf, questions); err != nil {
+			log.Printf("QuizHandler.Export: failed to write CSV for quiz %s: %v", quiz.ID, err)
+			writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to export quiz", r))
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.QuizCSVFileName(quiz.Title)))
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.Bytes())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := services.WriteQuizPDF(&buf, quiz, questions, includeKey); err != nil {
+		log.Printf("QuizHandler.Export: failed to render quiz %s: %v", quiz.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to export quiz", r))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.QuizExportFileName(quiz.Title, includeKey)))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
 func (h *QuizHandler) ToggleFavorite(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -261,6 +420,29 @@ func (h *QuizHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Quiz deleted"})
 }
 
+// Restore brings a quiz back from the trash while it is still inside the
+// recovery window.
+func (h *QuizHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid quiz ID", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	restored, err := h.quizRepo.Restore(r.Context(), id, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to restore quiz", r))
+		return
+	}
+	if !restored {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found in trash or recovery window has expired", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Quiz restored"})
+}
+
 func (h *QuizHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
 	quizID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -281,9 +463,32 @@ func (h *QuizHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// With ?resume=true, hand back a recent unfinished attempt instead of
+	// starting a duplicate.
+	if resume, _ := strconv.ParseBool(r.URL.Query().Get("resume")); resume {
+		active, err := h.quizRepo.GetActiveAttempt(r.Context(), quizID, userID, time.Now().Add(-activeAttemptWindow))
+		if err == nil {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"attempt_id":     active.ID,
+				"started_at":     active.StartedAt,
+				"resumed":        true,
+				"question_order": active.QuestionOrder,
+			})
+			return
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("QuizHandler.StartAttempt: failed to look up active attempt for quiz %s: %v", quizID, err)
+			writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to start quiz", r))
+			return
+		}
+	}
+
+	// The shuffle is fixed when the attempt starts so the review can show
+	// the questions in the order they were answered.
 	attempt := &models.QuizAttempt{
-		QuizID: quizID,
-		UserID: userID,
+		QuizID:        quizID,
+		UserID:        userID,
+		QuestionOrder: attemptQuestionOrder(quiz),
 	}
 
 	if err := h.quizRepo.CreateAttempt(r.Context(), attempt); err != nil {
@@ -292,8 +497,49 @@ func (h *QuizHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeJSON(w, http.StatusCreated, map[string]interface{}{
-		"attempt_id": attempt.ID,
-		"started_at": attempt.StartedAt,
+		"attempt_id":     attempt.ID,
+		"started_at":     attempt.StartedAt,
+		"resumed":        false,
+		"question_order": attempt.QuestionOrder,
+	})
+}
+
+// GetActiveAttempt returns the caller's most recent unfinished attempt on a
+// quiz, with its saved answers, so the client can resume after a refresh.
+// Responds 204 when there is nothing recent to resume.
+func (h *QuizHandler) GetActiveAttempt(w http.ResponseWriter, r *http.Request) {
+	quizID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid quiz ID", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+
+	quiz, err := h.quizRepo.GetByID(r.Context(), quizID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return
+	}
+
+	if quiz.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	attempt, err := h.quizRepo.GetActiveAttempt(r.Context(), quizID, userID, time.Now().Add(-activeAttemptWindow))
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		log.Printf("QuizHandler.GetActiveAttempt: failed to load active attempt for quiz %s: %v", quizID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load attempt", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"attempt": attempt,
 	})
 }
 
@@ -323,6 +569,15 @@ func (h *QuizHandler) SaveProgress(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if progress.AnswerText != nil {
+		h.saveTextAnswer(w, r, attempt, progress)
+		return
+	}
+	if progress.Matches != nil {
+		h.saveMatchingAnswer(w, r, attempt, progress)
+		return
+	}
+
 	// Merge with existing answers
 	var answers []map[string]int
 	if attempt.AnswersJSON != nil {
@@ -354,6 +609,89 @@ func (h *QuizHandler) SaveProgress(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// saveTextAnswer stores the text answer to an essay or fill_blank question of
+// the attempt.
+func (h *QuizHandler) saveTextAnswer(w http.ResponseWriter, r *http.Request, attempt *models.QuizAttempt, progress models.SaveProgressRequest) {
+	if len([]rune(*progress.AnswerText)) > services.MaxEssayAnswerLength {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"answer_text": fmt.Sprintf("answer_text must be at most %d characters", services.MaxEssayAnswerLength),
+		}, r))
+		return
+	}
+
+	q, ok := h.attemptQuestion(w, r, attempt, progress.QuestionIndex)
+	if !ok {
+		return
+	}
+	if q.Type != "essay" && q.Type != "fill_blank" {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"question_index": "answer_text is only accepted for essay and fill_blank questions",
+		}, r))
+		return
+	}
+	if q.Type == "fill_blank" && len([]rune(*progress.AnswerText)) > services.MaxFillBlankAnswerLength {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"answer_text": fmt.Sprintf("answer_text must be at most %d characters", services.MaxFillBlankAnswerLength),
+		}, r))
+		return
+	}
+
+	if err := h.quizRepo.SaveEssayAnswer(r.Context(), attempt.ID, progress.QuestionIndex, *progress.AnswerText); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to save progress", r))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// saveMatchingAnswer stores the matches given to a matching question of the
+// attempt.
+func (h *QuizHandler) saveMatchingAnswer(w http.ResponseWriter, r *http.Request, attempt *models.QuizAttempt, progress models.SaveProgressRequest) {
+	q, ok := h.attemptQuestion(w, r, attempt, progress.QuestionIndex)
+	if !ok {
+		return
+	}
+	if q.Type != "matching" {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"question_index": "matches are only accepted for matching questions",
+		}, r))
+		return
+	}
+	if !services.IsMatchingAnswer(q, progress.Matches) {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"matches": fmt.Sprintf("matches must give each of the %d prompts a different answer index, or -1", len(q.Pairs)),
+		}, r))
+		return
+	}
+
+	if err := h.quizRepo.SaveMatchingAnswer(r.Context(), attempt.ID, progress.QuestionIndex, progress.Matches); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to save progress", r))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// attemptQuestion loads the question an answer to the attempt is for. It
+// writes the error response and returns false when it cannot.
+func (h *QuizHandler) attemptQuestion(w http.ResponseWriter, r *http.Request, attempt *models.QuizAttempt, qi int) (models.QuizQuestion, bool) {
+	quiz, err := h.quizRepo.GetByID(r.Context(), attempt.QuizID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return models.QuizQuestion{}, false
+	}
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to parse quiz questions", r))
+		return models.QuizQuestion{}, false
+	}
+	if qi < 0 || qi >= len(questions) {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"question_index": fmt.Sprintf("question_index must be between 0 and %d", len(questions)-1),
+		}, r))
+		return models.QuizQuestion{}, false
+	}
+	return questions[qi], true
+}
+
 func (h *QuizHandler) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
 	attemptID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -403,33 +741,237 @@ func (h *QuizHandler) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Grade
+	var config models.GenerateQuizRequest
+	_ = json.Unmarshal(quiz.ConfigJSON, &config)
+	essayGrades := h.gradeEssays(r.Context(), userID, questions, attempt, config)
+	correct, score := gradeAnswers(questions, answers, attempt, config.HintPenaltyPercent, essayGrades)
+	total := len(questions)
+
+	answersJSON, _ := json.Marshal(answers)
+	if err := h.quizRepo.SubmitAttempt(r.Context(), attemptID, score, correct, answersJSON, essayGrades); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to submit attempt", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"score_percent": score,
+		"correct_count": correct,
+		"total":         total,
+		"hints_used":    attempt.HintsUsed,
+		"attempt_id":    attemptID,
+		"essay_grades":  essayGrades,
+	})
+}
+
+// gradeEssays grades the attempt's essay answers when the quiz opted into AI
+// grading. A blank answer scores 0 without a model call, and an essay the
+// model fails to grade is left ungraded rather than failing the submission.
+// It returns nil for quizzes without essays.
+func (h *QuizHandler) gradeEssays(ctx context.Context, userID uuid.UUID, questions []models.QuizQuestion, attempt *models.QuizAttempt, config models.GenerateQuizRequest) map[int]models.EssayGrade {
+	var grades map[int]models.EssayGrade
+	var pending []int
+	for qi, q := range questions {
+		if q.Type != "essay" {
+			continue
+		}
+		if grades == nil {
+			grades = make(map[int]models.EssayGrade)
+		}
+		switch {
+		case !config.AIGrading || h.essayGrader == nil:
+			grades[qi] = models.EssayGrade{}
+		case strings.TrimSpace(attempt.EssayAnswers[qi]) == "":
+			grades[qi] = models.EssayGrade{Feedback: "No answer was given.", Graded: true}
+		default:
+			pending = append(pending, qi)
+		}
+	}
+	if len(pending) == 0 {
+		return grades
+	}
+
+	ctx, cancel := context.WithTimeout(services.WithUsageUser(ctx, userID), essayGradingBudget)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, essayGradingConcurrency)
+	for _, qi := range pending {
+		wg.Add(1)
+		go func(qi int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			q := questions[qi]
+			grade := models.EssayGrade{}
+			score, feedback, err := h.essayGrader.GradeEssay(ctx, q.Question, q.Rubric, strings.TrimSpace(attempt.EssayAnswers[qi]))
+			if err != nil {
+				log.Printf("QuizHandler.SubmitAttempt: grading essay %d of attempt %s failed: %v", qi, attempt.ID, err)
+			} else {
+				grade = models.EssayGrade{Score: score, Feedback: feedback, Graded: true}
+			}
+			mu.Lock()
+			grades[qi] = grade
+			mu.Unlock()
+		}(qi)
+	}
+	wg.Wait()
+	return grades
+}
+
+// gradeAnswers counts the correct answers and scores them as a percentage of
+// the graded questions. A correct answer on a hinted question earns reduced
+// credit when the quiz was generated with a hint penalty. A graded essay
+// earns its score as partial credit, so it weighs the same as one objective
+// question, and counts as correct at essayPassScore; ungraded essays are left
+// out of the score. Fill-in-the-blank and matching answers are read from the
+// attempt's text and matching answers.
+func gradeAnswers(questions []models.QuizQuestion, answers []map[string]int, attempt *models.QuizAttempt, hintPenaltyPercent int, essayGrades map[int]models.EssayGrade) (int, float64) {
+	hinted := make(map[int]bool, len(attempt.HintsUsed))
+	for _, qi := range attempt.HintsUsed {
+		hinted[qi] = true
+	}
+	hintFactor := func(qi int) float64 {
+		if hinted[qi] {
+			return 1 - float64(hintPenaltyPercent)/100
+		}
+		return 1
+	}
+
 	correct := 0
+	credit := 0.0
+	graded := 0
+	for qi, q := range questions {
+		switch q.Type {
+		case "essay":
+			if grade, ok := essayGrades[qi]; ok && grade.Graded {
+				graded++
+				credit += float64(grade.Score) / 100 * hintFactor(qi)
+				if grade.Score >= essayPassScore {
+					correct++
+				}
+			}
+			continue
+		case "fill_blank":
+			if services.FillBlankCorrect(q, attempt.EssayAnswers[qi]) {
+				correct++
+				credit += hintFactor(qi)
+			}
+		case "matching":
+			if services.MatchingCorrect(q, attempt.MatchingAnswers[qi]) {
+				correct++
+				credit += hintFactor(qi)
+			}
+		}
+		graded++
+	}
 	for _, a := range answers {
 		qi := a["question_index"]
 		ai := a["answer_index"]
-		if qi < len(questions) && questions[qi].CorrectIndex == ai {
+		if qi >= 0 && qi < len(questions) && answeredByIndex(questions[qi]) && questions[qi].CorrectIndex == ai {
 			correct++
+			credit += hintFactor(qi)
 		}
 	}
 
-	total := len(questions)
-	score := 0.0
-	if total > 0 {
-		score = float64(correct) / float64(total) * 100
+	if graded == 0 {
+		return correct, 0
 	}
+	return correct, credit / float64(graded) * 100
+}
 
-	answersJSON, _ := json.Marshal(answers)
-	if err := h.quizRepo.SubmitAttempt(r.Context(), attemptID, score, correct, answersJSON); err != nil {
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to submit attempt", r))
+// answeredByIndex reports whether a question is answered by picking one of
+// its options, rather than with text or matches.
+func answeredByIndex(q models.QuizQuestion) bool {
+	switch q.Type {
+	case "essay", "fill_blank", "matching":
+		return false
+	}
+	return true
+}
+
+// GetHint reveals the hint for one question of an in-progress attempt and
+// records that it was used. Only the hint is returned — never the correct
+// answer or explanation.
+func (h *QuizHandler) GetHint(w http.ResponseWriter, r *http.Request) {
+	attemptID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid attempt ID", r))
+		return
+	}
+
+	questionIndex, err := strconv.Atoi(r.URL.Query().Get("question_index"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"question_index": "question_index must be an integer",
+		}, r))
+		return
+	}
+
+	attempt, err := h.quizRepo.GetAttemptByID(r.Context(), attemptID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Attempt not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if attempt.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if attempt.CompletedAt != nil {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Attempt has already been submitted", r))
+		return
+	}
+
+	quiz, err := h.quizRepo.GetByID(r.Context(), attempt.QuizID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return
+	}
+
+	if quiz.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	var config models.GenerateQuizRequest
+	_ = json.Unmarshal(quiz.ConfigJSON, &config)
+	if !config.EnableHints {
+		writeJSON(w, http.StatusForbidden, errorResp("HINTS_DISABLED", "Hints are not enabled for this quiz", r))
+		return
+	}
+
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to parse quiz questions", r))
+		return
+	}
+
+	if questionIndex < 0 || questionIndex >= len(questions) {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"question_index": fmt.Sprintf("question_index must be between 0 and %d", len(questions)-1),
+		}, r))
+		return
+	}
+
+	hint := strings.TrimSpace(questions[questionIndex].Hint)
+	if hint == "" {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "No hint available for this question", r))
+		return
+	}
+
+	if err := h.quizRepo.RecordHintUsage(r.Context(), attemptID, questionIndex); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to record hint usage", r))
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"score_percent": score,
-		"correct_count": correct,
-		"total":         total,
-		"attempt_id":    attemptID,
+		"question_index":       questionIndex,
+		"hint":                 hint,
+		"hint_penalty_percent": config.HintPenaltyPercent,
 	})
 }
 
@@ -464,9 +1006,26 @@ func (h *QuizHandler) GetAttempt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
+	var questions []json.RawMessage
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to parse quiz questions", r))
+		return
+	}
+
+	ordered := orderAttemptReview(attempt, questions)
+	response := map[string]interface{}{
 		"attempt":   attempt,
-		"questions": quiz.QuestionsJSON,
+		"questions": ordered,
 		"quiz":      quiz,
-	})
+	}
+	if h.reports != nil {
+		reported, err := h.reports.ListReportedQuestions(r.Context(), quiz.ID)
+		if err != nil {
+			log.Printf("GetAttempt: failed to list reported questions for quiz %s: %v", quiz.ID, err)
+		} else {
+			response["reported_questions"] = reviewPositions(attempt, reported)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
 }
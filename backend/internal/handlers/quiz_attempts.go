@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// ownedQuiz loads the quiz named in the URL and checks the caller owns it,
+// writing the error response when it does not.
+func (h *QuizHandler) ownedQuiz(w http.ResponseWriter, r *http.Request) (*models.Quiz, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid quiz ID", r))
+		return nil, false
+	}
+
+	quiz, err := h.quizRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return nil, false
+	}
+	if quiz.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return nil, false
+	}
+	return quiz, true
+}
+
+// ListAttempts returns the caller's attempts on a quiz, oldest first, with
+// the score and time taken of each submitted one.
+func (h *QuizHandler) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	quiz, ok := h.ownedQuiz(w, r)
+	if !ok {
+		return
+	}
+
+	attempts, err := h.quizRepo.ListAttemptsByUser(r.Context(), quiz.ID, quiz.UserID)
+	if err != nil {
+		log.Printf("QuizHandler.ListAttempts: quiz %s: %v", quiz.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to list attempts", r))
+		return
+	}
+
+	summaries := make([]models.QuizAttemptSummary, len(attempts))
+	for i, a := range attempts {
+		summaries[i] = models.QuizAttemptSummary{
+			ID:               a.ID,
+			StartedAt:        a.StartedAt,
+			CompletedAt:      a.CompletedAt,
+			ScorePercent:     a.ScorePercent,
+			CorrectCount:     a.CorrectCount,
+			TimeTakenSeconds: a.TimeTakenSeconds,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"quiz_id":  quiz.ID,
+		"attempts": summaries,
+	})
+}
+
+// QuestionStats returns, for each question of the quiz, how often the caller
+// answered it correctly across their submitted attempts, so consistently
+// missed questions stand out. Attempts taken on an earlier set of questions
+// are left out and counted as skipped_attempts.
+func (h *QuizHandler) QuestionStats(w http.ResponseWriter, r *http.Request) {
+	quiz, ok := h.ownedQuiz(w, r)
+	if !ok {
+		return
+	}
+
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to parse quiz questions", r))
+		return
+	}
+
+	attempts, err := h.quizRepo.ListAttemptsByUser(r.Context(), quiz.ID, quiz.UserID)
+	if err != nil {
+		log.Printf("QuizHandler.QuestionStats: quiz %s: %v", quiz.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to compute question stats", r))
+		return
+	}
+
+	stats, counted, skipped := questionStats(questions, attempts)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"quiz_id":          quiz.ID,
+		"attempts":         counted,
+		"skipped_attempts": skipped,
+		"questions":        stats,
+	})
+}
+
+// questionStats tallies each question's correct answers over the submitted
+// attempts. An attempt whose question order or answers do not fit the
+// current questions was taken before the quiz was regenerated; its indexes
+// point at other questions, so it is skipped rather than counted.
+func questionStats(questions []models.QuizQuestion, attempts []*models.QuizAttempt) ([]models.QuestionStat, int, int) {
+	stats := make([]models.QuestionStat, len(questions))
+	for qi, q := range questions {
+		stats[qi] = models.QuestionStat{QuestionIndex: qi, Question: q.Question, Type: q.Type}
+	}
+
+	counted, skipped := 0, 0
+	for _, a := range attempts {
+		if a.CompletedAt == nil {
+			continue
+		}
+		results, ok := attemptQuestionResults(questions, a)
+		if !ok {
+			skipped++
+			continue
+		}
+		counted++
+		for qi, correct := range results {
+			stats[qi].Attempts++
+			if correct {
+				stats[qi].Correct++
+			}
+		}
+	}
+
+	for i := range stats {
+		if stats[i].Attempts > 0 {
+			stats[i].CorrectRate = float64(stats[i].Correct) / float64(stats[i].Attempts) * 100
+		}
+	}
+	return stats, counted, skipped
+}
+
+// attemptQuestionResults reports which questions a submitted attempt got
+// right, judged as gradeAnswers does. Unanswered questions count as wrong and
+// ungraded essays are left out. It returns false when the attempt does not
+// fit the questions.
+func attemptQuestionResults(questions []models.QuizQuestion, a *models.QuizAttempt) (map[int]bool, bool) {
+	if a.QuestionOrder != nil && len(a.QuestionOrder) != len(questions) {
+		return nil, false
+	}
+	var answers []map[string]int
+	if len(a.AnswersJSON) > 0 {
+		if err := json.Unmarshal(a.AnswersJSON, &answers); err != nil {
+			return nil, false
+		}
+	}
+
+	results := make(map[int]bool, len(questions))
+	for qi, q := range questions {
+		switch q.Type {
+		case "essay":
+			if grade, ok := a.EssayGrades[qi]; ok && grade.Graded {
+				results[qi] = grade.Score >= essayPassScore
+			}
+		case "fill_blank":
+			results[qi] = services.FillBlankCorrect(q, a.EssayAnswers[qi])
+		case "matching":
+			results[qi] = services.MatchingCorrect(q, a.MatchingAnswers[qi])
+		default:
+			results[qi] = false
+		}
+	}
+	for _, answer := range answers {
+		qi := answer["question_index"]
+		if qi < 0 || qi >= len(questions) {
+			return nil, false
+		}
+		if answeredByIndex(questions[qi]) && questions[qi].CorrectIndex == answer["answer_index"] {
+			results[qi] = true
+		}
+	}
+	return results, true
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func completedAttempt(answers string, order []int) *models.QuizAttempt {
+	done := time.Now()
+	return &models.QuizAttempt{ID: uuid.New(), AnswersJSON: json.RawMessage(answers), CompletedAt: &done, QuestionOrder: order}
+}
+
+func TestQuestionStats_CorrectRatePerQuestion(t *testing.T) {
+	questions := []models.QuizQuestion{
+		{Question: "Q0", Type: "multiple_choice", CorrectIndex: 1},
+		{Question: "Q1", Type: "true_false", CorrectIndex: 0},
+		{Question: "Q2", Type: "fill_blank", AcceptedAnswers: []string{"mitosis"}},
+		{Question: "Q3", Type: "essay"},
+	}
+	first := completedAttempt(`[{"question_index":0,"answer_index":1},{"question_index":1,"answer_index":1}]`, nil)
+	first.EssayAnswers = map[int]string{2: " Mitosis "}
+	first.EssayGrades = map[int]models.EssayGrade{3: {Score: 80, Graded: true}}
+	second := completedAttempt(`[{"question_index":0,"answer_index":1}]`, []int{3, 2, 1, 0})
+	second.EssayGrades = map[int]models.EssayGrade{3: {Graded: false}}
+	inProgress := &models.QuizAttempt{ID: uuid.New(), AnswersJSON: json.RawMessage(`[{"question_index":1,"answer_index":0}]`)}
+
+	stats, counted, skipped := questionStats(questions, []*models.QuizAttempt{first, second, inProgress})
+
+	if counted != 2 || skipped != 0 {
+		t.Fatalf("counted=%d skipped=%d, want 2 and 0", counted, skipped)
+	}
+	want := []struct{ attempts, correct int }{{2, 2}, {2, 0}, {2, 1}, {1, 1}}
+	for qi, w := range want {
+		if stats[qi].Attempts != w.attempts || stats[qi].Correct != w.correct {
+			t.Fatalf("question %d: attempts=%d correct=%d, want %d/%d", qi, stats[qi].Attempts, stats[qi].Correct, w.attempts, w.correct)
+		}
+	}
+	if stats[0].CorrectRate != 100 || stats[1].CorrectRate != 0 || stats[2].CorrectRate != 50 {
+		t.Fatalf("correct rates = %v/%v/%v, want 100/0/50", stats[0].CorrectRate, stats[1].CorrectRate, stats[2].CorrectRate)
+	}
+}
+
+func TestQuestionStats_SkipsAttemptsOnOtherQuestionSets(t *testing.T) {
+	questions := []models.QuizQuestion{
+		{Question: "Q0", Type: "multiple_choice", CorrectIndex: 0},
+		{Question: "Q1", Type: "multiple_choice", CorrectIndex: 0},
+	}
+	current := completedAttempt(`[{"question_index":1,"answer_index":0}]`, []int{1, 0})
+	longerOrder := completedAttempt(`[{"question_index":0,"answer_index":0}]`, []int{2, 1, 0})
+	outOfRange := completedAttempt(`[{"question_index":4,"answer_index":0}]`, nil)
+
+	stats, counted, skipped := questionStats(questions, []*models.QuizAttempt{current, longerOrder, outOfRange})
+
+	if counted != 1 || skipped != 2 {
+		t.Fatalf("counted=%d skipped=%d, want 1 and 2", counted, skipped)
+	}
+	if stats[0].Attempts != 1 || stats[0].Correct != 0 || stats[1].Correct != 1 {
+		t.Fatalf("stats = %+v, want only the current attempt counted", stats)
+	}
+}
+
+func TestQuestionStats_NonOwner_Returns403(t *testing.T) {
+	quizID := uuid.New()
+	repo := &stubQuizRepoForMutations{quiz: &models.Quiz{ID: quizID, UserID: uuid.New(), QuestionsJSON: json.RawMessage(`[]`)}}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.QuestionStats(rr, makeQuizRequest(http.MethodGet, "/api/v1/quizzes/"+quizID.String()+"/question-stats", quizID, uuid.New()))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if repo.attemptsListedFor != uuid.Nil {
+		t.Fatalf("expected attempts not to be listed")
+	}
+}
+
+func TestListAttempts_ReturnsScoresAndTimes(t *testing.T) {
+	userID := uuid.New()
+	quizID := uuid.New()
+	score, correct, seconds := 75.0, 3, 240
+	done := time.Now()
+	repo := &stubQuizRepoForMutations{
+		quiz: &models.Quiz{ID: quizID, UserID: userID},
+		attempts: []*models.QuizAttempt{
+			{ID: uuid.New(), QuizID: quizID, UserID: userID, AnswersJSON: json.RawMessage(`[]`), ScorePercent: &score, CorrectCount: &correct, TimeTakenSeconds: &seconds, CompletedAt: &done},
+			{ID: uuid.New(), QuizID: quizID, UserID: userID, AnswersJSON: json.RawMessage(`[]`)},
+		},
+	}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.ListAttempts(rr, makeQuizRequest(http.MethodGet, "/api/v1/quizzes/"+quizID.String()+"/attempts", quizID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if repo.attemptsListedFor != userID {
+		t.Fatalf("attempts listed for %s, want %s", repo.attemptsListedFor, userID)
+	}
+	var resp struct {
+		Attempts []map[string]interface{} `json:"attempts"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Attempts) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(resp.Attempts))
+	}
+	if resp.Attempts[0]["score_percent"] != 75.0 || resp.Attempts[0]["time_taken_seconds"] != 240.0 {
+		t.Fatalf("first attempt = %v, want score 75 and 240 seconds", resp.Attempts[0])
+	}
+	if _, ok := resp.Attempts[0]["answers"]; ok {
+		t.Fatalf("attempt list should not include answers")
+	}
+	if resp.Attempts[1]["score_percent"] != nil {
+		t.Fatalf("unsubmitted attempt score = %v, want null", resp.Attempts[1]["score_percent"])
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+const essayQuizQuestions = `[
+	{"question":"Q1","type":"multiple_choice","options":["a","b"],"correct_index":1,"difficulty":"easy","topic":"t"},
+	{"question":"Explain photosynthesis.","type":"essay","options":[],"rubric":"Light, water and CO2 make glucose and oxygen.","difficulty":"medium","topic":"t"},
+	{"question":"Explain respiration.","type":"essay","options":[],"rubric":"Glucose and oxygen release energy.","difficulty":"medium","topic":"t"}
+]`
+
+type fakeEssayGrader struct {
+	mu    sync.Mutex
+	calls []string
+	score map[string]int
+}
+
+func (g *fakeEssayGrader) GradeEssay(ctx context.Context, question, rubric, answer string) (int, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls = append(g.calls, question)
+	score, ok := g.score[question]
+	if !ok {
+		return 0, "", errors.New("model unavailable")
+	}
+	return score, "Covers the main points.", nil
+}
+
+func newEssayTestRepo(userID uuid.UUID, config string) (*stubQuizRepoForMutations, uuid.UUID) {
+	attemptID := uuid.New()
+	quizID := uuid.New()
+	return &stubQuizRepoForMutations{
+		attempt: &models.QuizAttempt{
+			ID:          attemptID,
+			QuizID:      quizID,
+			UserID:      userID,
+			StartedAt:   time.Now(),
+			AnswersJSON: json.RawMessage(`[{"question_index":0,"answer_index":1}]`),
+			EssayAnswers: map[int]string{
+				1: "Plants turn light, water and CO2 into glucose.",
+				2: "Cells break down glucose.",
+			},
+		},
+		quiz: &models.Quiz{
+			ID:            quizID,
+			UserID:        userID,
+			ConfigJSON:    json.RawMessage(config),
+			QuestionsJSON: json.RawMessage(essayQuizQuestions),
+		},
+	}, attemptID
+}
+
+func TestSubmitAttempt_GradesEssays(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newEssayTestRepo(userID, `{"ai_grading":true}`)
+	// Question 2 has no score, so the fake grader fails on it.
+	grader := &fakeEssayGrader{score: map[string]int{"Explain photosynthesis.": 80}}
+	h := &QuizHandler{quizRepo: repo, essayGrader: grader}
+
+	rr := httptest.NewRecorder()
+	h.SubmitAttempt(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/submit", attemptID, userID, `{}`))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if len(grader.calls) != 2 {
+		t.Fatalf("expected both essays to be sent for grading, got %v", grader.calls)
+	}
+	grades := repo.submittedEssayGrades
+	if g := grades[1]; !g.Graded || g.Score != 80 || g.Feedback == "" {
+		t.Fatalf("expected essay 1 graded 80 with feedback, got %+v", g)
+	}
+	if g, ok := grades[2]; !ok || g.Graded {
+		t.Fatalf("expected essay 2 to be stored ungraded after the grader failed, got %+v", g)
+	}
+	// One objective answer at full credit and one essay at 80, out of two graded questions.
+	if repo.submittedScore != 90 {
+		t.Fatalf("expected score 90, got %v", repo.submittedScore)
+	}
+}
+
+func TestSubmitAttempt_EssaysUngradedWithoutOptIn(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newEssayTestRepo(userID, `{}`)
+	grader := &fakeEssayGrader{score: map[string]int{"Explain photosynthesis.": 80, "Explain respiration.": 80}}
+	h := &QuizHandler{quizRepo: repo, essayGrader: grader}
+
+	rr := httptest.NewRecorder()
+	h.SubmitAttempt(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/submit", attemptID, userID, `{}`))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if len(grader.calls) != 0 {
+		t.Fatalf("expected no grading calls without ai_grading, got %v", grader.calls)
+	}
+	for qi, g := range repo.submittedEssayGrades {
+		if g.Graded {
+			t.Fatalf("expected essay %d ungraded, got %+v", qi, g)
+		}
+	}
+	if repo.submittedScore != 100 {
+		t.Fatalf("expected ungraded essays to be left out of the score, got %v", repo.submittedScore)
+	}
+}
+
+func TestSubmitAttempt_BlankEssayScoresZero(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newEssayTestRepo(userID, `{"ai_grading":true}`)
+	repo.attempt.EssayAnswers = map[int]string{1: "   "}
+	grader := &fakeEssayGrader{score: map[string]int{"Explain respiration.": 40}}
+	h := &QuizHandler{quizRepo: repo, essayGrader: grader}
+
+	rr := httptest.NewRecorder()
+	h.SubmitAttempt(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/submit", attemptID, userID, `{}`))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if len(grader.calls) != 0 {
+		t.Fatalf("expected blank essays not to be sent for grading, got %v", grader.calls)
+	}
+	if g := repo.submittedEssayGrades[1]; !g.Graded || g.Score != 0 {
+		t.Fatalf("expected blank essay graded 0, got %+v", g)
+	}
+	// 1 objective + 0 + 0 out of three graded questions.
+	if got := repo.submittedScore; got < 33.3 || got > 33.4 {
+		t.Fatalf("expected score of one third, got %v", got)
+	}
+}
+
+func TestSaveProgress_EssayAnswer(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newEssayTestRepo(userID, `{}`)
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.SaveProgress(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/save-progress", attemptID, userID, `{"question_index":1,"answer_text":"Light becomes sugar."}`))
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusNoContent, rr.Code, rr.Body.String())
+	}
+	if repo.savedEssayIndex != 1 || repo.savedEssayAnswer != "Light becomes sugar." {
+		t.Fatalf("expected essay 1 to be saved, got %d %q", repo.savedEssayIndex, repo.savedEssayAnswer)
+	}
+}
+
+func TestSaveProgress_EssayAnswerValidation(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not an essay", `{"question_index":0,"answer_text":"b"}`},
+		{"index out of range", `{"question_index":3,"answer_text":"b"}`},
+		{"too long", `{"question_index":1,"answer_text":"` + strings.Repeat("a", 5001) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, attemptID := newEssayTestRepo(userID, `{}`)
+			h := &QuizHandler{quizRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.SaveProgress(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/save-progress", attemptID, userID, tt.body))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+			if repo.savedProgress {
+				t.Fatalf("expected nothing to be saved")
+			}
+		})
+	}
+}
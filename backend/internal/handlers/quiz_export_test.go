@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestQuizExport(t *testing.T) {
+	ownerID := uuid.New()
+	quizID := uuid.New()
+	questions := json.RawMessage(`[{"question":"What is 2+2?","type":"multiple_choice","options":["3","4"],"correct_index":1,"explanation":"Basic arithmetic","topic":"Math"}]`)
+
+	tests := []struct {
+		name      string
+		query     string
+		userID    uuid.UUID
+		questions json.RawMessage
+		wantCode  int
+		wantFile  string
+	}{
+		{"student copy", "?format=pdf", ownerID, questions, http.StatusOK, `filename="cell-biology.pdf"`},
+		{"instructor copy", "?format=pdf&key=true", ownerID, questions, http.StatusOK, `filename="cell-biology-answer-key.pdf"`},
+		{"other user", "?format=pdf", uuid.New(), questions, http.StatusForbidden, ""},
+		{"still generating", "?format=pdf", ownerID, json.RawMessage(`[]`), http.StatusConflict, ""},
+		{"unsupported format", "?format=docx", ownerID, questions, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubQuizRepoForMutations{
+				quiz: &models.Quiz{ID: quizID, UserID: ownerID, Title: "Cell Biology", QuestionsJSON: tt.questions},
+			}
+			h := &QuizHandler{quizRepo: repo}
+
+			req := makeAttemptRequest(http.MethodGet, "/api/v1/quizzes/"+quizID.String()+"/export"+tt.query, quizID, tt.userID, "")
+			rr := httptest.NewRecorder()
+			h.Export(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if got := rr.Header().Get("Content-Type"); got != "application/pdf" {
+				t.Fatalf("expected application/pdf, got %q", got)
+			}
+			if got := rr.Header().Get("Content-Disposition"); !strings.Contains(got, tt.wantFile) {
+				t.Fatalf("expected %s in Content-Disposition, got %q", tt.wantFile, got)
+			}
+			if !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) {
+				t.Fatalf("expected a PDF body")
+			}
+
+			hasKey := bytes.Contains(rr.Body.Bytes(), []byte("Answer Key"))
+			if wantKey := strings.Contains(tt.query, "key=true"); hasKey != wantKey {
+				t.Fatalf("answer key present = %v, want %v", hasKey, wantKey)
+			}
+		})
+	}
+}
+
+func TestQuizExport_CSV(t *testing.T) {
+	ownerID := uuid.New()
+	quizID := uuid.New()
+	repo := &stubQuizRepoForMutations{
+		quiz: &models.Quiz{ID: quizID, UserID: ownerID, Title: "Cell Biology", QuestionsJSON: json.RawMessage(`[{"question":"Is a cell alive?","type":"true_false","options":["True","False"],"correct_index":0,"difficulty":"easy"}]`)},
+	}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Export(rr, makeAttemptRequest(http.MethodGet, "/api/v1/quizzes/"+quizID.String()+"/export?format=csv", quizID, ownerID, ""))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Disposition"); !strings.Contains(got, `filename="cell-biology.csv"`) {
+		t.Fatalf("expected cell-biology.csv in Content-Disposition, got %q", got)
+	}
+	want := "question,type,options,correct,explanation,topic,difficulty\nIs a cell alive?,true_false,True|False,A,,,easy\n"
+	if rr.Body.String() != want {
+		t.Fatalf("unexpected CSV:\n%s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.Export(rr, makeAttemptRequest(http.MethodGet, "/api/v1/quizzes/"+quizID.String()+"/export?format=csv", quizID, uuid.New(), ""))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for another user, got %d", rr.Code)
+	}
+}
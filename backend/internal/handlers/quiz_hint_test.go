@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+const hintQuizQuestions = `[
+	{"question":"Q1","type":"multiple_choice","options":["a","b"],"correct_index":1,"explanation":"because b","hint":"think about b's neighbour","difficulty":"easy","topic":"t"},
+	{"question":"Q2","type":"multiple_choice","options":["a","b"],"correct_index":0,"explanation":"because a","hint":"","difficulty":"easy","topic":"t"}
+]`
+
+func newHintTestRepo(userID uuid.UUID, config string) (*stubQuizRepoForMutations, uuid.UUID) {
+	attemptID := uuid.New()
+	quizID := uuid.New()
+	return &stubQuizRepoForMutations{
+		attempt: &models.QuizAttempt{ID: attemptID, QuizID: quizID, UserID: userID, StartedAt: time.Now()},
+		quiz: &models.Quiz{
+			ID:            quizID,
+			UserID:        userID,
+			ConfigJSON:    json.RawMessage(config),
+			QuestionsJSON: json.RawMessage(hintQuizQuestions),
+		},
+	}, attemptID
+}
+
+func TestGetHint_DisabledQuizReturnsForbidden(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newHintTestRepo(userID, `{"enable_hints":false}`)
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.GetHint(rr, makeAttemptRequest(http.MethodGet, "/api/v1/quiz-attempts/"+attemptID.String()+"/hint?question_index=0", attemptID, userID, ""))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if len(repo.hintsRecorded) != 0 {
+		t.Fatalf("hint usage should not be recorded when hints are disabled")
+	}
+}
+
+func TestGetHint_ReturnsHintWithoutAnswer(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newHintTestRepo(userID, `{"enable_hints":true}`)
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.GetHint(rr, makeAttemptRequest(http.MethodGet, "/api/v1/quiz-attempts/"+attemptID.String()+"/hint?question_index=0", attemptID, userID, ""))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "think about b's neighbour") {
+		t.Fatalf("expected hint in response, got %s", body)
+	}
+	if strings.Contains(body, "correct_index") || strings.Contains(body, "because b") {
+		t.Fatalf("hint response must not leak the answer: %s", body)
+	}
+	if len(repo.hintsRecorded) != 1 || repo.hintsRecorded[0] != 0 {
+		t.Fatalf("expected hint usage recorded for question 0, got %v", repo.hintsRecorded)
+	}
+}
+
+func TestGetHint_OutOfRangeIndexReturns400(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newHintTestRepo(userID, `{"enable_hints":true}`)
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.GetHint(rr, makeAttemptRequest(http.MethodGet, "/api/v1/quiz-attempts/"+attemptID.String()+"/hint?question_index=5", attemptID, userID, ""))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestSubmitAttempt_AppliesHintPenalty(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newHintTestRepo(userID, `{"enable_hints":true,"hint_penalty_percent":50}`)
+	repo.attempt.HintsUsed = []int{0}
+	repo.attempt.AnswersJSON = json.RawMessage(`[{"question_index":0,"answer_index":1},{"question_index":1,"answer_index":0}]`)
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.SubmitAttempt(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/submit", attemptID, userID, `{}`))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.submittedScore != 75 {
+		t.Fatalf("expected score 75 with one half-credit hinted answer, got %v", repo.submittedScore)
+	}
+}
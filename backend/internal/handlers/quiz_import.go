@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// maxQuizCSVBytes caps an imported question bank; a full quiz of long
+// questions is well under it.
+const maxQuizCSVBytes = 1 << 20
+
+// ImportCSV creates a quiz from an uploaded CSV of questions in the export
+// format. Malformed rows are skipped and listed in the response.
+func (h *QuizHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	r.Body = http.MaxBytesReader(w, r.Body, maxQuizCSVBytes)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		if strings.Contains(err.Error(), "http: request body too large") {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResp("FILE_TOO_LARGE", fmt.Sprintf("CSV exceeds the maximum size of %d MB", maxQuizCSVBytes>>20), r))
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "No file provided", r))
+		return
+	}
+	defer file.Close()
+
+	title := strings.TrimSpace(r.FormValue("title"))
+	if title == "" {
+		title = strings.TrimSpace(strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename)))
+	}
+	if title == "" {
+		title = "Imported quiz"
+	}
+	if len([]rune(title)) > services.MaxTitleLength {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Invalid import", map[string]string{
+			"title": fmt.Sprintf("title must be at most %d characters", services.MaxTitleLength),
+		}, r))
+		return
+	}
+
+	questions, skipped, err := services.ParseQuizCSV(file)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", err.Error(), r))
+		return
+	}
+	if len(questions) == 0 {
+		fields := map[string]string{}
+		for _, row := range skipped {
+			fields[fmt.Sprintf("row_%d", row.Row)] = row.Reason
+		}
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "The CSV has no valid questions", fields, r))
+		return
+	}
+	if skipped == nil {
+		skipped = []services.QuizCSVSkippedRow{}
+	}
+
+	questionsJSON, _ := json.Marshal(questions)
+	configJSON, _ := json.Marshal(models.GenerateQuizRequest{Title: title, NumQuestions: len(questions)})
+	quiz := &models.Quiz{
+		UserID:        userID,
+		Title:         title,
+		QuestionCount: len(questions),
+		QuestionsJSON: questionsJSON,
+		ConfigJSON:    configJSON,
+	}
+	if err := h.quizRepo.Create(r.Context(), quiz); err != nil {
+		log.Printf("QuizHandler.ImportCSV: failed to create quiz for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create quiz", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"quiz":     quiz,
+		"imported": len(questions),
+		"skipped":  skipped,
+	})
+}
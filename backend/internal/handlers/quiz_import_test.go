@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+func makeQuizImportRequest(t *testing.T, userID uuid.UUID, filename, title, body string) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if title != "" {
+		if err := mw.WriteField("title", title); err != nil {
+			t.Fatalf("failed to write title: %v", err)
+		}
+	}
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write([]byte(body))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/quizzes/import", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestQuizImportCSV(t *testing.T) {
+	const bank = "question,type,options,correct\n" +
+		"Capital of France?,multiple_choice,Berlin|Paris|Rome|Madrid,B\n" +
+		"Water boils at 100C at sea level.,true_false,True|False,A\n" +
+		"Broken row,multiple_choice,One|Two,A\n"
+
+	userID := uuid.New()
+	repo := &stubQuizRepoForGenerate{}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.ImportCSV(rr, makeQuizImportRequest(t, userID, "chapter-3.csv", "", bank))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected one quiz to be created, got %d", len(repo.created))
+	}
+	quiz := repo.created[0]
+	if quiz.UserID != userID || quiz.Title != "chapter-3" || quiz.QuestionCount != 2 || quiz.SummaryID != nil {
+		t.Fatalf("unexpected quiz: %+v", quiz)
+	}
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil || len(questions) != 2 {
+		t.Fatalf("expected 2 stored questions, got %s (%v)", quiz.QuestionsJSON, err)
+	}
+
+	var resp struct {
+		Imported int `json:"imported"`
+		Skipped  []struct {
+			Row int `json:"row"`
+		} `json:"skipped"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Imported != 2 || len(resp.Skipped) != 1 || resp.Skipped[0].Row != 4 {
+		t.Fatalf("unexpected import report: %s", rr.Body.String())
+	}
+}
+
+func TestQuizImportCSV_Rejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		body     string
+		wantCode int
+	}{
+		{"missing correct column", "", "question,options\nQ?,a|b|c|d\n", http.StatusBadRequest},
+		{"no valid rows", "", "question,correct,options\nQ?,A,a|b\n", http.StatusBadRequest},
+		{"title too long", string(bytes.Repeat([]byte("x"), 300)), "question,correct,options\nQ?,A,a|b|c|d\n", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubQuizRepoForGenerate{}
+			h := &QuizHandler{quizRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.ImportCSV(rr, makeQuizImportRequest(t, uuid.New(), "bank.csv", tt.title, tt.body))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if len(repo.created) != 0 {
+				t.Fatalf("expected no quiz to be created")
+			}
+		})
+	}
+}
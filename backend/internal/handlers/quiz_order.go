@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+
+	"lectura-backend/internal/models"
+)
+
+// attemptQuestionOrder picks the order a new attempt shows the quiz's
+// questions in: a fresh shuffle when the quiz shuffles questions, or nil for
+// generation order. Quizzes whose config predates the option shuffle, as the
+// take page always did for them.
+func attemptQuestionOrder(quiz *models.Quiz) []int {
+	var config struct {
+		ShuffleQuestions *bool `json:"shuffle_questions"`
+	}
+	_ = json.Unmarshal(quiz.ConfigJSON, &config)
+	if config.ShuffleQuestions != nil && !*config.ShuffleQuestions {
+		return nil
+	}
+
+	var questions []json.RawMessage
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil || len(questions) < 2 {
+		return nil
+	}
+	return rand.Perm(len(questions))
+}
+
+// isQuestionOrder reports whether order is a permutation of n questions.
+func isQuestionOrder(order []int, n int) bool {
+	if len(order) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, qi := range order {
+		if qi < 0 || qi >= n || seen[qi] {
+			return false
+		}
+		seen[qi] = true
+	}
+	return true
+}
+
+// orderAttemptReview puts a quiz's questions in the order the attempt showed
+// them and renumbers the attempt's answers, hints, essays and matches to those
+// positions, so every question_index in the review points into the returned
+// questions. Each answer keeps its stored index as original_question_index.
+// An attempt without a usable order is returned in generation order, unchanged.
+func orderAttemptReview(attempt *models.QuizAttempt, questions []json.RawMessage) []json.RawMessage {
+	order := attempt.QuestionOrder
+	if !isQuestionOrder(order, len(questions)) {
+		attempt.QuestionOrder = nil
+		return questions
+	}
+
+	ordered := make([]json.RawMessage, len(order))
+	position := make(map[int]int, len(order))
+	for pos, qi := range order {
+		ordered[pos] = questions[qi]
+		position[qi] = pos
+	}
+
+	var answers []map[string]int
+	if len(attempt.AnswersJSON) > 0 && json.Unmarshal(attempt.AnswersJSON, &answers) == nil {
+		for _, a := range answers {
+			qi := a["question_index"]
+			if pos, ok := position[qi]; ok {
+				a["original_question_index"] = qi
+				a["question_index"] = pos
+			}
+		}
+		if remapped, err := json.Marshal(answers); err == nil {
+			attempt.AnswersJSON = remapped
+		}
+	}
+
+	hints := make([]int, 0, len(attempt.HintsUsed))
+	for _, qi := range attempt.HintsUsed {
+		if pos, ok := position[qi]; ok {
+			hints = append(hints, pos)
+		}
+	}
+	attempt.HintsUsed = hints
+
+	if attempt.EssayAnswers != nil {
+		essayAnswers := make(map[int]string, len(attempt.EssayAnswers))
+		for qi, text := range attempt.EssayAnswers {
+			if pos, ok := position[qi]; ok {
+				essayAnswers[pos] = text
+			}
+		}
+		attempt.EssayAnswers = essayAnswers
+	}
+	if attempt.EssayGrades != nil {
+		essayGrades := make(map[int]models.EssayGrade, len(attempt.EssayGrades))
+		for qi, grade := range attempt.EssayGrades {
+			if pos, ok := position[qi]; ok {
+				essayGrades[pos] = grade
+			}
+		}
+		attempt.EssayGrades = essayGrades
+	}
+	if attempt.MatchingAnswers != nil {
+		matchingAnswers := make(map[int][]int, len(attempt.MatchingAnswers))
+		for qi, matches := range attempt.MatchingAnswers {
+			if pos, ok := position[qi]; ok {
+				matchingAnswers[pos] = matches
+			}
+		}
+		attempt.MatchingAnswers = matchingAnswers
+	}
+
+	return ordered
+}
+
+// reviewPositions maps question indexes in generation order to their
+// positions in the attempt's review, after orderAttemptReview.
+func reviewPositions(attempt *models.QuizAttempt, indexes []int) []int {
+	if attempt.QuestionOrder == nil {
+		return indexes
+	}
+	position := make(map[int]int, len(attempt.QuestionOrder))
+	for pos, qi := range attempt.QuestionOrder {
+		position[qi] = pos
+	}
+	positions := make([]int, 0, len(indexes))
+	for _, qi := range indexes {
+		if pos, ok := position[qi]; ok {
+			positions = append(positions, pos)
+		}
+	}
+	sort.Ints(positions)
+	return positions
+}
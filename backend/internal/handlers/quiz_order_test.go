@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestAttemptQuestionOrder(t *testing.T) {
+	questions := json.RawMessage(`[{"question":"Q0"},{"question":"Q1"},{"question":"Q2"},{"question":"Q3"}]`)
+
+	tests := []struct {
+		name      string
+		config    string
+		questions json.RawMessage
+		shuffled  bool
+	}{
+		{"shuffle on", `{"shuffle_questions":true}`, questions, true},
+		{"config predates shuffle option", `{"num_questions":4}`, questions, true},
+		{"shuffle off", `{"shuffle_questions":false}`, questions, false},
+		{"single question", `{"shuffle_questions":true}`, json.RawMessage(`[{"question":"Q0"}]`), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := attemptQuestionOrder(&models.Quiz{ConfigJSON: json.RawMessage(tt.config), QuestionsJSON: tt.questions})
+			if !tt.shuffled {
+				if order != nil {
+					t.Fatalf("expected generation order, got %v", order)
+				}
+				return
+			}
+			sorted := append([]int(nil), order...)
+			sort.Ints(sorted)
+			for i, qi := range sorted {
+				if qi != i {
+					t.Fatalf("expected a permutation of 4 questions, got %v", order)
+				}
+			}
+		})
+	}
+}
+
+func TestStartAttempt_PersistsQuestionOrder(t *testing.T) {
+	userID := uuid.New()
+	quizID := uuid.New()
+	repo := &stubQuizRepoForMutations{
+		quiz: &models.Quiz{ID: quizID, UserID: userID, ConfigJSON: json.RawMessage(`{"shuffle_questions":true}`), QuestionsJSON: json.RawMessage(`[{},{},{}]`)},
+	}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.StartAttempt(rr, makeQuizRequest(http.MethodPost, "/api/v1/quizzes/"+quizID.String()+"/start", quizID, userID))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		QuestionOrder []int `json:"question_order"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if repo.createdAttempt == nil || len(repo.createdAttempt.QuestionOrder) != 3 {
+		t.Fatalf("expected the shuffle to be stored on the attempt, got %+v", repo.createdAttempt)
+	}
+	for i, qi := range body.QuestionOrder {
+		if repo.createdAttempt.QuestionOrder[i] != qi {
+			t.Fatalf("expected response order %v to match stored order %v", body.QuestionOrder, repo.createdAttempt.QuestionOrder)
+		}
+	}
+}
+
+func TestGetAttempt_ReturnsQuestionsInAttemptOrder(t *testing.T) {
+	userID := uuid.New()
+	quizID := uuid.New()
+	attemptID := uuid.New()
+
+	tests := []struct {
+		name          string
+		order         []int
+		wantQuestions []string
+		wantAnswers   map[int]int // question_index -> answer_index
+		wantHints     []int
+	}{
+		{"shuffled", []int{2, 0, 1}, []string{"Q2", "Q0", "Q1"}, map[int]int{1: 1, 0: 3}, []int{0}},
+		{"generation order", nil, []string{"Q0", "Q1", "Q2"}, map[int]int{0: 1, 2: 3}, []int{2}},
+		{"stale order", []int{0, 1}, []string{"Q0", "Q1", "Q2"}, map[int]int{0: 1, 2: 3}, []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubQuizRepoForMutations{
+				quiz: &models.Quiz{ID: quizID, UserID: userID, QuestionsJSON: json.RawMessage(`[{"question":"Q0"},{"question":"Q1"},{"question":"Q2"}]`)},
+				attempt: &models.QuizAttempt{
+					ID: attemptID, QuizID: quizID, UserID: userID,
+					AnswersJSON:   json.RawMessage(`[{"question_index":0,"answer_index":1},{"question_index":2,"answer_index":3}]`),
+					HintsUsed:     []int{2},
+					QuestionOrder: tt.order,
+				},
+			}
+			h := &QuizHandler{quizRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.GetAttempt(rr, makeAttemptRequest(http.MethodGet, "/api/v1/quiz-attempts/"+attemptID.String(), attemptID, userID, ""))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			var body struct {
+				Attempt struct {
+					Answers   []map[string]int `json:"answers"`
+					HintsUsed []int            `json:"hints_used"`
+				} `json:"attempt"`
+				Questions []models.QuizQuestion `json:"questions"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			for i, want := range tt.wantQuestions {
+				if body.Questions[i].Question != want {
+					t.Fatalf("expected questions %v, got %+v", tt.wantQuestions, body.Questions)
+				}
+			}
+			for _, a := range body.Attempt.Answers {
+				if want, ok := tt.wantAnswers[a["question_index"]]; !ok || want != a["answer_index"] {
+					t.Fatalf("expected answers %v by position, got %v", tt.wantAnswers, body.Attempt.Answers)
+				}
+			}
+			if len(body.Attempt.HintsUsed) != len(tt.wantHints) || body.Attempt.HintsUsed[0] != tt.wantHints[0] {
+				t.Fatalf("expected hints %v, got %v", tt.wantHints, body.Attempt.HintsUsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// maxSuggestedFixLength caps the free-text fix attached to a question report.
+const maxSuggestedFixLength = 2000
+
+var questionReportReasons = []string{"incorrect_answer", "ambiguous", "typo", "off_topic", "other"}
+
+type quizQuestionRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
+	CreateQuestionReport(ctx context.Context, report *models.QuestionReport) error
+	ListCompletedAttempts(ctx context.Context, quizID uuid.UUID) ([]*models.QuizAttempt, error)
+	UpdateQuestion(ctx context.Context, quizID uuid.UUID, index int, questions json.RawMessage, scores []models.AttemptScore) error
+}
+
+type QuizQuestionHandler struct {
+	quizRepo quizQuestionRepository
+}
+
+func NewQuizQuestionHandler(quizRepo quizQuestionRepository) *QuizQuestionHandler {
+	return &QuizQuestionHandler{quizRepo: quizRepo}
+}
+
+// ownedQuizQuestion loads the quiz named in the URL, checks the caller owns
+// it and parses the question index, writing the error response when any of
+// that fails.
+func (h *QuizQuestionHandler) ownedQuizQuestion(w http.ResponseWriter, r *http.Request) (*models.Quiz, []models.QuizQuestion, int, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid quiz ID", r))
+		return nil, nil, 0, false
+	}
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid question index", r))
+		return nil, nil, 0, false
+	}
+
+	quiz, err := h.quizRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return nil, nil, 0, false
+	}
+	if quiz.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return nil, nil, 0, false
+	}
+
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to parse quiz questions", r))
+		return nil, nil, 0, false
+	}
+	if index < 0 || index >= len(questions) {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", map[string]string{
+			"index": fmt.Sprintf("index must be between 0 and %d", len(questions)-1),
+		}, r))
+		return nil, nil, 0, false
+	}
+	return quiz, questions, index, true
+}
+
+// Report records that a question is wrong or unclear, with an optional
+// suggested fix. Open reports are flagged in attempt reviews until the
+// question is edited.
+func (h *QuizQuestionHandler) Report(w http.ResponseWriter, r *http.Request) {
+	var req models.ReportQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	fields := map[string]string{}
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	if !isQuestionReportReason(reason) {
+		fields["reason"] = "reason must be one of: " + strings.Join(questionReportReasons, ", ")
+	}
+	fix := strings.TrimSpace(req.SuggestedFix)
+	if len([]rune(fix)) > maxSuggestedFixLength {
+		fields["suggested_fix"] = fmt.Sprintf("suggested_fix must be at most %d characters", maxSuggestedFixLength)
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	quiz, _, index, ok := h.ownedQuizQuestion(w, r)
+	if !ok {
+		return
+	}
+
+	report := &models.QuestionReport{
+		QuizID:        quiz.ID,
+		UserID:        quiz.UserID,
+		QuestionIndex: index,
+		Reason:        reason,
+	}
+	if fix != "" {
+		report.SuggestedFix = &fix
+	}
+	if err := h.quizRepo.CreateQuestionReport(r.Context(), report); err != nil {
+		log.Printf("QuizQuestionHandler.Report: quiz %s question %d: %v", quiz.ID, index, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to report question", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, report)
+}
+
+// Update replaces one question of the quiz. The question keeps its type so
+// the answers stored on attempts still point at real options, and completed
+// attempts are regraded against the corrected question.
+func (h *QuizQuestionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req models.QuizQuestion
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	quiz, questions, index, ok := h.ownedQuizQuestion(w, r)
+	if !ok {
+		return
+	}
+
+	edited, fields := services.ValidateEditedQuizQuestion(req)
+	if len(fields) == 0 && edited.Type != questions[index].Type {
+		fields = map[string]string{"type": "type cannot be changed on an existing question"}
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+	questions[index] = edited
+
+	questionsJSON, err := json.Marshal(questions)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update question", r))
+		return
+	}
+
+	attempts, err := h.quizRepo.ListCompletedAttempts(r.Context(), quiz.ID)
+	if err != nil {
+		log.Printf("QuizQuestionHandler.Update: quiz %s: %v", quiz.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update question", r))
+		return
+	}
+	var config models.GenerateQuizRequest
+	_ = json.Unmarshal(quiz.ConfigJSON, &config)
+	scores := make([]models.AttemptScore, 0, len(attempts))
+	for _, a := range attempts {
+		var answers []map[string]int
+		if err := json.Unmarshal(a.AnswersJSON, &answers); err != nil {
+			continue
+		}
+		correct, score := gradeAnswers(questions, answers, a, config.HintPenaltyPercent, a.EssayGrades)
+		scores = append(scores, models.AttemptScore{AttemptID: a.ID, ScorePercent: score, CorrectCount: correct})
+	}
+
+	if err := h.quizRepo.UpdateQuestion(r.Context(), quiz.ID, index, questionsJSON, scores); err != nil {
+		log.Printf("QuizQuestionHandler.Update: quiz %s question %d: %v", quiz.ID, index, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update question", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"quiz_id":           quiz.ID,
+		"question_index":    index,
+		"question":          edited,
+		"regraded_attempts": len(scores),
+	})
+}
+
+func isQuestionReportReason(reason string) bool {
+	for _, allowed := range questionReportReasons {
+		if reason == allowed {
+			return true
+		}
+	}
+	return false
+}
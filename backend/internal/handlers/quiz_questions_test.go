@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubQuizQuestionRepo struct {
+	quiz          *models.Quiz
+	attempts      []*models.QuizAttempt
+	reports       []*models.QuestionReport
+	updatedIndex  int
+	updatedJSON   json.RawMessage
+	updatedScores []models.AttemptScore
+}
+
+func (s *stubQuizQuestionRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
+	if s.quiz == nil {
+		return nil, context.Canceled
+	}
+	return s.quiz, nil
+}
+
+func (s *stubQuizQuestionRepo) CreateQuestionReport(ctx context.Context, report *models.QuestionReport) error {
+	report.ID = uuid.New()
+	s.reports = append(s.reports, report)
+	return nil
+}
+
+func (s *stubQuizQuestionRepo) ListCompletedAttempts(ctx context.Context, quizID uuid.UUID) ([]*models.QuizAttempt, error) {
+	return s.attempts, nil
+}
+
+func (s *stubQuizQuestionRepo) UpdateQuestion(ctx context.Context, quizID uuid.UUID, index int, questions json.RawMessage, scores []models.AttemptScore) error {
+	s.updatedIndex = index
+	s.updatedJSON = questions
+	s.updatedScores = scores
+	return nil
+}
+
+func newQuestionTestQuiz(userID uuid.UUID) *models.Quiz {
+	return &models.Quiz{
+		ID:     uuid.New(),
+		UserID: userID,
+		QuestionsJSON: json.RawMessage(`[
+			{"question":"2+2?","type":"multiple_choice","options":["3","4","5","6"],"correct_index":0,"difficulty":"easy"},
+			{"question":"The sun is a star.","type":"true_false","options":["True","False"],"correct_index":0,"difficulty":"easy"}
+		]`),
+	}
+}
+
+func makeQuizQuestionRequest(method, quizID, index, body string, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", quizID)
+	rctx.URLParams.Add("index", index)
+	req := httptest.NewRequest(method, "/api/v1/quizzes/"+quizID+"/questions/"+index, strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestQuizQuestions_Report(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubQuizQuestionRepo{quiz: newQuestionTestQuiz(userID)}
+	h := NewQuizQuestionHandler(repo)
+
+	rr := httptest.NewRecorder()
+	body := `{"reason":"incorrect_answer","suggested_fix":"  The answer is 4. "}`
+	h.Report(rr, makeQuizQuestionRequest(http.MethodPost, repo.quiz.ID.String(), "0", body, userID))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(repo.reports) != 1 {
+		t.Fatalf("expected one report, got %d", len(repo.reports))
+	}
+	got := repo.reports[0]
+	if got.QuestionIndex != 0 || got.Reason != "incorrect_answer" || got.SuggestedFix == nil || *got.SuggestedFix != "The answer is 4." {
+		t.Fatalf("unexpected report %+v", got)
+	}
+}
+
+func TestQuizQuestions_ReportValidation(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubQuizQuestionRepo{quiz: newQuestionTestQuiz(userID)}
+	h := NewQuizQuestionHandler(repo)
+
+	tests := []struct {
+		name  string
+		index string
+		body  string
+	}{
+		{"unknown reason", "0", `{"reason":"boring"}`},
+		{"index out of range", "2", `{"reason":"typo"}`},
+		{"negative index", "-1", `{"reason":"typo"}`},
+		{"non-numeric index", "first", `{"reason":"typo"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.Report(rr, makeQuizQuestionRequest(http.MethodPost, repo.quiz.ID.String(), tt.index, tt.body, userID))
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+			}
+		})
+	}
+	if len(repo.reports) != 0 {
+		t.Fatalf("expected no reports, got %d", len(repo.reports))
+	}
+}
+
+func TestQuizQuestions_UpdateRegradesAttempts(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubQuizQuestionRepo{quiz: newQuestionTestQuiz(userID)}
+	attemptID := uuid.New()
+	repo.attempts = []*models.QuizAttempt{{
+		ID:          attemptID,
+		AnswersJSON: json.RawMessage(`[{"question_index":0,"answer_index":1},{"question_index":1,"answer_index":0}]`),
+	}}
+	h := NewQuizQuestionHandler(repo)
+
+	body := `{"question":"What is 2+2?","type":"multiple_choice","options":["3","4","5","6"],"correct_index":1,"difficulty":"easy"}`
+	rr := httptest.NewRecorder()
+	h.Update(rr, makeQuizQuestionRequest(http.MethodPut, repo.quiz.ID.String(), "0", body, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal(repo.updatedJSON, &questions); err != nil {
+		t.Fatalf("invalid stored questions: %v", err)
+	}
+	if len(questions) != 2 || questions[0].Question != "What is 2+2?" || questions[0].CorrectIndex != 1 {
+		t.Fatalf("expected question 0 to be replaced, got %+v", questions)
+	}
+	if len(repo.updatedScores) != 1 {
+		t.Fatalf("expected one regraded attempt, got %d", len(repo.updatedScores))
+	}
+	if s := repo.updatedScores[0]; s.AttemptID != attemptID || s.CorrectCount != 2 || s.ScorePercent != 100 {
+		t.Fatalf("expected the attempt to be regraded to 2/2, got %+v", s)
+	}
+}
+
+func TestQuizQuestions_UpdateRejectsTypeChange(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubQuizQuestionRepo{quiz: newQuestionTestQuiz(userID)}
+	h := NewQuizQuestionHandler(repo)
+
+	body := `{"question":"2+2 is 4.","type":"true_false","options":["True","False"],"correct_index":0}`
+	rr := httptest.NewRecorder()
+	h.Update(rr, makeQuizQuestionRequest(http.MethodPut, repo.quiz.ID.String(), "0", body, userID))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if repo.updatedJSON != nil {
+		t.Fatalf("expected the quiz not to be updated")
+	}
+}
+
+func TestQuizQuestions_UpdateForbiddenForNonOwner(t *testing.T) {
+	repo := &stubQuizQuestionRepo{quiz: newQuestionTestQuiz(uuid.New())}
+	h := NewQuizQuestionHandler(repo)
+
+	body := `{"question":"What is 2+2?","type":"multiple_choice","options":["3","4","5","6"],"correct_index":1}`
+	rr := httptest.NewRecorder()
+	h.Update(rr, makeQuizQuestionRequest(http.MethodPut, repo.quiz.ID.String(), "0", body, uuid.New()))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+	if repo.updatedJSON != nil {
+		t.Fatalf("expected the quiz not to be updated")
+	}
+}
+
+func TestReviewPositions(t *testing.T) {
+	attempt := &models.QuizAttempt{QuestionOrder: []int{2, 0, 1}}
+	if got := reviewPositions(attempt, []int{0, 2}); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Fatalf("expected positions [0 1], got %v", got)
+	}
+	if got := reviewPositions(&models.QuizAttempt{}, []int{1}); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected generation order to be kept, got %v", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 
 	"lectura-backend/internal/middleware"
@@ -30,8 +31,8 @@ func (s *stubQuizRepoForGenerate) Create(ctx context.Context, q *models.Quiz) er
 	return nil
 }
 
-func (s *stubQuizRepoForGenerate) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Quiz, error) {
-	return nil, nil
+func (s *stubQuizRepoForGenerate) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Quiz, int, error) {
+	return nil, 0, nil
 }
 
 func (s *stubQuizRepoForGenerate) GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
@@ -42,6 +43,14 @@ func (s *stubQuizRepoForGenerate) Delete(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+func (s *stubQuizRepoForGenerate) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *stubQuizRepoForGenerate) RecordHintUsage(ctx context.Context, attemptID uuid.UUID, questionIndex int) error {
+	return nil
+}
+
 func (s *stubQuizRepoForGenerate) ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	return nil
 }
@@ -58,11 +67,27 @@ func (s *stubQuizRepoForGenerate) GetAttemptByID(ctx context.Context, id uuid.UU
 	return nil, context.Canceled
 }
 
+func (s *stubQuizRepoForGenerate) GetActiveAttempt(ctx context.Context, quizID, userID uuid.UUID, since time.Time) (*models.QuizAttempt, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (s *stubQuizRepoForGenerate) ListAttemptsByUser(ctx context.Context, quizID, userID uuid.UUID) ([]*models.QuizAttempt, error) {
+	return nil, nil
+}
+
 func (s *stubQuizRepoForGenerate) SaveProgress(ctx context.Context, attemptID uuid.UUID, answers json.RawMessage) error {
 	return nil
 }
 
-func (s *stubQuizRepoForGenerate) SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage) error {
+func (s *stubQuizRepoForGenerate) SaveEssayAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, answer string) error {
+	return nil
+}
+
+func (s *stubQuizRepoForGenerate) SaveMatchingAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, matches []int) error {
+	return nil
+}
+
+func (s *stubQuizRepoForGenerate) SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage, essayGrades map[int]models.EssayGrade) error {
 	return nil
 }
 
@@ -114,20 +139,33 @@ func (f *quizFakeQueuePusher) LPush(ctx context.Context, key string, values ...i
 }
 
 type stubQuizRepoForMutations struct {
-	quiz            *models.Quiz
-	attempt         *models.QuizAttempt
-	savedProgress   bool
-	submitted       bool
-	savedAttemptID  uuid.UUID
-	submitAttemptID uuid.UUID
+	quiz                 *models.Quiz
+	attempt              *models.QuizAttempt
+	activeAttempt        *models.QuizAttempt
+	activeSince          time.Time
+	attemptsCreated      int
+	createdAttempt       *models.QuizAttempt
+	savedProgress        bool
+	submitted            bool
+	savedAttemptID       uuid.UUID
+	submitAttemptID      uuid.UUID
+	submittedScore       float64
+	submittedEssayGrades map[int]models.EssayGrade
+	savedEssayIndex      int
+	savedEssayAnswer     string
+	savedMatchingIndex   int
+	savedMatches         []int
+	hintsRecorded        []int
+	attempts             []*models.QuizAttempt
+	attemptsListedFor    uuid.UUID
 }
 
 func (s *stubQuizRepoForMutations) Create(ctx context.Context, q *models.Quiz) error {
 	return nil
 }
 
-func (s *stubQuizRepoForMutations) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Quiz, error) {
-	return nil, nil
+func (s *stubQuizRepoForMutations) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Quiz, int, error) {
+	return nil, 0, nil
 }
 
 func (s *stubQuizRepoForMutations) GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
@@ -141,6 +179,15 @@ func (s *stubQuizRepoForMutations) Delete(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
+func (s *stubQuizRepoForMutations) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *stubQuizRepoForMutations) RecordHintUsage(ctx context.Context, attemptID uuid.UUID, questionIndex int) error {
+	s.hintsRecorded = append(s.hintsRecorded, questionIndex)
+	return nil
+}
+
 func (s *stubQuizRepoForMutations) ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	return nil
 }
@@ -150,6 +197,8 @@ func (s *stubQuizRepoForMutations) TouchLastAccessed(ctx context.Context, id uui
 }
 
 func (s *stubQuizRepoForMutations) CreateAttempt(ctx context.Context, a *models.QuizAttempt) error {
+	s.attemptsCreated++
+	s.createdAttempt = a
 	if a.ID == uuid.Nil {
 		a.ID = uuid.New()
 	}
@@ -166,15 +215,46 @@ func (s *stubQuizRepoForMutations) GetAttemptByID(ctx context.Context, id uuid.U
 	return s.attempt, nil
 }
 
+func (s *stubQuizRepoForMutations) GetActiveAttempt(ctx context.Context, quizID, userID uuid.UUID, since time.Time) (*models.QuizAttempt, error) {
+	s.activeSince = since
+	if s.activeAttempt == nil || s.activeAttempt.QuizID != quizID || s.activeAttempt.UserID != userID {
+		return nil, pgx.ErrNoRows
+	}
+	return s.activeAttempt, nil
+}
+
+func (s *stubQuizRepoForMutations) ListAttemptsByUser(ctx context.Context, quizID, userID uuid.UUID) ([]*models.QuizAttempt, error) {
+	s.attemptsListedFor = userID
+	return s.attempts, nil
+}
+
 func (s *stubQuizRepoForMutations) SaveProgress(ctx context.Context, attemptID uuid.UUID, answers json.RawMessage) error {
 	s.savedProgress = true
 	s.savedAttemptID = attemptID
 	return nil
 }
 
-func (s *stubQuizRepoForMutations) SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage) error {
+func (s *stubQuizRepoForMutations) SaveEssayAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, answer string) error {
+	s.savedProgress = true
+	s.savedAttemptID = attemptID
+	s.savedEssayIndex = questionIndex
+	s.savedEssayAnswer = answer
+	return nil
+}
+
+func (s *stubQuizRepoForMutations) SaveMatchingAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, matches []int) error {
+	s.savedProgress = true
+	s.savedAttemptID = attemptID
+	s.savedMatchingIndex = questionIndex
+	s.savedMatches = matches
+	return nil
+}
+
+func (s *stubQuizRepoForMutations) SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage, essayGrades map[int]models.EssayGrade) error {
 	s.submitted = true
 	s.submitAttemptID = attemptID
+	s.submittedScore = score
+	s.submittedEssayGrades = essayGrades
 	return nil
 }
 
@@ -328,6 +408,126 @@ func TestStartAttempt_AllowsOwnedQuiz(t *testing.T) {
 	}
 }
 
+func makeQuizRequest(method, path string, quizID, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", quizID.String())
+	req := httptest.NewRequest(method, path, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestStartAttempt_ResumeReusesActiveAttempt(t *testing.T) {
+	userID := uuid.New()
+	quizID := uuid.New()
+	active := &models.QuizAttempt{ID: uuid.New(), QuizID: quizID, UserID: userID, StartedAt: time.Now().Add(-time.Hour)}
+
+	repo := &stubQuizRepoForMutations{
+		quiz:          &models.Quiz{ID: quizID, UserID: userID},
+		activeAttempt: active,
+	}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.StartAttempt(rr, makeQuizRequest(http.MethodPost, "/api/v1/quizzes/"+quizID.String()+"/start?resume=true", quizID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.attemptsCreated != 0 {
+		t.Fatalf("expected no new attempt, got %d", repo.attemptsCreated)
+	}
+	var body struct {
+		AttemptID uuid.UUID `json:"attempt_id"`
+		Resumed   bool      `json:"resumed"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.AttemptID != active.ID || !body.Resumed {
+		t.Fatalf("expected resumed attempt %s, got %+v", active.ID, body)
+	}
+}
+
+func TestStartAttempt_WithoutResumeAlwaysCreates(t *testing.T) {
+	userID := uuid.New()
+	quizID := uuid.New()
+
+	repo := &stubQuizRepoForMutations{
+		quiz:          &models.Quiz{ID: quizID, UserID: userID},
+		activeAttempt: &models.QuizAttempt{ID: uuid.New(), QuizID: quizID, UserID: userID},
+	}
+	h := &QuizHandler{quizRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.StartAttempt(rr, makeQuizRequest(http.MethodPost, "/api/v1/quizzes/"+quizID.String()+"/start", quizID, userID))
+
+	if rr.Code != http.StatusCreated || repo.attemptsCreated != 1 {
+		t.Fatalf("expected a new attempt, got status %d and %d created", rr.Code, repo.attemptsCreated)
+	}
+}
+
+func TestGetActiveAttempt(t *testing.T) {
+	userID := uuid.New()
+	quizID := uuid.New()
+
+	tests := []struct {
+		name   string
+		quiz   *models.Quiz
+		active *models.QuizAttempt
+		want   int
+	}{
+		{
+			name:   "returns saved attempt",
+			quiz:   &models.Quiz{ID: quizID, UserID: userID},
+			active: &models.QuizAttempt{ID: uuid.New(), QuizID: quizID, UserID: userID, AnswersJSON: json.RawMessage(`[{"question_index":0,"answer_index":2}]`)},
+			want:   http.StatusOK,
+		},
+		{
+			name: "nothing to resume",
+			quiz: &models.Quiz{ID: quizID, UserID: userID},
+			want: http.StatusNoContent,
+		},
+		{
+			name: "foreign quiz",
+			quiz: &models.Quiz{ID: quizID, UserID: uuid.New()},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "unknown quiz",
+			want: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubQuizRepoForMutations{quiz: tt.quiz, activeAttempt: tt.active}
+			h := &QuizHandler{quizRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.GetActiveAttempt(rr, makeQuizRequest(http.MethodGet, "/api/v1/quizzes/"+quizID.String()+"/active-attempt", quizID, userID))
+
+			if rr.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rr.Code)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			if since := time.Since(repo.activeSince); since < activeAttemptWindow-time.Minute || since > activeAttemptWindow+time.Minute {
+				t.Fatalf("expected freshness window of %s, got %s", activeAttemptWindow, since)
+			}
+			var body struct {
+				Attempt models.QuizAttempt `json:"attempt"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Attempt.ID != tt.active.ID || !strings.Contains(string(body.Attempt.AnswersJSON), `"answer_index":2`) {
+				t.Fatalf("unexpected attempt: %+v", body.Attempt)
+			}
+		})
+	}
+}
+
 func TestGetAttempt_DeniesWhenQuizOwnershipMismatch(t *testing.T) {
 	userID := uuid.New()
 	ownerID := uuid.New()
@@ -373,8 +573,8 @@ func TestQuizGenerate_QueueFailure_MarksJobFailed(t *testing.T) {
 
 	h.Generate(rr, req)
 
-	if rr.Code != http.StatusInternalServerError {
-		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
 	}
 	if code := errorCodeFromBody(t, rr); code != "QUEUE_ERROR" {
 		t.Fatalf("expected QUEUE_ERROR, got %q", code)
@@ -430,3 +630,55 @@ func TestQuizGenerate_QueueSuccess_Returns202(t *testing.T) {
 		t.Fatalf("expected quiz_id in response")
 	}
 }
+
+func TestQuizGenerate_TooManyQuestions_ReturnsFieldError(t *testing.T) {
+	userID := uuid.New()
+	quizRepo := &stubQuizRepoForGenerate{}
+	h := &QuizHandler{quizRepo: quizRepo}
+
+	body := `{"summary_id":"` + uuid.New().String() + `","title":"Quiz","num_questions":10000,"difficulty":"extreme"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/quizzes/generate", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	rr := httptest.NewRecorder()
+
+	h.Generate(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	var payload models.ErrorResponse
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if payload.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("expected VALIDATION_ERROR, got %q", payload.Error.Code)
+	}
+	if payload.Error.Fields["num_questions"] == "" || payload.Error.Fields["difficulty"] == "" {
+		t.Fatalf("expected num_questions and difficulty field errors, got %#v", payload.Error.Fields)
+	}
+	if len(quizRepo.created) != 0 {
+		t.Fatalf("quiz should not be created for invalid config")
+	}
+}
+
+func TestParseListPage(t *testing.T) {
+	cases := []struct {
+		query         string
+		limit, offset int
+	}{
+		{"", defaultListPageSize, 0},
+		{"?limit=10&offset=30", 10, 30},
+		{"?limit=500", maxListPageSize, 0},
+		{"?limit=-1&offset=-5", defaultListPageSize, 0},
+		{"?limit=abc", defaultListPageSize, 0},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/quizzes"+tc.query, nil)
+		limit, offset := parseListPage(req)
+		if limit != tc.limit || offset != tc.offset {
+			t.Fatalf("%q: expected limit=%d offset=%d, got limit=%d offset=%d", tc.query, tc.limit, tc.offset, limit, offset)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+const fillBlankAndMatchingQuestions = `[
+	{"question":"What is 2+2?","type":"multiple_choice","options":["3","4","5","6"],"correct_index":1,"difficulty":"easy","topic":"t"},
+	{"question":"The powerhouse of the cell is the _____.","type":"fill_blank","options":[],"accepted_answers":["mitochondria","mitochondrion"],"difficulty":"easy","topic":"t"},
+	{"question":"Match each organelle to its role.","type":"matching","options":[],"pairs":[{"prompt":"Nucleus","answer":"Holds DNA"},{"prompt":"Ribosome","answer":"Builds proteins"},{"prompt":"Lysosome","answer":"Digests waste"}],"difficulty":"easy","topic":"t"}
+]`
+
+func newTypesTestRepo(userID uuid.UUID, textAnswers map[int]string, matches map[int][]int) (*stubQuizRepoForMutations, uuid.UUID) {
+	attemptID := uuid.New()
+	quizID := uuid.New()
+	return &stubQuizRepoForMutations{
+		attempt: &models.QuizAttempt{
+			ID:              attemptID,
+			QuizID:          quizID,
+			UserID:          userID,
+			StartedAt:       time.Now(),
+			AnswersJSON:     json.RawMessage(`[{"question_index":0,"answer_index":1},{"question_index":1,"answer_index":0},{"question_index":2,"answer_index":0}]`),
+			EssayAnswers:    textAnswers,
+			MatchingAnswers: matches,
+		},
+		quiz: &models.Quiz{
+			ID:            quizID,
+			UserID:        userID,
+			ConfigJSON:    json.RawMessage(`{}`),
+			QuestionsJSON: json.RawMessage(fillBlankAndMatchingQuestions),
+		},
+	}, attemptID
+}
+
+func TestSubmitAttempt_GradesFillBlankAndMatching(t *testing.T) {
+	tests := []struct {
+		name        string
+		textAnswers map[int]string
+		matches     map[int][]int
+		wantCorrect float64
+	}{
+		{"both right", map[int]string{1: "  Mitochondrion "}, map[int][]int{2: {0, 1, 2}}, 3},
+		{"wrong blank", map[int]string{1: "nucleus"}, map[int][]int{2: {0, 1, 2}}, 2},
+		{"swapped matches", map[int]string{1: "MITOCHONDRIA"}, map[int][]int{2: {1, 0, 2}}, 2},
+		{"unanswered", nil, map[int][]int{2: {0, 1, -1}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			repo, attemptID := newTypesTestRepo(userID, tt.textAnswers, tt.matches)
+			h := &QuizHandler{quizRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.SubmitAttempt(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/submit", attemptID, userID, `{}`))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+			}
+			// An answer_index on a fill_blank or matching question earns nothing.
+			if want := tt.wantCorrect / 3 * 100; repo.submittedScore < want-0.1 || repo.submittedScore > want+0.1 {
+				t.Fatalf("expected score %v, got %v", want, repo.submittedScore)
+			}
+		})
+	}
+}
+
+func TestSaveProgress_FillBlankAndMatching(t *testing.T) {
+	userID := uuid.New()
+	repo, attemptID := newTypesTestRepo(userID, nil, nil)
+	h := &QuizHandler{quizRepo: repo}
+	path := "/api/v1/quiz-attempts/" + attemptID.String() + "/save-progress"
+
+	rr := httptest.NewRecorder()
+	h.SaveProgress(rr, makeAttemptRequest(http.MethodPost, path, attemptID, userID, `{"question_index":1,"answer_text":"mitochondria"}`))
+	if rr.Code != http.StatusNoContent || repo.savedEssayIndex != 1 || repo.savedEssayAnswer != "mitochondria" {
+		t.Fatalf("expected the blank to be saved, got %d %q: %s", repo.savedEssayIndex, repo.savedEssayAnswer, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.SaveProgress(rr, makeAttemptRequest(http.MethodPost, path, attemptID, userID, `{"question_index":2,"matches":[2,-1,0]}`))
+	if rr.Code != http.StatusNoContent || repo.savedMatchingIndex != 2 || len(repo.savedMatches) != 3 {
+		t.Fatalf("expected the matches to be saved, got %d %v: %s", repo.savedMatchingIndex, repo.savedMatches, rr.Body.String())
+	}
+}
+
+func TestSaveProgress_MatchingValidation(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not a matching question", `{"question_index":1,"matches":[0,1,2]}`},
+		{"too few matches", `{"question_index":2,"matches":[0,1]}`},
+		{"answer used twice", `{"question_index":2,"matches":[0,0,1]}`},
+		{"answer out of range", `{"question_index":2,"matches":[0,1,3]}`},
+		{"text on a matching question", `{"question_index":2,"answer_text":"Holds DNA"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, attemptID := newTypesTestRepo(userID, nil, nil)
+			h := &QuizHandler{quizRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.SaveProgress(rr, makeAttemptRequest(http.MethodPost, "/api/v1/quiz-attempts/"+attemptID.String()+"/save-progress", attemptID, userID, tt.body))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+			if repo.savedProgress {
+				t.Fatalf("expected nothing to be saved")
+			}
+		})
+	}
+}
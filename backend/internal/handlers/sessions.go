@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type sessionService interface {
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+}
+
+// SessionHandler lists and revokes the devices a user is signed in on.
+type SessionHandler struct {
+	sessions sessionService
+}
+
+func NewSessionHandler(authService *services.AuthService) *SessionHandler {
+	return &SessionHandler{sessions: authService}
+}
+
+// List returns the user's active sessions, most recently used first.
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !requireSession(w, r) {
+		return
+	}
+
+	sessions, err := h.sessions.ListSessions(r.Context(), middleware.GetUserID(r.Context()))
+	if err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"sessions": sessions})
+}
+
+// Delete signs one device out. Its access token keeps working until it
+// expires, at most 15 minutes.
+func (h *SessionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !requireSession(w, r) {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid session ID", r))
+		return
+	}
+
+	if err := h.sessions.RevokeSession(r.Context(), middleware.GetUserID(r.Context()), id); err != nil {
+		handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type stubSessionService struct {
+	sessions       []models.Session
+	revokedUserID  uuid.UUID
+	revokedSession uuid.UUID
+}
+
+func (s *stubSessionService) ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	return s.sessions, nil
+}
+
+func (s *stubSessionService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	for _, session := range s.sessions {
+		if session.ID == sessionID {
+			s.revokedUserID = userID
+			s.revokedSession = sessionID
+			return nil
+		}
+	}
+	return &services.NotFoundError{Message: "Session not found"}
+}
+
+func deleteSessionRequest(id string, userID uuid.UUID) *http.Request {
+	req := apiKeyRequest(http.MethodDelete, "/api/v1/user/sessions/"+id, "", userID, "")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestSessionList(t *testing.T) {
+	now := time.Now()
+	svc := &stubSessionService{sessions: []models.Session{
+		{ID: uuid.New(), UserAgent: "Firefox", IP: "203.0.113.7", CreatedAt: now, LastUsedAt: now},
+	}}
+	h := &SessionHandler{sessions: svc}
+
+	rr := httptest.NewRecorder()
+	h.List(rr, apiKeyRequest(http.MethodGet, "/api/v1/user/sessions", "", uuid.New(), ""))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var payload struct {
+		Sessions []models.Session `json:"sessions"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(payload.Sessions) != 1 || payload.Sessions[0].UserAgent != "Firefox" || payload.Sessions[0].IP != "203.0.113.7" {
+		t.Fatalf("unexpected sessions %+v", payload.Sessions)
+	}
+}
+
+func TestSessionList_RejectsAPIKeys(t *testing.T) {
+	h := &SessionHandler{sessions: &stubSessionService{}}
+
+	rr := httptest.NewRecorder()
+	h.List(rr, apiKeyRequest(http.MethodGet, "/api/v1/user/sessions", "", uuid.New(), models.APIKeyScopeWrite))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rr.Code)
+	}
+}
+
+func TestSessionDelete(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	svc := &stubSessionService{sessions: []models.Session{{ID: sessionID}}}
+	h := &SessionHandler{sessions: svc}
+
+	rr := httptest.NewRecorder()
+	h.Delete(rr, deleteSessionRequest("not-a-uuid", userID))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("invalid ID status = %d, want 400", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.Delete(rr, deleteSessionRequest(uuid.New().String(), userID))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown session status = %d, want 404", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.Delete(rr, deleteSessionRequest(sessionID.String(), userID))
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want 204: %s", rr.Code, rr.Body.String())
+	}
+	if svc.revokedUserID != userID || svc.revokedSession != sessionID {
+		t.Fatalf("expected session %s of user %s to be revoked, got %s of %s", sessionID, userID, svc.revokedSession, svc.revokedUserID)
+	}
+}
+
+func TestLogoutAll_RevokesEverySessionAndClearsCookie(t *testing.T) {
+	svc := &stubAuthServiceForCookies{}
+	h := &AuthHandler{authService: svc}
+	userID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout-all", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	rr := httptest.NewRecorder()
+	h.LogoutAll(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if svc.loggedOutAllUserID != userID {
+		t.Fatalf("expected every session of %s to be revoked, got %s", userID, svc.loggedOutAllUserID)
+	}
+	var cleared bool
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == refreshTokenCookieName && c.MaxAge < 0 {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Fatalf("expected the refresh cookie to be cleared")
+	}
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/repository"
+	"lectura-backend/internal/services"
+)
+
+// Import size caps. Merged decks can grow well past the generation limits,
+// so shared items are bounded separately.
+const (
+	maxSharedDeckCards     = 500
+	maxSharedQuizQuestions = 200
+	shareSlugBytes         = 8
+	maxShareSlugLength     = 64
+)
+
+type deckShareRepository interface {
+	GetDeckByID(ctx context.Context, id uuid.UUID) (*models.FlashcardDeck, error)
+	ShareDeck(ctx context.Context, id uuid.UUID, slug string) (*models.ShareLink, error)
+	UnshareDeck(ctx context.Context, id uuid.UUID) error
+	GetSharedDeck(ctx context.Context, slug string, maxCards int) (*models.SharedDeck, error)
+	ImportSharedDeck(ctx context.Context, slug string, userID uuid.UUID, maxCards int) (*models.FlashcardDeck, error)
+}
+
+type quizShareRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error)
+	ShareQuiz(ctx context.Context, id uuid.UUID, slug string) (*models.ShareLink, error)
+	UnshareQuiz(ctx context.Context, id uuid.UUID) error
+	GetSharedQuiz(ctx context.Context, slug string, maxQuestions int) (*models.SharedQuiz, error)
+	ImportSharedQuiz(ctx context.Context, slug string, userID uuid.UUID, maxQuestions int) (*models.Quiz, error)
+}
+
+// ShareHandler serves share links for decks and quizzes: owners create and
+// revoke them, anyone can preview, and signed-in users import a copy.
+type ShareHandler struct {
+	decks   deckShareRepository
+	quizzes quizShareRepository
+}
+
+func NewShareHandler(decks deckShareRepository, quizzes quizShareRepository) *ShareHandler {
+	return &ShareHandler{decks: decks, quizzes: quizzes}
+}
+
+// ShareDeck creates (or returns the existing) share link for a deck.
+func (h *ShareHandler) ShareDeck(w http.ResponseWriter, r *http.Request) {
+	deck, ok := h.ownedDeck(w, r)
+	if !ok {
+		return
+	}
+
+	slug, err := services.GenerateToken(shareSlugBytes)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to share deck", r))
+		return
+	}
+	link, err := h.decks.ShareDeck(r.Context(), deck.ID, slug)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to share deck", r))
+		return
+	}
+
+	link.Path = "/shared/decks/" + link.Slug
+	writeJSON(w, http.StatusOK, link)
+}
+
+// UnshareDeck revokes a deck's share link.
+func (h *ShareHandler) UnshareDeck(w http.ResponseWriter, r *http.Request) {
+	deck, ok := h.ownedDeck(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.decks.UnshareDeck(r.Context(), deck.ID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to unshare deck", r))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PreviewDeck shows a shared deck to anyone with the link.
+func (h *ShareHandler) PreviewDeck(w http.ResponseWriter, r *http.Request) {
+	slug, ok := shareSlug(w, r)
+	if !ok {
+		return
+	}
+
+	deck, err := h.decks.GetSharedDeck(r.Context(), slug, maxSharedDeckCards)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Shared deck not found", r))
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load shared deck", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deck)
+}
+
+// ImportDeck copies a shared deck into the caller's account.
+func (h *ShareHandler) ImportDeck(w http.ResponseWriter, r *http.Request) {
+	slug, ok := shareSlug(w, r)
+	if !ok {
+		return
+	}
+	userID := middleware.GetUserID(r.Context())
+
+	deck, err := h.decks.ImportSharedDeck(r.Context(), slug, userID, maxSharedDeckCards)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Shared deck not found", r))
+		return
+	case errors.Is(err, repository.ErrShareTooLarge):
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResp("TOO_LARGE", fmt.Sprintf("Shared decks with more than %d cards cannot be imported", maxSharedDeckCards), r))
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to import deck", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, deck)
+}
+
+// ShareQuiz creates (or returns the existing) share link for a quiz.
+func (h *ShareHandler) ShareQuiz(w http.ResponseWriter, r *http.Request) {
+	quiz, ok := h.ownedQuiz(w, r)
+	if !ok {
+		return
+	}
+
+	slug, err := services.GenerateToken(shareSlugBytes)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to share quiz", r))
+		return
+	}
+	link, err := h.quizzes.ShareQuiz(r.Context(), quiz.ID, slug)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to share quiz", r))
+		return
+	}
+
+	link.Path = "/shared/quizzes/" + link.Slug
+	writeJSON(w, http.StatusOK, link)
+}
+
+// UnshareQuiz revokes a quiz's share link.
+func (h *ShareHandler) UnshareQuiz(w http.ResponseWriter, r *http.Request) {
+	quiz, ok := h.ownedQuiz(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.quizzes.UnshareQuiz(r.Context(), quiz.ID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to unshare quiz", r))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PreviewQuiz shows a shared quiz, without answers, to anyone with the link.
+func (h *ShareHandler) PreviewQuiz(w http.ResponseWriter, r *http.Request) {
+	slug, ok := shareSlug(w, r)
+	if !ok {
+		return
+	}
+
+	quiz, err := h.quizzes.GetSharedQuiz(r.Context(), slug, maxSharedQuizQuestions)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Shared quiz not found", r))
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load shared quiz", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, quiz)
+}
+
+// ImportQuiz copies a shared quiz into the caller's account.
+func (h *ShareHandler) ImportQuiz(w http.ResponseWriter, r *http.Request) {
+	slug, ok := shareSlug(w, r)
+	if !ok {
+		return
+	}
+	userID := middleware.GetUserID(r.Context())
+
+	quiz, err := h.quizzes.ImportSharedQuiz(r.Context(), slug, userID, maxSharedQuizQuestions)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Shared quiz not found", r))
+		return
+	case errors.Is(err, repository.ErrShareTooLarge):
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResp("TOO_LARGE", fmt.Sprintf("Shared quizzes with more than %d questions cannot be imported", maxSharedQuizQuestions), r))
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to import quiz", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, quiz)
+}
+
+func (h *ShareHandler) ownedDeck(w http.ResponseWriter, r *http.Request) (*models.FlashcardDeck, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid deck ID", r))
+		return nil, false
+	}
+
+	deck, err := h.decks.GetDeckByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Deck not found", r))
+		return nil, false
+	}
+	if deck.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return nil, false
+	}
+	return deck, true
+}
+
+func (h *ShareHandler) ownedQuiz(w http.ResponseWriter, r *http.Request) (*models.Quiz, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid quiz ID", r))
+		return nil, false
+	}
+
+	quiz, err := h.quizzes.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Quiz not found", r))
+		return nil, false
+	}
+	if quiz.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return nil, false
+	}
+	return quiz, true
+}
+
+func shareSlug(w http.ResponseWriter, r *http.Request) (string, bool) {
+	slug := strings.TrimSpace(chi.URLParam(r, "slug"))
+	if slug == "" || len(slug) > maxShareSlugLength {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid share link", r))
+		return "", false
+	}
+	return slug, true
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/repository"
+)
+
+type stubDeckShareRepo struct {
+	deck      *models.FlashcardDeck
+	slug      string
+	shared    *models.SharedDeck
+	importErr error
+	imported  uuid.UUID
+	unshared  bool
+}
+
+func (s *stubDeckShareRepo) GetDeckByID(_ context.Context, id uuid.UUID) (*models.FlashcardDeck, error) {
+	if s.deck == nil || s.deck.ID != id {
+		return nil, pgx.ErrNoRows
+	}
+	return s.deck, nil
+}
+
+func (s *stubDeckShareRepo) ShareDeck(_ context.Context, _ uuid.UUID, slug string) (*models.ShareLink, error) {
+	if s.slug == "" {
+		s.slug = slug
+	}
+	return &models.ShareLink{Slug: s.slug, SharedAt: time.Now()}, nil
+}
+
+func (s *stubDeckShareRepo) UnshareDeck(context.Context, uuid.UUID) error {
+	s.unshared = true
+	return nil
+}
+
+func (s *stubDeckShareRepo) GetSharedDeck(_ context.Context, slug string, _ int) (*models.SharedDeck, error) {
+	if s.shared == nil || slug != s.slug {
+		return nil, pgx.ErrNoRows
+	}
+	return s.shared, nil
+}
+
+func (s *stubDeckShareRepo) ImportSharedDeck(_ context.Context, slug string, userID uuid.UUID, _ int) (*models.FlashcardDeck, error) {
+	if s.importErr != nil {
+		return nil, s.importErr
+	}
+	if slug != s.slug {
+		return nil, pgx.ErrNoRows
+	}
+	s.imported = userID
+	return &models.FlashcardDeck{ID: uuid.New(), UserID: userID, Title: s.deck.Title}, nil
+}
+
+type stubQuizShareRepo struct {
+	quiz   *models.Quiz
+	shared *models.SharedQuiz
+}
+
+func (s *stubQuizShareRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Quiz, error) {
+	if s.quiz == nil || s.quiz.ID != id {
+		return nil, pgx.ErrNoRows
+	}
+	return s.quiz, nil
+}
+
+func (s *stubQuizShareRepo) ShareQuiz(_ context.Context, _ uuid.UUID, slug string) (*models.ShareLink, error) {
+	return &models.ShareLink{Slug: slug, SharedAt: time.Now()}, nil
+}
+
+func (s *stubQuizShareRepo) UnshareQuiz(context.Context, uuid.UUID) error { return nil }
+
+func (s *stubQuizShareRepo) GetSharedQuiz(context.Context, string, int) (*models.SharedQuiz, error) {
+	if s.shared == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return s.shared, nil
+}
+
+func (s *stubQuizShareRepo) ImportSharedQuiz(context.Context, string, uuid.UUID, int) (*models.Quiz, error) {
+	return nil, repository.ErrShareTooLarge
+}
+
+func shareRequest(method, target string, params map[string]string, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if userID != uuid.Nil {
+		ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestShareDeck_OwnerGetsStableLink(t *testing.T) {
+	owner := uuid.New()
+	deck := &models.FlashcardDeck{ID: uuid.New(), UserID: owner, Title: "Cells"}
+	h := NewShareHandler(&stubDeckShareRepo{deck: deck}, &stubQuizShareRepo{})
+
+	var slugs []string
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		h.ShareDeck(rr, shareRequest(http.MethodPost, "/api/v1/flashcards/decks/x/share", map[string]string{"id": deck.ID.String()}, owner))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+		}
+		var link models.ShareLink
+		if err := json.Unmarshal(rr.Body.Bytes(), &link); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if link.Slug == "" || link.Path != "/shared/decks/"+link.Slug {
+			t.Fatalf("link = %+v, want slug and matching path", link)
+		}
+		slugs = append(slugs, link.Slug)
+	}
+	if slugs[0] != slugs[1] {
+		t.Fatalf("sharing twice gave %q and %q, want the same link", slugs[0], slugs[1])
+	}
+}
+
+func TestShareDeck_RejectsNonOwnerAndBadID(t *testing.T) {
+	deck := &models.FlashcardDeck{ID: uuid.New(), UserID: uuid.New()}
+	h := NewShareHandler(&stubDeckShareRepo{deck: deck}, &stubQuizShareRepo{})
+
+	rr := httptest.NewRecorder()
+	h.ShareDeck(rr, shareRequest(http.MethodPost, "/", map[string]string{"id": deck.ID.String()}, uuid.New()))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("non-owner status = %d, want 403", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ShareDeck(rr, shareRequest(http.MethodPost, "/", map[string]string{"id": "nope"}, deck.UserID))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("bad id status = %d, want 400", rr.Code)
+	}
+}
+
+func TestUnshareDeck_RevokesLink(t *testing.T) {
+	owner := uuid.New()
+	repo := &stubDeckShareRepo{deck: &models.FlashcardDeck{ID: uuid.New(), UserID: owner}}
+	h := NewShareHandler(repo, &stubQuizShareRepo{})
+
+	rr := httptest.NewRecorder()
+	h.UnshareDeck(rr, shareRequest(http.MethodDelete, "/", map[string]string{"id": repo.deck.ID.String()}, owner))
+	if rr.Code != http.StatusNoContent || !repo.unshared {
+		t.Fatalf("status = %d, unshared = %v; want 204 and true", rr.Code, repo.unshared)
+	}
+}
+
+func TestPreviewDeck_PublicAndNotFound(t *testing.T) {
+	repo := &stubDeckShareRepo{
+		slug:   "abc123",
+		shared: &models.SharedDeck{Title: "Cells", AuthorName: "Ada", CardCount: 1, Cards: []models.SharedCard{{Front: "Q", Back: "A"}}},
+	}
+	h := NewShareHandler(repo, &stubQuizShareRepo{})
+
+	rr := httptest.NewRecorder()
+	h.PreviewDeck(rr, shareRequest(http.MethodGet, "/", map[string]string{"slug": "abc123"}, uuid.Nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.PreviewDeck(rr, shareRequest(http.MethodGet, "/", map[string]string{"slug": "revoked"}, uuid.Nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("unknown slug status = %d, want 404", rr.Code)
+	}
+}
+
+func TestImportDeck_CopiesIntoCallerAccount(t *testing.T) {
+	repo := &stubDeckShareRepo{slug: "abc123", deck: &models.FlashcardDeck{ID: uuid.New(), UserID: uuid.New(), Title: "Cells"}}
+	h := NewShareHandler(repo, &stubQuizShareRepo{})
+	caller := uuid.New()
+
+	rr := httptest.NewRecorder()
+	h.ImportDeck(rr, shareRequest(http.MethodPost, "/", map[string]string{"slug": "abc123"}, caller))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rr.Code, rr.Body.String())
+	}
+	if repo.imported != caller {
+		t.Fatalf("imported into %s, want caller %s", repo.imported, caller)
+	}
+}
+
+func TestImportQuiz_TooLarge(t *testing.T) {
+	h := NewShareHandler(&stubDeckShareRepo{}, &stubQuizShareRepo{})
+
+	rr := httptest.NewRecorder()
+	h.ImportQuiz(rr, shareRequest(http.MethodPost, "/", map[string]string{"slug": "abc123"}, uuid.New()))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413", rr.Code)
+	}
+	if code := errorCodeFromBody(t, rr); code != "TOO_LARGE" {
+		t.Fatalf("code = %q, want TOO_LARGE", code)
+	}
+}
+
+func TestPreviewQuiz_NotFound(t *testing.T) {
+	h := NewShareHandler(&stubDeckShareRepo{}, &stubQuizShareRepo{})
+
+	rr := httptest.NewRecorder()
+	h.PreviewQuiz(rr, shareRequest(http.MethodGet, "/", map[string]string{"slug": "abc123"}, uuid.Nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rr.Code)
+	}
+}
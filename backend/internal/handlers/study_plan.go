@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/repository"
+	"lectura-backend/internal/services"
+)
+
+const (
+	maxStudyPlanSummaries        = 20
+	maxStudyPlanDays             = 90
+	defaultStudyPlanDailyMinutes = 60
+	minStudyPlanDailyMinutes     = 10
+	maxStudyPlanDailyMinutes     = 480
+	studyPlanTargetDateLayout    = "2006-01-02"
+	studyPlanAIOrderingTimeout   = 30 * time.Second
+)
+
+type studyPlanRepository interface {
+	PlanSummaries(ctx context.Context, ids []uuid.UUID) ([]models.StudyPlanSummary, error)
+	DueCardForecast(ctx context.Context, userID uuid.UUID, summaryIDs []uuid.UUID, from, until time.Time) ([]models.DueCardForecast, error)
+	Create(ctx context.Context, plan *models.StudyPlan) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.StudyPlan, error)
+	SetItemCompleted(ctx context.Context, userID, planID, itemID uuid.UUID, completed bool) (*models.StudyPlanItem, error)
+}
+
+type studyTopicOrderer interface {
+	OrderStudyTopics(ctx context.Context, summaries []models.StudyPlanSummary) ([]models.StudyPlanSummary, error)
+}
+
+type StudyPlanHandler struct {
+	repo    studyPlanRepository
+	orderer studyTopicOrderer
+	now     func() time.Time
+}
+
+func NewStudyPlanHandler(repo *repository.StudyPlanRepo, geminiService *services.GeminiService) *StudyPlanHandler {
+	return &StudyPlanHandler{repo: repo, orderer: geminiService, now: time.Now}
+}
+
+// Generate builds a day-by-day plan for reviewing the given summaries before
+// the target date, scheduling flashcard reviews on their SM-2 due dates.
+func (h *StudyPlanHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	var req models.GenerateStudyPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	today := h.now().UTC().Truncate(24 * time.Hour)
+	if req.DailyMinutes == 0 {
+		req.DailyMinutes = defaultStudyPlanDailyMinutes
+	}
+
+	fields := map[string]string{}
+	summaryIDs := uniqueUUIDs(req.SummaryIDs)
+	if len(summaryIDs) == 0 {
+		fields["summary_ids"] = "At least one summary is required"
+	} else if len(summaryIDs) > maxStudyPlanSummaries {
+		fields["summary_ids"] = fmt.Sprintf("A plan can cover at most %d summaries", maxStudyPlanSummaries)
+	}
+	targetDate, err := time.Parse(studyPlanTargetDateLayout, req.TargetDate)
+	switch {
+	case err != nil:
+		fields["target_date"] = "Target date must be a date in YYYY-MM-DD format"
+	case targetDate.Before(today):
+		fields["target_date"] = "Target date must not be in the past"
+	case targetDate.After(today.AddDate(0, 0, maxStudyPlanDays-1)):
+		fields["target_date"] = fmt.Sprintf("Target date must be within %d days", maxStudyPlanDays)
+	}
+	if req.DailyMinutes < minStudyPlanDailyMinutes || req.DailyMinutes > maxStudyPlanDailyMinutes {
+		fields["daily_minutes"] = fmt.Sprintf("Daily minutes must be between %d and %d", minStudyPlanDailyMinutes, maxStudyPlanDailyMinutes)
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	found, err := h.repo.PlanSummaries(r.Context(), summaryIDs)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load summaries", r))
+		return
+	}
+	byID := make(map[uuid.UUID]models.StudyPlanSummary, len(found))
+	for _, s := range found {
+		byID[s.SummaryID] = s
+	}
+	summaries := make([]models.StudyPlanSummary, 0, len(summaryIDs))
+	for _, id := range summaryIDs {
+		s, ok := byID[id]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+			return
+		}
+		if s.UserID != userID {
+			writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+			return
+		}
+		summaries = append(summaries, s)
+	}
+
+	aiOrdered := false
+	if req.AIOrdering && len(summaries) > 1 {
+		ctx, cancel := context.WithTimeout(r.Context(), studyPlanAIOrderingTimeout)
+		ordered, err := h.orderer.OrderStudyTopics(ctx, summaries)
+		cancel()
+		if err != nil {
+			// Ordering is an enhancement; the user's order still makes a plan.
+			log.Printf("study plan: AI ordering failed, keeping request order: %v", err)
+		} else {
+			summaries = ordered
+			aiOrdered = true
+		}
+	}
+
+	dueCards, err := h.repo.DueCardForecast(r.Context(), userID, summaryIDs, today, targetDate)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to forecast flashcard reviews", r))
+		return
+	}
+
+	plan := &models.StudyPlan{
+		UserID:       userID,
+		StartDate:    today,
+		TargetDate:   targetDate,
+		DailyMinutes: req.DailyMinutes,
+		AIOrdered:    aiOrdered,
+		Items: services.BuildStudyPlanItems(services.StudyPlanInput{
+			StartDate:    today,
+			TargetDate:   targetDate,
+			DailyMinutes: req.DailyMinutes,
+			Summaries:    summaries,
+			DueCards:     dueCards,
+		}),
+	}
+	for _, s := range summaries {
+		plan.SummaryIDs = append(plan.SummaryIDs, s.SummaryID)
+	}
+
+	if err := h.repo.Create(r.Context(), plan); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to save study plan", r))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, plan)
+}
+
+func (h *StudyPlanHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	planID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid study plan ID", r))
+		return
+	}
+
+	plan, err := h.repo.GetByID(r.Context(), planID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Study plan not found", r))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch study plan", r))
+		return
+	}
+	if plan.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// CompleteItem marks one task of a plan as done.
+func (h *StudyPlanHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
+	h.setItemCompleted(w, r, true)
+}
+
+// UncompleteItem marks a task as not done again.
+func (h *StudyPlanHandler) UncompleteItem(w http.ResponseWriter, r *http.Request) {
+	h.setItemCompleted(w, r, false)
+}
+
+func (h *StudyPlanHandler) setItemCompleted(w http.ResponseWriter, r *http.Request, completed bool) {
+	userID := middleware.GetUserID(r.Context())
+	planID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid study plan ID", r))
+		return
+	}
+	itemID, err := uuid.Parse(chi.URLParam(r, "itemId"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid item ID", r))
+		return
+	}
+
+	item, err := h.repo.SetItemCompleted(r.Context(), userID, planID, itemID, completed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Study plan item not found", r))
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update study plan item", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubStudyPlanRepo struct {
+	summaries []models.StudyPlanSummary
+	forecast  []models.DueCardForecast
+	plan      *models.StudyPlan
+	getErr    error
+
+	created *models.StudyPlan
+
+	itemErr       error
+	itemCompleted *bool
+	itemUserID    uuid.UUID
+}
+
+func (s *stubStudyPlanRepo) PlanSummaries(ctx context.Context, ids []uuid.UUID) ([]models.StudyPlanSummary, error) {
+	return s.summaries, nil
+}
+
+func (s *stubStudyPlanRepo) DueCardForecast(ctx context.Context, userID uuid.UUID, summaryIDs []uuid.UUID, from, until time.Time) ([]models.DueCardForecast, error) {
+	return s.forecast, nil
+}
+
+func (s *stubStudyPlanRepo) Create(ctx context.Context, plan *models.StudyPlan) error {
+	plan.ID = uuid.New()
+	s.created = plan
+	return nil
+}
+
+func (s *stubStudyPlanRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.StudyPlan, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	return s.plan, nil
+}
+
+func (s *stubStudyPlanRepo) SetItemCompleted(ctx context.Context, userID, planID, itemID uuid.UUID, completed bool) (*models.StudyPlanItem, error) {
+	s.itemCompleted = &completed
+	s.itemUserID = userID
+	if s.itemErr != nil {
+		return nil, s.itemErr
+	}
+	item := &models.StudyPlanItem{ID: itemID, PlanID: planID}
+	if completed {
+		now := time.Now()
+		item.CompletedAt = &now
+	}
+	return item, nil
+}
+
+type stubStudyTopicOrderer struct {
+	err   error
+	calls int
+}
+
+func (s *stubStudyTopicOrderer) OrderStudyTopics(ctx context.Context, summaries []models.StudyPlanSummary) ([]models.StudyPlanSummary, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	reversed := make([]models.StudyPlanSummary, 0, len(summaries))
+	for i := len(summaries) - 1; i >= 0; i-- {
+		reversed = append(reversed, summaries[i])
+	}
+	return reversed, nil
+}
+
+var studyPlanTestNow = time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+func newTestStudyPlanHandler(repo *stubStudyPlanRepo, orderer *stubStudyTopicOrderer) *StudyPlanHandler {
+	return &StudyPlanHandler{repo: repo, orderer: orderer, now: func() time.Time { return studyPlanTestNow }}
+}
+
+func makeStudyPlanReq(t *testing.T, method string, userID uuid.UUID, params map[string]string, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(method, "/api/v1/study-plan", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+	if len(params) > 0 {
+		rctx := chi.NewRouteContext()
+		for k, v := range params {
+			rctx.URLParams.Add(k, v)
+		}
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestGenerateStudyPlan_BuildsAndSavesPlan(t *testing.T) {
+	userID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	repo := &stubStudyPlanRepo{
+		summaries: []models.StudyPlanSummary{
+			{SummaryID: second, UserID: userID, Title: "Genetics", WordCount: 800},
+			{SummaryID: first, UserID: userID, Title: "Cells", WordCount: 1200},
+		},
+		forecast: []models.DueCardForecast{{DeckID: uuid.New(), DeckTitle: "Cells", Day: studyPlanTestNow, Count: 4}},
+	}
+	h := newTestStudyPlanHandler(repo, &stubStudyTopicOrderer{})
+
+	body := `{"summary_ids":["` + first.String() + `","` + second.String() + `","` + first.String() + `"],"target_date":"2026-03-09"}`
+	rr := httptest.NewRecorder()
+	h.Generate(rr, makeStudyPlanReq(t, http.MethodPost, userID, nil, body))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	plan := repo.created
+	if plan == nil {
+		t.Fatal("expected plan to be saved")
+	}
+	if len(plan.SummaryIDs) != 2 || plan.SummaryIDs[0] != first || plan.SummaryIDs[1] != second {
+		t.Fatalf("summary order = %v, want request order without duplicates", plan.SummaryIDs)
+	}
+	if plan.DailyMinutes != defaultStudyPlanDailyMinutes || plan.AIOrdered {
+		t.Fatalf("unexpected plan settings: %+v", plan)
+	}
+	if !plan.StartDate.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("start date = %s, want today", plan.StartDate)
+	}
+	if len(plan.Items) != 5 || plan.Items[0].Kind != models.StudyPlanItemFlashcards {
+		t.Fatalf("expected flashcards first plus a review and quiz per summary, got %+v", plan.Items)
+	}
+}
+
+func TestGenerateStudyPlan_AIOrdering(t *testing.T) {
+	userID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	repo := &stubStudyPlanRepo{summaries: []models.StudyPlanSummary{
+		{SummaryID: first, UserID: userID, Title: "Cells"},
+		{SummaryID: second, UserID: userID, Title: "Genetics"},
+	}}
+	body := `{"summary_ids":["` + first.String() + `","` + second.String() + `"],"target_date":"2026-03-09","ai_ordering":true}`
+
+	orderer := &stubStudyTopicOrderer{}
+	rr := httptest.NewRecorder()
+	newTestStudyPlanHandler(repo, orderer).Generate(rr, makeStudyPlanReq(t, http.MethodPost, userID, nil, body))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !repo.created.AIOrdered || repo.created.SummaryIDs[0] != second {
+		t.Fatalf("expected AI order to be applied, got %+v", repo.created.SummaryIDs)
+	}
+
+	failing := &stubStudyTopicOrderer{err: errors.New("gemini down")}
+	rr = httptest.NewRecorder()
+	newTestStudyPlanHandler(repo, failing).Generate(rr, makeStudyPlanReq(t, http.MethodPost, userID, nil, body))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 when ordering fails, got %d", rr.Code)
+	}
+	if repo.created.AIOrdered || repo.created.SummaryIDs[0] != first {
+		t.Fatalf("expected request order after an ordering failure, got %+v", repo.created.SummaryIDs)
+	}
+}
+
+func TestGenerateStudyPlan_Validation(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{name: "no summaries", body: `{"summary_ids":[],"target_date":"2026-03-09"}`, field: "summary_ids"},
+		{name: "bad date", body: `{"summary_ids":["` + summaryID.String() + `"],"target_date":"next week"}`, field: "target_date"},
+		{name: "past date", body: `{"summary_ids":["` + summaryID.String() + `"],"target_date":"2026-03-01"}`, field: "target_date"},
+		{name: "too far", body: `{"summary_ids":["` + summaryID.String() + `"],"target_date":"2026-12-01"}`, field: "target_date"},
+		{name: "daily minutes", body: `{"summary_ids":["` + summaryID.String() + `"],"target_date":"2026-03-09","daily_minutes":5}`, field: "daily_minutes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubStudyPlanRepo{}
+			rr := httptest.NewRecorder()
+			newTestStudyPlanHandler(repo, &stubStudyTopicOrderer{}).Generate(rr, makeStudyPlanReq(t, http.MethodPost, userID, nil, tt.body))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rr.Code)
+			}
+			var payload models.ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if _, ok := payload.Error.Fields[tt.field]; !ok {
+				t.Fatalf("expected a %s field error, got %+v", tt.field, payload.Error.Fields)
+			}
+			if repo.created != nil {
+				t.Fatal("expected no plan to be saved")
+			}
+		})
+	}
+}
+
+func TestGenerateStudyPlan_SummaryOwnership(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	body := `{"summary_ids":["` + summaryID.String() + `"],"target_date":"2026-03-09"}`
+
+	rr := httptest.NewRecorder()
+	newTestStudyPlanHandler(&stubStudyPlanRepo{}, &stubStudyTopicOrderer{}).Generate(rr, makeStudyPlanReq(t, http.MethodPost, userID, nil, body))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("missing summary: expected 404, got %d", rr.Code)
+	}
+
+	repo := &stubStudyPlanRepo{summaries: []models.StudyPlanSummary{{SummaryID: summaryID, UserID: uuid.New()}}}
+	rr = httptest.NewRecorder()
+	newTestStudyPlanHandler(repo, &stubStudyTopicOrderer{}).Generate(rr, makeStudyPlanReq(t, http.MethodPost, userID, nil, body))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("other user's summary: expected 403, got %d", rr.Code)
+	}
+}
+
+func TestGetStudyPlan(t *testing.T) {
+	userID := uuid.New()
+	planID := uuid.New()
+	params := map[string]string{"id": planID.String()}
+
+	repo := &stubStudyPlanRepo{plan: &models.StudyPlan{ID: planID, UserID: userID}}
+	rr := httptest.NewRecorder()
+	newTestStudyPlanHandler(repo, nil).Get(rr, makeStudyPlanReq(t, http.MethodGet, userID, params, ""))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("owner: expected 200, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	newTestStudyPlanHandler(repo, nil).Get(rr, makeStudyPlanReq(t, http.MethodGet, uuid.New(), params, ""))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("other user: expected 403, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	newTestStudyPlanHandler(&stubStudyPlanRepo{getErr: pgx.ErrNoRows}, nil).Get(rr, makeStudyPlanReq(t, http.MethodGet, userID, params, ""))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("missing plan: expected 404, got %d", rr.Code)
+	}
+}
+
+func TestStudyPlanItemCompletion(t *testing.T) {
+	userID := uuid.New()
+	params := map[string]string{"id": uuid.New().String(), "itemId": uuid.New().String()}
+
+	repo := &stubStudyPlanRepo{}
+	h := newTestStudyPlanHandler(repo, nil)
+
+	rr := httptest.NewRecorder()
+	h.CompleteItem(rr, makeStudyPlanReq(t, http.MethodPost, userID, params, ""))
+	if rr.Code != http.StatusOK || repo.itemCompleted == nil || !*repo.itemCompleted || repo.itemUserID != userID {
+		t.Fatalf("complete: got %d, completed=%v", rr.Code, repo.itemCompleted)
+	}
+
+	rr = httptest.NewRecorder()
+	h.UncompleteItem(rr, makeStudyPlanReq(t, http.MethodPost, userID, params, ""))
+	if rr.Code != http.StatusOK || *repo.itemCompleted {
+		t.Fatalf("uncomplete: got %d, completed=%v", rr.Code, *repo.itemCompleted)
+	}
+
+	repo.itemErr = pgx.ErrNoRows
+	rr = httptest.NewRecorder()
+	h.CompleteItem(rr, makeStudyPlanReq(t, http.MethodPost, userID, params, ""))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("missing item: expected 404, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.CompleteItem(rr, makeStudyPlanReq(t, http.MethodPost, userID, map[string]string{"id": "bad", "itemId": "bad"}, ""))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("bad id: expected 400, got %d", rr.Code)
+	}
+}
@@ -16,6 +16,8 @@ import (
 type studySessionRepository interface {
 	Start(ctx context.Context, s *models.StudySession) error
 	Heartbeat(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
+	Pause(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
+	Resume(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
 	Stop(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
 }
 
@@ -114,3 +116,49 @@ func (h *StudySessionHandler) Stop(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Study session stopped"})
 }
+
+// Pause stops the session's clock, e.g. while the tab is hidden. Pausing a
+// paused session is a no-op.
+func (h *StudySessionHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid session ID", r))
+		return
+	}
+
+	updated, err := h.repo.Pause(r.Context(), sessionID, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to pause study session", r))
+		return
+	}
+	if !updated {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Session not found or already ended", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Study session paused"})
+}
+
+// Resume restarts a paused session's clock. Resuming a running session
+// counts as a heartbeat.
+func (h *StudySessionHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid session ID", r))
+		return
+	}
+
+	updated, err := h.repo.Resume(r.Context(), sessionID, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to resume study session", r))
+		return
+	}
+	if !updated {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Session not found or already ended", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Study session resumed"})
+}
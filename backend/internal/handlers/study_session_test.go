@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -23,6 +24,14 @@ type stubStudySessionRepo struct {
 	heartbeatUserID  uuid.UUID
 	heartbeatID      uuid.UUID
 
+	pauseUpdated bool
+	pauseCalls   int
+	pauseID      uuid.UUID
+
+	resumeUpdated bool
+	resumeErr     error
+	resumeCalls   int
+
 	stopUpdated bool
 	stopErr     error
 	stopCalls   int
@@ -50,6 +59,20 @@ func (s *stubStudySessionRepo) Heartbeat(ctx context.Context, sessionID, userID
 	return s.heartbeatUpdated, nil
 }
 
+func (s *stubStudySessionRepo) Pause(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	s.pauseCalls++
+	s.pauseID = sessionID
+	return s.pauseUpdated, nil
+}
+
+func (s *stubStudySessionRepo) Resume(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	s.resumeCalls++
+	if s.resumeErr != nil {
+		return false, s.resumeErr
+	}
+	return s.resumeUpdated, nil
+}
+
 func (s *stubStudySessionRepo) Stop(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
 	s.stopCalls++
 	s.stopID = sessionID
@@ -192,3 +215,67 @@ func TestStop_ForeignSession_Returns404(t *testing.T) {
 		t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
 	}
 }
+
+func TestPause_ValidSession_Returns200(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	repo := &stubStudySessionRepo{pauseUpdated: true}
+	h := &StudySessionHandler{repo: repo}
+
+	req := makeStudySessionReq(t, http.MethodPost, "/api/v1/study-sessions/"+sessionID.String()+"/pause", userID, &sessionID, "")
+	rr := httptest.NewRecorder()
+
+	h.Pause(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.pauseCalls != 1 || repo.pauseID != sessionID {
+		t.Fatalf("unexpected pause call args")
+	}
+}
+
+func TestPause_EndedSession_Returns404(t *testing.T) {
+	sessionID := uuid.New()
+	h := &StudySessionHandler{repo: &stubStudySessionRepo{}}
+
+	req := makeStudySessionReq(t, http.MethodPost, "/api/v1/study-sessions/"+sessionID.String()+"/pause", uuid.New(), &sessionID, "")
+	rr := httptest.NewRecorder()
+
+	h.Pause(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestResume_InvalidID_Returns400(t *testing.T) {
+	repo := &stubStudySessionRepo{resumeUpdated: true}
+	h := &StudySessionHandler{repo: repo}
+
+	req := makeStudySessionReq(t, http.MethodPost, "/api/v1/study-sessions/nope/resume", uuid.New(), nil, "")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "nope")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+
+	h.Resume(rr, req)
+
+	if rr.Code != http.StatusBadRequest || repo.resumeCalls != 0 {
+		t.Fatalf("expected 400 without a repo call, got %d (%d calls)", rr.Code, repo.resumeCalls)
+	}
+}
+
+func TestResume_RepoError_Returns500(t *testing.T) {
+	sessionID := uuid.New()
+	h := &StudySessionHandler{repo: &stubStudySessionRepo{resumeErr: errors.New("db down")}}
+
+	req := makeStudySessionReq(t, http.MethodPost, "/api/v1/study-sessions/"+sessionID.String()+"/resume", uuid.New(), &sessionID, "")
+	rr := httptest.NewRecorder()
+
+	h.Resume(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -27,21 +28,37 @@ type SummaryHandler struct {
 	contentRepo  *repository.ContentRepo
 	jobRepo      *repository.JobRepo
 	redis        *redis.Client
+	queueBreaker *QueueBreaker
 	quotaService *services.QuotaService
 	userRepo     *repository.UserRepo
+	settingsRepo generationSettingsStore
+	tableBuilder smartTableRebuilder
+	versions     summaryVersionSnapshotter
+	versionLimit int
 }
 
 type summaryRepository interface {
 	Create(ctx context.Context, s *models.Summary) error
-	ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, limit, offset int) ([]*models.Summary, int, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Summary, int, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
 	Update(ctx context.Context, s *models.Summary) error
 	UpdateTitle(ctx context.Context, id uuid.UUID, title string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	SetArchived(ctx context.Context, id uuid.UUID, userID uuid.UUID, archived bool) error
+	Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error)
+	UpdateRawContent(ctx context.Context, id uuid.UUID, raw string, wordCount int, isQualityFallback bool, qualityFallbackReason *string) error
 }
 
-func NewSummaryHandler(summaryRepo summaryRepository, contentRepo *repository.ContentRepo, jobRepo *repository.JobRepo, redisClient *redis.Client, quotaService *services.QuotaService, userRepo *repository.UserRepo) *SummaryHandler {
+type summaryVersionSnapshotter interface {
+	Snapshot(ctx context.Context, summaryID uuid.UUID, keep int) (bool, error)
+}
+
+type smartTableRebuilder interface {
+	RebuildSmartSummaryTable(ctx context.Context, summary, transcript string) (string, error)
+}
+
+func NewSummaryHandler(summaryRepo summaryRepository, contentRepo *repository.ContentRepo, jobRepo *repository.JobRepo, redisClient *redis.Client, quotaService *services.QuotaService, userRepo *repository.UserRepo, geminiService *services.GeminiService) *SummaryHandler {
 	return &SummaryHandler{
 		summaryRepo:  summaryRepo,
 		contentRepo:  contentRepo,
@@ -49,10 +66,26 @@ func NewSummaryHandler(summaryRepo summaryRepository, contentRepo *repository.Co
 		redis:        redisClient,
 		quotaService: quotaService,
 		userRepo:     userRepo,
+		settingsRepo: userRepo,
+		tableBuilder: geminiService,
 	}
 }
 
+// SetQueueBreaker makes job pushes fail fast while b is open.
+func (h *SummaryHandler) SetQueueBreaker(b *QueueBreaker) {
+	h.queueBreaker = b
+}
+
+// SetVersionHistory saves a summary's body before each regeneration,
+// keeping the newest keep versions.
+func (h *SummaryHandler) SetVersionHistory(versions summaryVersionSnapshotter, keep int) {
+	h.versions = versions
+	h.versionLimit = keep
+}
+
 func (h *SummaryHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxGenerateRequestBytes)
+
 	var req models.GenerateSummaryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
@@ -60,6 +93,18 @@ func (h *SummaryHandler) Generate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := middleware.GetUserID(r.Context())
+	settings := loadGenerationSettings(r.Context(), h.settingsRepo, userID)
+	applySummaryDefaults(&req, settings)
+
+	fields := services.ValidateSummaryConfig(req)
+	for field, msg := range prepareAutoGenerate(req.AutoGenerate, settings) {
+		fields[field] = msg
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+	req.FocusAreas = services.NormalizeFocusAreas(req.FocusAreas)
 
 	// Verify content exists and belongs to user
 	content, err := h.contentRepo.GetByID(r.Context(), req.ContentID)
@@ -119,18 +164,16 @@ func (h *SummaryHandler) Generate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Push to Redis queue
-	jobBytes, _ := json.Marshal(job)
 	if h.redis == nil {
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Summary queue is unavailable", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
-	if err := h.redis.LPush(r.Context(), "queue:summary-generation", string(jobBytes)).Err(); err != nil {
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:summary-generation", job); err != nil {
 		log.Printf("failed to enqueue summary-generation job %s: %v", job.ID, err)
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to enqueue summary job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
@@ -144,6 +187,7 @@ func (h *SummaryHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	search := r.URL.Query().Get("search")
 	sortBy := r.URL.Query().Get("sort")
+	favoriteOnly := r.URL.Query().Get("favorite") == "true"
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 
@@ -151,7 +195,7 @@ func (h *SummaryHandler) List(w http.ResponseWriter, r *http.Request) {
 		limit = 1000 // High default to support frontend's unpaginated full-list filtering
 	}
 
-	summaries, total, err := h.summaryRepo.ListByUser(r.Context(), userID, search, sortBy, limit, offset)
+	summaries, total, err := h.summaryRepo.ListByUser(r.Context(), userID, search, sortBy, favoriteOnly, limit, offset)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to fetch summaries", r))
 		return
@@ -191,10 +235,68 @@ func (h *SummaryHandler) Get(w http.ResponseWriter, r *http.Request) {
 	if summary.FollowUpQuestions == nil {
 		summary.FollowUpQuestions = []string{}
 	}
+	if summary.SourceTruncated && summary.SourceCoveragePercent != nil {
+		summary.TruncationNotice = services.SourceTruncationNotice(*summary.SourceCoveragePercent)
+	}
 
 	writeJSON(w, http.StatusOK, summary)
 }
 
+// Export downloads the summary as a markdown file (?format=md, the default)
+// or a printable PDF (?format=pdf).
+func (h *SummaryHandler) Export(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "md"
+	}
+	if format != "md" && format != "pdf" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Unsupported export format", r))
+		return
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+	if summary.ContentRaw == nil || strings.TrimSpace(*summary.ContentRaw) == "" {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Summary has not finished generating yet", r))
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/markdown; charset=utf-8"
+	if format == "pdf" {
+		contentType = "application/pdf"
+		err = services.WriteSummaryPDF(&buf, summary)
+	} else {
+		err = services.WriteSummaryMarkdown(&buf, summary)
+	}
+	if err != nil {
+		log.Printf("SummaryHandler.Export: failed to render summary %s as %s: %v", summary.ID, format, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to export summary", r))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.SummaryExportFileName(summary.Title, format)))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
 func (h *SummaryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -215,8 +317,9 @@ func (h *SummaryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var update struct {
-		Title string   `json:"title"`
-		Tags  []string `json:"tags"`
+		Title       string   `json:"title"`
+		Tags        []string `json:"tags"`
+		Description *string  `json:"description"`
 	}
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
@@ -225,16 +328,37 @@ func (h *SummaryHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.TrimSpace(update.Title) == "" && update.Tags == nil {
+	if strings.TrimSpace(update.Title) == "" && update.Tags == nil && update.Description == nil {
 		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "No fields to update", r))
 		return
 	}
 
+	// Titles, tags and descriptions end up in emails and shared pages, so
+	// markup is stripped before storage.
+	fields := make(map[string]string)
 	if update.Title != "" {
-		summary.Title = strings.TrimSpace(update.Title)
+		title := services.SanitizeLine(update.Title)
+		if title == "" {
+			fields["title"] = "Title cannot be empty"
+		} else if len([]rune(title)) > services.MaxTitleLength {
+			fields["title"] = fmt.Sprintf("Title must be %d characters or fewer", services.MaxTitleLength)
+		}
+		summary.Title = title
 	}
 	if update.Tags != nil {
-		summary.Tags = update.Tags
+		tags := services.SanitizeTags(update.Tags)
+		if msg := services.ValidateTags(tags); msg != "" {
+			fields["tags"] = msg
+		}
+		summary.Tags = tags
+	}
+	if update.Description != nil {
+		description := services.StripMarkup(*update.Description)
+		summary.Description = &description
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
 	}
 
 	if err := h.summaryRepo.Update(r.Context(), summary); err != nil {
@@ -272,6 +396,29 @@ func (h *SummaryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Summary deleted"})
 }
 
+// Restore brings a summary back from the trash while it is still inside the
+// recovery window.
+func (h *SummaryHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	restored, err := h.summaryRepo.Restore(r.Context(), id, userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to restore summary", r))
+		return
+	}
+	if !restored {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found in trash or recovery window has expired", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Summary restored"})
+}
+
 func (h *SummaryHandler) ToggleFavorite(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -299,6 +446,57 @@ func (h *SummaryHandler) ToggleFavorite(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, map[string]string{"message": "Favorite toggled"})
 }
 
+// Archive hides a summary from the summary list and library without deleting it.
+func (h *SummaryHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h *SummaryHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *SummaryHandler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if err := h.summaryRepo.SetArchived(r.Context(), id, userID, archived); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to update archive state", r))
+		return
+	}
+
+	message := "Summary archived"
+	if !archived {
+		message = "Summary unarchived"
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":     message,
+		"is_archived": archived,
+	})
+}
+
+// FocusAreas lists the focus areas accepted in summary generation requests.
+func (h *SummaryHandler) FocusAreas(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"focus_areas": services.SummaryFocusAreas(),
+	})
+}
+
 func (h *SummaryHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -328,6 +526,13 @@ func (h *SummaryHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	fields := make(map[string]string)
+	services.ValidateFocusAreas(fields, req.FocusAreas)
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
 	// Fallback to existing summary config when body is empty or partially missing
 	if len(summary.ConfigJSON) > 0 {
 		var existing models.GenerateSummaryRequest
@@ -362,11 +567,17 @@ func (h *SummaryHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
 	if req.Length == "" {
 		req.Length = summary.LengthSetting
 	}
-	if req.Language == "" {
-		req.Language = "en"
-	}
-	if req.FocusAreas == nil {
-		req.FocusAreas = []string{}
+	// An empty language lets the worker follow the content's detected language.
+	// Inherited focus areas may predate the allow-list; drop unsupported ones.
+	req.FocusAreas = services.NormalizeFocusAreas(req.FocusAreas)
+
+	// Keep the body this regeneration replaces so it can be restored.
+	if h.versions != nil {
+		if _, err := h.versions.Snapshot(r.Context(), id, h.versionLimit); err != nil {
+			log.Printf("SummaryHandler.Regenerate: failed to save version of summary %s: %v", id, err)
+			writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to save summary version", r))
+			return
+		}
 	}
 
 	configBytes, _ := json.Marshal(req)
@@ -384,17 +595,16 @@ func (h *SummaryHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jobBytes, _ := json.Marshal(job)
 	if h.redis == nil {
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Summary queue is unavailable", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
-	if err := h.redis.LPush(r.Context(), "queue:summary-generation", string(jobBytes)).Err(); err != nil {
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:summary-generation", job); err != nil {
 		log.Printf("failed to enqueue summary-regeneration job %s: %v", job.ID, err)
 		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
-		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to enqueue summary job", r))
+		writeQueueUnavailable(w, r)
 		return
 	}
 
@@ -411,6 +621,203 @@ func (h *SummaryHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Rewrite condenses or expands an existing summary into a new summary record
+// linked to the same content. The original summary is left untouched.
+func (h *SummaryHandler) Rewrite(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+
+	source, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	if source.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	var body models.RewriteSummaryRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGenerateRequestBytes)).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid request body", r))
+		return
+	}
+
+	if source.ContentRaw == nil || strings.TrimSpace(*source.ContentRaw) == "" {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Summary has no generated content to rewrite yet", r))
+		return
+	}
+
+	// Carry over audience/language/focus from the original generation.
+	var req models.GenerateSummaryRequest
+	if len(source.ConfigJSON) > 0 {
+		_ = json.Unmarshal(source.ConfigJSON, &req)
+	}
+	if source.ContentID != nil {
+		req.ContentID = *source.ContentID
+	}
+	req.Length = strings.TrimSpace(body.Length)
+	req.Format = strings.TrimSpace(body.Format)
+	if req.Format == "" {
+		req.Format = source.Format
+	}
+	req.RewriteFromSummaryID = &source.ID
+	req.ExtractScreenText = false
+	req.FocusAreas = services.NormalizeFocusAreas(req.FocusAreas)
+
+	fields := services.ValidateSummaryConfig(req)
+	if req.Length == "" {
+		fields["length"] = "length is required"
+	}
+	if len(fields) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorRespWithFields("VALIDATION_ERROR", "Validation failed", fields, r))
+		return
+	}
+
+	// Quota Check
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load user profile", r))
+		return
+	}
+
+	if !user.HasGeminiKey {
+		allowed, err := h.quotaService.CheckQuota(r.Context(), userID, user.Plan, "summary")
+		if err != nil {
+			if err.Error() == "API_KEY_REQUIRED" {
+				writeJSON(w, http.StatusPaymentRequired, errorResp("API_KEY_REQUIRED", "Your Plus plan requires a custom Gemini API key. Please add it in settings.", r))
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to verify quota", r))
+			return
+		}
+		if !allowed {
+			writeJSON(w, http.StatusPaymentRequired, errorResp("QUOTA_EXCEEDED", "You have reached your monthly limit for Summaries. Please upgrade your plan or add a custom API key.", r))
+			return
+		}
+	}
+
+	summary := &models.Summary{
+		UserID:        userID,
+		ContentID:     source.ContentID,
+		Format:        req.Format,
+		LengthSetting: req.Length,
+	}
+	configBytes, _ := json.Marshal(req)
+	summary.ConfigJSON = configBytes
+
+	if err := h.summaryRepo.Create(r.Context(), summary); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create summary", r))
+		return
+	}
+
+	job := &models.Job{
+		UserID:      userID,
+		Type:        "summary-generation",
+		ReferenceID: summary.ID,
+		ConfigJSON:  configBytes,
+	}
+
+	if err := h.jobRepo.Create(r.Context(), job); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to create job", r))
+		return
+	}
+
+	if h.redis == nil {
+		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	if err := pushJob(r.Context(), h.queueBreaker, h.redis, "queue:summary-generation", job); err != nil {
+		log.Printf("failed to enqueue summary-rewrite job %s: %v", job.ID, err)
+		_ = h.jobRepo.UpdateStatus(r.Context(), job.ID, "failed")
+		writeQueueUnavailable(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"job_id":            job.ID,
+		"summary_id":        summary.ID,
+		"source_summary_id": source.ID,
+	})
+}
+
+// RebuildTable regenerates just the table of a smart summary, the cheap fix
+// for the weak "Point 1/2/3" fallback table, without a full regenerate.
+func (h *SummaryHandler) RebuildTable(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	if summary.Format != "smart" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Only smart summaries have a table to rebuild", r))
+		return
+	}
+	if summary.ContentRaw == nil || strings.TrimSpace(*summary.ContentRaw) == "" {
+		writeJSON(w, http.StatusConflict, errorResp("CONFLICT", "Summary has no generated content yet", r))
+		return
+	}
+
+	// The transcript grounds the new table; without one the model still has
+	// the summary itself to work from.
+	transcript := ""
+	if summary.ContentID != nil && h.contentRepo != nil {
+		content, err := h.contentRepo.GetByID(r.Context(), *summary.ContentID)
+		if err != nil {
+			log.Printf("SummaryHandler.RebuildTable: failed to load content %s for summary %s: %v", *summary.ContentID, id, err)
+		} else if content.Transcript != nil {
+			transcript = *content.Transcript
+		}
+	}
+
+	rebuilt, err := h.tableBuilder.RebuildSmartSummaryTable(r.Context(), *summary.ContentRaw, transcript)
+	if err != nil {
+		log.Printf("SummaryHandler.RebuildTable: failed to rebuild table for summary %s: %v", id, err)
+		writeJSON(w, http.StatusBadGateway, errorResp("UPSTREAM_ERROR", "Failed to rebuild summary table", r))
+		return
+	}
+
+	// A summary flagged only because its table had to be faked is no longer a
+	// fallback once it has a real one.
+	if summary.QualityFallbackReason != nil && *summary.QualityFallbackReason == "smart_summary_structure_fallback" {
+		summary.IsQualityFallback = false
+		summary.QualityFallbackReason = nil
+	}
+	summary.ContentRaw = &rebuilt
+	summary.WordCount = len(strings.Fields(rebuilt))
+
+	if err := h.summaryRepo.UpdateRawContent(r.Context(), id, rebuilt, summary.WordCount, summary.IsQualityFallback, summary.QualityFallbackReason); err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to save summary", r))
+		return
+	}
+	if summary.FollowUpQuestions == nil {
+		summary.FollowUpQuestions = []string{}
+	}
+
+	writeJSON(w, http.StatusOK, summary)
+}
+
 // PDF export is handled client-side via jsPDF in src/pages/SummaryPage.tsx.
 // The previous backend pdf_export.py pipeline was removed to avoid dual-path drift.
 
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+func newSummaryArchiveRequest(summaryID, userID uuid.UUID, action string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/summaries/"+summaryID.String()+"/"+action, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryHandler_Archive_NonOwnerForbidden(t *testing.T) {
+	summaryID := uuid.New()
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: uuid.New()}}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Archive(rr, newSummaryArchiveRequest(summaryID, uuid.New(), "archive"))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if repo.archived != nil {
+		t.Fatalf("archive should not be executed for non-owner")
+	}
+}
+
+func TestSummaryHandler_ArchiveAndUnarchive_Owner(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: ownerID}}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Archive(rr, newSummaryArchiveRequest(summaryID, ownerID, "archive"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.archived == nil || !*repo.archived {
+		t.Fatalf("expected summary to be archived")
+	}
+
+	rr = httptest.NewRecorder()
+	h.Unarchive(rr, newSummaryArchiveRequest(summaryID, ownerID, "unarchive"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.archived == nil || *repo.archived {
+		t.Fatalf("expected summary to be unarchived")
+	}
+	if repo.lastID != summaryID || repo.lastUser != ownerID {
+		t.Fatalf("unexpected archive params: id=%s user=%s", repo.lastID, repo.lastUser)
+	}
+}
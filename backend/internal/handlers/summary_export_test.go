@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestSummaryExport(t *testing.T) {
+	ownerID := uuid.New()
+	summaryID := uuid.New()
+	content := "## Key Points\n\n- Cells divide by mitosis"
+	description := "Intro lecture on cell division"
+
+	tests := []struct {
+		name     string
+		query    string
+		userID   uuid.UUID
+		content  *string
+		wantCode int
+		wantType string
+		wantFile string
+	}{
+		{"markdown by default", "", ownerID, &content, http.StatusOK, "text/markdown; charset=utf-8", `filename="cell-biology.md"`},
+		{"pdf", "?format=pdf", ownerID, &content, http.StatusOK, "application/pdf", `filename="cell-biology.pdf"`},
+		{"other user", "?format=md", uuid.New(), &content, http.StatusForbidden, "", ""},
+		{"still generating", "?format=pdf", ownerID, nil, http.StatusConflict, "", ""},
+		{"unsupported format", "?format=docx", ownerID, &content, http.StatusBadRequest, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubSummaryRepo{summary: &models.Summary{
+				ID: summaryID, UserID: ownerID, Title: "Cell Biology", Format: "smart", LengthSetting: "standard",
+				ContentRaw: tt.content, Tags: []string{"biology", "cells"}, Description: &description,
+			}}
+			h := &SummaryHandler{summaryRepo: repo}
+
+			rr := httptest.NewRecorder()
+			h.Export(rr, makeAttemptRequest(http.MethodGet, "/api/v1/summaries/"+summaryID.String()+"/export"+tt.query, summaryID, tt.userID, ""))
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if got := rr.Header().Get("Content-Type"); got != tt.wantType {
+				t.Fatalf("expected %s, got %q", tt.wantType, got)
+			}
+			if got := rr.Header().Get("Content-Disposition"); !strings.Contains(got, tt.wantFile) {
+				t.Fatalf("expected %s in Content-Disposition, got %q", tt.wantFile, got)
+			}
+			body := rr.Body.Bytes()
+			if tt.wantType == "application/pdf" {
+				if !bytes.HasPrefix(body, []byte("%PDF-")) {
+					t.Fatalf("expected a PDF body")
+				}
+			} else if !bytes.Contains(body, []byte("# Cell Biology")) || !bytes.Contains(body, []byte("- Tags: biology, cells")) || !bytes.Contains(body, []byte("> "+description)) {
+				t.Fatalf("expected title, tags and description in the header block, got:\n%s", body)
+			}
+			for _, want := range []string{"Key Points", "Cells divide by mitosis", "biology, cells"} {
+				if !bytes.Contains(body, []byte(want)) {
+					t.Fatalf("expected %q in the export", want)
+				}
+			}
+		})
+	}
+}
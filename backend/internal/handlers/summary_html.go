@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type summaryHTMLRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
+	GetHTML(ctx context.Context, id uuid.UUID) (string, error)
+	SaveHTML(ctx context.Context, id uuid.UUID, html string) error
+}
+
+type SummaryHTMLHandler struct {
+	summaryRepo summaryHTMLRepository
+}
+
+func NewSummaryHTMLHandler(summaryRepo summaryHTMLRepository) *SummaryHTMLHandler {
+	return &SummaryHTMLHandler{summaryRepo: summaryRepo}
+}
+
+// Get returns the summary body rendered to sanitized HTML, so every client
+// shows the same markup. The rendering is cached until the content changes.
+func (h *SummaryHTMLHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	cached, err := h.summaryRepo.GetHTML(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load summary HTML", r))
+		return
+	}
+	if cached != "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"summary_id": id,
+			"html":       cached,
+		})
+		return
+	}
+
+	html, err := services.RenderSummaryHTML(summary)
+	if err != nil {
+		log.Printf("SummaryHTMLHandler.Get: failed to render summary %s: %v", id, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to render summary", r))
+		return
+	}
+	if html == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Summary has no content to render", r))
+		return
+	}
+	if err := h.summaryRepo.SaveHTML(r.Context(), id, html); err != nil {
+		log.Printf("SummaryHTMLHandler.Get: failed to cache HTML for summary %s: %v", id, err)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summary_id": id,
+		"html":       html,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubSummaryHTMLRepo struct {
+	summary *models.Summary
+	html    string
+	saved   string
+}
+
+func (s *stubSummaryHTMLRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error) {
+	if s.summary == nil {
+		return nil, context.Canceled
+	}
+	return s.summary, nil
+}
+
+func (s *stubSummaryHTMLRepo) GetHTML(ctx context.Context, id uuid.UUID) (string, error) {
+	return s.html, nil
+}
+
+func (s *stubSummaryHTMLRepo) SaveHTML(ctx context.Context, id uuid.UUID, html string) error {
+	s.saved = html
+	return nil
+}
+
+func makeSummaryHTMLRequest(summaryID, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/summaries/"+summaryID.String()+"/html", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryHTMLGet_RendersAndCaches(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	content := "## Cells\n\n| Phase | Event |\n| --- | --- |\n| Prophase | Chromosomes condense |\n\n<script>alert(1)</script>"
+	repo := &stubSummaryHTMLRepo{summary: &models.Summary{ID: summaryID, UserID: userID, Format: "smart", ContentRaw: &content}}
+	h := NewSummaryHTMLHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeSummaryHTMLRequest(summaryID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var body struct {
+		HTML string `json:"html"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.Contains(body.HTML, "<table>") || strings.Contains(body.HTML, "<script") {
+		t.Fatalf("unexpected html: %s", body.HTML)
+	}
+	if repo.saved != body.HTML {
+		t.Fatal("expected rendered HTML to be cached")
+	}
+}
+
+func TestSummaryHTMLGet_UsesCachedHTML(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	content := "Fresh content"
+	repo := &stubSummaryHTMLRepo{
+		summary: &models.Summary{ID: summaryID, UserID: userID, Format: "smart", ContentRaw: &content},
+		html:    "<p>cached</p>",
+	}
+	h := NewSummaryHTMLHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeSummaryHTMLRequest(summaryID, userID))
+
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "cached") {
+		t.Fatalf("expected cached HTML, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if repo.saved != "" {
+		t.Fatal("cached HTML should not be re-rendered")
+	}
+}
+
+func TestSummaryHTMLGet_RejectsOtherUsers(t *testing.T) {
+	summaryID := uuid.New()
+	content := "Private"
+	repo := &stubSummaryHTMLRepo{summary: &models.Summary{ID: summaryID, UserID: uuid.New(), ContentRaw: &content}}
+	h := NewSummaryHTMLHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeSummaryHTMLRequest(summaryID, uuid.New()))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestSummaryHTMLGet_NoContent(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	repo := &stubSummaryHTMLRepo{summary: &models.Summary{ID: summaryID, UserID: userID, Format: "smart"}}
+	h := NewSummaryHTMLHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Get(rr, makeSummaryHTMLRequest(summaryID, userID))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
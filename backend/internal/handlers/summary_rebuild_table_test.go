@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubSmartTableRebuilder struct {
+	result  string
+	err     error
+	calls   int
+	summary string
+}
+
+func (s *stubSmartTableRebuilder) RebuildSmartSummaryTable(ctx context.Context, summary, transcript string) (string, error) {
+	s.calls++
+	s.summary = summary
+	return s.result, s.err
+}
+
+func newRebuildTableRequest(summaryID, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/summaries/"+summaryID.String()+"/rebuild-table", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryHandler_RebuildTable_ReplacesContentAndClearsTableFallback(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	raw := "## Key Concepts Table\n| Concept | Explanation |\n| --- | --- |\n| Point 1 | Key takeaway |"
+	reason := "smart_summary_structure_fallback"
+	repo := &stubSummaryRepo{summary: &models.Summary{
+		ID: summaryID, UserID: ownerID, Format: "smart", ContentRaw: &raw,
+		IsQualityFallback: true, QualityFallbackReason: &reason,
+	}}
+	builder := &stubSmartTableRebuilder{result: "## Key Concepts Table\n| Stage | Output |\n| --- | --- |\n| Light reactions | ATP |"}
+	h := &SummaryHandler{summaryRepo: repo, tableBuilder: builder}
+
+	rr := httptest.NewRecorder()
+	h.RebuildTable(rr, newRebuildTableRequest(summaryID, ownerID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if builder.summary != raw {
+		t.Fatalf("expected existing content to be sent to the rebuilder, got %q", builder.summary)
+	}
+	if repo.rawContent == nil || *repo.rawContent != builder.result {
+		t.Fatalf("expected rebuilt content to be saved, got %v", repo.rawContent)
+	}
+	if repo.qualityFallback {
+		t.Fatal("expected table fallback flag to be cleared")
+	}
+	if repo.wordCount == 0 {
+		t.Fatal("expected word count to be recomputed")
+	}
+}
+
+func TestSummaryHandler_RebuildTable_RejectsNonSmartAndForeignSummaries(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	raw := "Some summary text"
+
+	builder := &stubSmartTableRebuilder{}
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: ownerID, Format: "bullets", ContentRaw: &raw}}
+	h := &SummaryHandler{summaryRepo: repo, tableBuilder: builder}
+
+	rr := httptest.NewRecorder()
+	h.RebuildTable(rr, newRebuildTableRequest(summaryID, ownerID))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("non-smart summary: expected %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.RebuildTable(rr, newRebuildTableRequest(summaryID, uuid.New()))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("other user: expected %d, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	repo.summary = &models.Summary{ID: summaryID, UserID: ownerID, Format: "smart"}
+	rr = httptest.NewRecorder()
+	h.RebuildTable(rr, newRebuildTableRequest(summaryID, ownerID))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("no content: expected %d, got %d", http.StatusConflict, rr.Code)
+	}
+
+	if builder.calls != 0 {
+		t.Fatalf("expected no Gemini calls, got %d", builder.calls)
+	}
+}
+
+func TestSummaryHandler_RebuildTable_UpstreamFailureLeavesSummary(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	raw := "## Summary of Video Content\nText"
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: ownerID, Format: "smart", ContentRaw: &raw}}
+	h := &SummaryHandler{summaryRepo: repo, tableBuilder: &stubSmartTableRebuilder{err: errors.New("no usable table")}}
+
+	rr := httptest.NewRecorder()
+	h.RebuildTable(rr, newRebuildTableRequest(summaryID, ownerID))
+
+	if rr.Code != http.StatusBadGateway {
+		t.Fatalf("expected status %d, got %d", http.StatusBadGateway, rr.Code)
+	}
+	if repo.rawContent != nil {
+		t.Fatal("expected summary to be left unchanged")
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+const (
+	defaultRelatedSummaryLimit = 5
+	maxRelatedSummaryLimit     = 20
+)
+
+type summaryRelatedRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
+	FindRelated(ctx context.Context, userID, summaryID uuid.UUID, limit int) ([]models.RelatedSummary, error)
+}
+
+type SummaryRelatedHandler struct {
+	summaryRepo summaryRelatedRepository
+}
+
+func NewSummaryRelatedHandler(summaryRepo summaryRelatedRepository) *SummaryRelatedHandler {
+	return &SummaryRelatedHandler{summaryRepo: summaryRepo}
+}
+
+// Related returns the caller's other summaries that share the most tags with
+// this one. Archived and deleted summaries are left out.
+func (h *SummaryRelatedHandler) Related(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	limit := defaultRelatedSummaryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxRelatedSummaryLimit {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Limit must be between 1 and 20", r))
+			return
+		}
+		limit = parsed
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	related := []models.RelatedSummary{}
+	if len(summary.Tags) > 0 {
+		related, err = h.summaryRepo.FindRelated(r.Context(), userID, id, limit)
+		if err != nil {
+			log.Printf("SummaryRelatedHandler.Related: summary %s: %v", id, err)
+			writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load related summaries", r))
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summary_id": id,
+		"summaries":  related,
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubSummaryRelatedRepo struct {
+	summary    *models.Summary
+	related    []models.RelatedSummary
+	relatedErr error
+	calls      int
+	gotLimit   int
+}
+
+func (s *stubSummaryRelatedRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error) {
+	if s.summary == nil {
+		return nil, context.Canceled
+	}
+	return s.summary, nil
+}
+
+func (s *stubSummaryRelatedRepo) FindRelated(ctx context.Context, userID, summaryID uuid.UUID, limit int) ([]models.RelatedSummary, error) {
+	s.calls++
+	s.gotLimit = limit
+	return s.related, s.relatedErr
+}
+
+func makeSummaryRelatedRequest(summaryID, userID uuid.UUID, query string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/summaries/"+summaryID.String()+"/related"+query, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryRelated_ReturnsRankedSummaries(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	repo := &stubSummaryRelatedRepo{
+		summary: &models.Summary{ID: summaryID, UserID: userID, Tags: []string{"biology", "cells"}},
+		related: []models.RelatedSummary{
+			{ID: uuid.New(), Title: "Mitosis", SharedTags: []string{"biology", "cells"}},
+			{ID: uuid.New(), Title: "Ecology", SharedTags: []string{"biology"}},
+		},
+	}
+	h := NewSummaryRelatedHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Related(rr, makeSummaryRelatedRequest(summaryID, userID, "?limit=3"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if repo.gotLimit != 3 {
+		t.Fatalf("expected limit 3, got %d", repo.gotLimit)
+	}
+	var body struct {
+		Summaries []models.RelatedSummary `json:"summaries"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Summaries) != 2 || body.Summaries[0].Title != "Mitosis" || len(body.Summaries[0].SharedTags) != 2 {
+		t.Fatalf("unexpected related summaries: %s", rr.Body.String())
+	}
+}
+
+func TestSummaryRelated_UntaggedSummarySkipsQuery(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	repo := &stubSummaryRelatedRepo{summary: &models.Summary{ID: summaryID, UserID: userID}}
+	h := NewSummaryRelatedHandler(repo)
+
+	rr := httptest.NewRecorder()
+	h.Related(rr, makeSummaryRelatedRequest(summaryID, userID, ""))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if repo.calls != 0 {
+		t.Fatalf("expected no lookup for a summary without tags")
+	}
+	var body struct {
+		Summaries []models.RelatedSummary `json:"summaries"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Summaries == nil {
+		t.Fatalf("expected an empty list, got %s", rr.Body.String())
+	}
+}
+
+func TestSummaryRelated_Errors(t *testing.T) {
+	ownerID := uuid.New()
+	summaryID := uuid.New()
+	tagged := &models.Summary{ID: summaryID, UserID: ownerID, Tags: []string{"biology"}}
+
+	tests := []struct {
+		name     string
+		repo     *stubSummaryRelatedRepo
+		userID   uuid.UUID
+		query    string
+		wantCode int
+	}{
+		{"not found", &stubSummaryRelatedRepo{}, ownerID, "", http.StatusNotFound},
+		{"not owner", &stubSummaryRelatedRepo{summary: tagged}, uuid.New(), "", http.StatusForbidden},
+		{"bad limit", &stubSummaryRelatedRepo{summary: tagged}, ownerID, "?limit=50", http.StatusBadRequest},
+		{"query fails", &stubSummaryRelatedRepo{summary: tagged, relatedErr: errors.New("db down")}, ownerID, "", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSummaryRelatedHandler(tt.repo)
+			rr := httptest.NewRecorder()
+			h.Related(rr, makeSummaryRelatedRequest(summaryID, tt.userID, tt.query))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+)
+
+func newSummaryRestoreRequest(summaryID, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/summaries/"+summaryID.String()+"/restore", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryHandler_Restore_OutsideWindowReturnsNotFound(t *testing.T) {
+	summaryID := uuid.New()
+	userID := uuid.New()
+	repo := &stubSummaryRepo{restored: false}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Restore(rr, newSummaryRestoreRequest(summaryID, userID))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+	if code := errorCodeFromBody(t, rr); code != "NOT_FOUND" {
+		t.Fatalf("expected NOT_FOUND, got %q", code)
+	}
+}
+
+func TestSummaryHandler_Restore_ScopesToCaller(t *testing.T) {
+	summaryID := uuid.New()
+	userID := uuid.New()
+	repo := &stubSummaryRepo{restored: true}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Restore(rr, newSummaryRestoreRequest(summaryID, userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if repo.lastID != summaryID || repo.lastUser != userID {
+		t.Fatalf("unexpected restore params: id=%s user=%s", repo.lastID, repo.lastUser)
+	}
+}
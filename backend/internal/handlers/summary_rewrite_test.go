@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+func newSummaryRewriteRequest(summaryID, userID uuid.UUID, body string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/summaries/"+summaryID.String()+"/rewrite", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryHandler_Rewrite_NonOwnerForbidden(t *testing.T) {
+	summaryID := uuid.New()
+	raw := "Some summary text"
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: uuid.New(), ContentRaw: &raw}}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Rewrite(rr, newSummaryRewriteRequest(summaryID, uuid.New(), `{"length":"concise"}`))
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestSummaryHandler_Rewrite_NoContentReturnsConflict(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: ownerID}}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Rewrite(rr, newSummaryRewriteRequest(summaryID, ownerID, `{"length":"concise"}`))
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+}
+
+func TestSummaryHandler_Rewrite_InvalidLengthReturnsValidationError(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	contentID := uuid.New()
+	raw := "Some summary text"
+	repo := &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: ownerID, ContentID: &contentID, ContentRaw: &raw, Format: "bullets"}}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	rr := httptest.NewRecorder()
+	h.Rewrite(rr, newSummaryRewriteRequest(summaryID, ownerID, `{"length":"tiny"}`))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	if code := errorCodeFromBody(t, rr); code != "VALIDATION_ERROR" {
+		t.Fatalf("expected VALIDATION_ERROR, got %q", code)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+const (
+	defaultSummarySearchLimit = 5
+	maxSummarySearchLimit     = 20
+	maxSummarySearchQuery     = 500
+)
+
+type summarySearchRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
+}
+
+type SummarySearchHandler struct {
+	summaryRepo summarySearchRepository
+	search      summarySearcher
+}
+
+func NewSummarySearchHandler(summaryRepo summarySearchRepository, search *services.SummarySearchService) *SummarySearchHandler {
+	return &SummarySearchHandler{summaryRepo: summaryRepo, search: search}
+}
+
+// Search returns the passages of a summary's source that best match ?q= by
+// meaning rather than wording.
+func (h *SummarySearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Query parameter q is required", r))
+		return
+	}
+	if len([]rune(query)) > maxSummarySearchQuery {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Query is too long", r))
+		return
+	}
+
+	limit := defaultSummarySearchLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxSummarySearchLimit {
+			writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Limit must be between 1 and 20", r))
+			return
+		}
+		limit = parsed
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return
+	}
+
+	userID := middleware.GetUserID(r.Context())
+	if summary.UserID != userID {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return
+	}
+
+	results, err := h.search.Search(services.WithUsageUser(r.Context(), userID), summary, query, limit)
+	if errors.Is(err, services.ErrSearchUnavailable) {
+		writeJSON(w, http.StatusServiceUnavailable, errorResp("SERVICE_UNAVAILABLE", "Semantic search is not available on this server", r))
+		return
+	}
+	if err != nil {
+		log.Printf("SummarySearchHandler.Search: summary %s: %v", id, err)
+		writeJSON(w, http.StatusBadGateway, errorResp("UPSTREAM_ERROR", "Failed to search summary", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summary_id": id,
+		"query":      query,
+		"results":    results,
+	})
+}
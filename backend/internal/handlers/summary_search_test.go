@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+type stubSummarySearcher struct {
+	results []models.SummarySearchResult
+	err     error
+	limit   int
+}
+
+func (s *stubSummarySearcher) Search(ctx context.Context, summary *models.Summary, query string, limit int) ([]models.SummarySearchResult, error) {
+	s.limit = limit
+	return s.results, s.err
+}
+
+func makeSummarySearchRequest(summaryID, userID uuid.UUID, rawQuery string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/summaries/"+summaryID.String()+"/search?"+rawQuery, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummarySearch_ReturnsResults(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	searcher := &stubSummarySearcher{results: []models.SummarySearchResult{{ChunkIndex: 3, Content: "Mitochondria make ATP", Score: 0.82}}}
+	h := &SummarySearchHandler{
+		summaryRepo: &stubSummaryHTMLRepo{summary: &models.Summary{ID: summaryID, UserID: userID}},
+		search:      searcher,
+	}
+
+	rr := httptest.NewRecorder()
+	h.Search(rr, makeSummarySearchRequest(summaryID, userID, "q=energy&limit=3"))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var body struct {
+		Results []models.SummarySearchResult `json:"results"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Results) != 1 || body.Results[0].ChunkIndex != 3 {
+		t.Fatalf("unexpected results %+v", body.Results)
+	}
+	if searcher.limit != 3 {
+		t.Fatalf("expected limit 3 to be passed through, got %d", searcher.limit)
+	}
+}
+
+func TestSummarySearch_Errors(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+
+	tests := []struct {
+		name       string
+		owner      uuid.UUID
+		query      string
+		searchErr  error
+		wantStatus int
+	}{
+		{name: "missing query", owner: userID, query: "", wantStatus: http.StatusBadRequest},
+		{name: "bad limit", owner: userID, query: "q=cells&limit=50", wantStatus: http.StatusBadRequest},
+		{name: "not owner", owner: uuid.New(), query: "q=cells", wantStatus: http.StatusForbidden},
+		{name: "no pgvector", owner: userID, query: "q=cells", searchErr: services.ErrSearchUnavailable, wantStatus: http.StatusServiceUnavailable},
+		{name: "embedding failure", owner: userID, query: "q=cells", searchErr: context.DeadlineExceeded, wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &SummarySearchHandler{
+				summaryRepo: &stubSummaryHTMLRepo{summary: &models.Summary{ID: summaryID, UserID: tt.owner}},
+				search:      &stubSummarySearcher{err: tt.searchErr},
+			}
+
+			rr := httptest.NewRecorder()
+			h.Search(rr, makeSummarySearchRequest(summaryID, userID, tt.query))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
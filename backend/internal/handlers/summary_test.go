@@ -21,13 +21,16 @@ type stubSummaryRepoForUpdate struct {
 	getErr         error
 	updated        bool
 	updatedSummary *models.Summary
+
+	listFavoriteOnly bool
 }
 
 func (s *stubSummaryRepoForUpdate) Create(ctx context.Context, summary *models.Summary) error {
 	return nil
 }
 
-func (s *stubSummaryRepoForUpdate) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, limit, offset int) ([]*models.Summary, int, error) {
+func (s *stubSummaryRepoForUpdate) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Summary, int, error) {
+	s.listFavoriteOnly = favoriteOnly
 	return nil, 0, nil
 }
 
@@ -57,6 +60,18 @@ func (s *stubSummaryRepoForUpdate) ToggleFavorite(ctx context.Context, id uuid.U
 	return nil
 }
 
+func (s *stubSummaryRepoForUpdate) SetArchived(ctx context.Context, id uuid.UUID, userID uuid.UUID, archived bool) error {
+	return nil
+}
+
+func (s *stubSummaryRepoForUpdate) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (s *stubSummaryRepoForUpdate) UpdateRawContent(ctx context.Context, id uuid.UUID, raw string, wordCount int, isQualityFallback bool, qualityFallbackReason *string) error {
+	return nil
+}
+
 func TestSummaryUpdate_MalformedBody_Returns400(t *testing.T) {
 	userID := uuid.New()
 	summaryID := uuid.New()
@@ -185,3 +200,108 @@ func TestSummaryUpdate_NotFound_Returns404(t *testing.T) {
 		t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
 	}
 }
+
+func TestSummaryList_FavoriteFilter_PassedToRepo(t *testing.T) {
+	repo := &stubSummaryRepoForUpdate{}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/summaries?favorite=true", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	rr := httptest.NewRecorder()
+
+	h.List(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if !repo.listFavoriteOnly {
+		t.Fatal("expected favorite filter to be passed to the repository")
+	}
+}
+
+func TestSummaryUpdate_StripsMarkupAndValidatesTags(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	repo := &stubSummaryRepoForUpdate{summary: &models.Summary{ID: summaryID, UserID: userID, Title: "Old"}}
+	h := &SummaryHandler{summaryRepo: repo}
+
+	makeReq := func(body string) *http.Request {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", summaryID.String())
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/summaries/"+summaryID.String(), strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	}
+
+	rr := httptest.NewRecorder()
+	h.Update(rr, makeReq(`{"title":"<script>alert(1)</script>Cell   <b>Biology</b>","tags":["<i>bio</i>","Bio"," "]}`))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if repo.updatedSummary.Title != "alert(1)Cell Biology" {
+		t.Fatalf("expected markup to be stripped from title, got %q", repo.updatedSummary.Title)
+	}
+	if len(repo.updatedSummary.Tags) != 1 || repo.updatedSummary.Tags[0] != "bio" {
+		t.Fatalf("expected sanitized, deduplicated tags, got %#v", repo.updatedSummary.Tags)
+	}
+
+	repo.updated = false
+	rr = httptest.NewRecorder()
+	h.Update(rr, makeReq(`{"tags":["a","b","c","d","e","f","g","h","i","j","k"]}`))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d for too many tags, got %d", http.StatusBadRequest, rr.Code)
+	}
+	if repo.updated {
+		t.Fatal("expected no update when tags are invalid")
+	}
+}
+
+func TestSummaryHandler_Get_TruncationNotice(t *testing.T) {
+	summaryID := uuid.New()
+	ownerID := uuid.New()
+	coverage := 62
+
+	tests := []struct {
+		name    string
+		summary *models.Summary
+		want    string
+	}{
+		{"full transcript", &models.Summary{ID: summaryID, UserID: ownerID}, ""},
+		{"truncated transcript", &models.Summary{ID: summaryID, UserID: ownerID, SourceTruncated: true, SourceCoveragePercent: &coverage}, "first 62%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &SummaryHandler{summaryRepo: &stubSummaryRepo{summary: tt.summary}}
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", summaryID.String())
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/summaries/"+summaryID.String(), nil)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, ownerID))
+
+			rr := httptest.NewRecorder()
+			h.Get(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			var body struct {
+				SourceTruncated  bool   `json:"source_truncated"`
+				TruncationNotice string `json:"truncation_notice"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.want == "" {
+				if body.SourceTruncated || body.TruncationNotice != "" {
+					t.Fatalf("expected no truncation notice, got %+v", body)
+				}
+				return
+			}
+			if !body.SourceTruncated || !strings.Contains(body.TruncationNotice, tt.want) {
+				t.Fatalf("expected a notice mentioning %q, got %+v", tt.want, body)
+			}
+		})
+	}
+}
@@ -19,13 +19,19 @@ type stubSummaryRepo struct {
 	toggled  bool
 	lastID   uuid.UUID
 	lastUser uuid.UUID
+	archived *bool
+	restored bool
+
+	rawContent      *string
+	wordCount       int
+	qualityFallback bool
 }
 
 func (s *stubSummaryRepo) Create(ctx context.Context, summary *models.Summary) error {
 	return nil
 }
 
-func (s *stubSummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, limit, offset int) ([]*models.Summary, int, error) {
+func (s *stubSummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Summary, int, error) {
 	return nil, 0, nil
 }
 
@@ -55,6 +61,27 @@ func (s *stubSummaryRepo) ToggleFavorite(ctx context.Context, id uuid.UUID, user
 	return nil
 }
 
+func (s *stubSummaryRepo) SetArchived(ctx context.Context, id uuid.UUID, userID uuid.UUID, archived bool) error {
+	s.archived = &archived
+	s.lastID = id
+	s.lastUser = userID
+	return nil
+}
+
+func (s *stubSummaryRepo) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	s.lastID = id
+	s.lastUser = userID
+	return s.restored, nil
+}
+
+func (s *stubSummaryRepo) UpdateRawContent(ctx context.Context, id uuid.UUID, raw string, wordCount int, isQualityFallback bool, qualityFallbackReason *string) error {
+	s.lastID = id
+	s.rawContent = &raw
+	s.wordCount = wordCount
+	s.qualityFallback = isQualityFallback
+	return nil
+}
+
 func TestSummaryHandler_ToggleFavorite_Authorization(t *testing.T) {
 	summaryID := uuid.New()
 	ownerID := uuid.New()
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type summaryVersionSummaryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Summary, error)
+}
+
+type summaryVersionRepository interface {
+	List(ctx context.Context, summaryID uuid.UUID) ([]models.SummaryVersion, error)
+	Restore(ctx context.Context, summaryID uuid.UUID, version, keep int) error
+}
+
+type SummaryVersionHandler struct {
+	summaryRepo  summaryVersionSummaryRepository
+	versionRepo  summaryVersionRepository
+	versionLimit int
+}
+
+func NewSummaryVersionHandler(summaryRepo summaryVersionSummaryRepository, versionRepo summaryVersionRepository, keep int) *SummaryVersionHandler {
+	return &SummaryVersionHandler{summaryRepo: summaryRepo, versionRepo: versionRepo, versionLimit: keep}
+}
+
+// ownedSummary loads the summary named in the URL and checks the caller owns
+// it, writing the error response when not.
+func (h *SummaryVersionHandler) ownedSummary(w http.ResponseWriter, r *http.Request) (*models.Summary, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid summary ID", r))
+		return nil, false
+	}
+
+	summary, err := h.summaryRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Summary not found", r))
+		return nil, false
+	}
+
+	if summary.UserID != middleware.GetUserID(r.Context()) {
+		writeJSON(w, http.StatusForbidden, errorResp("FORBIDDEN", "Access denied", r))
+		return nil, false
+	}
+	return summary, true
+}
+
+// List returns the summary's earlier versions, newest first.
+func (h *SummaryVersionHandler) List(w http.ResponseWriter, r *http.Request) {
+	summary, ok := h.ownedSummary(w, r)
+	if !ok {
+		return
+	}
+
+	versions, err := h.versionRepo.List(r.Context(), summary.ID)
+	if err != nil {
+		log.Printf("SummaryVersionHandler.List: summary %s: %v", summary.ID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load summary versions", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summary_id": summary.ID,
+		"versions":   versions,
+	})
+}
+
+// Restore makes an earlier version the summary's body again. The body it
+// replaces is kept as the newest version.
+func (h *SummaryVersionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		writeJSON(w, http.StatusBadRequest, errorResp("VALIDATION_ERROR", "Invalid version", r))
+		return
+	}
+
+	summary, ok := h.ownedSummary(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.versionRepo.Restore(r.Context(), summary.ID, version, h.versionLimit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusNotFound, errorResp("NOT_FOUND", "Version not found", r))
+		return
+	}
+	if err != nil {
+		log.Printf("SummaryVersionHandler.Restore: summary %s version %d: %v", summary.ID, version, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to restore summary version", r))
+		return
+	}
+
+	restored, err := h.summaryRepo.GetByID(r.Context(), summary.ID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load summary", r))
+		return
+	}
+	writeJSON(w, http.StatusOK, restored)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubSummaryVersionRepo struct {
+	versions    []models.SummaryVersion
+	restoreErr  error
+	restored    int
+	restoreKeep int
+	snapshotErr error
+	snapshots   int
+}
+
+func (s *stubSummaryVersionRepo) List(ctx context.Context, summaryID uuid.UUID) ([]models.SummaryVersion, error) {
+	return s.versions, nil
+}
+
+func (s *stubSummaryVersionRepo) Restore(ctx context.Context, summaryID uuid.UUID, version, keep int) error {
+	if s.restoreErr != nil {
+		return s.restoreErr
+	}
+	s.restored = version
+	s.restoreKeep = keep
+	return nil
+}
+
+func (s *stubSummaryVersionRepo) Snapshot(ctx context.Context, summaryID uuid.UUID, keep int) (bool, error) {
+	s.snapshots++
+	return s.snapshotErr == nil, s.snapshotErr
+}
+
+func makeSummaryVersionRequest(method, summaryID, version string, userID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID)
+	path := "/api/v1/summaries/" + summaryID + "/versions"
+	if version != "" {
+		rctx.URLParams.Add("version", version)
+		path += "/" + version + "/restore"
+	}
+	req := httptest.NewRequest(method, path, nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestSummaryVersions_List(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	body := "Earlier body"
+	versions := &stubSummaryVersionRepo{versions: []models.SummaryVersion{
+		{Version: 2, Format: "bullets", ContentRaw: &body, WordCount: 2},
+		{Version: 1, Format: "cornell"},
+	}}
+	h := NewSummaryVersionHandler(&stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: userID}}, versions, 5)
+
+	rr := httptest.NewRecorder()
+	h.List(rr, makeSummaryVersionRequest(http.MethodGet, summaryID.String(), "", userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Versions []models.SummaryVersion `json:"versions"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Versions) != 2 || resp.Versions[0].Version != 2 || *resp.Versions[0].ContentRaw != body {
+		t.Fatalf("unexpected versions: %s", rr.Body.String())
+	}
+}
+
+func TestSummaryVersions_Restore(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	raw := "Restored body"
+	versions := &stubSummaryVersionRepo{}
+	h := NewSummaryVersionHandler(&stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: userID, ContentRaw: &raw}}, versions, 3)
+
+	rr := httptest.NewRecorder()
+	h.Restore(rr, makeSummaryVersionRequest(http.MethodPost, summaryID.String(), "2", userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if versions.restored != 2 || versions.restoreKeep != 3 {
+		t.Fatalf("expected version 2 restored keeping 3, got %d keeping %d", versions.restored, versions.restoreKeep)
+	}
+	var summary models.Summary
+	if err := json.Unmarshal(rr.Body.Bytes(), &summary); err != nil || summary.ContentRaw == nil || *summary.ContentRaw != raw {
+		t.Fatalf("expected the restored summary in the response, got %s", rr.Body.String())
+	}
+}
+
+func TestSummaryVersions_RestoreErrors(t *testing.T) {
+	ownerID := uuid.New()
+	summaryID := uuid.New()
+	owned := &models.Summary{ID: summaryID, UserID: ownerID}
+
+	tests := []struct {
+		name     string
+		summary  *models.Summary
+		userID   uuid.UUID
+		version  string
+		repoErr  error
+		wantCode int
+	}{
+		{"invalid version", owned, ownerID, "latest", nil, http.StatusBadRequest},
+		{"zero version", owned, ownerID, "0", nil, http.StatusBadRequest},
+		{"summary not found", nil, ownerID, "1", nil, http.StatusNotFound},
+		{"not owner", owned, uuid.New(), "1", nil, http.StatusForbidden},
+		{"version not found", owned, ownerID, "9", pgx.ErrNoRows, http.StatusNotFound},
+		{"restore fails", owned, ownerID, "1", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSummaryVersionHandler(&stubSummaryRepo{summary: tt.summary}, &stubSummaryVersionRepo{restoreErr: tt.repoErr}, 5)
+			rr := httptest.NewRecorder()
+			h.Restore(rr, makeSummaryVersionRequest(http.MethodPost, summaryID.String(), tt.version, tt.userID))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSummaryHandler_Regenerate_SnapshotFailureStopsRegeneration(t *testing.T) {
+	userID := uuid.New()
+	summaryID := uuid.New()
+	raw := "Current body"
+	versions := &stubSummaryVersionRepo{snapshotErr: errors.New("db down")}
+	h := &SummaryHandler{summaryRepo: &stubSummaryRepo{summary: &models.Summary{ID: summaryID, UserID: userID, ContentRaw: &raw, Format: "bullets"}}}
+	h.SetVersionHistory(versions, 5)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", summaryID.String())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/summaries/"+summaryID.String()+"/regenerate", strings.NewReader(`{}`))
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+	rr := httptest.NewRecorder()
+	h.Regenerate(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if versions.snapshots != 1 {
+		t.Fatalf("expected one snapshot attempt, got %d", versions.snapshots)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/repository"
+)
+
+type trashRepository interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error)
+}
+
+type TrashHandler struct {
+	trashRepo trashRepository
+}
+
+func NewTrashHandler(trashRepo trashRepository) *TrashHandler {
+	return &TrashHandler{trashRepo: trashRepo}
+}
+
+// List returns the caller's soft-deleted summaries, quizzes and decks that can
+// still be restored.
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+
+	items, err := h.trashRepo.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("TrashHandler.List: failed to list trash for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to retrieve trash", r))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items":          items,
+		"retention_days": int(repository.TrashRetention.Hours() / 24),
+	})
+}
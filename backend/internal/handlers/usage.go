@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type usageRepository interface {
+	OperationTotals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.UsageOperationStats, error)
+}
+
+type UsageHandler struct {
+	repo usageRepository
+	now  func() time.Time
+}
+
+func NewUsageHandler(repo usageRepository) *UsageHandler {
+	return &UsageHandler{repo: repo, now: time.Now}
+}
+
+// usageMonth returns the UTC calendar month containing t.
+func usageMonth(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// GetAIUsage reports the current user's Gemini requests and tokens for this
+// calendar month, broken down by operation type.
+func (h *UsageHandler) GetAIUsage(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	from, to := usageMonth(h.now())
+
+	operations, err := h.repo.OperationTotals(r.Context(), userID, from, to)
+	if err != nil {
+		log.Printf("UsageHandler.GetAIUsage: failed for user %s: %v", userID, err)
+		writeJSON(w, http.StatusInternalServerError, errorResp("INTERNAL_ERROR", "Failed to load usage", r))
+		return
+	}
+
+	report := models.UsageReport{PeriodStart: from, PeriodEnd: to, Operations: operations}
+	if report.Operations == nil {
+		report.Operations = []models.UsageOperationStats{}
+	}
+	for _, op := range report.Operations {
+		report.TotalRequests += op.Requests
+		report.TotalPromptTokens += op.PromptTokens
+		report.TotalCompletionTokens += op.CompletionTokens
+		report.TotalTokens += op.TotalTokens
+	}
+	writeJSON(w, http.StatusOK, report)
+}
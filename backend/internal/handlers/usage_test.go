@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+type stubUsageRepo struct {
+	stats    []models.UsageOperationStats
+	err      error
+	userID   uuid.UUID
+	from, to time.Time
+}
+
+func (s *stubUsageRepo) OperationTotals(_ context.Context, userID uuid.UUID, from, to time.Time) ([]models.UsageOperationStats, error) {
+	s.userID, s.from, s.to = userID, from, to
+	return s.stats, s.err
+}
+
+func usageRequest(userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/usage/ai", nil)
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+}
+
+func TestGetAIUsage_ReportsCurrentMonthTotals(t *testing.T) {
+	repo := &stubUsageRepo{stats: []models.UsageOperationStats{
+		{Operation: "summary", Requests: 3, PromptTokens: 9000, CompletionTokens: 3000, TotalTokens: 12000},
+		{Operation: "chat", Requests: 5, PromptTokens: 2000, CompletionTokens: 500, TotalTokens: 2500},
+	}}
+	h := NewUsageHandler(repo)
+	h.now = func() time.Time { return time.Date(2026, 3, 17, 10, 0, 0, 0, time.UTC) }
+	userID := uuid.New()
+
+	rr := httptest.NewRecorder()
+	h.GetAIUsage(rr, usageRequest(userID))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if repo.userID != userID {
+		t.Fatalf("queried user %s, want %s", repo.userID, userID)
+	}
+	if !repo.from.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !repo.to.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("period = [%s, %s), want March 2026", repo.from, repo.to)
+	}
+
+	var report models.UsageReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.TotalRequests != 8 || report.TotalTokens != 14500 || len(report.Operations) != 2 ||
+		report.TotalPromptTokens != 11000 || report.TotalCompletionTokens != 3500 {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+func TestGetAIUsage_EmptyMonthReturnsEmptyList(t *testing.T) {
+	h := NewUsageHandler(&stubUsageRepo{})
+
+	rr := httptest.NewRecorder()
+	h.GetAIUsage(rr, usageRequest(uuid.New()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	var body map[string]any
+	_ = json.Unmarshal(rr.Body.Bytes(), &body)
+	if ops, ok := body["operations"].([]any); !ok || len(ops) != 0 {
+		t.Fatalf("operations = %v, want []", body["operations"])
+	}
+}
+
+func TestGetAIUsage_RepoErrorIs500(t *testing.T) {
+	h := NewUsageHandler(&stubUsageRepo{err: errors.New("db down")})
+
+	rr := httptest.NewRecorder()
+	h.GetAIUsage(rr, usageRequest(uuid.New()))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rr.Code)
+	}
+}
+
+func TestUsageMonth_CrossesYear(t *testing.T) {
+	from, to := usageMonth(time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC))
+	if from.Month() != time.December || to.Year() != 2026 || to.Month() != time.January {
+		t.Fatalf("usageMonth = [%s, %s)", from, to)
+	}
+}
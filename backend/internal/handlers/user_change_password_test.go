@@ -78,6 +78,18 @@ func (s *stubUserRepoForPassword) SetNotificationSetting(ctx context.Context, us
 	return nil
 }
 
+func (s *stubUserRepoForPassword) GetNotificationSchedule(ctx context.Context, userID uuid.UUID) (models.NotificationSchedule, error) {
+	return models.NotificationSchedule{}, nil
+}
+
+func (s *stubUserRepoForPassword) SetNotificationSchedule(ctx context.Context, userID uuid.UUID, schedule models.NotificationSchedule) error {
+	return nil
+}
+
+func (s *stubUserRepoForPassword) GetAccountSummary(ctx context.Context, userID uuid.UUID) (*models.AccountSummary, error) {
+	return nil, nil
+}
+
 func (s *stubUserRepoForPassword) UpdateNotificationTimestamps(ctx context.Context, userID uuid.UUID, updates map[string]string) error {
 	return nil
 }
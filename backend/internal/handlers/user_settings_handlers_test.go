@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"lectura-backend/internal/middleware"
 	"lectura-backend/internal/models"
@@ -23,6 +26,11 @@ type stubUserRepoForSettingsHandlers struct {
 	updatedUser     bool
 	deletedUser     bool
 	updatedSettings bool
+	savedSchedule   *models.NotificationSchedule
+	accountSummary  *models.AccountSummary
+	accountErr      error
+	settings        *models.UserSettings
+	savedSettings   *models.UserSettings
 }
 
 func (s *stubUserRepoForSettingsHandlers) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
@@ -47,11 +55,16 @@ func (s *stubUserRepoForSettingsHandlers) Delete(ctx context.Context, id uuid.UU
 }
 
 func (s *stubUserRepoForSettingsHandlers) GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error) {
+	if s.settings != nil {
+		copied := *s.settings
+		return &copied, nil
+	}
 	return &models.UserSettings{UserID: userID}, nil
 }
 
 func (s *stubUserRepoForSettingsHandlers) UpdateSettings(ctx context.Context, settings *models.UserSettings) error {
 	s.updatedSettings = true
+	s.savedSettings = settings
 	return s.updateSettingsErr
 }
 
@@ -59,6 +72,22 @@ func (s *stubUserRepoForSettingsHandlers) SetNotificationSetting(ctx context.Con
 	return nil
 }
 
+func (s *stubUserRepoForSettingsHandlers) GetNotificationSchedule(ctx context.Context, userID uuid.UUID) (models.NotificationSchedule, error) {
+	return models.NotificationSchedule{}, nil
+}
+
+func (s *stubUserRepoForSettingsHandlers) SetNotificationSchedule(ctx context.Context, userID uuid.UUID, schedule models.NotificationSchedule) error {
+	s.savedSchedule = &schedule
+	return nil
+}
+
+func (s *stubUserRepoForSettingsHandlers) GetAccountSummary(ctx context.Context, userID uuid.UUID) (*models.AccountSummary, error) {
+	if s.accountErr != nil {
+		return nil, s.accountErr
+	}
+	return s.accountSummary, nil
+}
+
 func TestUserHandler_UpdateMe_InvalidRequestBody(t *testing.T) {
 	userID := uuid.New()
 	repo := &stubUserRepoForSettingsHandlers{
@@ -204,6 +233,60 @@ func TestUserHandler_UpdateSettings_RepoFailure(t *testing.T) {
 	}
 }
 
+func TestUserHandler_UpdateSettings_KeepsOmittedFields(t *testing.T) {
+	userID := uuid.New()
+	cards := 30
+	repo := &stubUserRepoForSettingsHandlers{
+		user: &models.User{ID: userID},
+		settings: &models.UserSettings{
+			UserID:               userID,
+			DefaultSummaryLength: "detailed",
+			DefaultFormat:        "bullets",
+			DefaultDifficulty:    "hard",
+			Language:             "de",
+			DefaultNumCards:      &cards,
+		},
+	}
+	h := &UserHandler{userRepo: repo}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/user/settings", strings.NewReader(`{"default_num_questions":15}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	rr := httptest.NewRecorder()
+	h.UpdateSettings(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	saved := repo.savedSettings
+	if saved == nil || saved.DefaultNumQuestions == nil || *saved.DefaultNumQuestions != 15 {
+		t.Fatalf("expected default_num_questions to be saved, got %#v", saved)
+	}
+	if saved.DefaultFormat != "bullets" || saved.DefaultSummaryLength != "detailed" || saved.Language != "de" {
+		t.Fatalf("expected omitted fields to be kept, got %#v", saved)
+	}
+	if saved.DefaultNumCards == nil || *saved.DefaultNumCards != 30 {
+		t.Fatalf("expected default_num_cards to be kept, got %v", saved.DefaultNumCards)
+	}
+}
+
+func TestUserHandler_UpdateSettings_RejectsOutOfRangeCounts(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubUserRepoForSettingsHandlers{user: &models.User{ID: userID}}
+	h := &UserHandler{userRepo: repo}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/user/settings", strings.NewReader(`{"default_num_questions":0,"default_num_cards":500}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	rr := httptest.NewRecorder()
+	h.UpdateSettings(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	if repo.updatedSettings {
+		t.Fatalf("settings should not be updated for invalid counts")
+	}
+}
+
 func TestUserHandler_DeleteMe_RepoFailure(t *testing.T) {
 	userID := uuid.New()
 	repo := &stubUserRepoForSettingsHandlers{
@@ -225,3 +308,101 @@ func TestUserHandler_DeleteMe_RepoFailure(t *testing.T) {
 		t.Fatalf("expected delete to be attempted")
 	}
 }
+
+func TestUserHandler_UpdateNotificationSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "timezone and quiet hours", body: `{"timezone":"Europe/Berlin","quiet_hours_start":22,"quiet_hours_end":7}`, wantStatus: http.StatusOK},
+		{name: "no quiet hours", body: `{"timezone":"America/New_York"}`, wantStatus: http.StatusOK},
+		{name: "unknown timezone", body: `{"timezone":"Mars/Olympus"}`, wantStatus: http.StatusBadRequest},
+		{name: "start without end", body: `{"timezone":"UTC","quiet_hours_start":22}`, wantStatus: http.StatusBadRequest},
+		{name: "hour out of range", body: `{"timezone":"UTC","quiet_hours_start":22,"quiet_hours_end":24}`, wantStatus: http.StatusBadRequest},
+		{name: "empty window", body: `{"timezone":"UTC","quiet_hours_start":8,"quiet_hours_end":8}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			repo := &stubUserRepoForSettingsHandlers{}
+			h := &UserHandler{userRepo: repo}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/user/notifications/schedule", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+			rr := httptest.NewRecorder()
+			h.UpdateNotificationSchedule(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if saved := repo.savedSchedule != nil; saved != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("schedule saved = %v, want %v", saved, tt.wantStatus == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestUserHandler_GetNotificationSchedule_DefaultsToUTC(t *testing.T) {
+	h := &UserHandler{userRepo: &stubUserRepoForSettingsHandlers{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/notifications/schedule", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+
+	rr := httptest.NewRecorder()
+	h.GetNotificationSchedule(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"timezone":"UTC"`) {
+		t.Fatalf("expected UTC default, got %s", rr.Body.String())
+	}
+}
+
+func TestUserHandler_GetAccountSummary(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubUserRepoForSettingsHandlers{
+		accountSummary: &models.AccountSummary{
+			Summaries:    4,
+			Plan:         "pro",
+			StorageBytes: 2048,
+			CreatedAt:    time.Now().Add(-72 * time.Hour),
+		},
+	}
+	h := &UserHandler{userRepo: repo}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/account-summary", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	rr := httptest.NewRecorder()
+	h.GetAccountSummary(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var resp models.AccountSummary
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Summaries != 4 || resp.Plan != "pro" || resp.StorageBytes != 2048 {
+		t.Fatalf("unexpected summary %+v", resp)
+	}
+	if resp.AccountAgeDays != 3 {
+		t.Fatalf("expected account age of 3 days, got %d", resp.AccountAgeDays)
+	}
+}
+
+func TestUserHandler_GetAccountSummary_UnknownUser(t *testing.T) {
+	h := &UserHandler{userRepo: &stubUserRepoForSettingsHandlers{accountErr: pgx.ErrNoRows}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/account-summary", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+	rr := httptest.NewRecorder()
+	h.GetAccountSummary(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
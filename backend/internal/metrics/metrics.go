@@ -0,0 +1,220 @@
+// Package metrics keeps worker and Gemini counters and renders them, together
+// with gauges sampled at scrape time, in the Prometheus text format served at
+// /metrics.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jobDurationBuckets are the upper bounds, in seconds, of the job duration
+// histogram. Flashcard jobs finish in seconds; long presentations take
+// several minutes.
+var jobDurationBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200}
+
+// geminiCallBuckets are the upper bounds, in seconds, of the Gemini call
+// duration histogram. Chat replies take a second or two; summaries of long
+// transcripts and audio transcription take minutes.
+var geminiCallBuckets = []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300}
+
+// Sample is one value of a gauge. Labels are written in the order given.
+type Sample struct {
+	Labels [][2]string
+	Value  float64
+}
+
+// GaugeFunc samples a gauge when /metrics is scraped. Samples that cannot be
+// taken (Redis down, ...) are left out rather than reported as zero.
+type GaugeFunc func(ctx context.Context) []Sample
+
+type gauge struct {
+	name, help string
+	collect    GaugeFunc
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative; the last is +Inf
+	sum    float64
+	total  uint64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	i := sort.SearchFloat64s(buckets, v)
+	h.counts[i]++
+	h.sum += v
+	h.total++
+}
+
+// Registry holds the counters recorded by workers and the gauges registered
+// at startup.
+type Registry struct {
+	mu            sync.Mutex
+	jobsProcessed map[string]uint64
+	jobsFailed    map[string]uint64
+	jobDurations  map[string]*histogram
+	geminiCalls   map[string]*histogram
+	gauges        []gauge
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		jobsProcessed: make(map[string]uint64),
+		jobsFailed:    make(map[string]uint64),
+		jobDurations:  make(map[string]*histogram),
+		geminiCalls:   make(map[string]*histogram),
+	}
+}
+
+// Default is the registry written by the /metrics endpoint.
+var Default = NewRegistry()
+
+// ObserveJob records one finished job attempt of jobType. A non-nil err
+// counts the attempt as failed, whether or not it will be retried.
+func (r *Registry) ObserveJob(jobType string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobsProcessed[jobType]++
+	if err != nil {
+		r.jobsFailed[jobType]++
+	}
+	observeIn(r.jobDurations, jobDurationBuckets, jobType, duration)
+}
+
+// ObserveGeminiCall records how long one request to Gemini took, whether it
+// succeeded or not. call names the kind of request (generate, stream, chat,
+// embed); a request retried after a 429 is recorded once per attempt.
+func (r *Registry) ObserveGeminiCall(call string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	observeIn(r.geminiCalls, geminiCallBuckets, call, duration)
+}
+
+func observeIn(hists map[string]*histogram, buckets []float64, key string, duration time.Duration) {
+	h, ok := hists[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(buckets)+1)}
+		hists[key] = h
+	}
+	h.observe(buckets, duration.Seconds())
+}
+
+// RegisterGauge adds a gauge sampled by collect on every scrape. Gauges are
+// written in registration order.
+func (r *Registry) RegisterGauge(name, help string, collect GaugeFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gauges = append(r.gauges, gauge{name: name, help: help, collect: collect})
+}
+
+// ObserveJob records a job attempt in the Default registry.
+func ObserveJob(jobType string, duration time.Duration, err error) {
+	Default.ObserveJob(jobType, duration, err)
+}
+
+// ObserveGeminiCall records a Gemini request in the Default registry.
+func ObserveGeminiCall(call string, duration time.Duration) {
+	Default.ObserveGeminiCall(call, duration)
+}
+
+// RegisterGauge registers a gauge in the Default registry.
+func RegisterGauge(name, help string, collect GaugeFunc) {
+	Default.RegisterGauge(name, help, collect)
+}
+
+// WritePrometheus writes every metric in the Prometheus text format. Gauges
+// are sampled with ctx, so a slow collector is bounded by the scrape request.
+func (r *Registry) WritePrometheus(ctx context.Context, w io.Writer) {
+	r.mu.Lock()
+	processed := copyCounts(r.jobsProcessed)
+	failed := copyCounts(r.jobsFailed)
+	durations := copyHistograms(r.jobDurations)
+	geminiCalls := copyHistograms(r.geminiCalls)
+	gauges := append([]gauge(nil), r.gauges...)
+	r.mu.Unlock()
+
+	writeCounter(w, "lectura_jobs_processed_total", "Job attempts finished by workers, by job type", processed)
+	writeCounter(w, "lectura_jobs_failed_total", "Job attempts that returned an error, by job type", failed)
+
+	writeHistogram(w, "lectura_job_duration_seconds", "Time spent processing one job attempt, by job type", "type", jobDurationBuckets, durations)
+	writeHistogram(w, "lectura_gemini_call_duration_seconds", "Time spent waiting on one Gemini request, by kind of call", "call", geminiCallBuckets, geminiCalls)
+
+	for _, g := range gauges {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n", g.name, g.help)
+		_, _ = fmt.Fprintf(w, "# TYPE %s gauge\n", g.name)
+		for _, s := range g.collect(ctx) {
+			_, _ = fmt.Fprintf(w, "%s%s %g\n", g.name, formatLabels(s.Labels), s.Value)
+		}
+	}
+}
+
+// WritePrometheus writes the Default registry.
+func WritePrometheus(ctx context.Context, w io.Writer) {
+	Default.WritePrometheus(ctx, w)
+}
+
+func writeCounter(w io.Writer, name, help string, counts map[string]uint64) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	_, _ = fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, jobType := range sortedKeys(counts) {
+		_, _ = fmt.Fprintf(w, "%s{type=%q} %d\n", name, jobType, counts[jobType])
+	}
+}
+
+func writeHistogram(w io.Writer, name, help, label string, buckets []float64, hists map[string]histogram) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	_, _ = fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, key := range sortedKeys(hists) {
+		h := hists[key]
+		var cumulative uint64
+		for i, bound := range buckets {
+			cumulative += h.counts[i]
+			_, _ = fmt.Fprintf(w, "%s_bucket{%s=%q,le=\"%g\"} %d\n", name, label, key, bound, cumulative)
+		}
+		_, _ = fmt.Fprintf(w, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", name, label, key, h.total)
+		_, _ = fmt.Fprintf(w, "%s_sum{%s=%q} %g\n", name, label, key, h.sum)
+		_, _ = fmt.Fprintf(w, "%s_count{%s=%q} %d\n", name, label, key, h.total)
+	}
+}
+
+func formatLabels(labels [][2]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = fmt.Sprintf("%s=%q", l[0], l[1])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func copyCounts(m map[string]uint64) map[string]uint64 {
+	out := make(map[string]uint64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func copyHistograms(m map[string]*histogram) map[string]histogram {
+	out := make(map[string]histogram, len(m))
+	for k, h := range m {
+		out[k] = histogram{counts: append([]uint64(nil), h.counts...), sum: h.sum, total: h.total}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheus_JobCountersAndHistogram(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveJob("quiz-generation", 3*time.Second, nil)
+	r.ObserveJob("quiz-generation", 90*time.Second, errors.New("gemini timeout"))
+
+	var out bytes.Buffer
+	r.WritePrometheus(context.Background(), &out)
+	text := out.String()
+
+	for _, want := range []string{
+		`lectura_jobs_processed_total{type="quiz-generation"} 2`,
+		`lectura_jobs_failed_total{type="quiz-generation"} 1`,
+		`lectura_job_duration_seconds_bucket{type="quiz-generation",le="1"} 0`,
+		`lectura_job_duration_seconds_bucket{type="quiz-generation",le="5"} 1`,
+		`lectura_job_duration_seconds_bucket{type="quiz-generation",le="120"} 2`,
+		`lectura_job_duration_seconds_bucket{type="quiz-generation",le="+Inf"} 2`,
+		`lectura_job_duration_seconds_sum{type="quiz-generation"} 93`,
+		`lectura_job_duration_seconds_count{type="quiz-generation"} 2`,
+		"# TYPE lectura_job_duration_seconds histogram",
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, text)
+		}
+	}
+}
+
+func TestWritePrometheus_GeminiCallHistogram(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveGeminiCall("chat", 1500*time.Millisecond)
+	r.ObserveGeminiCall("generate", 45*time.Second)
+
+	var out bytes.Buffer
+	r.WritePrometheus(context.Background(), &out)
+	text := out.String()
+
+	for _, want := range []string{
+		"# TYPE lectura_gemini_call_duration_seconds histogram",
+		`lectura_gemini_call_duration_seconds_bucket{call="chat",le="1"} 0`,
+		`lectura_gemini_call_duration_seconds_bucket{call="chat",le="2"} 1`,
+		`lectura_gemini_call_duration_seconds_bucket{call="generate",le="30"} 0`,
+		`lectura_gemini_call_duration_seconds_bucket{call="generate",le="60"} 1`,
+		`lectura_gemini_call_duration_seconds_sum{call="generate"} 45`,
+		`lectura_gemini_call_duration_seconds_count{call="chat"} 1`,
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("missing %q in output:\n%s", want, text)
+		}
+	}
+}
+
+func TestWritePrometheus_Gauges(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterGauge("lectura_queue_length", "Jobs waiting in each Redis queue", func(context.Context) []Sample {
+		return []Sample{{Labels: [][2]string{{"queue", "queue:presentation"}}, Value: 4}}
+	})
+
+	var out bytes.Buffer
+	r.WritePrometheus(context.Background(), &out)
+	text := out.String()
+
+	if !strings.Contains(text, "# TYPE lectura_queue_length gauge\n") ||
+		!strings.Contains(text, `lectura_queue_length{queue="queue:presentation"} 4`+"\n") {
+		t.Fatalf("unexpected gauge output:\n%s", text)
+	}
+}
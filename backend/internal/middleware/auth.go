@@ -3,26 +3,51 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "user_id"
+const (
+	UserIDKey      contextKey = "user_id"
+	APIKeyScopeKey contextKey = "api_key_scope"
+)
+
+// APIKeyPrefix marks personal access tokens, which are accepted in the
+// Authorization header alongside JWTs.
+const APIKeyPrefix = "lk_"
+
+// ErrInvalidAPIKey is returned by an APIKeyResolver for unknown or revoked keys.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyResolver maps a personal access token to its owner and scope.
+type APIKeyResolver interface {
+	ResolveAPIKey(ctx context.Context, token string) (userID uuid.UUID, scope string, err error)
+}
 
 type JWTAuth struct {
-	Secret []byte
+	Secret  []byte
+	apiKeys APIKeyResolver
 }
 
 func NewJWTAuth(secret string) *JWTAuth {
 	return &JWTAuth{Secret: []byte(secret)}
 }
 
+// WithAPIKeys enables personal access token authentication.
+func (j *JWTAuth) WithAPIKeys(resolver APIKeyResolver) *JWTAuth {
+	j.apiKeys = resolver
+	return j
+}
+
 // GenerateAccessToken creates a JWT with 15 minute expiry
 func (j *JWTAuth) GenerateAccessToken(userID uuid.UUID, email, plan string) (string, error) {
 	claims := jwt.MapClaims{
@@ -56,6 +81,11 @@ func (j *JWTAuth) Middleware(next http.Handler) http.Handler {
 
 		tokenStr := parts[1]
 
+		if strings.HasPrefix(tokenStr, APIKeyPrefix) {
+			j.serveAPIKey(w, r, next, tokenStr)
+			return
+		}
+
 		// Parse and verify
 		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -97,6 +127,54 @@ func (j *JWTAuth) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// serveAPIKey authenticates a request made with a personal access token.
+// Read-scoped keys are limited to safe methods.
+func (j *JWTAuth) serveAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	if j.apiKeys == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid API key", r)
+		return
+	}
+
+	userID, scope, err := j.apiKeys.ResolveAPIKey(r.Context(), token)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid API key", r)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to verify API key", r)
+		return
+	}
+
+	if scope != models.APIKeyScopeWrite && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "This API key is read-only", r)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, userID)
+	ctx = context.WithValue(ctx, APIKeyScopeKey, scope)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireSession refuses requests authenticated with an API key. It guards
+// routes that manage the account, its sessions and billing, so a leaked key
+// can only reach the study material it was made to script against.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetAPIKeyScope(r.Context()) != "" {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", "API keys cannot be used for this endpoint", r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetAPIKeyScope returns the scope of the API key that authenticated the
+// request, or "" for JWT sessions.
+func GetAPIKeyScope(ctx context.Context) string {
+	scope, _ := ctx.Value(APIKeyScopeKey).(string)
+	return scope
+}
+
 // GetUserID extracts user_id from request context
 func GetUserID(ctx context.Context) uuid.UUID {
 	id, _ := ctx.Value(UserIDKey).(uuid.UUID)
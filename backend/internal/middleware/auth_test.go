@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type stubAPIKeyResolver struct {
+	tokens map[string]string // token -> scope
+	userID uuid.UUID
+	err    error
+}
+
+func (s *stubAPIKeyResolver) ResolveAPIKey(_ context.Context, token string) (uuid.UUID, string, error) {
+	if s.err != nil {
+		return uuid.Nil, "", s.err
+	}
+	scope, ok := s.tokens[token]
+	if !ok {
+		return uuid.Nil, "", ErrInvalidAPIKey
+	}
+	return s.userID, scope, nil
+}
+
+func TestMiddleware_APIKeys(t *testing.T) {
+	userID := uuid.New()
+	resolver := &stubAPIKeyResolver{
+		tokens: map[string]string{"lk_read": "read", "lk_write": "write"},
+		userID: userID,
+	}
+
+	tests := []struct {
+		name     string
+		resolver APIKeyResolver
+		method   string
+		token    string
+		want     int
+	}{
+		{name: "read key can read", resolver: resolver, method: http.MethodGet, token: "lk_read", want: http.StatusOK},
+		{name: "read key cannot write", resolver: resolver, method: http.MethodPost, token: "lk_read", want: http.StatusForbidden},
+		{name: "write key can write", resolver: resolver, method: http.MethodDelete, token: "lk_write", want: http.StatusOK},
+		{name: "unknown key", resolver: resolver, method: http.MethodGet, token: "lk_nope", want: http.StatusUnauthorized},
+		{name: "keys disabled", resolver: nil, method: http.MethodGet, token: "lk_read", want: http.StatusUnauthorized},
+		{name: "lookup failure", resolver: &stubAPIKeyResolver{err: errors.New("db down")}, method: http.MethodGet, token: "lk_read", want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := NewJWTAuth("secret")
+			if tt.resolver != nil {
+				auth.WithAPIKeys(tt.resolver)
+			}
+
+			var gotUser uuid.UUID
+			var gotScope string
+			handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser = GetUserID(r.Context())
+				gotScope = GetAPIKeyScope(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (gotUser != userID || gotScope == "") {
+				t.Fatalf("context user = %s scope = %q, want %s and a scope", gotUser, gotScope, userID)
+			}
+		})
+	}
+}
+
+func TestMiddleware_JWTHasNoAPIKeyScope(t *testing.T) {
+	auth := NewJWTAuth("secret")
+	userID := uuid.New()
+	token, err := auth.GenerateAccessToken(userID, "a@example.com", "free")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if GetUserID(r.Context()) != userID || GetAPIKeyScope(r.Context()) != "" {
+			t.Fatalf("unexpected auth context")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	auth := NewJWTAuth("secret").WithAPIKeys(&stubAPIKeyResolver{
+		tokens: map[string]string{"lk_write": "write"},
+		userID: uuid.New(),
+	})
+	session, err := auth.GenerateAccessToken(uuid.New(), "a@example.com", "free")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	handler := auth.Middleware(RequireSession(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for token, want := range map[string]int{"lk_write": http.StatusForbidden, session: http.StatusOK} {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != want {
+			t.Fatalf("token %.8s...: status = %d, want %d", token, rr.Code, want)
+		}
+	}
+}
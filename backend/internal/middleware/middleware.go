@@ -108,6 +108,26 @@ func CORS(frontendURL string) func(http.Handler) http.Handler {
 	}
 }
 
+// SkipPaths applies mw to every request except those for the given exact
+// paths. Health probes and metrics scrapes come from orchestrators rather than
+// browsers, so they bypass CORS.
+func SkipPaths(mw func(http.Handler) http.Handler, paths ...string) func(http.Handler) http.Handler {
+	skip := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		skip[p] = true
+	}
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
 func normalizeOrigin(origin string) string {
 	trimmed := strings.TrimSpace(origin)
 	if trimmed == "" {
@@ -89,3 +89,21 @@ func TestRequestID_TooLong_Rejected(t *testing.T) {
 		t.Fatalf("expected fallback request ID to be valid UUID, got %q: %v", got, err)
 	}
 }
+
+func TestSkipPaths_BypassesCORSForProbes(t *testing.T) {
+	handler := SkipPaths(CORS("http://localhost:5173"), "/health/ready")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no CORS headers on a skipped path, got %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/summaries", nil))
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got == "" {
+		t.Fatalf("expected CORS headers on other paths")
+	}
+}
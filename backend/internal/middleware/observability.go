@@ -11,6 +11,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"lectura-backend/internal/metrics"
 )
 
 type statusRecorder struct {
@@ -124,8 +126,9 @@ func StructuredRequestLog(next http.Handler) http.Handler {
 	})
 }
 
-// MetricsHandler exposes minimal process/request metrics in Prometheus text format.
-func MetricsHandler(w http.ResponseWriter, _ *http.Request) {
+// MetricsHandler exposes process/request metrics, followed by worker and
+// queue metrics from the metrics package, in Prometheus text format.
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
 
 	total := defaultMetricsCollector.totalRequests.Load()
@@ -154,4 +157,6 @@ func MetricsHandler(w http.ResponseWriter, _ *http.Request) {
 	for _, row := range defaultMetricsCollector.snapshotStatusCounts() {
 		_, _ = fmt.Fprintf(w, "lectura_requests_by_status{code=\"%d\"} %d\n", row[0], row[1])
 	}
+
+	metrics.WritePrometheus(r.Context(), w)
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AccountSummary is an overview of everything a user has in their account.
+type AccountSummary struct {
+	Summaries      int       `json:"summaries"`
+	Quizzes        int       `json:"quizzes"`
+	FlashcardDecks int       `json:"flashcard_decks"`
+	FlashcardCards int       `json:"flashcard_cards"`
+	StudyHours     float64   `json:"study_hours"`
+	CreatedAt      time.Time `json:"account_created_at"`
+	AccountAgeDays int       `json:"account_age_days"`
+	Plan           string    `json:"plan"`
+	StorageBytes   int64     `json:"storage_bytes"`
+	IsVerified     bool      `json:"is_verified"`
+}
@@ -14,14 +14,27 @@ type ChatMessage struct {
 
 // ChatRequest is the payload sent to the chat endpoint.
 type ChatRequest struct {
-	Message string        `json:"message"`
+	Message string `json:"message"`
+	// History is only used when the server does not store chats; otherwise
+	// the stored conversation is sent with the question.
 	History []ChatMessage `json:"history"`
 }
 
+// ExplainRequest is the payload sent to the explain-selection endpoint.
+type ExplainRequest struct {
+	Selection string `json:"selection"`
+}
+
+// ExplainResponse is the explanation of a highlighted excerpt.
+type ExplainResponse struct {
+	Explanation string `json:"explanation"`
+}
+
 // ChatResponse is the reply from the AI chat.
 type ChatResponse struct {
-	Reply         string  `json:"reply"`
-	ScreenOcrHint *string `json:"screen_ocr_hint,omitempty"`
+	Reply         string   `json:"reply"`
+	ScreenOcrHint *string  `json:"screen_ocr_hint,omitempty"`
+	Suggestions   []string `json:"suggestions"` // follow-up questions; empty if they could not be generated
 }
 
 // ChatHistoryMessage is a persisted chat message row.
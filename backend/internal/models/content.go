@@ -8,21 +8,28 @@ import (
 )
 
 type Content struct {
-	ID              uuid.UUID       `json:"id"`
-	UserID          uuid.UUID       `json:"user_id"`
-	Type            string          `json:"type"`   // "youtube" | "file"
-	Status          string          `json:"status"` // "pending" | "processing" | "completed" | "failed"
-	SourceURL       *string         `json:"source_url"`
-	FilePath        *string         `json:"file_path"`
-	Title           string          `json:"title"`
-	DurationSeconds *int            `json:"duration_seconds"`
-	Transcript      *string         `json:"transcript"`
-	MetadataJSON    json.RawMessage `json:"metadata"`
-	CreatedAt       time.Time       `json:"created_at"`
+	ID               uuid.UUID       `json:"id"`
+	UserID           uuid.UUID       `json:"user_id"`
+	Type             string          `json:"type"`   // "youtube" | "file"
+	Status           string          `json:"status"` // "pending" | "processing" | "completed" | "failed"
+	SourceURL        *string         `json:"source_url"`
+	FilePath         *string         `json:"file_path"`
+	Title            string          `json:"title"`
+	DurationSeconds  *int            `json:"duration_seconds"`
+	Transcript       *string         `json:"transcript"`
+	MetadataJSON     json.RawMessage `json:"metadata"`
+	CreatedAt        time.Time       `json:"created_at"`
+	ErrorMessage     *string         `json:"error_message,omitempty"`     // from the latest processing job; only set when failed
+	ErrorCode        *string         `json:"error_code,omitempty"`        // the latest processing job's error code, e.g. "blocked_by_safety"
+	DetectedLanguage *string         `json:"detected_language,omitempty"` // ISO 639-1 code detected from the transcript
+	Redacted         bool            `json:"redacted"`                    // personal data was masked in the stored transcript
+	ContentHash      *string         `json:"-"`                           // source fingerprint used to skip reprocessing duplicates
+	Chapters         []VideoChapter  `json:"chapters,omitempty"`          // a YouTube video's chapters, read from metadata
 }
 
 type ValidateYouTubeRequest struct {
-	URL string `json:"url"`
+	URL             string `json:"url"`
+	CaptionLanguage string `json:"caption_language,omitempty"` // e.g. "en", "pt-BR" or "auto"; defaults to the account language
 }
 
 type YouTubeMetadata struct {
@@ -32,4 +39,31 @@ type YouTubeMetadata struct {
 	ThumbnailURL string `json:"thumbnail_url"`
 	Duration     int    `json:"duration_seconds"`
 	WordCount    int    `json:"word_count,omitempty"`
+	// CaptionLanguage is the caption track to transcribe; "auto" (or empty, for
+	// older content) accepts any track.
+	CaptionLanguage string `json:"caption_language,omitempty"`
+	// Chapters are the video's chapter markers, in order; empty when it has none.
+	Chapters []VideoChapter `json:"chapters,omitempty"`
+}
+
+// VideoChapter is a chapter marker of a YouTube video.
+type VideoChapter struct {
+	Title        string `json:"title"`
+	StartSeconds int    `json:"start_seconds"`
+}
+
+// PlaylistVideoResult is a playlist video imported as content.
+type PlaylistVideoResult struct {
+	VideoID      string     `json:"video_id"`
+	Title        string     `json:"title"`
+	ContentID    uuid.UUID  `json:"content_id"`
+	JobID        *uuid.UUID `json:"job_id,omitempty"` // nil when already processed content was reused
+	Deduplicated bool       `json:"deduplicated"`
+}
+
+// PlaylistWarning is a playlist video that was not imported, and why.
+type PlaylistWarning struct {
+	VideoID string `json:"video_id"`
+	Title   string `json:"title,omitempty"`
+	Reason  string `json:"reason"`
 }
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserDataExport is everything a user has created, shaped for a personal data
+// export. Credentials, billing identifiers and owner IDs are deliberately left
+// out; entity IDs are kept so exported records can reference each other.
+type UserDataExport struct {
+	ExportedAt    time.Time            `json:"exported_at"`
+	Profile       ExportProfile        `json:"profile"`
+	Settings      *ExportSettings      `json:"settings,omitempty"`
+	Summaries     []ExportSummary      `json:"summaries"`
+	Quizzes       []ExportQuiz         `json:"quizzes"`
+	QuizAttempts  []ExportQuizAttempt  `json:"quiz_attempts"`
+	Decks         []ExportDeck         `json:"flashcard_decks"`
+	Cards         []ExportCard         `json:"flashcard_cards"`
+	StudySessions []ExportStudySession `json:"study_sessions"`
+	// Unmasked transcripts kept for users who asked to retain them
+	OriginalTranscripts []ExportOriginalTranscript `json:"original_transcripts"`
+}
+
+type ExportProfile struct {
+	Email        string     `json:"email"`
+	FullName     string     `json:"full_name"`
+	Bio          *string    `json:"bio,omitempty"`
+	AvatarURL    *string    `json:"avatar_url,omitempty"`
+	Plan         string     `json:"plan"`
+	AuthProvider string     `json:"auth_provider"`
+	IsVerified   bool       `json:"is_verified"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+}
+
+type ExportSettings struct {
+	DefaultSummaryLength string          `json:"default_summary_length"`
+	DefaultFormat        string          `json:"default_format"`
+	DefaultDifficulty    string          `json:"default_difficulty"`
+	Language             string          `json:"language"`
+	Notifications        json.RawMessage `json:"notifications"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+}
+
+type ExportSummary struct {
+	ID             uuid.UUID  `json:"id"`
+	Title          string     `json:"title"`
+	Source         string     `json:"source,omitempty"`
+	Format         string     `json:"format"`
+	Length         string     `json:"length"`
+	Content        *string    `json:"content,omitempty"`
+	CornellCues    *string    `json:"cornell_cues,omitempty"`
+	CornellNotes   *string    `json:"cornell_notes,omitempty"`
+	CornellSummary *string    `json:"cornell_summary,omitempty"`
+	Tags           []string   `json:"tags"`
+	Description    *string    `json:"description,omitempty"`
+	WordCount      int        `json:"word_count"`
+	IsFavorite     bool       `json:"is_favorite"`
+	IsArchived     bool       `json:"is_archived"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+type ExportQuiz struct {
+	ID            uuid.UUID       `json:"id"`
+	SummaryID     *uuid.UUID      `json:"summary_id,omitempty"`
+	Title         string          `json:"title"`
+	Config        json.RawMessage `json:"config"`
+	Questions     json.RawMessage `json:"questions"`
+	QuestionCount int             `json:"question_count"`
+	IsFavorite    bool            `json:"is_favorite"`
+	CreatedAt     time.Time       `json:"created_at"`
+	DeletedAt     *time.Time      `json:"deleted_at,omitempty"`
+}
+
+type ExportQuizAttempt struct {
+	ID               uuid.UUID       `json:"id"`
+	QuizID           uuid.UUID       `json:"quiz_id"`
+	Answers          json.RawMessage `json:"answers"`
+	ScorePercent     *float64        `json:"score_percent,omitempty"`
+	CorrectCount     *int            `json:"correct_count,omitempty"`
+	StartedAt        time.Time       `json:"started_at"`
+	CompletedAt      *time.Time      `json:"completed_at,omitempty"`
+	TimeTakenSeconds *int            `json:"time_taken_seconds,omitempty"`
+}
+
+type ExportDeck struct {
+	ID         uuid.UUID       `json:"id"`
+	SummaryID  *uuid.UUID      `json:"summary_id,omitempty"`
+	Title      string          `json:"title"`
+	Config     json.RawMessage `json:"config"`
+	CardCount  int             `json:"card_count"`
+	IsFavorite bool            `json:"is_favorite"`
+	CreatedAt  time.Time       `json:"created_at"`
+	DeletedAt  *time.Time      `json:"deleted_at,omitempty"`
+}
+
+type ExportCard struct {
+	DeckID         uuid.UUID  `json:"deck_id"`
+	Front          string     `json:"front"`
+	Back           string     `json:"back"`
+	Mnemonic       *string    `json:"mnemonic,omitempty"`
+	Example        *string    `json:"example,omitempty"`
+	Topic          *string    `json:"topic,omitempty"`
+	Difficulty     int        `json:"difficulty"`
+	IntervalDays   int        `json:"interval_days"`
+	EaseFactor     float64    `json:"ease_factor"`
+	Repetitions    int        `json:"repetitions"`
+	NextReviewAt   time.Time  `json:"next_review_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+}
+
+type ExportStudySession struct {
+	ActivityType    string     `json:"activity_type"`
+	ResourceID      uuid.UUID  `json:"resource_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds int        `json:"duration_seconds"`
+}
+
+type ExportOriginalTranscript struct {
+	ContentID  uuid.UUID `json:"content_id"`
+	Title      string    `json:"title"`
+	Transcript string    `json:"transcript"`
+	CreatedAt  time.Time `json:"created_at"`
+}
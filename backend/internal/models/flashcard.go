@@ -16,6 +16,46 @@ type FlashcardDeck struct {
 	CardCount  int             `json:"card_count"`
 	IsFavorite bool            `json:"is_favorite"`
 	CreatedAt  time.Time       `json:"created_at"`
+
+	// OriginalAuthor is set on decks imported from a share link.
+	OriginalAuthor *string `json:"original_author,omitempty"`
+
+	// ContentID is set instead of SummaryID on decks generated straight from
+	// a transcript.
+	ContentID *uuid.UUID `json:"content_id,omitempty"`
+
+	// SchedulingParams overrides the default SM-2 parameters for this deck.
+	SchedulingParams *SchedulingParams `json:"scheduling_params,omitempty"`
+}
+
+// SchedulingParams tunes SM-2 for a deck. Cards keep their own ease factor;
+// these control the fixed first steps and how intervals grow after that.
+type SchedulingParams struct {
+	FirstIntervalDays  int     `json:"first_interval_days"`  // interval after the first successful review
+	SecondIntervalDays int     `json:"second_interval_days"` // interval after the second
+	IntervalModifier   float64 `json:"interval_modifier"`    // scales later intervals (1.0 = plain SM-2)
+	MaxIntervalDays    int     `json:"max_interval_days"`
+
+	// A card failed LeechThreshold reviews in a row is a leech; with
+	// AutoSuspendLeeches set it is suspended when it gets there.
+	LeechThreshold     int   `json:"leech_threshold"`
+	AutoSuspendLeeches *bool `json:"auto_suspend_leeches,omitempty"`
+}
+
+type UpdateDeckScheduleRequest struct {
+	SchedulingParams
+	RescheduleExisting bool `json:"reschedule_existing"` // recompute due dates of already-reviewed cards
+	DryRun             bool `json:"dry_run"`             // report the changes without saving anything
+}
+
+// ScheduleChange is one card whose due date moves under new parameters.
+type ScheduleChange struct {
+	CardID          uuid.UUID `json:"card_id"`
+	Front           string    `json:"front"`
+	OldIntervalDays int       `json:"old_interval_days"`
+	NewIntervalDays int       `json:"new_interval_days"`
+	OldNextReviewAt time.Time `json:"old_next_review_at"`
+	NewNextReviewAt time.Time `json:"new_next_review_at"`
 }
 
 type FlashcardCard struct {
@@ -32,6 +72,15 @@ type FlashcardCard struct {
 	Repetitions    int        `json:"repetitions"`
 	NextReviewAt   time.Time  `json:"next_review_at"`
 	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	Lapses         int        `json:"lapses"`    // consecutive failed reviews
+	IsLeech        bool       `json:"is_leech"`  // lapses reached the deck's leech threshold
+	Suspended      bool       `json:"suspended"` // left out of reviews until unsuspended
+}
+
+// DueCard is a card in the cross-deck review queue, with its deck's title.
+type DueCard struct {
+	FlashcardCard
+	DeckTitle string `json:"deck_title"`
 }
 
 type GenerateFlashcardsRequest struct {
@@ -44,12 +93,53 @@ type GenerateFlashcardsRequest struct {
 	IncludeMnemonics       bool      `json:"include_mnemonics"`
 	IncludeExamples        bool      `json:"include_examples"`
 	ExtractScreenText      bool      `json:"extract_screen_text"`
+
+	// ContentID generates cards from the content's transcript instead of a
+	// summary. Exactly one of SummaryID and ContentID must be set.
+	ContentID *uuid.UUID `json:"content_id,omitempty"`
+}
+
+type MergeDecksRequest struct {
+	SourceIDs        []uuid.UUID `json:"source_ids"`
+	Title            string      `json:"title"`
+	PreserveProgress bool        `json:"preserve_progress"` // keep SM-2 state instead of resetting cards to new
+	DeleteSources    bool        `json:"delete_sources"`    // move source decks to the trash after merging
 }
 
 type CardRatingRequest struct {
 	Rating int `json:"rating"` // 0=Again, 1=Hard, 2=Good, 3=Easy
 }
 
+// CardReview is one logged rating of a card.
+type CardReview struct {
+	ID               uuid.UUID `json:"id"`
+	CardID           uuid.UUID `json:"card_id"`
+	Rating           int       `json:"rating"`
+	PrevIntervalDays int       `json:"prev_interval_days"`
+	NextIntervalDays int       `json:"next_interval_days"`
+	ReviewedAt       time.Time `json:"reviewed_at"`
+}
+
+// DeckReviewDay totals a deck's reviews on one UTC day. Correct counts Good
+// and Easy ratings; Accuracy is their share in percent.
+type DeckReviewDay struct {
+	Date     string  `json:"date"`
+	Reviews  int     `json:"reviews"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// DeckReviewHistory is a deck's review activity over the last Days days,
+// oldest day first, plus its most recent reviews.
+type DeckReviewHistory struct {
+	DeckID       uuid.UUID       `json:"deck_id"`
+	Days         int             `json:"days"`
+	TotalReviews int             `json:"total_reviews"`
+	Accuracy     float64         `json:"accuracy"`
+	Daily        []DeckReviewDay `json:"daily"`
+	Recent       []CardReview    `json:"recent"`
+}
+
 type DeckStats struct {
 	TotalCards  int     `json:"total_cards"`
 	Mastered    int     `json:"mastered"`
@@ -57,4 +147,6 @@ type DeckStats struct {
 	New         int     `json:"new"`
 	DueToday    int     `json:"due_today"`
 	MasteryRate float64 `json:"mastery_rate"`
+	Leeches     int     `json:"leeches"`
+	Suspended   int     `json:"suspended"`
 }
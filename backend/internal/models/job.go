@@ -17,7 +17,9 @@ type Job struct {
 	RetryCount   int             `json:"retry_count"`
 	MaxRetries   int             `json:"max_retries"`
 	ErrorMessage *string         `json:"error_message"`
+	ErrorCode    *string         `json:"error_code,omitempty"` // set for failures the UI explains, e.g. "blocked_by_safety"
 	CreatedAt    time.Time       `json:"created_at"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
 	CompletedAt  *time.Time      `json:"completed_at"`
 }
 
@@ -27,8 +29,14 @@ type UserSettings struct {
 	DefaultFormat        string          `json:"default_format"`
 	DefaultDifficulty    string          `json:"default_difficulty"`
 	Language             string          `json:"language"`
+	DefaultNumQuestions  *int            `json:"default_num_questions"`
+	DefaultNumCards      *int            `json:"default_num_cards"`
 	NotificationsJSON    json.RawMessage `json:"notifications"`
-	UpdatedAt            time.Time       `json:"updated_at"`
+	// Personal data masking for new transcripts; see services.RedactPII.
+	RedactTranscripts        bool      `json:"redact_transcripts"`
+	RedactNames              bool      `json:"redact_names"`
+	RetainOriginalTranscript bool      `json:"retain_original_transcript"`
+	UpdatedAt                time.Time `json:"updated_at"`
 }
 
 // WebSocket message types
@@ -44,6 +52,17 @@ type StatusUpdate struct {
 	EstimatedSecondsRemaining int       `json:"estimated_seconds_remaining"`
 }
 
+// JobProgress is the polling view of a job: its status plus the last status
+// update published for it, for clients without a WebSocket.
+type JobProgress struct {
+	JobID                     uuid.UUID `json:"job_id"`
+	Status                    string    `json:"status"`
+	Step                      int       `json:"step"`
+	StepName                  string    `json:"step_name"`
+	Percent                   int       `json:"percent"`
+	EstimatedSecondsRemaining int       `json:"estimated_seconds_remaining"`
+}
+
 type PartialContent struct {
 	JobID           uuid.UUID `json:"job_id"`
 	Chunk           string    `json:"chunk"`
@@ -56,6 +75,17 @@ type CompletedEvent struct {
 	ResultType string    `json:"result_type"`
 }
 
+// ChainedJobEvent announces a job queued automatically after ParentJobID
+// completed. When the job could not be queued, JobID and ResultID are unset
+// and SkippedReason says why ("QUOTA_EXCEEDED", "API_KEY_REQUIRED", ...).
+type ChainedJobEvent struct {
+	ParentJobID   uuid.UUID  `json:"parent_job_id"`
+	JobID         *uuid.UUID `json:"job_id,omitempty"`
+	ResultID      *uuid.UUID `json:"result_id,omitempty"`
+	ResultType    string     `json:"result_type"`
+	SkippedReason string     `json:"skipped_reason,omitempty"`
+}
+
 type ErrorEvent struct {
 	JobID        uuid.UUID `json:"job_id"`
 	ErrorCode    string    `json:"error_code"`
@@ -68,6 +98,8 @@ type APIError struct {
 	Message   string            `json:"message"`
 	Fields    map[string]string `json:"fields,omitempty"`
 	RequestID string            `json:"request_id"`
+	// RetryAfterSeconds is set on RATE_LIMITED errors when the wait is known.
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -0,0 +1,32 @@
+package models
+
+// NotificationSchedule says when scheduled emails may reach a user. Quiet
+// hours are local hours [start, end), wrapping past midnight when start >
+// end; both are nil when the user has none.
+type NotificationSchedule struct {
+	Timezone        string `json:"timezone"`
+	QuietHoursStart *int   `json:"quiet_hours_start"`
+	QuietHoursEnd   *int   `json:"quiet_hours_end"`
+}
+
+// DailyBrief is what a user's daily brief email reports.
+type DailyBrief struct {
+	CardsDue         int
+	WeeklyGoalType   string
+	WeeklyGoalTarget int
+	WeeklyGoalDone   int
+	Streak           int
+}
+
+// GoalRemaining is how many more items the weekly goal needs.
+func (b DailyBrief) GoalRemaining() int {
+	if b.WeeklyGoalDone >= b.WeeklyGoalTarget {
+		return 0
+	}
+	return b.WeeklyGoalTarget - b.WeeklyGoalDone
+}
+
+// Actionable reports whether the brief has anything for the user to do.
+func (b DailyBrief) Actionable() bool {
+	return b.CardsDue > 0 || b.GoalRemaining() > 0
+}
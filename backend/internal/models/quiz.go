@@ -19,6 +19,9 @@ type Quiz struct {
 	LastScore     *float64        `json:"last_score,omitempty"`
 	LastAttemptID *uuid.UUID      `json:"last_attempt_id,omitempty"`
 	CreatedAt     time.Time       `json:"created_at"`
+
+	// OriginalAuthor is set on quizzes imported from a share link.
+	OriginalAuthor *string `json:"original_author,omitempty"`
 }
 
 type QuizAttempt struct {
@@ -31,6 +34,24 @@ type QuizAttempt struct {
 	StartedAt        time.Time       `json:"started_at"`
 	CompletedAt      *time.Time      `json:"completed_at"`
 	TimeTakenSeconds *int            `json:"time_taken_seconds"`
+	HintsUsed        []int           `json:"hints_used"`
+	QuestionOrder    []int           `json:"question_order,omitempty"`
+	// Essay answers and their grades, keyed by question index. Answers to
+	// fill_blank questions are kept with the essay answers.
+	EssayAnswers map[int]string     `json:"essay_answers,omitempty"`
+	EssayGrades  map[int]EssayGrade `json:"essay_grades,omitempty"`
+	// MatchingAnswers holds each matching question's matches, keyed by
+	// question index; see SaveProgressRequest.Matches.
+	MatchingAnswers map[int][]int `json:"matching_answers,omitempty"`
+}
+
+// EssayGrade is the AI grade of one essay answer on a submitted attempt.
+// Graded is false when AI grading was off or the grading call failed; such
+// essays are left out of the score.
+type EssayGrade struct {
+	Score    int    `json:"score"`
+	Feedback string `json:"feedback,omitempty"`
+	Graded   bool   `json:"graded"`
 }
 
 type GenerateQuizRequest struct {
@@ -42,8 +63,19 @@ type GenerateQuizRequest struct {
 	EnableTimer         bool      `json:"enable_timer"`
 	ShuffleQuestions    bool      `json:"shuffle_questions"`
 	EnableHints         bool      `json:"enable_hints"`
+	HintPenaltyPercent  int       `json:"hint_penalty_percent"`
 	Topics              []string  `json:"topics"`
 	ExtractScreenText   bool      `json:"extract_screen_text"`
+	// Fresh asks for questions as different as possible from earlier quizzes
+	// on the same summary.
+	Fresh bool `json:"fresh,omitempty"`
+	// DifficultyMix asks for an exact number of questions per difficulty,
+	// e.g. {"easy": 3, "medium": 4, "hard": 3}; it overrides Difficulty and
+	// must add up to NumQuestions.
+	DifficultyMix map[string]int `json:"difficulty_mix,omitempty"`
+	// AIGrading has essay answers graded by the model on submit, which
+	// costs tokens; without it essays are left ungraded.
+	AIGrading bool `json:"ai_grading,omitempty"`
 }
 
 type QuizQuestion struct {
@@ -55,9 +87,77 @@ type QuizQuestion struct {
 	Hint         string   `json:"hint"`
 	Difficulty   string   `json:"difficulty"`
 	Topic        string   `json:"topic"`
+	// Rubric is what a full-credit essay answer covers. Essay questions
+	// have no options.
+	Rubric string `json:"rubric,omitempty"`
+	// AcceptedAnswers are the answers a fill_blank question takes, the
+	// expected one first. They are compared trimmed and case-insensitively.
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+	// Pairs are a matching question's prompts, each with the answer it
+	// matches. Takers see the answers in shuffled order.
+	Pairs []MatchingPair `json:"pairs,omitempty"`
+}
+
+// MatchingPair is one prompt of a matching question and its answer.
+type MatchingPair struct {
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer"`
 }
 
 type SaveProgressRequest struct {
 	QuestionIndex int `json:"question_index"`
 	AnswerIndex   int `json:"answer_index"`
+	// AnswerText is set instead of AnswerIndex for essay and fill_blank
+	// questions.
+	AnswerText *string `json:"answer_text,omitempty"`
+	// Matches is set instead of AnswerIndex for matching questions: for each
+	// prompt, the index in Pairs of the answer matched to it.
+	Matches []int `json:"matches,omitempty"`
+}
+
+// QuestionReport is a user's report of a wrong or unclear quiz question.
+type QuestionReport struct {
+	ID            uuid.UUID  `json:"id"`
+	QuizID        uuid.UUID  `json:"quiz_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	QuestionIndex int        `json:"question_index"`
+	Reason        string     `json:"reason"`
+	SuggestedFix  *string    `json:"suggested_fix,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type ReportQuestionRequest struct {
+	Reason       string `json:"reason"`
+	SuggestedFix string `json:"suggested_fix"`
+}
+
+// QuizAttemptSummary is one attempt in a quiz's attempt history. Score,
+// correct count and time taken are nil until the attempt is submitted.
+type QuizAttemptSummary struct {
+	ID               uuid.UUID  `json:"id"`
+	StartedAt        time.Time  `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	ScorePercent     *float64   `json:"score_percent"`
+	CorrectCount     *int       `json:"correct_count"`
+	TimeTakenSeconds *int       `json:"time_taken_seconds"`
+}
+
+// QuestionStat is how one question of a quiz went across the user's
+// completed attempts. CorrectRate is the share answered correctly, in percent.
+type QuestionStat struct {
+	QuestionIndex int     `json:"question_index"`
+	Question      string  `json:"question"`
+	Type          string  `json:"type"`
+	Attempts      int     `json:"attempts"`
+	Correct       int     `json:"correct"`
+	CorrectRate   float64 `json:"correct_rate"`
+}
+
+// AttemptScore is a completed attempt's grade, recomputed after a question
+// is corrected.
+type AttemptScore struct {
+	AttemptID    uuid.UUID
+	ScorePercent float64
+	CorrectCount int
 }
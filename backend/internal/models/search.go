@@ -0,0 +1,16 @@
+package models
+
+// SummaryChunk is one embedded passage of a summary's source text.
+type SummaryChunk struct {
+	Index     int
+	Content   string
+	Embedding []float32
+}
+
+// SummarySearchResult is a passage that matched a semantic search, with its
+// cosine similarity to the query.
+type SummarySearchResult struct {
+	ChunkIndex int     `json:"chunk_index"`
+	Content    string  `json:"content"`
+	Score      float64 `json:"score"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// ShareLink identifies a shared deck or quiz.
+type ShareLink struct {
+	Slug     string    `json:"share_slug"`
+	Path     string    `json:"path"`
+	SharedAt time.Time `json:"shared_at"`
+}
+
+// SharedDeck is the public preview of a shared deck. It never carries the
+// owner's user ID, review progress or links to the owner's other content.
+type SharedDeck struct {
+	Title      string       `json:"title"`
+	AuthorName string       `json:"author_name"`
+	CardCount  int          `json:"card_count"`
+	SharedAt   time.Time    `json:"shared_at"`
+	Cards      []SharedCard `json:"cards"`
+}
+
+type SharedCard struct {
+	Front      string  `json:"front"`
+	Back       string  `json:"back"`
+	Mnemonic   *string `json:"mnemonic,omitempty"`
+	Example    *string `json:"example,omitempty"`
+	Topic      string  `json:"topic"`
+	Difficulty int     `json:"difficulty"`
+}
+
+// SharedQuiz is the public preview of a shared quiz. Answers and
+// explanations are left out so the preview does not spoil the quiz.
+type SharedQuiz struct {
+	Title         string               `json:"title"`
+	AuthorName    string               `json:"author_name"`
+	QuestionCount int                  `json:"question_count"`
+	SharedAt      time.Time            `json:"shared_at"`
+	Questions     []SharedQuizQuestion `json:"questions"`
+}
+
+type SharedQuizQuestion struct {
+	Question string   `json:"question"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Study plan item kinds.
+const (
+	StudyPlanItemSummaryReview = "summary_review"
+	StudyPlanItemQuiz          = "quiz"
+	StudyPlanItemFlashcards    = "flashcards"
+)
+
+// StudyPlan is a day-by-day schedule for working through a set of summaries
+// before a target date.
+type StudyPlan struct {
+	ID             uuid.UUID       `json:"id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	SummaryIDs     []uuid.UUID     `json:"summary_ids"`
+	StartDate      time.Time       `json:"start_date"`
+	TargetDate     time.Time       `json:"target_date"`
+	DailyMinutes   int             `json:"daily_minutes"`
+	AIOrdered      bool            `json:"ai_ordered"`
+	Items          []StudyPlanItem `json:"items"`
+	CompletedItems int             `json:"completed_items"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type StudyPlanItem struct {
+	ID               uuid.UUID  `json:"id"`
+	PlanID           uuid.UUID  `json:"plan_id"`
+	Day              time.Time  `json:"day"`
+	Kind             string     `json:"kind"`
+	Title            string     `json:"title"`
+	SummaryID        *uuid.UUID `json:"summary_id,omitempty"`
+	QuizID           *uuid.UUID `json:"quiz_id,omitempty"` // latest quiz on the summary; nil means generate one
+	DeckID           *uuid.UUID `json:"deck_id,omitempty"`
+	CardCount        int        `json:"card_count,omitempty"` // flashcards forecast to be due that day
+	EstimatedMinutes int        `json:"estimated_minutes"`
+	CompletedAt      *time.Time `json:"completed_at"`
+}
+
+type GenerateStudyPlanRequest struct {
+	SummaryIDs   []uuid.UUID `json:"summary_ids"`
+	TargetDate   string      `json:"target_date"` // YYYY-MM-DD, the last day of the plan
+	DailyMinutes int         `json:"daily_minutes"`
+	AIOrdering   bool        `json:"ai_ordering"` // let Gemini put foundational topics first
+}
+
+// StudyPlanSummary is what the planner needs to know about one summary.
+type StudyPlanSummary struct {
+	SummaryID   uuid.UUID
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	WordCount   int
+	QuizID      *uuid.UUID
+}
+
+// DueCardForecast counts the cards of one deck that fall due on Day. Cards
+// already overdue are counted on the plan's first day.
+type DueCardForecast struct {
+	DeckID    uuid.UUID
+	DeckTitle string
+	Day       time.Time
+	Count     int
+}
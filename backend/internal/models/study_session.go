@@ -15,6 +15,8 @@ type StudySession struct {
 	StartedAt       time.Time       `json:"started_at"`
 	LastHeartbeatAt time.Time       `json:"last_heartbeat_at"`
 	EndedAt         *time.Time      `json:"ended_at,omitempty"`
+	PausedAt        *time.Time      `json:"paused_at,omitempty"`
+	PausedSeconds   int             `json:"paused_seconds"`
 	DurationSeconds int             `json:"duration_seconds"`
 	ClientMetaJSON  json.RawMessage `json:"client_meta"`
 	CreatedAt       time.Time       `json:"created_at"`
@@ -28,6 +28,10 @@ type Summary struct {
 	IsArchived            bool            `json:"is_archived"`
 	IsQualityFallback     bool            `json:"is_quality_fallback"`
 	QualityFallbackReason *string         `json:"quality_fallback_reason,omitempty"`
+	LengthCorrected       bool            `json:"length_corrected"`
+	SourceTruncated       bool            `json:"source_truncated"`
+	SourceCoveragePercent *int            `json:"source_coverage_percent,omitempty"`
+	TruncationNotice      string          `json:"truncation_notice,omitempty"`
 	CreatedAt             time.Time       `json:"created_at"`
 	LastAccessedAt        *time.Time      `json:"last_accessed_at"`
 }
@@ -40,4 +44,69 @@ type GenerateSummaryRequest struct {
 	TargetAudience      string    `json:"target_audience"`
 	Language            string    `json:"language"`
 	ExtractScreenText   bool      `json:"extract_screen_text"`
+	// RewriteFromSummaryID makes the worker condense/expand an existing summary's
+	// content instead of re-reading the source transcript.
+	RewriteFromSummaryID *uuid.UUID `json:"rewrite_from_summary_id,omitempty"`
+	// AutoGenerate queues quiz and/or flashcard jobs for the summary once it
+	// completes.
+	AutoGenerate *AutoGenerateOptions `json:"auto_generate,omitempty"`
+}
+
+// AutoGenerateOptions picks the follow-up jobs for a summary. A nil config
+// uses the user's default settings; its summary_id is filled in by the worker.
+type AutoGenerateOptions struct {
+	Quiz            bool                       `json:"quiz"`
+	Flashcards      bool                       `json:"flashcards"`
+	QuizConfig      *GenerateQuizRequest       `json:"quiz_config,omitempty"`
+	FlashcardConfig *GenerateFlashcardsRequest `json:"flashcard_config,omitempty"`
+}
+
+// FocusArea is a supported summary focus area.
+type FocusArea struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+type RewriteSummaryRequest struct {
+	Length string `json:"length"`
+	Format string `json:"format"`
+}
+
+// OutlineNode is one node of a summary's mind-map outline.
+type OutlineNode struct {
+	Title    string        `json:"title"`
+	Children []OutlineNode `json:"children,omitempty"`
+}
+
+// RelatedSummary is another of the user's summaries that shares tags with the
+// one being viewed, ranked by how many it shares.
+type RelatedSummary struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Format      string    `json:"format"`
+	Source      string    `json:"source"`
+	Tags        []string  `json:"tags"`
+	Description *string   `json:"description"`
+	IsFavorite  bool      `json:"is_favorite"`
+	SharedTags  []string  `json:"shared_tags"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SummaryVersion is an earlier body of a summary, kept so a regeneration can
+// be rolled back. Versions are numbered per summary, newest highest.
+type SummaryVersion struct {
+	Version               int             `json:"version"`
+	Format                string          `json:"format"`
+	LengthSetting         string          `json:"length_setting"`
+	ConfigJSON            json.RawMessage `json:"config"`
+	ContentRaw            *string         `json:"content_raw"`
+	CornellCues           *string         `json:"cornell_cues"`
+	CornellNotes          *string         `json:"cornell_notes"`
+	CornellSummary        *string         `json:"cornell_summary"`
+	Tags                  []string        `json:"tags"` // nil on versions saved before tags were kept
+	WordCount             int             `json:"word_count"`
+	IsQualityFallback     bool            `json:"is_quality_fallback"`
+	QualityFallbackReason *string         `json:"quality_fallback_reason,omitempty"`
+	CreatedAt             time.Time       `json:"created_at"`
 }
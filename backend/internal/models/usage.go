@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageEvent is one Gemini call attributed to a user, and to the job it ran
+// for when there is one.
+type UsageEvent struct {
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"-"`
+	JobID            *uuid.UUID `json:"job_id,omitempty"`
+	Operation        string     `json:"operation"`
+	PromptTokens     int        `json:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// UsageOperationStats totals one operation type over a report period.
+type UsageOperationStats struct {
+	Operation        string `json:"operation"`
+	Requests         int    `json:"requests"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+}
+
+// UsageReport is a user's Gemini consumption for [PeriodStart, PeriodEnd).
+type UsageReport struct {
+	PeriodStart           time.Time             `json:"period_start"`
+	PeriodEnd             time.Time             `json:"period_end"`
+	TotalRequests         int                   `json:"total_requests"`
+	TotalPromptTokens     int                   `json:"total_prompt_tokens"`
+	TotalCompletionTokens int                   `json:"total_completion_tokens"`
+	TotalTokens           int                   `json:"total_tokens"`
+	Operations            []UsageOperationStats `json:"operations"`
+}
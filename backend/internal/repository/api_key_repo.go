@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+type APIKeyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepo(pool *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{pool: pool}
+}
+
+// Create stores a new key under the hash of its secret.
+func (r *APIKeyRepo) Create(ctx context.Context, k *models.APIKey, keyHash string) error {
+	k.ID = uuid.New()
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scope)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`,
+		k.ID, k.UserID, k.Name, k.Prefix, keyHash, k.Scope,
+	).Scan(&k.CreatedAt)
+}
+
+func (r *APIKeyRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, user_id, name, prefix, scope, last_used_at, created_at
+		FROM api_keys WHERE user_id = $1
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Scope, &k.LastUsedAt, &k.CreatedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM api_keys WHERE user_id = $1", userID).Scan(&count)
+	return count, err
+}
+
+// GetByHash finds the key with the given secret hash. Keys of deactivated
+// accounts are not returned.
+func (r *APIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	k := &models.APIKey{}
+	err := r.pool.QueryRow(ctx, `
+		SELECT k.id, k.user_id, k.name, k.prefix, k.scope, k.last_used_at, k.created_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = $1 AND u.is_active`, keyHash,
+	).Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Scope, &k.LastUsedAt, &k.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// TouchLastUsed records that the key was just used. Writes are coalesced to
+// one a minute so busy scripts do not update the row on every request.
+func (r *APIKeyRepo) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, id)
+	return err
+}
+
+// Delete removes one of the user's keys and reports whether it existed.
+func (r *APIKeyRepo) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM api_keys WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+// recentDeckReviews is how many individual reviews a deck history lists.
+const recentDeckReviews = 20
+
+// GetDeckReviewHistory returns the deck's reviews per day over the last days
+// days and its latest individual reviews.
+func (r *FlashcardRepo) GetDeckReviewHistory(ctx context.Context, deckID uuid.UUID, days int) (*models.DeckReviewHistory, error) {
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1)).Truncate(24 * time.Hour)
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT to_char((reviewed_at AT TIME ZONE 'UTC')::date, 'YYYY-MM-DD') AS day,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE rating >= 2)
+		FROM card_reviews
+		WHERE deck_id = $1 AND reviewed_at >= $2
+		GROUP BY day
+		ORDER BY day
+	`, deckID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	daily := make([]models.DeckReviewDay, 0)
+	for rows.Next() {
+		var day models.DeckReviewDay
+		if err := rows.Scan(&day.Date, &day.Reviews, &day.Correct); err != nil {
+			return nil, err
+		}
+		daily = append(daily, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	recentRows, err := r.pool.Query(ctx, `
+		SELECT id, card_id, rating, prev_interval_days, next_interval_days, reviewed_at
+		FROM card_reviews
+		WHERE deck_id = $1
+		ORDER BY reviewed_at DESC
+		LIMIT $2
+	`, deckID, recentDeckReviews)
+	if err != nil {
+		return nil, err
+	}
+	defer recentRows.Close()
+
+	recent := make([]models.CardReview, 0)
+	for recentRows.Next() {
+		var review models.CardReview
+		if err := recentRows.Scan(&review.ID, &review.CardID, &review.Rating, &review.PrevIntervalDays, &review.NextIntervalDays, &review.ReviewedAt); err != nil {
+			return nil, err
+		}
+		recent = append(recent, review)
+	}
+	if err := recentRows.Err(); err != nil {
+		return nil, err
+	}
+
+	history := summarizeReviewDays(daily)
+	history.DeckID = deckID
+	history.Days = days
+	history.Recent = recent
+	return history, nil
+}
+
+// summarizeReviewDays fills in per-day accuracy and the overall totals.
+func summarizeReviewDays(daily []models.DeckReviewDay) *models.DeckReviewHistory {
+	history := &models.DeckReviewHistory{Daily: daily}
+	correct := 0
+	for i := range daily {
+		if daily[i].Reviews > 0 {
+			daily[i].Accuracy = float64(daily[i].Correct) / float64(daily[i].Reviews) * 100
+		}
+		history.TotalReviews += daily[i].Reviews
+		correct += daily[i].Correct
+	}
+	if history.TotalReviews > 0 {
+		history.Accuracy = float64(correct) / float64(history.TotalReviews) * 100
+	}
+	return history
+}
+
+// UndoLastReview restores the card rated most recently in the deck to its
+// state before that rating and removes the review from the log. It returns
+// pgx.ErrNoRows when the deck has no logged reviews.
+func (r *FlashcardRepo) UndoLastReview(ctx context.Context, deckID uuid.UUID) (*models.CardReview, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var review models.CardReview
+	var easeFactor float64
+	var repetitions, lapses int
+	var suspended bool
+	var nextReviewAt, lastReviewedAt *time.Time
+	err = tx.QueryRow(ctx, `
+		DELETE FROM card_reviews
+		WHERE id = (
+			SELECT id FROM card_reviews
+			WHERE deck_id = $1
+			ORDER BY reviewed_at DESC
+			LIMIT 1
+			FOR UPDATE
+		)
+		RETURNING id, card_id, rating, prev_interval_days, next_interval_days, reviewed_at,
+			prev_ease_factor, prev_repetitions, prev_next_review_at, prev_last_reviewed_at, prev_lapses, prev_suspended
+	`, deckID).Scan(
+		&review.ID, &review.CardID, &review.Rating, &review.PrevIntervalDays, &review.NextIntervalDays, &review.ReviewedAt,
+		&easeFactor, &repetitions, &nextReviewAt, &lastReviewedAt, &lapses, &suspended,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE flashcard_cards
+		SET interval_days = $2, ease_factor = $3, repetitions = $4, next_review_at = $5,
+			last_reviewed_at = $6, lapses = $7, suspended = $8
+		WHERE id = $1
+	`, review.CardID, review.PrevIntervalDays, easeFactor, repetitions, nextReviewAt, lastReviewedAt, lapses, suspended)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestSummarizeReviewDays(t *testing.T) {
+	history := summarizeReviewDays([]models.DeckReviewDay{
+		{Date: "2026-03-01", Reviews: 4, Correct: 2},
+		{Date: "2026-03-02", Reviews: 6, Correct: 6},
+	})
+
+	if history.TotalReviews != 10 {
+		t.Fatalf("expected 10 reviews, got %d", history.TotalReviews)
+	}
+	if history.Accuracy != 80 {
+		t.Fatalf("expected 80%% accuracy, got %v", history.Accuracy)
+	}
+	if history.Daily[0].Accuracy != 50 || history.Daily[1].Accuracy != 100 {
+		t.Fatalf("unexpected daily accuracy %+v", history.Daily)
+	}
+}
+
+func TestSummarizeReviewDays_NoReviews(t *testing.T) {
+	history := summarizeReviewDays([]models.DeckReviewDay{})
+	if history.TotalReviews != 0 || history.Accuracy != 0 {
+		t.Fatalf("expected empty history, got %+v", history)
+	}
+}
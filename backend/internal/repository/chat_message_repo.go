@@ -45,6 +45,37 @@ func (r *ChatMessageRepo) GetBySummaryAndUser(ctx context.Context, summaryID, us
 	return out, nil
 }
 
+// GetRecentBySummaryAndUser returns the last limit messages of the
+// conversation, oldest first.
+func (r *ChatMessageRepo) GetRecentBySummaryAndUser(ctx context.Context, summaryID, userID uuid.UUID, limit int) ([]models.ChatHistoryMessage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, summary_id, user_id, role, content, created_at
+		FROM (
+			SELECT id, summary_id, user_id, role, content, created_at
+			FROM chat_messages
+			WHERE summary_id = $1 AND user_id = $2
+			ORDER BY created_at DESC
+			LIMIT $3
+		) recent
+		ORDER BY created_at ASC
+	`, summaryID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.ChatHistoryMessage, 0, limit)
+	for rows.Next() {
+		var msg models.ChatHistoryMessage
+		if err := rows.Scan(&msg.ID, &msg.SummaryID, &msg.UserID, &msg.Role, &msg.Content, &msg.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, msg)
+	}
+
+	return out, rows.Err()
+}
+
 func (r *ChatMessageRepo) Create(ctx context.Context, summaryID, userID uuid.UUID, role, content string) (*models.ChatHistoryMessage, error) {
 	msg := &models.ChatHistoryMessage{}
 	err := r.pool.QueryRow(ctx, `
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -10,14 +11,25 @@ import (
 	"lectura-backend/internal/models"
 )
 
+// ErrOriginalNeedsEncryption is returned when an unmasked transcript would be
+// retained without encryption at rest.
+var ErrOriginalNeedsEncryption = errors.New("retaining an original transcript requires CONTENT_ENCRYPTION_KEY")
+
 type ContentRepo struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	textCipher *TextCipher
 }
 
 func NewContentRepo(pool *pgxpool.Pool) *ContentRepo {
 	return &ContentRepo{pool: pool}
 }
 
+// SetTextCipher turns on encryption at rest for transcripts. Without it they
+// are stored as plaintext.
+func (r *ContentRepo) SetTextCipher(c *TextCipher) {
+	r.textCipher = c
+}
+
 func (r *ContentRepo) Create(ctx context.Context, c *models.Content) error {
 	c.ID = uuid.New()
 
@@ -26,32 +38,100 @@ func (r *ContentRepo) Create(ctx context.Context, c *models.Content) error {
 		metaBytes = []byte("{}")
 	}
 
-	query := `INSERT INTO content (id, user_id, type, status, source_url, file_path, title, duration_seconds, metadata_json)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING created_at`
+	query := `INSERT INTO content (id, user_id, type, status, source_url, file_path, title, duration_seconds, metadata_json, content_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING created_at`
 
 	return r.pool.QueryRow(ctx, query,
 		c.ID, c.UserID, c.Type, c.Status, c.SourceURL, c.FilePath, c.Title,
-		c.DurationSeconds, metaBytes,
+		c.DurationSeconds, metaBytes, c.ContentHash,
 	).Scan(&c.CreatedAt)
 }
 
+// FindCompletedByHash returns the user's most recent successfully processed
+// content with the given source fingerprint, or pgx.ErrNoRows.
+func (r *ContentRepo) FindCompletedByHash(ctx context.Context, userID uuid.UUID, hash string) (*models.Content, error) {
+	c := &models.Content{}
+	query := `SELECT id, user_id, type, status, source_url, file_path, title, duration_seconds, transcript, metadata_json, created_at, detected_language, content_hash, redacted
+		FROM content
+		WHERE user_id = $1 AND content_hash = $2 AND status = 'completed'
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	err := r.pool.QueryRow(ctx, query, userID, hash).Scan(
+		&c.ID, &c.UserID, &c.Type, &c.Status, &c.SourceURL, &c.FilePath,
+		&c.Title, &c.DurationSeconds, &c.Transcript, &c.MetadataJSON, &c.CreatedAt, &c.DetectedLanguage, &c.ContentHash, &c.Redacted,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.textCipher.decryptInPlace(c.Transcript); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func (r *ContentRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error) {
 	c := &models.Content{}
-	query := `SELECT id, user_id, type, status, source_url, file_path, title, duration_seconds, transcript, metadata_json, created_at
+	query := `SELECT id, user_id, type, status, source_url, file_path, title, duration_seconds, transcript, metadata_json, created_at, detected_language, redacted
 		FROM content WHERE id = $1`
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&c.ID, &c.UserID, &c.Type, &c.Status, &c.SourceURL, &c.FilePath,
-		&c.Title, &c.DurationSeconds, &c.Transcript, &c.MetadataJSON, &c.CreatedAt,
+		&c.Title, &c.DurationSeconds, &c.Transcript, &c.MetadataJSON, &c.CreatedAt, &c.DetectedLanguage, &c.Redacted,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := r.textCipher.decryptInPlace(c.Transcript); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
 func (r *ContentRepo) UpdateTranscript(ctx context.Context, id uuid.UUID, transcript string) error {
-	_, err := r.pool.Exec(ctx, "UPDATE content SET transcript = $1, status = 'completed' WHERE id = $2", transcript, id)
+	stored, err := r.textCipher.Encrypt(transcript)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, "UPDATE content SET transcript = $1, status = 'completed' WHERE id = $2", stored, id)
+	return err
+}
+
+// EncryptsText reports whether transcripts are encrypted at rest, which
+// retaining an unmasked original requires.
+func (r *ContentRepo) EncryptsText() bool {
+	return r.textCipher != nil
+}
+
+// UpdateRedactedTranscript stores a transcript that had personal data masked.
+// original is the unmasked transcript, kept only when the owner asked to
+// retain it; nil leaves none. An original is never stored in plaintext:
+// without a text cipher it is refused with ErrOriginalNeedsEncryption.
+func (r *ContentRepo) UpdateRedactedTranscript(ctx context.Context, id uuid.UUID, transcript string, original *string) error {
+	if original != nil && !r.EncryptsText() {
+		return ErrOriginalNeedsEncryption
+	}
+	stored, err := r.textCipher.Encrypt(transcript)
+	if err != nil {
+		return err
+	}
+	var storedOriginal *string
+	if original != nil {
+		enc, err := r.textCipher.Encrypt(*original)
+		if err != nil {
+			return err
+		}
+		storedOriginal = &enc
+	}
+	_, err = r.pool.Exec(ctx,
+		"UPDATE content SET transcript = $1, original_transcript = $2, redacted = TRUE, status = 'completed' WHERE id = $3",
+		stored, storedOriginal, id)
+	return err
+}
+
+// UpdateDetectedLanguage records the language detected from the transcript.
+func (r *ContentRepo) UpdateDetectedLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE content SET detected_language = $1 WHERE id = $2", language, id)
 	return err
 }
 
@@ -60,6 +140,13 @@ func (r *ContentRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status str
 	return err
 }
 
+// ResetForReprocessing clears the transcript of failed content and puts it back
+// into the pending state ahead of a new content-processing job.
+func (r *ContentRepo) ResetForReprocessing(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE content SET transcript = NULL, original_transcript = NULL, redacted = FALSE, detected_language = NULL, status = 'pending' WHERE id = $1", id)
+	return err
+}
+
 func (r *ContentRepo) UpdateMetadata(ctx context.Context, id uuid.UUID, metadataJSON json.RawMessage) error {
 	metaBytes := []byte("{}")
 	if len(metadataJSON) > 0 {
@@ -68,3 +155,11 @@ func (r *ContentRepo) UpdateMetadata(ctx context.Context, id uuid.UUID, metadata
 	_, err := r.pool.Exec(ctx, "UPDATE content SET metadata_json = $1 WHERE id = $2", metaBytes, id)
 	return err
 }
+
+func (r *ContentRepo) RefreshMetadata(ctx context.Context, id uuid.UUID, title string, durationSeconds int, metadataJSON json.RawMessage) error {
+	_, err := r.pool.Exec(ctx,
+		"UPDATE content SET title = $1, duration_seconds = $2, metadata_json = $3 WHERE id = $4",
+		title, durationSeconds, metadataJSON, id,
+	)
+	return err
+}
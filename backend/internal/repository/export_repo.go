@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+// ExportRepo collects a single user's data for personal data exports. Every
+// query is scoped by user_id (directly or through the owning deck/quiz).
+type ExportRepo struct {
+	pool       *pgxpool.Pool
+	textCipher *TextCipher
+}
+
+func NewExportRepo(pool *pgxpool.Pool) *ExportRepo {
+	return &ExportRepo{pool: pool}
+}
+
+// SetTextCipher lets exports read summary bodies encrypted by SummaryRepo and
+// original transcripts encrypted by ContentRepo.
+func (r *ExportRepo) SetTextCipher(c *TextCipher) {
+	r.textCipher = c
+}
+
+// CountRows returns roughly how many records an export for the user would
+// contain, used to decide between a streamed and a background export.
+func (r *ExportRepo) CountRows(ctx context.Context, userID uuid.UUID) (int, error) {
+	var total int
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM summaries WHERE user_id = $1) +
+			(SELECT COUNT(*) FROM quizzes WHERE user_id = $1) +
+			(SELECT COUNT(*) FROM quiz_attempts WHERE user_id = $1) +
+			(SELECT COUNT(*) FROM flashcard_decks WHERE user_id = $1) +
+			(SELECT COUNT(*) FROM flashcard_cards fc JOIN flashcard_decks fd ON fd.id = fc.deck_id WHERE fd.user_id = $1) +
+			(SELECT COUNT(*) FROM study_sessions WHERE user_id = $1) +
+			(SELECT COUNT(*) FROM content WHERE user_id = $1 AND original_transcript IS NOT NULL)
+	`, userID).Scan(&total)
+	return total, err
+}
+
+// CollectUserData loads everything the user owns, including items still in the
+// trash. Password hashes, OAuth/billing identifiers and owner IDs are never read.
+func (r *ExportRepo) CollectUserData(ctx context.Context, userID uuid.UUID) (*models.UserDataExport, error) {
+	data := &models.UserDataExport{
+		ExportedAt:    time.Now().UTC(),
+		Summaries:     []models.ExportSummary{},
+		Quizzes:       []models.ExportQuiz{},
+		QuizAttempts:  []models.ExportQuizAttempt{},
+		Decks:         []models.ExportDeck{},
+		Cards:         []models.ExportCard{},
+		StudySessions: []models.ExportStudySession{},
+
+		OriginalTranscripts: []models.ExportOriginalTranscript{},
+	}
+
+	p := &data.Profile
+	err := r.pool.QueryRow(ctx, `
+		SELECT email, full_name, bio, avatar_url, plan, COALESCE(auth_provider, 'local'), is_verified, created_at, last_login_at
+		FROM users WHERE id = $1`, userID,
+	).Scan(&p.Email, &p.FullName, &p.Bio, &p.AvatarURL, &p.Plan, &p.AuthProvider, &p.IsVerified, &p.CreatedAt, &p.LastLoginAt)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.ExportSettings{}
+	err = r.pool.QueryRow(ctx, `
+		SELECT default_summary_length, default_format, default_difficulty, language, notifications_json, updated_at
+		FROM user_settings WHERE user_id = $1`, userID,
+	).Scan(&settings.DefaultSummaryLength, &settings.DefaultFormat, &settings.DefaultDifficulty, &settings.Language, &settings.Notifications, &settings.UpdatedAt)
+	if err == nil {
+		data.Settings = settings
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.title, COALESCE(c.type, ''), s.format, s.length_setting, s.content_raw,
+			s.cornell_cues, s.cornell_notes, s.cornell_summary, COALESCE(s.tags, '{}'), s.description,
+			s.word_count, s.is_favorite, s.is_archived, s.created_at, s.deleted_at
+		FROM summaries s
+		LEFT JOIN content c ON c.id = s.content_id
+		WHERE s.user_id = $1
+		ORDER BY s.created_at ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var s models.ExportSummary
+		if err := rows.Scan(&s.ID, &s.Title, &s.Source, &s.Format, &s.Length, &s.Content,
+			&s.CornellCues, &s.CornellNotes, &s.CornellSummary, &s.Tags, &s.Description,
+			&s.WordCount, &s.IsFavorite, &s.IsArchived, &s.CreatedAt, &s.DeletedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if err := r.textCipher.decryptInPlace(s.Content, s.CornellCues, s.CornellNotes, s.CornellSummary); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.Summaries = append(data.Summaries, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, summary_id, title, config_json, questions_json, question_count, is_favorite, created_at, deleted_at
+		FROM quizzes
+		WHERE user_id = $1
+		ORDER BY created_at ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var q models.ExportQuiz
+		if err := rows.Scan(&q.ID, &q.SummaryID, &q.Title, &q.Config, &q.Questions, &q.QuestionCount,
+			&q.IsFavorite, &q.CreatedAt, &q.DeletedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.Quizzes = append(data.Quizzes, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, quiz_id, answers_json, score_percent::float8, correct_count, started_at, completed_at, time_taken_seconds
+		FROM quiz_attempts
+		WHERE user_id = $1
+		ORDER BY started_at ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var a models.ExportQuizAttempt
+		if err := rows.Scan(&a.ID, &a.QuizID, &a.Answers, &a.ScorePercent, &a.CorrectCount,
+			&a.StartedAt, &a.CompletedAt, &a.TimeTakenSeconds); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.QuizAttempts = append(data.QuizAttempts, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, summary_id, title, config_json, card_count, is_favorite, created_at, deleted_at
+		FROM flashcard_decks
+		WHERE user_id = $1
+		ORDER BY created_at ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var d models.ExportDeck
+		if err := rows.Scan(&d.ID, &d.SummaryID, &d.Title, &d.Config, &d.CardCount, &d.IsFavorite,
+			&d.CreatedAt, &d.DeletedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.Decks = append(data.Decks, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT fc.deck_id, fc.front, fc.back, fc.mnemonic, fc.example, fc.topic, fc.difficulty,
+			fc.interval_days, fc.ease_factor::float8, fc.repetitions, fc.next_review_at, fc.last_reviewed_at
+		FROM flashcard_cards fc
+		JOIN flashcard_decks fd ON fd.id = fc.deck_id
+		WHERE fd.user_id = $1
+		ORDER BY fd.created_at ASC, fc.id ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var c models.ExportCard
+		if err := rows.Scan(&c.DeckID, &c.Front, &c.Back, &c.Mnemonic, &c.Example, &c.Topic, &c.Difficulty,
+			&c.IntervalDays, &c.EaseFactor, &c.Repetitions, &c.NextReviewAt, &c.LastReviewedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.Cards = append(data.Cards, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT activity_type, resource_id, started_at, ended_at, duration_seconds
+		FROM study_sessions
+		WHERE user_id = $1
+		ORDER BY started_at ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var s models.ExportStudySession
+		if err := rows.Scan(&s.ActivityType, &s.ResourceID, &s.StartedAt, &s.EndedAt, &s.DurationSeconds); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.StudySessions = append(data.StudySessions, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, title, original_transcript, created_at
+		FROM content
+		WHERE user_id = $1 AND original_transcript IS NOT NULL
+		ORDER BY created_at ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var t models.ExportOriginalTranscript
+		if err := rows.Scan(&t.ContentID, &t.Title, &t.Transcript, &t.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if err := r.textCipher.decryptInPlace(&t.Transcript); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		data.OriginalTranscripts = append(data.OriginalTranscripts, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,21 +31,23 @@ func (r *FlashcardRepo) CreateDeck(ctx context.Context, d *models.FlashcardDeck)
 		configBytes = []byte("{}")
 	}
 
-	query := `INSERT INTO flashcard_decks (id, user_id, summary_id, title, config_json, card_count)
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`
+	query := `INSERT INTO flashcard_decks (id, user_id, summary_id, content_id, title, config_json, card_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at`
 
 	return r.pool.QueryRow(ctx, query,
-		d.ID, d.UserID, d.SummaryID, d.Title, configBytes, d.CardCount,
+		d.ID, d.UserID, d.SummaryID, d.ContentID, d.Title, configBytes, d.CardCount,
 	).Scan(&d.CreatedAt)
 }
 
 func (r *FlashcardRepo) GetDeckByID(ctx context.Context, id uuid.UUID) (*models.FlashcardDeck, error) {
 	d := &models.FlashcardDeck{}
-	query := `SELECT id, user_id, summary_id, title, config_json, card_count, is_favorite, created_at
-		FROM flashcard_decks WHERE id = $1`
+	query := `SELECT id, user_id, summary_id, title, config_json, card_count, is_favorite, created_at, original_author_name, content_id,
+			scheduling_params
+		FROM flashcard_decks WHERE id = $1 AND deleted_at IS NULL`
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&d.ID, &d.UserID, &d.SummaryID, &d.Title, &d.ConfigJSON, &d.CardCount, &d.IsFavorite, &d.CreatedAt,
+		&d.ID, &d.UserID, &d.SummaryID, &d.Title, &d.ConfigJSON, &d.CardCount, &d.IsFavorite, &d.CreatedAt, &d.OriginalAuthor, &d.ContentID,
+		&d.SchedulingParams,
 	)
 	if err != nil {
 		return nil, err
@@ -53,33 +55,80 @@ func (r *FlashcardRepo) GetDeckByID(ctx context.Context, id uuid.UUID) (*models.
 	return d, nil
 }
 
-func (r *FlashcardRepo) ListDecksByUser(ctx context.Context, userID uuid.UUID) ([]*models.FlashcardDeck, error) {
+func (r *FlashcardRepo) ListDecksByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.FlashcardDeck, int, error) {
+	search = strings.TrimSpace(search)
+	searchLike := "%" + search + "%"
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM flashcard_decks
+		WHERE user_id = $1 AND deleted_at IS NULL AND is_archived = FALSE AND ($2 = '' OR title ILIKE $3)
+		  AND ($4 = FALSE OR is_favorite = TRUE)`
+	if err := r.pool.QueryRow(ctx, countQuery, userID, search, searchLike, favoriteOnly).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	orderBy := "created_at DESC"
+	switch sortBy {
+	case "title":
+		orderBy = "title ASC"
+	case "oldest":
+		orderBy = "created_at ASC"
+	case "recent":
+		orderBy = "last_accessed_at DESC NULLS LAST, created_at DESC"
+	}
+
 	query := `SELECT id, user_id, summary_id, title, config_json, card_count, is_favorite, created_at
-		FROM flashcard_decks WHERE user_id = $1 ORDER BY created_at DESC`
+		FROM flashcard_decks
+		WHERE user_id = $1 AND deleted_at IS NULL AND is_archived = FALSE AND ($2 = '' OR title ILIKE $3)
+		  AND ($4 = FALSE OR is_favorite = TRUE)
+		ORDER BY ` + orderBy + `
+		LIMIT $5 OFFSET $6`
 
-	rows, err := r.pool.Query(ctx, query, userID)
+	rows, err := r.pool.Query(ctx, query, userID, search, searchLike, favoriteOnly, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var decks []*models.FlashcardDeck
+	decks := make([]*models.FlashcardDeck, 0)
 	for rows.Next() {
 		d := &models.FlashcardDeck{}
 		err := rows.Scan(&d.ID, &d.UserID, &d.SummaryID, &d.Title, &d.ConfigJSON, &d.CardCount, &d.IsFavorite, &d.CreatedAt)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		decks = append(decks, d)
 	}
-	return decks, nil
+	return decks, total, nil
 }
 
+// DeleteDeck moves the deck to the trash; see RestoreDeck and TrashRepo.PurgeExpired.
 func (r *FlashcardRepo) DeleteDeck(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE flashcard_decks SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
+	return err
+}
+
+// HardDeleteDeck removes the row immediately, bypassing the trash.
+func (r *FlashcardRepo) HardDeleteDeck(ctx context.Context, id uuid.UUID) error {
 	_, err := r.pool.Exec(ctx, "DELETE FROM flashcard_decks WHERE id = $1", id)
 	return err
 }
 
+// RestoreDeck clears deleted_at if the row was soft-deleted within the recovery
+// window. It reports false when there is nothing restorable.
+func (r *FlashcardRepo) RestoreDeck(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE flashcard_decks SET deleted_at = NULL
+		 WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		   AND deleted_at > NOW() - make_interval(days => $3)`,
+		id, userID, trashRetentionDays,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (r *FlashcardRepo) ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	_, err := r.pool.Exec(ctx, "UPDATE flashcard_decks SET is_favorite = NOT is_favorite WHERE id = $1 AND user_id = $2", id, userID)
 	return err
@@ -96,6 +145,225 @@ func (r *FlashcardRepo) TouchLastAccessed(ctx context.Context, id uuid.UUID) (bo
 	return tag.RowsAffected() == 1, nil
 }
 
+// MergeDecks creates a new deck for userID containing the cards of every
+// source deck, all inside one transaction. Sources must belong to userID.
+func (r *FlashcardRepo) MergeDecks(ctx context.Context, userID uuid.UUID, req models.MergeDecksRequest) (*models.FlashcardDeck, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var owned int
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM (
+			SELECT id FROM flashcard_decks
+			WHERE id = ANY($1::uuid[]) AND user_id = $2 AND deleted_at IS NULL
+			FOR UPDATE
+		) owned`,
+		req.SourceIDs, userID,
+	).Scan(&owned)
+	if err != nil {
+		return nil, err
+	}
+	if owned != len(req.SourceIDs) {
+		return nil, fmt.Errorf("merge decks: %d of %d source decks are not owned by user", len(req.SourceIDs)-owned, len(req.SourceIDs))
+	}
+
+	configBytes, _ := json.Marshal(map[string]interface{}{
+		"merged_from":       req.SourceIDs,
+		"preserve_progress": req.PreserveProgress,
+	})
+	deck := &models.FlashcardDeck{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Title:      req.Title,
+		ConfigJSON: configBytes,
+	}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO flashcard_decks (id, user_id, title, config_json, card_count)
+		 VALUES ($1, $2, $3, $4, 0) RETURNING created_at`,
+		deck.ID, userID, deck.Title, configBytes,
+	).Scan(&deck.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	copied, err := r.CopyCards(ctx, tx, req.SourceIDs, deck.ID, req.PreserveProgress)
+	if err != nil {
+		return nil, err
+	}
+	deck.CardCount = int(copied)
+
+	if _, err := tx.Exec(ctx, "UPDATE flashcard_decks SET card_count = $1 WHERE id = $2", deck.CardCount, deck.ID); err != nil {
+		return nil, err
+	}
+
+	if req.DeleteSources {
+		if _, err := tx.Exec(ctx,
+			"UPDATE flashcard_decks SET deleted_at = NOW() WHERE id = ANY($1::uuid[]) AND user_id = $2 AND deleted_at IS NULL",
+			req.SourceIDs, userID,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
+// CopyCards copies the cards of sourceDeckIDs into targetDeckID, keeping one
+// card per distinct front (case- and whitespace-insensitive). When several
+// cards share a front, the most-reviewed one wins. Unless preserveProgress is
+// set, copies start over as new cards. Returns the number of cards copied.
+func (r *FlashcardRepo) CopyCards(ctx context.Context, tx pgx.Tx, sourceDeckIDs []uuid.UUID, targetDeckID uuid.UUID, preserveProgress bool) (int64, error) {
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO flashcard_cards (id, deck_id, front, back, mnemonic, example, topic, difficulty,
+			interval_days, ease_factor, repetitions, next_review_at, last_reviewed_at, lapses, suspended)
+		 SELECT gen_random_uuid(), $2, front, back, mnemonic, example, topic, difficulty,
+			CASE WHEN $3::boolean THEN interval_days ELSE 1 END,
+			CASE WHEN $3::boolean THEN ease_factor ELSE 2.50 END,
+			CASE WHEN $3::boolean THEN repetitions ELSE 0 END,
+			CASE WHEN $3::boolean THEN next_review_at ELSE CURRENT_DATE + 1 END,
+			CASE WHEN $3::boolean THEN last_reviewed_at ELSE NULL END,
+			CASE WHEN $3::boolean THEN lapses ELSE 0 END,
+			$3::boolean AND suspended
+		 FROM (
+			SELECT DISTINCT ON (lower(btrim(front))) *
+			FROM flashcard_cards
+			WHERE deck_id = ANY($1::uuid[])
+			ORDER BY lower(btrim(front)), repetitions DESC, last_reviewed_at DESC NULLS LAST, id
+		 ) deduped`,
+		sourceDeckIDs, targetDeckID, preserveProgress,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Sharing
+
+// ShareDeck gives the deck a share slug, keeping the existing one if the deck
+// is already shared.
+func (r *FlashcardRepo) ShareDeck(ctx context.Context, id uuid.UUID, slug string) (*models.ShareLink, error) {
+	link := &models.ShareLink{}
+	err := r.pool.QueryRow(ctx,
+		`UPDATE flashcard_decks
+		 SET share_slug = COALESCE(share_slug, $2), shared_at = COALESCE(shared_at, NOW())
+		 WHERE id = $1 AND deleted_at IS NULL
+		 RETURNING share_slug, shared_at`,
+		id, slug,
+	).Scan(&link.Slug, &link.SharedAt)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// UnshareDeck revokes the deck's share link. Copies already imported are kept.
+func (r *FlashcardRepo) UnshareDeck(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE flashcard_decks SET share_slug = NULL, shared_at = NULL WHERE id = $1 AND deleted_at IS NULL", id)
+	return err
+}
+
+// GetSharedDeck loads the public preview of a shared deck, with at most
+// maxCards cards.
+func (r *FlashcardRepo) GetSharedDeck(ctx context.Context, slug string, maxCards int) (*models.SharedDeck, error) {
+	deck := &models.SharedDeck{Cards: []models.SharedCard{}}
+	var deckID uuid.UUID
+	err := r.pool.QueryRow(ctx,
+		`SELECT d.id, d.title, d.card_count, d.shared_at, COALESCE(d.original_author_name, u.full_name)
+		 FROM flashcard_decks d
+		 JOIN users u ON u.id = d.user_id
+		 WHERE d.share_slug = $1 AND d.deleted_at IS NULL`,
+		slug,
+	).Scan(&deckID, &deck.Title, &deck.CardCount, &deck.SharedAt, &deck.AuthorName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT front, back, mnemonic, example, COALESCE(topic, ''), COALESCE(difficulty, 1)
+		 FROM flashcard_cards WHERE deck_id = $1
+		 ORDER BY topic NULLS LAST, front, id
+		 LIMIT $2`,
+		deckID, maxCards,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.SharedCard
+		if err := rows.Scan(&c.Front, &c.Back, &c.Mnemonic, &c.Example, &c.Topic, &c.Difficulty); err != nil {
+			return nil, err
+		}
+		deck.Cards = append(deck.Cards, c)
+	}
+	return deck, rows.Err()
+}
+
+// ImportSharedDeck copies a shared deck and its cards into userID's account.
+// Cards start over as new, and the copy keeps only what the preview shows:
+// no summary link, folder or references to the sharer's other decks. Decks
+// with more than maxCards cards are rejected with ErrShareTooLarge.
+func (r *FlashcardRepo) ImportSharedDeck(ctx context.Context, slug string, userID uuid.UUID, maxCards int) (*models.FlashcardDeck, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var sourceID uuid.UUID
+	var cardCount int
+	deck := &models.FlashcardDeck{ID: uuid.New(), UserID: userID}
+	author := ""
+	err = tx.QueryRow(ctx,
+		`SELECT d.id, d.title, COALESCE(d.config_json, '{}'::jsonb) - 'summary_id' - 'content_id' - 'merged_from',
+			COALESCE(d.original_author_name, u.full_name),
+			(SELECT COUNT(*) FROM flashcard_cards c WHERE c.deck_id = d.id)
+		 FROM flashcard_decks d
+		 JOIN users u ON u.id = d.user_id
+		 WHERE d.share_slug = $1 AND d.deleted_at IS NULL`,
+		slug,
+	).Scan(&sourceID, &deck.Title, &deck.ConfigJSON, &author, &cardCount)
+	if err != nil {
+		return nil, err
+	}
+	if cardCount > maxCards {
+		return nil, ErrShareTooLarge
+	}
+	deck.OriginalAuthor = &author
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO flashcard_decks (id, user_id, title, config_json, card_count, original_author_name, imported_from_id)
+		 VALUES ($1, $2, $3, $4, 0, $5, $6) RETURNING created_at`,
+		deck.ID, userID, deck.Title, deck.ConfigJSON, author, sourceID,
+	).Scan(&deck.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	copied, err := r.CopyCards(ctx, tx, []uuid.UUID{sourceID}, deck.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	deck.CardCount = int(copied)
+
+	if _, err := tx.Exec(ctx, "UPDATE flashcard_decks SET card_count = $1 WHERE id = $2", deck.CardCount, deck.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
 // Card operations
 
 func (r *FlashcardRepo) CreateCards(ctx context.Context, deckID uuid.UUID, cards []models.FlashcardCard) error {
@@ -119,12 +387,17 @@ func (r *FlashcardRepo) CreateCards(ctx context.Context, deckID uuid.UUID, cards
 	return err
 }
 
-func (r *FlashcardRepo) GetCardsByDeck(ctx context.Context, deckID uuid.UUID) ([]models.FlashcardCard, error) {
-	query := `SELECT id, deck_id, front, back, mnemonic, example, topic, difficulty,
-		interval_days, ease_factor, repetitions, next_review_at, last_reviewed_at
-		FROM flashcard_cards WHERE deck_id = $1 ORDER BY repetitions ASC, next_review_at ASC, id ASC`
-
-	rows, err := r.pool.Query(ctx, query, deckID)
+// GetCardsByDeck lists a deck's cards in study order. Suspended cards are
+// left out unless includeSuspended is set; a deleted deck has no cards.
+func (r *FlashcardRepo) GetCardsByDeck(ctx context.Context, deckID uuid.UUID, includeSuspended bool) ([]models.FlashcardCard, error) {
+	query := `SELECT c.id, c.deck_id, c.front, c.back, c.mnemonic, c.example, c.topic, c.difficulty,
+		c.interval_days, c.ease_factor, c.repetitions, c.next_review_at, c.last_reviewed_at,
+		c.lapses, c.lapses >= ` + leechThresholdSQL("$3") + `, c.suspended
+		FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		WHERE c.deck_id = $1 AND d.deleted_at IS NULL AND ($2::boolean OR NOT c.suspended)
+		ORDER BY c.repetitions ASC, c.next_review_at ASC, c.id ASC`
+
+	rows, err := r.pool.Query(ctx, query, deckID, includeSuspended, defaultLeechThreshold)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +409,7 @@ func (r *FlashcardRepo) GetCardsByDeck(ctx context.Context, deckID uuid.UUID) ([
 		err := rows.Scan(
 			&c.ID, &c.DeckID, &c.Front, &c.Back, &c.Mnemonic, &c.Example, &c.Topic,
 			&c.Difficulty, &c.IntervalDays, &c.EaseFactor, &c.Repetitions, &c.NextReviewAt, &c.LastReviewedAt,
+			&c.Lapses, &c.IsLeech, &c.Suspended,
 		)
 		if err != nil {
 			return nil, err
@@ -145,17 +419,100 @@ func (r *FlashcardRepo) GetCardsByDeck(ctx context.Context, deckID uuid.UUID) ([
 	return cards, nil
 }
 
+// GetLeechCards lists the deck's leeches, cards whose lapse streak reached
+// the deck's leech threshold, most lapses first. Suspended leeches are
+// included so they can be rewritten and put back.
+func (r *FlashcardRepo) GetLeechCards(ctx context.Context, deckID uuid.UUID) ([]models.FlashcardCard, error) {
+	query := `SELECT c.id, c.deck_id, c.front, c.back, c.mnemonic, c.example, c.topic, c.difficulty,
+		c.interval_days, c.ease_factor, c.repetitions, c.next_review_at, c.last_reviewed_at,
+		c.lapses, TRUE, c.suspended
+		FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		WHERE c.deck_id = $1 AND d.deleted_at IS NULL AND c.lapses >= ` + leechThresholdSQL("$2") + `
+		ORDER BY c.lapses DESC, c.id ASC`
+
+	rows, err := r.pool.Query(ctx, query, deckID, defaultLeechThreshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := []models.FlashcardCard{}
+	for rows.Next() {
+		c := models.FlashcardCard{}
+		err := rows.Scan(
+			&c.ID, &c.DeckID, &c.Front, &c.Back, &c.Mnemonic, &c.Example, &c.Topic,
+			&c.Difficulty, &c.IntervalDays, &c.EaseFactor, &c.Repetitions, &c.NextReviewAt, &c.LastReviewedAt,
+			&c.Lapses, &c.IsLeech, &c.Suspended,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+// ListDueCards returns up to limit of the user's cards that are due for
+// review, across every deck in their library, most overdue first, and the
+// number of cards due in total. Suspended cards and cards in deleted or
+// archived decks are left out.
+func (r *FlashcardRepo) ListDueCards(ctx context.Context, userID uuid.UUID, limit int) ([]models.DueCard, int, error) {
+	var total int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		 WHERE d.user_id = $1 AND d.deleted_at IS NULL AND d.is_archived = FALSE
+		   AND NOT c.suspended AND c.next_review_at <= NOW()`,
+		userID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT c.id, c.deck_id, c.front, c.back, c.mnemonic, c.example, c.topic, c.difficulty,
+		c.interval_days, c.ease_factor, c.repetitions, c.next_review_at, c.last_reviewed_at,
+		c.lapses, c.lapses >= ` + leechThresholdSQL("$3") + `, c.suspended, d.title
+		FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		WHERE d.user_id = $1 AND d.deleted_at IS NULL AND d.is_archived = FALSE
+		  AND NOT c.suspended AND c.next_review_at <= NOW()
+		ORDER BY c.next_review_at ASC, c.id ASC
+		LIMIT $2`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, defaultLeechThreshold)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	cards := []models.DueCard{}
+	for rows.Next() {
+		c := models.DueCard{}
+		err := rows.Scan(
+			&c.ID, &c.DeckID, &c.Front, &c.Back, &c.Mnemonic, &c.Example, &c.Topic,
+			&c.Difficulty, &c.IntervalDays, &c.EaseFactor, &c.Repetitions, &c.NextReviewAt, &c.LastReviewedAt,
+			&c.Lapses, &c.IsLeech, &c.Suspended, &c.DeckTitle,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, total, rows.Err()
+}
+
+// GetCardByID returns a card, unless its deck has been deleted.
 func (r *FlashcardRepo) GetCardByID(ctx context.Context, id uuid.UUID) (*models.FlashcardCard, error) {
 	c := &models.FlashcardCard{}
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, deck_id, front, back, mnemonic, example, topic, difficulty,
-		 interval_days, ease_factor, repetitions, next_review_at, last_reviewed_at
-		 FROM flashcard_cards WHERE id = $1`,
-		id,
+		`SELECT c.id, c.deck_id, c.front, c.back, c.mnemonic, c.example, c.topic, c.difficulty,
+		 c.interval_days, c.ease_factor, c.repetitions, c.next_review_at, c.last_reviewed_at,
+		 c.lapses, c.lapses >= `+leechThresholdSQL("$2")+`, c.suspended
+		 FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		 WHERE c.id = $1 AND d.deleted_at IS NULL`,
+		id, defaultLeechThreshold,
 	).Scan(
 		&c.ID, &c.DeckID, &c.Front, &c.Back, &c.Mnemonic, &c.Example, &c.Topic,
 		&c.Difficulty, &c.IntervalDays, &c.EaseFactor, &c.Repetitions,
-		&c.NextReviewAt, &c.LastReviewedAt,
+		&c.NextReviewAt, &c.LastReviewedAt, &c.Lapses, &c.IsLeech, &c.Suspended,
 	)
 	if err != nil {
 		return nil, err
@@ -163,38 +520,53 @@ func (r *FlashcardRepo) GetCardByID(ctx context.Context, id uuid.UUID) (*models.
 	return c, nil
 }
 
-// SM-2 Algorithm — pure math, no Gemini
+// SM-2 Algorithm — pure math, no Gemini. Also tracks lapses so leeches can
+// be flagged and, if the deck asks for it, suspended. Every rating is logged
+// to card_reviews with the card's previous state so it can be undone.
 func (r *FlashcardRepo) RateCard(ctx context.Context, cardID uuid.UUID, rating int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// Get current card values
+	var deckID uuid.UUID
 	var interval int
 	var easeFactor float64
 	var repetitions int
-
-	err := r.pool.QueryRow(ctx,
-		"SELECT interval_days, ease_factor, repetitions FROM flashcard_cards WHERE id = $1",
+	var lapses int
+	var suspended bool
+	var nextReviewAt, lastReviewedAt *time.Time
+	var params *models.SchedulingParams
+
+	err = tx.QueryRow(ctx,
+		`SELECT c.deck_id, c.interval_days, c.ease_factor, c.repetitions, c.lapses, c.suspended,
+		 c.next_review_at, c.last_reviewed_at, d.scheduling_params
+		 FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		 WHERE c.id = $1
+		 FOR UPDATE OF c`,
 		cardID,
-	).Scan(&interval, &easeFactor, &repetitions)
+	).Scan(&deckID, &interval, &easeFactor, &repetitions, &lapses, &suspended, &nextReviewAt, &lastReviewedAt, &params)
 	if err != nil {
 		return err
 	}
+	effective := effectiveSchedulingParams(params)
+
+	prevInterval, prevEaseFactor, prevRepetitions, prevLapses, prevSuspended := interval, easeFactor, repetitions, lapses, suspended
 
 	// SM-2 calculation
 	if rating < 2 {
 		// Again or Hard — reset
 		repetitions = 0
-		interval = 1
 	} else {
 		// Good or Easy
 		repetitions++
-		switch repetitions {
-		case 1:
-			interval = 1
-		case 2:
-			interval = 6
-		default:
-			interval = int(math.Round(float64(interval) * easeFactor))
-		}
 	}
+	interval = nextInterval(effective, repetitions, interval, easeFactor)
+
+	lapses, suspend := rateLapses(effective, lapses, rating)
+	suspended = suspended || suspend
 
 	// Update ease factor: EF' = EF + (0.1 - (3 - rating) * (0.08 + (3 - rating) * 0.02))
 	easeFactor = easeFactor + (0.1 - float64(3-rating)*(0.08+float64(3-rating)*0.02))
@@ -204,10 +576,37 @@ func (r *FlashcardRepo) RateCard(ctx context.Context, cardID uuid.UUID, rating i
 
 	nextReview := time.Now().AddDate(0, 0, interval)
 
-	_, err = r.pool.Exec(ctx,
+	_, err = tx.Exec(ctx,
 		`UPDATE flashcard_cards SET interval_days = $1, ease_factor = $2, repetitions = $3,
-		 next_review_at = $4, last_reviewed_at = NOW() WHERE id = $5`,
-		interval, easeFactor, repetitions, nextReview, cardID,
+		 next_review_at = $4, last_reviewed_at = NOW(), lapses = $5, suspended = $6 WHERE id = $7`,
+		interval, easeFactor, repetitions, nextReview, lapses, suspended, cardID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO card_reviews (card_id, deck_id, rating, prev_interval_days, prev_ease_factor,
+		 prev_repetitions, prev_next_review_at, prev_last_reviewed_at, prev_lapses, prev_suspended, next_interval_days)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		cardID, deckID, rating, prevInterval, prevEaseFactor, prevRepetitions, nextReviewAt, lastReviewedAt,
+		prevLapses, prevSuspended, interval,
+	)
+	if err != nil {
+		return fmt.Errorf("log review: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetCardSuspended suspends or unsuspends a card. Unsuspending also clears
+// its lapses so it is no longer a leech until it racks them up again.
+func (r *FlashcardRepo) SetCardSuspended(ctx context.Context, cardID uuid.UUID, suspended bool) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE flashcard_cards
+		 SET suspended = $2, lapses = CASE WHEN $2 THEN lapses ELSE 0 END
+		 WHERE id = $1`,
+		cardID, suspended,
 	)
 	return err
 }
@@ -228,18 +627,22 @@ func getDeckStatsWithQueryRow(
 	err := queryRow(ctx, `
 		SELECT
 			COUNT(*) AS total,
-			COUNT(*) FILTER (WHERE repetitions > 0) AS mastered,
-			COUNT(*) FILTER (WHERE repetitions = 0 AND last_reviewed_at IS NOT NULL) AS learning,
-			COUNT(*) FILTER (WHERE repetitions = 0 AND last_reviewed_at IS NULL) AS new,
-			COUNT(*) FILTER (WHERE next_review_at <= CURRENT_DATE) AS due_today
-		FROM flashcard_cards
-		WHERE deck_id = $1
-	`, deckID).Scan(
+			COUNT(*) FILTER (WHERE c.repetitions > 0) AS mastered,
+			COUNT(*) FILTER (WHERE c.repetitions = 0 AND c.last_reviewed_at IS NOT NULL) AS learning,
+			COUNT(*) FILTER (WHERE c.repetitions = 0 AND c.last_reviewed_at IS NULL) AS new,
+			COUNT(*) FILTER (WHERE c.next_review_at <= CURRENT_DATE AND NOT c.suspended) AS due_today,
+			COUNT(*) FILTER (WHERE c.lapses >= `+leechThresholdSQL("$2")+`) AS leeches,
+			COUNT(*) FILTER (WHERE c.suspended) AS suspended
+		FROM flashcard_cards c JOIN flashcard_decks d ON d.id = c.deck_id
+		WHERE c.deck_id = $1 AND d.deleted_at IS NULL
+	`, deckID, defaultLeechThreshold).Scan(
 		&stats.TotalCards,
 		&stats.Mastered,
 		&stats.Learning,
 		&stats.New,
 		&stats.DueToday,
+		&stats.Leeches,
+		&stats.Suspended,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("GetDeckStats: %w", err)
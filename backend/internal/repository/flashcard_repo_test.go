@@ -19,10 +19,10 @@ func (r fakeDeckStatsRow) Scan(dest ...interface{}) error {
 	if r.scanErr != nil {
 		return r.scanErr
 	}
-	if len(dest) != 5 {
+	if len(dest) != 7 {
 		return errors.New("unexpected destination count")
 	}
-	if len(r.values) != 5 {
+	if len(r.values) != 7 {
 		return errors.New("unexpected values count")
 	}
 
@@ -56,7 +56,7 @@ func TestGetDeckStats_EmptyDeck_ReturnsZeroStats(t *testing.T) {
 	deckID := uuid.New()
 
 	stats, err := getDeckStatsWithQueryRow(context.Background(), deckID, func(context.Context, string, ...interface{}) pgx.Row {
-		return fakeDeckStatsRow{values: []int{0, 0, 0, 0, 0}}
+		return fakeDeckStatsRow{values: []int{0, 0, 0, 0, 0, 0, 0}}
 	})
 
 	if err != nil {
@@ -74,14 +74,14 @@ func TestGetDeckStats_MixedCards_CorrectCounts(t *testing.T) {
 	deckID := uuid.New()
 
 	stats, err := getDeckStatsWithQueryRow(context.Background(), deckID, func(context.Context, string, ...interface{}) pgx.Row {
-		// total, mastered, learning, new, due_today
-		return fakeDeckStatsRow{values: []int{10, 4, 3, 3, 2}}
+		// total, mastered, learning, new, due_today, leeches, suspended
+		return fakeDeckStatsRow{values: []int{10, 4, 3, 3, 2, 1, 1}}
 	})
 
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
-	if stats.TotalCards != 10 || stats.Mastered != 4 || stats.Learning != 3 || stats.New != 3 || stats.DueToday != 2 {
+	if stats.TotalCards != 10 || stats.Mastered != 4 || stats.Learning != 3 || stats.New != 3 || stats.DueToday != 2 || stats.Leeches != 1 || stats.Suspended != 1 {
 		t.Fatalf("unexpected stats: %+v", stats)
 	}
 	if math.Abs(stats.MasteryRate-40.0) > 0.0001 {
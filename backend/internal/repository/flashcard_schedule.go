@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+// maxProjectedRepetitions bounds the interval replay in projectedInterval;
+// by then any interval has long hit the cap.
+const maxProjectedRepetitions = 64
+
+// defaultLeechThreshold is how many failed reviews in a row make a card a
+// leech when the deck does not say otherwise.
+const defaultLeechThreshold = 8
+
+// DefaultSchedulingParams are classic SM-2: 1 day, then 6, then the previous
+// interval times the card's ease factor.
+func DefaultSchedulingParams() models.SchedulingParams {
+	return models.SchedulingParams{
+		FirstIntervalDays:  1,
+		SecondIntervalDays: 6,
+		IntervalModifier:   1.0,
+		MaxIntervalDays:    36500,
+		LeechThreshold:     defaultLeechThreshold,
+	}
+}
+
+// effectiveSchedulingParams fills unset fields of a deck's parameters with
+// the defaults.
+func effectiveSchedulingParams(p *models.SchedulingParams) models.SchedulingParams {
+	def := DefaultSchedulingParams()
+	if p == nil {
+		return def
+	}
+	out := *p
+	if out.FirstIntervalDays <= 0 {
+		out.FirstIntervalDays = def.FirstIntervalDays
+	}
+	if out.SecondIntervalDays <= 0 {
+		out.SecondIntervalDays = def.SecondIntervalDays
+	}
+	if out.IntervalModifier <= 0 {
+		out.IntervalModifier = def.IntervalModifier
+	}
+	if out.MaxIntervalDays <= 0 {
+		out.MaxIntervalDays = def.MaxIntervalDays
+	}
+	if out.LeechThreshold <= 0 {
+		out.LeechThreshold = def.LeechThreshold
+	}
+	return out
+}
+
+// leechThresholdSQL is the leech threshold of the deck aliased d in a query,
+// with placeholder bound to defaultLeechThreshold for decks without one.
+func leechThresholdSQL(placeholder string) string {
+	return "COALESCE(NULLIF((d.scheduling_params->>'leech_threshold')::int, 0), " + placeholder + ")"
+}
+
+// rateLapses is a card's consecutive-lapse count after a rating: failures
+// (Again, Hard) extend the streak and any success clears it. It reports
+// whether the card should now be suspended as a leech.
+func rateLapses(p models.SchedulingParams, lapses, rating int) (int, bool) {
+	if rating >= 2 {
+		return 0, false
+	}
+	lapses++
+	autoSuspend := p.AutoSuspendLeeches != nil && *p.AutoSuspendLeeches
+	return lapses, autoSuspend && lapses >= p.LeechThreshold
+}
+
+// nextInterval is the SM-2 interval for a card that has just reached
+// repetitions successful reviews in a row. Zero repetitions means it lapsed.
+func nextInterval(p models.SchedulingParams, repetitions, prevInterval int, easeFactor float64) int {
+	var interval int
+	switch {
+	case repetitions <= 0:
+		interval = 1
+	case repetitions == 1:
+		interval = p.FirstIntervalDays
+	case repetitions == 2:
+		interval = p.SecondIntervalDays
+	default:
+		interval = int(math.Round(float64(prevInterval) * easeFactor * p.IntervalModifier))
+	}
+	if interval < 1 {
+		interval = 1
+	}
+	if interval > p.MaxIntervalDays {
+		interval = p.MaxIntervalDays
+	}
+	return interval
+}
+
+// projectedInterval replays a card's streak under p. Only the current ease
+// factor is stored, so it stands in for the ease at every earlier step.
+func projectedInterval(p models.SchedulingParams, repetitions int, easeFactor float64) int {
+	if repetitions > maxProjectedRepetitions {
+		repetitions = maxProjectedRepetitions
+	}
+	interval := nextInterval(p, 0, 0, easeFactor)
+	for n := 1; n <= repetitions; n++ {
+		interval = nextInterval(p, n, interval, easeFactor)
+	}
+	return interval
+}
+
+// SetSchedulingParams stores a deck's SM-2 parameters without touching
+// existing cards; they apply from each card's next rating.
+func (r *FlashcardRepo) SetSchedulingParams(ctx context.Context, deckID uuid.UUID, params models.SchedulingParams) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE flashcard_decks SET scheduling_params = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		deckID, params,
+	)
+	return err
+}
+
+// RescheduleDeck recomputes the due dates of every reviewed card in a deck
+// under params, counting from each card's last review. Cards that were never
+// reviewed keep their dates. Unless dryRun is set, the new parameters and
+// dates are saved together; either way the cards whose schedule moves are
+// returned.
+func (r *FlashcardRepo) RescheduleDeck(ctx context.Context, deckID uuid.UUID, params models.SchedulingParams, dryRun bool) ([]models.ScheduleChange, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, front, interval_days, ease_factor::float8, repetitions, next_review_at, last_reviewed_at
+		 FROM flashcard_cards
+		 WHERE deck_id = $1 AND last_reviewed_at IS NOT NULL
+		 ORDER BY next_review_at, id
+		 FOR UPDATE`,
+		deckID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := effectiveSchedulingParams(&params)
+	changes := []models.ScheduleChange{}
+	for rows.Next() {
+		var (
+			change       models.ScheduleChange
+			easeFactor   float64
+			repetitions  int
+			lastReviewed time.Time
+		)
+		if err := rows.Scan(&change.CardID, &change.Front, &change.OldIntervalDays, &easeFactor, &repetitions, &change.OldNextReviewAt, &lastReviewed); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		change.NewIntervalDays = projectedInterval(effective, repetitions, easeFactor)
+		reviewedOn := time.Date(lastReviewed.Year(), lastReviewed.Month(), lastReviewed.Day(), 0, 0, 0, 0, time.UTC)
+		change.NewNextReviewAt = reviewedOn.AddDate(0, 0, change.NewIntervalDays)
+
+		oldDue := change.OldNextReviewAt
+		sameDay := oldDue.Year() == change.NewNextReviewAt.Year() && oldDue.YearDay() == change.NewNextReviewAt.YearDay()
+		if change.NewIntervalDays != change.OldIntervalDays || !sameDay {
+			changes = append(changes, change)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+
+	if len(changes) > 0 {
+		ids := make([]uuid.UUID, len(changes))
+		intervals := make([]int32, len(changes))
+		dueDates := make([]time.Time, len(changes))
+		for i, c := range changes {
+			ids[i] = c.CardID
+			intervals[i] = int32(c.NewIntervalDays)
+			dueDates[i] = c.NewNextReviewAt
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE flashcard_cards c
+			 SET interval_days = u.interval_days, next_review_at = u.next_review_at
+			 FROM unnest($1::uuid[], $2::int[], $3::date[]) AS u(id, interval_days, next_review_at)
+			 WHERE c.id = u.id AND c.deck_id = $4`,
+			ids, intervals, dueDates, deckID,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE flashcard_decks SET scheduling_params = $2 WHERE id = $1`,
+		deckID, params,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
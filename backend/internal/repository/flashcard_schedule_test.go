@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestNextInterval_DefaultsMatchSM2(t *testing.T) {
+	p := DefaultSchedulingParams()
+
+	tests := []struct {
+		name        string
+		repetitions int
+		prev        int
+		ease        float64
+		want        int
+	}{
+		{"lapse", 0, 30, 2.5, 1},
+		{"first success", 1, 1, 2.5, 1},
+		{"second success", 2, 1, 2.5, 6},
+		{"third success", 3, 6, 2.5, 15},
+		{"low ease", 4, 15, 1.3, 20},
+	}
+	for _, tt := range tests {
+		if got := nextInterval(p, tt.repetitions, tt.prev, tt.ease); got != tt.want {
+			t.Fatalf("%s: nextInterval() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextInterval_AppliesModifierAndCap(t *testing.T) {
+	p := models.SchedulingParams{FirstIntervalDays: 2, SecondIntervalDays: 4, IntervalModifier: 0.5, MaxIntervalDays: 10}
+
+	if got := nextInterval(p, 1, 0, 2.5); got != 2 {
+		t.Fatalf("first interval = %d, want 2", got)
+	}
+	if got := nextInterval(p, 3, 4, 2.5); got != 5 {
+		t.Fatalf("modified interval = %d, want 5", got)
+	}
+	if got := nextInterval(p, 5, 30, 2.5); got != 10 {
+		t.Fatalf("capped interval = %d, want 10", got)
+	}
+}
+
+func TestProjectedInterval_ReplaysStreak(t *testing.T) {
+	p := DefaultSchedulingParams()
+
+	if got := projectedInterval(p, 0, 2.5); got != 1 {
+		t.Fatalf("lapsed card = %d, want 1", got)
+	}
+	// 1 → 6 → 15 → 38
+	if got := projectedInterval(p, 4, 2.5); got != 38 {
+		t.Fatalf("four reviews = %d, want 38", got)
+	}
+
+	shorter := p
+	shorter.SecondIntervalDays = 3
+	// 1 → 3 → 8 → 20
+	if got := projectedInterval(shorter, 4, 2.5); got != 20 {
+		t.Fatalf("four reviews with shorter second step = %d, want 20", got)
+	}
+
+	if got := projectedInterval(p, 1000, 2.5); got != p.MaxIntervalDays {
+		t.Fatalf("long streak = %d, want cap %d", got, p.MaxIntervalDays)
+	}
+}
+
+func TestEffectiveSchedulingParams_FillsDefaults(t *testing.T) {
+	got := effectiveSchedulingParams(&models.SchedulingParams{SecondIntervalDays: 4})
+	want := DefaultSchedulingParams()
+	want.SecondIntervalDays = 4
+	if got != want {
+		t.Fatalf("effectiveSchedulingParams() = %+v, want %+v", got, want)
+	}
+	if got := effectiveSchedulingParams(nil); got != DefaultSchedulingParams() {
+		t.Fatalf("nil params = %+v, want defaults", got)
+	}
+}
+
+func TestRateLapses(t *testing.T) {
+	p := DefaultSchedulingParams()
+
+	if lapses, suspend := rateLapses(p, 3, 2); lapses != 0 || suspend {
+		t.Fatalf("success = (%d, %v), want lapses cleared", lapses, suspend)
+	}
+	if lapses, suspend := rateLapses(p, 7, 0); lapses != 8 || suspend {
+		t.Fatalf("eighth lapse without auto-suspend = (%d, %v), want (8, false)", lapses, suspend)
+	}
+
+	on := true
+	p.AutoSuspendLeeches = &on
+	if lapses, suspend := rateLapses(p, 6, 1); lapses != 7 || suspend {
+		t.Fatalf("below threshold = (%d, %v), want (7, false)", lapses, suspend)
+	}
+	if lapses, suspend := rateLapses(p, 7, 1); lapses != 8 || !suspend {
+		t.Fatalf("reaching threshold = (%d, %v), want (8, true)", lapses, suspend)
+	}
+}
@@ -40,12 +40,12 @@ func (r *JobRepo) Create(ctx context.Context, j *models.Job) error {
 
 func (r *JobRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
 	j := &models.Job{}
-	query := `SELECT id, user_id, type, reference_id, config_json, status, retry_count, error_message, created_at, completed_at
+	query := `SELECT id, user_id, type, reference_id, config_json, status, retry_count, error_message, error_code, created_at, started_at, completed_at
 		FROM jobs WHERE id = $1`
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&j.ID, &j.UserID, &j.Type, &j.ReferenceID, &j.ConfigJSON, &j.Status,
-		&j.RetryCount, &j.ErrorMessage, &j.CreatedAt, &j.CompletedAt,
+		&j.RetryCount, &j.ErrorMessage, &j.ErrorCode, &j.CreatedAt, &j.StartedAt, &j.CompletedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -53,8 +53,63 @@ func (r *JobRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error
 	return j, nil
 }
 
+// GetLatestByReference returns the most recently created job of the given type
+// for a reference (content, summary, ...).
+func (r *JobRepo) GetLatestByReference(ctx context.Context, referenceID uuid.UUID, jobType string) (*models.Job, error) {
+	j := &models.Job{}
+	query := `SELECT id, user_id, type, reference_id, config_json, status, retry_count, error_message, error_code, created_at, started_at, completed_at
+		FROM jobs WHERE reference_id = $1 AND type = $2
+		ORDER BY created_at DESC LIMIT 1`
+
+	err := r.pool.QueryRow(ctx, query, referenceID, jobType).Scan(
+		&j.ID, &j.UserID, &j.Type, &j.ReferenceID, &j.ConfigJSON, &j.Status,
+		&j.RetryCount, &j.ErrorMessage, &j.ErrorCode, &j.CreatedAt, &j.StartedAt, &j.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// ListByReference returns the user's jobs for a reference, newest first,
+// optionally limited to the given job types. Other users' jobs are never
+// returned.
+func (r *JobRepo) ListByReference(ctx context.Context, userID, referenceID uuid.UUID, jobTypes ...string) ([]*models.Job, error) {
+	query := `SELECT id, user_id, type, reference_id, config_json, status, retry_count, error_message, error_code, created_at, started_at, completed_at
+		FROM jobs
+		WHERE user_id = $1 AND reference_id = $2
+		  AND (cardinality($3::text[]) = 0 OR type = ANY($3))
+		ORDER BY created_at DESC
+		LIMIT 50`
+
+	if jobTypes == nil {
+		jobTypes = []string{}
+	}
+	rows, err := r.pool.Query(ctx, query, userID, referenceID, jobTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.Job, 0)
+	for rows.Next() {
+		j := &models.Job{}
+		if err := rows.Scan(
+			&j.ID, &j.UserID, &j.Type, &j.ReferenceID, &j.ConfigJSON, &j.Status,
+			&j.RetryCount, &j.ErrorMessage, &j.ErrorCode, &j.CreatedAt, &j.StartedAt, &j.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
 func (r *JobRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
 	query := "UPDATE jobs SET status = $1 WHERE id = $2"
+	if status == "processing" {
+		query = "UPDATE jobs SET status = $1, started_at = NOW() WHERE id = $2"
+	}
 	if updateStatusSetsCompletedAt(status) {
 		now := time.Now()
 		query = "UPDATE jobs SET status = $1, completed_at = $2 WHERE id = $3"
@@ -87,6 +142,96 @@ func (r *JobRepo) UpdateStatusIfNotTerminal(ctx context.Context, id uuid.UUID, s
 	return tag.RowsAffected() == 1, nil
 }
 
+// ListStuck returns jobs that have been 'processing' for longer than olderThan,
+// oldest first.
+func (r *JobRepo) ListStuck(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Job, error) {
+	query := `SELECT id, user_id, type, reference_id, config_json, status, retry_count, error_message, error_code, created_at, started_at, completed_at
+		FROM jobs
+		WHERE status = 'processing'
+		  AND COALESCE(started_at, created_at) < NOW() - make_interval(secs => $1)
+		ORDER BY COALESCE(started_at, created_at) ASC
+		LIMIT $2`
+
+	rows, err := r.pool.Query(ctx, query, olderThan.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.Job, 0)
+	for rows.Next() {
+		j := &models.Job{}
+		if err := rows.Scan(
+			&j.ID, &j.UserID, &j.Type, &j.ReferenceID, &j.ConfigJSON, &j.Status,
+			&j.RetryCount, &j.ErrorMessage, &j.ErrorCode, &j.CreatedAt, &j.StartedAt, &j.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// ListCompletedBefore returns jobs of jobType that completed more than
+// olderThan ago, oldest first.
+func (r *JobRepo) ListCompletedBefore(ctx context.Context, jobType string, olderThan time.Duration, limit int) ([]*models.Job, error) {
+	query := `SELECT id, user_id, type, reference_id, config_json, status, retry_count, error_message, error_code, created_at, started_at, completed_at
+		FROM jobs
+		WHERE type = $1 AND status = 'completed'
+		  AND completed_at < NOW() - make_interval(secs => $2)
+		ORDER BY completed_at ASC
+		LIMIT $3`
+
+	rows, err := r.pool.Query(ctx, query, jobType, olderThan.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.Job, 0)
+	for rows.Next() {
+		j := &models.Job{}
+		if err := rows.Scan(
+			&j.ID, &j.UserID, &j.Type, &j.ReferenceID, &j.ConfigJSON, &j.Status,
+			&j.RetryCount, &j.ErrorMessage, &j.ErrorCode, &j.CreatedAt, &j.StartedAt, &j.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// ResetToPending moves a job back to 'pending' if its current status is one of
+// fromStatuses. Returns false when the job was in any other state.
+func (r *JobRepo) ResetToPending(ctx context.Context, id uuid.UUID, fromStatuses ...string) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE jobs SET status = 'pending', started_at = NULL, completed_at = NULL, error_code = NULL
+		WHERE id = $1 AND status = ANY($2)`,
+		id, fromStatuses,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RequeueStuck moves a job still 'processing' back to 'pending' and counts
+// the lost attempt against its retries. Returns false when the job has left
+// 'processing' in the meantime.
+func (r *JobRepo) RequeueStuck(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE jobs SET status = 'pending', started_at = NULL, completed_at = NULL, error_code = NULL,
+			retry_count = retry_count + 1
+		WHERE id = $1 AND status = 'processing'`,
+		id,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func updateStatusSetsCompletedAt(status string) bool {
 	return status == "completed" || status == "failed" || status == "cancelled"
 }
@@ -99,6 +244,19 @@ func (r *JobRepo) UpdateError(ctx context.Context, id uuid.UUID, errMsg string,
 	return err
 }
 
+// SetErrorCode records a machine-readable reason for a failed job, so clients
+// can explain known failures instead of showing the raw error.
+func (r *JobRepo) SetErrorCode(ctx context.Context, id uuid.UUID, code string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE jobs SET error_code = $1 WHERE id = $2", code, id)
+	return err
+}
+
+// Delete removes a job.
+func (r *JobRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM jobs WHERE id = $1`, id)
+	return err
+}
+
 func (r *JobRepo) DeleteByReference(ctx context.Context, referenceID uuid.UUID, jobTypes ...string) error {
 	if len(jobTypes) == 0 {
 		_, err := r.pool.Exec(ctx, `DELETE FROM jobs WHERE reference_id = $1`, referenceID)
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LibraryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewLibraryRepo(pool *pgxpool.Pool) *LibraryRepo {
+	return &LibraryRepo{pool: pool}
+}
+
+// BulkUpdate applies action ("delete", "archive" or "unarchive") to the
+// user's library items of one type ("summary", "quiz" or "flashcard") in a
+// single statement and returns the IDs it changed. IDs that belong to
+// someone else, are in the trash or already have the requested state are
+// left out. Delete moves items to the trash; see TrashRepo.
+func (r *LibraryRepo) BulkUpdate(ctx context.Context, userID uuid.UUID, itemType, action string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var table string
+	switch itemType {
+	case "summary":
+		table = "summaries"
+	case "quiz":
+		table = "quizzes"
+	case "flashcard":
+		table = "flashcard_decks"
+	default:
+		return nil, fmt.Errorf("unsupported library item type %q", itemType)
+	}
+
+	// Rows the action would not change are not matched, so they count as
+	// skipped rather than affected.
+	var set, onlyIf string
+	switch action {
+	case "delete":
+		set = "deleted_at = NOW()"
+	case "archive":
+		set, onlyIf = "is_archived = TRUE", " AND is_archived IS NOT TRUE"
+	case "unarchive":
+		set, onlyIf = "is_archived = FALSE", " AND is_archived IS TRUE"
+	default:
+		return nil, fmt.Errorf("unsupported library bulk action %q", action)
+	}
+
+	query := `UPDATE ` + table + ` SET ` + set + `
+		WHERE user_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL` + onlyIf + `
+		RETURNING id`
+	rows, err := r.pool.Query(ctx, query, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	affected := make([]uuid.UUID, 0, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		affected = append(affected, id)
+	}
+	return affected, rows.Err()
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"lectura-backend/internal/models"
@@ -40,11 +43,11 @@ func (r *QuizRepo) Create(ctx context.Context, q *models.Quiz) error {
 
 func (r *QuizRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, error) {
 	q := &models.Quiz{}
-	query := `SELECT id, user_id, summary_id, title, config_json, questions_json, question_count, created_at
-		FROM quizzes WHERE id = $1`
+	query := `SELECT id, user_id, summary_id, title, config_json, questions_json, question_count, created_at, original_author_name
+		FROM quizzes WHERE id = $1 AND deleted_at IS NULL`
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&q.ID, &q.UserID, &q.SummaryID, &q.Title, &q.ConfigJSON, &q.QuestionsJSON, &q.QuestionCount, &q.CreatedAt,
+		&q.ID, &q.UserID, &q.SummaryID, &q.Title, &q.ConfigJSON, &q.QuestionsJSON, &q.QuestionCount, &q.CreatedAt, &q.OriginalAuthor,
 	)
 	if err != nil {
 		return nil, err
@@ -52,7 +55,28 @@ func (r *QuizRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Quiz, err
 	return q, nil
 }
 
-func (r *QuizRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Quiz, error) {
+func (r *QuizRepo) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Quiz, int, error) {
+	search = strings.TrimSpace(search)
+	searchLike := "%" + search + "%"
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM quizzes q
+		WHERE q.user_id = $1 AND q.deleted_at IS NULL AND q.is_archived = FALSE AND ($2 = '' OR q.title ILIKE $3)
+		  AND ($4 = FALSE OR q.is_favorite = TRUE)`
+	if err := r.pool.QueryRow(ctx, countQuery, userID, search, searchLike, favoriteOnly).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	orderBy := "q.created_at DESC"
+	switch sortBy {
+	case "title":
+		orderBy = "q.title ASC"
+	case "oldest":
+		orderBy = "q.created_at ASC"
+	case "recent":
+		orderBy = "q.last_accessed_at DESC NULLS LAST, q.created_at DESC"
+	}
+
 	query := `SELECT
 		q.id,
 		q.user_id,
@@ -76,15 +100,20 @@ func (r *QuizRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.
 		LIMIT 1
 	) qa ON true
 	WHERE q.user_id = $1
-	ORDER BY q.created_at DESC`
+	  AND q.deleted_at IS NULL
+	  AND q.is_archived = FALSE
+	  AND ($2 = '' OR q.title ILIKE $3)
+	  AND ($4 = FALSE OR q.is_favorite = TRUE)
+	ORDER BY ` + orderBy + `
+	LIMIT $5 OFFSET $6`
 
-	rows, err := r.pool.Query(ctx, query, userID)
+	rows, err := r.pool.Query(ctx, query, userID, search, searchLike, favoriteOnly, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var quizzes []*models.Quiz
+	quizzes := make([]*models.Quiz, 0)
 	for rows.Next() {
 		q := &models.Quiz{}
 		err := rows.Scan(
@@ -101,11 +130,11 @@ func (r *QuizRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.
 			&q.LastAttemptID,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		quizzes = append(quizzes, q)
 	}
-	return quizzes, nil
+	return quizzes, total, nil
 }
 
 func (r *QuizRepo) UpdateQuestions(ctx context.Context, id uuid.UUID, questions json.RawMessage, count int) error {
@@ -116,11 +145,33 @@ func (r *QuizRepo) UpdateQuestions(ctx context.Context, id uuid.UUID, questions
 	return err
 }
 
+// Delete moves the quiz to the trash; see Restore and TrashRepo.PurgeExpired.
 func (r *QuizRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE quizzes SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
+	return err
+}
+
+// HardDelete removes the row immediately, bypassing the trash.
+func (r *QuizRepo) HardDelete(ctx context.Context, id uuid.UUID) error {
 	_, err := r.pool.Exec(ctx, "DELETE FROM quizzes WHERE id = $1", id)
 	return err
 }
 
+// Restore clears deleted_at if the row was soft-deleted within the recovery
+// window. It reports false when there is nothing restorable.
+func (r *QuizRepo) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE quizzes SET deleted_at = NULL
+		 WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		   AND deleted_at > NOW() - make_interval(days => $3)`,
+		id, userID, trashRetentionDays,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (r *QuizRepo) ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	_, err := r.pool.Exec(ctx, "UPDATE quizzes SET is_favorite = NOT is_favorite WHERE id = $1 AND user_id = $2", id, userID)
 	return err
@@ -137,45 +188,425 @@ func (r *QuizRepo) TouchLastAccessed(ctx context.Context, id uuid.UUID) (bool, e
 	return tag.RowsAffected() == 1, nil
 }
 
+// Sharing
+
+// ShareQuiz gives the quiz a share slug, keeping the existing one if the quiz
+// is already shared.
+func (r *QuizRepo) ShareQuiz(ctx context.Context, id uuid.UUID, slug string) (*models.ShareLink, error) {
+	link := &models.ShareLink{}
+	err := r.pool.QueryRow(ctx,
+		`UPDATE quizzes
+		 SET share_slug = COALESCE(share_slug, $2), shared_at = COALESCE(shared_at, NOW())
+		 WHERE id = $1 AND deleted_at IS NULL
+		 RETURNING share_slug, shared_at`,
+		id, slug,
+	).Scan(&link.Slug, &link.SharedAt)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// UnshareQuiz revokes the quiz's share link. Copies already imported are kept.
+func (r *QuizRepo) UnshareQuiz(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE quizzes SET share_slug = NULL, shared_at = NULL WHERE id = $1 AND deleted_at IS NULL", id)
+	return err
+}
+
+// GetSharedQuiz loads the public preview of a shared quiz, with at most
+// maxQuestions questions and no answers.
+func (r *QuizRepo) GetSharedQuiz(ctx context.Context, slug string, maxQuestions int) (*models.SharedQuiz, error) {
+	quiz := &models.SharedQuiz{Questions: []models.SharedQuizQuestion{}}
+	var questionsJSON json.RawMessage
+	err := r.pool.QueryRow(ctx,
+		`SELECT q.title, q.question_count, q.shared_at, COALESCE(q.original_author_name, u.full_name), q.questions_json
+		 FROM quizzes q
+		 JOIN users u ON u.id = q.user_id
+		 WHERE q.share_slug = $1 AND q.deleted_at IS NULL`,
+		slug,
+	).Scan(&quiz.Title, &quiz.QuestionCount, &quiz.SharedAt, &quiz.AuthorName, &questionsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []models.QuizQuestion
+	if len(questionsJSON) > 0 {
+		if err := json.Unmarshal(questionsJSON, &questions); err != nil {
+			return nil, err
+		}
+	}
+	for i, q := range questions {
+		if i == maxQuestions {
+			break
+		}
+		quiz.Questions = append(quiz.Questions, models.SharedQuizQuestion{
+			Question: q.Question,
+			Type:     q.Type,
+			Options:  q.Options,
+		})
+	}
+	return quiz, nil
+}
+
+// ImportSharedQuiz copies a shared quiz into userID's account without the
+// sharer's attempts or summary link. Quizzes with more than maxQuestions
+// questions are rejected with ErrShareTooLarge.
+func (r *QuizRepo) ImportSharedQuiz(ctx context.Context, slug string, userID uuid.UUID, maxQuestions int) (*models.Quiz, error) {
+	var questionCount int
+	err := r.pool.QueryRow(ctx,
+		`SELECT jsonb_array_length(COALESCE(questions_json, '[]'::jsonb))
+		 FROM quizzes WHERE share_slug = $1 AND deleted_at IS NULL`,
+		slug,
+	).Scan(&questionCount)
+	if err != nil {
+		return nil, err
+	}
+	if questionCount > maxQuestions {
+		return nil, ErrShareTooLarge
+	}
+
+	q := &models.Quiz{ID: uuid.New(), UserID: userID}
+	err = r.pool.QueryRow(ctx,
+		`INSERT INTO quizzes (id, user_id, title, config_json, questions_json, question_count, original_author_name, imported_from_id)
+		 SELECT $2, $3, s.title, COALESCE(s.config_json, '{}'::jsonb) - 'summary_id', COALESCE(s.questions_json, '[]'::jsonb),
+			jsonb_array_length(COALESCE(s.questions_json, '[]'::jsonb)), COALESCE(s.original_author_name, u.full_name), s.id
+		 FROM quizzes s
+		 JOIN users u ON u.id = s.user_id
+		 WHERE s.share_slug = $1 AND s.deleted_at IS NULL
+		 RETURNING title, config_json, questions_json, question_count, original_author_name, created_at`,
+		slug, q.ID, userID,
+	).Scan(&q.Title, &q.ConfigJSON, &q.QuestionsJSON, &q.QuestionCount, &q.OriginalAuthor, &q.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
 // Quiz Attempts
 
 func (r *QuizRepo) CreateAttempt(ctx context.Context, a *models.QuizAttempt) error {
 	a.ID = uuid.New()
 	a.StartedAt = time.Now()
-	query := `INSERT INTO quiz_attempts (id, quiz_id, user_id, started_at)
-		VALUES ($1, $2, $3, $4)`
+	var questionOrder []byte
+	if a.QuestionOrder != nil {
+		questionOrder, _ = json.Marshal(a.QuestionOrder)
+	}
+	query := `INSERT INTO quiz_attempts (id, quiz_id, user_id, started_at, question_order)
+		VALUES ($1, $2, $3, $4, $5)`
 
-	_, err := r.pool.Exec(ctx, query, a.ID, a.QuizID, a.UserID, a.StartedAt)
+	_, err := r.pool.Exec(ctx, query, a.ID, a.QuizID, a.UserID, a.StartedAt, questionOrder)
 	return err
 }
 
-func (r *QuizRepo) GetAttemptByID(ctx context.Context, id uuid.UUID) (*models.QuizAttempt, error) {
+// attemptColumns are the quiz_attempts columns scanAttempt reads, in order.
+const attemptColumns = `id, quiz_id, user_id, answers_json, score_percent, correct_count, started_at, completed_at, time_taken_seconds,
+		COALESCE(hints_used, '[]'::jsonb), question_order, essay_answers, essay_grades, matching_answers`
+
+// scanAttempt reads one row of attemptColumns.
+func scanAttempt(row pgx.Row) (*models.QuizAttempt, error) {
 	a := &models.QuizAttempt{}
-	query := `SELECT id, quiz_id, user_id, answers_json, score_percent, correct_count, started_at, completed_at, time_taken_seconds
-		FROM quiz_attempts WHERE id = $1`
+	var hintsUsedRaw, questionOrderRaw, essayAnswersRaw, essayGradesRaw, matchingAnswersRaw []byte
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
 		&a.ID, &a.QuizID, &a.UserID, &a.AnswersJSON, &a.ScorePercent, &a.CorrectCount,
-		&a.StartedAt, &a.CompletedAt, &a.TimeTakenSeconds,
+		&a.StartedAt, &a.CompletedAt, &a.TimeTakenSeconds, &hintsUsedRaw, &questionOrderRaw,
+		&essayAnswersRaw, &essayGradesRaw, &matchingAnswersRaw,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if len(matchingAnswersRaw) > 0 {
+		if err := json.Unmarshal(matchingAnswersRaw, &a.MatchingAnswers); err != nil {
+			return nil, err
+		}
+	}
+	if len(essayAnswersRaw) > 0 {
+		if err := json.Unmarshal(essayAnswersRaw, &a.EssayAnswers); err != nil {
+			return nil, err
+		}
+	}
+	if len(essayGradesRaw) > 0 {
+		if err := json.Unmarshal(essayGradesRaw, &a.EssayGrades); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(hintsUsedRaw, &a.HintsUsed); err != nil || a.HintsUsed == nil {
+		a.HintsUsed = []int{}
+	}
+	if len(questionOrderRaw) > 0 {
+		if err := json.Unmarshal(questionOrderRaw, &a.QuestionOrder); err != nil {
+			a.QuestionOrder = nil
+		}
+	}
 	return a, nil
 }
 
+func (r *QuizRepo) GetAttemptByID(ctx context.Context, id uuid.UUID) (*models.QuizAttempt, error) {
+	return scanAttempt(r.pool.QueryRow(ctx, `SELECT `+attemptColumns+` FROM quiz_attempts WHERE id = $1`, id))
+}
+
+// ListAttemptsByUser returns the user's attempts on a quiz, submitted or not,
+// oldest first.
+func (r *QuizRepo) ListAttemptsByUser(ctx context.Context, quizID, userID uuid.UUID) ([]*models.QuizAttempt, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+attemptColumns+` FROM quiz_attempts
+		 WHERE quiz_id = $1 AND user_id = $2
+		 ORDER BY started_at ASC, id ASC`,
+		quizID, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*models.QuizAttempt{}
+	for rows.Next() {
+		a, err := scanAttempt(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// GetActiveAttempt returns the user's most recent incomplete attempt on a quiz
+// started after since. It returns pgx.ErrNoRows when there is none.
+func (r *QuizRepo) GetActiveAttempt(ctx context.Context, quizID, userID uuid.UUID, since time.Time) (*models.QuizAttempt, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx,
+		`SELECT id FROM quiz_attempts
+		 WHERE quiz_id = $1 AND user_id = $2 AND completed_at IS NULL AND started_at >= $3
+		 ORDER BY started_at DESC LIMIT 1`,
+		quizID, userID, since,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAttemptByID(ctx, id)
+}
+
+// RecordHintUsage marks a question's hint as revealed on an in-progress
+// attempt. Recording the same question twice is a no-op.
+func (r *QuizRepo) RecordHintUsage(ctx context.Context, attemptID uuid.UUID, questionIndex int) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE quiz_attempts SET hints_used = hints_used || to_jsonb($2::int)
+		 WHERE id = $1 AND completed_at IS NULL AND NOT hints_used @> to_jsonb($2::int)`,
+		attemptID, questionIndex,
+	)
+	return err
+}
+
 func (r *QuizRepo) SaveProgress(ctx context.Context, attemptID uuid.UUID, answers json.RawMessage) error {
 	_, err := r.pool.Exec(ctx, "UPDATE quiz_attempts SET answers_json = $1 WHERE id = $2", answers, attemptID)
 	return err
 }
 
-func (r *QuizRepo) SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage) error {
+// SaveEssayAnswer stores the text answer to one essay question on an
+// in-progress attempt, replacing any earlier answer to it.
+func (r *QuizRepo) SaveEssayAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, answer string) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE quiz_attempts
+		 SET essay_answers = COALESCE(essay_answers, '{}'::jsonb) || jsonb_build_object($2::text, $3::text)
+		 WHERE id = $1 AND completed_at IS NULL`,
+		attemptID, strconv.Itoa(questionIndex), answer,
+	)
+	return err
+}
+
+// SaveMatchingAnswer stores the matches given to one matching question on an
+// in-progress attempt, replacing any earlier answer to it.
+func (r *QuizRepo) SaveMatchingAnswer(ctx context.Context, attemptID uuid.UUID, questionIndex int, matches []int) error {
+	raw, err := json.Marshal(matches)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx,
+		`UPDATE quiz_attempts
+		 SET matching_answers = COALESCE(matching_answers, '{}'::jsonb) || jsonb_build_object($2::text, $3::jsonb)
+		 WHERE id = $1 AND completed_at IS NULL`,
+		attemptID, strconv.Itoa(questionIndex), raw,
+	)
+	return err
+}
+
+// SubmitAttempt completes an attempt with its score. essayGrades may be nil
+// when the quiz has no essay questions.
+func (r *QuizRepo) SubmitAttempt(ctx context.Context, attemptID uuid.UUID, score float64, correct int, answers json.RawMessage, essayGrades map[int]models.EssayGrade) error {
+	var grades []byte
+	if len(essayGrades) > 0 {
+		var err error
+		if grades, err = json.Marshal(essayGrades); err != nil {
+			return err
+		}
+	}
 	now := time.Now()
 	_, err := r.pool.Exec(ctx,
 		`UPDATE quiz_attempts SET answers_json = $1, score_percent = $2, correct_count = $3,
-		 completed_at = $4, time_taken_seconds = EXTRACT(EPOCH FROM ($4 - started_at))::INTEGER
+		 completed_at = $4, time_taken_seconds = EXTRACT(EPOCH FROM ($4 - started_at))::INTEGER,
+		 essay_grades = $6
 		 WHERE id = $5`,
-		answers, score, correct, now, attemptID,
+		answers, score, correct, now, attemptID, grades,
+	)
+	return err
+}
+
+// ListQuestionHistory returns the normalized text of questions previously
+// generated for a summary, newest first.
+func (r *QuizRepo) ListQuestionHistory(ctx context.Context, summaryID uuid.UUID, limit int) ([]string, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT question_text FROM quiz_question_history
+		 WHERE summary_id = $1 ORDER BY created_at DESC LIMIT $2`,
+		summaryID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var texts []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, err
+		}
+		texts = append(texts, text)
+	}
+	return texts, rows.Err()
+}
+
+// RecordQuestionHistory remembers generated questions for a summary. hashes
+// and texts are parallel slices; questions already recorded are skipped.
+func (r *QuizRepo) RecordQuestionHistory(ctx context.Context, summaryID uuid.UUID, hashes, texts []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO quiz_question_history (summary_id, question_hash, question_text)
+		 SELECT $1, h, t FROM unnest($2::text[], $3::text[]) AS q(h, t)
+		 ON CONFLICT (summary_id, question_hash) DO NOTHING`,
+		summaryID, hashes, texts,
 	)
 	return err
 }
+
+// Question Reports
+
+func (r *QuizRepo) CreateQuestionReport(ctx context.Context, report *models.QuestionReport) error {
+	return r.pool.QueryRow(ctx,
+		`INSERT INTO question_reports (quiz_id, user_id, question_index, reason, suggested_fix)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		report.QuizID, report.UserID, report.QuestionIndex, report.Reason, report.SuggestedFix,
+	).Scan(&report.ID, &report.CreatedAt)
+}
+
+// ListReportedQuestions returns the indexes of a quiz's questions that have
+// unresolved reports, in ascending order.
+func (r *QuizRepo) ListReportedQuestions(ctx context.Context, quizID uuid.UUID) ([]int, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT DISTINCT question_index FROM question_reports
+		 WHERE quiz_id = $1 AND resolved_at IS NULL
+		 ORDER BY question_index`,
+		quizID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	indexes := []int{}
+	for rows.Next() {
+		var index int
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+// ListCompletedAttempts returns every submitted attempt on a quiz with its
+// answers, hints and essay grades, for regrading.
+func (r *QuizRepo) ListCompletedAttempts(ctx context.Context, quizID uuid.UUID) ([]*models.QuizAttempt, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, answers_json, COALESCE(hints_used, '[]'::jsonb), essay_answers, essay_grades, matching_answers
+		 FROM quiz_attempts WHERE quiz_id = $1 AND completed_at IS NOT NULL`,
+		quizID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*models.QuizAttempt
+	for rows.Next() {
+		a := &models.QuizAttempt{QuizID: quizID}
+		var hintsUsedRaw, essayAnswersRaw, essayGradesRaw, matchingAnswersRaw []byte
+		if err := rows.Scan(&a.ID, &a.AnswersJSON, &hintsUsedRaw, &essayAnswersRaw, &essayGradesRaw, &matchingAnswersRaw); err != nil {
+			return nil, err
+		}
+		if len(essayAnswersRaw) > 0 {
+			if err := json.Unmarshal(essayAnswersRaw, &a.EssayAnswers); err != nil {
+				return nil, err
+			}
+		}
+		if len(matchingAnswersRaw) > 0 {
+			if err := json.Unmarshal(matchingAnswersRaw, &a.MatchingAnswers); err != nil {
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal(hintsUsedRaw, &a.HintsUsed); err != nil || a.HintsUsed == nil {
+			a.HintsUsed = []int{}
+		}
+		if len(essayGradesRaw) > 0 {
+			if err := json.Unmarshal(essayGradesRaw, &a.EssayGrades); err != nil {
+				return nil, err
+			}
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// UpdateQuestion stores a quiz's questions after one was corrected, saves the
+// regraded scores of its completed attempts and resolves the open reports on
+// the corrected question, all in one transaction.
+func (r *QuizRepo) UpdateQuestion(ctx context.Context, quizID uuid.UUID, index int, questions json.RawMessage, scores []models.AttemptScore) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "UPDATE quizzes SET questions_json = $1 WHERE id = $2", questions, quizID); err != nil {
+		return err
+	}
+
+	if len(scores) > 0 {
+		ids := make([]uuid.UUID, len(scores))
+		percents := make([]float64, len(scores))
+		counts := make([]int, len(scores))
+		for i, s := range scores {
+			ids[i], percents[i], counts[i] = s.AttemptID, s.ScorePercent, s.CorrectCount
+		}
+		_, err := tx.Exec(ctx,
+			`UPDATE quiz_attempts a SET score_percent = s.score, correct_count = s.correct
+			 FROM unnest($2::uuid[], $3::float8[], $4::int[]) AS s(id, score, correct)
+			 WHERE a.id = s.id AND a.quiz_id = $1`,
+			quizID, ids, percents, counts,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE question_reports SET resolved_at = NOW()
+		 WHERE quiz_id = $1 AND question_index = $2 AND resolved_at IS NULL`,
+		quizID, index,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrShareTooLarge is returned when a shared deck or quiz exceeds the import
+// size limit.
+var ErrShareTooLarge = errors.New("shared item is too large to import")
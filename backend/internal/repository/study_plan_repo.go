@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+type StudyPlanRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStudyPlanRepo(pool *pgxpool.Pool) *StudyPlanRepo {
+	return &StudyPlanRepo{pool: pool}
+}
+
+// PlanSummaries loads the summaries a plan is built from, with the latest
+// quiz on each. Deleted summaries are left out; ownership is not checked so
+// callers can tell a missing summary from someone else's.
+func (r *StudyPlanRepo) PlanSummaries(ctx context.Context, ids []uuid.UUID) ([]models.StudyPlanSummary, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.user_id, s.title, COALESCE(s.description, ''), COALESCE(s.word_count, 0), q.id
+		FROM summaries s
+		LEFT JOIN LATERAL (
+			SELECT id FROM quizzes
+			WHERE summary_id = s.id AND user_id = s.user_id AND deleted_at IS NULL
+			ORDER BY created_at DESC
+			LIMIT 1
+		) q ON TRUE
+		WHERE s.id = ANY($1::uuid[]) AND s.deleted_at IS NULL
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []models.StudyPlanSummary
+	for rows.Next() {
+		var s models.StudyPlanSummary
+		if err := rows.Scan(&s.SummaryID, &s.UserID, &s.Title, &s.Description, &s.WordCount, &s.QuizID); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// DueCardForecast counts, per deck and day, the cards of the summaries' decks
+// that fall due between from and until. Overdue cards count on from;
+// suspended cards are left out.
+func (r *StudyPlanRepo) DueCardForecast(ctx context.Context, userID uuid.UUID, summaryIDs []uuid.UUID, from, until time.Time) ([]models.DueCardForecast, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT d.id, d.title, GREATEST(c.next_review_at, $3::date) AS due_day, COUNT(*)
+		FROM flashcard_cards c
+		JOIN flashcard_decks d ON d.id = c.deck_id
+		WHERE d.user_id = $1
+		  AND d.summary_id = ANY($2::uuid[])
+		  AND d.deleted_at IS NULL
+		  AND NOT c.suspended
+		  AND c.next_review_at <= $4::date
+		GROUP BY d.id, d.title, due_day
+	`, userID, summaryIDs, from, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var forecast []models.DueCardForecast
+	for rows.Next() {
+		var f models.DueCardForecast
+		if err := rows.Scan(&f.DeckID, &f.DeckTitle, &f.Day, &f.Count); err != nil {
+			return nil, err
+		}
+		forecast = append(forecast, f)
+	}
+	return forecast, rows.Err()
+}
+
+// Create stores a plan and its items, assigning IDs and item positions.
+func (r *StudyPlanRepo) Create(ctx context.Context, plan *models.StudyPlan) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	plan.ID = uuid.New()
+	err = tx.QueryRow(ctx, `
+		INSERT INTO study_plans (id, user_id, summary_ids, start_date, target_date, daily_minutes, ai_ordered)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, plan.ID, plan.UserID, plan.SummaryIDs, plan.StartDate, plan.TargetDate, plan.DailyMinutes, plan.AIOrdered).Scan(&plan.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range plan.Items {
+		item := &plan.Items[i]
+		item.ID = uuid.New()
+		item.PlanID = plan.ID
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO study_plan_items
+				(id, plan_id, position, day, kind, title, summary_id, quiz_id, deck_id, card_count, estimated_minutes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, item.ID, plan.ID, i, item.Day, item.Kind, item.Title, item.SummaryID, item.QuizID, item.DeckID,
+			item.CardCount, item.EstimatedMinutes); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID returns a plan with its items in schedule order, or pgx.ErrNoRows.
+func (r *StudyPlanRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.StudyPlan, error) {
+	plan := &models.StudyPlan{}
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, user_id, summary_ids, start_date, target_date, daily_minutes, ai_ordered, created_at
+		FROM study_plans WHERE id = $1
+	`, id).Scan(&plan.ID, &plan.UserID, &plan.SummaryIDs, &plan.StartDate, &plan.TargetDate,
+		&plan.DailyMinutes, &plan.AIOrdered, &plan.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+studyPlanItemColumns+`
+		FROM study_plan_items i WHERE i.plan_id = $1
+		ORDER BY i.position
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan.Items = []models.StudyPlanItem{}
+	for rows.Next() {
+		var item models.StudyPlanItem
+		if err := scanStudyPlanItem(rows, &item); err != nil {
+			return nil, err
+		}
+		if item.CompletedAt != nil {
+			plan.CompletedItems++
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan, rows.Err()
+}
+
+// SetItemCompleted marks an item of one of userID's plans done, or not done
+// again. It returns pgx.ErrNoRows when no such item belongs to userID.
+func (r *StudyPlanRepo) SetItemCompleted(ctx context.Context, userID, planID, itemID uuid.UUID, completed bool) (*models.StudyPlanItem, error) {
+	var item models.StudyPlanItem
+	err := scanStudyPlanItem(r.pool.QueryRow(ctx, `
+		UPDATE study_plan_items i
+		SET completed_at = CASE
+			WHEN NOT $4::boolean THEN NULL
+			ELSE COALESCE(i.completed_at, NOW())
+		END
+		FROM study_plans p
+		WHERE i.id = $1 AND i.plan_id = $2 AND p.id = i.plan_id AND p.user_id = $3
+		RETURNING `+studyPlanItemColumns,
+		itemID, planID, userID, completed,
+	), &item)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+const studyPlanItemColumns = `i.id, i.plan_id, i.day, i.kind, i.title, i.summary_id, i.quiz_id, i.deck_id,
+		i.card_count, i.estimated_minutes, i.completed_at`
+
+func scanStudyPlanItem(row pgx.Row, item *models.StudyPlanItem) error {
+	return row.Scan(&item.ID, &item.PlanID, &item.Day, &item.Kind, &item.Title, &item.SummaryID, &item.QuizID,
+		&item.DeckID, &item.CardCount, &item.EstimatedMinutes, &item.CompletedAt)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import "time"
+
+const (
+	// StudySessionIdleTimeout is how long a session may go without a
+	// heartbeat before the gap stops counting as study time.
+	StudySessionIdleTimeout = 5 * time.Minute
+
+	// maxStudySessionSeconds caps the time one session records (12 hours).
+	maxStudySessionSeconds = 43200
+)
+
+// studyClock is the part of a study session its active time is computed
+// from. Active time runs from StartedAt, less PausedSeconds of finished
+// pauses, until the session is paused, goes idle or stops.
+type studyClock struct {
+	StartedAt       time.Time
+	LastHeartbeatAt time.Time
+	PausedAt        *time.Time
+	PausedSeconds   int
+}
+
+// activeUntil is when the session last counted as active at now: the start
+// of an open pause, the last heartbeat if it is more than
+// StudySessionIdleTimeout old, otherwise now.
+func (c studyClock) activeUntil(now time.Time) time.Time {
+	if c.PausedAt != nil {
+		return *c.PausedAt
+	}
+	if now.Sub(c.LastHeartbeatAt) > StudySessionIdleTimeout {
+		return c.LastHeartbeatAt
+	}
+	return now
+}
+
+// activeSeconds is the session's study time at now, within
+// [0, maxStudySessionSeconds].
+func (c studyClock) activeSeconds(now time.Time) int {
+	seconds := int(c.activeUntil(now).Sub(c.StartedAt).Seconds()) - c.PausedSeconds
+	if seconds < 0 {
+		return 0
+	}
+	if seconds > maxStudySessionSeconds {
+		return maxStudySessionSeconds
+	}
+	return seconds
+}
+
+// pause opens a pause where active time last ran. An open pause is kept.
+func (c *studyClock) pause(now time.Time) {
+	if c.PausedAt != nil {
+		return
+	}
+	at := c.activeUntil(now)
+	c.PausedAt = &at
+}
+
+// resume closes an open pause, adding its length to PausedSeconds, and
+// restarts the idle timer.
+func (c *studyClock) resume(now time.Time) {
+	if c.PausedAt != nil {
+		c.PausedSeconds += int(now.Sub(*c.PausedAt).Seconds())
+		c.PausedAt = nil
+	}
+	c.LastHeartbeatAt = now
+}
+
+// heartbeat records that the session is still open. A gap since the last
+// heartbeat longer than StudySessionIdleTimeout counts as a pause, so a tab
+// left open over lunch does not add to study time when it wakes up.
+func (c *studyClock) heartbeat(now time.Time) {
+	if c.PausedAt == nil && now.Sub(c.LastHeartbeatAt) > StudySessionIdleTimeout {
+		c.PausedSeconds += int(now.Sub(c.LastHeartbeatAt).Seconds())
+	}
+	c.LastHeartbeatAt = now
+}
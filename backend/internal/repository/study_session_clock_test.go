@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+var clockStart = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+func at(minutes int) time.Time {
+	return clockStart.Add(time.Duration(minutes) * time.Minute)
+}
+
+func TestStudyClock_CountsTimeSinceStart(t *testing.T) {
+	c := studyClock{StartedAt: clockStart, LastHeartbeatAt: at(19)}
+	if got := c.activeSeconds(at(20)); got != 1200 {
+		t.Fatalf("activeSeconds = %d, want 1200", got)
+	}
+}
+
+func TestStudyClock_PauseAndResumeLeaveOutTheBreak(t *testing.T) {
+	c := studyClock{StartedAt: clockStart, LastHeartbeatAt: clockStart}
+
+	c.heartbeat(at(2))
+	c.pause(at(3))
+	if got := c.activeSeconds(at(60)); got != 180 {
+		t.Fatalf("paused activeSeconds = %d, want 180", got)
+	}
+
+	c.pause(at(30)) // already paused: the pause keeps its start
+	c.resume(at(63))
+	if c.PausedAt != nil || c.PausedSeconds != 3600 {
+		t.Fatalf("after resume: pausedAt=%v pausedSeconds=%d, want nil/3600", c.PausedAt, c.PausedSeconds)
+	}
+	if got := c.activeSeconds(at(65)); got != 300 {
+		t.Fatalf("resumed activeSeconds = %d, want 300", got)
+	}
+}
+
+func TestStudyClock_IdleTailIsNotCounted(t *testing.T) {
+	// Tab left open over lunch: the last heartbeat was two hours before stop.
+	c := studyClock{StartedAt: clockStart, LastHeartbeatAt: at(25)}
+	if got := c.activeSeconds(at(145)); got != 1500 {
+		t.Fatalf("activeSeconds = %d, want 1500", got)
+	}
+
+	// A heartbeat within the timeout keeps the clock running.
+	c.LastHeartbeatAt = at(141)
+	if got := c.activeSeconds(at(145)); got != 8700 {
+		t.Fatalf("activeSeconds = %d, want 8700", got)
+	}
+}
+
+func TestStudyClock_PauseWhileIdleStartsAtLastHeartbeat(t *testing.T) {
+	c := studyClock{StartedAt: clockStart, LastHeartbeatAt: at(10)}
+	c.pause(at(40))
+	if c.PausedAt == nil || !c.PausedAt.Equal(at(10)) {
+		t.Fatalf("pausedAt = %v, want %v", c.PausedAt, at(10))
+	}
+	c.resume(at(50))
+	if got := c.activeSeconds(at(55)); got != 900 {
+		t.Fatalf("activeSeconds = %d, want 900", got)
+	}
+}
+
+func TestStudyClock_LateHeartbeatCountsGapAsPause(t *testing.T) {
+	c := studyClock{StartedAt: clockStart, LastHeartbeatAt: at(10)}
+
+	c.heartbeat(at(14)) // within the timeout
+	if c.PausedSeconds != 0 {
+		t.Fatalf("pausedSeconds = %d after a timely heartbeat, want 0", c.PausedSeconds)
+	}
+	c.heartbeat(at(74)) // tab woke up after an hour
+	if c.PausedSeconds != 3600 {
+		t.Fatalf("pausedSeconds = %d, want 3600", c.PausedSeconds)
+	}
+	if got := c.activeSeconds(at(78)); got != 1080 {
+		t.Fatalf("activeSeconds = %d, want 1080", got)
+	}
+}
+
+func TestStudyClock_ClampsToRange(t *testing.T) {
+	c := studyClock{StartedAt: clockStart, LastHeartbeatAt: at(60 * 20)}
+	if got := c.activeSeconds(at(60 * 20)); got != maxStudySessionSeconds {
+		t.Fatalf("activeSeconds = %d, want the %d cap", got, maxStudySessionSeconds)
+	}
+
+	c = studyClock{StartedAt: clockStart, LastHeartbeatAt: clockStart, PausedSeconds: 600}
+	if got := c.activeSeconds(at(1)); got != 0 {
+		t.Fatalf("activeSeconds = %d, want 0", got)
+	}
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"lectura-backend/internal/models"
@@ -24,16 +27,32 @@ func (r *StudySessionRepo) Start(ctx context.Context, s *models.StudySession) er
 	}
 
 	// Close previous active session for same user/activity/resource (idempotent behavior)
-	_, _ = r.pool.Exec(ctx, `
-		UPDATE study_sessions
-		SET ended_at = NOW(),
-			duration_seconds = GREATEST(0, LEAST(43200, EXTRACT(EPOCH FROM (NOW() - started_at))::INT)),
-			last_heartbeat_at = NOW()
+	rows, err := r.pool.Query(ctx, `
+		SELECT id FROM study_sessions
 		WHERE user_id = $1
 		  AND activity_type = $2
 		  AND resource_id = $3
 		  AND ended_at IS NULL
 	`, s.UserID, s.ActivityType, s.ResourceID)
+	if err != nil {
+		return err
+	}
+	var previous []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		previous = append(previous, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, id := range previous {
+		_, _ = r.Stop(ctx, id, s.UserID)
+	}
 
 	query := `
 		INSERT INTO study_sessions (user_id, activity_type, resource_id, client_meta_json)
@@ -49,35 +68,76 @@ func (r *StudySessionRepo) Start(ctx context.Context, s *models.StudySession) er
 	)
 }
 
+// Heartbeat records that the session is still open. A gap since the
+// previous heartbeat longer than StudySessionIdleTimeout is not counted as
+// study time. It returns false when the session is not the user's or has
+// ended.
 func (r *StudySessionRepo) Heartbeat(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
-	tag, err := r.pool.Exec(ctx, `
-		UPDATE study_sessions
-		SET last_heartbeat_at = NOW()
+	return r.updateClock(ctx, sessionID, userID, false, func(c *studyClock, now time.Time) { c.heartbeat(now) })
+}
+
+// Pause stops the session's clock and stores its study time so far in
+// duration_seconds. Pausing a paused session changes nothing.
+func (r *StudySessionRepo) Pause(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	return r.updateClock(ctx, sessionID, userID, false, func(c *studyClock, now time.Time) { c.pause(now) })
+}
+
+// Resume restarts a paused session's clock. The pause is not counted as
+// study time. Resuming a running session only records a heartbeat.
+func (r *StudySessionRepo) Resume(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	return r.updateClock(ctx, sessionID, userID, false, func(c *studyClock, now time.Time) { c.resume(now) })
+}
+
+// Stop ends the session with its study time: pauses and a trailing idle gap
+// (no heartbeat for StudySessionIdleTimeout) are left out.
+func (r *StudySessionRepo) Stop(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	return r.updateClock(ctx, sessionID, userID, true, func(*studyClock, time.Time) {})
+}
+
+// updateClock applies change to an open session of the user and saves its
+// clock and study time, ending the session when end is set. It returns false
+// when there is no such session.
+func (r *StudySessionRepo) updateClock(ctx context.Context, sessionID, userID uuid.UUID, end bool, change func(c *studyClock, now time.Time)) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var c studyClock
+	var now time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT started_at, last_heartbeat_at, paused_at, paused_seconds, NOW()
+		FROM study_sessions
 		WHERE id = $1
 		  AND user_id = $2
 		  AND ended_at IS NULL
-	`, sessionID, userID)
+		FOR UPDATE
+	`, sessionID, userID).Scan(&c.StartedAt, &c.LastHeartbeatAt, &c.PausedAt, &c.PausedSeconds, &now)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	return tag.RowsAffected() == 1, nil
-}
 
-func (r *StudySessionRepo) Stop(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
-	tag, err := r.pool.Exec(ctx, `
+	change(&c, now)
+	var endedAt *time.Time
+	if end {
+		endedAt = &now
+	}
+
+	_, err = tx.Exec(ctx, `
 		UPDATE study_sessions
-		SET ended_at = CASE WHEN ended_at IS NULL THEN NOW() ELSE ended_at END,
-			last_heartbeat_at = NOW(),
-			duration_seconds = CASE
-				WHEN ended_at IS NULL THEN GREATEST(0, LEAST(43200, EXTRACT(EPOCH FROM (NOW() - started_at))::INT))
-				ELSE duration_seconds
-			END
+		SET last_heartbeat_at = $2,
+			paused_at = $3,
+			paused_seconds = $4,
+			duration_seconds = $5,
+			ended_at = $6
 		WHERE id = $1
-		  AND user_id = $2
-		  AND ended_at IS NULL
-	`, sessionID, userID)
+	`, sessionID, c.LastHeartbeatAt, c.PausedAt, c.PausedSeconds, c.activeSeconds(now), endedAt)
 	if err != nil {
 		return false, err
 	}
-	return tag.RowsAffected() == 1, nil
+	return true, tx.Commit(ctx)
 }
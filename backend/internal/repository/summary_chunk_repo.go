@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+// SummaryChunkRepo stores embedded passages in summary_chunks, which only
+// exists where the pgvector extension is installed.
+type SummaryChunkRepo struct {
+	pool       *pgxpool.Pool
+	textCipher *TextCipher
+}
+
+func NewSummaryChunkRepo(pool *pgxpool.Pool) *SummaryChunkRepo {
+	return &SummaryChunkRepo{pool: pool}
+}
+
+// SetTextCipher encrypts passage text at rest, like the content it came from.
+func (r *SummaryChunkRepo) SetTextCipher(c *TextCipher) {
+	r.textCipher = c
+}
+
+// Available reports whether the summary_chunks table exists.
+func (r *SummaryChunkRepo) Available(ctx context.Context) (bool, error) {
+	var available bool
+	err := r.pool.QueryRow(ctx, `SELECT to_regclass('summary_chunks') IS NOT NULL`).Scan(&available)
+	return available, err
+}
+
+// SourceHash returns the hash of the text a summary's passages were cut from,
+// or "" when it has none.
+func (r *SummaryChunkRepo) SourceHash(ctx context.Context, summaryID uuid.UUID) (string, error) {
+	var hash string
+	err := r.pool.QueryRow(ctx, `
+		SELECT source_hash FROM summary_chunks WHERE summary_id = $1 LIMIT 1
+	`, summaryID).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return hash, err
+}
+
+// ReplaceChunks swaps a summary's passages for chunks in one transaction.
+// Concurrent calls for the same summary run one after the other.
+func (r *SummaryChunkRepo) ReplaceChunks(ctx context.Context, summaryID uuid.UUID, sourceHash string, chunks []models.SummaryChunk) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text))`, summaryID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM summary_chunks WHERE summary_id = $1`, summaryID); err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		content, err := r.textCipher.Encrypt(chunk.Content)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO summary_chunks (summary_id, chunk_index, content, embedding, source_hash)
+			VALUES ($1, $2, $3, $4::vector, $5)
+		`, summaryID, chunk.Index, content, vectorLiteral(chunk.Embedding), sourceHash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Search returns the summary's passages closest to query by cosine distance,
+// best first.
+func (r *SummaryChunkRepo) Search(ctx context.Context, summaryID uuid.UUID, query []float32, limit int) ([]models.SummarySearchResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT chunk_index, content, 1 - (embedding <=> $2::vector) AS score
+		FROM summary_chunks
+		WHERE summary_id = $1
+		ORDER BY embedding <=> $2::vector
+		LIMIT $3
+	`, summaryID, vectorLiteral(query), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]models.SummarySearchResult, 0)
+	for rows.Next() {
+		var result models.SummarySearchResult
+		if err := rows.Scan(&result.ChunkIndex, &result.Content, &result.Score); err != nil {
+			return nil, err
+		}
+		if err := r.textCipher.decryptInPlace(&result.Content); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// vectorLiteral formats v as pgvector's text input, e.g. "[0.1,-0.2]".
+func vectorLiteral(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "testing"
+
+func TestVectorLiteral(t *testing.T) {
+	tests := []struct {
+		in   []float32
+		want string
+	}{
+		{in: nil, want: "[]"},
+		{in: []float32{0.5}, want: "[0.5]"},
+		{in: []float32{0.1, -0.25, 3}, want: "[0.1,-0.25,3]"},
+		{in: []float32{1e-7}, want: "[1e-07]"},
+	}
+
+	for _, tt := range tests {
+		if got := vectorLiteral(tt.in); got != tt.want {
+			t.Errorf("vectorLiteral(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
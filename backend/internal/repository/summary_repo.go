@@ -12,13 +12,20 @@ import (
 )
 
 type SummaryRepo struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	textCipher *TextCipher
 }
 
 func NewSummaryRepo(pool *pgxpool.Pool) *SummaryRepo {
 	return &SummaryRepo{pool: pool}
 }
 
+// SetTextCipher turns on encryption at rest for summary bodies (content_raw)
+// and Cornell sections. Without it they are stored as plaintext.
+func (r *SummaryRepo) SetTextCipher(c *TextCipher) {
+	r.textCipher = c
+}
+
 func (r *SummaryRepo) Create(ctx context.Context, s *models.Summary) error {
 	s.ID = uuid.New()
 	configBytes, _ := json.Marshal(s.ConfigJSON)
@@ -38,21 +45,25 @@ func (r *SummaryRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Summar
 	s := &models.Summary{}
 	query := `SELECT s.id, s.user_id, s.content_id, COALESCE(c.type, '') AS source, s.title, s.format, s.length_setting, s.config_json,
 		s.content_raw, s.cornell_cues, s.cornell_notes, s.cornell_summary,
-		COALESCE(s.follow_up_questions, '[]'::jsonb), s.tags, s.description, s.word_count, s.is_favorite, s.is_archived, s.is_quality_fallback, s.quality_fallback_reason, s.created_at, s.last_accessed_at
+		COALESCE(s.follow_up_questions, '[]'::jsonb), s.tags, s.description, s.word_count, s.is_favorite, s.is_archived, s.is_quality_fallback, s.quality_fallback_reason, s.created_at, s.last_accessed_at,
+		s.length_corrected, s.source_truncated, s.source_coverage_percent
 		FROM summaries s
 		LEFT JOIN content c ON c.id = s.content_id
-		WHERE s.id = $1`
+		WHERE s.id = $1 AND s.deleted_at IS NULL`
 	var followUpQuestionsRaw []byte
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&s.ID, &s.UserID, &s.ContentID, &s.Source, &s.Title, &s.Format, &s.LengthSetting, &s.ConfigJSON,
 		&s.ContentRaw, &s.CornellCues, &s.CornellNotes, &s.CornellSummary,
 		&followUpQuestionsRaw, &s.Tags, &s.Description, &s.WordCount, &s.IsFavorite, &s.IsArchived, &s.IsQualityFallback, &s.QualityFallbackReason,
-		&s.CreatedAt, &s.LastAccessedAt,
+		&s.CreatedAt, &s.LastAccessedAt, &s.LengthCorrected, &s.SourceTruncated, &s.SourceCoveragePercent,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := r.textCipher.decryptInPlace(s.ContentRaw, s.CornellCues, s.CornellNotes, s.CornellSummary); err != nil {
+		return nil, err
+	}
 	if len(followUpQuestionsRaw) == 0 {
 		s.FollowUpQuestions = []string{}
 	} else if err := json.Unmarshal(followUpQuestionsRaw, &s.FollowUpQuestions); err != nil || s.FollowUpQuestions == nil {
@@ -64,7 +75,7 @@ func (r *SummaryRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Summar
 	return s, nil
 }
 
-func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, limit, offset int) ([]*models.Summary, int, error) {
+func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search, sortBy string, favoriteOnly bool, limit, offset int) ([]*models.Summary, int, error) {
 	searchLike := "%" + search + "%"
 
 	// Count total
@@ -73,8 +84,10 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 		FROM summaries s
 		WHERE s.user_id = $1
 		  AND s.is_archived = FALSE
-		  AND ($2 = '' OR s.title ILIKE $3 OR s.description ILIKE $3)`
-	err := r.pool.QueryRow(ctx, countQuery, userID, search, searchLike).Scan(&total)
+		  AND s.deleted_at IS NULL
+		  AND ($2 = '' OR s.title ILIKE $3 OR s.description ILIKE $3)
+		  AND ($4 = FALSE OR s.is_favorite = TRUE)`
+	err := r.pool.QueryRow(ctx, countQuery, userID, search, searchLike, favoriteOnly).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -89,9 +102,11 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 			LEFT JOIN content c ON c.id = s.content_id
 			WHERE s.user_id = $1
 			  AND s.is_archived = FALSE
+			  AND s.deleted_at IS NULL
 			  AND ($2 = '' OR s.title ILIKE $3 OR s.description ILIKE $3)
+			  AND ($4 = FALSE OR s.is_favorite = TRUE)
 			ORDER BY s.title ASC
-			LIMIT $4 OFFSET $5`
+			LIMIT $5 OFFSET $6`
 	case "oldest":
 		query = `SELECT s.id, s.user_id, s.content_id, COALESCE(c.type, '') AS source, s.title, s.format, s.length_setting, s.config_json,
 			s.content_raw, s.cornell_cues, s.cornell_notes, s.cornell_summary,
@@ -100,9 +115,11 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 			LEFT JOIN content c ON c.id = s.content_id
 			WHERE s.user_id = $1
 			  AND s.is_archived = FALSE
+			  AND s.deleted_at IS NULL
 			  AND ($2 = '' OR s.title ILIKE $3 OR s.description ILIKE $3)
+			  AND ($4 = FALSE OR s.is_favorite = TRUE)
 			ORDER BY s.created_at ASC
-			LIMIT $4 OFFSET $5`
+			LIMIT $5 OFFSET $6`
 	case "recent":
 		query = `SELECT s.id, s.user_id, s.content_id, COALESCE(c.type, '') AS source, s.title, s.format, s.length_setting, s.config_json,
 			s.content_raw, s.cornell_cues, s.cornell_notes, s.cornell_summary,
@@ -111,9 +128,11 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 			LEFT JOIN content c ON c.id = s.content_id
 			WHERE s.user_id = $1
 			  AND s.is_archived = FALSE
+			  AND s.deleted_at IS NULL
 			  AND ($2 = '' OR s.title ILIKE $3 OR s.description ILIKE $3)
+			  AND ($4 = FALSE OR s.is_favorite = TRUE)
 			ORDER BY s.last_accessed_at DESC NULLS LAST
-			LIMIT $4 OFFSET $5`
+			LIMIT $5 OFFSET $6`
 	default:
 		query = `SELECT s.id, s.user_id, s.content_id, COALESCE(c.type, '') AS source, s.title, s.format, s.length_setting, s.config_json,
 			s.content_raw, s.cornell_cues, s.cornell_notes, s.cornell_summary,
@@ -122,12 +141,14 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 			LEFT JOIN content c ON c.id = s.content_id
 			WHERE s.user_id = $1
 			  AND s.is_archived = FALSE
+			  AND s.deleted_at IS NULL
 			  AND ($2 = '' OR s.title ILIKE $3 OR s.description ILIKE $3)
+			  AND ($4 = FALSE OR s.is_favorite = TRUE)
 			ORDER BY s.created_at DESC
-			LIMIT $4 OFFSET $5`
+			LIMIT $5 OFFSET $6`
 	}
 
-	rows, err := r.pool.Query(ctx, query, userID, search, searchLike, limit, offset)
+	rows, err := r.pool.Query(ctx, query, userID, search, searchLike, favoriteOnly, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -146,6 +167,9 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 		if err != nil {
 			return nil, 0, err
 		}
+		if err := r.textCipher.decryptInPlace(s.ContentRaw, s.CornellCues, s.CornellNotes, s.CornellSummary); err != nil {
+			return nil, 0, err
+		}
 		if len(followUpQuestionsRaw) == 0 {
 			s.FollowUpQuestions = []string{}
 		} else if err := json.Unmarshal(followUpQuestionsRaw, &s.FollowUpQuestions); err != nil || s.FollowUpQuestions == nil {
@@ -157,6 +181,48 @@ func (r *SummaryRepo) ListByUser(ctx context.Context, userID uuid.UUID, search,
 	return summaries, total, nil
 }
 
+// FindRelated returns up to limit of the user's other active summaries that
+// share tags with summaryID, most shared tags first and newest first among
+// ties. Tags are compared case-insensitively.
+func (r *SummaryRepo) FindRelated(ctx context.Context, userID, summaryID uuid.UUID, limit int) ([]models.RelatedSummary, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, title, format, source, tags, description, is_favorite, shared_tags, created_at
+		FROM (
+			SELECT s.id, s.title, s.format, COALESCE(c.type, '') AS source, s.tags, s.description, s.is_favorite, s.created_at,
+				ARRAY(
+					SELECT DISTINCT lower(t) FROM unnest(s.tags) AS t
+					WHERE lower(t) IN (SELECT lower(ct) FROM unnest(cur.tags) AS ct)
+					ORDER BY 1
+				) AS shared_tags
+			FROM summaries s
+			JOIN summaries cur ON cur.id = $2 AND cur.user_id = $1
+			LEFT JOIN content c ON c.id = s.content_id
+			WHERE s.user_id = $1
+			  AND s.id <> $2
+			  AND s.is_archived = FALSE
+			  AND s.deleted_at IS NULL
+		) related
+		WHERE cardinality(shared_tags) > 0
+		ORDER BY cardinality(shared_tags) DESC, created_at DESC
+		LIMIT $3`,
+		userID, summaryID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := make([]models.RelatedSummary, 0)
+	for rows.Next() {
+		var s models.RelatedSummary
+		if err := rows.Scan(&s.ID, &s.Title, &s.Format, &s.Source, &s.Tags, &s.Description, &s.IsFavorite, &s.SharedTags, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		related = append(related, s)
+	}
+	return related, rows.Err()
+}
+
 func (r *SummaryRepo) Update(ctx context.Context, s *models.Summary) error {
 	_, err := r.pool.Exec(ctx,
 		"UPDATE summaries SET title = $1, tags = $2, description = $3 WHERE id = $4",
@@ -184,19 +250,86 @@ func (r *SummaryRepo) UpdateContent(
 	wordCount int,
 	isQualityFallback bool,
 	qualityFallbackReason *string,
+	lengthCorrected bool,
+	sourceCoveragePercent *int,
 ) error {
 	followUpQuestionsJSON, err := json.Marshal(followUpQuestions)
 	if err != nil {
 		return err
 	}
+	raw, err = r.textCipher.Encrypt(raw)
+	if err != nil {
+		return err
+	}
+	if cues, err = r.textCipher.encryptNullable(cues); err != nil {
+		return err
+	}
+	if notes, err = r.textCipher.encryptNullable(notes); err != nil {
+		return err
+	}
+	if summary, err = r.textCipher.encryptNullable(summary); err != nil {
+		return err
+	}
 	_, err = r.pool.Exec(ctx,
 		`UPDATE summaries SET content_raw = $1, cornell_cues = $2, cornell_notes = $3, cornell_summary = $4,
-		 follow_up_questions = $5, tags = $6, description = $7, word_count = $8, is_quality_fallback = $9, quality_fallback_reason = $10 WHERE id = $11`,
-		raw, cues, notes, summary, followUpQuestionsJSON, tags, desc, wordCount, isQualityFallback, qualityFallbackReason, id,
+		 follow_up_questions = $5, tags = $6, description = $7, word_count = $8, is_quality_fallback = $9, quality_fallback_reason = $10,
+		 length_corrected = $11, source_truncated = $12, source_coverage_percent = $13, outline_json = NULL, content_html = NULL WHERE id = $14`,
+		raw, cues, notes, summary, followUpQuestionsJSON, tags, desc, wordCount, isQualityFallback, qualityFallbackReason, lengthCorrected,
+		sourceCoveragePercent != nil, sourceCoveragePercent, id,
+	)
+	return err
+}
+
+// UpdateRawContent replaces only the summary text, for targeted repairs that
+// leave the Cornell sections, tags and follow-ups as they were.
+func (r *SummaryRepo) UpdateRawContent(ctx context.Context, id uuid.UUID, raw string, wordCount int, isQualityFallback bool, qualityFallbackReason *string) error {
+	raw, err := r.textCipher.Encrypt(raw)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx,
+		`UPDATE summaries SET content_raw = $1, word_count = $2, is_quality_fallback = $3, quality_fallback_reason = $4,
+		 outline_json = NULL, content_html = NULL WHERE id = $5`,
+		raw, wordCount, isQualityFallback, qualityFallbackReason, id,
 	)
 	return err
 }
 
+// GetOutline returns the cached outline, or nil if none has been generated yet.
+func (r *SummaryRepo) GetOutline(ctx context.Context, id uuid.UUID) (json.RawMessage, error) {
+	var outline []byte
+	err := r.pool.QueryRow(ctx, "SELECT outline_json FROM summaries WHERE id = $1 AND deleted_at IS NULL", id).Scan(&outline)
+	if err != nil {
+		return nil, err
+	}
+	return outline, nil
+}
+
+func (r *SummaryRepo) SaveOutline(ctx context.Context, id uuid.UUID, outline json.RawMessage) error {
+	_, err := r.pool.Exec(ctx, "UPDATE summaries SET outline_json = $1 WHERE id = $2", outline, id)
+	return err
+}
+
+// GetHTML returns the cached HTML rendering, or "" if none has been stored
+// since the content last changed.
+func (r *SummaryRepo) GetHTML(ctx context.Context, id uuid.UUID) (string, error) {
+	var html *string
+	err := r.pool.QueryRow(ctx, "SELECT content_html FROM summaries WHERE id = $1 AND deleted_at IS NULL", id).Scan(&html)
+	if err != nil || html == nil {
+		return "", err
+	}
+	return r.textCipher.Decrypt(*html)
+}
+
+func (r *SummaryRepo) SaveHTML(ctx context.Context, id uuid.UUID, html string) error {
+	stored, err := r.textCipher.Encrypt(html)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, "UPDATE summaries SET content_html = $1 WHERE id = $2", stored, id)
+	return err
+}
+
 func (r *SummaryRepo) UpdateFollowUpQuestions(ctx context.Context, summaryID uuid.UUID, questions []string) error {
 	data, err := json.Marshal(questions)
 	if err != nil {
@@ -209,25 +342,122 @@ func (r *SummaryRepo) UpdateFollowUpQuestions(ctx context.Context, summaryID uui
 	return err
 }
 
+// Delete moves the summary to the trash; see Restore and TrashRepo.PurgeExpired.
 func (r *SummaryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "UPDATE summaries SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", id)
+	return err
+}
+
+// HardDelete removes the row immediately, bypassing the trash.
+func (r *SummaryRepo) HardDelete(ctx context.Context, id uuid.UUID) error {
 	_, err := r.pool.Exec(ctx, "DELETE FROM summaries WHERE id = $1", id)
 	return err
 }
 
+// Restore clears deleted_at if the row was soft-deleted within the recovery
+// window. It reports false when there is nothing restorable.
+func (r *SummaryRepo) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE summaries SET deleted_at = NULL
+		 WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		   AND deleted_at > NOW() - make_interval(days => $3)`,
+		id, userID, trashRetentionDays,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (r *SummaryRepo) ToggleFavorite(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	_, err := r.pool.Exec(ctx, "UPDATE summaries SET is_favorite = NOT is_favorite WHERE id = $1 AND user_id = $2", id, userID)
 	return err
 }
 
-func (r *SummaryRepo) BulkDelete(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	query := `DELETE FROM summaries WHERE user_id = $1 AND id = ANY($2::uuid[])`
-	_, err := r.pool.Exec(ctx, query, userID, ids)
+func (r *SummaryRepo) SetArchived(ctx context.Context, id uuid.UUID, userID uuid.UUID, archived bool) error {
+	_, err := r.pool.Exec(ctx, "UPDATE summaries SET is_archived = $1 WHERE id = $2 AND user_id = $3", archived, id, userID)
 	return err
 }
 
 // Ensure pgx import is used
 var _ pgx.Rows = (pgx.Rows)(nil)
+
+// EncryptPlaintext encrypts summary bodies and Cornell sections written before
+// encryption was turned on, in summaries and their saved versions, batchSize
+// rows at a time. It returns the number of rows rewritten. Without a cipher it
+// does nothing.
+func (r *SummaryRepo) EncryptPlaintext(ctx context.Context, batchSize int) (int, error) {
+	if r.textCipher == nil {
+		return 0, nil
+	}
+
+	total := 0
+	for _, table := range []string{"summaries", "summary_versions"} {
+		for {
+			selected, rewritten, err := r.encryptPlaintextBatch(ctx, table, batchSize)
+			total += rewritten
+			if err != nil {
+				return total, err
+			}
+			if selected < batchSize || rewritten == 0 {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+// encryptPlaintextBatch rewrites up to batchSize rows of table that still hold
+// plaintext. A row changed since it was read is skipped; the next batch picks
+// it up if it is still plaintext.
+func (r *SummaryRepo) encryptPlaintextBatch(ctx context.Context, table string, batchSize int) (selected, rewritten int, err error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, content_raw, cornell_cues, cornell_notes, cornell_summary FROM `+table+`
+		WHERE content_raw NOT LIKE $1 OR cornell_cues NOT LIKE $1 OR cornell_notes NOT LIKE $1 OR cornell_summary NOT LIKE $1
+		LIMIT $2`,
+		encryptedTextPrefix+"%", batchSize,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	type storedBody struct {
+		id      uuid.UUID
+		columns []*string
+	}
+	var bodies []storedBody
+	for rows.Next() {
+		var id uuid.UUID
+		var raw, cues, notes, summary *string
+		if err := rows.Scan(&id, &raw, &cues, &notes, &summary); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		bodies = append(bodies, storedBody{id: id, columns: []*string{raw, cues, notes, summary}})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	for _, b := range bodies {
+		stored, changed, err := r.textCipher.encryptPlaintext(b.columns...)
+		if err != nil {
+			return len(bodies), rewritten, err
+		}
+		if !changed {
+			continue
+		}
+		tag, err := r.pool.Exec(ctx,
+			`UPDATE `+table+` SET content_raw = $1, cornell_cues = $2, cornell_notes = $3, cornell_summary = $4
+			WHERE id = $5 AND content_raw IS NOT DISTINCT FROM $6 AND cornell_cues IS NOT DISTINCT FROM $7
+			  AND cornell_notes IS NOT DISTINCT FROM $8 AND cornell_summary IS NOT DISTINCT FROM $9`,
+			stored[0], stored[1], stored[2], stored[3], b.id, b.columns[0], b.columns[1], b.columns[2], b.columns[3],
+		)
+		if err != nil {
+			return len(bodies), rewritten, err
+		}
+		rewritten += int(tag.RowsAffected())
+	}
+	return len(bodies), rewritten, nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+// DefaultSummaryVersionLimit is how many earlier versions of a summary are
+// kept when no limit is configured.
+const DefaultSummaryVersionLimit = 5
+
+// SummaryVersionRepo keeps earlier bodies of summaries in summary_versions.
+type SummaryVersionRepo struct {
+	pool       *pgxpool.Pool
+	textCipher *TextCipher
+}
+
+func NewSummaryVersionRepo(pool *pgxpool.Pool) *SummaryVersionRepo {
+	return &SummaryVersionRepo{pool: pool}
+}
+
+// SetTextCipher decrypts version bodies, which are stored as the summary
+// stored them.
+func (r *SummaryVersionRepo) SetTextCipher(c *TextCipher) {
+	r.textCipher = c
+}
+
+// Snapshot saves the summary's current body as its newest version and drops
+// all but the newest keep versions. A summary with no body yet is skipped;
+// saved reports whether a version was written.
+func (r *SummaryVersionRepo) Snapshot(ctx context.Context, summaryID uuid.UUID, keep int) (saved bool, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockSummaryVersions(ctx, tx, summaryID); err != nil {
+		return false, err
+	}
+	saved, err = snapshotSummary(ctx, tx, summaryID)
+	if err != nil || !saved {
+		return false, err
+	}
+	if err := pruneSummaryVersions(ctx, tx, summaryID, keep); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// List returns the summary's saved versions, newest first.
+func (r *SummaryVersionRepo) List(ctx context.Context, summaryID uuid.UUID) ([]models.SummaryVersion, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT version, format, length_setting, COALESCE(config_json, '{}'::jsonb), content_raw, cornell_cues, cornell_notes, cornell_summary,
+			tags, word_count, is_quality_fallback, quality_fallback_reason, created_at
+		FROM summary_versions
+		WHERE summary_id = $1
+		ORDER BY version DESC`,
+		summaryID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]models.SummaryVersion, 0)
+	for rows.Next() {
+		var v models.SummaryVersion
+		if err := rows.Scan(
+			&v.Version, &v.Format, &v.LengthSetting, &v.ConfigJSON, &v.ContentRaw, &v.CornellCues, &v.CornellNotes, &v.CornellSummary,
+			&v.Tags, &v.WordCount, &v.IsQualityFallback, &v.QualityFallbackReason, &v.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := r.textCipher.decryptInPlace(v.ContentRaw, v.CornellCues, v.CornellNotes, v.CornellSummary); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// Restore puts version back as the summary's body. The body it replaces is
+// saved as a new version first, so a restore can itself be undone. It returns
+// pgx.ErrNoRows when the summary has no such version.
+func (r *SummaryVersionRepo) Restore(ctx context.Context, summaryID uuid.UUID, version, keep int) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockSummaryVersions(ctx, tx, summaryID); err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM summary_versions WHERE summary_id = $1 AND version = $2)`,
+		summaryID, version,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return pgx.ErrNoRows
+	}
+
+	if _, err := snapshotSummary(ctx, tx, summaryID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE summaries s SET content_raw = v.content_raw, cornell_cues = v.cornell_cues, cornell_notes = v.cornell_notes,
+			cornell_summary = v.cornell_summary, tags = COALESCE(v.tags, s.tags), format = v.format, length_setting = v.length_setting, config_json = v.config_json,
+			word_count = v.word_count, is_quality_fallback = v.is_quality_fallback, quality_fallback_reason = v.quality_fallback_reason,
+			length_corrected = FALSE, outline_json = NULL, content_html = NULL
+		FROM summary_versions v
+		WHERE s.id = $1 AND v.summary_id = $1 AND v.version = $2`,
+		summaryID, version,
+	); err != nil {
+		return err
+	}
+	if err := pruneSummaryVersions(ctx, tx, summaryID, keep); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// lockSummaryVersions serializes version writes for one summary, so
+// concurrent snapshots cannot take the same version number.
+func lockSummaryVersions(ctx context.Context, tx pgx.Tx, summaryID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('summary_versions:' || $1::text))`, summaryID)
+	return err
+}
+
+func snapshotSummary(ctx context.Context, tx pgx.Tx, summaryID uuid.UUID) (bool, error) {
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO summary_versions (summary_id, version, format, length_setting, config_json, content_raw,
+			cornell_cues, cornell_notes, cornell_summary, tags, word_count, is_quality_fallback, quality_fallback_reason)
+		SELECT s.id,
+			COALESCE((SELECT MAX(version) FROM summary_versions WHERE summary_id = s.id), 0) + 1,
+			s.format, s.length_setting, s.config_json, s.content_raw,
+			s.cornell_cues, s.cornell_notes, s.cornell_summary, COALESCE(s.tags, '{}'), COALESCE(s.word_count, 0),
+			COALESCE(s.is_quality_fallback, FALSE), s.quality_fallback_reason
+		FROM summaries s
+		WHERE s.id = $1
+		  AND (s.content_raw IS NOT NULL OR s.cornell_notes IS NOT NULL)`,
+		summaryID,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func pruneSummaryVersions(ctx context.Context, tx pgx.Tx, summaryID uuid.UUID, keep int) error {
+	if keep <= 0 {
+		keep = DefaultSummaryVersionLimit
+	}
+	_, err := tx.Exec(ctx,
+		`DELETE FROM summary_versions
+		WHERE summary_id = $1
+		  AND version NOT IN (
+			SELECT version FROM summary_versions WHERE summary_id = $1 ORDER BY version DESC LIMIT $2
+		  )`,
+		summaryID, keep,
+	)
+	return err
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// encryptedTextPrefix marks a column value written by TextCipher. Values
+// without it are plaintext, from before encryption was turned on.
+const encryptedTextPrefix = "lectura:enc:v1:"
+
+// ErrTextKeyMissing is returned when reading a value encrypted under a key
+// this process was not given.
+var ErrTextKeyMissing = errors.New("encrypted text: key not configured")
+
+// TextCipher encrypts sensitive text columns at rest with AES-256-GCM. Stored
+// values look like "lectura:enc:v1:<key id>:<base64 nonce+ciphertext>"; the
+// key ID lets rows written under a retired key be read after rotation.
+//
+// A nil *TextCipher writes plaintext and reads plaintext unchanged, so
+// repositories behave as before when no key is configured.
+type TextCipher struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewTextCipher encrypts with current and decrypts with current or any of
+// previous. Keys are secrets of any length, stretched with SHA-256. It
+// returns nil when current is empty.
+func NewTextCipher(current string, previous ...string) (*TextCipher, error) {
+	if strings.TrimSpace(current) == "" {
+		return nil, nil
+	}
+
+	c := &TextCipher{keys: make(map[string]cipher.AEAD)}
+	for i, secret := range append([]string{current}, previous...) {
+		if strings.TrimSpace(secret) == "" {
+			continue
+		}
+		key := sha256.Sum256([]byte(secret))
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			return nil, fmt.Errorf("text cipher: %w", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("text cipher: %w", err)
+		}
+		id := textKeyID(key[:])
+		if i == 0 {
+			c.currentID = id
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+// textKeyID names a key by a hash of it, so the ID can be stored next to the
+// ciphertext without revealing anything about the key.
+func textKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// Encrypt returns the stored form of plaintext.
+func (c *TextCipher) Encrypt(plaintext string) (string, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	aead := c.keys[c.currentID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("text cipher: failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedTextPrefix + c.currentID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of a stored value. Plaintext values pass
+// through, so rows written before encryption was enabled stay readable.
+func (c *TextCipher) Decrypt(stored string) (string, error) {
+	rest, ok := strings.CutPrefix(stored, encryptedTextPrefix)
+	if !ok {
+		return stored, nil
+	}
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", fmt.Errorf("text cipher: malformed value")
+	}
+	if c == nil {
+		return "", ErrTextKeyMissing
+	}
+	aead, ok := c.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w (key id %s)", ErrTextKeyMissing, id)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("text cipher: failed to decode: %w", err)
+	}
+	if len(data) < aead.NonceSize() {
+		return "", fmt.Errorf("text cipher: ciphertext too short")
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("text cipher: failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// decryptInPlace replaces scanned nullable columns with their plaintext.
+func (c *TextCipher) decryptInPlace(values ...*string) error {
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		plaintext, err := c.Decrypt(*value)
+		if err != nil {
+			return err
+		}
+		*value = plaintext
+	}
+	return nil
+}
+
+// encryptNullable returns the stored form of a nullable column.
+func (c *TextCipher) encryptNullable(value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	stored, err := c.Encrypt(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// encryptPlaintext returns values with any plaintext encrypted, and whether
+// any changed. Values already encrypted, under any key, are kept as they are.
+func (c *TextCipher) encryptPlaintext(values ...*string) ([]*string, bool, error) {
+	stored := make([]*string, len(values))
+	changed := false
+	for i, value := range values {
+		stored[i] = value
+		if c == nil || value == nil || strings.HasPrefix(*value, encryptedTextPrefix) {
+			continue
+		}
+		encrypted, err := c.Encrypt(*value)
+		if err != nil {
+			return nil, false, err
+		}
+		stored[i] = &encrypted
+		changed = true
+	}
+	return stored, changed, nil
+}
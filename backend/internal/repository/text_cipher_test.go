@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTextCipher_RoundTrip(t *testing.T) {
+	c, err := NewTextCipher("current-secret")
+	if err != nil {
+		t.Fatalf("NewTextCipher() error = %v", err)
+	}
+
+	stored, err := c.Encrypt("the lecture transcript")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(stored, encryptedTextPrefix) || strings.Contains(stored, "lecture") {
+		t.Fatalf("stored value %q is not encrypted", stored)
+	}
+
+	again, _ := c.Encrypt("the lecture transcript")
+	if again == stored {
+		t.Fatal("two encryptions of the same text should use different nonces")
+	}
+
+	got, err := c.Decrypt(stored)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if got != "the lecture transcript" {
+		t.Fatalf("Decrypt() = %q", got)
+	}
+}
+
+func TestTextCipher_PlaintextPassesThrough(t *testing.T) {
+	c, _ := NewTextCipher("current-secret")
+
+	got, err := c.Decrypt("written before encryption")
+	if err != nil || got != "written before encryption" {
+		t.Fatalf("Decrypt(plaintext) = %q, %v", got, err)
+	}
+}
+
+func TestTextCipher_ReadsRowsFromRotatedKey(t *testing.T) {
+	old, _ := NewTextCipher("old-secret")
+	stored, _ := old.Encrypt("summary body")
+
+	rotated, err := NewTextCipher("new-secret", "old-secret")
+	if err != nil {
+		t.Fatalf("NewTextCipher() error = %v", err)
+	}
+	got, err := rotated.Decrypt(stored)
+	if err != nil || got != "summary body" {
+		t.Fatalf("Decrypt(old row) = %q, %v", got, err)
+	}
+
+	fresh, _ := rotated.Encrypt("summary body")
+	if _, err := old.Decrypt(fresh); !errors.Is(err, ErrTextKeyMissing) {
+		t.Fatalf("old cipher reading new row: err = %v, want ErrTextKeyMissing", err)
+	}
+}
+
+func TestTextCipher_NilCipher(t *testing.T) {
+	c, err := NewTextCipher("  ")
+	if err != nil || c != nil {
+		t.Fatalf("NewTextCipher(blank) = %v, %v; want nil, nil", c, err)
+	}
+
+	stored, err := c.Encrypt("plain")
+	if err != nil || stored != "plain" {
+		t.Fatalf("nil Encrypt() = %q, %v", stored, err)
+	}
+
+	enc, _ := NewTextCipher("secret")
+	ciphertext, _ := enc.Encrypt("plain")
+	if _, err := c.Decrypt(ciphertext); !errors.Is(err, ErrTextKeyMissing) {
+		t.Fatalf("nil Decrypt(ciphertext) err = %v, want ErrTextKeyMissing", err)
+	}
+}
+
+func TestTextCipher_DecryptInPlace(t *testing.T) {
+	c, _ := NewTextCipher("secret")
+	stored, _ := c.Encrypt("hello")
+
+	if err := c.decryptInPlace(nil); err != nil {
+		t.Fatalf("decryptInPlace(nil) error = %v", err)
+	}
+	if err := c.decryptInPlace(&stored); err != nil || stored != "hello" {
+		t.Fatalf("decryptInPlace() = %q, %v", stored, err)
+	}
+}
+
+func TestTextCipher_CornellSectionsRoundTrip(t *testing.T) {
+	c, _ := NewTextCipher("secret")
+	cues, notes := "Key terms", "Mitochondria make ATP"
+
+	storedCues, err := c.encryptNullable(&cues)
+	if err != nil || *storedCues == cues {
+		t.Fatalf("encryptNullable() = %v, %v; want ciphertext", storedCues, err)
+	}
+	storedNotes, _ := c.encryptNullable(&notes)
+	if missing, err := c.encryptNullable(nil); missing != nil || err != nil {
+		t.Fatalf("encryptNullable(nil) = %v, %v", missing, err)
+	}
+
+	if err := c.decryptInPlace(storedCues, storedNotes, nil); err != nil {
+		t.Fatalf("decryptInPlace() error = %v", err)
+	}
+	if *storedCues != cues || *storedNotes != notes {
+		t.Fatalf("decryptInPlace() = %q, %q", *storedCues, *storedNotes)
+	}
+}
+
+func TestTextCipher_EncryptPlaintextSkipsEncryptedValues(t *testing.T) {
+	c, _ := NewTextCipher("secret")
+	raw := "summary body"
+	encrypted, _ := c.Encrypt("cornell notes")
+
+	stored, changed, err := c.encryptPlaintext(&raw, &encrypted, nil)
+	if err != nil || !changed {
+		t.Fatalf("encryptPlaintext() changed=%v err=%v", changed, err)
+	}
+	if *stored[0] == raw || !strings.HasPrefix(*stored[0], encryptedTextPrefix) {
+		t.Fatalf("expected plaintext to be encrypted, got %q", *stored[0])
+	}
+	if *stored[1] != encrypted || stored[2] != nil {
+		t.Fatalf("expected encrypted and null values to be kept, got %v", stored[1:])
+	}
+	if raw != "summary body" {
+		t.Fatalf("encryptPlaintext() modified its input")
+	}
+
+	if _, changed, _ := c.encryptPlaintext(stored...); changed {
+		t.Fatalf("expected a second pass to change nothing")
+	}
+	var none *TextCipher
+	if _, changed, _ := none.encryptPlaintext(&raw); changed {
+		t.Fatalf("expected a nil cipher to leave plaintext")
+	}
+}
+
+func TestContentRepo_RefusesPlaintextOriginalTranscript(t *testing.T) {
+	repo := &ContentRepo{}
+	original := "Call Jane on 555-0100"
+	err := repo.UpdateRedactedTranscript(context.Background(), uuid.New(), "Call [NAME] on [PHONE]", &original)
+	if !errors.Is(err, ErrOriginalNeedsEncryption) {
+		t.Fatalf("UpdateRedactedTranscript() error = %v, want ErrOriginalNeedsEncryption", err)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+// TrashRetention is how long soft-deleted summaries, quizzes and decks can be
+// restored before they are purged for good.
+const TrashRetention = 30 * 24 * time.Hour
+
+// trashRetentionDays mirrors TrashRetention for use in SQL intervals.
+const trashRetentionDays = 30
+
+type TrashRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTrashRepo(pool *pgxpool.Pool) *TrashRepo {
+	return &TrashRepo{pool: pool}
+}
+
+// ListByUser returns the user's soft-deleted items that are still within the
+// recovery window, most recently deleted first.
+func (r *TrashRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.TrashItem, error) {
+	query := `
+		SELECT id, type, title, deleted_at FROM (
+			SELECT id, 'summary' AS type, title, deleted_at FROM summaries
+			WHERE user_id = $1 AND deleted_at IS NOT NULL
+			UNION ALL
+			SELECT id, 'quiz' AS type, title, deleted_at FROM quizzes
+			WHERE user_id = $1 AND deleted_at IS NOT NULL
+			UNION ALL
+			SELECT id, 'flashcard' AS type, title, deleted_at FROM flashcard_decks
+			WHERE user_id = $1 AND deleted_at IS NOT NULL
+		) trash
+		WHERE deleted_at > NOW() - make_interval(days => $2)
+		ORDER BY deleted_at DESC
+	`
+	rows, err := r.pool.Query(ctx, query, userID, trashRetentionDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.ID, &item.Type, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		item.PurgeAt = item.DeletedAt.Add(TrashRetention)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// PurgeExpired hard-deletes every soft-deleted row older than the retention
+// window and returns the number of rows removed.
+func (r *TrashRepo) PurgeExpired(ctx context.Context) (int64, error) {
+	var purged int64
+	for _, table := range []string{"summaries", "quizzes", "flashcard_decks"} {
+		tag, err := r.pool.Exec(ctx,
+			"DELETE FROM "+table+" WHERE deleted_at IS NOT NULL AND deleted_at <= NOW() - make_interval(days => $1)",
+			trashRetentionDays,
+		)
+		if err != nil {
+			return purged, err
+		}
+		purged += tag.RowsAffected()
+	}
+	return purged, nil
+}
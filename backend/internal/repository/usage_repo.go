@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"lectura-backend/internal/models"
+)
+
+type UsageRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewUsageRepo(pool *pgxpool.Pool) *UsageRepo {
+	return &UsageRepo{pool: pool}
+}
+
+// RecordUsage stores one attributed Gemini call.
+func (r *UsageRepo) RecordUsage(ctx context.Context, e *models.UsageEvent) error {
+	e.ID = uuid.New()
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO usage_events (id, user_id, job_id, operation, prompt_tokens, completion_tokens)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`,
+		e.ID, e.UserID, e.JobID, e.Operation, e.PromptTokens, e.CompletionTokens,
+	).Scan(&e.CreatedAt)
+}
+
+// OperationTotals sums a user's usage per operation for calls made in
+// [from, to), busiest operation first.
+func (r *UsageRepo) OperationTotals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.UsageOperationStats, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT operation, COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0)
+		FROM usage_events
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY operation
+		ORDER BY COUNT(*) DESC, operation`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.UsageOperationStats{}
+	for rows.Next() {
+		var s models.UsageOperationStats
+		if err := rows.Scan(&s.Operation, &s.Requests, &s.PromptTokens, &s.CompletionTokens); err != nil {
+			return nil, err
+		}
+		s.TotalTokens = s.PromptTokens + s.CompletionTokens
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
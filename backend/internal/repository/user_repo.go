@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -149,11 +150,14 @@ func (r *UserRepo) CreateSettings(ctx context.Context, userID uuid.UUID) error {
 
 func (r *UserRepo) GetSettings(ctx context.Context, userID uuid.UUID) (*models.UserSettings, error) {
 	s := &models.UserSettings{}
-	query := `SELECT user_id, default_summary_length, default_format, default_difficulty, language, notifications_json, updated_at
+	query := `SELECT user_id, default_summary_length, default_format, default_difficulty, language,
+		default_num_questions, default_num_cards, notifications_json, redact_transcripts, redact_names,
+		retain_original_transcript, updated_at
 		FROM user_settings WHERE user_id = $1`
 	err := r.pool.QueryRow(ctx, query, userID).Scan(
 		&s.UserID, &s.DefaultSummaryLength, &s.DefaultFormat, &s.DefaultDifficulty,
-		&s.Language, &s.NotificationsJSON, &s.UpdatedAt,
+		&s.Language, &s.DefaultNumQuestions, &s.DefaultNumCards, &s.NotificationsJSON,
+		&s.RedactTranscripts, &s.RedactNames, &s.RetainOriginalTranscript, &s.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -164,8 +168,12 @@ func (r *UserRepo) GetSettings(ctx context.Context, userID uuid.UUID) (*models.U
 func (r *UserRepo) UpdateSettings(ctx context.Context, s *models.UserSettings) error {
 	_, err := r.pool.Exec(ctx,
 		`UPDATE user_settings SET default_summary_length = $1, default_format = $2, default_difficulty = $3,
-		 language = $4, notifications_json = $5, updated_at = NOW() WHERE user_id = $6`,
-		s.DefaultSummaryLength, s.DefaultFormat, s.DefaultDifficulty, s.Language, s.NotificationsJSON, s.UserID,
+		 language = $4, default_num_questions = $5, default_num_cards = $6, notifications_json = $7,
+		 redact_transcripts = $8, redact_names = $9, retain_original_transcript = $10,
+		 updated_at = NOW() WHERE user_id = $11`,
+		s.DefaultSummaryLength, s.DefaultFormat, s.DefaultDifficulty, s.Language,
+		s.DefaultNumQuestions, s.DefaultNumCards, s.NotificationsJSON,
+		s.RedactTranscripts, s.RedactNames, s.RetainOriginalTranscript, s.UserID,
 	)
 	return err
 }
@@ -270,9 +278,9 @@ func (r *UserRepo) ListUsersWithNotificationEnabled(ctx context.Context, notific
 func (r *UserRepo) GetWeeklyDigestStats(ctx context.Context, userID uuid.UUID) (summaries int, quizzes int, flashcards int, studyHours float64, err error) {
 	err = r.pool.QueryRow(ctx, `
 		SELECT
-			(SELECT COUNT(*) FROM summaries WHERE user_id = $1 AND created_at >= NOW() - INTERVAL '7 days') AS summaries,
-			(SELECT COUNT(*) FROM quizzes WHERE user_id = $1 AND created_at >= NOW() - INTERVAL '7 days') AS quizzes,
-			(SELECT COUNT(*) FROM flashcard_decks WHERE user_id = $1 AND created_at >= NOW() - INTERVAL '7 days') AS flashcards,
+			(SELECT COUNT(*) FROM summaries WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= NOW() - INTERVAL '7 days') AS summaries,
+			(SELECT COUNT(*) FROM quizzes WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= NOW() - INTERVAL '7 days') AS quizzes,
+			(SELECT COUNT(*) FROM flashcard_decks WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= NOW() - INTERVAL '7 days') AS flashcards,
 			COALESCE((
 				SELECT SUM(duration_seconds)::float8 / 3600.0
 				FROM study_sessions
@@ -283,6 +291,49 @@ func (r *UserRepo) GetWeeklyDigestStats(ctx context.Context, userID uuid.UUID) (
 	return
 }
 
+// GetAccountSummary totals the user's library, study time and uploaded
+// storage. Items in the trash are not counted.
+func (r *UserRepo) GetAccountSummary(ctx context.Context, userID uuid.UUID) (*models.AccountSummary, error) {
+	var summary models.AccountSummary
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			u.plan,
+			u.is_verified,
+			u.created_at,
+			(SELECT COUNT(*) FROM summaries WHERE user_id = u.id AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM quizzes WHERE user_id = u.id AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM flashcard_decks WHERE user_id = u.id AND deleted_at IS NULL),
+			(SELECT COUNT(*)
+			 FROM flashcard_cards fc
+			 JOIN flashcard_decks fd ON fd.id = fc.deck_id
+			 WHERE fd.user_id = u.id AND fd.deleted_at IS NULL),
+			COALESCE((SELECT SUM(duration_seconds) FROM study_sessions WHERE user_id = u.id), 0)::float8 / 3600.0,
+			COALESCE((
+				SELECT SUM((metadata_json->>'size_bytes')::bigint)
+				FROM content
+				WHERE user_id = u.id
+				  AND type = 'file'
+				  AND metadata_json->>'size_bytes' ~ '^[0-9]+$'
+			), 0)::bigint
+		FROM users u
+		WHERE u.id = $1
+	`, userID).Scan(
+		&summary.Plan,
+		&summary.IsVerified,
+		&summary.CreatedAt,
+		&summary.Summaries,
+		&summary.Quizzes,
+		&summary.FlashcardDecks,
+		&summary.FlashcardCards,
+		&summary.StudyHours,
+		&summary.StorageBytes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
 func (r *UserRepo) GetLatestActivityAt(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
 	var ts pgtype.Timestamptz
 	err := r.pool.QueryRow(ctx, `
@@ -329,3 +380,158 @@ func (r *UserRepo) SetWeeklyGoalTarget(ctx context.Context, userID uuid.UUID, ta
 	`, userID, target, goalType)
 	return err
 }
+
+// GetNotificationSchedule returns the user's timezone and quiet hours. Users
+// without settings get the zero schedule.
+func (r *UserRepo) GetNotificationSchedule(ctx context.Context, userID uuid.UUID) (models.NotificationSchedule, error) {
+	var schedule models.NotificationSchedule
+	err := r.pool.QueryRow(ctx, `
+		SELECT
+			COALESCE(us.notifications_json->>'timezone', ''),
+			(us.notifications_json->>'quiet_hours_start')::int,
+			(us.notifications_json->>'quiet_hours_end')::int
+		FROM (SELECT $1::uuid AS user_id) u
+		LEFT JOIN user_settings us ON us.user_id = u.user_id
+	`, userID).Scan(&schedule.Timezone, &schedule.QuietHoursStart, &schedule.QuietHoursEnd)
+	return schedule, err
+}
+
+func (r *UserRepo) SetNotificationSchedule(ctx context.Context, userID uuid.UUID, schedule models.NotificationSchedule) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_settings (user_id, notifications_json, updated_at)
+		VALUES (
+			$1,
+			jsonb_build_object(
+				'timezone', to_jsonb($2::text),
+				'quiet_hours_start', to_jsonb($3::int),
+				'quiet_hours_end', to_jsonb($4::int)
+			),
+			NOW()
+		)
+		ON CONFLICT (user_id) DO UPDATE
+		SET notifications_json = COALESCE(user_settings.notifications_json, '{}'::jsonb) ||
+			jsonb_build_object(
+				'timezone', to_jsonb($2::text),
+				'quiet_hours_start', to_jsonb($3::int),
+				'quiet_hours_end', to_jsonb($4::int)
+			),
+			updated_at = NOW()
+	`, userID, schedule.Timezone, schedule.QuietHoursStart, schedule.QuietHoursEnd)
+	return err
+}
+
+// GetCurrentStreak counts the consecutive days, ending today or yesterday, on
+// which the user created or studied anything.
+// rowQuerier is a pool or a transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *UserRepo) GetCurrentStreak(ctx context.Context, userID uuid.UUID) (int, error) {
+	return currentStreak(ctx, r.pool, userID)
+}
+
+func currentStreak(ctx context.Context, q rowQuerier, userID uuid.UUID) (int, error) {
+	var streak int
+	err := q.QueryRow(ctx, `
+		WITH RECURSIVE activity_days AS (
+			SELECT DISTINCT DATE(created_at) AS d FROM summaries WHERE user_id = $1
+			UNION
+			SELECT DISTINCT DATE(started_at) FROM quiz_attempts WHERE user_id = $1
+			UNION
+			SELECT DISTINCT DATE(last_reviewed_at) FROM flashcard_cards fc
+			JOIN flashcard_decks fd ON fc.deck_id = fd.id
+			WHERE fd.user_id = $1 AND fc.last_reviewed_at IS NOT NULL
+			UNION
+			SELECT DISTINCT DATE(created_at) FROM presentations WHERE user_id = $1 AND status = 'completed'
+		),
+		start_day AS (
+			SELECT CASE
+				WHEN EXISTS (SELECT 1 FROM activity_days WHERE d = CURRENT_DATE) THEN CURRENT_DATE
+				WHEN EXISTS (SELECT 1 FROM activity_days WHERE d = CURRENT_DATE - INTERVAL '1 day') THEN (CURRENT_DATE - INTERVAL '1 day')::date
+				ELSE NULL::date
+			END AS d
+		),
+		streak_days AS (
+			SELECT d FROM start_day WHERE d IS NOT NULL
+			UNION ALL
+			SELECT (sd.d - INTERVAL '1 day')::date
+			FROM streak_days sd
+			JOIN activity_days a ON a.d = (sd.d - INTERVAL '1 day')::date
+		)
+		SELECT COUNT(*) FROM streak_days
+	`, userID).Scan(&streak)
+	return streak, err
+}
+
+// GetDailyBriefStats loads the cards due by today (the user's local date), the
+// weekly goal with progress over the last 7 days, and the current streak.
+func (r *UserRepo) GetDailyBriefStats(ctx context.Context, userID uuid.UUID, today time.Time) (*models.DailyBrief, error) {
+	return dailyBriefStats(ctx, r.pool, userID, today)
+}
+
+// GetDailyBriefStatsTx is GetDailyBriefStats read inside tx, so it can share
+// a snapshot with the caller's other queries.
+func (r *UserRepo) GetDailyBriefStatsTx(ctx context.Context, tx pgx.Tx, userID uuid.UUID, today time.Time) (*models.DailyBrief, error) {
+	return dailyBriefStats(ctx, tx, userID, today)
+}
+
+func dailyBriefStats(ctx context.Context, q rowQuerier, userID uuid.UUID, today time.Time) (*models.DailyBrief, error) {
+	brief := &models.DailyBrief{}
+	err := q.QueryRow(ctx, `
+		WITH goal AS (
+			SELECT
+				COALESCE((
+					SELECT (notifications_json->>'weekly_goal_target')::int
+					FROM user_settings WHERE user_id = $1
+				), 5) AS target,
+				COALESCE((
+					SELECT notifications_json->>'weekly_goal_type'
+					FROM user_settings WHERE user_id = $1
+				), 'summary') AS goal_type
+		)
+		SELECT
+			(
+				SELECT COUNT(*)
+				FROM flashcard_cards c
+				JOIN flashcard_decks d ON d.id = c.deck_id
+				WHERE d.user_id = $1
+				  AND d.deleted_at IS NULL
+				  AND NOT c.suspended
+				  AND c.next_review_at <= $2::date
+			) AS cards_due,
+			goal.goal_type,
+			goal.target,
+			CASE goal.goal_type
+				WHEN 'quiz' THEN (
+					SELECT COUNT(*) FROM quizzes
+					WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= NOW() - INTERVAL '7 days'
+				)
+				WHEN 'flashcard' THEN (
+					SELECT COUNT(*) FROM flashcard_decks
+					WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= NOW() - INTERVAL '7 days'
+				)
+				WHEN 'presentation' THEN (
+					SELECT COUNT(*) FROM presentations
+					WHERE user_id = $1 AND created_at >= NOW() - INTERVAL '7 days'
+				)
+				ELSE (
+					SELECT COUNT(*) FROM summaries
+					WHERE user_id = $1 AND deleted_at IS NULL AND is_archived = FALSE AND created_at >= NOW() - INTERVAL '7 days'
+				)
+			END AS goal_done
+		FROM goal
+	`, userID, today).Scan(&brief.CardsDue, &brief.WeeklyGoalType, &brief.WeeklyGoalTarget, &brief.WeeklyGoalDone)
+	if err != nil {
+		return nil, err
+	}
+	if brief.WeeklyGoalTarget <= 0 {
+		brief.WeeklyGoalTarget = 5
+	}
+
+	brief.Streak, err = currentStreak(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	return brief, nil
+}
@@ -20,6 +20,7 @@ func New(
 	summaryHandler *handlers.SummaryHandler,
 	presentationHandler *handlers.PresentationHandler,
 	quizHandler *handlers.QuizHandler,
+	quizQuestionHandler *handlers.QuizQuestionHandler,
 	flashcardHandler *handlers.FlashcardHandler,
 	studySessionHandler *handlers.StudySessionHandler,
 	dashboardHandler *handlers.DashboardHandler,
@@ -29,6 +30,20 @@ func New(
 	chatHandler *handlers.ChatHandler,
 	billingHandler *handlers.BillingHandler,
 	folderHandler *handlers.FolderHandler,
+	trashHandler *handlers.TrashHandler,
+	exportHandler *handlers.ExportHandler,
+	outlineHandler *handlers.OutlineHandler,
+	adminHandler *handlers.AdminHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	sessionHandler *handlers.SessionHandler,
+	shareHandler *handlers.ShareHandler,
+	healthHandler *handlers.HealthHandler,
+	studyPlanHandler *handlers.StudyPlanHandler,
+	usageHandler *handlers.UsageHandler,
+	summaryHTMLHandler *handlers.SummaryHTMLHandler,
+	summarySearchHandler *handlers.SummarySearchHandler,
+	summaryRelatedHandler *handlers.SummaryRelatedHandler,
+	summaryVersionHandler *handlers.SummaryVersionHandler,
 	wsHub *websocket.Hub,
 	frontendURL string,
 	trustedProxyCIDRs []string,
@@ -41,23 +56,28 @@ func New(
 	r.Use(chimiddleware.RealIP)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.StructuredRequestLog)
-	r.Use(middleware.CORS(frontendURL))
+	r.Use(middleware.SkipPaths(middleware.CORS(frontendURL),
+		"/health", "/health/live", "/health/ready", "/metrics",
+		"/api/v1/health", "/api/v1/health/live", "/api/v1/health/ready",
+	))
+
+	// API keys only reach content, summaries, quizzes, flashcards and the jobs
+	// generating them; every other authenticated route needs a login session.
+	sessionAuth := chi.Middlewares{jwtAuth.Middleware, middleware.RequireSession}
 
 	// Auth rate limiter (10 req/min per IP)
 	authLimiter := middleware.NewRateLimiterWithTrustedProxies(10, time.Minute, trustedProxyCIDRs)
 
-	// Health check
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"status":"ok"}`))
-	})
+	// Health checks: /health is kept as an alias for liveness
+	r.Get("/health", healthHandler.Live)
+	r.Get("/health/live", healthHandler.Live)
+	r.Get("/health/ready", healthHandler.Ready)
 	r.Get("/metrics", middleware.MetricsHandler)
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(`{"status":"ok"}`))
-		})
+		r.Get("/health", healthHandler.Live)
+		r.Get("/health/live", healthHandler.Live)
+		r.Get("/health/ready", healthHandler.Ready)
 
 		// ──── Auth Routes (public) ────
 		r.Route("/auth", func(r chi.Router) {
@@ -73,24 +93,33 @@ func New(
 				r.Post("/google/code", authHandler.GoogleCodeLogin)
 				r.Post("/refresh", authHandler.Refresh)
 				r.Post("/resend-verification", authHandler.ResendVerification)
+				r.Post("/forgot-password", authHandler.ForgotPassword)
+				r.Post("/reset-password", authHandler.ResetPassword)
 			})
 
 			// Logout requires auth
 			r.Group(func(r chi.Router) {
-				r.Use(jwtAuth.Middleware)
+				r.Use(sessionAuth...)
 				r.Post("/logout", authHandler.Logout)
+				r.Post("/logout-all", authHandler.LogoutAll)
 			})
 		})
 
 		// ──── Content Routes ────
 		r.Route("/content", func(r chi.Router) {
 			r.Get("/supported-formats", contentHandler.SupportedFormats) // Public
+			r.Get("/{id}/file", contentHandler.SignedDownload)           // Public, HMAC-signed link
 
 			r.Group(func(r chi.Router) {
 				r.Use(jwtAuth.Middleware)
 				r.Post("/validate-youtube", contentHandler.ValidateYouTube)
+				r.Post("/validate-playlist", contentHandler.ValidatePlaylist)
 				r.Post("/upload", contentHandler.Upload)
 				r.Get("/{id}", contentHandler.GetContent)
+				r.Get("/{id}/download", contentHandler.Download)
+				r.Get("/{id}/download-url", contentHandler.DownloadURL)
+				r.Post("/{id}/refresh-metadata", contentHandler.RefreshMetadata)
+				r.Post("/{id}/reprocess", contentHandler.Reprocess)
 			})
 		})
 
@@ -98,13 +127,29 @@ func New(
 		r.Route("/summaries", func(r chi.Router) {
 			r.Use(jwtAuth.Middleware)
 			r.Post("/generate", summaryHandler.Generate)
+			r.Get("/focus-areas", summaryHandler.FocusAreas)
 			r.Get("/", summaryHandler.List)
 			r.Get("/{id}", summaryHandler.Get)
+			r.Get("/{id}/export", summaryHandler.Export)
 			r.Put("/{id}", summaryHandler.Update)
 			r.Delete("/{id}", summaryHandler.Delete)
+			r.Post("/{id}/restore", summaryHandler.Restore)
 			r.Post("/{id}/regenerate", summaryHandler.Regenerate)
+			r.Post("/{id}/rewrite", summaryHandler.Rewrite)
+			r.Post("/{id}/rebuild-table", summaryHandler.RebuildTable)
+			r.Get("/{id}/outline", outlineHandler.Get)
+			r.Get("/{id}/html", summaryHTMLHandler.Get)
+			r.Get("/{id}/search", summarySearchHandler.Search)
+			r.Get("/{id}/related", summaryRelatedHandler.Related)
+			r.Get("/{id}/versions", summaryVersionHandler.List)
+			r.Post("/{id}/versions/{version}/restore", summaryVersionHandler.Restore)
 			r.Put("/{id}/favorite", summaryHandler.ToggleFavorite)
+			r.Put("/{id}/archive", summaryHandler.Archive)
+			r.Put("/{id}/unarchive", summaryHandler.Unarchive)
+			r.Get("/{id}/chat", chatHandler.GetChatHistory)
 			r.Post("/{id}/chat", chatHandler.AskQuestion)
+			r.Get("/{id}/chat/suggestions", chatHandler.GetSuggestions)
+			r.Post("/{id}/explain", chatHandler.ExplainSelection)
 			r.Get("/{id}/chat-history", chatHandler.GetChatHistory)
 			r.Post("/{id}/chat-history", chatHandler.CreateChatHistory)
 			r.Delete("/{id}/chat-history", chatHandler.ClearChatHistory)
@@ -112,7 +157,7 @@ func New(
 
 		// ──── Presentation Routes ────
 		r.Route("/presentations", func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Post("/", presentationHandler.CreatePresentation)
 			r.Get("/", presentationHandler.ListPresentations)
 			r.Get("/{id}", presentationHandler.GetPresentation)
@@ -125,11 +170,21 @@ func New(
 		r.Route("/quizzes", func(r chi.Router) {
 			r.Use(jwtAuth.Middleware)
 			r.Post("/generate", quizHandler.Generate)
+			r.Post("/import", quizHandler.ImportCSV)
 			r.Get("/", quizHandler.List)
 			r.Get("/{id}", quizHandler.Get)
+			r.Get("/{id}/export", quizHandler.Export)
 			r.Put("/{id}/favorite", quizHandler.ToggleFavorite)
 			r.Delete("/{id}", quizHandler.Delete)
+			r.Post("/{id}/restore", quizHandler.Restore)
 			r.Post("/{id}/start", quizHandler.StartAttempt)
+			r.Get("/{id}/active-attempt", quizHandler.GetActiveAttempt)
+			r.Get("/{id}/attempts", quizHandler.ListAttempts)
+			r.Get("/{id}/question-stats", quizHandler.QuestionStats)
+			r.Post("/{id}/questions/{index}/report", quizQuestionHandler.Report)
+			r.Put("/{id}/questions/{index}", quizQuestionHandler.Update)
+			r.Post("/{id}/share", shareHandler.ShareQuiz)
+			r.Delete("/{id}/share", shareHandler.UnshareQuiz)
 		})
 
 		r.Route("/quiz-attempts", func(r chi.Router) {
@@ -137,53 +192,92 @@ func New(
 			r.Post("/{id}/save-progress", quizHandler.SaveProgress)
 			r.Post("/{id}/submit", quizHandler.SubmitAttempt)
 			r.Get("/{id}", quizHandler.GetAttempt)
+			r.Get("/{id}/hint", quizHandler.GetHint)
 		})
 
 		// ──── Flashcard Routes ────
 		r.Route("/flashcards", func(r chi.Router) {
 			r.Use(jwtAuth.Middleware)
 			r.Post("/generate", flashcardHandler.Generate)
+			r.Get("/review", flashcardHandler.ReviewQueue)
 
 			r.Route("/decks", func(r chi.Router) {
 				r.Get("/", flashcardHandler.ListDecks)
+				r.Post("/merge", flashcardHandler.MergeDecks)
 				r.Get("/{id}", flashcardHandler.GetDeck)
 				r.Get("/{id}/stats", flashcardHandler.GetDeckStats)
+				r.Get("/{id}/history", flashcardHandler.GetDeckHistory)
+				r.Get("/{id}/leeches", flashcardHandler.GetDeckLeeches)
+				r.Post("/{id}/undo-review", flashcardHandler.UndoLastReview)
 				r.Put("/{id}/favorite", flashcardHandler.ToggleFavorite)
+				r.Put("/{id}/schedule", flashcardHandler.UpdateSchedule)
 				r.Delete("/{id}", flashcardHandler.DeleteDeck)
+				r.Post("/{id}/restore", flashcardHandler.RestoreDeck)
+				r.Post("/{id}/share", shareHandler.ShareDeck)
+				r.Delete("/{id}/share", shareHandler.UnshareDeck)
 			})
 
 			r.Route("/cards", func(r chi.Router) {
 				r.Post("/{id}/rating", flashcardHandler.RateCard)
+				r.Post("/{id}/suspend", flashcardHandler.SuspendCard)
+				r.Post("/{id}/unsuspend", flashcardHandler.UnsuspendCard)
+			})
+		})
+
+		// ──── Shared Deck & Quiz Routes ────
+		r.Route("/shared", func(r chi.Router) {
+			r.Get("/decks/{slug}", shareHandler.PreviewDeck)   // Public
+			r.Get("/quizzes/{slug}", shareHandler.PreviewQuiz) // Public
+
+			r.Group(func(r chi.Router) {
+				r.Use(sessionAuth...)
+				r.Post("/decks/{slug}/import", shareHandler.ImportDeck)
+				r.Post("/quizzes/{slug}/import", shareHandler.ImportQuiz)
 			})
 		})
 
 		// ──── Study Session Routes ────
 		r.Route("/study-sessions", func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Post("/start", studySessionHandler.Start)
 			r.Post("/{id}/heartbeat", studySessionHandler.Heartbeat)
+			r.Post("/{id}/pause", studySessionHandler.Pause)
+			r.Post("/{id}/resume", studySessionHandler.Resume)
 			r.Post("/{id}/stop", studySessionHandler.Stop)
 		})
 
+		// ──── Study Plan Routes ────
+		r.Route("/study-plan", func(r chi.Router) {
+			r.Use(sessionAuth...)
+			r.Post("/generate", studyPlanHandler.Generate)
+			r.Get("/{id}", studyPlanHandler.Get)
+			r.Post("/{id}/items/{itemId}/complete", studyPlanHandler.CompleteItem)
+			r.Post("/{id}/items/{itemId}/uncomplete", studyPlanHandler.UncompleteItem)
+		})
+
 		// ──── Dashboard Routes ────
 		r.Route("/dashboard", func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Get("/stats", dashboardHandler.Stats)
 			r.Put("/weekly-goal", dashboardHandler.SetWeeklyGoal)
 			r.Get("/recent", dashboardHandler.Recent)
 			r.Get("/streak", dashboardHandler.Streak)
 			r.Get("/activity", dashboardHandler.Activity)
+			r.Get("/score-trend", dashboardHandler.ScoreTrend)
+			r.Get("/today", dashboardHandler.Today)
 		})
 
 		// ──── Library Routes ────
 		r.Route("/library", func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Get("/", libraryHandler.List)
+			r.Get("/favorites", libraryHandler.Favorites)
+			r.Post("/bulk", libraryHandler.Bulk)
 		})
 
 		// ──── Folder Routes ────
 		r.Route("/folders", func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Get("/", folderHandler.ListFolders)
 			r.Post("/", folderHandler.CreateFolder)
 			r.Put("/{id}", folderHandler.UpdateFolder)
@@ -192,11 +286,18 @@ func New(
 			r.Delete("/items", folderHandler.RemoveItems)
 		})
 
+		// ──── Trash Routes ────
+		r.Route("/trash", func(r chi.Router) {
+			r.Use(sessionAuth...)
+			r.Get("/", trashHandler.List)
+		})
+
 		// ──── User & Settings Routes ────
 		r.Route("/user", func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Get("/me", userHandler.GetMe)
 			r.Put("/me", userHandler.UpdateMe)
+			r.Get("/account-summary", userHandler.GetAccountSummary)
 			r.Put("/password", userHandler.ChangePassword)
 			r.Put("/gemini-key", userHandler.SetGeminiKey)
 			r.Delete("/me", userHandler.DeleteMe)
@@ -204,18 +305,41 @@ func New(
 			r.Put("/settings", userHandler.UpdateSettings)
 			r.Get("/notifications", userHandler.GetNotificationSettings)
 			r.Put("/notifications", userHandler.UpdateNotificationSetting)
+			r.Get("/notifications/schedule", userHandler.GetNotificationSchedule)
+			r.Put("/notifications/schedule", userHandler.UpdateNotificationSchedule)
+			r.Get("/export", exportHandler.Export)
+			r.Get("/export/{id}/download", exportHandler.Download)
+			r.Post("/api-keys", apiKeyHandler.Create)
+			r.Get("/api-keys", apiKeyHandler.List)
+			r.Delete("/api-keys/{id}", apiKeyHandler.Delete)
+			r.Get("/sessions", sessionHandler.List)
+			r.Delete("/sessions/{id}", sessionHandler.Delete)
+			r.Get("/usage", usageHandler.GetAIUsage)
+			r.Get("/usage/ai", usageHandler.GetAIUsage)
 		})
 
 		// ──── Job Routes ────
 		r.Route("/jobs", func(r chi.Router) {
 			r.Use(jwtAuth.Middleware)
+			r.Get("/", jobHandler.ListJobs)
 			r.Get("/{id}", jobHandler.GetJob)
+			r.Get("/{id}/progress", jobHandler.GetJobProgress)
 			r.Delete("/{id}", jobHandler.CancelJob)
 		})
 
+		// ──── Admin Routes ────
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(sessionAuth...)
+			r.Use(adminHandler.RequireAdmin)
+			r.Get("/jobs/stuck", adminHandler.ListStuckJobs)
+			r.Post("/jobs/{id}/requeue", adminHandler.RequeueJob)
+			r.Get("/gemini/rate", adminHandler.GeminiRateStats)
+			r.Put("/gemini/rate", adminHandler.ResizeGeminiRate)
+		})
+
 		// ──── WebSocket ────
 		r.Group(func(r chi.Router) {
-			r.Use(jwtAuth.Middleware)
+			r.Use(sessionAuth...)
 			r.Get("/ws/ticket", wsTicketHandler.IssueTicket)
 		})
 		r.Get("/ws", wsHub.HandleWebSocket)
@@ -225,7 +349,7 @@ func New(
 			r.Post("/webhook", billingHandler.Webhook) // Public (webhook signature verification inside)
 
 			r.Group(func(r chi.Router) {
-				r.Use(jwtAuth.Middleware)
+				r.Use(sessionAuth...)
 				r.Post("/checkout", billingHandler.CreateCheckoutSession)
 				r.Post("/portal", billingHandler.CreatePortalSession)
 			})
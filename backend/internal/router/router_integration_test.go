@@ -2,18 +2,33 @@ package router
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"lectura-backend/internal/handlers"
 	"lectura-backend/internal/middleware"
 	"lectura-backend/internal/websocket"
 )
 
+type stubAPIKeyResolver struct {
+	userID uuid.UUID
+	scope  string
+}
+
+func (s stubAPIKeyResolver) ResolveAPIKey(context.Context, string) (uuid.UUID, string, error) {
+	return s.userID, s.scope, nil
+}
+
 func buildTestRouter() http.Handler {
-	jwtAuth := middleware.NewJWTAuth("test-jwt-secret")
+	return buildTestRouterWithAuth(middleware.NewJWTAuth("test-jwt-secret"))
+}
+
+func buildTestRouterWithAuth(jwtAuth *middleware.JWTAuth) http.Handler {
 	wsHub := websocket.NewHub(nil, "https://app.example.com")
 
 	return New(
@@ -24,6 +39,7 @@ func buildTestRouter() http.Handler {
 		(*handlers.SummaryHandler)(nil),
 		(*handlers.PresentationHandler)(nil),
 		(*handlers.QuizHandler)(nil),
+		(*handlers.QuizQuestionHandler)(nil),
 		(*handlers.FlashcardHandler)(nil),
 		(*handlers.StudySessionHandler)(nil),
 		(*handlers.DashboardHandler)(nil),
@@ -31,6 +47,22 @@ func buildTestRouter() http.Handler {
 		(*handlers.UserHandler)(nil),
 		(*handlers.JobHandler)(nil),
 		(*handlers.ChatHandler)(nil),
+		(*handlers.BillingHandler)(nil),
+		(*handlers.FolderHandler)(nil),
+		(*handlers.TrashHandler)(nil),
+		(*handlers.ExportHandler)(nil),
+		(*handlers.OutlineHandler)(nil),
+		(*handlers.AdminHandler)(nil),
+		(*handlers.APIKeyHandler)(nil),
+		(*handlers.SessionHandler)(nil),
+		(*handlers.ShareHandler)(nil),
+		(*handlers.HealthHandler)(nil),
+		(*handlers.StudyPlanHandler)(nil),
+		(*handlers.UsageHandler)(nil),
+		(*handlers.SummaryHTMLHandler)(nil),
+		(*handlers.SummarySearchHandler)(nil),
+		(*handlers.SummaryRelatedHandler)(nil),
+		(*handlers.SummaryVersionHandler)(nil),
 		wsHub,
 		"https://app.example.com",
 		nil,
@@ -74,8 +106,9 @@ func TestRouterNew_HealthEndpointsAndRequestID(t *testing.T) {
 			if rr.Header().Get("X-Request-ID") == "" {
 				t.Fatalf("expected X-Request-ID header to be set")
 			}
-			if rr.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
-				t.Fatalf("unexpected Access-Control-Allow-Origin: %q", rr.Header().Get("Access-Control-Allow-Origin"))
+			// Probes come from orchestrators, not browsers, so they skip CORS.
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Fatalf("expected no Access-Control-Allow-Origin on health probes, got %q", got)
 			}
 		})
 	}
@@ -83,7 +116,7 @@ func TestRouterNew_HealthEndpointsAndRequestID(t *testing.T) {
 
 func TestRouterNew_CORSPreflightShortCircuit(t *testing.T) {
 	r := buildTestRouter()
-	req := httptest.NewRequest(http.MethodOptions, "/api/v1/health", nil)
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/auth/login", nil)
 	req.Header.Set("Origin", "https://app.example.com")
 	req.Header.Set("Access-Control-Request-Method", "GET")
 	rr := httptest.NewRecorder()
@@ -128,3 +161,33 @@ func TestRouterNew_PublicAuthRouteWired(t *testing.T) {
 		t.Fatalf("expected VALIDATION_ERROR, got %q", code)
 	}
 }
+
+func TestRouterNew_APIKeysCannotReachAccountRoutes(t *testing.T) {
+	jwtAuth := middleware.NewJWTAuth("test-jwt-secret").
+		WithAPIKeys(stubAPIKeyResolver{userID: uuid.New(), scope: "write"})
+	r := buildTestRouterWithAuth(jwtAuth)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodDelete, "/api/v1/user/me"},
+		{http.MethodPut, "/api/v1/user/password"},
+		{http.MethodDelete, "/api/v1/user/sessions/" + uuid.NewString()},
+		{http.MethodPost, "/api/v1/auth/logout-all"},
+		{http.MethodGet, "/api/v1/ws/ticket"},
+		{http.MethodGet, "/api/v1/admin/jobs/stuck"},
+	} {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.path, nil)
+			req.Header.Set("Authorization", "Bearer lk_write_key")
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusForbidden {
+				t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+			}
+			if code := errorCodeFromRouterResponse(t, rr); code != "FORBIDDEN" {
+				t.Fatalf("expected FORBIDDEN, got %q", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"lectura-backend/internal/middleware"
+	"lectura-backend/internal/models"
+)
+
+// apiKeyDisplayChars is how much of the random part is kept as the visible
+// prefix, e.g. "lk_3f9a1c0e".
+const apiKeyDisplayChars = 8
+
+type apiKeyStore interface {
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+// APIKeyService resolves personal access tokens for the auth middleware.
+type APIKeyService struct {
+	repo apiKeyStore
+}
+
+func NewAPIKeyService(repo apiKeyStore) *APIKeyService {
+	return &APIKeyService{repo: repo}
+}
+
+// GenerateAPIKey creates a new token along with its displayable prefix and the
+// hash to store. The token itself must only be shown to the user once.
+func GenerateAPIKey() (token, prefix, keyHash string, err error) {
+	secret, err := GenerateToken(32)
+	if err != nil {
+		return "", "", "", err
+	}
+	token = middleware.APIKeyPrefix + secret
+	return token, token[:len(middleware.APIKeyPrefix)+apiKeyDisplayChars], HashAPIKey(token), nil
+}
+
+// HashAPIKey returns the lookup hash of a token. Tokens carry 256 random bits,
+// so a fast hash is enough.
+func HashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResolveAPIKey implements middleware.APIKeyResolver.
+func (s *APIKeyService) ResolveAPIKey(ctx context.Context, token string) (uuid.UUID, string, error) {
+	key, err := s.repo.GetByHash(ctx, HashAPIKey(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, "", middleware.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("look up API key: %w", err)
+	}
+
+	if err := s.repo.TouchLastUsed(ctx, key.ID); err != nil {
+		log.Printf("Failed to record API key use for %s: %v", key.ID, err)
+	}
+	return key.UserID, key.Scope, nil
+}
@@ -31,15 +31,16 @@ type AuthService struct {
 	userRepo           authUserRepository
 	redis              *redis.Client
 	jwt                *middleware.JWTAuth
-	email              verificationEmailSender
+	email              accountEmailSender
 	googleClientID     string
 	googleClientSecret string
 	googleRedirectURI  string
 	issueTokensFn      func(ctx context.Context, user *models.User) (*models.AuthTokens, error)
 }
 
-type verificationEmailSender interface {
+type accountEmailSender interface {
 	SendVerificationEmail(to, token string) error
+	SendPasswordResetEmail(to, token string) error
 }
 
 type authUserRepository interface {
@@ -51,6 +52,16 @@ type authUserRepository interface {
 	UpdateLastLogin(ctx context.Context, userID uuid.UUID) error
 	GetByGoogleID(ctx context.Context, googleID string) (*models.User, error)
 	LinkGoogle(ctx context.Context, userID uuid.UUID, googleID string) error
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+}
+
+const (
+	refreshTokenTTL  = 7 * 24 * time.Hour
+	passwordResetTTL = time.Hour
+)
+
+func passwordResetKey(token string) string {
+	return "password_reset:" + token
 }
 
 func NewAuthService(
@@ -187,7 +198,7 @@ func (s *AuthService) VerifyEmail(ctx context.Context, token string) (*models.Au
 		return nil, err
 	}
 
-	return s.issueTokens(ctx, user)
+	return s.issueTokens(ctx, user, nil)
 }
 
 func (s *AuthService) Login(ctx context.Context, req models.LoginRequest) (*models.AuthTokens, error) {
@@ -219,19 +230,31 @@ func (s *AuthService) Login(ctx context.Context, req models.LoginRequest) (*mode
 }
 
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
-	// Look up refresh token
-	userIDStr, err := s.redis.Get(ctx, "refresh:"+refreshToken).Result()
+	// Look up and delete the old token (rotation) in one step, so it cannot
+	// be used twice.
+	value, err := s.redis.GetDel(ctx, "refresh:"+refreshToken).Result()
 	if err != nil {
 		return nil, &UnauthorizedError{Message: "Invalid or expired refresh token. Please log in again."}
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	userID, sessionID, err := parseRefreshTokenValue(value)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, err
+	}
+	// Tokens from before sessions cannot be revoked by LogoutAll or a
+	// password reset, so they are not honoured.
+	if sessionID == uuid.Nil {
+		return nil, errSessionSignedOut
 	}
 
-	// Delete old token (rotation)
-	s.redis.Del(ctx, "refresh:"+refreshToken)
+	// A revoked session is gone from the hash even if its token survived.
+	stored, err := s.getSession(ctx, userID, sessionID)
+	if errors.Is(err, redis.Nil) {
+		return nil, errSessionSignedOut
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -242,49 +265,150 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*m
 		return nil, &UnauthorizedError{Message: "Account is deactivated"}
 	}
 
-	return s.issueTokens(ctx, user)
+	return s.issueTokens(ctx, user, &stored.Session)
 }
 
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	return s.redis.Del(ctx, "refresh:"+refreshToken).Err()
+	value, err := s.redis.GetDel(ctx, "refresh:"+refreshToken).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if userID, sessionID, err := parseRefreshTokenValue(value); err == nil && sessionID != uuid.Nil {
+		s.redis.HDel(ctx, userSessionsKey(userID), sessionID.String())
+	}
+	return nil
 }
 
-func (s *AuthService) ResendVerification(ctx context.Context, email string) error {
+// RequestPasswordReset emails a one-hour password reset link and returns how
+// long the caller must wait before asking again. The cooldown is kept per
+// address and runs the same for unknown and deactivated addresses, which get
+// no email, and a cooldown still running is reported as the wait rather than
+// an error, so the response does not reveal whether an account exists.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) (time.Duration, error) {
 	email = normalizeEmail(email)
 
+	if err := checkEmailCooldown(ctx, s.redis, EmailKindPasswordReset, email); err != nil {
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) {
+			return rateErr.RetryAfter, nil
+		}
+		return 0, err
+	}
+	cooldown, err := startEmailCooldown(ctx, s.redis, EmailKindPasswordReset, email)
+	if err != nil {
+		return 0, err
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil
+			return cooldown, nil
 		}
-		return fmt.Errorf("failed to look up user for resend verification: %w", err)
+		return 0, fmt.Errorf("failed to look up user for password reset: %w", err)
 	}
 
-	if user.IsVerified {
-		return nil
+	if !user.IsActive {
+		return cooldown, nil
+	}
+
+	token, err := GenerateToken(32)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.redis.Set(ctx, passwordResetKey(token), user.ID.String(), passwordResetTTL).Err(); err != nil {
+		return 0, fmt.Errorf("failed to store password reset token: %w", err)
+	}
+
+	if s.email != nil {
+		go func(email, resetToken string) {
+			if err := s.email.SendPasswordResetEmail(email, resetToken); err != nil {
+				log.Printf("✗ password reset email send failed to %s: %v", email, err)
+			} else {
+				log.Printf("✓ password reset email queued to %s", email)
+			}
+		}(user.Email, token)
 	}
 
-	// Rate limit check
-	rateLimitKey := fmt.Sprintf("resend_limit:%s", user.ID.String())
-	exists, err := s.redis.Exists(ctx, rateLimitKey).Result()
+	return cooldown, nil
+}
+
+// ResetPassword sets a new password using a token from RequestPasswordReset.
+// The token works once, and every refresh token of the user is revoked so
+// other sessions must sign in again.
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if err := validatePassword(newPassword); err != nil {
+		return &ValidationError{Fields: map[string]string{"password": err.Error()}}
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return &NotFoundError{Message: "Invalid or expired reset token"}
+	}
+	// GETDEL consumes the token atomically, so it cannot be used twice.
+	userIDStr, err := s.redis.GetDel(ctx, passwordResetKey(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return &NotFoundError{Message: "Invalid or expired reset token"}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up password reset token: %w", err)
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid user ID in reset token: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), 12)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hash)); err != nil {
+		return err
+	}
+
+	return s.revokeRefreshTokens(ctx, userID)
+}
+
+// ResendVerification emails a fresh verification link and returns how long
+// the caller must wait before asking again. Each resend in a row waits longer.
+// The cooldown is kept per address and runs the same for unknown and
+// already-verified addresses, which get no email, so neither the wait nor a
+// rate limit error reveals whether an account exists.
+func (s *AuthService) ResendVerification(ctx context.Context, email string) (time.Duration, error) {
+	email = normalizeEmail(email)
+
+	if err := checkEmailCooldown(ctx, s.redis, EmailKindVerification, email); err != nil {
+		return 0, err
+	}
+	cooldown, err := startEmailCooldown(ctx, s.redis, EmailKindVerification, email)
 	if err != nil {
-		return fmt.Errorf("failed to check resend rate limit: %w", err)
+		return 0, err
 	}
-	if exists > 0 {
-		return &RateLimitError{Message: "Please wait 60 seconds before requesting another verification email"}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return cooldown, nil
+		}
+		return 0, fmt.Errorf("failed to look up user for resend verification: %w", err)
+	}
+
+	if user.IsVerified {
+		return cooldown, nil
 	}
 
 	// Generate new token
 	token, err := GenerateToken(32)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if err := s.redis.Set(ctx, "email_verify:"+token, user.ID.String(), 24*time.Hour).Err(); err != nil {
-		return fmt.Errorf("failed to store verification token: %w", err)
-	}
-	if err := s.redis.Set(ctx, rateLimitKey, "1", 60*time.Second).Err(); err != nil {
-		return fmt.Errorf("failed to set resend rate limit: %w", err)
+		return 0, fmt.Errorf("failed to store verification token: %w", err)
 	}
 
 	// Send verification email
@@ -298,26 +422,22 @@ func (s *AuthService) ResendVerification(ctx context.Context, email string) erro
 		}(user.Email, token)
 	}
 
-	return nil
+	return cooldown, nil
 }
 
-func (s *AuthService) issueTokens(ctx context.Context, user *models.User) (*models.AuthTokens, error) {
+// issueTokens signs the user in on the given session, or on a new one when
+// session is nil.
+func (s *AuthService) issueTokens(ctx context.Context, user *models.User, session *models.Session) (*models.AuthTokens, error) {
 	accessToken, err := s.jwt.GenerateAccessToken(user.ID, user.Email, user.Plan)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	refreshToken, err := GenerateToken(64)
+	refreshToken, err := s.storeSession(ctx, user.ID, session)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store refresh token in Redis (7 days)
-	err = s.redis.Set(ctx, "refresh:"+refreshToken, user.ID.String(), 7*24*time.Hour).Err()
-	if err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
-	}
-
 	return &models.AuthTokens{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -498,7 +618,7 @@ func (s *AuthService) issueTokensForUser(ctx context.Context, user *models.User)
 	if s.issueTokensFn != nil {
 		return s.issueTokensFn(ctx, user)
 	}
-	return s.issueTokens(ctx, user)
+	return s.issueTokens(ctx, user, nil)
 }
 
 func GenerateToken(bytes int) (string, error) {
@@ -549,6 +669,11 @@ type ForbiddenError struct{ Message string }
 
 func (e *ForbiddenError) Error() string { return e.Message }
 
-type RateLimitError struct{ Message string }
+// RateLimitError reports a throttled request. RetryAfter is how long to wait,
+// or zero if unknown.
+type RateLimitError struct {
+	Message    string
+	RetryAfter time.Duration
+}
 
 func (e *RateLimitError) Error() string { return e.Message }
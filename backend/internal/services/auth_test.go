@@ -26,11 +26,19 @@ func (s *stubVerificationEmailSender) SendVerificationEmail(to, token string) er
 	return nil
 }
 
+func (s *stubVerificationEmailSender) SendPasswordResetEmail(to, token string) error {
+	if s.called != nil {
+		s.called <- to
+	}
+	return nil
+}
+
 type stubAuthUserRepo struct {
 	usersByEmail      map[string]*models.User
 	createdUsers      []*models.User
 	lastGetByEmailArg string
 	getByEmailErr     error
+	updatedPasswords  map[uuid.UUID]string
 }
 
 func (s *stubAuthUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
@@ -85,6 +93,14 @@ func (s *stubAuthUserRepo) LinkGoogle(ctx context.Context, userID uuid.UUID, goo
 	return nil
 }
 
+func (s *stubAuthUserRepo) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	if s.updatedPasswords == nil {
+		s.updatedPasswords = map[uuid.UUID]string{}
+	}
+	s.updatedPasswords[userID] = passwordHash
+	return nil
+}
+
 func TestRegister_MixedCaseEmail_StoredAsLowercase(t *testing.T) {
 	repo := &stubAuthUserRepo{usersByEmail: map[string]*models.User{}}
 	svc := &AuthService{userRepo: repo, redis: redis.NewClient(&redis.Options{
@@ -149,18 +165,10 @@ func TestLogin_MixedCaseEmail_SucceedsLookupWithNormalizedEmail(t *testing.T) {
 func TestResendVerification_UnknownEmail_ReturnsNilAndDoesNotSend(t *testing.T) {
 	repo := &stubAuthUserRepo{usersByEmail: map[string]*models.User{}}
 	emailSender := &stubVerificationEmailSender{called: make(chan string, 1)}
-	svc := &AuthService{
-		userRepo: repo,
-		redis: redis.NewClient(&redis.Options{
-			Addr:         "127.0.0.1:0",
-			DialTimeout:  10 * time.Millisecond,
-			ReadTimeout:  10 * time.Millisecond,
-			WriteTimeout: 10 * time.Millisecond,
-		}),
-		email: emailSender,
-	}
+	svc, _ := newCannedAuthService(nil)
+	svc.userRepo, svc.email = repo, emailSender
 
-	err := svc.ResendVerification(context.Background(), "Unknown@Example.com")
+	_, err := svc.ResendVerification(context.Background(), "Unknown@Example.com")
 	if err != nil {
 		t.Fatalf("expected nil error for unknown email, got %v", err)
 	}
@@ -191,7 +199,7 @@ func TestResendVerification_KnownUnverifiedEmail_ReturnsNilAndSends(t *testing.T
 		email: emailSender,
 	}
 
-	err := svc.ResendVerification(context.Background(), "Ada@Example.com")
+	_, err := svc.ResendVerification(context.Background(), "Ada@Example.com")
 	if err == nil {
 		t.Fatalf("expected error when redis is unavailable")
 	}
@@ -236,9 +244,10 @@ func TestGoogleCodeLogin_UpstreamTimeout_ReturnsError(t *testing.T) {
 
 func TestResendVerification_DBError_ReturnsInternalError(t *testing.T) {
 	repo := &stubAuthUserRepo{getByEmailErr: errors.New("db timeout")}
-	svc := &AuthService{userRepo: repo}
+	svc, _ := newCannedAuthService(nil)
+	svc.userRepo = repo
 
-	err := svc.ResendVerification(context.Background(), "Ada@Example.com")
+	_, err := svc.ResendVerification(context.Background(), "Ada@Example.com")
 	if err == nil {
 		t.Fatalf("expected non-nil error on db failure")
 	}
@@ -250,9 +259,10 @@ func TestResendVerification_DBError_ReturnsInternalError(t *testing.T) {
 
 func TestResendVerification_UnknownEmail_ReturnsNil(t *testing.T) {
 	repo := &stubAuthUserRepo{usersByEmail: map[string]*models.User{}}
-	svc := &AuthService{userRepo: repo}
+	svc, _ := newCannedAuthService(nil)
+	svc.userRepo = repo
 
-	err := svc.ResendVerification(context.Background(), "unknown@example.com")
+	_, err := svc.ResendVerification(context.Background(), "unknown@example.com")
 	if err != nil {
 		t.Fatalf("expected nil for unknown email, got %v", err)
 	}
@@ -261,3 +271,112 @@ func TestResendVerification_UnknownEmail_ReturnsNil(t *testing.T) {
 type roundTripFuncAuth func(*http.Request) (*http.Response, error)
 
 func (f roundTripFuncAuth) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestRequestPasswordReset_UnknownEmail_ReturnsCooldownWithoutSending(t *testing.T) {
+	repo := &stubAuthUserRepo{usersByEmail: map[string]*models.User{}}
+	emailSender := &stubVerificationEmailSender{called: make(chan string, 1)}
+	svc, _ := newCannedAuthService(nil)
+	svc.userRepo, svc.email = repo, emailSender
+
+	cooldown, err := svc.RequestPasswordReset(context.Background(), "Nobody@Example.com")
+	if err != nil {
+		t.Fatalf("expected nil error for unknown email, got %v", err)
+	}
+	if cooldown != emailCooldownStep(1) {
+		t.Fatalf("cooldown = %v, want %v", cooldown, emailCooldownStep(1))
+	}
+	if repo.lastGetByEmailArg != "nobody@example.com" {
+		t.Fatalf("expected normalized lookup email, got %q", repo.lastGetByEmailArg)
+	}
+
+	select {
+	case <-emailSender.called:
+		t.Fatalf("did not expect a reset email for an unknown address")
+	default:
+	}
+}
+
+func TestRequestPasswordReset_DeactivatedUser_DoesNotSend(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", IsActive: false}
+	repo := &stubAuthUserRepo{usersByEmail: map[string]*models.User{"ada@example.com": user}}
+	emailSender := &stubVerificationEmailSender{called: make(chan string, 1)}
+	svc, _ := newCannedAuthService(nil)
+	svc.userRepo, svc.email = repo, emailSender
+
+	if _, err := svc.RequestPasswordReset(context.Background(), "ada@example.com"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	select {
+	case <-emailSender.called:
+		t.Fatalf("did not expect a reset email for a deactivated account")
+	default:
+	}
+}
+
+func TestEmailCooldowns_SameForUnknownAndRegisteredAddresses(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", IsActive: true}
+	repo := &stubAuthUserRepo{usersByEmail: map[string]*models.User{"ada@example.com": user}}
+	svc, hook := newCannedAuthService(map[string]func(redis.Cmder){
+		"pttl": func(cmd redis.Cmder) { cmd.(*redis.DurationCmd).SetVal(4 * time.Minute) },
+	})
+	svc.userRepo = repo
+
+	for _, email := range []string{"ada@example.com", "nobody@example.com"} {
+		cooldown, err := svc.RequestPasswordReset(context.Background(), email)
+		if err != nil || cooldown != 4*time.Minute {
+			t.Fatalf("RequestPasswordReset(%s) = %v, %v; want the running 4m cooldown", email, cooldown, err)
+		}
+
+		_, err = svc.ResendVerification(context.Background(), email)
+		var rateErr *RateLimitError
+		if !errors.As(err, &rateErr) || rateErr.RetryAfter != 4*time.Minute {
+			t.Fatalf("ResendVerification(%s) error = %v, want the running 4m cooldown", email, err)
+		}
+	}
+
+	if got := hook.sent("pttl"); len(got) != 2 || got[1] != emailCooldownKey(EmailKindPasswordReset, "ada@example.com") {
+		t.Fatalf("cooldown should be keyed by address, got %v", got)
+	}
+	if len(repo.updatedPasswords) != 0 || hook.sent("set") != nil {
+		t.Fatalf("nothing should be issued while cooling down: %v", hook.commands)
+	}
+}
+
+func TestResetPassword_WeakPassword_ReturnsValidationError(t *testing.T) {
+	repo := &stubAuthUserRepo{}
+	svc := &AuthService{userRepo: repo}
+
+	err := svc.ResetPassword(context.Background(), "token", "short")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Fields["password"] == "" {
+		t.Fatalf("expected a password validation error, got %v", err)
+	}
+	if len(repo.updatedPasswords) != 0 {
+		t.Fatalf("password must not change when validation fails")
+	}
+}
+
+func TestResetPassword_RedisUnavailable_DoesNotChangePassword(t *testing.T) {
+	repo := &stubAuthUserRepo{}
+	svc := &AuthService{
+		userRepo: repo,
+		redis: redis.NewClient(&redis.Options{
+			Addr:         "127.0.0.1:0",
+			DialTimeout:  10 * time.Millisecond,
+			ReadTimeout:  10 * time.Millisecond,
+			WriteTimeout: 10 * time.Millisecond,
+		}),
+	}
+
+	err := svc.ResetPassword(context.Background(), "token", "newpass123")
+	if err == nil {
+		t.Fatalf("expected an error when redis is unavailable")
+	}
+	if _, ok := err.(*NotFoundError); ok {
+		t.Fatalf("redis failures must not be reported as an invalid token")
+	}
+	if len(repo.updatedPasswords) != 0 {
+		t.Fatalf("password must not change when the token cannot be checked")
+	}
+}
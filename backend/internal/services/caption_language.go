@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// CaptionLanguageAny asks for whatever caption track a video has, preferring
+// English. It is the behaviour for content created before caption languages
+// could be chosen.
+const CaptionLanguageAny = "auto"
+
+// ErrCaptionLanguageUnavailable means the video has captions, but none in the
+// language the user asked for.
+var ErrCaptionLanguageUnavailable = errors.New("captions are not available in the requested language")
+
+var captionLanguagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+// NormalizeCaptionLanguage validates a caption language code such as "en" or
+// "pt-BR". The primary subtag is lower-cased; "auto" is accepted as-is.
+func NormalizeCaptionLanguage(lang string) (string, bool) {
+	lang = strings.TrimSpace(lang)
+	if strings.EqualFold(lang, CaptionLanguageAny) {
+		return CaptionLanguageAny, true
+	}
+	if !captionLanguagePattern.MatchString(lang) {
+		return "", false
+	}
+	base, region, found := strings.Cut(lang, "-")
+	base = strings.ToLower(base)
+	if !found {
+		return base, true
+	}
+	return base + "-" + region, true
+}
+
+// captionLanguageCandidates lists the track codes accepted for a requested
+// language: the exact code first, then its primary subtag.
+func captionLanguageCandidates(lang string) []string {
+	if lang == "" || lang == CaptionLanguageAny {
+		return nil
+	}
+	candidates := []string{lang}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		candidates = append(candidates, base)
+	}
+	return candidates
+}
+
+// captionLanguageMatches reports whether a track in language got satisfies a
+// request for want. Tracks match on their primary subtag, so "en-GB" captions
+// satisfy a request for "en".
+func captionLanguageMatches(want, got string) bool {
+	if want == "" || want == CaptionLanguageAny {
+		return true
+	}
+	wantBase, _, _ := strings.Cut(strings.ToLower(want), "-")
+	gotBase, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(got)), "-")
+	return wantBase == gotBase
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCaptionLanguage(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"en", "en", true},
+		{" ES ", "es", true},
+		{"pt-BR", "pt-BR", true},
+		{"zh-Hans", "zh-Hans", true},
+		{"AUTO", CaptionLanguageAny, true},
+		{"english", "", false},
+		{"e", "", false},
+		{"en_US", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeCaptionLanguage(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("NormalizeCaptionLanguage(%q) = (%q, %v), want (%q, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCaptionLanguageMatches(t *testing.T) {
+	if !captionLanguageMatches("en", "en-GB") {
+		t.Fatalf("expected en-GB captions to satisfy en")
+	}
+	if !captionLanguageMatches("pt-BR", "pt") {
+		t.Fatalf("expected pt captions to satisfy pt-BR")
+	}
+	if captionLanguageMatches("en", "es") {
+		t.Fatalf("expected es captions not to satisfy en")
+	}
+	if !captionLanguageMatches(CaptionLanguageAny, "es") {
+		t.Fatalf("expected auto to accept any track")
+	}
+}
+
+const captionTracksPage = `"captionTracks":[{"baseUrl":"https:\/\/www.youtube.com\/api\/timedtext?v=x&lang=es","name":{"simpleText":"Spanish"},"languageCode":"es","isTranslatable":true},{"baseUrl":"https:\/\/www.youtube.com\/api\/timedtext?v=x&lang=en","name":{"simpleText":"English"},"languageCode":"en","isTranslatable":true}],"audioTracks"`
+
+func TestExtractCaptionURL_SelectsRequestedLanguage(t *testing.T) {
+	got, err := extractCaptionURL(captionTracksPage, "en")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "https://www.youtube.com/api/timedtext?v=x&lang=en" {
+		t.Fatalf("expected English track, got %q", got)
+	}
+
+	got, err = extractCaptionURL(captionTracksPage, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "https://www.youtube.com/api/timedtext?v=x&lang=es" {
+		t.Fatalf("expected first track when no language requested, got %q", got)
+	}
+}
+
+func TestExtractCaptionURL_MissingLanguage(t *testing.T) {
+	_, err := extractCaptionURL(captionTracksPage, "fr")
+	if !errors.Is(err, ErrCaptionLanguageUnavailable) {
+		t.Fatalf("expected ErrCaptionLanguageUnavailable, got %v", err)
+	}
+}
+
+func TestExtractCaptionURL_NoCaptionsOnlyForPlayablePage(t *testing.T) {
+	_, err := extractCaptionURL(`"playabilityStatus":{"status":"OK","playableInEmbed":true},"videoDetails":{}`, "")
+	if !errors.Is(err, ErrNoCaptions) {
+		t.Fatalf("expected ErrNoCaptions for a playable page without tracks, got %v", err)
+	}
+
+	_, err = extractCaptionURL(`<html><form action="https://consent.youtube.com/save"></form></html>`, "")
+	if err == nil || errors.Is(err, ErrNoCaptions) {
+		t.Fatalf("expected a non-cacheable error for a consent page, got %v", err)
+	}
+
+	if !errors.Is(ErrNoCaptionsCached, ErrNoCaptions) {
+		t.Fatalf("expected ErrNoCaptionsCached to wrap ErrNoCaptions")
+	}
+}
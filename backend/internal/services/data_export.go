@@ -0,0 +1,133 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+var exportSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// DataExportRetention is how long a background data export archive can be
+// downloaded. Archives hold unmasked transcripts, so the worker deletes them
+// once it passes, and with the account.
+const DataExportRetention = 7 * 24 * time.Hour
+
+// DataExportKey is the storage key of a background data export archive.
+func DataExportKey(userID, jobID uuid.UUID) string {
+	return "exports/" + userID.String() + "/" + jobID.String() + ".zip"
+}
+
+// WriteDataExportZip writes a personal data export as a ZIP archive: one JSON
+// file per entity plus a markdown copy of every summary.
+func WriteDataExportZip(w io.Writer, data *models.UserDataExport) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{"profile.json", data.Profile},
+		{"settings.json", data.Settings},
+		{"summaries.json", data.Summaries},
+		{"quizzes.json", data.Quizzes},
+		{"quiz_attempts.json", data.QuizAttempts},
+		{"flashcard_decks.json", data.Decks},
+		{"flashcard_cards.json", data.Cards},
+		{"study_sessions.json", data.StudySessions},
+		{"original_transcripts.json", data.OriginalTranscripts},
+	}
+	for _, f := range files {
+		if err := writeZipJSON(zw, f.name, f.value); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+
+	for _, s := range data.Summaries {
+		fw, err := zw.Create("summaries/" + exportSummaryFileName(s))
+		if err != nil {
+			zw.Close()
+			return err
+		}
+		if _, err := io.WriteString(fw, RenderSummaryMarkdown(s)); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeZipJSON(zw *zip.Writer, name string, value interface{}) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	return nil
+}
+
+func exportSummaryFileName(s models.ExportSummary) string {
+	slug := strings.Trim(exportSlugPattern.ReplaceAllString(strings.ToLower(s.Title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = "summary"
+	}
+	return fmt.Sprintf("%s-%s.md", slug, s.ID.String()[:8])
+}
+
+// RenderSummaryMarkdown renders an exported summary as a standalone markdown file.
+func RenderSummaryMarkdown(s models.ExportSummary) string {
+	var b strings.Builder
+
+	title := strings.TrimSpace(s.Title)
+	if title == "" {
+		title = "Untitled summary"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- Created: %s\n", s.CreatedAt.UTC().Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "- Format: %s (%s)\n", s.Format, s.Length)
+	if len(s.Tags) > 0 {
+		fmt.Fprintf(&b, "- Tags: %s\n", strings.Join(s.Tags, ", "))
+	}
+	b.WriteString("\n")
+
+	if s.Description != nil && strings.TrimSpace(*s.Description) != "" {
+		fmt.Fprintf(&b, "> %s\n\n", strings.TrimSpace(*s.Description))
+	}
+
+	if s.Format == "cornell" && (s.CornellCues != nil || s.CornellNotes != nil || s.CornellSummary != nil) {
+		writeMarkdownSection(&b, "Cues", s.CornellCues)
+		writeMarkdownSection(&b, "Notes", s.CornellNotes)
+		writeMarkdownSection(&b, "Summary", s.CornellSummary)
+		return b.String()
+	}
+
+	if s.Content != nil {
+		b.WriteString(strings.TrimSpace(*s.Content))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func writeMarkdownSection(b *strings.Builder, heading string, body *string) {
+	if body == nil || strings.TrimSpace(*body) == "" {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n%s\n\n", heading, strings.TrimSpace(*body))
+}
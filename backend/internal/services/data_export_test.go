@@ -0,0 +1,70 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestWriteDataExportZip_WritesEntityFilesAndMarkdown(t *testing.T) {
+	content := "Photosynthesis converts light into chemical energy."
+	data := &models.UserDataExport{
+		ExportedAt: time.Now(),
+		Profile:    models.ExportProfile{Email: "student@example.com", FullName: "Student"},
+		Summaries: []models.ExportSummary{{
+			ID:        uuid.New(),
+			Title:     "Biology: Photosynthesis!",
+			Format:    "bullets",
+			Length:    "standard",
+			Content:   &content,
+			CreatedAt: time.Now(),
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDataExportZip(&buf, data); err != nil {
+		t.Fatalf("WriteDataExportZip returned error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+
+	for _, name := range []string{"profile.json", "summaries.json", "quizzes.json", "quiz_attempts.json", "flashcard_decks.json", "flashcard_cards.json", "study_sessions.json", "original_transcripts.json", "settings.json"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("expected %s in archive", name)
+		}
+	}
+	if strings.Contains(files["profile.json"], "password") {
+		t.Fatalf("profile export must not contain password fields: %s", files["profile.json"])
+	}
+
+	var markdown string
+	for name, body := range files {
+		if strings.HasPrefix(name, "summaries/biology-photosynthesis-") && strings.HasSuffix(name, ".md") {
+			markdown = body
+		}
+	}
+	if !strings.Contains(markdown, "# Biology: Photosynthesis!") || !strings.Contains(markdown, content) {
+		t.Fatalf("unexpected summary markdown: %q", markdown)
+	}
+}
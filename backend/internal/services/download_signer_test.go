@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDownloadSigner_RoundTrip(t *testing.T) {
+	s := NewDownloadSigner("secret", time.Minute)
+	id := uuid.New()
+	now := time.Unix(1_700_000_000, 0)
+
+	expires, sig := s.Sign(id, now)
+	if expires != now.Add(time.Minute).Unix() {
+		t.Fatalf("unexpected expiry %d", expires)
+	}
+	exp := strconv.FormatInt(expires, 10)
+
+	if err := s.Verify(id, exp, sig, now.Add(30*time.Second)); err != nil {
+		t.Fatalf("expected valid link, got %v", err)
+	}
+	if err := s.Verify(id, exp, sig, now.Add(2*time.Minute)); !errors.Is(err, ErrDownloadLinkExpired) {
+		t.Fatalf("expected expired link, got %v", err)
+	}
+}
+
+func TestDownloadSigner_RejectsTampering(t *testing.T) {
+	s := NewDownloadSigner("secret", time.Minute)
+	id := uuid.New()
+	now := time.Now()
+	expires, sig := s.Sign(id, now)
+	exp := strconv.FormatInt(expires, 10)
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		expires string
+		sig     string
+		signer  *DownloadSigner
+	}{
+		{"other content", uuid.New(), exp, sig, s},
+		{"extended expiry", id, strconv.FormatInt(expires+3600, 10), sig, s},
+		{"garbage expiry", id, "soon", sig, s},
+		{"non-hex signature", id, exp, "zz", s},
+		{"empty signature", id, exp, "", s},
+		{"other secret", id, exp, sig, NewDownloadSigner("other", time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.signer.Verify(tt.id, tt.expires, tt.sig, now); !errors.Is(err, ErrDownloadLinkInvalid) {
+				t.Fatalf("expected invalid link, got %v", err)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"fmt"
+	"html"
 	"log"
 	"net/smtp"
 	"strings"
 	"time"
+
+	"lectura-backend/internal/models"
 )
 
 type EmailService struct {
@@ -105,20 +108,71 @@ func (s *EmailService) SendPasswordResetEmail(to, token string) error {
 	return s.sendHTML(to, subject, body)
 }
 
+// completionEmail holds the wording for a "your result is ready" email.
+type completionEmail struct {
+	subject       string
+	noun          string
+	fallbackTitle string
+	button        string
+	path          string
+}
+
+var (
+	summaryCompletionEmail = completionEmail{
+		subject:       "Your summary is ready",
+		noun:          "summary",
+		fallbackTitle: "Your summary",
+		button:        "Open Summary",
+		path:          "/summary/",
+	}
+	quizCompletionEmail = completionEmail{
+		subject:       "Your quiz is ready",
+		noun:          "quiz",
+		fallbackTitle: "Your quiz",
+		button:        "Start Quiz",
+		path:          "/quiz/take/",
+	}
+	flashcardsCompletionEmail = completionEmail{
+		subject:       "Your flashcards are ready",
+		noun:          "flashcards",
+		fallbackTitle: "Your flashcards",
+		button:        "Study Flashcards",
+		path:          "/flashcards/study/",
+	}
+)
+
 func (s *EmailService) SendProcessingCompleteEmail(to, summaryTitle string, summaryID string) error {
+	return s.sendCompletionEmail(to, summaryCompletionEmail, summaryTitle, summaryID)
+}
+
+// SendQuizReadyEmail tells a user their quiz has finished generating.
+func (s *EmailService) SendQuizReadyEmail(to, quizTitle, quizID string) error {
+	return s.sendCompletionEmail(to, quizCompletionEmail, quizTitle, quizID)
+}
+
+// SendFlashcardsReadyEmail tells a user their flashcard deck has finished
+// generating.
+func (s *EmailService) SendFlashcardsReadyEmail(to, deckTitle, deckID string) error {
+	return s.sendCompletionEmail(to, flashcardsCompletionEmail, deckTitle, deckID)
+}
+
+func (s *EmailService) sendCompletionEmail(to string, c completionEmail, resultTitle, resultID string) error {
 	if strings.TrimSpace(to) == "" {
 		return fmt.Errorf("recipient email is required")
 	}
 
-	title := strings.TrimSpace(summaryTitle)
+	viewURL := s.frontendURL + c.path + resultID
+	return s.sendHTML(to, c.subject, c.render(resultTitle, viewURL))
+}
+
+func (c completionEmail) render(resultTitle, viewURL string) string {
+	title := strings.TrimSpace(resultTitle)
 	if title == "" {
-		title = "Your summary"
+		title = c.fallbackTitle
 	}
+	title = html.EscapeString(title)
 
-	viewURL := fmt.Sprintf("%s/summary/%s", s.frontendURL, summaryID)
-
-	subject := "Your summary is ready"
-	body := fmt.Sprintf(`<!DOCTYPE html>
+	return fmt.Sprintf(`<!DOCTYPE html>
 <html>
 <head><meta charset="utf-8"></head>
 <body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f8fafc;">
@@ -128,15 +182,15 @@ func (s *EmailService) SendProcessingCompleteEmail(to, summaryTitle string, summ
       <p style="color: rgba(255,255,255,0.9); margin: 8px 0 0; font-size: 14px;">Processing complete</p>
     </div>
     <div style="padding: 28px 32px;">
-      <h2 style="margin: 0 0 12px; font-size: 20px; color: #0f172a;">Your summary is ready</h2>
+      <h2 style="margin: 0 0 12px; font-size: 20px; color: #0f172a;">%s</h2>
       <p style="margin: 0 0 14px; color: #334155; font-size: 14px; line-height: 1.6;">
-        We finished generating your summary:
+        We finished generating your %s:
       </p>
       <p style="margin: 0 0 22px; color: #0f172a; font-size: 15px; font-weight: 600;">
         %s
       </p>
       <a href="%s" style="display: inline-block; background: #6366f1; color: white; text-decoration: none; padding: 11px 24px; border-radius: 8px; font-weight: 600; font-size: 14px;">
-        Open Summary
+        %s
       </a>
       <p style="color: #94a3b8; font-size: 12px; margin: 20px 0 0; line-height: 1.5;">
         If the button does not work, copy and paste this link:<br>
@@ -145,7 +199,47 @@ func (s *EmailService) SendProcessingCompleteEmail(to, summaryTitle string, summ
     </div>
   </div>
 </body>
-</html>`, title, viewURL, viewURL, viewURL)
+</html>`, c.subject, c.noun, title, viewURL, c.button, viewURL, viewURL)
+}
+
+func (s *EmailService) SendDataExportReadyEmail(to, fullName, jobID string) error {
+	if strings.TrimSpace(to) == "" {
+		return fmt.Errorf("recipient email is required")
+	}
+
+	name := strings.TrimSpace(fullName)
+	if name == "" {
+		name = "there"
+	}
+	name = html.EscapeString(name)
+
+	downloadURL := fmt.Sprintf("%s/settings?export=%s", s.frontendURL, jobID)
+
+	subject := "Your Lectura data export is ready"
+	body := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"></head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f8fafc;">
+  <div style="max-width: 520px; margin: 40px auto; background: white; border-radius: 12px; box-shadow: 0 4px 24px rgba(0,0,0,0.08); overflow: hidden;">
+    <div style="background: linear-gradient(135deg, #6366f1 0%%, #8b5cf6 100%%); padding: 28px 32px; text-align: center;">
+      <h1 style="color: white; margin: 0; font-size: 22px; font-weight: 700;">Lectura</h1>
+      <p style="color: rgba(255,255,255,0.9); margin: 8px 0 0; font-size: 14px;">Data export</p>
+    </div>
+    <div style="padding: 28px 32px;">
+      <h2 style="margin: 0 0 12px; font-size: 20px; color: #0f172a;">Hi %s, your export is ready</h2>
+      <p style="margin: 0 0 22px; color: #334155; font-size: 14px; line-height: 1.6;">
+        We packaged your summaries, quizzes, flashcards, study sessions and settings into a ZIP archive. Sign in to download it within %d days; after that it is deleted.
+      </p>
+      <a href="%s" style="display: inline-block; background: #6366f1; color: white; text-decoration: none; padding: 11px 24px; border-radius: 8px; font-weight: 600; font-size: 14px;">
+        Download Export
+      </a>
+      <p style="color: #94a3b8; font-size: 12px; margin: 20px 0 0; line-height: 1.5;">
+        If you did not request this export, please change your password.
+      </p>
+    </div>
+  </div>
+</body>
+</html>`, name, int(DataExportRetention.Hours()/24), downloadURL)
 
 	return s.sendHTML(to, subject, body)
 }
@@ -159,6 +253,7 @@ func (s *EmailService) SendWeeklyDigestEmail(to, fullName string, summaries, qui
 	if name == "" {
 		name = "there"
 	}
+	name = html.EscapeString(name)
 
 	subject := "Your weekly Lectura digest"
 	body := fmt.Sprintf(`<!DOCTYPE html>
@@ -201,6 +296,7 @@ func (s *EmailService) SendStudyReminderEmail(to, fullName string, lastActivityA
 	if name == "" {
 		name = "there"
 	}
+	name = html.EscapeString(name)
 
 	activityLine := "You have not studied in the last 3+ days."
 	if lastActivityAt != nil && !lastActivityAt.IsZero() {
@@ -236,6 +332,91 @@ func (s *EmailService) SendStudyReminderEmail(to, fullName string, lastActivityA
 	return s.sendHTML(to, subject, body)
 }
 
+// SendDailyBriefEmail sends the opt-in morning summary of due cards, weekly
+// goal progress, and streak.
+func (s *EmailService) SendDailyBriefEmail(to, fullName string, brief models.DailyBrief) error {
+	if strings.TrimSpace(to) == "" {
+		return fmt.Errorf("recipient email is required")
+	}
+
+	return s.sendHTML(to, "Your daily Lectura brief", renderDailyBriefEmail(fullName, brief, s.frontendURL))
+}
+
+func renderDailyBriefEmail(fullName string, brief models.DailyBrief, frontendURL string) string {
+	name := strings.TrimSpace(fullName)
+	if name == "" {
+		name = "there"
+	}
+	name = html.EscapeString(name)
+
+	dueLine := "No flashcards are due today. Nice work staying on top of your reviews."
+	if brief.CardsDue == 1 {
+		dueLine = "<strong>1</strong> flashcard is due for review today."
+	} else if brief.CardsDue > 1 {
+		dueLine = fmt.Sprintf("<strong>%d</strong> flashcards are due for review today.", brief.CardsDue)
+	}
+
+	goalNoun := weeklyGoalNoun(brief.WeeklyGoalType)
+	goalLine := fmt.Sprintf("Weekly goal reached: <strong>%d/%d</strong> %s this week.", brief.WeeklyGoalDone, brief.WeeklyGoalTarget, goalNoun)
+	if remaining := brief.GoalRemaining(); remaining > 0 {
+		goalLine = fmt.Sprintf("<strong>%d/%d</strong> %s toward your weekly goal, %d more to go.", brief.WeeklyGoalDone, brief.WeeklyGoalTarget, goalNoun, remaining)
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"></head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f8fafc;">
+  <div style="max-width: 560px; margin: 40px auto; background: white; border-radius: 12px; box-shadow: 0 4px 24px rgba(0,0,0,0.08); overflow: hidden;">
+    <div style="background: linear-gradient(135deg, #6366f1 0%%, #8b5cf6 100%%); padding: 28px 32px; text-align: center;">
+      <h1 style="color: white; margin: 0; font-size: 22px; font-weight: 700;">Lectura</h1>
+      <p style="color: rgba(255,255,255,0.9); margin: 8px 0 0; font-size: 14px;">Daily Brief</p>
+    </div>
+    <div style="padding: 28px 32px;">
+      <h2 style="margin: 0 0 12px; font-size: 20px; color: #0f172a;">Good morning, %s</h2>
+      <p style="margin: 0 0 18px; color: #334155; font-size: 14px; line-height: 1.6;">
+        Here is what is waiting for you today:
+      </p>
+      <ul style="margin: 0 0 18px; padding-left: 18px; color: #0f172a; font-size: 14px; line-height: 1.8;">
+        <li>%s</li>
+        <li>%s</li>
+      </ul>
+      <p style="margin: 0 0 20px; color: #334155; font-size: 14px; line-height: 1.6;">
+        %s
+      </p>
+      <a href="%s/dashboard" style="display: inline-block; background: #6366f1; color: white; text-decoration: none; padding: 11px 24px; border-radius: 8px; font-weight: 600; font-size: 14px;">
+        Start Studying
+      </a>
+    </div>
+  </div>
+</body>
+</html>`, name, dueLine, goalLine, streakLine(brief.Streak), frontendURL)
+}
+
+// weeklyGoalNoun names what a weekly goal type counts.
+func weeklyGoalNoun(goalType string) string {
+	switch goalType {
+	case "quiz":
+		return "quizzes"
+	case "flashcard":
+		return "flashcard decks"
+	case "presentation":
+		return "presentations"
+	default:
+		return "summaries"
+	}
+}
+
+func streakLine(streak int) string {
+	switch {
+	case streak <= 0:
+		return "Start a new streak today. Even five minutes counts."
+	case streak == 1:
+		return "You are on a 1-day streak. Study today to build on it!"
+	default:
+		return fmt.Sprintf("You are on a %d-day streak. Study today to keep it alive!", streak)
+	}
+}
+
 func (s *EmailService) sendHTML(to, subject, htmlBody string) error {
 	if s.devMode {
 		log.Printf("📧 [DEV EMAIL] To: %s | Subject: %s", to, subject)
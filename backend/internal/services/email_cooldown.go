@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Kinds of account email that share the resend cooldown logic.
+const (
+	EmailKindVerification  = "verify"
+	EmailKindPasswordReset = "password_reset"
+)
+
+// emailCooldownSteps is the wait after each send in a streak; the last step
+// repeats. A streak ends once emailCooldownStreakTTL passes without a send.
+var emailCooldownSteps = []time.Duration{60 * time.Second, 5 * time.Minute, 15 * time.Minute}
+
+const emailCooldownStreakTTL = time.Hour
+
+// emailCooldownID identifies an address in the cooldown keys. Cooldowns are
+// kept per address whether or not an account uses it, so the wait a caller
+// is told does not reveal which addresses are registered.
+func emailCooldownID(email string) string {
+	sum := sha256.Sum256([]byte(normalizeEmail(email)))
+	return hex.EncodeToString(sum[:])
+}
+
+func emailCooldownKey(kind, email string) string {
+	return fmt.Sprintf("resend_limit:%s:%s", kind, emailCooldownID(email))
+}
+
+func emailStreakKey(kind, email string) string {
+	return fmt.Sprintf("resend_streak:%s:%s", kind, emailCooldownID(email))
+}
+
+// emailCooldownStep returns the cooldown after the n-th send of a streak.
+func emailCooldownStep(n int64) time.Duration {
+	if n < 1 {
+		n = 1
+	}
+	if n > int64(len(emailCooldownSteps)) {
+		n = int64(len(emailCooldownSteps))
+	}
+	return emailCooldownSteps[n-1]
+}
+
+// cooldownMessage phrases a wait for the user, rounded up to whole units.
+func cooldownMessage(kind string, wait time.Duration) string {
+	what := "verification email"
+	if kind == EmailKindPasswordReset {
+		what = "password reset email"
+	}
+	if wait > time.Minute {
+		return fmt.Sprintf("Please wait %d minutes before requesting another %s", int(math.Ceil(wait.Minutes())), what)
+	}
+	return fmt.Sprintf("Please wait %d seconds before requesting another %s", int(math.Ceil(wait.Seconds())), what)
+}
+
+// checkEmailCooldown returns a RateLimitError while the previous request for
+// an email of this kind to the address is still cooling down.
+func checkEmailCooldown(ctx context.Context, rdb *redis.Client, kind, email string) error {
+	remaining, err := rdb.PTTL(ctx, emailCooldownKey(kind, email)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check resend rate limit: %w", err)
+	}
+	if remaining > 0 {
+		return &RateLimitError{Message: cooldownMessage(kind, remaining), RetryAfter: remaining}
+	}
+	return nil
+}
+
+// startEmailCooldown records a request and starts the next, escalated
+// cooldown for the address, returning its length.
+func startEmailCooldown(ctx context.Context, rdb *redis.Client, kind, email string) (time.Duration, error) {
+	streakKey := emailStreakKey(kind, email)
+	var sends *redis.IntCmd
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		sends = pipe.Incr(ctx, streakKey)
+		pipe.Expire(ctx, streakKey, emailCooldownStreakTTL)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record resend: %w", err)
+	}
+
+	cooldown := emailCooldownStep(sends.Val())
+	if err := rdb.Set(ctx, emailCooldownKey(kind, email), "1", cooldown).Err(); err != nil {
+		return 0, fmt.Errorf("failed to set resend rate limit: %w", err)
+	}
+	return cooldown, nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmailCooldownStep_Escalates(t *testing.T) {
+	tests := []struct {
+		sends int64
+		want  time.Duration
+	}{
+		{0, 60 * time.Second},
+		{1, 60 * time.Second},
+		{2, 5 * time.Minute},
+		{3, 15 * time.Minute},
+		{10, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := emailCooldownStep(tt.sends); got != tt.want {
+			t.Fatalf("emailCooldownStep(%d) = %s, want %s", tt.sends, got, tt.want)
+		}
+	}
+}
+
+func TestCooldownMessage(t *testing.T) {
+	if got := cooldownMessage(EmailKindVerification, 42*time.Second); got != "Please wait 42 seconds before requesting another verification email" {
+		t.Fatalf("seconds message = %q", got)
+	}
+	if got := cooldownMessage(EmailKindPasswordReset, 4*time.Minute+10*time.Second); got != "Please wait 5 minutes before requesting another password reset email" {
+		t.Fatalf("minutes message = %q", got)
+	}
+}
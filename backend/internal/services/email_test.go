@@ -0,0 +1,95 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestCompletionEmailRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		email completionEmail
+		want  []string
+	}{
+		{
+			name:  "summary keeps existing wording",
+			email: summaryCompletionEmail,
+			want:  []string{"Your summary is ready", "We finished generating your summary:", "Open Summary", "http://app.test/summary/abc"},
+		},
+		{
+			name:  "quiz",
+			email: quizCompletionEmail,
+			want:  []string{"Your quiz is ready", "We finished generating your quiz:", "Start Quiz", "http://app.test/quiz/take/abc"},
+		},
+		{
+			name:  "flashcards",
+			email: flashcardsCompletionEmail,
+			want:  []string{"Your flashcards are ready", "We finished generating your flashcards:", "Study Flashcards", "http://app.test/flashcards/study/abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.email.render("Cell <Biology>", "http://app.test"+tt.email.path+"abc")
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body missing %q", want)
+				}
+			}
+			if !strings.Contains(body, "Cell &lt;Biology&gt;") {
+				t.Errorf("expected escaped title in body")
+			}
+		})
+	}
+}
+
+func TestCompletionEmailRender_FallbackTitle(t *testing.T) {
+	body := quizCompletionEmail.render("  ", "http://app.test/quiz/take/abc")
+	if !strings.Contains(body, "Your quiz\n") {
+		t.Fatalf("expected fallback title in body")
+	}
+}
+
+func TestRenderDailyBriefEmail(t *testing.T) {
+	body := renderDailyBriefEmail("Ada <Lovelace>", models.DailyBrief{
+		CardsDue:         12,
+		WeeklyGoalType:   "quiz",
+		WeeklyGoalTarget: 5,
+		WeeklyGoalDone:   2,
+		Streak:           4,
+	}, "http://app.test")
+
+	for _, want := range []string{
+		"Ada &lt;Lovelace&gt;",
+		"<strong>12</strong> flashcards are due",
+		"<strong>2/5</strong> quizzes toward your weekly goal, 3 more to go.",
+		"4-day streak",
+		"http://app.test/dashboard",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q", want)
+		}
+	}
+}
+
+func TestRenderDailyBriefEmail_GoalReachedNoStreak(t *testing.T) {
+	body := renderDailyBriefEmail("", models.DailyBrief{
+		CardsDue:         1,
+		WeeklyGoalType:   "summary",
+		WeeklyGoalTarget: 3,
+		WeeklyGoalDone:   4,
+	}, "http://app.test")
+
+	for _, want := range []string{
+		"Good morning, there",
+		"<strong>1</strong> flashcard is due",
+		"Weekly goal reached: <strong>4/3</strong> summaries",
+		"Start a new streak today",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"lectura-backend/internal/metrics"
+)
+
+// embeddingBatchSize is the most texts one batch embedding request takes.
+const embeddingBatchSize = 100
+
+// EmbedDocuments returns one embedding per text, for storing and searching
+// against later.
+func (s *GeminiService) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	return s.embed(ctx, genai.TaskTypeRetrievalDocument, texts)
+}
+
+// EmbedQuery returns the embedding of a search query.
+func (s *GeminiService) EmbedQuery(ctx context.Context, query string) ([]float32, error) {
+	vectors, err := s.embed(ctx, genai.TaskTypeRetrievalQuery, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (s *GeminiService) embed(ctx context.Context, taskType genai.TaskType, texts []string) ([][]float32, error) {
+	if err := s.acquireRate(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseRate()
+
+	model := s.client.EmbeddingModel(s.modelConfig.EmbeddingModel)
+	model.TaskType = taskType
+
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(texts))
+		batch := model.NewBatch()
+		for _, text := range texts[start:end] {
+			batch.AddContent(genai.Text(text))
+		}
+
+		started := time.Now()
+		resp, err := model.BatchEmbedContents(ctx, batch)
+		metrics.ObserveGeminiCall("embed", time.Since(started))
+		if err != nil {
+			return nil, fmt.Errorf("Gemini embedding error: %w", err)
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("Gemini returned %d embeddings for %d texts", len(resp.Embeddings), end-start)
+		}
+		for _, e := range resp.Embeddings {
+			if e == nil || len(e.Values) == 0 {
+				return nil, fmt.Errorf("Gemini returned an empty embedding")
+			}
+			vectors = append(vectors, e.Values)
+		}
+	}
+	return vectors, nil
+}
+
+// chunkText splits text into passages of about size words, each repeating
+// the last overlap words of the one before so an idea cut at a boundary
+// still appears whole in one passage.
+func chunkText(text string, size, overlap int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []string
+	for start := 0; ; start += size - overlap {
+		end := min(start+size, len(words))
+		chunks = append(chunks, strings.Join(words[start:end], " "))
+		if end == len(words) {
+			return chunks
+		}
+	}
+}
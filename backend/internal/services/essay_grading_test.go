@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestParseEssayGrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		score    int
+		feedback string
+	}{
+		{"plain", `{"score": 72, "feedback": "Covers scattering, misses wavelength."}`, 72, "Covers scattering, misses wavelength."},
+		{"fenced with prose", "Here is the grade:\n```json\n{\"score\": 88.6, \"feedback\": \" Good. \"}\n```", 89, "Good."},
+		{"clamped high", `{"score": 140, "feedback": "x"}`, 100, "x"},
+		{"clamped low", `{"score": -5, "feedback": "x"}`, 0, "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, feedback, err := ParseEssayGrade(tt.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if score != tt.score || feedback != tt.feedback {
+				t.Fatalf("got %d %q, want %d %q", score, feedback, tt.score, tt.feedback)
+			}
+		})
+	}
+}
+
+func TestParseEssayGrade_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "I cannot grade this.", `{"feedback": "no score"}`, `{"score": "high"}`} {
+		if _, _, err := ParseEssayGrade(raw); err == nil {
+			t.Fatalf("expected an error for %q", raw)
+		}
+	}
+}
+
+func TestBuildQuizPrompt_Essay(t *testing.T) {
+	prompt := buildQuizPrompt(models.GenerateQuizRequest{NumQuestions: 3, QuestionTypes: []string{"essay"}}, "content")
+	if !strings.Contains(prompt, `ALL questions MUST be type="essay"`) || !strings.Contains(prompt, "rubric") {
+		t.Fatalf("expected essay instructions in the prompt:\n%s", prompt)
+	}
+	if prompt := buildQuizPrompt(models.GenerateQuizRequest{NumQuestions: 3, QuestionTypes: []string{"multiple_choice"}}, "content"); strings.Contains(prompt, "For essay:") {
+		t.Fatalf("expected no essay instructions without essay questions")
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/google/uuid"
+)
+
+// Explain-selection limits.
+const (
+	MaxExplainSelectionLength = 1000
+	maxExplainContext         = 30000
+)
+
+func buildExplainPrompt(summaryContent, selection string) string {
+	if len(summaryContent) > maxExplainContext {
+		summaryContent = strings.ToValidUTF8(summaryContent[:maxExplainContext], "") + "\n[...summary truncated]"
+	}
+
+	return fmt.Sprintf(`A student highlighted an excerpt of the summary below and wants it explained. Explain this excerpt in the context of the summary.
+
+Rules:
+1) Explain what the excerpt means and why it matters in 2 to 4 sentences.
+2) Connect it to the surrounding ideas of the summary; define any term the student may not know.
+3) Do not restate the excerpt word for word.
+4) Plain conversational text only. No markdown, no bullet points, no headers.
+5) Use the same language as the excerpt.
+
+Excerpt:
+"""%s"""
+
+Summary:
+%s`, selection, summaryContent)
+}
+
+// ExplainSelection explains a highlighted excerpt of a summary in the context
+// of the whole summary.
+func (s *GeminiService) ExplainSelection(ctx context.Context, summaryContent, selection string) (string, error) {
+	ctx = s.trackUsage(ctx, uuid.Nil, UsageOpChat)
+	if err := s.acquireRate(ctx); err != nil {
+		return "", err
+	}
+	defer s.releaseRate()
+
+	resp, err := generateContentWithTimeout(ctx, s.model, 30*time.Second, genai.Text(buildExplainPrompt(summaryContent, selection)))
+	if err != nil {
+		return "", fmt.Errorf("Gemini API error: %w", err)
+	}
+
+	explanation := strings.TrimSpace(extractText(resp))
+	if explanation == "" {
+		return "", fmt.Errorf("Gemini returned an empty explanation")
+	}
+	return explanation, nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildExplainPrompt_IncludesSelectionAndSummary(t *testing.T) {
+	prompt := buildExplainPrompt("Cells divide by mitosis. Mitosis has four phases.", "four phases")
+	if !strings.Contains(prompt, `"""four phases"""`) {
+		t.Fatalf("expected prompt to quote the selection")
+	}
+	if !strings.Contains(prompt, "Cells divide by mitosis.") {
+		t.Fatalf("expected prompt to include the summary")
+	}
+}
+
+func TestBuildExplainPrompt_TruncatesLongSummary(t *testing.T) {
+	prompt := buildExplainPrompt(strings.Repeat("a", maxExplainContext+500), "a")
+	if !strings.Contains(prompt, "[...summary truncated]") {
+		t.Fatalf("expected long summary to be truncated")
+	}
+	if len(prompt) > maxExplainContext+2000 {
+		t.Fatalf("prompt too long: %d bytes", len(prompt))
+	}
+}
@@ -3,22 +3,50 @@ package services
 import (
 	"archive/zip"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ledongthuc/pdf"
 )
 
+// minEmbeddedTextChars is the amount of embedded text below which a PDF is
+// treated as scanned (image-only slides, photographed handouts) and sent to OCR.
+const minEmbeddedTextChars = 80
+
+// maxOCRPages caps how many PDF pages are rasterized for OCR per document.
+const maxOCRPages = 40
+
+// ErrPDFRendererMissing is returned by RenderPDFPages when pdftoppm is not on
+// PATH, which leaves scanned PDFs without OCR.
+var ErrPDFRendererMissing = errors.New("pdftoppm not found on PATH; install poppler-utils to OCR scanned PDFs")
+
+var imageMimeTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+}
+
 type FileExtractService struct{}
 
 func NewFileExtractService() *FileExtractService {
 	return &FileExtractService{}
 }
 
+// CanRenderPDFPages reports whether RenderPDFPages can run on this machine.
+func (s *FileExtractService) CanRenderPDFPages() bool {
+	_, err := exec.LookPath("pdftoppm")
+	return err == nil
+}
+
 func (s *FileExtractService) ExtractTextFromPath(path string) (string, error) {
 	ext := strings.ToLower(filepath.Ext(path))
 
@@ -29,11 +57,77 @@ func (s *FileExtractService) ExtractTextFromPath(path string) (string, error) {
 		return s.extractPDF(path)
 	case ".docx":
 		return s.extractDOCX(path)
+	case ".srt", ".vtt":
+		return s.extractSubtitles(path)
 	default:
 		return "", fmt.Errorf("unsupported file type for text extraction: %s", ext)
 	}
 }
 
+// ImageMimeType reports the MIME type for image uploads that are routed through OCR.
+func ImageMimeType(path string) (string, bool) {
+	mimeType, ok := imageMimeTypes[strings.ToLower(filepath.Ext(path))]
+	return mimeType, ok
+}
+
+// NeedsOCR reports whether extracted text is too sparse to be the real document
+// text layer.
+func NeedsOCR(text string) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(text)) < minEmbeddedTextChars
+}
+
+// RenderPDFPages rasterizes the first maxOCRPages pages of a PDF to PNG using
+// pdftoppm (poppler-utils). Pages are returned in document order. Without
+// pdftoppm it returns ErrPDFRendererMissing.
+func (s *FileExtractService) RenderPDFPages(ctx context.Context, path string) ([][]byte, error) {
+	if !s.CanRenderPDFPages() {
+		return nil, ErrPDFRendererMissing
+	}
+
+	tmpDir, err := os.MkdirTemp("", "lectura-pdf-ocr-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{
+		"-png",
+		"-r", "150",
+		"-l", fmt.Sprintf("%d", maxOCRPages),
+		path,
+		filepath.Join(tmpDir, "page"),
+	}
+	cmd := exec.CommandContext(ctx, "pdftoppm", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return nil, fmt.Errorf("pdftoppm render failed: %s", errMsg)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(tmpDir, "page-*.png"))
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("pdftoppm produced no pages")
+	}
+
+	// pdftoppm zero-pads page numbers to a common width, so lexical order is page order.
+	sort.Strings(matches)
+
+	pages := make([][]byte, 0, len(matches))
+	for _, m := range matches {
+		b, err := os.ReadFile(m)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, b)
+	}
+
+	return pages, nil
+}
+
 func (s *FileExtractService) extractTXT(path string) (string, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
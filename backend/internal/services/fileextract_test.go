@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRenderPDFPages_MissingPdftoppm(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	s := NewFileExtractService()
+	if s.CanRenderPDFPages() {
+		t.Fatalf("expected pdftoppm to be unavailable on an empty PATH")
+	}
+	if _, err := s.RenderPDFPages(context.Background(), "scan.pdf"); !errors.Is(err, ErrPDFRendererMissing) {
+		t.Fatalf("expected ErrPDFRendererMissing, got %v", err)
+	}
+}
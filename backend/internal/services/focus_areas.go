@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"lectura-backend/internal/models"
+)
+
+type summaryFocusArea struct {
+	models.FocusArea
+	prompt string
+}
+
+// summaryFocusAreas is the allow-list of summary focus areas, in display
+// order. Each carries the instruction added to the summary prompt.
+var summaryFocusAreas = []summaryFocusArea{
+	{
+		FocusArea: models.FocusArea{ID: "definitions", Label: "Definitions", Description: "Key terms and what they mean"},
+		prompt:    "Define every key term the first time it appears and label it clearly as a definition.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "formulas", Label: "Formulas", Description: "Equations, with what each variable means"},
+		prompt:    "Reproduce every formula or equation exactly, and explain each variable and when the formula applies.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "dates", Label: "Dates & timeline", Description: "Dates and the order of events"},
+		prompt:    "Call out every date, period and sequence of events, keeping them in chronological order.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "examples", Label: "Examples", Description: "Worked examples and illustrations"},
+		prompt:    "Keep the concrete examples and worked problems from the source and label them as examples.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "key_people", Label: "Key people", Description: "People, their roles and contributions"},
+		prompt:    "Name the key people mentioned and state their role or contribution.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "processes", Label: "Processes", Description: "Step-by-step procedures and mechanisms"},
+		prompt:    "Present processes and procedures as ordered steps, noting inputs, outputs and conditions.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "comparisons", Label: "Comparisons", Description: "Similarities and differences between concepts"},
+		prompt:    "Make comparisons between concepts explicit, stating how they are alike and how they differ.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "causes_effects", Label: "Causes & effects", Description: "Why things happen and what follows"},
+		prompt:    "Highlight cause-and-effect relationships, stating what leads to what.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "statistics", Label: "Statistics", Description: "Numbers, measurements and data points"},
+		prompt:    "Preserve every statistic, measurement and quantity with its unit and context.",
+	},
+	{
+		FocusArea: models.FocusArea{ID: "exam_tips", Label: "Exam tips", Description: "Points the lecturer stressed as important"},
+		prompt:    "Flag points the speaker stresses as important, likely to be examined, or commonly misunderstood.",
+	},
+}
+
+// SummaryFocusAreas lists the supported summary focus areas.
+func SummaryFocusAreas() []models.FocusArea {
+	areas := make([]models.FocusArea, len(summaryFocusAreas))
+	for i, a := range summaryFocusAreas {
+		areas[i] = a.FocusArea
+	}
+	return areas
+}
+
+// NormalizeFocusArea maps a focus area ID or label ("Key People",
+// "key-people") to its canonical ID, reporting whether it is supported.
+func NormalizeFocusArea(value string) (string, bool) {
+	key := focusAreaKey(value)
+	if key == "" {
+		return "", false
+	}
+	for _, a := range summaryFocusAreas {
+		if key == a.ID || key == focusAreaKey(a.Label) {
+			return a.ID, true
+		}
+	}
+	return "", false
+}
+
+func focusAreaKey(value string) string {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "_")
+}
+
+// NormalizeFocusAreas canonicalizes focus areas, dropping duplicates and
+// unsupported values (e.g. free text saved before the allow-list existed).
+func NormalizeFocusAreas(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		id, ok := NormalizeFocusArea(v)
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+func focusAreaPrompt(id string) string {
+	for _, a := range summaryFocusAreas {
+		if a.ID == id {
+			return a.prompt
+		}
+	}
+	return ""
+}
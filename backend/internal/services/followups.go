@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// Follow-up suggestion limits.
+const (
+	MaxFollowupSuggestions = 3
+	MaxFollowupLength      = 120
+	maxFollowupContext     = 30000
+	maxFollowupExchange    = 2000
+)
+
+func buildFollowupPrompt(summaryContent string, lastExchange []models.ChatMessage) string {
+	if len(summaryContent) > maxFollowupContext {
+		summaryContent = summaryContent[:maxFollowupContext]
+	}
+
+	var exchange strings.Builder
+	for _, msg := range lastExchange {
+		content := msg.Content
+		if runes := []rune(content); len(runes) > maxFollowupExchange {
+			content = string(runes[:maxFollowupExchange])
+		}
+		role := "Student"
+		if msg.Role == "assistant" {
+			role = "Tutor"
+		}
+		fmt.Fprintf(&exchange, "%s: %s\n", role, content)
+	}
+	if exchange.Len() == 0 {
+		exchange.WriteString("(The student has not asked anything yet.)\n")
+	}
+
+	return fmt.Sprintf(`A student is studying the summary below with a tutor. Suggest the next questions they could ask.
+
+Rules:
+1) Return ONLY a JSON array of exactly %d strings.
+2) Each string is one short question (max 15 words) ending with "?".
+3) Every question must be answerable from the summary alone. Do not introduce concepts the summary does not mention.
+4) Build on the latest exchange without repeating what was already asked.
+5) Use the same language as the summary.
+
+Latest exchange:
+%s
+Summary:
+%s`, MaxFollowupSuggestions, exchange.String(), summaryContent)
+}
+
+// ParseFollowups decodes a model response into suggested questions, keeping
+// at most MaxFollowupSuggestions short, distinct questions that are grounded
+// in the summary content.
+func ParseFollowups(raw, summaryContent string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	raw = strings.TrimSpace(raw)
+
+	var candidates []string
+	if err := json.Unmarshal([]byte(raw), &candidates); err != nil {
+		start := strings.Index(raw, "[")
+		end := strings.LastIndex(raw, "]")
+		if start < 0 || end <= start {
+			return nil, fmt.Errorf("suggestions response is not JSON: %w", err)
+		}
+		if err := json.Unmarshal([]byte(raw[start:end+1]), &candidates); err != nil {
+			return nil, fmt.Errorf("suggestions response is not JSON: %w", err)
+		}
+	}
+
+	contentLower := strings.ToLower(summaryContent)
+	seen := make(map[string]bool, len(candidates))
+	suggestions := make([]string, 0, MaxFollowupSuggestions)
+	for _, c := range candidates {
+		q := strings.Join(strings.Fields(c), " ")
+		q = strings.TrimLeft(q, "-*• ")
+		if q == "" || len([]rune(q)) > MaxFollowupLength || !strings.HasSuffix(q, "?") {
+			continue
+		}
+		if !isOutlineTitleGrounded(q, contentLower) {
+			continue
+		}
+		key := strings.ToLower(q)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		suggestions = append(suggestions, q)
+		if len(suggestions) == MaxFollowupSuggestions {
+			break
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestParseFollowups(t *testing.T) {
+	summary := "Photosynthesis converts light energy into chemical energy inside chloroplasts. Chlorophyll absorbs light."
+	raw := "```json\n[" +
+		`"Where does photosynthesis take place?",` +
+		`"where does photosynthesis take place?",` +
+		`"Why is chlorophyll green",` +
+		`"How do black holes evaporate?",` +
+		`"` + strings.Repeat("Why does chlorophyll absorb light ", 10) + `?",` +
+		`"What does chlorophyll absorb?",` +
+		`"- How is light energy stored?",` +
+		`"Which energy does photosynthesis produce?"` +
+		"]\n```"
+
+	got, err := ParseFollowups(raw, summary)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"Where does photosynthesis take place?",
+		"What does chlorophyll absorb?",
+		"How is light energy stored?",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestParseFollowups_NotJSON(t *testing.T) {
+	if _, err := ParseFollowups("Here are some ideas", "summary"); err == nil {
+		t.Fatalf("expected an error for a non-JSON response")
+	}
+}
+
+func TestBuildFollowupPrompt_IncludesExchange(t *testing.T) {
+	prompt := buildFollowupPrompt("Cells divide by mitosis.", []models.ChatMessage{
+		{Role: "user", Content: "What is mitosis?"},
+		{Role: "assistant", Content: "Cell division."},
+	})
+	if !strings.Contains(prompt, "Student: What is mitosis?") || !strings.Contains(prompt, "Tutor: Cell division.") {
+		t.Fatalf("expected prompt to include the latest exchange")
+	}
+}
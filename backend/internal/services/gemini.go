@@ -1,6 +1,5 @@
 package services
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +22,7 @@ import (
 
 	"google.golang.org/api/option"
 
+	"lectura-backend/internal/metrics"
 	"lectura-backend/internal/models"
 	"lectura-backend/internal/repository"
 )
@@ -38,8 +39,17 @@ type GeminiService struct {
 	redis             *redis.Client
 	unsplashAccessKey string
 	httpClient        *http.Client
-	rateChan          chan struct{} // Token bucket
-	encryptionKey     string        // For decrypting user API keys
+	rate              *rateLimiter // Shared concurrency bucket
+	encryptionKey     string       // For decrypting user API keys
+	dedupThreshold    float64      // Similarity at which a quiz question counts as a repeat
+	debugLogging      bool         // Log raw model output when it fails to parse
+	usage             usageRecorder
+	// Longest transcript a summary reads; 0 uses DefaultMaxTranscriptChars
+	maxTranscriptChars int
+	// Longest transcript quoted whole in one prompt; 0 uses DefaultSummaryChunkChars
+	summaryChunkChars int
+	// Model names and sampling settings; see SetModelConfig
+	modelConfig GeminiModelConfig
 }
 
 func NewGeminiService(
@@ -61,19 +71,15 @@ func NewGeminiService(
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
 
-	model := client.GenerativeModel("gemini-3-flash-preview")
-	model.SetTemperature(0.3)
-	model.SetTopP(0.95)
-
-	// Token bucket for rate limiting
-	rateChan := make(chan struct{}, concurrentReqs)
-	for i := 0; i < concurrentReqs; i++ {
-		rateChan <- struct{}{}
-	}
+	modelConfig := DefaultGeminiModelConfig()
+	model := client.GenerativeModel(modelConfig.Model)
+	model.SetTemperature(modelConfig.Temperature)
+	model.SetTopP(modelConfig.TopP)
 
 	return &GeminiService{
 		client:            client,
 		model:             model,
+		modelConfig:       modelConfig,
 		summaryRepo:       summaryRepo,
 		presentationRepo:  presentationRepo,
 		quizRepo:          quizRepo,
@@ -83,11 +89,20 @@ func NewGeminiService(
 		redis:             redisClient,
 		unsplashAccessKey: strings.TrimSpace(unsplashAccessKey),
 		httpClient:        &http.Client{Timeout: 15 * time.Second},
-		rateChan:          rateChan,
+		rate:              newRateLimiter(concurrentReqs),
 		encryptionKey:     encryptionKey,
 	}, nil
 }
 
+// Ready reports whether the Gemini client was initialized. It makes no API
+// call, so readiness probes do not spend quota.
+func (s *GeminiService) Ready() error {
+	if s == nil || s.client == nil || s.model == nil {
+		return errors.New("Gemini client not initialized")
+	}
+	return nil
+}
+
 func (s *GeminiService) Close() {
 	if s.client != nil {
 		s.client.Close()
@@ -105,24 +120,30 @@ func (s *GeminiService) WithAPIKey(apiKey string) (*GeminiService, error) {
 		return nil, fmt.Errorf("failed to create Gemini client with user key: %w", err)
 	}
 
-	model := client.GenerativeModel("gemini-3-flash-preview")
-	model.SetTemperature(0.3)
-	model.SetTopP(0.95)
+	model := client.GenerativeModel(s.modelConfig.Model)
+	model.SetTemperature(s.modelConfig.Temperature)
+	model.SetTopP(s.modelConfig.TopP)
 
 	return &GeminiService{
-		client:            client,
-		model:             model,
-		summaryRepo:       s.summaryRepo,
-		presentationRepo:  s.presentationRepo,
-		quizRepo:          s.quizRepo,
-		flashRepo:         s.flashRepo,
-		jobRepo:           s.jobRepo,
-		userRepo:          s.userRepo,
-		redis:             s.redis,
-		unsplashAccessKey: s.unsplashAccessKey,
-		httpClient:        s.httpClient,
-		rateChan:          s.rateChan,
-		encryptionKey:     s.encryptionKey,
+		client:             client,
+		model:              model,
+		modelConfig:        s.modelConfig,
+		summaryRepo:        s.summaryRepo,
+		presentationRepo:   s.presentationRepo,
+		quizRepo:           s.quizRepo,
+		flashRepo:          s.flashRepo,
+		jobRepo:            s.jobRepo,
+		userRepo:           s.userRepo,
+		redis:              s.redis,
+		unsplashAccessKey:  s.unsplashAccessKey,
+		httpClient:         s.httpClient,
+		rate:               s.rate,
+		encryptionKey:      s.encryptionKey,
+		dedupThreshold:     s.dedupThreshold,
+		debugLogging:       s.debugLogging,
+		usage:              s.usage,
+		maxTranscriptChars: s.maxTranscriptChars,
+		summaryChunkChars:  s.summaryChunkChars,
 	}, nil
 }
 
@@ -156,18 +177,22 @@ func (s *GeminiService) ResolveForUser(ctx context.Context, userID uuid.UUID) (*
 
 // acquireRate blocks until a rate slot is available
 func (s *GeminiService) acquireRate(ctx context.Context) error {
-	select {
-	case <-s.rateChan:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(10 * time.Minute):
-		return fmt.Errorf("timeout waiting for Gemini rate slot")
-	}
+	return s.rate.acquire(ctx)
 }
 
 func (s *GeminiService) releaseRate() {
-	s.rateChan <- struct{}{}
+	s.rate.release()
+}
+
+// RateStats reports how much of the shared Gemini concurrency bucket is in use.
+func (s *GeminiService) RateStats() GeminiRateStats {
+	return s.rate.stats()
+}
+
+// ResizeRateSlots changes the number of concurrent Gemini calls at runtime.
+// The bucket is shared with per-user clones, so the change applies to all.
+func (s *GeminiService) ResizeRateSlots(slots int) error {
+	return s.rate.resize(slots)
 }
 
 func generateContentWithTimeout(
@@ -176,22 +201,39 @@ func generateContentWithTimeout(
 	timeout time.Duration,
 	parts ...genai.Part,
 ) (*genai.GenerateContentResponse, error) {
-	callCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	// The timeout applies per attempt; 429 backoff waits are bounded by ctx.
+	return withRateLimitRetry(ctx, func(ctx context.Context) (*genai.GenerateContentResponse, error) {
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
 
-	resp, err := model.GenerateContent(callCtx, parts...)
-	if err != nil {
-		if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
-			return nil, fmt.Errorf("Gemini call timed out after %s", timeout)
+		started := time.Now()
+		resp, err := model.GenerateContent(callCtx, parts...)
+		metrics.ObserveGeminiCall("generate", time.Since(started))
+		if err != nil {
+			if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("Gemini call timed out after %s", timeout)
+			}
+			return nil, err
 		}
-		return nil, err
-	}
 
-	return resp, nil
+		recordUsage(ctx, resp, parts)
+		return resp, nil
+	})
 }
 
-// PublishUpdate sends a WebSocket update via Redis pub/sub
+// PublishUpdate sends a WebSocket update via Redis pub/sub. Status updates for
+// a job tracked by JobTimings report its historical ETA in place of the
+// static per-step guess, and are also kept for LoadJobProgress.
 func (s *GeminiService) PublishUpdate(ctx context.Context, userID uuid.UUID, msg models.WSMessage) {
+	if update, ok := msg.Payload.(models.StatusUpdate); ok {
+		if remaining, ok := jobETAFrom(ctx).secondsRemaining(time.Now()); ok {
+			update.EstimatedSecondsRemaining = remaining
+			msg.Payload = update
+		}
+		if err := saveJobProgress(ctx, s.redis, update, time.Now()); err != nil {
+			log.Printf("PublishUpdate: failed to save progress of job %s: %v", update.JobID, err)
+		}
+	}
 	data, _ := json.Marshal(msg)
 	s.redis.Publish(ctx, fmt.Sprintf("user_updates:%s", userID.String()), string(data))
 }
@@ -219,7 +261,11 @@ func (s *GeminiService) uploadFileForContext(ctx context.Context, filePath, mime
 		if getErr == nil && current.State == genai.FileStateFailed {
 			return nil, fmt.Errorf("gemini failed to process uploaded document")
 		}
-		time.Sleep(2 * time.Second)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
 	}
 
 	return nil, fmt.Errorf("uploaded document did not become active in time")
@@ -227,6 +273,7 @@ func (s *GeminiService) uploadFileForContext(ctx context.Context, filePath, mime
 
 // GenerateSummary handles the full summary generation flow
 func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, transcript string, filePath string, mimeType string) error {
+	ctx = s.trackJobUsage(ctx, job, UsageOpSummary)
 	if err := s.acquireRate(ctx); err != nil {
 		return err
 	}
@@ -238,23 +285,56 @@ func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, tr
 		FocusAreas     []string `json:"focus_areas"`
 		TargetAudience string   `json:"target_audience"`
 		Language       string   `json:"language"`
+		SourceLanguage string   `json:"source_language"`
+		Chapters       []models.VideoChapter `json:"chapters"`
 		ExtractScreenText bool `json:"extract_screen_text"`
 	}
 	json.Unmarshal(job.ConfigJSON, &config)
 	metadataOnlyMode := isMetadataOnlyContent(transcript)
 
-	summaryModel := s.model
+	transcript, sourceCoverage := limitTranscript(transcript, s.transcriptLimit())
+	if sourceCoverage != nil {
+		log.Printf("Transcript for job %s exceeds %d characters; summarizing the first %d%%", job.ID, s.transcriptLimit(), *sourceCoverage)
+		if filePath != "" {
+			// The uploaded document is attached in full, so nothing is lost.
+			sourceCoverage = nil
+		}
+	}
+
+	var summaryModel *genai.GenerativeModel
+	if metadataOnlyMode {
+		summaryModel = s.generativeModel(s.modelConfig.Model, s.modelConfig.Temperature)
+		summaryModel.SetMaxOutputTokens(3072)
+	} else {
+		summaryModel = s.summaryModel(ctx, job, transcript)
+	}
+
+	// A transcript too long for one prompt is condensed part by part first;
+	// the summary prompt then reads the notes, so its length bands and format
+	// rules apply to the merged result. Later steps still quote the transcript.
+	source := transcript
+	if filePath == "" && !metadataOnlyMode {
+		if chunks := splitTranscript(transcript, s.summaryChunkLimit()); len(chunks) > 1 {
+			log.Printf("Transcript for job %s exceeds %d characters; summarizing it in %d parts", job.ID, s.summaryChunkLimit(), len(chunks))
+			merged, err := s.summarizeTranscriptChunks(ctx, job, summaryModel, chunks, config.FocusAreas)
+			if err != nil {
+				return err
+			}
+			source = merged
+		}
+	}
+
+	// Metadata stands in for a missing transcript and is not in the content's
+	// language, so there is nothing to translate.
+	sourceLanguage := config.SourceLanguage
 	if metadataOnlyMode {
-		metadataModel := s.client.GenerativeModel("gemini-3-flash-preview")
-		metadataModel.SetTemperature(0.3)
-		metadataModel.SetTopP(0.95)
-		metadataModel.SetMaxOutputTokens(3072)
-		summaryModel = metadataModel
+		sourceLanguage = ""
 	}
+	translating := needsTranslation(sourceLanguage, config.Language)
 
 	// Build layered prompt
-	prompt := buildSummaryPrompt(config.Format, config.Length, config.FocusAreas,
-		config.TargetAudience, config.Language, transcript, metadataOnlyMode, config.ExtractScreenText)
+	prompt := buildSummaryPrompt(config.Format, config.Length, config.FocusAreas, config.Chapters,
+		config.TargetAudience, config.Language, sourceLanguage, source, metadataOnlyMode, config.ExtractScreenText)
 
 	// Publish status update
 	s.PublishUpdate(ctx, job.UserID, models.WSMessage{
@@ -278,11 +358,21 @@ func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, tr
 		parts = []genai.Part{genai.Text(prompt)}
 	}
 
-	// Call Gemini
-	resp, err := generateContentWithTimeout(ctx, summaryModel, 10*time.Minute, parts...)
+	// Call Gemini, streaming the draft to the client as partial_content
+	// messages. The full text is post-processed below, so the saved summary
+	// can differ from what was streamed.
+	partial := newPartialContentBatcher(job.ID, func(p models.PartialContent) {
+		s.PublishUpdate(ctx, job.UserID, models.WSMessage{Type: "partial_content", Payload: p})
+	})
+	resp, err := streamContentWithTimeout(ctx, summaryModel, 10*time.Minute, partial.add, parts...)
 	if err != nil {
+		if reason := safetyBlockReason(nil, err); reason != "" {
+			log.Printf("Gemini blocked summary for job %s: %s", job.ID, reason)
+			return safetyBlockedError(reason)
+		}
 		return fmt.Errorf("Gemini API error: %w", err)
 	}
+	partial.flush()
 
 	// Debug logging for Gemini response
 	for i, cand := range resp.Candidates {
@@ -296,8 +386,12 @@ func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, tr
 	isQualityFallback := false
 	var qualityFallbackReason *string
 	if rawText == "" {
+		if reason := safetyBlockReason(resp, nil); reason != "" {
+			log.Printf("Gemini blocked summary for job %s: %s", job.ID, reason)
+			return safetyBlockedError(reason)
+		}
 		log.Println("WARNING: Gemini returned empty text. Using fallback.")
-		rawText = "We could not generate a summary for this content. The transcript was likely unavailable or the content was blocked by safety filters."
+		rawText = "We could not generate a summary for this content. The transcript was likely unavailable."
 		isQualityFallback = true
 		reason := "gemini_empty_response"
 		qualityFallbackReason = &reason
@@ -311,6 +405,13 @@ func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, tr
 		}
 	}
 
+	// The prompt asks for the preset's word band but models drift; verify it
+	// and allow one corrective pass. Fallback text has no band to meet.
+	lengthCorrected := false
+	if !isQualityFallback && config.Format != "mindmap" {
+		rawText, lengthCorrected = s.correctSummaryLength(ctx, summaryModel, config.Format, config.Length, rawText, transcript)
+	}
+
 	if config.Format == "smart" && !hasValidSmartSummaryTable(rawText) {
 		isQualityFallback = true
 		if qualityFallbackReason == nil {
@@ -345,7 +446,7 @@ func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, tr
 		}
 		if !metadataOnlyMode {
 			log.Println("INFO: Running Smart summary fidelity rewrite")
-			rawText = s.rewriteSmartSummaryForFidelity(ctx, rawText, transcript)
+			rawText = s.rewriteSmartSummaryForFidelity(ctx, rawText, transcript, translating)
 			if !hasValidSmartSummaryTable(rawText) {
 				rawText = ensureSmartSummaryTable(rawText)
 				log.Println("INFO: Smart summary table restored after fidelity rewrite")
@@ -355,14 +456,14 @@ func (s *GeminiService) GenerateSummary(ctx context.Context, job *models.Job, tr
 		// Safety net: detect missing "Summary of Video Content" and auto-generate it
 		if !strings.Contains(strings.ToLower(rawText), "summary of video content") {
 			log.Println("WARNING: Smart summary missing 'Summary of Video Content' section — auto-generating")
-			excerpt := rawText[:min(len(rawText), 3000)]
+			excerpt := clipText(rawText, summaryOverviewExcerptChars)
 			microPrompt := fmt.Sprintf(`Read the following summary and write ONE concise narrative paragraph (3-5 sentences) that summarizes the overall topic and main points of the video. Return ONLY the paragraph text, no headings, no markdown.
 
 Summary:
 %s`, excerpt)
 			microCtx, microCancel := context.WithTimeout(ctx, 60*time.Second)
 			defer microCancel()
-			microResp, microErr := s.model.GenerateContent(microCtx, genai.Text(microPrompt))
+			microResp, microErr := generateContent(microCtx, s.model, genai.Text(microPrompt))
 			if microErr == nil {
 				paragraph := strings.TrimSpace(extractText(microResp))
 				if paragraph != "" {
@@ -389,6 +490,34 @@ Summary:
 		}
 	}
 
+	// A mind map is kept as its tree, which the outline endpoint serves, and
+	// as nested bullets for everything that reads the summary as text.
+	var mindMap *models.OutlineNode
+	if config.Format == "mindmap" && rawText != "" {
+		// A translated tree cannot be matched word for word against the
+		// transcript, so like a file or metadata summary it grounds in itself.
+		grounding := source
+		if filePath != "" || metadataOnlyMode || translating {
+			grounding = rawText
+		}
+		tree, fromBullets, err := mindMapFromResponse(rawText, grounding)
+		switch {
+		case err != nil:
+			log.Printf("WARNING: Mind map summary for job %s is not a tree: %v", job.ID, err)
+			isQualityFallback = true
+			if qualityFallbackReason == nil {
+				reason := "mindmap_structure_fallback"
+				qualityFallbackReason = &reason
+			}
+		default:
+			if fromBullets {
+				log.Printf("INFO: Mind map summary for job %s built from bullets", job.ID)
+			}
+			mindMap = tree
+			rawText = renderMindMapMarkdown(tree)
+		}
+	}
+
 	// Parse Cornell if applicable
 	var cues, notes, summaryText *string
 	if config.Format == "cornell" {
@@ -418,7 +547,7 @@ Summary:
 				"- The [SUMMARY] section must synthesize — do not paraphrase the Notes section.\n" +
 				"- Write the Summary as if explaining to someone who has not read the Notes.\n\n" +
 				"Text to restructure:\n" + rawText
-			resp2, err := summaryModel.GenerateContent(ctx, genai.Text(restructurePrompt))
+			resp2, err := generateContent(ctx, summaryModel, genai.Text(restructurePrompt))
 			if err == nil {
 				rawText2 := extractText(resp2)
 				if strings.TrimSpace(rawText2) != "" {
@@ -488,7 +617,7 @@ Example format:
 Summary:
 %s`, excerpt)
 
-		resp, err := s.model.GenerateContent(followUpCtx, genai.Text(followUpPrompt))
+		resp, err := generateContent(followUpCtx, s.model, genai.Text(followUpPrompt))
 		if err != nil {
 			log.Printf("follow-up questions generation failed for summary %s: %v", job.ReferenceID, err)
 			return
@@ -524,7 +653,7 @@ Summary:
 		}
 
 		questions = valid
-	}(rawText[:min(len(rawText), 4000)])
+	}(clipText(rawText, summaryFollowUpExcerptChars))
 
 	metaCh := make(chan metaResult, 1)
 	go func(summaryExcerpt string) {
@@ -558,7 +687,7 @@ Rules:
 Summary:
 %s`, summaryExcerpt)
 
-		metaResp, err := s.model.GenerateContent(metaCtx, genai.Text(metaPrompt))
+		metaResp, err := generateContent(metaCtx, s.model, genai.Text(metaPrompt))
 		if err == nil {
 			metaJSON := extractText(metaResp)
 			metaJSON = strings.TrimPrefix(metaJSON, "```json")
@@ -587,7 +716,7 @@ Summary:
 		} else {
 			log.Printf("metadata generation failed for summary %s: %v", job.ReferenceID, err)
 		}
-	}(rawText[:min(len(rawText), 6000)])
+	}(clipText(rawText, summaryMetadataExcerptChars))
 
 	// Count words while metadata call runs concurrently
 	wordCount := len(strings.Fields(rawText))
@@ -622,11 +751,24 @@ Summary:
 		wordCount,
 		isQualityFallback,
 		qualityFallbackReason,
+		lengthCorrected,
+		sourceCoverage,
 	)
 	if err != nil {
 		return err
 	}
 
+	if mindMap != nil {
+		if mindMap.Title == "" {
+			mindMap.Title = title
+		}
+		if outline, err := json.Marshal(mindMap); err == nil {
+			if err := s.summaryRepo.SaveOutline(ctx, job.ReferenceID, outline); err != nil {
+				log.Printf("failed to save mind map for summary %s: %v", job.ReferenceID, err)
+			}
+		}
+	}
+
 	if len(followUpQuestions) > 0 {
 		if err := s.summaryRepo.UpdateFollowUpQuestions(ctx, job.ReferenceID, followUpQuestions); err != nil {
 			log.Printf("failed to save follow-up questions for summary %s: %v", job.ReferenceID, err)
@@ -642,6 +784,7 @@ Summary:
 }
 
 func (s *GeminiService) GeneratePresentation(ctx context.Context, job *models.Job, transcript string, filePath string, mimeType string) error {
+	ctx = s.trackJobUsage(ctx, job, UsageOpPresentation)
 	if err := s.acquireRate(ctx); err != nil {
 		return err
 	}
@@ -665,9 +808,7 @@ func (s *GeminiService) GeneratePresentation(ctx context.Context, job *models.Jo
 		config.FocusAreas = []string{}
 	}
 
-	presentationModel := s.client.GenerativeModel("gemini-3-flash-preview")
-	presentationModel.SetTemperature(0.6)
-	presentationModel.SetTopP(0.95)
+	presentationModel := s.generativeModel(s.modelConfig.Model, 0.6)
 
 	s.PublishUpdate(ctx, job.UserID, models.WSMessage{
 		Type: "status_update",
@@ -747,74 +888,16 @@ func (s *GeminiService) GeneratePresentation(ctx context.Context, job *models.Jo
 	return nil
 }
 
-// TranscribeAudio uses Gemini File API to transcribe uploaded audio bytes.
-func (s *GeminiService) TranscribeAudio(ctx context.Context, audio []byte, mimeType string) (string, error) {
-	if err := s.acquireRate(ctx); err != nil {
-		return "", err
-	}
-	defer s.releaseRate()
-
-	if len(audio) == 0 {
-		return "", fmt.Errorf("audio payload is empty")
-	}
-
-	file, err := s.client.UploadFile(ctx, "", bytes.NewReader(audio), &genai.UploadFileOptions{
-		DisplayName: "youtube-audio",
-		MIMEType:    mimeType,
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to upload audio to Gemini: %w", err)
-	}
-
-	// Ensure remote file is cleaned up
-	defer s.client.DeleteFile(context.Background(), file.Name)
-
-	// Wait until file is active
-	for i := 0; i < 20; i++ {
-		current, getErr := s.client.GetFile(ctx, file.Name)
-		if getErr != nil {
-			return "", fmt.Errorf("failed to get uploaded file status: %w", getErr)
-		}
-
-		if current.State == genai.FileStateActive {
-			file = current
-			break
-		}
-		if current.State == genai.FileStateFailed {
-			return "", fmt.Errorf("Gemini failed to process uploaded audio file")
-		}
-
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(2 * time.Second):
-		}
-	}
-
-	if file.State != genai.FileStateActive {
-		return "", fmt.Errorf("audio file did not become active in time")
-	}
-
-	prompt := "Transcribe the provided audio verbatim. Return plain text only, without markdown, headers, or explanations."
-
-	resp, err := s.model.GenerateContent(ctx,
-		genai.Text(prompt),
-		genai.FileData{MIMEType: mimeType, URI: file.URI},
-	)
-	if err != nil {
-		return "", fmt.Errorf("Gemini transcription error: %w", err)
-	}
-
-	text := strings.TrimSpace(extractText(resp))
-	if text == "" {
-		return "", fmt.Errorf("Gemini returned empty transcription")
-	}
-
-	return text, nil
-}
+// ErrNoValidQuestions and ErrNoValidCards mean every item the model returned
+// failed validation. Retrying the same prompt is not expected to help.
+var (
+	ErrNoValidQuestions = errors.New("quiz generation produced zero valid questions")
+	ErrNoValidCards     = errors.New("flashcard generation produced zero valid cards")
+)
 
 // GenerateQuiz handles quiz generation
 func (s *GeminiService) GenerateQuiz(ctx context.Context, job *models.Job, summaryContent string) error {
+	ctx = s.trackJobUsage(ctx, job, UsageOpQuiz)
 	if err := s.acquireRate(ctx); err != nil {
 		return err
 	}
@@ -823,7 +906,11 @@ func (s *GeminiService) GenerateQuiz(ctx context.Context, job *models.Job, summa
 	var config models.GenerateQuizRequest
 	json.Unmarshal(job.ConfigJSON, &config)
 
-	prompt := buildQuizPrompt(config, summaryContent)
+	target := ClampQuizQuestions(config.NumQuestions)
+	history := s.loadQuestionHistory(ctx, config.SummaryID)
+	deduper := newQuestionDeduper(history, quizDedupThreshold(s.dedupThreshold, config.Fresh))
+
+	prompt := buildQuizPrompt(config, summaryContent) + buildQuizAvoidSection(history, config.Fresh)
 
 	s.PublishUpdate(ctx, job.UserID, models.WSMessage{
 		Type: "status_update",
@@ -833,31 +920,48 @@ func (s *GeminiService) GenerateQuiz(ctx context.Context, job *models.Job, summa
 		},
 	})
 
-	resp, err := generateContentWithTimeout(ctx, s.model, 10*time.Minute, genai.Text(prompt))
+	candidates, err := s.generateQuizCandidates(ctx, prompt, config)
 	if err != nil {
-		return fmt.Errorf("Gemini API error: %w", err)
+		return err
+	}
+	if len(candidates) == 0 {
+		return ErrNoValidQuestions
 	}
 
-	rawText := extractText(resp)
-	rawText = strings.TrimPrefix(rawText, "```json")
-	rawText = strings.TrimPrefix(rawText, "```")
-	rawText = strings.TrimSuffix(rawText, "```")
-	rawText = strings.TrimSpace(rawText)
+	// Drop repeats of earlier quizzes on this summary and ask for
+	// replacements until the target count is reached. With a difficulty mix,
+	// short difficulties are topped up as well.
+	mix := quizDifficultyMix(config)
+	validQuestions, rejected := deduper.filter(candidates)
+	validQuestions = trimQuizToMix(validQuestions, mix)
+	for round := 0; (rejected > 0 || mix != nil) && len(validQuestions) < target && round < maxQuizReplacementRounds; round++ {
+		need := target - len(validQuestions)
+		replacementConfig := config
+		replacementConfig.NumQuestions = need
+		if mix != nil {
+			replacementConfig.DifficultyMix = missingQuizMix(validQuestions, mix)
+		}
 
-	var questions []models.QuizQuestion
-	if err := json.Unmarshal([]byte(rawText), &questions); err != nil {
-		// Try to extract JSON array
-		start := strings.Index(rawText, "[")
-		end := strings.LastIndex(rawText, "]")
-		if start >= 0 && end > start {
-			json.Unmarshal([]byte(rawText[start:end+1]), &questions)
+		avoid := append(questionTexts(validQuestions), history...)
+		replacementPrompt := buildQuizPrompt(replacementConfig, summaryContent) + buildQuizAvoidSection(avoid, true)
+
+		more, err := s.generateQuizCandidates(ctx, replacementPrompt, replacementConfig)
+		if err != nil {
+			log.Printf("GenerateQuiz: replacement round %d for job %s failed: %v", round+1, job.ID, err)
+			break
 		}
+		var kept []models.QuizQuestion
+		kept, rejected = deduper.filter(more)
+		validQuestions = trimQuizToMix(append(validQuestions, kept...), mix)
 	}
 
-	// Validate + enforce config constraints
-	validQuestions := validateQuizQuestions(questions, config)
 	if len(validQuestions) == 0 {
-		return fmt.Errorf("quiz generation produced zero valid questions")
+		// Every question repeated an earlier one; a familiar quiz beats none.
+		log.Printf("GenerateQuiz: no novel questions left for summary %s, reusing generated ones", config.SummaryID)
+		validQuestions = trimQuizToMix(candidates, mix)
+	}
+	if len(validQuestions) > target {
+		validQuestions = validQuestions[:target]
 	}
 	questionsJSON, _ := json.Marshal(validQuestions)
 
@@ -865,6 +969,13 @@ func (s *GeminiService) GenerateQuiz(ctx context.Context, job *models.Job, summa
 		return err
 	}
 
+	if config.SummaryID != uuid.Nil {
+		hashes, texts := questionHistoryEntries(validQuestions)
+		if err := s.quizRepo.RecordQuestionHistory(ctx, config.SummaryID, hashes, texts); err != nil {
+			log.Printf("GenerateQuiz: failed to record question history for summary %s: %v", config.SummaryID, err)
+		}
+	}
+
 	s.PublishUpdate(ctx, job.UserID, models.WSMessage{
 		Type: "completed",
 		Payload: models.CompletedEvent{
@@ -877,8 +988,102 @@ func (s *GeminiService) GenerateQuiz(ctx context.Context, job *models.Job, summa
 	return nil
 }
 
+// generateQuizCandidates runs one quiz prompt and returns the questions that
+// pass validation, without truncating to the requested count.
+func (s *GeminiService) generateQuizCandidates(ctx context.Context, prompt string, config models.GenerateQuizRequest) ([]models.QuizQuestion, error) {
+	var questions []models.QuizQuestion
+	if err := s.generateJSONArray(ctx, "GenerateQuiz", prompt, &questions); err != nil {
+		return nil, err
+	}
+
+	// Validate + enforce config constraints; the caller trims to the target
+	// after deduplication.
+	uncapped := config
+	uncapped.NumQuestions = 0
+	return validateQuizQuestions(questions, uncapped), nil
+}
+
+// loadQuestionHistory returns questions previously generated for a summary,
+// newest first. Failures only disable deduplication.
+func (s *GeminiService) loadQuestionHistory(ctx context.Context, summaryID uuid.UUID) []string {
+	if summaryID == uuid.Nil || s.quizRepo == nil {
+		return nil
+	}
+	history, err := s.quizRepo.ListQuestionHistory(ctx, summaryID, maxQuizHistoryQuestions)
+	if err != nil {
+		log.Printf("GenerateQuiz: failed to load question history for summary %s: %v", summaryID, err)
+		return nil
+	}
+	return history
+}
+
+// SetDebugLogging enables logging of raw model output that fails to parse.
+// It can contain user content, so it is off unless LOG_LEVEL=debug.
+func (s *GeminiService) SetDebugLogging(enabled bool) {
+	s.debugLogging = enabled
+}
+
+func (s *GeminiService) debugf(format string, args ...any) {
+	if s.debugLogging {
+		log.Printf("DEBUG: "+format, args...)
+	}
+}
+
+// jsonOnlyReminder is appended to the prompt when the first reply could not
+// be parsed.
+const jsonOnlyReminder = "\n\nIMPORTANT: Your previous reply was not valid JSON. Return ONLY a valid JSON array: no markdown fences, no commentary, no trailing commas, and make sure every string and bracket is closed."
+
+// maxDebugPayloadBytes caps how much raw model output a debug log line holds.
+const maxDebugPayloadBytes = 8 << 10
+
+// generateJSONArray runs prompt and decodes the JSON array in the reply into
+// v, repairing malformed output where possible. If the reply still cannot be
+// parsed, the model is asked once more to return only valid JSON.
+func (s *GeminiService) generateJSONArray(ctx context.Context, label, prompt string, v any) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		p := prompt
+		if attempt > 0 {
+			p += jsonOnlyReminder
+		}
+		resp, err := generateContentWithTimeout(ctx, s.model, 10*time.Minute, genai.Text(p))
+		if err != nil {
+			return fmt.Errorf("Gemini API error: %w", err)
+		}
+
+		raw := extractText(resp)
+		parseErr := decodeJSONArray(raw, v)
+		if parseErr == nil {
+			return nil
+		}
+		if len(raw) > maxDebugPayloadBytes {
+			raw = raw[:maxDebugPayloadBytes] + "…(truncated)"
+		}
+		s.debugf("%s: unparseable model output (attempt %d): %s", label, attempt+1, raw)
+		if attempt > 0 {
+			return fmt.Errorf("Gemini returned malformed JSON twice: %w", parseErr)
+		}
+		log.Printf("%s: model returned malformed JSON (%v); retrying with a JSON-only reminder", label, parseErr)
+	}
+	return nil
+}
+
+// SetQuizDedupThreshold sets the similarity (0-1] at or above which a newly
+// generated question counts as a repeat of an earlier one.
+func (s *GeminiService) SetQuizDedupThreshold(threshold float64) {
+	s.dedupThreshold = threshold
+}
+
+func questionTexts(questions []models.QuizQuestion) []string {
+	texts := make([]string, 0, len(questions))
+	for _, q := range questions {
+		texts = append(texts, q.Question)
+	}
+	return texts
+}
+
 // GenerateFlashcards handles flashcard generation
 func (s *GeminiService) GenerateFlashcards(ctx context.Context, job *models.Job, summaryContent string) error {
+	ctx = s.trackJobUsage(ctx, job, UsageOpFlashcards)
 	if err := s.acquireRate(ctx); err != nil {
 		return err
 	}
@@ -897,17 +1102,6 @@ func (s *GeminiService) GenerateFlashcards(ctx context.Context, job *models.Job,
 		},
 	})
 
-	resp, err := generateContentWithTimeout(ctx, s.model, 10*time.Minute, genai.Text(prompt))
-	if err != nil {
-		return fmt.Errorf("Gemini API error: %w", err)
-	}
-
-	rawText := extractText(resp)
-	rawText = strings.TrimPrefix(rawText, "```json")
-	rawText = strings.TrimPrefix(rawText, "```")
-	rawText = strings.TrimSuffix(rawText, "```")
-	rawText = strings.TrimSpace(rawText)
-
 	type cardJSON struct {
 		Front      string  `json:"front"`
 		Back       string  `json:"back"`
@@ -918,12 +1112,8 @@ func (s *GeminiService) GenerateFlashcards(ctx context.Context, job *models.Job,
 	}
 
 	var cards []cardJSON
-	if err := json.Unmarshal([]byte(rawText), &cards); err != nil {
-		start := strings.Index(rawText, "[")
-		end := strings.LastIndex(rawText, "]")
-		if start >= 0 && end > start {
-			json.Unmarshal([]byte(rawText[start:end+1]), &cards)
-		}
+	if err := s.generateJSONArray(ctx, "GenerateFlashcards", prompt, &cards); err != nil {
+		return err
 	}
 
 	// Convert to model cards
@@ -944,7 +1134,7 @@ func (s *GeminiService) GenerateFlashcards(ctx context.Context, job *models.Job,
 
 	validCards := validateFlashcardCards(modelCards, config)
 	if len(validCards) == 0 {
-		return fmt.Errorf("flashcard generation produced zero valid cards")
+		return ErrNoValidCards
 	}
 
 	if err := s.flashRepo.CreateCards(ctx, job.ReferenceID, validCards); err != nil {
@@ -2054,8 +2244,12 @@ func removeWeakExampleLines(text string) string {
 	return strings.TrimSpace(cleaned)
 }
 
-func (s *GeminiService) rewriteSmartSummaryForFidelity(ctx context.Context, summaryText, transcript string) string {
-	snippet := transcript[:min(len(transcript), 6000)]
+func (s *GeminiService) rewriteSmartSummaryForFidelity(ctx context.Context, summaryText, transcript string, translated bool) string {
+	snippet, snippetNote := transcriptEvidence(transcript)
+	languageRule := ""
+	if translated {
+		languageRule = "12) The summary is a translation of the transcript. Keep it in its current language; match transcript terms by meaning and keep the original terms given in parentheses.\n"
+	}
 	prompt := fmt.Sprintf(`You are revising a Smart Summary for strict factual fidelity.
 
 Rules:
@@ -2073,14 +2267,14 @@ Rules:
 9) For "Additional Interesting Facts", output 3-6 markdown bullets (each line starts with '- '). Do NOT output that section as a paragraph.
 10) Keep markdown format and keep at least one markdown table.
 11) Return markdown only.
-
-Transcript excerpt:
 %s
+Transcript excerpt:
+%s%s
 
 Current summary:
-%s`, snippet, summaryText)
+%s`, languageRule, snippetNote, snippet, summaryText)
 
-	resp, err := s.model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := generateContent(ctx, s.model, genai.Text(prompt))
 	if err != nil {
 		return summaryText
 	}
@@ -2093,7 +2287,7 @@ Current summary:
 	return text
 }
 
-func buildSummaryPrompt(format, length string, focusAreas []string, audience, language, transcript string, metadataOnlyMode bool, extractScreenText bool) string {
+func buildSummaryPrompt(format, length string, focusAreas []string, chapters []models.VideoChapter, audience, language, sourceLanguage, transcript string, metadataOnlyMode bool, extractScreenText bool) string {
 	var b strings.Builder
 
 	// Layer 1 — Role
@@ -2232,77 +2426,40 @@ func buildSummaryPrompt(format, length string, focusAreas []string, audience, la
 		b.WriteString("If transcript evidence is weak, explicitly mark uncertainty instead of fabricating details.\n")
 		b.WriteString("Markdown structure rule for Additional Interesting Facts: this section MUST be a markdown unordered list ('- item'). Do NOT write it as a paragraph.\n")
 		b.WriteString("FINAL OUTPUT RULE: Do NOT wrap the output in code fences (``` or ```markdown). Output raw markdown only. Do NOT add trailing ``` at the end.\n\n")
+	case "mindmap":
+		writeMindMapFormat(&b, length)
 	}
+	// The lecturer's own chapters shape the smart and bullets layouts.
+	writeChapterOutline(&b, format, chapters)
 
-	// Layer 3 — Length (strict bands, adjusted per format)
-	sourceWords := len(strings.Fields(transcript))
-	var targetPercent int
-	var minWords int
-	var maxWords int
-	var lengthLabel string
-	switch length {
-	case "concise":
-		targetPercent = 15
-		minWords = 120
-		maxWords = 220
-		lengthLabel = "Short"
-	case "standard":
-		targetPercent = 25
-		minWords = 260
-		maxWords = 420
-		lengthLabel = "Medium"
-	case "detailed":
-		targetPercent = 40
-		minWords = 500
-		maxWords = 850
-		lengthLabel = "Long"
-	case "comprehensive":
-		targetPercent = 55
-		minWords = 900
-		maxWords = 1600
-		lengthLabel = "Deep Dive"
-	default:
-		targetPercent = 25
-		minWords = 260
-		maxWords = 420
-		lengthLabel = "Medium"
-	}
+	// Layer 3 — Length (strict bands, adjusted per format). A mind map's size
+	// is set by its node budget instead.
+	if format != "mindmap" {
+		sourceWords := len(strings.Fields(transcript))
+		band := summaryLengthBandFor(format, length)
+		targetPercent, minWords, maxWords, lengthLabel := band.TargetPercent, band.MinWords, band.MaxWords, band.Label
 
-	// Format-specific multiplier: multi-section formats need more words
-	// to fill all required sections (Cornell: Cues+Notes+Summary, Smart: Summary+Insights+Table+Facts)
-	var formatMultiplier float64
-	switch format {
-	case "cornell":
-		formatMultiplier = 1.8 // 3 mandatory sections with structured content
-	case "smart":
-		formatMultiplier = 1.6 // 4 mandatory sections including a table
-	case "bullets":
-		formatMultiplier = 1.1 // structured bullets add some overhead
-	default:
-		formatMultiplier = 1.0 // paragraph stays as-is
-	}
-
-	minWords = int(float64(minWords) * formatMultiplier)
-	maxWords = int(float64(maxWords) * formatMultiplier)
+		targetWords := sourceWords * targetPercent / 100
+		if targetWords < minWords {
+			targetWords = minWords
+		}
+		if targetWords > maxWords {
+			targetWords = maxWords
+		}
 
-	targetWords := sourceWords * targetPercent / 100
-	if targetWords < minWords {
-		targetWords = minWords
-	}
-	if targetWords > maxWords {
-		targetWords = maxWords
+		b.WriteString(fmt.Sprintf("Length preset: %s.\n", lengthLabel))
+		b.WriteString(fmt.Sprintf("CRITICAL LENGTH CONSTRAINT: Output MUST be between %d and %d words.\n", minWords, maxWords))
+		b.WriteString(fmt.Sprintf("Target about %d words (%d%% of %d source words, clamped to preset range).\n", targetWords, targetPercent, sourceWords))
+		b.WriteString(fmt.Sprintf("UNDER NO CIRCUMSTANCES should your output exceed %d words. Cut non-essential details to fit.\n\n", maxWords))
 	}
 
-	b.WriteString(fmt.Sprintf("Length preset: %s.\n", lengthLabel))
-	b.WriteString(fmt.Sprintf("CRITICAL LENGTH CONSTRAINT: Output MUST be between %d and %d words.\n", minWords, maxWords))
-	b.WriteString(fmt.Sprintf("Target about %d words (%d%% of %d source words, clamped to preset range).\n", targetWords, targetPercent, sourceWords))
-	b.WriteString(fmt.Sprintf("UNDER NO CIRCUMSTANCES should your output exceed %d words. Cut non-essential details to fit.\n\n", maxWords))
-
-	// Layer 4 — Focus areas
-	for _, area := range focusAreas {
-		b.WriteString(fmt.Sprintf("Priority: Prioritize and clearly label all %s.\n", area))
+	// Layer 4 — Focus areas (unsupported values from old job configs are skipped)
+	focusWritten := false
+	for _, area := range NormalizeFocusAreas(focusAreas) {
+		b.WriteString("Priority: " + focusAreaPrompt(area) + "\n")
+		focusWritten = true
 	}
-	if len(focusAreas) > 0 {
+	if focusWritten {
 		b.WriteString("\n")
 	}
 
@@ -2312,9 +2469,7 @@ func buildSummaryPrompt(format, length string, focusAreas []string, audience, la
 	}
 
 	// Layer 6 — Language
-	if language != "" && language != "en" {
-		b.WriteString(fmt.Sprintf("Language: Respond entirely in %s.\n\n", language))
-	}
+	b.WriteString(summaryLanguageInstruction(language, sourceLanguage))
 
 	// Layer 7 — Transcript
 	b.WriteString("---TRANSCRIPT START---\n")
@@ -6204,55 +6359,32 @@ func buildQuizPrompt(config models.GenerateQuizRequest, content string) string {
 	b.WriteString("You are an expert educational assessor. Generate quiz questions based on the following content.\n\n")
 	b.WriteString("CRITICAL: Return ONLY a valid JSON array. No preamble, no markdown, no backticks.\n\n")
 
-	b.WriteString(fmt.Sprintf("Generate exactly %d questions.\n", config.NumQuestions))
+	b.WriteString(fmt.Sprintf("Generate exactly %d questions.\n", ClampQuizQuestions(config.NumQuestions)))
 
-	allowedTypes := make([]string, 0, 2)
-	hasMC := false
-	hasTF := false
+	allowedTypes := make([]string, 0, 3)
 	for _, qt := range config.QuestionTypes {
-		switch strings.ToLower(strings.TrimSpace(qt)) {
-		case "multiple_choice":
-			if !hasMC {
-				hasMC = true
-				allowedTypes = append(allowedTypes, "multiple_choice")
-			}
-		case "true_false":
-			if !hasTF {
-				hasTF = true
-				allowedTypes = append(allowedTypes, "true_false")
-			}
+		if n := normalizeQuestionType(qt); n != "" && !slices.Contains(allowedTypes, n) {
+			allowedTypes = append(allowedTypes, n)
 		}
 	}
 	if len(allowedTypes) == 0 {
 		allowedTypes = []string{"multiple_choice", "true_false"}
-		hasMC = true
-		hasTF = true
 	}
 
-	switch {
-	case hasTF && !hasMC:
-		b.WriteString("Question type rule: ALL questions MUST be type=\"true_false\".\n")
-		b.WriteString("Do NOT output any multiple_choice question.\n")
-	case hasMC && !hasTF:
-		b.WriteString("Question type rule: ALL questions MUST be type=\"multiple_choice\".\n")
-		b.WriteString("Do NOT output any true_false question.\n")
-	default:
-		b.WriteString("Question type rule: Use both multiple_choice and true_false questions with balanced distribution.\n")
+	if len(allowedTypes) == 1 {
+		b.WriteString(fmt.Sprintf("Question type rule: ALL questions MUST be type=\"%s\".\n", allowedTypes[0]))
+		b.WriteString("Do NOT output any other question type.\n")
+	} else {
+		b.WriteString("Question type rule: Use only these question types, with balanced distribution: " + strings.Join(allowedTypes, ", ") + ".\n")
 	}
+	hasEssay := slices.Contains(allowedTypes, "essay")
+	hasFillBlank := slices.Contains(allowedTypes, "fill_blank")
+	hasMatching := slices.Contains(allowedTypes, "matching")
 
-	b.WriteString(fmt.Sprintf("Difficulty: %s\n", config.Difficulty))
-
-	switch config.Difficulty {
-	case "easy":
-		b.WriteString("Easy = direct recall from explicit statements in the source.\n")
-		b.WriteString("Avoid multi-step inference, ambiguity, or trick wording.\n")
-	case "medium":
-		b.WriteString("Medium = basic application and comparison of concepts from the source.\n")
-	case "hard":
-		b.WriteString("Hard = deep analytical questions requiring synthesis across multiple parts of the content.\n")
-		b.WriteString("Use nuanced distinctions, implications, edge cases, and strong distractors.\n")
+	writeQuizDifficulty(&b, config)
+	if config.EnableHints {
+		b.WriteString("Give every question a short hint that nudges toward the answer without stating it or naming the correct option.\n")
 	}
-	b.WriteString("Set every item's difficulty field exactly to this requested difficulty value.\n")
 	if config.ExtractScreenText {
 		b.WriteString("Use any reliable on-screen text evidence available in the source context (slides, diagrams, labels, formulas) in addition to spoken transcript content.\n")
 	}
@@ -6269,13 +6401,38 @@ func buildQuizPrompt(config models.GenerateQuizRequest, content string) string {
 		b.WriteString("Allowed topics: " + strings.Join(cleanTopics, ", ") + "\n")
 	}
 
+	// Only the fields of the requested types are asked for.
+	typeFields := ""
+	if hasEssay {
+		typeFields += `, "rubric": "string"`
+	}
+	if hasFillBlank {
+		typeFields += `, "accepted_answers": ["string"]`
+	}
+	if hasMatching {
+		typeFields += `, "pairs": [{"prompt": "string", "answer": "string"}]`
+	}
+	b.WriteString("\nJSON schema per question:\n")
+	b.WriteString(`{"question": "string", "type": "` + strings.Join(allowedTypes, `"|"`) + `", "options": ["string"], "correct_index": int, "explanation": "string", "hint": "string", "difficulty": "easy"|"medium"|"hard", "topic": "string"` + typeFields + "}\n")
 	b.WriteString(`
-JSON schema per question:
-{"question": "string", "type": "multiple_choice"|"true_false", "options": ["string"], "correct_index": int, "explanation": "string", "hint": "string", "difficulty": "easy"|"medium"|"hard", "topic": "string"}
-
 For multiple_choice: exactly 4 options. For true_false: exactly 2 options ["True", "False"].
 For true_false: correct_index must be 0 or 1.
 `)
+	if hasEssay {
+		b.WriteString(`For essay: an open question answerable in one or two paragraphs that tests understanding, not recall. Set options to [] and correct_index to 0.
+Give every essay a rubric listing the 3-5 points a full-credit answer covers, so answers can be graded against it. Use the explanation for a model answer.
+`)
+	}
+	if hasFillBlank {
+		b.WriteString(`For fill_blank: a sentence from the content with one key term replaced by "_____". Set options to [] and correct_index to 0.
+List the missing term in accepted_answers, followed by any equally correct spellings or synonyms (at most 5). The answer must be a word or short phrase.
+`)
+	}
+	if hasMatching {
+		b.WriteString(fmt.Sprintf(`For matching: the question says what to match (e.g. "Match each term to its definition"). Set options to [] and correct_index to 0.
+Give %d-%d pairs, each a short prompt and the answer it matches. Every prompt and every answer must be distinct, so each prompt has exactly one right match.
+`, minMatchingPairs, maxMatchingPairs))
+	}
 
 	b.WriteString("\n---CONTENT---\n")
 	b.WriteString(content)
@@ -6289,7 +6446,7 @@ func buildFlashcardPrompt(config models.GenerateFlashcardsRequest, content strin
 
 	b.WriteString("You are an expert flashcard creator. Generate high-quality flashcards from the content below.\n\n")
 	b.WriteString("CRITICAL: Return ONLY a valid JSON array. No preamble, no markdown, no backticks.\n\n")
-	b.WriteString(fmt.Sprintf("Generate exactly %d flashcards.\n\n", config.NumCards))
+	b.WriteString(fmt.Sprintf("Generate exactly %d flashcards.\n\n", ClampFlashcards(config.NumCards)))
 
 	strategy := strings.ToLower(strings.TrimSpace(config.Strategy))
 	switch strategy {
@@ -6386,6 +6543,9 @@ func validateFlashcardCards(cards []models.FlashcardCard, config models.Generate
 	if limit <= 0 {
 		limit = len(cards)
 	}
+	if limit > MaxFlashcards {
+		limit = MaxFlashcards
+	}
 
 	valid := make([]models.FlashcardCard, 0, limit)
 	for _, c := range cards {
@@ -6477,6 +6637,8 @@ func validateQuizQuestions(questions []models.QuizQuestion, config models.Genera
 	if targetDifficulty != "easy" && targetDifficulty != "medium" && targetDifficulty != "hard" {
 		targetDifficulty = "medium"
 	}
+	mix := quizDifficultyMix(config)
+	mixCounts := map[string]int{}
 
 	allowedTypes := map[string]bool{}
 	for _, qt := range config.QuestionTypes {
@@ -6510,6 +6672,9 @@ func validateQuizQuestions(questions []models.QuizQuestion, config models.Genera
 	if limit <= 0 {
 		limit = len(questions)
 	}
+	if limit > MaxQuizQuestions {
+		limit = MaxQuizQuestions
+	}
 
 	for _, q := range questions {
 		if len(valid) >= limit {
@@ -6523,7 +6688,13 @@ func validateQuizQuestions(questions []models.QuizQuestion, config models.Genera
 
 		normalizedType := normalizeQuestionType(q.Type)
 		if normalizedType == "" {
-			if isTrueFalseOptions(q.Options) {
+			if len(q.Pairs) > 0 {
+				normalizedType = "matching"
+			} else if len(q.Options) == 0 && len(q.AcceptedAnswers) > 0 {
+				normalizedType = "fill_blank"
+			} else if len(q.Options) == 0 && strings.TrimSpace(q.Rubric) != "" {
+				normalizedType = "essay"
+			} else if isTrueFalseOptions(q.Options) {
 				normalizedType = "true_false"
 			} else {
 				normalizedType = "multiple_choice"
@@ -6534,7 +6705,40 @@ func validateQuizQuestions(questions []models.QuizQuestion, config models.Genera
 			continue
 		}
 
-		if normalizedType == "true_false" {
+		if normalizedType != "fill_blank" {
+			q.AcceptedAnswers = nil
+		}
+		if normalizedType != "matching" {
+			q.Pairs = nil
+		}
+
+		if normalizedType == "essay" {
+			// Graded against the rubric, so one without a rubric is unusable.
+			q.Rubric = strings.TrimSpace(q.Rubric)
+			if q.Rubric == "" {
+				continue
+			}
+			q.Options = []string{}
+			q.CorrectIndex = 0
+		} else if normalizedType == "fill_blank" {
+			q.Rubric = ""
+			q.AcceptedAnswers = cleanAcceptedAnswers(q.AcceptedAnswers)
+			if len(q.AcceptedAnswers) == 0 {
+				continue
+			}
+			q.Options = []string{}
+			q.CorrectIndex = 0
+		} else if normalizedType == "matching" {
+			q.Rubric = ""
+			pairs, ok := cleanMatchingPairs(q.Pairs)
+			if !ok {
+				continue
+			}
+			q.Pairs = pairs
+			q.Options = []string{}
+			q.CorrectIndex = 0
+		} else if normalizedType == "true_false" {
+			q.Rubric = ""
 			if !isTrueFalseOptions(q.Options) {
 				continue
 			}
@@ -6551,6 +6755,7 @@ func validateQuizQuestions(questions []models.QuizQuestion, config models.Genera
 			}
 			q.Options = []string{"True", "False"}
 		} else {
+			q.Rubric = ""
 			if len(q.Options) < 4 {
 				continue
 			}
@@ -6563,7 +6768,21 @@ func validateQuizQuestions(questions []models.QuizQuestion, config models.Genera
 		}
 
 		q.Type = normalizedType
-		q.Difficulty = targetDifficulty
+		if mix != nil {
+			// Keep the model's label so questions can be bucketed, capping
+			// each difficulty at its requested count when trimming.
+			difficulty := strings.ToLower(strings.TrimSpace(q.Difficulty))
+			if mix[difficulty] == 0 {
+				continue
+			}
+			if config.NumQuestions > 0 && mixCounts[difficulty] >= mix[difficulty] {
+				continue
+			}
+			mixCounts[difficulty]++
+			q.Difficulty = difficulty
+		} else {
+			q.Difficulty = targetDifficulty
+		}
 
 		if len(originalTopics) > 0 {
 			topic := strings.TrimSpace(q.Topic)
@@ -6587,6 +6806,12 @@ func normalizeQuestionType(v string) string {
 		return "multiple_choice"
 	case "true_false", "true-false", "truefalse", "boolean":
 		return "true_false"
+	case "essay", "open_ended", "open-ended", "openended":
+		return "essay"
+	case "fill_blank", "fill-blank", "fill_in_the_blank", "fill-in-the-blank", "fillblank", "cloze":
+		return "fill_blank"
+	case "matching", "match":
+		return "matching"
 	default:
 		return ""
 	}
@@ -6603,15 +6828,14 @@ func isTrueFalseOptions(options []string) bool {
 
 // ChatWithSummary sends a user question to Gemini with summary content as context.
 func (s *GeminiService) ChatWithSummary(ctx context.Context, summaryContent, userMessage string, history []models.ChatMessage) (string, error) {
+	ctx = s.trackUsage(ctx, uuid.Nil, UsageOpChat)
 	if err := s.acquireRate(ctx); err != nil {
 		return "", err
 	}
 	defer s.releaseRate()
 
 	// Create a chat-specific model instance with a system instruction
-	chatModel := s.client.GenerativeModel("gemini-3-flash-preview")
-	chatModel.SetTemperature(0.4)
-	chatModel.SetTopP(0.95)
+	chatModel := s.generativeModel(s.modelConfig.Model, 0.4)
 
 	// Truncate summary if very long to stay within token limits
 	maxContext := 30000
@@ -6654,11 +6878,21 @@ SUMMARY CONTENT:
 		})
 	}
 
+	// Everything sent counts toward the prompt estimate.
+	promptParts := append([]genai.Part{}, chatModel.SystemInstruction.Parts...)
+	for _, content := range chat.History {
+		promptParts = append(promptParts, content.Parts...)
+	}
+	promptParts = append(promptParts, genai.Text(userMessage))
+
 	// Send the new message
+	started := time.Now()
 	resp, err := chat.SendMessage(ctx, genai.Text(userMessage))
+	metrics.ObserveGeminiCall("chat", time.Since(started))
 	if err != nil {
 		return "", fmt.Errorf("Gemini chat error: %w", err)
 	}
+	recordUsage(ctx, resp, promptParts)
 
 	reply := strings.TrimSpace(extractText(resp))
 	if reply == "" {
@@ -6668,6 +6902,43 @@ SUMMARY CONTENT:
 	return reply, nil
 }
 
+// SuggestFollowups proposes up to MaxFollowupSuggestions short questions the
+// student could ask next, answerable from the summary. lastExchange may be
+// empty at the start of a conversation.
+func (s *GeminiService) SuggestFollowups(ctx context.Context, summaryContent string, lastExchange []models.ChatMessage) ([]string, error) {
+	ctx = s.trackUsage(ctx, uuid.Nil, UsageOpChat)
+	if err := s.acquireRate(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseRate()
+
+	resp, err := generateContentWithTimeout(ctx, s.model, 20*time.Second, genai.Text(buildFollowupPrompt(summaryContent, lastExchange)))
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API error: %w", err)
+	}
+
+	return ParseFollowups(extractText(resp), summaryContent)
+}
+
+// GenerateOutline builds a validated mind-map outline of summary content.
+func (s *GeminiService) GenerateOutline(ctx context.Context, content, title string) (*models.OutlineNode, error) {
+	if err := s.acquireRate(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseRate()
+
+	resp, err := generateContentWithTimeout(ctx, s.model, 2*time.Minute, genai.Text(buildOutlinePrompt(content)))
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API error: %w", err)
+	}
+
+	root, err := ParseOutline(extractText(resp))
+	if err != nil {
+		return nil, err
+	}
+	return NormalizeOutline(root, content, title)
+}
+
 // OCRImage extracts on-screen text from an image.
 // Returns plain text only (no markdown).
 func (s *GeminiService) OCRImage(ctx context.Context, imageBytes []byte, mimeType string) (string, error) {
@@ -6676,8 +6947,7 @@ func (s *GeminiService) OCRImage(ctx context.Context, imageBytes []byte, mimeTyp
 	}
 	defer s.releaseRate()
 
-	visionModel := s.client.GenerativeModel("gemini-3-flash-preview")
-	visionModel.SetTemperature(0.1)
+	visionModel := s.generativeModel(s.modelConfig.Model, 0.1)
 	visionModel.SetTopP(0.9)
 	visionModel.SetMaxOutputTokens(2048)
 
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+
+	"lectura-backend/internal/metrics"
+)
+
+// Gemini 429 backoff limits. Quota errors usually clear within a minute, so
+// they are retried here with longer waits than the worker's generic backoff.
+const (
+	maxGeminiRateLimitRetries = 3
+	geminiRateLimitBaseDelay  = 5 * time.Second
+	geminiRateLimitMaxDelay   = 90 * time.Second
+)
+
+// geminiRetryDelayPattern matches the retry hint Gemini puts in quota errors,
+// either as a RetryInfo detail ("retryDelay": "37s") or in the message
+// ("Please retry in 37.5s").
+var geminiRetryDelayPattern = regexp.MustCompile(`(?i)retry(?:delay"?\s*:\s*"?|\s+in\s+)(\d+(?:\.\d+)?)s`)
+
+// rateLimitWait sleeps between 429 retries; tests replace it.
+var rateLimitWait = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isGeminiRateLimited reports whether err is a quota/429 error from Gemini.
+func isGeminiRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "RESOURCE_EXHAUSTED") ||
+		strings.Contains(msg, "ResourceExhausted") ||
+		strings.Contains(msg, "Error 429")
+}
+
+// geminiRetryAfter extracts the server's suggested wait from a 429, or 0 when
+// it gave none.
+func geminiRetryAfter(err error) time.Duration {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		if header := strings.TrimSpace(apiErr.Header.Get("Retry-After")); header != "" {
+			if seconds, convErr := strconv.Atoi(header); convErr == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			if at, parseErr := http.ParseTime(header); parseErr == nil {
+				if d := time.Until(at); d > 0 {
+					return d
+				}
+			}
+		}
+		if m := geminiRetryDelayPattern.FindStringSubmatch(apiErr.Body); m != nil {
+			return parseRetrySeconds(m[1])
+		}
+	}
+	if m := geminiRetryDelayPattern.FindStringSubmatch(err.Error()); m != nil {
+		return parseRetrySeconds(m[1])
+	}
+	return 0
+}
+
+func parseRetrySeconds(s string) time.Duration {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// geminiRateLimitDelay is the wait before 429 retry number attempt (0-based).
+// A server hint is honoured; otherwise the delay doubles from the base. Both
+// get random jitter so workers that were throttled together do not retry in
+// lockstep.
+func geminiRateLimitDelay(attempt int, retryAfter time.Duration) time.Duration {
+	var delay time.Duration
+	if retryAfter > 0 {
+		// Never retry before the hint; spread the herd over a quarter of it.
+		delay = retryAfter + rand.N(retryAfter/4+time.Second)
+	} else {
+		if attempt > 5 {
+			attempt = 5
+		}
+		backoff := geminiRateLimitBaseDelay << uint(attempt)
+		delay = backoff/2 + rand.N(backoff/2+1)
+	}
+	if delay > geminiRateLimitMaxDelay {
+		delay = geminiRateLimitMaxDelay
+	}
+	return delay
+}
+
+// withRateLimitRetry runs call, retrying Gemini 429s with a jittered backoff
+// for as long as ctx allows. Other errors are returned immediately.
+func withRateLimitRetry(ctx context.Context, call func(context.Context) (*genai.GenerateContentResponse, error)) (*genai.GenerateContentResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := call(ctx)
+		if err == nil || !isGeminiRateLimited(err) {
+			return resp, err
+		}
+		if attempt >= maxGeminiRateLimitRetries {
+			return nil, fmt.Errorf("Gemini rate limit persisted after %d retries: %w", attempt, err)
+		}
+
+		delay := geminiRateLimitDelay(attempt, geminiRetryAfter(err))
+		log.Printf("Gemini rate limited (retry %d/%d in %s): %v", attempt+1, maxGeminiRateLimitRetries, delay.Round(time.Millisecond), err)
+		if waitErr := rateLimitWait(ctx, delay); waitErr != nil {
+			return nil, fmt.Errorf("gave up waiting out Gemini rate limit: %w", err)
+		}
+	}
+}
+
+// generateContent calls the model, backing off on 429s.
+func generateContent(ctx context.Context, model *genai.GenerativeModel, parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	return withRateLimitRetry(ctx, func(ctx context.Context) (*genai.GenerateContentResponse, error) {
+		started := time.Now()
+		resp, err := model.GenerateContent(ctx, parts...)
+		metrics.ObserveGeminiCall("generate", time.Since(started))
+		if err == nil {
+			recordUsage(ctx, resp, parts)
+		}
+		return resp, err
+	})
+}
+
+// streamContentWithTimeout is generateContentWithTimeout for a streamed
+// reply: onText receives each piece of text as it arrives and the merged
+// response is returned at the end. A 429 is only retried before any text has
+// been handed to onText, so callers never see the reply start over.
+func streamContentWithTimeout(
+	ctx context.Context,
+	model *genai.GenerativeModel,
+	timeout time.Duration,
+	onText func(string),
+	parts ...genai.Part,
+) (*genai.GenerateContentResponse, error) {
+	return withRateLimitRetry(ctx, func(ctx context.Context) (*genai.GenerateContentResponse, error) {
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		started := time.Now()
+		defer func() { metrics.ObserveGeminiCall("stream", time.Since(started)) }()
+
+		iter := model.GenerateContentStream(callCtx, parts...)
+		delivered := false
+		for {
+			resp, err := iter.Next()
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			if err != nil {
+				if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+					return nil, fmt.Errorf("Gemini call timed out after %s", timeout)
+				}
+				if delivered && isGeminiRateLimited(err) {
+					return nil, fmt.Errorf("Gemini stream interrupted: %v", err)
+				}
+				return nil, err
+			}
+			if text := extractText(resp); text != "" {
+				onText(text)
+				delivered = true
+			}
+		}
+
+		merged := iter.MergedResponse()
+		if merged == nil {
+			merged = &genai.GenerateContentResponse{}
+		}
+		recordUsage(ctx, merged, parts)
+		return merged, nil
+	})
+}
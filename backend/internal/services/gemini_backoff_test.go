@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsGeminiRateLimited(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "googleapi 429", err: fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusTooManyRequests}), want: true},
+		{name: "googleapi 500", err: &googleapi.Error{Code: http.StatusInternalServerError}, want: false},
+		{name: "grpc status", err: errors.New("rpc error: code = ResourceExhausted desc = quota exceeded"), want: true},
+		{name: "status name", err: errors.New(`{"status": "RESOURCE_EXHAUSTED"}`), want: true},
+		{name: "timeout", err: errors.New("Gemini call timed out after 10m0s"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGeminiRateLimited(tt.err); got != tt.want {
+				t.Fatalf("isGeminiRateLimited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiRetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "12")
+
+	tests := []struct {
+		name string
+		err  error
+		want time.Duration
+	}{
+		{name: "header", err: &googleapi.Error{Code: 429, Header: header}, want: 12 * time.Second},
+		{name: "retry info body", err: &googleapi.Error{Code: 429, Body: `{"details":[{"retryDelay": "37s"}]}`}, want: 37 * time.Second},
+		{name: "message hint", err: errors.New("Error 429: quota exceeded. Please retry in 2.5s."), want: 2500 * time.Millisecond},
+		{name: "no hint", err: errors.New("Error 429: quota exceeded"), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := geminiRetryAfter(tt.err); got != tt.want {
+				t.Fatalf("geminiRetryAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiRateLimitDelay(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := geminiRateLimitDelay(0, 0); d < geminiRateLimitBaseDelay/2 || d > geminiRateLimitBaseDelay {
+			t.Fatalf("first backoff %s outside [%s, %s]", d, geminiRateLimitBaseDelay/2, geminiRateLimitBaseDelay)
+		}
+		if d := geminiRateLimitDelay(2, 0); d < 2*geminiRateLimitBaseDelay || d > 4*geminiRateLimitBaseDelay {
+			t.Fatalf("third backoff %s outside [%s, %s]", d, 2*geminiRateLimitBaseDelay, 4*geminiRateLimitBaseDelay)
+		}
+		if d := geminiRateLimitDelay(0, 20*time.Second); d < 20*time.Second || d > 26*time.Second {
+			t.Fatalf("hinted backoff %s outside [20s, 26s]", d)
+		}
+		if d := geminiRateLimitDelay(10, 10*time.Minute); d != geminiRateLimitMaxDelay {
+			t.Fatalf("capped backoff = %s, want %s", d, geminiRateLimitMaxDelay)
+		}
+	}
+}
+
+func stubRateLimitWait(t *testing.T, wait func(context.Context, time.Duration) error) {
+	t.Helper()
+	original := rateLimitWait
+	rateLimitWait = wait
+	t.Cleanup(func() { rateLimitWait = original })
+}
+
+func TestWithRateLimitRetry(t *testing.T) {
+	quotaErr := &googleapi.Error{Code: http.StatusTooManyRequests, Message: "quota exceeded"}
+
+	t.Run("recovers after 429s", func(t *testing.T) {
+		var waits []time.Duration
+		stubRateLimitWait(t, func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		})
+
+		calls := 0
+		want := &genai.GenerateContentResponse{}
+		resp, err := withRateLimitRetry(context.Background(), func(context.Context) (*genai.GenerateContentResponse, error) {
+			calls++
+			if calls < 3 {
+				return nil, quotaErr
+			}
+			return want, nil
+		})
+		if err != nil || resp != want {
+			t.Fatalf("withRateLimitRetry() = %v, %v; want response", resp, err)
+		}
+		if calls != 3 || len(waits) != 2 {
+			t.Fatalf("calls = %d, waits = %d; want 3 and 2", calls, len(waits))
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		stubRateLimitWait(t, func(context.Context, time.Duration) error { return nil })
+
+		calls := 0
+		_, err := withRateLimitRetry(context.Background(), func(context.Context) (*genai.GenerateContentResponse, error) {
+			calls++
+			return nil, quotaErr
+		})
+		if calls != maxGeminiRateLimitRetries+1 {
+			t.Fatalf("calls = %d, want %d", calls, maxGeminiRateLimitRetries+1)
+		}
+		if !errors.Is(err, quotaErr) || !strings.Contains(err.Error(), "rate limit persisted") {
+			t.Fatalf("err = %v, want wrapped quota error", err)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		stubRateLimitWait(t, func(context.Context, time.Duration) error {
+			t.Fatal("unexpected backoff")
+			return nil
+		})
+
+		calls := 0
+		boom := errors.New("boom")
+		_, err := withRateLimitRetry(context.Background(), func(context.Context) (*genai.GenerateContentResponse, error) {
+			calls++
+			return nil, boom
+		})
+		if calls != 1 || !errors.Is(err, boom) {
+			t.Fatalf("calls = %d, err = %v; want 1 and boom", calls, err)
+		}
+	})
+
+	t.Run("stops when the job is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stubRateLimitWait(t, func(ctx context.Context, _ time.Duration) error {
+			cancel()
+			return ctx.Err()
+		})
+
+		calls := 0
+		_, err := withRateLimitRetry(ctx, func(context.Context) (*genai.GenerateContentResponse, error) {
+			calls++
+			return nil, quotaErr
+		})
+		if calls != 1 || !errors.Is(err, quotaErr) {
+			t.Fatalf("calls = %d, err = %v; want 1 and quota error", calls, err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"lectura-backend/internal/models"
+)
+
+// DefaultGeminiModel is the model used when none is configured.
+const DefaultGeminiModel = "gemini-3-flash-preview"
+
+// DefaultEmbeddingModel is the embedding model used when none is configured.
+// Stored summary chunks are 768-dimensional, so any replacement must produce
+// vectors of that size.
+const DefaultEmbeddingModel = "text-embedding-004"
+
+// knownGeminiModels are the model names accepted in configuration, so that a
+// typo fails at startup rather than on the first job. Add new releases here.
+var knownGeminiModels = []string{
+	"gemini-2.0-flash",
+	"gemini-2.0-flash-lite",
+	"gemini-2.5-flash",
+	"gemini-2.5-flash-lite",
+	"gemini-2.5-pro",
+	"gemini-3-flash-preview",
+	"gemini-3-pro-preview",
+	"text-embedding-004",
+}
+
+// GeminiModelConfig chooses the model behind every generation and its
+// sampling settings. Summaries switch to ProModel, when set, for transcripts
+// of at least ProMinTranscriptChars characters (0 disables the length rule)
+// or for users on one of ProPlans. EmbeddingModel embeds summary chunks and
+// search queries.
+type GeminiModelConfig struct {
+	Model       string
+	Temperature float32
+	TopP        float32
+
+	ProModel              string
+	ProMinTranscriptChars int
+	ProPlans              []string
+
+	EmbeddingModel string
+}
+
+// DefaultGeminiModelConfig is the flash model with the sampling settings
+// summaries have always used.
+func DefaultGeminiModelConfig() GeminiModelConfig {
+	return GeminiModelConfig{Model: DefaultGeminiModel, Temperature: 0.3, TopP: 0.95, EmbeddingModel: DefaultEmbeddingModel}
+}
+
+// Validate rejects unknown model names and out-of-range sampling settings.
+func (c GeminiModelConfig) Validate() error {
+	if !isKnownGeminiModel(c.Model) {
+		return fmt.Errorf("unknown Gemini model %q (known: %s)", c.Model, strings.Join(knownGeminiModels, ", "))
+	}
+	if c.ProModel != "" && !isKnownGeminiModel(c.ProModel) {
+		return fmt.Errorf("unknown Gemini pro model %q (known: %s)", c.ProModel, strings.Join(knownGeminiModels, ", "))
+	}
+	if !isKnownGeminiModel(c.EmbeddingModel) {
+		return fmt.Errorf("unknown Gemini embedding model %q (known: %s)", c.EmbeddingModel, strings.Join(knownGeminiModels, ", "))
+	}
+	if c.Temperature < 0 || c.Temperature > 2 {
+		return fmt.Errorf("Gemini temperature must be between 0 and 2, got %g", c.Temperature)
+	}
+	if c.TopP <= 0 || c.TopP > 1 {
+		return fmt.Errorf("Gemini top-p must be above 0 and at most 1, got %g", c.TopP)
+	}
+	if c.ProMinTranscriptChars < 0 {
+		return fmt.Errorf("Gemini pro model transcript threshold must not be negative, got %d", c.ProMinTranscriptChars)
+	}
+	return nil
+}
+
+func isKnownGeminiModel(name string) bool {
+	for _, known := range knownGeminiModels {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// summaryModelName picks the model for a summary of a transcript this long
+// requested by a user on plan.
+func (c GeminiModelConfig) summaryModelName(transcriptChars int, plan string) string {
+	if c.ProModel == "" {
+		return c.Model
+	}
+	if c.ProMinTranscriptChars > 0 && transcriptChars >= c.ProMinTranscriptChars {
+		return c.ProModel
+	}
+	for _, p := range c.ProPlans {
+		if strings.EqualFold(strings.TrimSpace(p), plan) {
+			return c.ProModel
+		}
+	}
+	return c.Model
+}
+
+// SetModelConfig validates cfg and switches the service to it. Per-user
+// clones made afterwards inherit it.
+func (s *GeminiService) SetModelConfig(cfg GeminiModelConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.modelConfig = cfg
+	s.model = s.generativeModel(cfg.Model, cfg.Temperature)
+	return nil
+}
+
+// generativeModel returns the named model with the configured top-p and the
+// given temperature; callers tune other settings on the result.
+func (s *GeminiService) generativeModel(name string, temperature float32) *genai.GenerativeModel {
+	model := s.client.GenerativeModel(name)
+	model.SetTemperature(temperature)
+	model.SetTopP(s.modelConfig.TopP)
+	return model
+}
+
+// summaryModel is the model a summary job runs on. It is the shared model
+// unless the transcript length or the owner's plan calls for the pro model.
+func (s *GeminiService) summaryModel(ctx context.Context, job *models.Job, transcript string) *genai.GenerativeModel {
+	cfg := s.modelConfig
+	if cfg.ProModel == "" {
+		return s.model
+	}
+
+	plan := ""
+	if len(cfg.ProPlans) > 0 && s.userRepo != nil {
+		if user, err := s.userRepo.GetByID(ctx, job.UserID); err == nil {
+			plan = user.Plan
+		}
+	}
+	name := cfg.summaryModelName(utf8.RuneCountInString(transcript), plan)
+	if name == cfg.Model {
+		return s.model
+	}
+	return s.generativeModel(name, cfg.Temperature)
+}
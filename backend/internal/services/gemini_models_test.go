@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeminiModelConfig_Validate(t *testing.T) {
+	if err := DefaultGeminiModelConfig().Validate(); err != nil {
+		t.Fatalf("default config should be valid: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(*GeminiModelConfig)
+		wantErr string
+	}{
+		{"typo in model", func(c *GeminiModelConfig) { c.Model = "gemini-3-flahs-preview" }, "unknown Gemini model"},
+		{"typo in pro model", func(c *GeminiModelConfig) { c.ProModel = "gemini-2.5-por" }, "unknown Gemini pro model"},
+		{"typo in embedding model", func(c *GeminiModelConfig) { c.EmbeddingModel = "text-embeding-004" }, "unknown Gemini embedding model"},
+		{"temperature too high", func(c *GeminiModelConfig) { c.Temperature = 2.5 }, "temperature"},
+		{"top-p zero", func(c *GeminiModelConfig) { c.TopP = 0 }, "top-p"},
+		{"negative threshold", func(c *GeminiModelConfig) { c.ProMinTranscriptChars = -1 }, "threshold"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultGeminiModelConfig()
+			tt.edit(&cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeminiModelConfig_SummaryModelName(t *testing.T) {
+	cfg := DefaultGeminiModelConfig()
+	cfg.ProModel = "gemini-2.5-pro"
+	cfg.ProMinTranscriptChars = 50000
+	cfg.ProPlans = []string{"ultra"}
+
+	tests := []struct {
+		name  string
+		chars int
+		plan  string
+		want  string
+	}{
+		{"short transcript on free plan", 1000, "free", DefaultGeminiModel},
+		{"long transcript", 50000, "free", "gemini-2.5-pro"},
+		{"listed plan", 1000, "Ultra", "gemini-2.5-pro"},
+	}
+	for _, tt := range tests {
+		if got := cfg.summaryModelName(tt.chars, tt.plan); got != tt.want {
+			t.Errorf("%s: summaryModelName(%d, %q) = %q, want %q", tt.name, tt.chars, tt.plan, got, tt.want)
+		}
+	}
+
+	cfg.ProModel = ""
+	if got := cfg.summaryModelName(1_000_000, "ultra"); got != DefaultGeminiModel {
+		t.Fatalf("without a pro model every summary should use %s, got %s", DefaultGeminiModel, got)
+	}
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestGenerateSummary_DeadlineAbortsSlotWait(t *testing.T) {
+	s := &GeminiService{rate: newRateLimiter(1)}
+	if err := s.acquireRate(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer s.releaseRate()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err := s.GenerateSummary(ctx, &models.Job{ID: uuid.New(), UserID: uuid.New()}, "transcript", "", "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GenerateSummary = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("GenerateSummary took %s to notice the deadline", elapsed)
+	}
+	if got := s.RateStats(); got.InUse != 1 || got.Waiting != 0 {
+		t.Fatalf("aborted call should leave only the held slot in use: %+v", got)
+	}
+}
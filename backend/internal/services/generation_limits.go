@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+// Generation limits shared by handlers (request validation), the worker
+// (job config validation) and prompt builders (defensive clamping).
+const (
+	MinQuizQuestions = 1
+	MaxQuizQuestions = 50
+
+	MinFlashcards = 1
+	MaxFlashcards = 100
+
+	MaxMergeDecks = 20
+
+	MaxTitleLength  = 200
+	MaxTopics       = 20
+	MaxFocusAreas   = 10
+	MaxTopicLength  = 100
+	MaxAudienceText = 200
+)
+
+var (
+	AllowedSummaryFormats      = []string{"paragraph", "bullets", "cornell", "smart", "mindmap"}
+	AllowedSummaryLengths      = []string{"concise", "standard", "detailed", "comprehensive"}
+	AllowedQuizDifficulties    = []string{"easy", "medium", "hard"}
+	AllowedFlashcardStrategies = []string{"term_definition", "question_answer"}
+)
+
+func isAllowedValue(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// ClampQuizQuestions bounds a requested question count to the supported range.
+func ClampQuizQuestions(n int) int {
+	return clampInt(n, MinQuizQuestions, MaxQuizQuestions)
+}
+
+// ClampFlashcards bounds a requested card count to the supported range.
+func ClampFlashcards(n int) int {
+	return clampInt(n, MinFlashcards, MaxFlashcards)
+}
+
+func clampInt(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
+
+func validateTextList(fields map[string]string, key string, values []string, maxItems int) {
+	if len(values) > maxItems {
+		fields[key] = fmt.Sprintf("At most %d entries are allowed", maxItems)
+		return
+	}
+	for _, v := range values {
+		if len([]rune(strings.TrimSpace(v))) > MaxTopicLength {
+			fields[key] = fmt.Sprintf("Each entry must be at most %d characters", MaxTopicLength)
+			return
+		}
+	}
+}
+
+// ValidateSummaryConfig returns field errors for a summary generation request.
+// Empty format/length are allowed and fall back to prompt defaults.
+func ValidateSummaryConfig(req models.GenerateSummaryRequest) map[string]string {
+	fields := make(map[string]string)
+
+	if req.ContentID == uuid.Nil {
+		fields["content_id"] = "content_id is required"
+	}
+	if req.Format != "" && !isAllowedValue(req.Format, AllowedSummaryFormats) {
+		fields["format"] = "format must be one of: " + strings.Join(AllowedSummaryFormats, ", ")
+	}
+	if req.Length != "" && !isAllowedValue(req.Length, AllowedSummaryLengths) {
+		fields["length"] = "length must be one of: " + strings.Join(AllowedSummaryLengths, ", ")
+	}
+	if len([]rune(req.TargetAudience)) > MaxAudienceText {
+		fields["target_audience"] = fmt.Sprintf("target_audience must be at most %d characters", MaxAudienceText)
+	}
+	ValidateFocusAreas(fields, req.FocusAreas)
+
+	return fields
+}
+
+// ValidateFocusAreas records a field error when focus areas exceed the limit
+// or include a value outside the supported taxonomy.
+func ValidateFocusAreas(fields map[string]string, values []string) {
+	if len(values) > MaxFocusAreas {
+		fields["focus_areas"] = fmt.Sprintf("At most %d entries are allowed", MaxFocusAreas)
+		return
+	}
+	for _, v := range values {
+		if _, ok := NormalizeFocusArea(v); !ok {
+			fields["focus_areas"] = fmt.Sprintf("unsupported focus area: %q", v)
+			return
+		}
+	}
+}
+
+// ValidateQuizConfig returns field errors for a quiz generation request.
+func ValidateQuizConfig(req models.GenerateQuizRequest) map[string]string {
+	fields := make(map[string]string)
+
+	if req.SummaryID == uuid.Nil {
+		fields["summary_id"] = "summary_id is required"
+	}
+	if req.NumQuestions < MinQuizQuestions || req.NumQuestions > MaxQuizQuestions {
+		fields["num_questions"] = fmt.Sprintf("num_questions must be between %d and %d", MinQuizQuestions, MaxQuizQuestions)
+	}
+	if req.Difficulty != "" && !isAllowedValue(req.Difficulty, AllowedQuizDifficulties) {
+		fields["difficulty"] = "difficulty must be one of: " + strings.Join(AllowedQuizDifficulties, ", ")
+	}
+	validateDifficultyMix(fields, req)
+	for _, qt := range req.QuestionTypes {
+		if normalizeQuestionType(qt) == "" {
+			fields["question_types"] = fmt.Sprintf("unsupported question type: %q", qt)
+			break
+		}
+	}
+	if len([]rune(req.Title)) > MaxTitleLength {
+		fields["title"] = fmt.Sprintf("title must be at most %d characters", MaxTitleLength)
+	}
+	if req.HintPenaltyPercent < 0 || req.HintPenaltyPercent > 100 {
+		fields["hint_penalty_percent"] = "hint_penalty_percent must be between 0 and 100"
+	}
+	validateTextList(fields, "topics", req.Topics, MaxTopics)
+
+	return fields
+}
+
+// ValidateFlashcardConfig returns field errors for a flashcard generation request.
+// Strategy aliases must already be normalized by the caller.
+func ValidateFlashcardConfig(req models.GenerateFlashcardsRequest) map[string]string {
+	fields := make(map[string]string)
+
+	hasContent := req.ContentID != nil && *req.ContentID != uuid.Nil
+	switch {
+	case req.SummaryID == uuid.Nil && !hasContent:
+		fields["summary_id"] = "summary_id or content_id is required"
+	case req.SummaryID != uuid.Nil && hasContent:
+		fields["content_id"] = "provide either summary_id or content_id, not both"
+	}
+	if req.NumCards < MinFlashcards || req.NumCards > MaxFlashcards {
+		fields["num_cards"] = fmt.Sprintf("num_cards must be between %d and %d", MinFlashcards, MaxFlashcards)
+	}
+	if !isAllowedValue(req.Strategy, AllowedFlashcardStrategies) {
+		fields["strategy"] = "strategy must be term_definition or question_answer"
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		fields["title"] = "title is required"
+	} else if len([]rune(req.Title)) > MaxTitleLength {
+		fields["title"] = fmt.Sprintf("title must be at most %d characters", MaxTitleLength)
+	}
+	validateTextList(fields, "topics", req.Topics, MaxTopics)
+
+	return fields
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/models"
+)
+
+// jobProgressTTL keeps a job's last status update around long enough for a
+// client to poll it after the job ends.
+const jobProgressTTL = 24 * time.Hour
+
+// jobStepCounts is how many numbered steps each job type reports through
+// status updates. Percentages are spread over these steps.
+var jobStepCounts = map[string]int{
+	"content-processing":   2,
+	"summary-generation":   4,
+	"quiz-generation":      2,
+	"flashcard-generation": 2,
+	"presentation":         5,
+}
+
+// storedJobUpdate is a job's latest status update and when it was published,
+// so the remaining time can count down between updates.
+type storedJobUpdate struct {
+	models.StatusUpdate
+	PublishedAt time.Time `json:"published_at"`
+}
+
+func jobProgressKey(jobID uuid.UUID) string {
+	return fmt.Sprintf("job_progress:%s", jobID.String())
+}
+
+// saveJobProgress keeps the latest status update of a job for clients that
+// poll instead of holding a WebSocket open.
+func saveJobProgress(ctx context.Context, rdb *redis.Client, update models.StatusUpdate, now time.Time) error {
+	if rdb == nil || update.JobID == uuid.Nil {
+		return nil
+	}
+	data, err := json.Marshal(storedJobUpdate{StatusUpdate: update, PublishedAt: now})
+	if err != nil {
+		return err
+	}
+	return rdb.Set(ctx, jobProgressKey(update.JobID), data, jobProgressTTL).Err()
+}
+
+// LoadJobProgress reports how far along a job is from its status and the last
+// status update published for it. Without Redis, or before the first update,
+// progress is derived from the status alone.
+func LoadJobProgress(ctx context.Context, rdb *redis.Client, job *models.Job) (models.JobProgress, error) {
+	if rdb == nil {
+		return jobProgress(job, nil, time.Now()), nil
+	}
+	raw, err := rdb.Get(ctx, jobProgressKey(job.ID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return jobProgress(job, nil, time.Now()), nil
+	}
+	if err != nil {
+		return models.JobProgress{}, fmt.Errorf("LoadJobProgress: %w", err)
+	}
+	var update storedJobUpdate
+	if err := json.Unmarshal(raw, &update); err != nil {
+		return jobProgress(job, nil, time.Now()), nil
+	}
+	return jobProgress(job, &update, time.Now()), nil
+}
+
+func jobProgress(job *models.Job, update *storedJobUpdate, now time.Time) models.JobProgress {
+	progress := models.JobProgress{JobID: job.ID, Status: job.Status}
+	if update != nil {
+		progress.Step = update.Step
+		progress.StepName = update.StepName
+	}
+
+	switch job.Status {
+	case "completed":
+		progress.Percent = 100
+		return progress
+	case "pending":
+		// Queued, or waiting to retry after a failed attempt.
+		return progress
+	case "failed", "cancelled":
+		progress.Percent = stepPercent(job.Type, progress.Step)
+		return progress
+	}
+
+	progress.Percent = stepPercent(job.Type, progress.Step)
+	if update != nil && update.EstimatedSecondsRemaining > 0 {
+		remaining := update.EstimatedSecondsRemaining - int(now.Sub(update.PublishedAt)/time.Second)
+		if remaining < 1 {
+			// Running longer than estimated; "almost done" beats zero.
+			remaining = 1
+		}
+		progress.EstimatedSecondsRemaining = remaining
+	}
+	return progress
+}
+
+// stepPercent places a running step within its job type's steps. A step is
+// reported when it starts, so even the last one stays short of 100.
+func stepPercent(jobType string, step int) int {
+	if step <= 0 {
+		return 0
+	}
+	total, ok := jobStepCounts[jobType]
+	if !ok {
+		total = 4
+	}
+	if step > total {
+		step = total
+	}
+	return step * 100 / (total + 1)
+}
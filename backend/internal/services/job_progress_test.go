@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestJobProgress_RunningJobCountsDown(t *testing.T) {
+	published := time.Now()
+	job := &models.Job{ID: uuid.New(), Type: "summary-generation", Status: "processing"}
+	update := &storedJobUpdate{
+		StatusUpdate: models.StatusUpdate{JobID: job.ID, Step: 3, StepName: "Generating Summary", EstimatedSecondsRemaining: 30},
+		PublishedAt:  published,
+	}
+
+	got := jobProgress(job, update, published.Add(12*time.Second))
+	if got.Step != 3 || got.StepName != "Generating Summary" || got.Percent != 60 || got.EstimatedSecondsRemaining != 18 {
+		t.Fatalf("unexpected progress %+v", got)
+	}
+
+	got = jobProgress(job, update, published.Add(time.Minute))
+	if got.EstimatedSecondsRemaining != 1 {
+		t.Fatalf("overdue job should report 1 second left, got %d", got.EstimatedSecondsRemaining)
+	}
+}
+
+func TestJobProgress_FollowsJobStatus(t *testing.T) {
+	update := &storedJobUpdate{StatusUpdate: models.StatusUpdate{Step: 2, StepName: "Creating Flashcards", EstimatedSecondsRemaining: 15}, PublishedAt: time.Now()}
+
+	tests := []struct {
+		status      string
+		wantPercent int
+	}{
+		{"completed", 100},
+		{"pending", 0},
+		{"failed", 66},
+	}
+	for _, tt := range tests {
+		job := &models.Job{Type: "flashcard-generation", Status: tt.status}
+		got := jobProgress(job, update, time.Now())
+		if got.Percent != tt.wantPercent || got.EstimatedSecondsRemaining != 0 || got.Status != tt.status {
+			t.Fatalf("%s: unexpected progress %+v", tt.status, got)
+		}
+	}
+}
+
+func TestLoadJobProgress_WithoutRedis(t *testing.T) {
+	job := &models.Job{ID: uuid.New(), Type: "quiz-generation", Status: "processing"}
+
+	got, err := LoadJobProgress(context.Background(), nil, job)
+	if err != nil || got.JobID != job.ID || got.Percent != 0 || got.Step != 0 {
+		t.Fatalf("LoadJobProgress = %+v, %v", got, err)
+	}
+}
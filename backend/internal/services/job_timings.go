@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rolling window of job durations kept per job type and input size bucket.
+// Estimates need a few samples before they beat the static per-step guesses.
+const (
+	jobTimingWindow     = 50
+	jobTimingMinSamples = 5
+	jobTimingTTL        = 30 * 24 * time.Hour
+)
+
+// jobTimingAllSizes buckets samples regardless of input size, for job types
+// whose input is not known up front and as a fallback for sparse buckets.
+const jobTimingAllSizes = "all"
+
+// JobTimings records how long each job type takes to complete and turns that
+// history into the ETA shown while a job runs.
+type JobTimings struct {
+	redis *redis.Client
+}
+
+func NewJobTimings(redisClient *redis.Client) *JobTimings {
+	return &JobTimings{redis: redisClient}
+}
+
+// jobETA is the in-flight estimate for one job, carried on its context so
+// status updates published anywhere during the job can report it.
+type jobETA struct {
+	mu         sync.Mutex
+	jobType    string
+	start      time.Time
+	inputChars int
+	expected   time.Duration
+}
+
+type jobETAKey struct{}
+
+func jobETAFrom(ctx context.Context) *jobETA {
+	eta, _ := ctx.Value(jobETAKey{}).(*jobETA)
+	return eta
+}
+
+// secondsRemaining is the historical duration minus the time already spent,
+// or false when there is not enough history to say.
+func (e *jobETA) secondsRemaining(now time.Time) (int, bool) {
+	if e == nil {
+		return 0, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.expected <= 0 {
+		return 0, false
+	}
+	remaining := int((e.expected - now.Sub(e.start)).Round(time.Second) / time.Second)
+	if remaining < 1 {
+		// Running longer than usual; "almost done" beats a zero or negative ETA.
+		remaining = 1
+	}
+	return remaining, true
+}
+
+// Track starts timing a job and attaches its ETA to the returned context.
+// Until NoteInputSize is called the estimate covers all input sizes.
+func (t *JobTimings) Track(ctx context.Context, jobType string) context.Context {
+	eta := &jobETA{jobType: jobType, start: time.Now()}
+	if expected, ok := t.Estimate(ctx, jobType, 0); ok {
+		eta.expected = expected
+	}
+	return context.WithValue(ctx, jobETAKey{}, eta)
+}
+
+// NoteInputSize narrows the estimate of the job tracked in ctx to jobs with
+// a similar amount of input text.
+func (t *JobTimings) NoteInputSize(ctx context.Context, chars int) {
+	eta := jobETAFrom(ctx)
+	if eta == nil {
+		return
+	}
+	eta.mu.Lock()
+	eta.inputChars = chars
+	jobType := eta.jobType
+	eta.mu.Unlock()
+
+	if expected, ok := t.Estimate(ctx, jobType, chars); ok {
+		eta.mu.Lock()
+		eta.expected = expected
+		eta.mu.Unlock()
+	}
+}
+
+// Finish records how long the job tracked in ctx took. Call it only for jobs
+// that completed, so failures cut short do not drag the estimate down.
+func (t *JobTimings) Finish(ctx context.Context) {
+	eta := jobETAFrom(ctx)
+	if eta == nil {
+		return
+	}
+	eta.mu.Lock()
+	jobType, chars, elapsed := eta.jobType, eta.inputChars, time.Since(eta.start)
+	eta.mu.Unlock()
+
+	if err := t.Record(ctx, jobType, chars, elapsed); err != nil {
+		log.Printf("job timings: failed to record %s duration: %v", jobType, err)
+	}
+}
+
+// Record adds a completed job's duration to the history for its type, both
+// in its input size bucket and across all sizes.
+func (t *JobTimings) Record(ctx context.Context, jobType string, inputChars int, d time.Duration) error {
+	if t == nil || t.redis == nil {
+		return nil
+	}
+	buckets := []string{jobTimingAllSizes}
+	if inputChars > 0 {
+		buckets = append(buckets, inputSizeBucket(inputChars))
+	}
+
+	pipe := t.redis.Pipeline()
+	for _, bucket := range buckets {
+		key := jobTimingKey(jobType, bucket)
+		pipe.LPush(ctx, key, d.Milliseconds())
+		pipe.LTrim(ctx, key, 0, jobTimingWindow-1)
+		pipe.Expire(ctx, key, jobTimingTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Estimate is the median duration of recent jobs of this type with a
+// similar input size, falling back to all sizes when that bucket is sparse.
+// It returns false when there is not enough history either way.
+func (t *JobTimings) Estimate(ctx context.Context, jobType string, inputChars int) (time.Duration, bool) {
+	if t == nil || t.redis == nil {
+		return 0, false
+	}
+	buckets := []string{jobTimingAllSizes}
+	if inputChars > 0 {
+		buckets = []string{inputSizeBucket(inputChars), jobTimingAllSizes}
+	}
+
+	for _, bucket := range buckets {
+		raw, err := t.redis.LRange(ctx, jobTimingKey(jobType, bucket), 0, -1).Result()
+		if err != nil {
+			return 0, false
+		}
+		samples := make([]time.Duration, 0, len(raw))
+		for _, v := range raw {
+			if ms, err := strconv.ParseInt(v, 10, 64); err == nil && ms > 0 {
+				samples = append(samples, time.Duration(ms)*time.Millisecond)
+			}
+		}
+		if len(samples) >= jobTimingMinSamples {
+			return medianDuration(samples), true
+		}
+	}
+	return 0, false
+}
+
+func jobTimingKey(jobType, bucket string) string {
+	return fmt.Sprintf("job_timings:%s:%s", jobType, bucket)
+}
+
+// inputSizeBucket groups transcripts by length; generation time grows with
+// input, but only coarsely enough to need a handful of buckets.
+func inputSizeBucket(chars int) string {
+	switch {
+	case chars < 5000:
+		return "small"
+	case chars < 20000:
+		return "medium"
+	case chars < 60000:
+		return "large"
+	default:
+		return "xlarge"
+	}
+}
+
+// medianDuration returns the median of samples, which must not be empty. It
+// sorts samples in place.
+func medianDuration(samples []time.Duration) time.Duration {
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	mid := len(samples) / 2
+	if len(samples)%2 == 1 {
+		return samples[mid]
+	}
+	return (samples[mid-1] + samples[mid]) / 2
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMedianDuration(t *testing.T) {
+	odd := []time.Duration{30 * time.Second, 10 * time.Second, 20 * time.Second}
+	if got := medianDuration(odd); got != 20*time.Second {
+		t.Fatalf("odd median = %s, want 20s", got)
+	}
+	even := []time.Duration{40 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second}
+	if got := medianDuration(even); got != 25*time.Second {
+		t.Fatalf("even median = %s, want 25s", got)
+	}
+}
+
+func TestInputSizeBucket(t *testing.T) {
+	tests := map[int]string{
+		100:    "small",
+		4999:   "small",
+		5000:   "medium",
+		25000:  "large",
+		100000: "xlarge",
+	}
+	for chars, want := range tests {
+		if got := inputSizeBucket(chars); got != want {
+			t.Fatalf("inputSizeBucket(%d) = %q, want %q", chars, got, want)
+		}
+	}
+}
+
+func TestJobETA_SecondsRemaining(t *testing.T) {
+	start := time.Now()
+	eta := &jobETA{start: start, expected: 40 * time.Second}
+
+	if got, ok := eta.secondsRemaining(start.Add(15 * time.Second)); !ok || got != 25 {
+		t.Fatalf("secondsRemaining = %d, %v; want 25, true", got, ok)
+	}
+	if got, ok := eta.secondsRemaining(start.Add(2 * time.Minute)); !ok || got != 1 {
+		t.Fatalf("overdue secondsRemaining = %d, %v; want 1, true", got, ok)
+	}
+
+	var untracked *jobETA
+	if _, ok := untracked.secondsRemaining(start); ok {
+		t.Fatal("expected no estimate for an untracked job")
+	}
+	if _, ok := (&jobETA{start: start}).secondsRemaining(start); ok {
+		t.Fatal("expected no estimate without history")
+	}
+}
+
+func TestJobTimings_WithoutRedisFallsBack(t *testing.T) {
+	timings := NewJobTimings(nil)
+	ctx := timings.Track(context.Background(), "summary-generation")
+	timings.NoteInputSize(ctx, 12000)
+
+	eta := jobETAFrom(ctx)
+	if eta == nil || eta.inputChars != 12000 {
+		t.Fatalf("expected tracked ETA with input size, got %+v", eta)
+	}
+	if _, ok := eta.secondsRemaining(time.Now()); ok {
+		t.Fatal("expected static fallback when there is no history")
+	}
+	if err := timings.Record(ctx, "summary-generation", 12000, time.Minute); err != nil {
+		t.Fatalf("Record without redis: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// maxJSONRepairStarts bounds how many '[' positions decodeJSONArray tries.
+const maxJSONRepairStarts = 5
+
+// errNoJSONArray is returned when a model reply contains no JSON array.
+var errNoJSONArray = errors.New("no JSON array in model output")
+
+// stripCodeFences removes the ```json fences models like to wrap output in.
+func stripCodeFences(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```JSON")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	return strings.TrimSpace(raw)
+}
+
+// decodeJSONArray unmarshals the JSON array in a model reply into v,
+// repairing the usual defects (surrounding prose, trailing commas, output
+// truncated at the token limit) when the reply does not parse as-is.
+func decodeJSONArray(raw string, v any) error {
+	raw = stripCodeFences(raw)
+	err := json.Unmarshal([]byte(raw), v)
+	if err == nil {
+		return nil
+	}
+
+	// Prose before the array may itself contain brackets ("here are [10]
+	// questions"), so try the first few candidates.
+	offset := 0
+	for attempt := 0; attempt < maxJSONRepairStarts; attempt++ {
+		repaired, ok := repairJSONArray(raw[offset:])
+		if !ok {
+			break
+		}
+		if err = json.Unmarshal([]byte(repaired), v); err == nil {
+			return nil
+		}
+		offset += strings.IndexByte(raw[offset:], '[') + 1
+	}
+	if offset == 0 {
+		return errNoJSONArray
+	}
+	return err
+}
+
+// repairJSONArray extracts the first JSON array from raw and fixes it up:
+// text outside the array is dropped and trailing commas are removed. If the
+// array was cut off, the last incomplete element is discarded so a
+// half-written question or card does not slip through; when not even one
+// element is complete, open strings and brackets are closed instead.
+func repairJSONArray(raw string) (string, bool) {
+	start := strings.IndexByte(raw, '[')
+	if start < 0 {
+		return "", false
+	}
+
+	var (
+		out          strings.Builder
+		stack        []byte
+		inString     bool
+		escaped      bool
+		lastComplete int // out length after the last complete top-level element
+	)
+	out.Grow(len(raw) - start)
+
+	for i := start; i < len(raw); i++ {
+		c := raw[i]
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				if len(stack) == 1 {
+					lastComplete = out.Len()
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			out.WriteByte(c)
+		case '[', '{':
+			stack = append(stack, c)
+			out.WriteByte(c)
+		case ']', '}':
+			if len(stack) == 0 {
+				continue
+			}
+			// Close whatever is open; models occasionally mismatch brackets.
+			trimTrailingComma(&out)
+			out.WriteByte(closerFor(stack[len(stack)-1]))
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return out.String(), true
+			}
+			if len(stack) == 1 {
+				lastComplete = out.Len()
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	// Truncated: prefer dropping the unfinished element.
+	if lastComplete > 0 {
+		s := strings.TrimRight(out.String()[:lastComplete], " \t\r\n")
+		return strings.TrimSuffix(s, ",") + "]", true
+	}
+
+	if inString {
+		if escaped {
+			// Drop the dangling backslash so the closing quote is not escaped.
+			s := out.String()
+			out.Reset()
+			out.WriteString(s[:len(s)-1])
+		}
+		out.WriteByte('"')
+	}
+	s := strings.TrimRight(out.String(), " \t\r\n")
+	switch {
+	case strings.HasSuffix(s, ","):
+		s = strings.TrimSuffix(s, ",")
+	case strings.HasSuffix(s, ":"):
+		s += "null"
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		s += string(closerFor(stack[i]))
+	}
+	return s, true
+}
+
+// trimTrailingComma drops a comma (and the whitespace after it) at the end of
+// out, turning "[1, 2, ]" into "[1, 2]".
+func trimTrailingComma(out *strings.Builder) {
+	s := out.String()
+	trimmed := strings.TrimRight(s, " \t\r\n")
+	if !strings.HasSuffix(trimmed, ",") {
+		return
+	}
+	out.Reset()
+	out.WriteString(trimmed[:len(trimmed)-1])
+}
+
+func closerFor(opener byte) byte {
+	if opener == '[' {
+		return ']'
+	}
+	return '}'
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+type repairCard struct {
+	Front string `json:"front"`
+	Back  string `json:"back"`
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantFront []string
+	}{
+		{
+			name:      "valid",
+			raw:       `[{"front":"A","back":"1"},{"front":"B","back":"2"}]`,
+			wantFront: []string{"A", "B"},
+		},
+		{
+			name:      "fenced with prose",
+			raw:       "Sure! Here are your cards:\n```json\n[{\"front\":\"A\",\"back\":\"1\"}]\n```\nLet me know if you need more.",
+			wantFront: []string{"A"},
+		},
+		{
+			name:      "trailing commas",
+			raw:       `[{"front":"A","back":"1",},{"front":"B","back":"2"},]`,
+			wantFront: []string{"A", "B"},
+		},
+		{
+			name:      "truncated mid string drops partial element",
+			raw:       `[{"front":"A","back":"1"},{"front":"B","back":"The mitochondria is the power`,
+			wantFront: []string{"A"},
+		},
+		{
+			name:      "truncated after comma",
+			raw:       `[{"front":"A","back":"1"}, {"front":"B","back":"2"},`,
+			wantFront: []string{"A", "B"},
+		},
+		{
+			name:      "truncated first element closes string and brackets",
+			raw:       `[{"front":"A","back":"cut off here`,
+			wantFront: []string{"A"},
+		},
+		{
+			name:      "brackets inside strings",
+			raw:       `[{"front":"What is [x]?","back":"a } b"}`,
+			wantFront: []string{"What is [x]?"},
+		},
+		{
+			name:      "bracketed prose before array",
+			raw:       `Here are [2] cards: [{"front":"A","back":"1"},{"front":"B","back":"2"}]`,
+			wantFront: []string{"A", "B"},
+		},
+		{
+			name:      "wrapped in object",
+			raw:       `{"cards": [{"front":"A","back":"1"}]}`,
+			wantFront: []string{"A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cards []repairCard
+			if err := decodeJSONArray(tt.raw, &cards); err != nil {
+				t.Fatalf("decodeJSONArray() error = %v", err)
+			}
+			if len(cards) != len(tt.wantFront) {
+				t.Fatalf("got %d cards %+v, want %d", len(cards), cards, len(tt.wantFront))
+			}
+			for i, want := range tt.wantFront {
+				if cards[i].Front != want {
+					t.Fatalf("card %d front = %q, want %q", i, cards[i].Front, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeJSONArray_Unrecoverable(t *testing.T) {
+	var cards []repairCard
+	if err := decodeJSONArray("I cannot help with that.", &cards); !errors.Is(err, errNoJSONArray) {
+		t.Fatalf("err = %v, want errNoJSONArray", err)
+	}
+	if err := decodeJSONArray(`[{"front": "A", "back" 1}]`, &cards); err == nil {
+		t.Fatal("expected an error for JSON that cannot be repaired")
+	}
+}
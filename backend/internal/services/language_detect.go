@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Language detection limits.
+const (
+	maxLanguageSampleRunes  = 20000
+	minLanguageLetters      = 40
+	minLanguageStopwordHits = 5
+	languageWinMargin       = 1.25
+)
+
+// AutoLanguage asks summary generation to follow the content's detected
+// language instead of a fixed one.
+const AutoLanguage = "auto"
+
+// languageStopwords are frequent function words for Latin-script languages.
+// Shared words count for every language listing them; the distinctive ones
+// decide the winner.
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "for", "you", "was", "with", "on", "are", "this", "be", "have", "we", "not", "they"},
+	"es": {"el", "la", "de", "que", "y", "los", "las", "del", "en", "un", "una", "por", "con", "para", "es", "se", "no", "lo", "como", "más", "pero"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "que", "qui", "dans", "pour", "pas", "sur", "au", "du", "ce", "il", "nous", "vous"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "sich", "des", "auf", "für", "dem", "auch", "es", "wir", "ich"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "é", "no", "na", "se", "mais", "dos", "das"},
+	"it": {"il", "la", "di", "che", "e", "è", "un", "una", "per", "non", "del", "della", "in", "sono", "con", "gli", "le", "si", "lo", "anche"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "met", "voor", "ook", "er", "maar", "wij", "ik", "je"},
+	"tr": {"ve", "bir", "bu", "da", "de", "için", "ile", "çok", "olarak", "daha", "gibi", "ama", "ne", "var", "değil", "olan", "sonra"},
+	"pl": {"i", "w", "nie", "na", "się", "z", "że", "do", "jest", "to", "jak", "ale", "o", "co", "od", "po", "tak", "przez"},
+	"id": {"yang", "dan", "di", "ini", "itu", "dengan", "untuk", "tidak", "dari", "dalam", "akan", "ada", "pada", "juga", "kita", "saya"},
+}
+
+var stopwordLanguages = func() map[string][]string {
+	index := map[string][]string{}
+	for lang, words := range languageStopwords {
+		for _, w := range words {
+			index[w] = append(index[w], lang)
+		}
+	}
+	return index
+}()
+
+// kazakhLetters and ukrainianLetters are Cyrillic letters Russian does not use.
+const (
+	kazakhLetters    = "әғқңөұүһ"
+	ukrainianLetters = "їєґ"
+)
+
+// DetectLanguage guesses the ISO 639-1 code of a transcript. Non-Latin
+// scripts are identified by their letters; Latin-script languages by their
+// most frequent function words. It returns "" when the text is too short or
+// too mixed to call.
+func DetectLanguage(text string) string {
+	if runes := []rune(text); len(runes) > maxLanguageSampleRunes {
+		text = string(runes[:maxLanguageSampleRunes])
+	}
+
+	scripts := map[string]int{}
+	letters, kana, kazakh, ukrainian := 0, 0, 0, 0
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			scripts["latin"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["cyrillic"]++
+			if strings.ContainsRune(kazakhLetters, r) {
+				kazakh++
+			} else if strings.ContainsRune(ukrainianLetters, r) {
+				ukrainian++
+			}
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			scripts["han"]++
+			kana++
+		case unicode.Is(unicode.Han, r):
+			scripts["han"]++
+		case unicode.Is(unicode.Hangul, r):
+			scripts["ko"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["ar"]++
+		case unicode.Is(unicode.Greek, r):
+			scripts["el"]++
+		case unicode.Is(unicode.Hebrew, r):
+			scripts["he"]++
+		case unicode.Is(unicode.Devanagari, r):
+			scripts["hi"]++
+		case unicode.Is(unicode.Thai, r):
+			scripts["th"]++
+		}
+	}
+	if letters < minLanguageLetters {
+		return ""
+	}
+
+	script, count := "", 0
+	for s, n := range scripts {
+		if n > count {
+			script, count = s, n
+		}
+	}
+	if count*2 <= letters {
+		return ""
+	}
+
+	switch script {
+	case "latin":
+		return detectLatinLanguage(text)
+	case "cyrillic":
+		// A handful of distinctive letters is enough; Russian text rarely
+		// contains any.
+		switch {
+		case kazakh >= 3 && kazakh*200 >= count:
+			return "kk"
+		case ukrainian >= 3 && ukrainian*200 >= count:
+			return "uk"
+		default:
+			return "ru"
+		}
+	case "han":
+		if kana*10 >= count {
+			return "ja"
+		}
+		return "zh"
+	default:
+		return script
+	}
+}
+
+func detectLatinLanguage(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	scores := map[string]int{}
+	for _, w := range words {
+		for _, lang := range stopwordLanguages[w] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, runnerUp := "", 0, 0
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, runnerUp = lang, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < minLanguageStopwordHits || bestScore*10 < len(words) {
+		return ""
+	}
+	if float64(bestScore) < float64(runnerUp)*languageWinMargin {
+		return ""
+	}
+	return best
+}
+
+// ResolveSummaryLanguage picks the summary language: an explicit choice wins,
+// otherwise the content's detected language, otherwise English.
+func ResolveSummaryLanguage(requested string, detected *string) string {
+	requested = strings.TrimSpace(requested)
+	if requested != "" && !strings.EqualFold(requested, AutoLanguage) {
+		return requested
+	}
+	if detected != nil && *detected != "" {
+		return *detected
+	}
+	return "en"
+}
+
+// languageNames spells out the codes DetectLanguage returns, so a translation
+// instruction names both languages plainly.
+var languageNames = map[string]string{
+	"en": "English", "es": "Spanish", "fr": "French", "de": "German",
+	"pt": "Portuguese", "it": "Italian", "nl": "Dutch", "tr": "Turkish",
+	"pl": "Polish", "id": "Indonesian", "ru": "Russian", "uk": "Ukrainian",
+	"kk": "Kazakh", "zh": "Chinese", "ja": "Japanese", "ko": "Korean",
+	"ar": "Arabic", "el": "Greek", "he": "Hebrew", "hi": "Hindi", "th": "Thai",
+}
+
+// languageName returns the English name of a language code, or the code
+// itself when it is not one we know.
+func languageName(code string) string {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	if name, ok := languageNames[base]; ok {
+		return name
+	}
+	return code
+}
+
+// needsTranslation reports whether a transcript in source has to be
+// translated for a summary in target. Codes match on their primary subtag,
+// and an unknown source never asks for translation.
+func needsTranslation(source, target string) bool {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return false
+	}
+	if target = strings.TrimSpace(target); target == "" {
+		target = "en"
+	}
+	sourceBase, _, _ := strings.Cut(strings.ToLower(source), "-")
+	targetBase, _, _ := strings.Cut(strings.ToLower(target), "-")
+	return sourceBase != targetBase
+}
+
+// summaryLanguageInstruction is the language layer of the summary prompt.
+// When the transcript is in another language than the summary, the model
+// translates while it summarizes, keeping the original wording of technical
+// terms next to their translation so students can match them to the lecture.
+func summaryLanguageInstruction(language, sourceLanguage string) string {
+	if needsTranslation(sourceLanguage, language) {
+		target := language
+		if strings.TrimSpace(target) == "" {
+			target = "en"
+		}
+		source, target := languageName(sourceLanguage), languageName(target)
+		return fmt.Sprintf("Language: The transcript is in %s. Read it in %s and write the whole summary in %s; do not leave sentences or headings untranslated. "+
+			"Translate technical terms with their established %s equivalent and give the original %s term in parentheses the first time each appears. "+
+			"Keep names, formulas, code and quoted titles as they are.\n\n", source, source, target, target, source)
+	}
+	if language != "" && language != "en" {
+		return fmt.Sprintf("Language: Respond entirely in %s.\n\n", language)
+	}
+	return ""
+}
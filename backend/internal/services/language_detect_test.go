@@ -0,0 +1,139 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english",
+			text: "In this lecture we look at the structure of the cell and how it is able to produce energy. The mitochondria are the part of the cell that is responsible for this process, and they have their own DNA.",
+			want: "en",
+		},
+		{
+			name: "spanish",
+			text: "En esta clase vamos a estudiar la estructura de la célula y cómo produce energía. Las mitocondrias son la parte de la célula que se encarga de este proceso, y tienen su propio ADN, como las bacterias.",
+			want: "es",
+		},
+		{
+			name: "french",
+			text: "Dans ce cours nous allons étudier la structure de la cellule et comment elle produit de l'énergie. Les mitochondries sont la partie de la cellule qui est responsable de ce processus, et elles ont leur propre ADN.",
+			want: "fr",
+		},
+		{
+			name: "german",
+			text: "In dieser Vorlesung betrachten wir die Struktur der Zelle und wie sie Energie erzeugt. Die Mitochondrien sind der Teil der Zelle, der für diesen Prozess zuständig ist, und sie haben auch eine eigene DNA.",
+			want: "de",
+		},
+		{
+			name: "portuguese",
+			text: "Nesta aula vamos estudar a estrutura da célula e como ela produz energia. As mitocôndrias são a parte da célula que é responsável por esse processo, e elas têm o seu próprio DNA, como as bactérias.",
+			want: "pt",
+		},
+		{
+			name: "russian",
+			text: "В этой лекции мы рассмотрим строение клетки и то, как она вырабатывает энергию. Митохондрии отвечают за этот процесс и имеют собственную ДНК.",
+			want: "ru",
+		},
+		{
+			name: "kazakh",
+			text: "Бұл дәрісте біз жасушаның құрылысын және оның энергияны қалай өндіретінін қарастырамыз. Митохондриялар осы үдеріске жауап береді және олардың өз ДНҚ-сы бар.",
+			want: "kk",
+		},
+		{
+			name: "ukrainian",
+			text: "У цій лекції ми розглянемо будову клітини та те, як вона виробляє енергію. Мітохондрії відповідають за цей процес і мають свою власну ДНК, як і бактерії, що є її предками.",
+			want: "uk",
+		},
+		{
+			name: "japanese",
+			text: "この講義では細胞の構造と、細胞がどのようにエネルギーを作り出すのかを学びます。ミトコンドリアはこの過程を担っています。",
+			want: "ja",
+		},
+		{
+			name: "chinese",
+			text: "在本次讲座中，我们将研究细胞的结构以及细胞如何产生能量。线粒体负责这一过程，并且拥有自己的脱氧核糖核酸，就像细菌一样。",
+			want: "zh",
+		},
+		{
+			name: "korean",
+			text: "이번 강의에서는 세포의 구조와 세포가 어떻게 에너지를 만들어 내는지 살펴봅니다. 미토콘드리아는 이 과정을 담당합니다.",
+			want: "ko",
+		},
+		{name: "too short", text: "Hello there", want: ""},
+		{name: "no words", text: "[00:01] ... [00:02] 12345 67890 !!! ???", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Fatalf("DetectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSummaryLanguage(t *testing.T) {
+	ru := "ru"
+	empty := ""
+
+	tests := []struct {
+		name      string
+		requested string
+		detected  *string
+		want      string
+	}{
+		{name: "explicit choice wins", requested: "fr", detected: &ru, want: "fr"},
+		{name: "empty uses detected", requested: "", detected: &ru, want: "ru"},
+		{name: "auto uses detected", requested: "Auto", detected: &ru, want: "ru"},
+		{name: "nothing detected", requested: "auto", detected: nil, want: "en"},
+		{name: "blank detected", requested: "", detected: &empty, want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveSummaryLanguage(tt.requested, tt.detected); got != tt.want {
+				t.Fatalf("ResolveSummaryLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeedsTranslation(t *testing.T) {
+	tests := []struct {
+		source, target string
+		want           bool
+	}{
+		{source: "es", target: "en", want: true},
+		{source: "es", target: "", want: true},
+		{source: "en", target: "", want: false},
+		{source: "pt", target: "pt-BR", want: false},
+		{source: "", target: "fr", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := needsTranslation(tt.source, tt.target); got != tt.want {
+			t.Fatalf("needsTranslation(%q, %q) = %v, want %v", tt.source, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestSummaryLanguageInstruction(t *testing.T) {
+	got := summaryLanguageInstruction("en", "es")
+	if !strings.Contains(got, "The transcript is in Spanish") || !strings.Contains(got, "whole summary in English") {
+		t.Fatalf("instruction = %q, want a Spanish to English translation", got)
+	}
+
+	if got := summaryLanguageInstruction("ru", "ru"); got != "Language: Respond entirely in ru.\n\n" {
+		t.Fatalf("same-language instruction = %q", got)
+	}
+	if got := summaryLanguageInstruction("en", ""); got != "" {
+		t.Fatalf("English without a source language should add nothing, got %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// mindMapNodeBudget is how many nodes a mind-map summary aims for at a length
+// preset, within MaxOutlineNodes.
+func mindMapNodeBudget(length string) int {
+	switch length {
+	case "concise":
+		return 20
+	case "detailed":
+		return 55
+	case "comprehensive":
+		return MaxOutlineNodes
+	default:
+		return 35
+	}
+}
+
+// writeMindMapFormat writes the prompt rules of the mindmap format. The tree
+// takes the place of prose, so the node budget stands in for the word bands
+// of the other formats.
+func writeMindMapFormat(b *strings.Builder, length string) {
+	b.WriteString("Format: Mind map. Return ONLY a JSON object of the form {\"title\": \"...\", \"children\": [{\"title\": \"...\", \"children\": [...]}]}. No markdown, no code fences, no commentary before or after the JSON.\n")
+	b.WriteString("Mind map output rules:\n")
+	b.WriteString("1) The root title names the overall topic of the lecture. Its children are the main themes, in the order the lecture covers them. Deeper levels hold the supporting points: definitions, mechanisms, examples, names, dates and figures.\n")
+	fmt.Fprintf(b, "2) Use at most %d levels below the root and at most %d nodes in total; aim for about %d nodes.\n", MaxOutlineDepth, MaxOutlineNodes, mindMapNodeBudget(length))
+	fmt.Fprintf(b, "3) Each title is a short phrase of at most 12 words and %d characters, not a sentence. Keep the exact term, number or name from the transcript in it.\n", maxOutlineTitleLength)
+	b.WriteString("4) Every node must come from the transcript. Do not add outside knowledge.\n")
+	b.WriteString("5) Do not repeat a parent's title in its children, and do not add empty \"children\" arrays to leaves.\n\n")
+}
+
+// mindMapFromResponse reads a mindmap-format summary. A JSON tree is
+// normalized like an on-demand outline, keeping only nodes grounded in
+// grounding. When the model answered with headings and bullets instead, they
+// become a two-level tree; fromBullets reports that fallback.
+func mindMapFromResponse(raw, grounding string) (tree *models.OutlineNode, fromBullets bool, err error) {
+	if root, parseErr := ParseOutline(raw); parseErr == nil {
+		if tree, err := NormalizeOutline(root, grounding, ""); err == nil {
+			return tree, false, nil
+		}
+	}
+
+	root := outlineFromBullets(raw)
+	if root == nil {
+		return nil, false, fmt.Errorf("mind map response has neither a JSON tree nor bullets")
+	}
+	// The bullets are the model's own summary, so they are grounded in
+	// themselves; normalizing only cleans titles and applies the limits.
+	tree, err = NormalizeOutline(root, raw, "")
+	if err != nil {
+		return nil, false, err
+	}
+	return tree, true, nil
+}
+
+// outlineFromBullets turns markdown headings and bullets into a two-level
+// tree. Headings and top-level bullets become themes; bullets under a
+// heading, and indented bullets, become points of the theme above them. A
+// leading "# " heading is taken as the root title. Other lines are ignored.
+// It returns nil when there are no themes.
+func outlineFromBullets(text string) *models.OutlineNode {
+	root := &models.OutlineNode{}
+	theme := -1
+	underHeading := false
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			title := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			if title == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "# ") && root.Title == "" && len(root.Children) == 0 {
+				root.Title = outlineBulletTitle(title)
+				continue
+			}
+			root.Children = append(root.Children, models.OutlineNode{Title: outlineBulletTitle(title)})
+			theme = len(root.Children) - 1
+			underHeading = true
+			continue
+		}
+
+		title, ok := bulletLineText(trimmed)
+		if !ok {
+			continue
+		}
+		indented := len(line)-len(strings.TrimLeft(line, " \t")) > 0
+		if theme < 0 || (!indented && !underHeading) {
+			root.Children = append(root.Children, models.OutlineNode{Title: outlineBulletTitle(title)})
+			theme = len(root.Children) - 1
+			continue
+		}
+		root.Children[theme].Children = append(root.Children[theme].Children, models.OutlineNode{Title: outlineBulletTitle(title)})
+	}
+
+	if len(root.Children) == 0 {
+		return nil
+	}
+	return root
+}
+
+// bulletLineText returns the text of a "-", "*", "+" or "1." list item.
+func bulletLineText(line string) (string, bool) {
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(line[len(marker):]), true
+		}
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	if digits > 0 && (strings.HasPrefix(line[digits:], ". ") || strings.HasPrefix(line[digits:], ") ")) {
+		return strings.TrimSpace(line[digits+2:]), true
+	}
+	return "", false
+}
+
+// outlineBulletTitle drops markdown emphasis from a heading or bullet.
+func outlineBulletTitle(text string) string {
+	text = strings.ReplaceAll(text, "**", "")
+	text = strings.ReplaceAll(text, "__", "")
+	return strings.TrimSpace(text)
+}
+
+// renderMindMapMarkdown writes a mind map as nested markdown bullets under
+// its title. It is stored as the summary's text, so search, exports, word
+// counts and quizzes read the same content the tree shows.
+func renderMindMapMarkdown(root *models.OutlineNode) string {
+	var b strings.Builder
+	if root.Title != "" {
+		b.WriteString("# " + root.Title + "\n\n")
+	}
+	writeMindMapBullets(&b, root.Children, 0)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeMindMapBullets(b *strings.Builder, nodes []models.OutlineNode, depth int) {
+	for _, node := range nodes {
+		b.WriteString(strings.Repeat("  ", depth) + "- " + node.Title + "\n")
+		writeMindMapBullets(b, node.Children, depth+1)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestMindMapFromResponse_ParsesJSONTree(t *testing.T) {
+	transcript := "Photosynthesis turns light energy into chemical energy. The Calvin cycle fixes carbon dioxide using ATP."
+	raw := "```json\n" + `{"title": "Photosynthesis", "children": [
+		{"title": "Light energy", "children": [{"title": "Chemical energy"}]},
+		{"title": "Calvin cycle", "children": [{"title": "Quantum tunnelling"}]}
+	]}` + "\n```"
+
+	tree, fromBullets, err := mindMapFromResponse(raw, transcript)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fromBullets {
+		t.Fatalf("expected the JSON tree to be used")
+	}
+	if tree.Title != "Photosynthesis" || len(tree.Children) != 2 {
+		t.Fatalf("tree = %+v, want the root and its two themes", tree)
+	}
+	if len(tree.Children[1].Children) != 0 {
+		t.Fatalf("expected the ungrounded node to be dropped, got %+v", tree.Children[1].Children)
+	}
+}
+
+func TestMindMapFromResponse_FallsBackToBullets(t *testing.T) {
+	raw := `# Cell Division
+
+## Mitosis
+- **Prophase**: chromosomes condense
+- Metaphase
+  - Chromosomes line up
+
+## Meiosis
+1. Produces four gametes
+Some closing prose that is not a bullet.`
+
+	tree, fromBullets, err := mindMapFromResponse(raw, "unrelated transcript")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fromBullets {
+		t.Fatalf("expected the bullet fallback")
+	}
+	want := &models.OutlineNode{Title: "Cell Division", Children: []models.OutlineNode{
+		{Title: "Mitosis", Children: []models.OutlineNode{
+			{Title: "Prophase: chromosomes condense"}, {Title: "Metaphase"}, {Title: "Chromosomes line up"},
+		}},
+		{Title: "Meiosis", Children: []models.OutlineNode{{Title: "Produces four gametes"}}},
+	}}
+	if got, wantMD := renderMindMapMarkdown(tree), renderMindMapMarkdown(want); got != wantMD {
+		t.Fatalf("tree =\n%s\nwant\n%s", got, wantMD)
+	}
+}
+
+func TestOutlineFromBullets_TopLevelBulletsAreThemes(t *testing.T) {
+	tree := outlineFromBullets("- Supply\n  - Price rises\n- Demand\n* Elasticity\n    + Inelastic goods")
+	if tree == nil || tree.Title != "" || len(tree.Children) != 3 {
+		t.Fatalf("tree = %+v, want three themes", tree)
+	}
+	if len(tree.Children[0].Children) != 1 || len(tree.Children[2].Children) != 1 {
+		t.Fatalf("expected indented bullets under their themes, got %+v", tree.Children)
+	}
+}
+
+func TestMindMapFromResponse_NoStructure(t *testing.T) {
+	if _, _, err := mindMapFromResponse("Just a paragraph of prose.", "prose"); err == nil {
+		t.Fatalf("expected an error for a response without a tree or bullets")
+	}
+}
+
+func TestRenderMindMapMarkdown(t *testing.T) {
+	tree := &models.OutlineNode{Title: "Topic", Children: []models.OutlineNode{
+		{Title: "Theme", Children: []models.OutlineNode{{Title: "Point"}}},
+	}}
+	want := "# Topic\n\n- Theme\n  - Point"
+	if got := renderMindMapMarkdown(tree); got != want {
+		t.Fatalf("markdown = %q, want %q", got, want)
+	}
+}
+
+func TestBuildSummaryPrompt_MindMapUsesNodeBudget(t *testing.T) {
+	prompt := buildSummaryPrompt("mindmap", "concise", nil, nil, "", "en", "", "some transcript text", false, false)
+	if !strings.Contains(prompt, "Return ONLY a JSON object") || !strings.Contains(prompt, "about 20 nodes") {
+		t.Fatalf("prompt is missing the mind map rules:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "CRITICAL LENGTH CONSTRAINT") {
+		t.Fatalf("mind map prompt should not ask for a word count")
+	}
+}
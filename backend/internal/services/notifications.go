@@ -5,21 +5,29 @@ import (
 	"log"
 	"time"
 
+	"lectura-backend/internal/models"
 	"lectura-backend/internal/repository"
 )
 
 const (
 	weeklyDigestLastSentKey  = "weekly_digest_last_sent_at"
 	studyReminderLastSentKey = "study_reminders_last_sent_at"
+	dailyBriefLastSentKey    = "daily_brief_last_sent_at"
 	weeklyDigestInterval     = 7 * 24 * time.Hour
 	studyReminderInterval    = 72 * time.Hour
 	notificationPollInterval = 1 * time.Hour
+
+	// The daily brief goes out in the first poll of dailyBriefHour local
+	// time; the interval only guards against a second send the same day.
+	dailyBriefHour     = 8
+	dailyBriefInterval = 20 * time.Hour
 )
 
 type NotificationScheduler struct {
-	userRepo *repository.UserRepo
-	email    *EmailService
-	stopChan chan struct{}
+	userRepo  *repository.UserRepo
+	email     *EmailService
+	trashRepo *repository.TrashRepo
+	stopChan  chan struct{}
 }
 
 func NewNotificationScheduler(userRepo *repository.UserRepo, email *EmailService) *NotificationScheduler {
@@ -30,7 +38,18 @@ func NewNotificationScheduler(userRepo *repository.UserRepo, email *EmailService
 	}
 }
 
+// WithTrashPurge makes the scheduler hard-delete soft-deleted items once their
+// recovery window has passed.
+func (s *NotificationScheduler) WithTrashPurge(trashRepo *repository.TrashRepo) *NotificationScheduler {
+	s.trashRepo = trashRepo
+	return s
+}
+
 func (s *NotificationScheduler) Start() {
+	if s.trashRepo != nil {
+		go s.loop(s.purgeExpiredTrash)
+	}
+
 	if s.userRepo == nil || s.email == nil {
 		return
 	}
@@ -41,6 +60,9 @@ func (s *NotificationScheduler) Start() {
 	go s.loop(func(ctx context.Context, now time.Time) {
 		s.sendStudyReminders(ctx, now)
 	})
+	go s.loop(func(ctx context.Context, now time.Time) {
+		s.sendDailyBriefs(ctx, now)
+	})
 
 	log.Printf("Notification scheduler started")
 }
@@ -138,6 +160,61 @@ func (s *NotificationScheduler) sendStudyReminders(ctx context.Context, now time
 	}
 }
 
+func (s *NotificationScheduler) sendDailyBriefs(ctx context.Context, now time.Time) {
+	recipients, err := s.userRepo.ListUsersWithNotificationEnabled(ctx, "daily_brief", dailyBriefLastSentKey)
+	if err != nil {
+		log.Printf("daily brief: failed to list recipients: %v", err)
+		return
+	}
+
+	for _, recipient := range recipients {
+		if !shouldSendByLastSent(recipient.LastSentAtRaw, dailyBriefInterval, now) {
+			continue
+		}
+
+		schedule, scheduleErr := s.userRepo.GetNotificationSchedule(ctx, recipient.ID)
+		if scheduleErr != nil {
+			log.Printf("daily brief: failed to load schedule for user %s: %v", recipient.ID, scheduleErr)
+			continue
+		}
+
+		local := now.In(scheduleLocation(schedule.Timezone))
+		if local.Hour() != dailyBriefSendHour(schedule) {
+			continue
+		}
+
+		brief, statsErr := s.userRepo.GetDailyBriefStats(ctx, recipient.ID, local)
+		if statsErr != nil {
+			log.Printf("daily brief: failed to load stats for user %s: %v", recipient.ID, statsErr)
+			continue
+		}
+
+		if !brief.Actionable() {
+			continue
+		}
+
+		if err := s.email.SendDailyBriefEmail(recipient.Email, recipient.FullName, *brief); err != nil {
+			log.Printf("daily brief: failed to send to %s: %v", recipient.Email, err)
+			continue
+		}
+
+		if err := s.userRepo.SetNotificationTimestamp(ctx, recipient.ID, dailyBriefLastSentKey, now); err != nil {
+			log.Printf("daily brief: failed to persist last sent at for user %s: %v", recipient.ID, err)
+		}
+	}
+}
+
+func (s *NotificationScheduler) purgeExpiredTrash(ctx context.Context, now time.Time) {
+	purged, err := s.trashRepo.PurgeExpired(ctx)
+	if err != nil {
+		log.Printf("trash purge: failed to purge expired items: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("trash purge: permanently deleted %d items older than %s", purged, repository.TrashRetention)
+	}
+}
+
 func shouldSendByLastSent(lastSentRaw string, minInterval time.Duration, now time.Time) bool {
 	if lastSentRaw == "" {
 		return true
@@ -158,3 +235,42 @@ func reminderReferenceTime(lastActivityAt *time.Time, createdAt time.Time) time.
 
 	return createdAt.UTC()
 }
+
+// scheduleLocation resolves a user's timezone, falling back to UTC when it is
+// unset or unknown.
+func scheduleLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// inQuietHours reports whether a local hour falls within the schedule's quiet
+// hours.
+func inQuietHours(hour int, schedule models.NotificationSchedule) bool {
+	if schedule.QuietHoursStart == nil || schedule.QuietHoursEnd == nil {
+		return false
+	}
+	start, end := *schedule.QuietHoursStart, *schedule.QuietHoursEnd
+	switch {
+	case start == end:
+		return false
+	case start < end:
+		return hour >= start && hour < end
+	default:
+		return hour >= start || hour < end
+	}
+}
+
+// dailyBriefSendHour is the local hour the brief is sent: dailyBriefHour, or
+// the end of quiet hours when they cover it.
+func dailyBriefSendHour(schedule models.NotificationSchedule) int {
+	if inQuietHours(dailyBriefHour, schedule) {
+		return *schedule.QuietHoursEnd
+	}
+	return dailyBriefHour
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"testing"
 	"time"
+
+	"lectura-backend/internal/models"
 )
 
 func TestShouldSendByLastSent(t *testing.T) {
@@ -41,3 +43,45 @@ func TestReminderReferenceTime(t *testing.T) {
 		t.Fatalf("expected last_activity_at to be preferred reference time")
 	}
 }
+
+func TestDailyBriefSendHour(t *testing.T) {
+	hours := func(start, end int) models.NotificationSchedule {
+		return models.NotificationSchedule{QuietHoursStart: &start, QuietHoursEnd: &end}
+	}
+
+	tests := []struct {
+		name     string
+		schedule models.NotificationSchedule
+		want     int
+	}{
+		{name: "no quiet hours", schedule: models.NotificationSchedule{}, want: dailyBriefHour},
+		{name: "overnight quiet hours ending before the brief", schedule: hours(22, 7), want: dailyBriefHour},
+		{name: "overnight quiet hours covering the brief", schedule: hours(23, 10), want: 10},
+		{name: "daytime quiet hours covering the brief", schedule: hours(6, 9), want: 9},
+		{name: "quiet hours ending at the brief hour", schedule: hours(0, dailyBriefHour), want: dailyBriefHour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dailyBriefSendHour(tt.schedule)
+			if got != tt.want {
+				t.Fatalf("dailyBriefSendHour() = %d, want %d", got, tt.want)
+			}
+			if inQuietHours(got, tt.schedule) {
+				t.Fatalf("send hour %d falls within quiet hours", got)
+			}
+		})
+	}
+}
+
+func TestScheduleLocation(t *testing.T) {
+	if loc := scheduleLocation(""); loc != time.UTC {
+		t.Fatalf("expected UTC for empty timezone, got %v", loc)
+	}
+	if loc := scheduleLocation("Not/AZone"); loc != time.UTC {
+		t.Fatalf("expected UTC for unknown timezone, got %v", loc)
+	}
+	if loc := scheduleLocation("Asia/Tokyo"); loc.String() != "Asia/Tokyo" {
+		t.Fatalf("expected Asia/Tokyo, got %v", loc)
+	}
+}
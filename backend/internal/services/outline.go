@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"lectura-backend/internal/models"
+)
+
+// Outline limits. Nodes beyond MaxOutlineDepth levels below the root, or past
+// the MaxOutlineNodes budget (in depth-first order), are dropped.
+const (
+	MaxOutlineDepth       = 4
+	MaxOutlineNodes       = 80
+	maxOutlineTitleLength = 120
+	maxOutlineContext     = 30000
+)
+
+func buildOutlinePrompt(content string) string {
+	if len(content) > maxOutlineContext {
+		content = content[:maxOutlineContext]
+	}
+	return fmt.Sprintf(`Turn the following study summary into a hierarchical outline for a mind map.
+
+Rules:
+1) Return ONLY a JSON object of the form {"title": "...", "children": [{"title": "...", "children": [...]}]}.
+2) The root title is the overall topic; its children are the main themes, and deeper levels are supporting points.
+3) Use at most %d levels below the root and at most %d nodes in total.
+4) Each title is a short phrase (max 12 words), not a sentence.
+5) Only use concepts that appear in the summary. Do not add outside knowledge.
+6) Use the same language as the summary.
+
+Summary:
+%s`, MaxOutlineDepth, MaxOutlineNodes, content)
+}
+
+// ParseOutline decodes a model response into an outline tree, tolerating code
+// fences and surrounding prose.
+func ParseOutline(raw string) (*models.OutlineNode, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	raw = strings.TrimSpace(raw)
+
+	var root models.OutlineNode
+	if err := json.Unmarshal([]byte(raw), &root); err != nil {
+		start := strings.Index(raw, "{")
+		end := strings.LastIndex(raw, "}")
+		if start < 0 || end <= start {
+			return nil, fmt.Errorf("outline response is not JSON: %w", err)
+		}
+		if err := json.Unmarshal([]byte(raw[start:end+1]), &root); err != nil {
+			return nil, fmt.Errorf("outline response is not JSON: %w", err)
+		}
+	}
+	return &root, nil
+}
+
+// NormalizeOutline enforces the depth and size limits and removes nodes whose
+// titles are not grounded in the summary content. Children of a removed node
+// are removed with it. The root title falls back to fallbackTitle.
+func NormalizeOutline(root *models.OutlineNode, content, fallbackTitle string) (*models.OutlineNode, error) {
+	if root == nil {
+		return nil, fmt.Errorf("outline is empty")
+	}
+
+	contentLower := strings.ToLower(content)
+	budget := MaxOutlineNodes - 1
+
+	out := &models.OutlineNode{Title: cleanOutlineTitle(root.Title)}
+	if out.Title == "" {
+		out.Title = cleanOutlineTitle(fallbackTitle)
+	}
+	out.Children = normalizeOutlineChildren(root.Children, contentLower, 1, &budget)
+
+	if len(out.Children) == 0 {
+		return nil, fmt.Errorf("outline has no nodes grounded in the summary")
+	}
+	return out, nil
+}
+
+func normalizeOutlineChildren(children []models.OutlineNode, contentLower string, depth int, budget *int) []models.OutlineNode {
+	if depth > MaxOutlineDepth {
+		return nil
+	}
+
+	var out []models.OutlineNode
+	for _, child := range children {
+		if *budget <= 0 {
+			break
+		}
+		title := cleanOutlineTitle(child.Title)
+		if title == "" || !isOutlineTitleGrounded(title, contentLower) {
+			continue
+		}
+		*budget--
+		node := models.OutlineNode{Title: title}
+		node.Children = normalizeOutlineChildren(child.Children, contentLower, depth+1, budget)
+		out = append(out, node)
+	}
+	return out
+}
+
+func cleanOutlineTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	title = strings.Trim(title, "-*#: ")
+	if runes := []rune(title); len(runes) > maxOutlineTitleLength {
+		title = strings.TrimSpace(string(runes[:maxOutlineTitleLength]))
+	}
+	return title
+}
+
+// isOutlineTitleGrounded reports whether a node title shares at least one
+// significant word with the content. Titles made only of short words (e.g.
+// acronyms) must appear verbatim.
+func isOutlineTitleGrounded(title, contentLower string) bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	significant := 0
+	for _, w := range words {
+		if len([]rune(w)) < 4 {
+			continue
+		}
+		significant++
+		if strings.Contains(contentLower, w) {
+			return true
+		}
+	}
+	if significant > 0 {
+		return false
+	}
+	return strings.Contains(contentLower, strings.ToLower(title))
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestNormalizeOutline_DropsUngroundedNodesAndTheirChildren(t *testing.T) {
+	content := "Photosynthesis converts light energy into chemical energy. The Calvin cycle fixes carbon dioxide. ATP and NADPH power it."
+	root := &models.OutlineNode{
+		Title: "",
+		Children: []models.OutlineNode{
+			{Title: "Light energy conversion", Children: []models.OutlineNode{{Title: "ATP"}}},
+			{Title: "Quantum entanglement", Children: []models.OutlineNode{{Title: "Calvin cycle"}}},
+			{Title: "  Calvin   cycle  "},
+		},
+	}
+
+	out, err := NormalizeOutline(root, content, "Photosynthesis")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Title != "Photosynthesis" {
+		t.Fatalf("expected fallback root title, got %q", out.Title)
+	}
+	if len(out.Children) != 2 {
+		t.Fatalf("expected 2 grounded children, got %+v", out.Children)
+	}
+	if out.Children[1].Title != "Calvin cycle" {
+		t.Fatalf("expected whitespace to be collapsed, got %q", out.Children[1].Title)
+	}
+	if len(out.Children[0].Children) != 1 || out.Children[0].Children[0].Title != "ATP" {
+		t.Fatalf("expected short grounded acronym to be kept, got %+v", out.Children[0].Children)
+	}
+}
+
+func TestNormalizeOutline_EnforcesDepthAndNodeBudget(t *testing.T) {
+	content := "topic"
+	node := models.OutlineNode{Title: "topic"}
+	for i := 0; i < MaxOutlineDepth+3; i++ {
+		node = models.OutlineNode{Title: "topic", Children: []models.OutlineNode{node}}
+	}
+	out, err := NormalizeOutline(&node, content, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	depth := 0
+	for cur := out; len(cur.Children) > 0; cur = &cur.Children[0] {
+		depth++
+	}
+	if depth != MaxOutlineDepth {
+		t.Fatalf("expected depth %d, got %d", MaxOutlineDepth, depth)
+	}
+
+	wide := &models.OutlineNode{Title: "topic"}
+	for i := 0; i < MaxOutlineNodes*2; i++ {
+		wide.Children = append(wide.Children, models.OutlineNode{Title: "topic"})
+	}
+	out, err = NormalizeOutline(wide, content, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Children) != MaxOutlineNodes-1 {
+		t.Fatalf("expected %d children, got %d", MaxOutlineNodes-1, len(out.Children))
+	}
+}
+
+func TestNormalizeOutline_NothingGrounded_ReturnsError(t *testing.T) {
+	root := &models.OutlineNode{Title: "Root", Children: []models.OutlineNode{{Title: "Unrelated material"}}}
+	if _, err := NormalizeOutline(root, "cells divide by mitosis", "Biology"); err == nil {
+		t.Fatal("expected an error when no node is grounded")
+	}
+}
+
+func TestParseOutline_ToleratesCodeFences(t *testing.T) {
+	root, err := ParseOutline("```json\n{\"title\":\"Cells\",\"children\":[{\"title\":\"Mitosis\"}]}\n```")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root.Title != "Cells" || len(root.Children) != 1 {
+		t.Fatalf("unexpected outline: %+v", root)
+	}
+}
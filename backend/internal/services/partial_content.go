@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+// partialContentInterval is the least time between partial_content messages
+// for one job, so a fast stream is not published to Redis token by token.
+const partialContentInterval = 400 * time.Millisecond
+
+// partialContentBatcher collects streamed summary text and hands it to
+// publish in batches at most one interval apart.
+type partialContentBatcher struct {
+	jobID    uuid.UUID
+	publish  func(models.PartialContent)
+	interval time.Duration
+	now      func() time.Time
+
+	pending  strings.Builder
+	sent     int
+	lastSent time.Time
+}
+
+func newPartialContentBatcher(jobID uuid.UUID, publish func(models.PartialContent)) *partialContentBatcher {
+	return &partialContentBatcher{
+		jobID:    jobID,
+		publish:  publish,
+		interval: partialContentInterval,
+		now:      time.Now,
+	}
+}
+
+// add queues a piece of text and publishes the queue once the interval since
+// the last message has passed. The first piece goes out straight away.
+func (b *partialContentBatcher) add(text string) {
+	if text == "" {
+		return
+	}
+	b.pending.WriteString(text)
+	if b.sent == 0 || b.now().Sub(b.lastSent) >= b.interval {
+		b.flush()
+	}
+}
+
+// flush publishes whatever text is queued.
+func (b *partialContentBatcher) flush() {
+	if b.pending.Len() == 0 {
+		return
+	}
+	b.sent++
+	b.publish(models.PartialContent{
+		JobID:           b.jobID,
+		Chunk:           b.pending.String(),
+		TotalChunksSent: b.sent,
+	})
+	b.pending.Reset()
+	b.lastSent = b.now()
+}
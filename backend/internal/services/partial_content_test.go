@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestPartialContentBatcher_ThrottlesPublishes(t *testing.T) {
+	jobID := uuid.New()
+	var published []models.PartialContent
+	b := newPartialContentBatcher(jobID, func(p models.PartialContent) { published = append(published, p) })
+	clock := time.Unix(0, 0)
+	b.now = func() time.Time { return clock }
+
+	b.add("The cell ")
+	b.add("is the ")
+	b.add("")
+	clock = clock.Add(partialContentInterval / 2)
+	b.add("basic unit ")
+	clock = clock.Add(partialContentInterval)
+	b.add("of life.")
+	b.add(" Cells divide.")
+	b.flush()
+	b.flush()
+
+	want := []string{"The cell ", "is the basic unit of life.", " Cells divide."}
+	if len(published) != len(want) {
+		t.Fatalf("expected %d messages, got %+v", len(want), published)
+	}
+	for i, p := range published {
+		if p.Chunk != want[i] || p.TotalChunksSent != i+1 || p.JobID != jobID {
+			t.Fatalf("message %d = %+v, want chunk %q numbered %d", i, p, want[i], i+1)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A4 page geometry in PDF points.
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+	pdfFooterY    = 32.0
+)
+
+// helveticaWidths are the standard Helvetica glyph widths (1/1000 em) for
+// ASCII 32..126, used for line wrapping.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsiPunctuation maps common typographic characters to WinAnsiEncoding.
+var winAnsiPunctuation = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95, '–': 0x96, '—': 0x97,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '™': 0x99,
+}
+
+type pdfLine struct {
+	text   string // WinAnsi-encoded
+	bold   bool
+	size   float64
+	indent float64
+	y      float64
+}
+
+// PDFDocument renders simple, text-only A4 documents (headings, wrapped
+// paragraphs, page numbers) with the built-in Helvetica fonts, so no font
+// files or external tools are needed. Characters outside WinAnsiEncoding are
+// replaced with '?'.
+type PDFDocument struct {
+	title string
+	pages [][]pdfLine
+	y     float64
+}
+
+func NewPDFDocument(title string) *PDFDocument {
+	d := &PDFDocument{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *PDFDocument) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// Heading adds a bold title line.
+func (d *PDFDocument) Heading(text string) {
+	d.write(text, true, 16, 0)
+	d.Space(4)
+}
+
+// Subheading adds a bold section line.
+func (d *PDFDocument) Subheading(text string) {
+	d.write(text, true, 12, 0)
+}
+
+// Paragraph adds wrapped body text. Newlines in text start new lines.
+func (d *PDFDocument) Paragraph(text string) {
+	d.write(text, false, 11, 0)
+}
+
+// Indented adds wrapped body text indented from the left margin.
+func (d *PDFDocument) Indented(text string, bold bool) {
+	d.write(text, bold, 11, 18)
+}
+
+// Space adds vertical whitespace.
+func (d *PDFDocument) Space(points float64) {
+	d.y -= points
+}
+
+// PageBreak starts a new page unless the current one is still empty.
+func (d *PDFDocument) PageBreak() {
+	if len(d.pages[len(d.pages)-1]) > 0 {
+		d.newPage()
+	}
+}
+
+func (d *PDFDocument) write(text string, bold bool, size, indent float64) {
+	leading := size * 1.35
+	maxWidth := pdfPageWidth - 2*pdfMargin - indent
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrapPDFText(encodeWinAnsi(paragraph), bold, size, maxWidth) {
+			if d.y-leading < pdfMargin {
+				d.newPage()
+			}
+			d.y -= leading
+			page := len(d.pages) - 1
+			d.pages[page] = append(d.pages[page], pdfLine{text: line, bold: bold, size: size, indent: indent, y: d.y})
+		}
+	}
+}
+
+// WriteTo writes the finished PDF.
+func (d *PDFDocument) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-5 are fixed; each page then takes a page and a content object.
+	const firstPageObj = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (Lectura) /CreationDate (D:%s) >>",
+		escapePDFString(encodeWinAnsi(d.title)), time.Now().UTC().Format("20060102150405Z")))
+
+	for i, lines := range d.pages {
+		var content bytes.Buffer
+		for _, l := range lines {
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, l.size, pdfMargin+l.indent, l.y, escapePDFString(l.text))
+		}
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		footerX := pdfPageWidth/2 - pdfTextWidth(footer, false, 9)/2
+		fmt.Fprintf(&content, "BT /F1 9 Tf %.2f %.2f Td (%s) Tj ET\n", footerX, pdfFooterY, footer)
+
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPageObj+2*i+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// encodeWinAnsi converts text to single-byte WinAnsiEncoding, dropping control
+// characters and replacing anything unrepresentable with '?'.
+func encodeWinAnsi(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 0x20 || r == 0x7f:
+			continue
+		case r < 0x80, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := winAnsiPunctuation[r]; ok {
+				b.WriteByte(c)
+			} else if unicode.IsSpace(r) {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+func escapePDFString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
+
+// pdfTextWidth estimates the rendered width of WinAnsi text in points. Bold
+// glyphs are approximated as slightly wider than regular ones.
+func pdfTextWidth(s string, bold bool, size float64) float64 {
+	units := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 32 && c <= 126 {
+			units += helveticaWidths[c-32]
+		} else {
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if bold {
+		width *= 1.08
+	}
+	return width
+}
+
+// wrapPDFText breaks text into lines no wider than maxWidth, splitting words
+// that are too long to fit on a line of their own.
+func wrapPDFText(s string, bold bool, size, maxWidth float64) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		for pdfTextWidth(word, bold, size) > maxWidth {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			cut := len(word) - 1
+			for cut > 1 && pdfTextWidth(word[:cut], bold, size) > maxWidth {
+				cut--
+			}
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if pdfTextWidth(candidate, bold, size) > maxWidth {
+			lines = append(lines, current)
+			current = word
+		} else {
+			current = candidate
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPDFDocument_WritesValidStructure(t *testing.T) {
+	doc := NewPDFDocument("Notes (draft)")
+	doc.Heading("Chapter 1 \\ intro")
+	for i := 0; i < 120; i++ {
+		doc.Paragraph("Mitochondria are the powerhouse of the cell, and this line is repeated to fill several pages.")
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	out := buf.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("missing PDF header or trailer")
+	}
+	if len(doc.pages) < 2 {
+		t.Fatalf("expected content to span several pages, got %d", len(doc.pages))
+	}
+	if !bytes.Contains(out, []byte(`(Notes \(draft\))`)) || !bytes.Contains(out, []byte(`(Chapter 1 \\ intro)`)) {
+		t.Fatalf("expected parentheses and backslashes to be escaped")
+	}
+
+	// startxref must point at the xref table, and every xref entry at its object.
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref does not point at the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Fatalf("xref entry %d does not point at %q", i+1, want)
+		}
+	}
+}
+
+func TestWrapPDFText_FitsWidth(t *testing.T) {
+	text := strings.Repeat("word ", 200) + strings.Repeat("x", 300)
+	for _, line := range wrapPDFText(text, false, 11, 200) {
+		if w := pdfTextWidth(line, false, 11); w > 200 {
+			t.Fatalf("line %q is %.1fpt wide, want <= 200", line, w)
+		}
+	}
+}
+
+func TestEncodeWinAnsi(t *testing.T) {
+	if got := encodeWinAnsi("café – “quoted” 日本"); got != "caf\xe9 \x96 \x93quoted\x94 ??" {
+		t.Fatalf("unexpected encoding %q", got)
+	}
+}
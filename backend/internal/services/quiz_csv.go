@@ -0,0 +1,280 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// quizCSVColumns is the column order of exported quiz CSVs. Imports match
+// columns by header name, so spreadsheets may reorder or omit the optional ones.
+var quizCSVColumns = []string{"question", "type", "options", "correct", "explanation", "topic", "difficulty"}
+
+// quizCSVOptionSeparator joins a question's options in the options column.
+const quizCSVOptionSeparator = "|"
+
+// QuizCSVSkippedRow reports an imported row that did not become a question.
+// Row is the spreadsheet line number, counting the header as row 1.
+type QuizCSVSkippedRow struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+// WriteQuizCSV writes one row per question, with the correct answer as an
+// option letter. Fill-in-the-blank rows list the accepted answers as the
+// correct answer, and matching rows list their pairs as the options.
+func WriteQuizCSV(w io.Writer, questions []models.QuizQuestion) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(quizCSVColumns); err != nil {
+		return err
+	}
+	for _, q := range questions {
+		correct := ""
+		if q.CorrectIndex >= 0 && q.CorrectIndex < len(q.Options) {
+			correct = optionLetter(q.CorrectIndex)
+		}
+		options := make([]string, len(q.Options))
+		for i, opt := range q.Options {
+			options[i] = strings.TrimSpace(opt)
+		}
+		switch q.Type {
+		case "fill_blank":
+			correct = strings.Join(q.AcceptedAnswers, quizCSVOptionSeparator)
+		case "matching":
+			// Each pair as "prompt = answer"; the order is the answer.
+			options = make([]string, len(q.Pairs))
+			for i, p := range q.Pairs {
+				options[i] = p.Prompt + " = " + p.Answer
+			}
+		}
+		row := []string{
+			q.Question,
+			q.Type,
+			strings.Join(options, quizCSVOptionSeparator),
+			correct,
+			q.Explanation,
+			q.Topic,
+			q.Difficulty,
+		}
+		for i := range row {
+			row[i] = escapeCSVFormula(row[i])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ParseQuizCSV reads quiz questions from a CSV with a header row. Rows that
+// are malformed are skipped and reported; the error is only for a file that
+// cannot be read as a question bank at all. At most MaxQuizQuestions
+// questions are returned.
+func ParseQuizCSV(r io.Reader) ([]models.QuizQuestion, []QuizCSVSkippedRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := columns[name]; !dup {
+			columns[name] = i
+		}
+	}
+	for _, required := range []string{"question", "correct"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("the CSV header must include a %q column", required)
+		}
+	}
+
+	var questions []models.QuizQuestion
+	var skipped []QuizCSVSkippedRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				skipped = append(skipped, QuizCSVSkippedRow{Row: parseErr.StartLine, Reason: "malformed CSV row"})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(questions) >= MaxQuizQuestions {
+			skipped = append(skipped, QuizCSVSkippedRow{Row: line, Reason: fmt.Sprintf("a quiz holds at most %d questions", MaxQuizQuestions)})
+			continue
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return unescapeCSVFormula(strings.TrimSpace(record[i]))
+		}
+		q, err := quizQuestionFromCSV(field)
+		if err != nil {
+			skipped = append(skipped, QuizCSVSkippedRow{Row: line, Reason: err.Error()})
+			continue
+		}
+		questions = append(questions, q)
+	}
+	return questions, skipped, nil
+}
+
+// quizQuestionFromCSV builds a question from one row and normalizes it the
+// same way generated questions are.
+func quizQuestionFromCSV(field func(string) string) (models.QuizQuestion, error) {
+	q := models.QuizQuestion{
+		Question:    field("question"),
+		Explanation: field("explanation"),
+		Topic:       field("topic"),
+	}
+	if q.Question == "" {
+		return q, errors.New("question is empty")
+	}
+
+	rawType := field("type")
+	q.Type = normalizeQuestionType(rawType)
+	if rawType != "" && q.Type == "" {
+		return q, fmt.Errorf("unsupported question type %q", rawType)
+	}
+	if q.Type == "essay" || q.Type == "fill_blank" || q.Type == "matching" {
+		return q, fmt.Errorf("%s questions cannot be imported from CSV", q.Type)
+	}
+
+	for _, opt := range strings.Split(field("options"), quizCSVOptionSeparator) {
+		if opt = strings.TrimSpace(opt); opt != "" {
+			q.Options = append(q.Options, opt)
+		}
+	}
+	correct := field("correct")
+	if q.Type == "" && len(q.Options) == 0 && isTrueFalseAnswer(correct) {
+		q.Type = "true_false"
+	}
+	if q.Type == "true_false" && len(q.Options) == 0 {
+		q.Options = []string{"True", "False"}
+	}
+	if q.Type == "" {
+		if isTrueFalseOptions(q.Options) {
+			q.Type = "true_false"
+		} else {
+			q.Type = "multiple_choice"
+		}
+	}
+
+	switch q.Type {
+	case "true_false":
+		if !isTrueFalseOptions(q.Options) {
+			return q, errors.New("true/false questions need the options True|False")
+		}
+	case "multiple_choice":
+		if len(q.Options) != 4 {
+			return q, fmt.Errorf("multiple-choice questions need exactly 4 options, got %d", len(q.Options))
+		}
+	}
+
+	index, err := parseCorrectOption(correct, q.Options)
+	if err != nil {
+		return q, err
+	}
+	q.CorrectIndex = index
+
+	difficulty := strings.ToLower(field("difficulty"))
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	if !isAllowedValue(difficulty, AllowedQuizDifficulties) {
+		return q, fmt.Errorf("unsupported difficulty %q", field("difficulty"))
+	}
+	q.Difficulty = difficulty
+
+	// A one-question mix keeps the row's own difficulty through validation.
+	normalized := validateQuizQuestions([]models.QuizQuestion{q}, models.GenerateQuizRequest{
+		NumQuestions:  1,
+		DifficultyMix: map[string]int{difficulty: 1},
+	})
+	if len(normalized) == 0 {
+		return q, errors.New("not a valid multiple-choice or true/false question")
+	}
+	return normalized[0], nil
+}
+
+// parseCorrectOption reads the correct column as an option letter (A, B, ...),
+// a 1-based option number, or the text of the correct option.
+func parseCorrectOption(value string, options []string) (int, error) {
+	if value == "" {
+		return 0, errors.New("correct answer is empty")
+	}
+	if len(value) == 1 {
+		if c := strings.ToUpper(value)[0]; c >= 'A' && c <= 'Z' {
+			if i := int(c - 'A'); i < len(options) {
+				return i, nil
+			}
+			return 0, fmt.Errorf("correct answer %q does not match any option", value)
+		}
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		if n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		return 0, fmt.Errorf("correct answer %q does not match any option", value)
+	}
+	for i, opt := range options {
+		if strings.EqualFold(opt, value) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("correct answer %q does not match any option", value)
+}
+
+func isTrueFalseAnswer(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value == "true" || value == "false"
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeCSVFormula stops spreadsheets from evaluating a cell as a formula.
+func escapeCSVFormula(v string) string {
+	if v != "" && strings.ContainsRune("=+-@", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// unescapeCSVFormula undoes escapeCSVFormula so exported files re-import
+// unchanged.
+func unescapeCSVFormula(v string) string {
+	if len(v) > 1 && v[0] == '\'' && strings.ContainsRune("=+-@", rune(v[1])) {
+		return v[1:]
+	}
+	return v
+}
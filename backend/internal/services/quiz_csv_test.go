@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestQuizCSV_RoundTrip(t *testing.T) {
+	questions := []models.QuizQuestion{
+		{Question: "What is 2+2?", Type: "multiple_choice", Options: []string{"3", "4", "5", "22"}, CorrectIndex: 1, Explanation: "-3 would be subtraction; \"4\", is right", Topic: "Math", Difficulty: "easy"},
+		{Question: "The sun is a star.", Type: "true_false", Options: []string{"True", "False"}, CorrectIndex: 0, Topic: "Astronomy", Difficulty: "hard"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteQuizCSV(&buf, questions); err != nil {
+		t.Fatalf("WriteQuizCSV: %v", err)
+	}
+	if !strings.Contains(buf.String(), "'-3 would") {
+		t.Fatalf("expected formula cell to be escaped, got %q", buf.String())
+	}
+
+	got, skipped, err := ParseQuizCSV(&buf)
+	if err != nil {
+		t.Fatalf("ParseQuizCSV: %v", err)
+	}
+	if len(skipped) != 0 {
+		t.Fatalf("expected no skipped rows, got %+v", skipped)
+	}
+	if !reflect.DeepEqual(got, questions) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, questions)
+	}
+}
+
+func TestParseQuizCSV_SkipsMalformedRows(t *testing.T) {
+	csv := "\ufeffQuestion,Correct,Options,Type\n" +
+		"Capital of France?,Paris,Berlin|Paris|Rome|Madrid,\n" +
+		"Too few options?,A,One|Two,mcq\n" +
+		"Water is wet.,false,,\n" +
+		",A,x|y|z|w,\n" +
+		"\n" +
+		"Out of range?,5,a|b|c|d,\n" +
+		"Odd type?,A,a|b|c|d,essay\n"
+
+	got, skipped, err := ParseQuizCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseQuizCSV: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 questions, got %d: %+v", len(got), got)
+	}
+	if got[0].Type != "multiple_choice" || got[0].CorrectIndex != 1 || got[0].Difficulty != "medium" {
+		t.Fatalf("unexpected first question: %+v", got[0])
+	}
+	if got[1].Type != "true_false" || got[1].CorrectIndex != 1 || !reflect.DeepEqual(got[1].Options, []string{"True", "False"}) {
+		t.Fatalf("unexpected true/false question: %+v", got[1])
+	}
+
+	var rows []int
+	for _, s := range skipped {
+		rows = append(rows, s.Row)
+	}
+	if want := []int{3, 5, 7, 8}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("expected skipped rows %v, got %+v", want, skipped)
+	}
+}
+
+func TestParseQuizCSV_RequiresColumns(t *testing.T) {
+	if _, _, err := ParseQuizCSV(strings.NewReader("question,options\nQ?,a|b|c|d\n")); err == nil {
+		t.Fatalf("expected an error for a missing correct column")
+	}
+	if _, _, err := ParseQuizCSV(strings.NewReader("")); err == nil {
+		t.Fatalf("expected an error for an empty file")
+	}
+}
+
+func TestParseCorrectOption(t *testing.T) {
+	options := []string{"Red", "Green", "Blue", "Yellow"}
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"C", 2, false},
+		{"b", 1, false},
+		{"4", 3, false},
+		{"green", 1, false},
+		{"E", 0, true},
+		{"0", 0, true},
+		{"Purple", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCorrectOption(tt.value, options)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("parseCorrectOption(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if err == nil && got != tt.want {
+			t.Fatalf("parseCorrectOption(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
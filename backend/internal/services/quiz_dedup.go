@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strings"
+	"unicode"
+
+	"lectura-backend/internal/models"
+)
+
+// Question-bank deduplication limits.
+const (
+	DefaultQuizDedupThreshold = 0.8
+	freshQuizDedupThreshold   = 0.5
+	maxQuizHistoryQuestions   = 500
+	maxQuizAvoidListed        = 20
+	maxFreshQuizAvoidListed   = 60
+	maxQuizReplacementRounds  = 2
+)
+
+// questionStopwords are ignored when comparing questions so that shared
+// phrasing ("what is the ...") does not make unrelated questions look alike.
+var questionStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "was": true, "were": true,
+	"be": true, "of": true, "in": true, "on": true, "to": true, "for": true, "and": true,
+	"or": true, "by": true, "with": true, "as": true, "at": true, "from": true, "that": true,
+	"this": true, "which": true, "what": true, "who": true, "whom": true, "when": true,
+	"where": true, "why": true, "how": true, "does": true, "do": true, "did": true,
+	"it": true, "its": true, "true": true, "false": true, "following": true,
+}
+
+// NormalizeQuestionText lowercases a question and reduces it to its words, so
+// trivially reworded copies (punctuation, spacing, case) compare equal.
+func NormalizeQuestionText(question string) string {
+	words := strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// QuestionHash returns the history key for a normalized question.
+func QuestionHash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func questionTermCounts(normalized string) map[string]int {
+	counts := map[string]int{}
+	for _, w := range strings.Fields(normalized) {
+		if !questionStopwords[w] {
+			counts[w]++
+		}
+	}
+	return counts
+}
+
+// questionSimilarity is the cosine similarity of two questions' term counts.
+func questionSimilarity(a, b map[string]int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for w, ca := range a {
+		normA += float64(ca * ca)
+		dot += float64(ca * b[w])
+	}
+	for _, cb := range b {
+		normB += float64(cb * cb)
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// questionDeduper rejects questions that are too similar to ones generated
+// before for the same summary or earlier in the same batch.
+type questionDeduper struct {
+	threshold float64
+	seen      map[string]bool
+	terms     []map[string]int
+}
+
+func newQuestionDeduper(history []string, threshold float64) *questionDeduper {
+	d := &questionDeduper{
+		threshold: threshold,
+		seen:      make(map[string]bool, len(history)),
+	}
+	for _, h := range history {
+		d.remember(NormalizeQuestionText(h))
+	}
+	return d
+}
+
+func (d *questionDeduper) remember(normalized string) {
+	d.seen[normalized] = true
+	d.terms = append(d.terms, questionTermCounts(normalized))
+}
+
+// admit records the question and reports whether it is novel enough to keep.
+func (d *questionDeduper) admit(question string) bool {
+	normalized := NormalizeQuestionText(question)
+	if normalized == "" || d.seen[normalized] {
+		return false
+	}
+	terms := questionTermCounts(normalized)
+	for _, prev := range d.terms {
+		if questionSimilarity(terms, prev) >= d.threshold {
+			return false
+		}
+	}
+	d.remember(normalized)
+	return true
+}
+
+// filter keeps novel questions in order and reports how many were rejected.
+func (d *questionDeduper) filter(questions []models.QuizQuestion) ([]models.QuizQuestion, int) {
+	kept := make([]models.QuizQuestion, 0, len(questions))
+	rejected := 0
+	for _, q := range questions {
+		if d.admit(q.Question) {
+			kept = append(kept, q)
+		} else {
+			rejected++
+		}
+	}
+	return kept, rejected
+}
+
+// quizDedupThreshold returns the similarity at or above which a question
+// counts as a repeat. Fresh requests use a stricter bar.
+func quizDedupThreshold(configured float64, fresh bool) float64 {
+	if configured <= 0 || configured > 1 {
+		configured = DefaultQuizDedupThreshold
+	}
+	if fresh && configured > freshQuizDedupThreshold {
+		return freshQuizDedupThreshold
+	}
+	return configured
+}
+
+// buildQuizAvoidSection lists earlier questions the model must not repeat.
+func buildQuizAvoidSection(previous []string, fresh bool) string {
+	limit := maxQuizAvoidListed
+	if fresh {
+		limit = maxFreshQuizAvoidListed
+	}
+	if len(previous) > limit {
+		previous = previous[:limit]
+	}
+	if len(previous) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nThese questions were already asked about this content. Do NOT repeat or paraphrase them:\n")
+	for _, p := range previous {
+		b.WriteString("- " + p + "\n")
+	}
+	if fresh {
+		b.WriteString("Prefer concepts, details and angles none of the questions above cover.\n")
+	}
+	return b.String()
+}
+
+// questionHistoryEntries returns parallel hash/text slices for recording.
+func questionHistoryEntries(questions []models.QuizQuestion) (hashes, texts []string) {
+	for _, q := range questions {
+		normalized := NormalizeQuestionText(q.Question)
+		if normalized == "" {
+			continue
+		}
+		hashes = append(hashes, QuestionHash(normalized))
+		texts = append(texts, normalized)
+	}
+	return hashes, texts
+}
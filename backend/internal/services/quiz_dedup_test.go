@@ -0,0 +1,89 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestNormalizeQuestionText(t *testing.T) {
+	got := NormalizeQuestionText("  What is   the Krebs-cycle's ROLE?! ")
+	if got != "what is the krebs cycle s role" {
+		t.Fatalf("unexpected normalization: %q", got)
+	}
+	if QuestionHash(got) != QuestionHash(NormalizeQuestionText("what is the krebs cycle's role")) {
+		t.Fatal("expected equal hashes for reworded punctuation")
+	}
+}
+
+func TestQuestionDeduper_RejectsRepeatsOfHistory(t *testing.T) {
+	d := newQuestionDeduper([]string{"what is the role of mitochondria in the cell"}, DefaultQuizDedupThreshold)
+
+	tests := []struct {
+		question string
+		want     bool
+	}{
+		{"What is the role of mitochondria in the cell?", false},
+		{"What role do mitochondria play in a cell?", false},
+		{"What is the capital of France?", true},
+		{"Which enzyme unwinds DNA during replication?", true},
+	}
+	for _, tt := range tests {
+		if got := d.admit(tt.question); got != tt.want {
+			t.Errorf("admit(%q) = %v, want %v", tt.question, got, tt.want)
+		}
+	}
+}
+
+func TestQuestionDeduper_RejectsRepeatsWithinBatch(t *testing.T) {
+	d := newQuestionDeduper(nil, DefaultQuizDedupThreshold)
+	kept, rejected := d.filter([]models.QuizQuestion{
+		{Question: "What does ATP synthase produce?"},
+		{Question: "What does ATP synthase produce"},
+		{Question: "Where does glycolysis take place?"},
+	})
+	if len(kept) != 2 || rejected != 1 {
+		t.Fatalf("expected 2 kept and 1 rejected, got %d kept and %d rejected", len(kept), rejected)
+	}
+}
+
+func TestQuizDedupThreshold(t *testing.T) {
+	tests := []struct {
+		configured float64
+		fresh      bool
+		want       float64
+	}{
+		{0, false, DefaultQuizDedupThreshold},
+		{1.5, false, DefaultQuizDedupThreshold},
+		{0.9, false, 0.9},
+		{0.9, true, freshQuizDedupThreshold},
+		{0.3, true, 0.3},
+	}
+	for _, tt := range tests {
+		if got := quizDedupThreshold(tt.configured, tt.fresh); got != tt.want {
+			t.Errorf("quizDedupThreshold(%v, %v) = %v, want %v", tt.configured, tt.fresh, got, tt.want)
+		}
+	}
+}
+
+func TestBuildQuizAvoidSection(t *testing.T) {
+	if got := buildQuizAvoidSection(nil, true); got != "" {
+		t.Fatalf("expected empty section without history, got %q", got)
+	}
+
+	previous := make([]string, maxFreshQuizAvoidListed+5)
+	for i := range previous {
+		previous[i] = "question"
+	}
+	if got := strings.Count(buildQuizAvoidSection(previous, false), "- question"); got != maxQuizAvoidListed {
+		t.Fatalf("expected %d listed questions, got %d", maxQuizAvoidListed, got)
+	}
+	fresh := buildQuizAvoidSection(previous, true)
+	if got := strings.Count(fresh, "- question"); got != maxFreshQuizAvoidListed {
+		t.Fatalf("expected %d listed questions in fresh mode, got %d", maxFreshQuizAvoidListed, got)
+	}
+	if !strings.Contains(fresh, "none of the questions above cover") {
+		t.Fatal("expected fresh mode to ask for new angles")
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// ValidateEditedQuizQuestion checks a hand-edited quiz question and
+// normalizes it the same way generated questions are. It returns the
+// normalized question and field errors; the question is only usable when
+// there are none.
+func ValidateEditedQuizQuestion(q models.QuizQuestion) (models.QuizQuestion, map[string]string) {
+	fields := map[string]string{}
+
+	q.Question = strings.TrimSpace(q.Question)
+	q.Explanation = strings.TrimSpace(q.Explanation)
+	q.Hint = strings.TrimSpace(q.Hint)
+	q.Topic = strings.TrimSpace(q.Topic)
+	q.Rubric = strings.TrimSpace(q.Rubric)
+	if q.Question == "" {
+		fields["question"] = "Question is required"
+	}
+	for i, opt := range q.Options {
+		q.Options[i] = strings.TrimSpace(opt)
+		if q.Options[i] == "" {
+			fields["options"] = "Options cannot be empty"
+		}
+	}
+
+	q.Type = normalizeQuestionType(q.Type)
+	switch q.Type {
+	case "multiple_choice":
+		if len(q.Options) != 4 {
+			fields["options"] = "Multiple-choice questions need exactly 4 options"
+		}
+	case "true_false":
+		if !isTrueFalseOptions(q.Options) {
+			fields["options"] = "True/false questions need the options True and False"
+		}
+	case "essay":
+		if len(q.Options) > 0 {
+			fields["options"] = "Essay questions have no options"
+		}
+		if q.Rubric == "" {
+			fields["rubric"] = "Essay questions need a rubric to be graded against"
+		}
+	case "fill_blank":
+		if len(q.Options) > 0 {
+			fields["options"] = "Fill-in-the-blank questions have no options"
+		}
+		if len(cleanAcceptedAnswers(q.AcceptedAnswers)) == 0 {
+			fields["accepted_answers"] = "Fill-in-the-blank questions need at least one accepted answer"
+		}
+	case "matching":
+		if len(q.Options) > 0 {
+			fields["options"] = "Matching questions have no options"
+		}
+		if _, ok := cleanMatchingPairs(q.Pairs); !ok || len(q.Pairs) > maxMatchingPairs {
+			fields["pairs"] = fmt.Sprintf("Matching questions need %d to %d complete pairs with distinct prompts and answers", minMatchingPairs, maxMatchingPairs)
+		}
+	default:
+		fields["type"] = "Type must be multiple_choice, true_false, essay, fill_blank or matching"
+	}
+	if (q.Type == "multiple_choice" || q.Type == "true_false") && (q.CorrectIndex < 0 || q.CorrectIndex >= len(q.Options)) {
+		fields["correct_index"] = "correct_index must point to one of the options"
+	}
+
+	difficulty := strings.ToLower(strings.TrimSpace(q.Difficulty))
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	if !isAllowedValue(difficulty, AllowedQuizDifficulties) {
+		fields["difficulty"] = "Difficulty must be one of: easy, medium, hard"
+	}
+	q.Difficulty = difficulty
+	if len(fields) > 0 {
+		return q, fields
+	}
+
+	// A one-question mix keeps the question's own difficulty.
+	normalized := validateQuizQuestions([]models.QuizQuestion{q}, models.GenerateQuizRequest{
+		NumQuestions:  1,
+		QuestionTypes: []string{q.Type},
+		DifficultyMix: map[string]int{difficulty: 1},
+	})
+	if len(normalized) == 0 {
+		return q, map[string]string{"question": "Not a valid quiz question"}
+	}
+	return normalized[0], nil
+}
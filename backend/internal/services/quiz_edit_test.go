@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestValidateEditedQuizQuestion(t *testing.T) {
+	q, fields := ValidateEditedQuizQuestion(models.QuizQuestion{
+		Question:     "  Is the sun a star? ",
+		Type:         "true-false",
+		Options:      []string{"False", "True"},
+		CorrectIndex: 1,
+		Difficulty:   "Hard",
+	})
+	if len(fields) != 0 {
+		t.Fatalf("expected a valid question, got %v", fields)
+	}
+	if q.Question != "Is the sun a star?" || q.Type != "true_false" || q.Difficulty != "hard" {
+		t.Fatalf("expected the question to be normalized, got %+v", q)
+	}
+	if q.Options[q.CorrectIndex] != "True" {
+		t.Fatalf("expected the correct answer to stay True, got %+v", q)
+	}
+}
+
+func TestValidateEditedQuizQuestion_Essay(t *testing.T) {
+	q, fields := ValidateEditedQuizQuestion(models.QuizQuestion{
+		Question: "Explain why the sky is blue.",
+		Type:     "open-ended",
+		Rubric:   " Mentions Rayleigh scattering. ",
+	})
+	if len(fields) != 0 {
+		t.Fatalf("expected a valid essay, got %v", fields)
+	}
+	if q.Type != "essay" || q.Rubric != "Mentions Rayleigh scattering." || len(q.Options) != 0 {
+		t.Fatalf("expected a normalized essay, got %+v", q)
+	}
+}
+
+func TestValidateEditedQuizQuestion_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		q     models.QuizQuestion
+		field string
+	}{
+		{"missing question", models.QuizQuestion{Type: "multiple_choice", Options: []string{"a", "b", "c", "d"}}, "question"},
+		{"three options", models.QuizQuestion{Question: "Q?", Type: "multiple_choice", Options: []string{"a", "b", "c"}}, "options"},
+		{"blank option", models.QuizQuestion{Question: "Q?", Type: "multiple_choice", Options: []string{"a", " ", "c", "d"}}, "options"},
+		{"unknown type", models.QuizQuestion{Question: "Q?", Type: "ordering", Options: []string{"a", "b", "c", "d"}}, "type"},
+		{"correct index out of range", models.QuizQuestion{Question: "Q?", Type: "multiple_choice", Options: []string{"a", "b", "c", "d"}, CorrectIndex: 4}, "correct_index"},
+		{"essay without rubric", models.QuizQuestion{Question: "Why?", Type: "essay"}, "rubric"},
+		{"essay with options", models.QuizQuestion{Question: "Why?", Type: "essay", Options: []string{"a"}, Rubric: "Covers X."}, "options"},
+		{"fill_blank without answers", models.QuizQuestion{Question: "The sun is a _____.", Type: "fill_blank", AcceptedAnswers: []string{" "}}, "accepted_answers"},
+		{"matching with two pairs", models.QuizQuestion{Question: "Match.", Type: "matching", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "b", Answer: "2"}}}, "pairs"},
+		{"matching with a repeated answer", models.QuizQuestion{Question: "Match.", Type: "matching", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "b", Answer: "1"}, {Prompt: "c", Answer: "3"}}}, "pairs"},
+		{"unknown difficulty", models.QuizQuestion{Question: "Q?", Type: "true_false", Options: []string{"True", "False"}, Difficulty: "extreme"}, "difficulty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, fields := ValidateEditedQuizQuestion(tt.q); fields[tt.field] == "" {
+				t.Fatalf("expected a %s error, got %v", tt.field, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+var quizDifficultyGuidance = map[string][]string{
+	"easy": {
+		"Easy = direct recall from explicit statements in the source.",
+		"Avoid multi-step inference, ambiguity, or trick wording.",
+	},
+	"medium": {
+		"Medium = basic application and comparison of concepts from the source.",
+	},
+	"hard": {
+		"Hard = deep analytical questions requiring synthesis across multiple parts of the content.",
+		"Use nuanced distinctions, implications, edge cases, and strong distractors.",
+	},
+}
+
+// quizDifficultyMix returns the requested question count per difficulty, or
+// nil when the quiz uses a single difficulty.
+func quizDifficultyMix(config models.GenerateQuizRequest) map[string]int {
+	var mix map[string]int
+	for level, count := range config.DifficultyMix {
+		level = strings.ToLower(strings.TrimSpace(level))
+		if count <= 0 || !isAllowedValue(level, AllowedQuizDifficulties) {
+			continue
+		}
+		if mix == nil {
+			mix = make(map[string]int)
+		}
+		mix[level] += count
+	}
+	return mix
+}
+
+func writeQuizDifficulty(b *strings.Builder, config models.GenerateQuizRequest) {
+	mix := quizDifficultyMix(config)
+	if mix == nil {
+		b.WriteString(fmt.Sprintf("Difficulty: %s\n", config.Difficulty))
+		for _, line := range quizDifficultyGuidance[config.Difficulty] {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("Set every item's difficulty field exactly to this requested difficulty value.\n")
+		return
+	}
+
+	var counts []string
+	for _, level := range AllowedQuizDifficulties {
+		if mix[level] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", mix[level], level))
+		}
+	}
+	b.WriteString("Difficulty mix: generate exactly " + strings.Join(counts, ", ") + " questions.\n")
+	for _, level := range AllowedQuizDifficulties {
+		if mix[level] > 0 {
+			for _, line := range quizDifficultyGuidance[level] {
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	b.WriteString("Set every item's difficulty field to the difficulty it was written for.\n")
+}
+
+// trimQuizToMix keeps at most the requested number of questions per
+// difficulty, in order. Without a mix the questions are returned unchanged.
+func trimQuizToMix(questions []models.QuizQuestion, mix map[string]int) []models.QuizQuestion {
+	if mix == nil {
+		return questions
+	}
+	counts := make(map[string]int, len(mix))
+	kept := make([]models.QuizQuestion, 0, len(questions))
+	for _, q := range questions {
+		if counts[q.Difficulty] >= mix[q.Difficulty] {
+			continue
+		}
+		counts[q.Difficulty]++
+		kept = append(kept, q)
+	}
+	return kept
+}
+
+// missingQuizMix is the part of the mix the questions do not fill yet, or nil
+// when every difficulty is covered.
+func missingQuizMix(questions []models.QuizQuestion, mix map[string]int) map[string]int {
+	counts := make(map[string]int, len(mix))
+	for _, q := range questions {
+		counts[q.Difficulty]++
+	}
+	var missing map[string]int
+	for level, want := range mix {
+		if counts[level] < want {
+			if missing == nil {
+				missing = make(map[string]int)
+			}
+			missing[level] = want - counts[level]
+		}
+	}
+	return missing
+}
+
+func validateDifficultyMix(fields map[string]string, req models.GenerateQuizRequest) {
+	if len(req.DifficultyMix) == 0 {
+		return
+	}
+	total := 0
+	for level, count := range req.DifficultyMix {
+		if !isAllowedValue(level, AllowedQuizDifficulties) {
+			fields["difficulty_mix"] = "difficulty_mix keys must be one of: " + strings.Join(AllowedQuizDifficulties, ", ")
+			return
+		}
+		if count < 0 {
+			fields["difficulty_mix"] = "difficulty_mix counts must not be negative"
+			return
+		}
+		total += count
+	}
+	if total != req.NumQuestions {
+		fields["difficulty_mix"] = fmt.Sprintf("difficulty_mix must add up to num_questions (%d), got %d", req.NumQuestions, total)
+	}
+}
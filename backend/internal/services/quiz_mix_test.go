@@ -0,0 +1,110 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func mixQuestion(text, difficulty string) models.QuizQuestion {
+	return models.QuizQuestion{
+		Question:   text,
+		Type:       "true_false",
+		Options:    []string{"True", "False"},
+		Difficulty: difficulty,
+	}
+}
+
+func TestBuildQuizPrompt_DifficultyMix(t *testing.T) {
+	config := models.GenerateQuizRequest{
+		NumQuestions:  10,
+		Difficulty:    "medium",
+		DifficultyMix: map[string]int{"hard": 3, "easy": 3, "medium": 4},
+	}
+	prompt := buildQuizPrompt(config, "content")
+
+	if !strings.Contains(prompt, "Difficulty mix: generate exactly 3 easy, 4 medium, 3 hard questions.") {
+		t.Fatalf("expected exact per-difficulty counts in prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Difficulty: medium") {
+		t.Fatalf("expected the mix to replace the single difficulty")
+	}
+}
+
+func TestBuildQuizPrompt_SingleDifficulty(t *testing.T) {
+	prompt := buildQuizPrompt(models.GenerateQuizRequest{NumQuestions: 5, Difficulty: "easy"}, "content")
+	if !strings.Contains(prompt, "Difficulty: easy\nEasy = direct recall") {
+		t.Fatalf("expected single difficulty guidance, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "exactly to this requested difficulty value") {
+		t.Fatalf("expected single difficulty rule")
+	}
+}
+
+func TestValidateQuizQuestions_BucketsByDifficulty(t *testing.T) {
+	questions := []models.QuizQuestion{
+		mixQuestion("e1", "easy"),
+		mixQuestion("e2", "Easy"),
+		mixQuestion("e3", "easy"),
+		mixQuestion("h1", "hard"),
+		mixQuestion("x1", "expert"),
+		mixQuestion("m1", "medium"),
+	}
+	config := models.GenerateQuizRequest{
+		NumQuestions:  4,
+		DifficultyMix: map[string]int{"easy": 2, "hard": 2},
+	}
+
+	got := validateQuizQuestions(questions, config)
+
+	var texts []string
+	for _, q := range got {
+		texts = append(texts, q.Question+":"+q.Difficulty)
+	}
+	if strings.Join(texts, ",") != "e1:easy,e2:easy,h1:hard" {
+		t.Fatalf("unexpected questions %v", texts)
+	}
+}
+
+func TestMissingQuizMix(t *testing.T) {
+	mix := map[string]int{"easy": 2, "hard": 2}
+	questions := []models.QuizQuestion{mixQuestion("e1", "easy"), mixQuestion("e2", "easy"), mixQuestion("h1", "hard")}
+
+	missing := missingQuizMix(questions, mix)
+	if len(missing) != 1 || missing["hard"] != 1 {
+		t.Fatalf("expected one hard question missing, got %v", missing)
+	}
+	if missing := missingQuizMix(append(questions, mixQuestion("h2", "hard")), mix); missing != nil {
+		t.Fatalf("expected nothing missing, got %v", missing)
+	}
+}
+
+func TestValidateQuizConfig_DifficultyMix(t *testing.T) {
+	tests := []struct {
+		name    string
+		mix     map[string]int
+		wantErr bool
+	}{
+		{"no mix", nil, false},
+		{"sums to total", map[string]int{"easy": 3, "medium": 4, "hard": 3}, false},
+		{"wrong total", map[string]int{"easy": 3, "hard": 3}, true},
+		{"unknown difficulty", map[string]int{"expert": 10}, true},
+		{"negative count", map[string]int{"easy": 12, "hard": -2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := ValidateQuizConfig(models.GenerateQuizRequest{
+				SummaryID:     uuid.New(),
+				NumQuestions:  10,
+				DifficultyMix: tt.mix,
+			})
+			if _, got := fields["difficulty_mix"]; got != tt.wantErr {
+				t.Fatalf("difficulty_mix error = %v, want %v (%v)", got, tt.wantErr, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// WriteQuizPDF renders a printable quiz. With includeKey the questions are
+// followed by an answer key (correct option, explanation and topic) on a new
+// page; without it the export is safe to hand to students.
+func WriteQuizPDF(w io.Writer, quiz *models.Quiz, questions []models.QuizQuestion, includeKey bool) error {
+	title := strings.TrimSpace(quiz.Title)
+	if title == "" {
+		title = "Untitled quiz"
+	}
+
+	doc := NewPDFDocument(title)
+	doc.Heading(title)
+	doc.Paragraph(fmt.Sprintf("%d questions", len(questions)))
+	if !includeKey {
+		doc.Paragraph("Name: ____________________________    Date: ______________")
+	}
+	doc.Space(12)
+
+	for i, q := range questions {
+		doc.Subheading(fmt.Sprintf("%d. %s", i+1, strings.TrimSpace(q.Question)))
+		for j, opt := range q.Options {
+			doc.Indented(fmt.Sprintf("%s) %s", optionLetter(j), strings.TrimSpace(opt)), false)
+		}
+		if q.Type == "matching" {
+			for j, p := range q.Pairs {
+				doc.Indented(fmt.Sprintf("%d. %s  ____", j+1, p.Prompt), false)
+			}
+			doc.Space(4)
+			for j, pi := range matchingAnswerOrder(q) {
+				doc.Indented(fmt.Sprintf("%s) %s", optionLetter(j), q.Pairs[pi].Answer), false)
+			}
+		} else if len(q.Options) == 0 {
+			doc.Indented("Answer: ____________________________", false)
+		}
+		doc.Space(10)
+	}
+
+	if includeKey {
+		doc.PageBreak()
+		doc.Heading("Answer Key")
+		for i, q := range questions {
+			doc.Subheading(fmt.Sprintf("%d. %s", i+1, quizAnswerText(q)))
+			if e := strings.TrimSpace(q.Explanation); e != "" {
+				doc.Indented(e, false)
+			}
+			if t := strings.TrimSpace(q.Topic); t != "" {
+				doc.Indented("Topic: "+t, false)
+			}
+			doc.Space(8)
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// ParseQuizQuestions decodes a quiz's stored questions.
+func ParseQuizQuestions(quiz *models.Quiz) ([]models.QuizQuestion, error) {
+	var questions []models.QuizQuestion
+	if len(quiz.QuestionsJSON) == 0 {
+		return questions, nil
+	}
+	if err := json.Unmarshal(quiz.QuestionsJSON, &questions); err != nil {
+		return nil, fmt.Errorf("decode quiz questions: %w", err)
+	}
+	return questions, nil
+}
+
+// QuizExportFileName builds a download name from the quiz title.
+func QuizExportFileName(title string, includeKey bool) string {
+	slug := quizExportSlug(title)
+	if includeKey {
+		return slug + "-answer-key.pdf"
+	}
+	return slug + ".pdf"
+}
+
+// QuizCSVFileName builds the download name of a quiz's CSV export.
+func QuizCSVFileName(title string) string {
+	return quizExportSlug(title) + ".csv"
+}
+
+func quizExportSlug(title string) string {
+	return exportTitleSlug(title, "quiz")
+}
+
+// exportTitleSlug turns a title into a file-name-safe slug, falling back to
+// fallback when nothing usable is left.
+func exportTitleSlug(title, fallback string) string {
+	slug := strings.Trim(exportSlugPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = fallback
+	}
+	return slug
+}
+
+func optionLetter(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return fmt.Sprintf("%d", i+1)
+}
+
+func quizAnswerText(q models.QuizQuestion) string {
+	if q.Type == "essay" && q.Rubric != "" {
+		return "Rubric: " + q.Rubric
+	}
+	if q.Type == "fill_blank" && len(q.AcceptedAnswers) > 0 {
+		return "Answer: " + strings.Join(q.AcceptedAnswers, " / ")
+	}
+	if q.Type == "matching" && len(q.Pairs) > 0 {
+		letters := make([]string, len(q.Pairs))
+		for j, pi := range matchingAnswerOrder(q) {
+			letters[pi] = optionLetter(j)
+		}
+		matches := make([]string, len(q.Pairs))
+		for j, letter := range letters {
+			matches[j] = fmt.Sprintf("%d-%s", j+1, letter)
+		}
+		return "Matches: " + strings.Join(matches, ", ")
+	}
+	if q.CorrectIndex >= 0 && q.CorrectIndex < len(q.Options) {
+		return fmt.Sprintf("%s) %s", optionLetter(q.CorrectIndex), strings.TrimSpace(q.Options[q.CorrectIndex]))
+	}
+	return "See explanation"
+}
+
+// matchingAnswerOrder lists the pair indexes of a matching question in the
+// order its answers are printed: alphabetically, so they do not line up with
+// their prompts.
+func matchingAnswerOrder(q models.QuizQuestion) []int {
+	order := make([]int, len(q.Pairs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return strings.ToLower(q.Pairs[order[a]].Answer) < strings.ToLower(q.Pairs[order[b]].Answer)
+	})
+	return order
+}
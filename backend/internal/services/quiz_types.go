@@ -0,0 +1,115 @@
+package services
+
+import (
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+const (
+	// maxAcceptedAnswers caps the answer variants of a fill_blank question.
+	maxAcceptedAnswers = 6
+	// A matching question has between minMatchingPairs and maxMatchingPairs
+	// pairs.
+	minMatchingPairs = 3
+	maxMatchingPairs = 8
+	// MaxFillBlankAnswerLength caps a saved fill_blank answer, in characters.
+	MaxFillBlankAnswerLength = 200
+)
+
+// FillBlankCorrect reports whether answer is one of the question's accepted
+// answers, ignoring case and surrounding whitespace.
+func FillBlankCorrect(q models.QuizQuestion, answer string) bool {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return false
+	}
+	for _, accepted := range q.AcceptedAnswers {
+		if strings.EqualFold(strings.TrimSpace(accepted), answer) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMatchingAnswer reports whether matches fits the question: one entry per
+// prompt, each an index into Pairs used at most once, or -1 for a prompt not
+// matched yet.
+func IsMatchingAnswer(q models.QuizQuestion, matches []int) bool {
+	if len(matches) != len(q.Pairs) {
+		return false
+	}
+	used := make([]bool, len(q.Pairs))
+	for _, m := range matches {
+		if m == -1 {
+			continue
+		}
+		if m < 0 || m >= len(q.Pairs) || used[m] {
+			return false
+		}
+		used[m] = true
+	}
+	return true
+}
+
+// MatchingCorrect reports whether every prompt of the question was matched
+// to its answer.
+func MatchingCorrect(q models.QuizQuestion, matches []int) bool {
+	if len(q.Pairs) == 0 || !IsMatchingAnswer(q, matches) {
+		return false
+	}
+	for i, m := range matches {
+		if m == -1 || !strings.EqualFold(q.Pairs[m].Answer, q.Pairs[i].Answer) {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanAcceptedAnswers trims a fill_blank question's answers and drops empty
+// and duplicate ones, keeping at most maxAcceptedAnswers.
+func cleanAcceptedAnswers(answers []string) []string {
+	seen := map[string]bool{}
+	cleaned := make([]string, 0, len(answers))
+	for _, a := range answers {
+		a = strings.TrimSpace(a)
+		key := strings.ToLower(a)
+		if a == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, a)
+		if len(cleaned) == maxAcceptedAnswers {
+			break
+		}
+	}
+	return cleaned
+}
+
+// cleanMatchingPairs trims a matching question's pairs, drops incomplete ones
+// and keeps at most maxMatchingPairs. It reports false when fewer than
+// minMatchingPairs are left or a prompt or answer repeats, since the
+// question would then have more than one right set of matches.
+func cleanMatchingPairs(pairs []models.MatchingPair) ([]models.MatchingPair, bool) {
+	prompts := map[string]bool{}
+	answers := map[string]bool{}
+	cleaned := make([]models.MatchingPair, 0, len(pairs))
+	for _, p := range pairs {
+		p.Prompt = strings.TrimSpace(p.Prompt)
+		p.Answer = strings.TrimSpace(p.Answer)
+		if p.Prompt == "" || p.Answer == "" {
+			continue
+		}
+		if len(cleaned) == maxMatchingPairs {
+			break
+		}
+		prompt, answer := strings.ToLower(p.Prompt), strings.ToLower(p.Answer)
+		if prompts[prompt] || answers[answer] {
+			return nil, false
+		}
+		prompts[prompt] = true
+		answers[answer] = true
+		cleaned = append(cleaned, p)
+	}
+	return cleaned, len(cleaned) >= minMatchingPairs
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestValidateQuizQuestions_FillBlankAndMatching(t *testing.T) {
+	pairs := []models.MatchingPair{{Prompt: " Nucleus ", Answer: "Holds DNA"}, {Prompt: "Ribosome", Answer: "Builds proteins"}, {Prompt: "Lysosome", Answer: "Digests waste"}, {Prompt: "", Answer: "Orphan"}}
+	questions := []models.QuizQuestion{
+		{Question: "The powerhouse of the cell is the _____.", Type: "fill-in-the-blank", Options: []string{"x"}, AcceptedAnswers: []string{" mitochondria", "Mitochondria", "", "mitochondrion"}},
+		{Question: "Blank with no answer _____.", Type: "fill_blank"},
+		{Question: "Match each organelle to its role.", Pairs: pairs},
+		{Question: "Match too few.", Type: "matching", Pairs: pairs[:2]},
+		{Question: "Match with a repeat.", Type: "matching", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "b", Answer: "1"}, {Prompt: "c", Answer: "2"}}},
+	}
+
+	valid := validateQuizQuestions(questions, models.GenerateQuizRequest{QuestionTypes: []string{"fill_blank", "matching"}})
+	if len(valid) != 2 {
+		t.Fatalf("expected 2 valid questions, got %d: %+v", len(valid), valid)
+	}
+	blank := valid[0]
+	if blank.Type != "fill_blank" || len(blank.Options) != 0 || strings.Join(blank.AcceptedAnswers, ",") != "mitochondria,mitochondrion" {
+		t.Fatalf("expected a normalized fill_blank question, got %+v", blank)
+	}
+	match := valid[1]
+	if match.Type != "matching" || len(match.Pairs) != 3 || match.Pairs[0].Prompt != "Nucleus" {
+		t.Fatalf("expected a normalized matching question, got %+v", match)
+	}
+}
+
+func TestMatchingCorrect(t *testing.T) {
+	q := models.QuizQuestion{Type: "matching", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "b", Answer: "2"}, {Prompt: "c", Answer: "3"}}}
+	tests := []struct {
+		matches []int
+		want    bool
+	}{
+		{[]int{0, 1, 2}, true},
+		{[]int{1, 0, 2}, false},
+		{[]int{0, 1, -1}, false},
+		{[]int{0, 1}, false},
+		{[]int{0, 0, 2}, false},
+	}
+	for _, tt := range tests {
+		if got := MatchingCorrect(q, tt.matches); got != tt.want {
+			t.Fatalf("MatchingCorrect(%v) = %v, want %v", tt.matches, got, tt.want)
+		}
+	}
+}
+
+func TestBuildQuizPrompt_FillBlankAndMatching(t *testing.T) {
+	prompt := buildQuizPrompt(models.GenerateQuizRequest{NumQuestions: 4, QuestionTypes: []string{"fill_blank", "matching"}}, "content")
+	for _, want := range []string{`"type": "fill_blank"|"matching"`, `"accepted_answers"`, `"pairs"`, "For fill_blank:", "For matching:"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("expected %s in the prompt:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, `"rubric"`) {
+		t.Fatalf("expected no essay fields without essay questions")
+	}
+}
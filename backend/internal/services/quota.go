@@ -3,17 +3,27 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type QuotaService struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	limits PlanCreditLimits
 }
 
 func NewQuotaService(pool *pgxpool.Pool) *QuotaService {
-	return &QuotaService{pool: pool}
+	return &QuotaService{pool: pool, limits: NewPlanCreditLimits(nil)}
+}
+
+// WithPlanLimits replaces the monthly credit limit of each plan.
+func (s *QuotaService) WithPlanLimits(limits PlanCreditLimits) *QuotaService {
+	s.limits = limits
+	return s
 }
 
 var JobCreditCost = map[string]int{
@@ -23,17 +33,36 @@ var JobCreditCost = map[string]int{
 	"presentation":   20,
 }
 
-func GetMonthlyCreditLimit(plan string) int {
-	switch plan {
-	case "ultra":
-		return 20000
-	case "pro":
-		return 4000
-	case "plus":
-		return 0 // Handled separately (BYOK, practically unlimited on our side)
-	default:
-		return 100 // Free
+// PlanCreditLimits maps a plan name to the credits its users may spend per
+// calendar month. The "plus" plan is not listed: it runs on the user's own
+// API key and is never limited.
+type PlanCreditLimits map[string]int
+
+// NewPlanCreditLimits builds limits from entries of the form "plan:credits"
+// on top of the defaults. Malformed entries are logged and skipped.
+func NewPlanCreditLimits(entries []string) PlanCreditLimits {
+	limits := PlanCreditLimits{"free": 100, "pro": 4000, "ultra": 20000}
+
+	for _, entry := range entries {
+		plan, credits, ok := strings.Cut(entry, ":")
+		plan = strings.ToLower(strings.TrimSpace(plan))
+		n, err := strconv.Atoi(strings.TrimSpace(credits))
+		if !ok || plan == "" || plan == "plus" || err != nil || n < 0 {
+			log.Printf("ignoring malformed plan credit limit %q", entry)
+			continue
+		}
+		limits[plan] = n
+	}
+
+	return limits
+}
+
+// Limit returns the plan's monthly credits. Unknown plans get the free limit.
+func (l PlanCreditLimits) Limit(plan string) int {
+	if n, ok := l[plan]; ok {
+		return n
 	}
+	return l["free"]
 }
 
 // GetUserCreditStatus returns (usedCredits, totalCredits, error)
@@ -43,7 +72,7 @@ func (s *QuotaService) GetUserCreditStatus(ctx context.Context, userID uuid.UUID
 		return 0, -1, nil
 	}
 
-	limit := GetMonthlyCreditLimit(plan)
+	limit := s.limits.Limit(plan)
 
 	query := `
 		SELECT 
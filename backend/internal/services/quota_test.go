@@ -0,0 +1,26 @@
+package services
+
+import "testing"
+
+func TestNewPlanCreditLimits_Defaults(t *testing.T) {
+	limits := NewPlanCreditLimits(nil)
+	if limits.Limit("free") != 100 || limits.Limit("pro") != 4000 || limits.Limit("ultra") != 20000 {
+		t.Fatalf("unexpected defaults: %v", limits)
+	}
+	if got := limits.Limit("legacy"); got != 100 {
+		t.Fatalf("expected an unknown plan to get the free limit, got %d", got)
+	}
+}
+
+func TestNewPlanCreditLimits_Overrides(t *testing.T) {
+	limits := NewPlanCreditLimits([]string{"free:50", " Team : 9000", "pro", "ultra:-1", "plus:10", "pro:lots"})
+	if limits.Limit("free") != 50 || limits.Limit("team") != 9000 {
+		t.Fatalf("expected overrides to apply, got %v", limits)
+	}
+	if limits.Limit("pro") != 4000 || limits.Limit("ultra") != 20000 {
+		t.Fatalf("expected malformed entries to keep defaults, got %v", limits)
+	}
+	if _, ok := limits["plus"]; ok {
+		t.Fatal("expected the plus plan to stay unlimited")
+	}
+}
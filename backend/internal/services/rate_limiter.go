@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaxGeminiRateSlots bounds how far the Gemini concurrency bucket can be
+// resized at runtime.
+const MaxGeminiRateSlots = 256
+
+// geminiRateWaitTimeout is how long a caller waits for a free slot before
+// giving up.
+const geminiRateWaitTimeout = 10 * time.Minute
+
+// GeminiRateStats is a point-in-time view of the Gemini concurrency bucket.
+type GeminiRateStats struct {
+	Capacity        int   `json:"capacity"`
+	InUse           int64 `json:"in_use"`
+	Available       int   `json:"available"`
+	Waiting         int64 `json:"waiting"`
+	TotalAcquired   int64 `json:"total_acquired"`
+	AcquireTimeouts int64 `json:"acquire_timeouts"`
+}
+
+// rateLimiter is a token bucket limiting concurrent Gemini calls. The channel
+// is allocated at MaxGeminiRateSlots so the bucket can grow without
+// reallocating; shrinking drains idle tokens and swallows busy ones as they
+// are released.
+type rateLimiter struct {
+	tokens      chan struct{}
+	waitTimeout time.Duration
+
+	mu           sync.Mutex
+	capacity     int
+	pendingDrain int
+
+	inUse    atomic.Int64
+	waiting  atomic.Int64
+	acquired atomic.Int64
+	timeouts atomic.Int64
+}
+
+func newRateLimiter(slots int) *rateLimiter {
+	if slots < 1 {
+		slots = 1
+	}
+	if slots > MaxGeminiRateSlots {
+		slots = MaxGeminiRateSlots
+	}
+	l := &rateLimiter{
+		tokens:      make(chan struct{}, MaxGeminiRateSlots),
+		waitTimeout: geminiRateWaitTimeout,
+		capacity:    slots,
+	}
+	for i := 0; i < slots; i++ {
+		l.tokens <- struct{}{}
+	}
+	return l
+}
+
+func (l *rateLimiter) acquire(ctx context.Context) error {
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	select {
+	case <-l.tokens:
+		l.inUse.Add(1)
+		l.acquired.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(l.waitTimeout):
+		n := l.timeouts.Add(1)
+		log.Printf("WARNING: timed out after %s waiting for a Gemini rate slot (%d timeouts so far)", l.waitTimeout, n)
+		return fmt.Errorf("timeout waiting for Gemini rate slot")
+	}
+}
+
+func (l *rateLimiter) release() {
+	l.inUse.Add(-1)
+
+	l.mu.Lock()
+	if l.pendingDrain > 0 {
+		l.pendingDrain--
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+
+	l.tokens <- struct{}{}
+}
+
+// resize changes the number of concurrent slots. Growing takes effect
+// immediately; shrinking below the number of busy slots completes as those
+// calls finish.
+func (l *rateLimiter) resize(slots int) error {
+	if slots < 1 || slots > MaxGeminiRateSlots {
+		return fmt.Errorf("rate slots must be between 1 and %d", MaxGeminiRateSlots)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delta := slots - l.capacity
+	for delta > 0 && l.pendingDrain > 0 {
+		l.pendingDrain--
+		delta--
+	}
+	for ; delta > 0; delta-- {
+		l.tokens <- struct{}{}
+	}
+	for delta < 0 {
+		select {
+		case <-l.tokens:
+			delta++
+		default:
+			l.pendingDrain += -delta
+			delta = 0
+		}
+	}
+
+	l.capacity = slots
+	return nil
+}
+
+func (l *rateLimiter) stats() GeminiRateStats {
+	l.mu.Lock()
+	capacity := l.capacity
+	l.mu.Unlock()
+
+	return GeminiRateStats{
+		Capacity:        capacity,
+		InUse:           l.inUse.Load(),
+		Available:       len(l.tokens),
+		Waiting:         l.waiting.Load(),
+		TotalAcquired:   l.acquired.Load(),
+		AcquireTimeouts: l.timeouts.Load(),
+	}
+}
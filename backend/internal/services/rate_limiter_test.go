@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_AcquireReleaseStats(t *testing.T) {
+	l := newRateLimiter(2)
+
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	got := l.stats()
+	if got.Capacity != 2 || got.InUse != 1 || got.Available != 1 || got.TotalAcquired != 1 {
+		t.Fatalf("unexpected stats after acquire: %+v", got)
+	}
+
+	l.release()
+	got = l.stats()
+	if got.InUse != 0 || got.Available != 2 || got.TotalAcquired != 1 {
+		t.Fatalf("unexpected stats after release: %+v", got)
+	}
+}
+
+func TestRateLimiter_TimeoutIsCounted(t *testing.T) {
+	l := newRateLimiter(1)
+	l.waitTimeout = 10 * time.Millisecond
+
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if err := l.acquire(context.Background()); err == nil {
+		t.Fatal("expected timeout with no free slots")
+	}
+	if got := l.stats(); got.AcquireTimeouts != 1 || got.Waiting != 0 {
+		t.Fatalf("unexpected stats after timeout: %+v", got)
+	}
+}
+
+func TestRateLimiter_CancelledContextIsNotATimeout(t *testing.T) {
+	l := newRateLimiter(1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.acquire(ctx); err == nil {
+		t.Fatal("expected context error")
+	}
+	if got := l.stats(); got.AcquireTimeouts != 0 {
+		t.Fatalf("cancellation should not count as timeout: %+v", got)
+	}
+}
+
+func TestRateLimiter_GrowUnblocksWaiter(t *testing.T) {
+	l := newRateLimiter(1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- l.acquire(context.Background()) }()
+
+	if err := l.resize(2); err != nil {
+		t.Fatalf("resize: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("waiter acquire: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not unblocked by growing the bucket")
+	}
+	if got := l.stats(); got.Capacity != 2 || got.InUse != 2 || got.Available != 0 {
+		t.Fatalf("unexpected stats after grow: %+v", got)
+	}
+}
+
+func TestRateLimiter_ShrinkBelowInUse(t *testing.T) {
+	l := newRateLimiter(3)
+	for i := 0; i < 3; i++ {
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+
+	if err := l.resize(1); err != nil {
+		t.Fatalf("resize: %v", err)
+	}
+
+	// The first two releases retire slots; only the last returns to the bucket.
+	l.release()
+	l.release()
+	if got := l.stats(); got.Available != 0 || got.InUse != 1 {
+		t.Fatalf("expected retired slots to be swallowed: %+v", got)
+	}
+	l.release()
+	if got := l.stats(); got.Capacity != 1 || got.Available != 1 || got.InUse != 0 {
+		t.Fatalf("unexpected stats after shrink settles: %+v", got)
+	}
+}
+
+func TestRateLimiter_ShrinkThenGrowCancelsPendingDrain(t *testing.T) {
+	l := newRateLimiter(2)
+	for i := 0; i < 2; i++ {
+		if err := l.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+
+	if err := l.resize(1); err != nil {
+		t.Fatalf("shrink: %v", err)
+	}
+	if err := l.resize(2); err != nil {
+		t.Fatalf("grow: %v", err)
+	}
+	l.release()
+	l.release()
+	if got := l.stats(); got.Capacity != 2 || got.Available != 2 {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+}
+
+func TestRateLimiter_ResizeRejectsOutOfRange(t *testing.T) {
+	l := newRateLimiter(2)
+	for _, n := range []int{0, -1, MaxGeminiRateSlots + 1} {
+		if err := l.resize(n); err == nil {
+			t.Fatalf("expected error resizing to %d", n)
+		}
+	}
+	if got := l.stats(); got.Capacity != 2 || got.Available != 2 {
+		t.Fatalf("rejected resize changed the bucket: %+v", got)
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Masks written in place of redacted personal data.
+const (
+	RedactedEmail  = "[EMAIL]"
+	RedactedPhone  = "[PHONE]"
+	RedactedNumber = "[NUMBER]"
+	RedactedName   = "[NAME]"
+)
+
+const (
+	// nameScanChunkChars is how much transcript one name-detection call reads.
+	nameScanChunkChars = 50000
+	// maxNameScanChunks caps the calls per transcript; names found in the
+	// scanned part are still masked wherever they appear later.
+	maxNameScanChunks = 8
+)
+
+var (
+	redactEmailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`)
+	// Grouped digits with an optional country code or area code in brackets,
+	// e.g. "+1 (555) 123-4567" or "020 7946 0958".
+	redactPhonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{1,4}\)[\s.\-]?|\d{2,5}[\s.\-])\d{2,4}(?:[\s.\-]\d{2,4}){1,3}`)
+	// Card, account and ID numbers, and phone numbers written without spaces.
+	redactDigitsPattern = regexp.MustCompile(`\d{9,}`)
+	redactDatePattern   = regexp.MustCompile(`^(?:\d{4}[./\-]\d{1,2}[./\-]\d{1,2}|\d{1,2}[./\-]\d{1,2}[./\-]\d{2,4})$`)
+	redactYearsPattern  = regexp.MustCompile(`^(?:1\d{3}|20\d{2})(?:[\s.\-]+(?:1\d{3}|20\d{2}))+$`)
+)
+
+// RedactPII masks email addresses, phone numbers and long digit sequences in
+// text. It returns the masked text and how many values were masked. Dates and
+// runs of years, which look like grouped digits, are left alone.
+func RedactPII(text string) (string, int) {
+	masked := 0
+	text = redactEmailPattern.ReplaceAllStringFunc(text, func(string) string {
+		masked++
+		return RedactedEmail
+	})
+	text = replaceStandalone(text, redactPhonePattern, func(match string) string {
+		if redactDatePattern.MatchString(match) || redactYearsPattern.MatchString(match) {
+			return ""
+		}
+		switch digits := countDigits(match); {
+		case digits < 7:
+			return ""
+		case digits <= 15:
+			return RedactedPhone
+		default:
+			// Grouped card and account numbers.
+			return RedactedNumber
+		}
+	}, &masked)
+	text = replaceStandalone(text, redactDigitsPattern, maskWith(RedactedNumber), &masked)
+	return text, masked
+}
+
+func maskWith(mask string) func(string) string {
+	return func(string) string { return mask }
+}
+
+// replaceStandalone replaces each match of pattern with the mask returned
+// for it, leaving matches with an empty mask and any that run on from a
+// letter or digit (part of a longer token, such as a hash or a product code).
+func replaceStandalone(text string, pattern *regexp.Regexp, mask func(string) string, count *int) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if !wordEdge(text, start, end) {
+			continue
+		}
+		m := mask(text[start:end])
+		if m == "" {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(m)
+		last = end
+		*count++
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// wordEdge reports whether text[start:end] is not joined to a letter or digit
+// on either side.
+func wordEdge(text string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(text[:start])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[end:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// RedactNames masks each whole-word occurrence of names in text and returns
+// the masked text and how many occurrences were masked. Longer names are
+// masked first so "Jane Doe" is not left as "[NAME] Doe".
+func RedactNames(text string, names []string) (string, int) {
+	sorted := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		// Skip the masks themselves, in case the model lists them.
+		if utf8.RuneCountInString(name) >= 2 && !strings.Contains(name, "[") {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	masked := 0
+	for _, name := range sorted {
+		text = replaceStandalone(text, regexp.MustCompile(regexp.QuoteMeta(name)), maskWith(RedactedName), &masked)
+	}
+	return text, masked
+}
+
+// DetectPersonNames asks Gemini for the names of private individuals in a
+// transcript, as written. Long transcripts are scanned in chunks up to
+// maxNameScanChunks.
+func (s *GeminiService) DetectPersonNames(ctx context.Context, transcript string) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	rest := transcript
+	for i := 0; i < maxNameScanChunks && strings.TrimSpace(rest) != ""; i++ {
+		chunk := clipText(rest, nameScanChunkChars)
+		rest = rest[len(chunk):]
+
+		var found []string
+		if err := s.detectPersonNamesChunk(ctx, chunk, &found); err != nil {
+			return nil, err
+		}
+		for _, name := range found {
+			if name = strings.TrimSpace(name); name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+func (s *GeminiService) detectPersonNamesChunk(ctx context.Context, chunk string, found *[]string) error {
+	if err := s.acquireRate(ctx); err != nil {
+		return err
+	}
+	defer s.releaseRate()
+
+	prompt := fmt.Sprintf(`List the names of private individuals in the lecture transcript below: students, audience members, callers, and anyone whose personal details are mentioned.
+Do not include public or historical figures, cited authors, or names of places, organizations, theories, laws or products.
+Write each name exactly as it appears in the text, including each different form (for example "Jane Doe" and "Jane").
+Return ONLY a JSON array of strings, or [] if there are none.
+
+TRANSCRIPT:
+%s`, chunk)
+	return s.generateJSONArray(ctx, "name redaction", prompt, found)
+}
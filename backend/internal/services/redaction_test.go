@@ -0,0 +1,41 @@
+package services
+
+import "testing"
+
+func TestRedactPII(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   string
+		masked int
+	}{
+		{"email", "Write to jane.doe+notes@uni.example.edu after class.", "Write to [EMAIL] after class.", 1},
+		{"phone with country code", "Call +1 (555) 123-4567 today.", "Call [PHONE] today.", 1},
+		{"uk phone", "The office is on 020 7946 0958.", "The office is on [PHONE].", 1},
+		{"card number", "Card 4111 1111 1111 1111 expires soon.", "Card [NUMBER] expires soon.", 1},
+		{"long digit run", "Student ID 2023004512 was enrolled.", "Student ID [NUMBER] was enrolled.", 1},
+		{"date kept", "The exam is on 2024-01-15 at noon.", "The exam is on 2024-01-15 at noon.", 0},
+		{"dotted date kept", "Due 15.01.2024.", "Due 15.01.2024.", 0},
+		{"years kept", "The wars of 1914 1918 and 1939 1945 reshaped Europe.", "The wars of 1914 1918 and 1939 1945 reshaped Europe.", 0},
+		{"short numbers kept", "Chapter 12 covers pages 100-120.", "Chapter 12 covers pages 100-120.", 0},
+		{"inside a token kept", "Commit abc1234567890def fixed it.", "Commit abc1234567890def fixed it.", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, masked := RedactPII(tt.in)
+			if got != tt.want || masked != tt.masked {
+				t.Fatalf("RedactPII(%q) = %q, %d; want %q, %d", tt.in, got, masked, tt.want, tt.masked)
+			}
+		})
+	}
+}
+
+func TestRedactNames(t *testing.T) {
+	in := "Jane Doe asked a question. Thanks, Jane! Janet answered, and Анна agreed."
+	got, masked := RedactNames(in, []string{"Jane", "Jane Doe", "Анна", " ", "[EMAIL]"})
+	want := "[NAME] asked a question. Thanks, [NAME]! Janet answered, and [NAME] agreed."
+	if got != want || masked != 3 {
+		t.Fatalf("got %q (%d masked), want %q (3 masked)", got, masked, want)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ErrBlockedBySafety means Gemini refused the content because of its safety
+// filters. The same input is refused again on retry.
+var ErrBlockedBySafety = errors.New("this content couldn't be processed due to safety filters")
+
+// JobErrorBlockedBySafety is the job error code recorded for
+// ErrBlockedBySafety failures.
+const JobErrorBlockedBySafety = "blocked_by_safety"
+
+// safetyBlockReason describes why Gemini blocked a call, or returns "" when
+// it did not. The genai client reports blocks either as a *genai.BlockedError
+// or as a response whose prompt feedback or finish reason says so.
+func safetyBlockReason(resp *genai.GenerateContentResponse, err error) string {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		if reason := promptBlockReason(blocked.PromptFeedback); reason != "" {
+			return reason
+		}
+		if blocked.Candidate != nil {
+			return candidateBlockReason(blocked.Candidate)
+		}
+		return "response blocked"
+	}
+	if resp == nil {
+		return ""
+	}
+	if reason := promptBlockReason(resp.PromptFeedback); reason != "" {
+		return reason
+	}
+	for _, cand := range resp.Candidates {
+		if cand == nil {
+			continue
+		}
+		switch cand.FinishReason {
+		case genai.FinishReasonSafety, genai.FinishReasonRecitation:
+			return candidateBlockReason(cand)
+		}
+	}
+	return ""
+}
+
+func promptBlockReason(feedback *genai.PromptFeedback) string {
+	if feedback == nil || feedback.BlockReason == genai.BlockReasonUnspecified {
+		return ""
+	}
+	return "prompt blocked: " + feedback.BlockReason.String() + blockedCategories(feedback.SafetyRatings)
+}
+
+func candidateBlockReason(cand *genai.Candidate) string {
+	return "response stopped: " + cand.FinishReason.String() + blockedCategories(cand.SafetyRatings)
+}
+
+// blockedCategories lists the harm categories that triggered a block, as a
+// suffix for the reason ("" when Gemini did not say).
+func blockedCategories(ratings []*genai.SafetyRating) string {
+	var categories []string
+	for _, rating := range ratings {
+		if rating != nil && rating.Blocked {
+			categories = append(categories, rating.Category.String())
+		}
+	}
+	if len(categories) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(categories, ", ") + ")"
+}
+
+// safetyBlockedError wraps ErrBlockedBySafety with the reason Gemini gave.
+func safetyBlockedError(reason string) error {
+	return fmt.Errorf("%w [%s]", ErrBlockedBySafety, reason)
+}
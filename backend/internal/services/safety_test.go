@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+func TestSafetyBlockReason(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *genai.GenerateContentResponse
+		err  error
+		want string
+	}{
+		{"nothing blocked", &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonStop}}}, nil, ""},
+		{"other error", nil, errors.New("503 unavailable"), ""},
+		{
+			"prompt blocked",
+			&genai.GenerateContentResponse{PromptFeedback: &genai.PromptFeedback{
+				BlockReason:   genai.BlockReasonSafety,
+				SafetyRatings: []*genai.SafetyRating{{Category: genai.HarmCategoryHarassment, Blocked: true}},
+			}},
+			nil,
+			"prompt blocked",
+		},
+		{"candidate stopped", &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonSafety}}}, nil, "response stopped"},
+		{"blocked error", nil, fmt.Errorf("call failed: %w", &genai.BlockedError{Candidate: &genai.Candidate{FinishReason: genai.FinishReasonSafety}}), "response stopped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := safetyBlockReason(tt.resp, tt.err)
+			if tt.want == "" && got != "" {
+				t.Fatalf("expected no block, got %q", got)
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Fatalf("expected reason starting with %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSafetyBlockedError_WrapsSentinel(t *testing.T) {
+	err := fmt.Errorf("STT fallback transcription failed: %w", safetyBlockedError("prompt blocked: SAFETY"))
+	if !errors.Is(err, ErrBlockedBySafety) {
+		t.Fatalf("expected ErrBlockedBySafety, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "prompt blocked: SAFETY") {
+		t.Fatalf("expected the reason in the message, got %q", err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Limits for user-editable metadata.
+const (
+	MaxTags      = 10
+	MaxTagLength = 50
+	MaxBioLength = 300
+)
+
+// markupTagPattern matches tags, closing tags, comments and declarations: a
+// '<' directly followed by a letter, '/', '!' or '?'. A '<' used as
+// less-than, as in "a < b", is left alone.
+var markupTagPattern = regexp.MustCompile(`(?s)<[a-zA-Z/!?][^>]*>`)
+
+// StripMarkup removes HTML tags and control characters from user input. Line
+// breaks are kept; values are still HTML-escaped wherever they are rendered.
+func StripMarkup(s string) string {
+	s = markupTagPattern.ReplaceAllString(s, "")
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// SanitizeLine strips markup and collapses all whitespace, for single-line
+// values such as titles, tags and names.
+func SanitizeLine(s string) string {
+	return strings.Join(strings.Fields(StripMarkup(s)), " ")
+}
+
+// SanitizeTags cleans each tag with SanitizeLine and drops empty and
+// case-insensitive duplicate tags, keeping the first spelling.
+func SanitizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = SanitizeLine(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// ValidateTags checks already-sanitized tags against MaxTags and MaxTagLength.
+// It returns a user-facing message, or "" when the tags are valid.
+func ValidateTags(tags []string) string {
+	if len(tags) > MaxTags {
+		return fmt.Sprintf("At most %d tags are allowed", MaxTags)
+	}
+	for _, tag := range tags {
+		if len([]rune(tag)) > MaxTagLength {
+			return fmt.Sprintf("Each tag must be %d characters or fewer", MaxTagLength)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripMarkup(t *testing.T) {
+	cases := map[string]string{
+		"  plain  ":                         "plain",
+		"<b>bold</b> text":                  "bold text",
+		"<img src=x onerror=alert(1)>hi":    "hi",
+		"line one\nline two":                "line one\nline two",
+		"null\x00byte":                      "nullbyte",
+		"a < b and c > d":                   "a < b and c > d",
+		"<a href=\"javascript:x\">link</a>": "link",
+		"<!-- note -->kept":                 "kept",
+		"x<3 and y>2":                       "x<3 and y>2",
+	}
+	for in, want := range cases {
+		if got := StripMarkup(in); got != want {
+			t.Fatalf("StripMarkup(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSanitizeTags_DropsEmptyAndDuplicates(t *testing.T) {
+	got := SanitizeTags([]string{" Physics ", "<b>physics</b>", "", "Quantum\nMechanics"})
+	if len(got) != 2 || got[0] != "Physics" || got[1] != "Quantum Mechanics" {
+		t.Fatalf("unexpected tags: %#v", got)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	if msg := ValidateTags([]string{"a", "b"}); msg != "" {
+		t.Fatalf("expected valid tags, got %q", msg)
+	}
+	if msg := ValidateTags(make([]string, MaxTags+1)); msg == "" {
+		t.Fatal("expected too many tags to be rejected")
+	}
+	if msg := ValidateTags([]string{strings.Repeat("x", MaxTagLength+1)}); msg == "" {
+		t.Fatal("expected an overlong tag to be rejected")
+	}
+}
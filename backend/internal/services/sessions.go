@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/models"
+)
+
+// maxSessionUserAgentLength caps the User-Agent kept on a session.
+const maxSessionUserAgentLength = 512
+
+// errSessionSignedOut rejects a refresh token whose session was revoked or
+// that predates sessions.
+var errSessionSignedOut = &UnauthorizedError{Message: "This session was signed out. Please log in again."}
+
+// rotateSessionScript stores a session's new refresh token only if the
+// session is still in the hash, so a refresh racing RevokeSession or
+// LogoutAll cannot bring the session back.
+//
+// KEYS[1] sessions hash, KEYS[2] new "refresh:<token>" key
+// ARGV[1] session ID, ARGV[2] session JSON, ARGV[3] token value, ARGV[4] TTL seconds
+var rotateSessionScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[3], 'EX', ARGV[4])
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+return 1
+`)
+
+// storedSession is a session as kept in the user's sessions hash, with the
+// refresh token it currently holds.
+type storedSession struct {
+	models.Session
+	RefreshToken string `json:"refresh_token"`
+}
+
+type sessionClientKey struct{}
+
+type sessionClient struct {
+	userAgent string
+	ip        string
+}
+
+// WithSessionClient records the caller's User-Agent and IP on the returned
+// context, so tokens issued with it are tagged with the device.
+func WithSessionClient(ctx context.Context, userAgent, ip string) context.Context {
+	return context.WithValue(ctx, sessionClientKey{}, sessionClient{
+		userAgent: clipText(strings.TrimSpace(userAgent), maxSessionUserAgentLength),
+		ip:        strings.TrimSpace(ip),
+	})
+}
+
+func sessionClientFrom(ctx context.Context) sessionClient {
+	client, _ := ctx.Value(sessionClientKey{}).(sessionClient)
+	return client
+}
+
+// legacyRefreshTokensKey names the set of refresh tokens tracked before
+// sessions replaced it. It is only read to revoke what it still lists.
+func legacyRefreshTokensKey(userID uuid.UUID) string {
+	return "refresh_tokens:" + userID.String()
+}
+
+// userSessionsKey names the hash of a user's sessions, keyed by session ID.
+func userSessionsKey(userID uuid.UUID) string {
+	return "sessions:" + userID.String()
+}
+
+// refreshTokenValue is what "refresh:<token>" holds: the user and session the
+// token belongs to.
+func refreshTokenValue(userID, sessionID uuid.UUID) string {
+	return userID.String() + ":" + sessionID.String()
+}
+
+// parseRefreshTokenValue reads a refresh token's owner. Tokens issued before
+// sessions existed hold only the user ID and come back with uuid.Nil as the
+// session; RefreshToken refuses them.
+func parseRefreshTokenValue(value string) (uuid.UUID, uuid.UUID, error) {
+	userPart, sessionPart, hasSession := strings.Cut(value, ":")
+	userID, err := uuid.Parse(userPart)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if !hasSession {
+		return userID, uuid.Nil, nil
+	}
+	sessionID, err := uuid.Parse(sessionPart)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	return userID, sessionID, nil
+}
+
+func (s *AuthService) getSession(ctx context.Context, userID, sessionID uuid.UUID) (*storedSession, error) {
+	raw, err := s.redis.HGet(ctx, userSessionsKey(userID), sessionID.String()).Result()
+	if err != nil {
+		return nil, err
+	}
+	var session storedSession
+	if err := json.Unmarshal([]byte(raw), &session); err != nil {
+		return nil, fmt.Errorf("invalid session %s: %w", sessionID, err)
+	}
+	return &session, nil
+}
+
+// loadSessions returns the user's sessions whose refresh token is still live.
+// Sessions that expired without being revoked are dropped from the hash.
+func (s *AuthService) loadSessions(ctx context.Context, userID uuid.UUID) ([]storedSession, error) {
+	key := userSessionsKey(userID)
+	all, err := s.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	cutoff := time.Now().Add(-refreshTokenTTL)
+	sessions := make([]storedSession, 0, len(all))
+	var expired []string
+	for field, raw := range all {
+		var session storedSession
+		if err := json.Unmarshal([]byte(raw), &session); err != nil || session.LastUsedAt.Before(cutoff) {
+			expired = append(expired, field)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	if len(expired) > 0 {
+		s.redis.HDel(ctx, key, expired...)
+	}
+	return sessions, nil
+}
+
+// ListSessions returns the user's signed-in devices, most recently used first.
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	stored, err := s.loadSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.Session, len(stored))
+	for i, session := range stored {
+		sessions[i] = session.Session
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt) })
+	return sessions, nil
+}
+
+// RevokeSession signs one device out by deleting its refresh token. Its
+// access token stays valid until it expires.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.getSession(ctx, userID, sessionID)
+	if errors.Is(err, redis.Nil) {
+		return &NotFoundError{Message: "Session not found"}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up session: %w", err)
+	}
+
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, "refresh:"+session.RefreshToken)
+		pipe.HDel(ctx, userSessionsKey(userID), sessionID.String())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// LogoutAll signs every device of the user out.
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return s.revokeRefreshTokens(ctx, userID)
+}
+
+// revokeRefreshTokens deletes every refresh token issued to the user,
+// including those still listed in the set used before sessions.
+func (s *AuthService) revokeRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	key := userSessionsKey(userID)
+	all, err := s.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	legacyKey := legacyRefreshTokensKey(userID)
+	legacy, err := s.redis.SMembers(ctx, legacyKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list refresh tokens: %w", err)
+	}
+
+	keys := make([]string, 0, len(all)+len(legacy)+2)
+	for _, raw := range all {
+		var session storedSession
+		if err := json.Unmarshal([]byte(raw), &session); err == nil && session.RefreshToken != "" {
+			keys = append(keys, "refresh:"+session.RefreshToken)
+		}
+	}
+	for _, token := range legacy {
+		keys = append(keys, "refresh:"+token)
+	}
+	keys = append(keys, key, legacyKey)
+	if err := s.redis.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// storeSession issues a new refresh token for the session and saves the
+// session with the caller's device details. A nil session starts a new one;
+// an existing session that has been revoked meanwhile is not restored.
+func (s *AuthService) storeSession(ctx context.Context, userID uuid.UUID, session *models.Session) (string, error) {
+	refreshToken, err := GenerateToken(64)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	isNew := session == nil
+	if isNew {
+		session = &models.Session{ID: uuid.New(), CreatedAt: now}
+	}
+	stored := storedSession{Session: *session, RefreshToken: refreshToken}
+	stored.LastUsedAt = now
+	client := sessionClientFrom(ctx)
+	if client.userAgent != "" {
+		stored.UserAgent = client.userAgent
+	}
+	if client.ip != "" {
+		stored.IP = client.ip
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return "", err
+	}
+
+	key := userSessionsKey(userID)
+	if !isNew {
+		kept, err := rotateSessionScript.Run(ctx, s.redis,
+			[]string{key, "refresh:" + refreshToken},
+			stored.ID.String(), raw, refreshTokenValue(userID, stored.ID), int(refreshTokenTTL/time.Second),
+		).Int()
+		if err != nil {
+			return "", fmt.Errorf("failed to store refresh token: %w", err)
+		}
+		if kept == 0 {
+			return "", errSessionSignedOut
+		}
+		return refreshToken, nil
+	}
+
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "refresh:"+refreshToken, refreshTokenValue(userID, stored.ID), refreshTokenTTL)
+		pipe.HSet(ctx, key, stored.ID.String(), raw)
+		pipe.Expire(ctx, key, refreshTokenTTL)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return refreshToken, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/models"
+)
+
+func TestParseRefreshTokenValue(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	gotUser, gotSession, err := parseRefreshTokenValue(refreshTokenValue(userID, sessionID))
+	if err != nil || gotUser != userID || gotSession != sessionID {
+		t.Fatalf("round trip = %s, %s, %v; want %s, %s", gotUser, gotSession, err, userID, sessionID)
+	}
+
+	// Tokens issued before sessions hold only the user ID.
+	gotUser, gotSession, err = parseRefreshTokenValue(userID.String())
+	if err != nil || gotUser != userID || gotSession != uuid.Nil {
+		t.Fatalf("legacy value = %s, %s, %v; want %s with no session", gotUser, gotSession, err, userID)
+	}
+
+	for _, value := range []string{"", "nope", userID.String() + ":nope"} {
+		if _, _, err := parseRefreshTokenValue(value); err == nil {
+			t.Fatalf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestWithSessionClient_ClipsUserAgent(t *testing.T) {
+	ctx := WithSessionClient(context.Background(), strings.Repeat("a", 2000), " 203.0.113.7 ")
+
+	client := sessionClientFrom(ctx)
+	if len(client.userAgent) != maxSessionUserAgentLength {
+		t.Fatalf("expected user agent clipped to %d bytes, got %d", maxSessionUserAgentLength, len(client.userAgent))
+	}
+	if client.ip != "203.0.113.7" {
+		t.Fatalf("expected trimmed IP, got %q", client.ip)
+	}
+	if got := sessionClientFrom(context.Background()); got != (sessionClient{}) {
+		t.Fatalf("expected no client on a bare context, got %+v", got)
+	}
+}
+
+func TestRevokeSession_RedisUnavailable_ReturnsError(t *testing.T) {
+	svc := &AuthService{
+		redis: redis.NewClient(&redis.Options{
+			Addr:         "127.0.0.1:0",
+			DialTimeout:  10 * time.Millisecond,
+			ReadTimeout:  10 * time.Millisecond,
+			WriteTimeout: 10 * time.Millisecond,
+		}),
+	}
+
+	err := svc.RevokeSession(context.Background(), uuid.New(), uuid.New())
+	if err == nil {
+		t.Fatalf("expected an error when redis is unavailable")
+	}
+	if _, ok := err.(*NotFoundError); ok {
+		t.Fatalf("redis failures must not be reported as a missing session")
+	}
+}
+
+// cannedRedisHook answers commands from canned replies instead of a server
+// and records the name and arguments of each.
+type cannedRedisHook struct {
+	replies  map[string]func(redis.Cmder)
+	commands [][]interface{}
+}
+
+func (h *cannedRedisHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *cannedRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.commands = append(h.commands, cmd.Args())
+		if reply, ok := h.replies[cmd.Name()]; ok {
+			reply(cmd)
+		}
+		return cmd.Err()
+	}
+}
+
+func (h *cannedRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.commands = append(h.commands, cmd.Args())
+		}
+		return nil
+	}
+}
+
+func (h *cannedRedisHook) sent(name string) []interface{} {
+	for _, args := range h.commands {
+		if len(args) > 0 && args[0] == name {
+			return args
+		}
+	}
+	return nil
+}
+
+func newCannedAuthService(replies map[string]func(redis.Cmder)) (*AuthService, *cannedRedisHook) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	hook := &cannedRedisHook{replies: replies}
+	client.AddHook(hook)
+	return &AuthService{redis: client}, hook
+}
+
+func TestRefreshToken_RejectsTokenIssuedBeforeSessions(t *testing.T) {
+	userID := uuid.New()
+	svc, _ := newCannedAuthService(map[string]func(redis.Cmder){
+		"getdel": func(cmd redis.Cmder) { cmd.(*redis.StringCmd).SetVal(userID.String()) },
+	})
+
+	if _, err := svc.RefreshToken(context.Background(), "legacy-token"); err != errSessionSignedOut {
+		t.Fatalf("RefreshToken() error = %v, want the session to be signed out", err)
+	}
+}
+
+func TestRefreshToken_RejectsRevokedSession(t *testing.T) {
+	userID, sessionID := uuid.New(), uuid.New()
+	svc, hook := newCannedAuthService(map[string]func(redis.Cmder){
+		"getdel": func(cmd redis.Cmder) { cmd.(*redis.StringCmd).SetVal(refreshTokenValue(userID, sessionID)) },
+		"hget":   func(cmd redis.Cmder) { cmd.SetErr(redis.Nil) },
+	})
+
+	if _, err := svc.RefreshToken(context.Background(), "token"); err != errSessionSignedOut {
+		t.Fatalf("RefreshToken() error = %v, want the session to be signed out", err)
+	}
+	if hook.sent("hset") != nil || hook.sent("set") != nil {
+		t.Fatalf("a revoked session must not be stored again: %v", hook.commands)
+	}
+}
+
+func TestStoreSession_RotationDoesNotRestoreRevokedSession(t *testing.T) {
+	svc, hook := newCannedAuthService(map[string]func(redis.Cmder){
+		// The script finds the session gone from the hash.
+		"evalsha": func(cmd redis.Cmder) { cmd.(*redis.Cmd).SetVal(int64(0)) },
+	})
+
+	session := &models.Session{ID: uuid.New(), CreatedAt: time.Now()}
+	if _, err := svc.storeSession(context.Background(), uuid.New(), session); err != errSessionSignedOut {
+		t.Fatalf("storeSession() error = %v, want the session to be signed out", err)
+	}
+	if hook.sent("hset") != nil || hook.sent("set") != nil {
+		t.Fatalf("rotation must go through the script only: %v", hook.commands)
+	}
+}
+
+func TestLogoutAll_RevokesTokensFromBeforeSessions(t *testing.T) {
+	userID := uuid.New()
+	svc, hook := newCannedAuthService(map[string]func(redis.Cmder){
+		"hgetall":  func(cmd redis.Cmder) { cmd.(*redis.MapStringStringCmd).SetVal(map[string]string{}) },
+		"smembers": func(cmd redis.Cmder) { cmd.(*redis.StringSliceCmd).SetVal([]string{"old-token"}) },
+	})
+
+	if err := svc.LogoutAll(context.Background(), userID); err != nil {
+		t.Fatalf("LogoutAll() error = %v", err)
+	}
+	deleted := map[interface{}]bool{}
+	for _, key := range hook.sent("del")[1:] {
+		deleted[key] = true
+	}
+	for _, want := range []string{"refresh:old-token", legacyRefreshTokensKey(userID), userSessionsKey(userID)} {
+		if !deleted[want] {
+			t.Fatalf("LogoutAll deleted %v, want %s among them", hook.sent("del"), want)
+		}
+	}
+}
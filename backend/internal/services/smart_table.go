@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+const smartTableRebuildTimeout = 90 * time.Second
+
+// ErrSmartTableUnusable is returned when the model's answer holds no table
+// that passes hasValidSmartSummaryTable.
+var ErrSmartTableUnusable = errors.New("model did not return a usable table")
+
+func buildSmartTablePrompt(summary, transcript string) string {
+	var b strings.Builder
+	b.WriteString(`You are fixing the table section of a Smart Summary of a lecture. Write ONE markdown table that organizes the most important concepts, entities or steps from the lecture.
+
+Rules:
+1) Use 2-4 columns with specific, topic-appropriate headers. Use "Concept | Explanation" only if nothing more specific fits.
+2) Write 3-8 data rows. Every cell must contain real content; write "Not specified" if something is unknown. Never use dashes, empty cells, or placeholders such as "Point 1".
+3) Put the separator row (| --- | --- |) exactly once, right after the header row.
+4) Use terminology from the transcript and only facts it supports. Do not repeat the summary's sentences verbatim.
+5) Write in the same language as the summary.
+6) Return ONLY the table: no heading, no code fences, no commentary.
+
+Current summary:
+`)
+	b.WriteString(summary)
+	if transcript = strings.TrimSpace(transcript); transcript != "" {
+		excerpt, note := transcriptEvidence(transcript)
+		b.WriteString("\n\nTranscript excerpt:\n")
+		b.WriteString(note)
+		b.WriteString(excerpt)
+	}
+	return b.String()
+}
+
+// markdownTableSpan returns the line range [start, end) of the first block of
+// two or more consecutive lines that start with a pipe, valid table or not.
+func markdownTableSpan(lines []string) (int, int, bool) {
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), "|") {
+			end++
+		}
+		if end-i >= 2 {
+			return i, end, true
+		}
+		i = end
+	}
+	return 0, 0, false
+}
+
+// extractSmartTable pulls the first table out of a model answer, tolerating
+// code fences and surrounding prose. It reports false unless the table is
+// well formed with no placeholder cells.
+func extractSmartTable(raw string) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	start, end, ok := markdownTableSpan(lines)
+	if !ok {
+		return "", false
+	}
+	rows := make([]string, 0, end-start)
+	for _, line := range lines[start:end] {
+		rows = append(rows, strings.TrimSpace(line))
+	}
+	table := strings.Join(rows, "\n")
+	if !hasValidSmartSummaryTable(table) {
+		return "", false
+	}
+	return table, true
+}
+
+// replaceSmartSummaryTable swaps the summary's first table for table, leaving
+// every other section as it was. A summary without a table gets one appended
+// under the heading ensureSmartSummaryTable uses.
+func replaceSmartSummaryTable(text, table string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	start, end, ok := markdownTableSpan(lines)
+	if !ok {
+		return strings.TrimSpace(text) + "\n\n## Key Concepts Table\n" + table + "\n"
+	}
+
+	out := make([]string, 0, len(lines))
+	out = append(out, lines[:start]...)
+	out = append(out, table)
+	out = append(out, lines[end:]...)
+	return strings.Join(out, "\n")
+}
+
+// RebuildSmartSummaryTable regenerates only the table section of a smart
+// summary from the summary and its transcript, returning the full summary
+// with the new table in place of the old one.
+func (s *GeminiService) RebuildSmartSummaryTable(ctx context.Context, summary, transcript string) (string, error) {
+	if err := s.acquireRate(ctx); err != nil {
+		return "", err
+	}
+	defer s.releaseRate()
+
+	resp, err := generateContentWithTimeout(ctx, s.model, smartTableRebuildTimeout, genai.Text(buildSmartTablePrompt(summary, transcript)))
+	if err != nil {
+		return "", fmt.Errorf("Gemini API error: %w", err)
+	}
+
+	table, ok := extractSmartTable(extractText(resp))
+	if !ok {
+		return "", ErrSmartTableUnusable
+	}
+	return replaceSmartSummaryTable(summary, table), nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+const weakSmartSummary = `## Summary of Video Content
+The lecture covers photosynthesis.
+
+## Key Concepts Table
+| Concept | Explanation |
+| --- | --- |
+| Point 1 | The lecture covers photosynthesis. |
+| Point 2 | Key takeaway |
+| Point 3 | Key takeaway |
+
+## Additional Interesting Facts
+- Chlorophyll absorbs mostly red and blue light.`
+
+func TestExtractSmartTable(t *testing.T) {
+	raw := "Here is the table:\n```markdown\n| Stage | Location | Output |\n| --- | --- | --- |\n| Light reactions | Thylakoid | ATP and NADPH |\n| Calvin cycle | Stroma | Glucose |\n```"
+	table, ok := extractSmartTable(raw)
+	if !ok {
+		t.Fatal("expected a usable table")
+	}
+	if !strings.HasPrefix(table, "| Stage |") || strings.Contains(table, "```") {
+		t.Fatalf("unexpected table:\n%s", table)
+	}
+
+	if _, ok := extractSmartTable("| Stage | Output |\n| --- | --- |\n| Light reactions | --- |"); ok {
+		t.Fatal("expected a table with placeholder cells to be rejected")
+	}
+	if _, ok := extractSmartTable("Sorry, I cannot help with that."); ok {
+		t.Fatal("expected no table")
+	}
+}
+
+func TestReplaceSmartSummaryTable(t *testing.T) {
+	table := "| Stage | Output |\n| --- | --- |\n| Light reactions | ATP and NADPH |"
+
+	got := replaceSmartSummaryTable(weakSmartSummary, table)
+	if strings.Contains(got, "Point 1") {
+		t.Fatalf("old table was not replaced:\n%s", got)
+	}
+	for _, want := range []string{"## Summary of Video Content", "## Key Concepts Table\n| Stage | Output |", "| Light reactions | ATP and NADPH |\n\n## Additional Interesting Facts", "- Chlorophyll"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rebuilt summary missing %q:\n%s", want, got)
+		}
+	}
+
+	appended := replaceSmartSummaryTable("## Summary of Video Content\nNo table here.", table)
+	if !strings.HasSuffix(appended, "## Key Concepts Table\n"+table+"\n") {
+		t.Fatalf("expected table to be appended:\n%s", appended)
+	}
+}
+
+func TestBuildSmartTablePrompt(t *testing.T) {
+	long := strings.Repeat("a", transcriptEvidenceChars+500)
+	prompt := buildSmartTablePrompt(weakSmartSummary, long)
+	if !strings.Contains(prompt, "Point 1") || !strings.Contains(prompt, "Transcript excerpt:") {
+		t.Fatalf("prompt missing summary or transcript:\n%.300s", prompt)
+	}
+	if strings.Contains(prompt, strings.Repeat("a", transcriptEvidenceChars+1)) {
+		t.Fatal("expected transcript to be truncated")
+	}
+	if strings.Contains(buildSmartTablePrompt(weakSmartSummary, "  "), "Transcript excerpt:") {
+		t.Fatal("expected no transcript section without a transcript")
+	}
+}
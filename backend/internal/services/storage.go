@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrStorageObjectNotFound is returned by Storage.Get for a key that holds
+	// no file.
+	ErrStorageObjectNotFound = errors.New("stored file not found")
+	// ErrInvalidStorageKey is returned for keys that are absolute or would
+	// escape the storage root.
+	ErrInvalidStorageKey = errors.New("invalid storage key")
+)
+
+// Storage holds uploaded files under slash-separated keys such as
+// "users/<user id>/uploads/<file id>.pdf", the form stored in
+// contents.file_path.
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any file
+	// already there.
+	Put(ctx context.Context, key string, body io.Reader, size int64) error
+	// Get opens the file stored under key. A missing file is reported as
+	// ErrStorageObjectNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file stored under key. Deleting a missing file is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalPather is implemented by storage backends whose files are already on
+// this machine's disk, letting callers that need a path skip a copy.
+type LocalPather interface {
+	LocalPath(key string) (string, bool)
+}
+
+// NewStorage builds the backend named by storageType: "local" (the default)
+// keeps files under localRoot, "s3" in the bucket described by s3.
+func NewStorage(storageType, localRoot string, s3 S3Config) (Storage, error) {
+	switch strings.ToLower(strings.TrimSpace(storageType)) {
+	case "", "local":
+		return NewLocalStorage(localRoot), nil
+	case "s3":
+		return NewS3Storage(s3)
+	default:
+		return nil, fmt.Errorf("unknown storage type %q (expected local or s3)", storageType)
+	}
+}
+
+func validateStorageKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || filepath.IsAbs(key) {
+		return ErrInvalidStorageKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidStorageKey
+		}
+	}
+	return nil
+}
+
+// LocalStorage keeps files under a directory on the local filesystem. It
+// only works for a single backend replica, or replicas sharing that disk.
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+// LocalPath is the file's path on disk, or false when key is invalid.
+func (s *LocalStorage) LocalPath(key string) (string, bool) {
+	if s.root == "" || validateStorageKey(key) != nil {
+		return "", false
+	}
+	absRoot, err := filepath.Abs(s.root)
+	if err != nil {
+		return "", false
+	}
+	full := filepath.Join(absRoot, filepath.FromSlash(key))
+	if !strings.HasPrefix(full, absRoot+string(filepath.Separator)) {
+		return "", false
+	}
+	return full, true
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	path, ok := s.LocalPath(key)
+	if !ok {
+		return ErrInvalidStorageKey
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("LocalStorage.Put: %w", err)
+	}
+
+	// Write next to the target and rename, so a failed upload never leaves a
+	// truncated file under the key.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("LocalStorage.Put: %w", err)
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("LocalStorage.Put: %w", err)
+	}
+	// CreateTemp makes the file owner-only; keep the permissions uploads
+	// have always had.
+	_ = tmp.Chmod(0o644)
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("LocalStorage.Put: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("LocalStorage.Put: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, ok := s.LocalPath(key)
+	if !ok {
+		return nil, ErrInvalidStorageKey
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrStorageObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LocalStorage.Get: %w", err)
+	}
+	return f, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, ok := s.LocalPath(key)
+	if !ok {
+		return ErrInvalidStorageKey
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("LocalStorage.Delete: %w", err)
+	}
+	return nil
+}
+
+// LocalCopy makes a stored file available at a local path for code that
+// needs one (text extractors, pdftoppm, Gemini file uploads). Local storage
+// hands back its own path; other backends download into a temporary file
+// that keeps the key's extension. Callers must run cleanup when done.
+func LocalCopy(ctx context.Context, storage Storage, key string) (string, func(), error) {
+	if local, ok := storage.(LocalPather); ok {
+		path, ok := local.LocalPath(key)
+		if !ok {
+			return "", nil, ErrInvalidStorageKey
+		}
+		return path, func() {}, nil
+	}
+
+	body, err := storage.Get(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "lectura-upload-*"+filepath.Ext(key))
+	if err != nil {
+		return "", nil, fmt.Errorf("LocalCopy: %w", err)
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("LocalCopy: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("LocalCopy: %w", err)
+	}
+	return tmp.Name(), cleanup, nil
+}
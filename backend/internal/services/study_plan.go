@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+// Time budgeting for study plans. Reading time comes from the summary's word
+// count; quizzes and card reviews use flat per-item estimates.
+const (
+	studyReadingWordsPerMinute = 200
+	studyMinReviewMinutes      = 5
+	studyQuizMinutes           = 10
+	studySecondsPerCard        = 30
+
+	// A summary is quizzed this many days after it is reviewed, so the quiz
+	// tests recall rather than what was just read.
+	studyQuizGapDays = 2
+)
+
+// StudyPlanInput is everything BuildStudyPlanItems schedules. Summaries are
+// reviewed in the order given.
+type StudyPlanInput struct {
+	StartDate    time.Time
+	TargetDate   time.Time
+	DailyMinutes int
+	Summaries    []models.StudyPlanSummary
+	DueCards     []models.DueCardForecast
+}
+
+// BuildStudyPlanItems lays out a plan day by day between StartDate and
+// TargetDate, both inclusive:
+//   - flashcard reviews land on the days the cards fall due;
+//   - summary reviews are spread evenly over the days before the last, moving
+//     to a later day when the daily budget is already spent;
+//   - each summary is quizzed studyQuizGapDays after its review, or on the
+//     target date if that comes first.
+//
+// Items are ordered by day, then flashcards, reviews and quizzes.
+func BuildStudyPlanItems(in StudyPlanInput) []models.StudyPlanItem {
+	start := truncateToDay(in.StartDate)
+	days := int(truncateToDay(in.TargetDate).Sub(start).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+	dayAt := func(i int) time.Time { return start.AddDate(0, 0, i) }
+	load := make([]int, days)
+	var items []models.StudyPlanItem
+
+	// Flashcards first: their days are fixed by the SM-2 due dates.
+	type deckDay struct {
+		deck uuid.UUID
+		day  int
+	}
+	cardsDue := make(map[deckDay]int)
+	deckTitles := make(map[uuid.UUID]string)
+	for _, f := range in.DueCards {
+		day := int(truncateToDay(f.Day).Sub(start).Hours() / 24)
+		if day < 0 {
+			day = 0
+		}
+		if day >= days || f.Count <= 0 {
+			continue
+		}
+		cardsDue[deckDay{f.DeckID, day}] += f.Count
+		deckTitles[f.DeckID] = f.DeckTitle
+	}
+	for key, count := range cardsDue {
+		deckID := key.deck
+		minutes := (count*studySecondsPerCard + 59) / 60
+		load[key.day] += minutes
+		items = append(items, models.StudyPlanItem{
+			Day:              dayAt(key.day),
+			Kind:             models.StudyPlanItemFlashcards,
+			Title:            deckTitles[deckID],
+			DeckID:           &deckID,
+			CardCount:        count,
+			EstimatedMinutes: minutes,
+		})
+	}
+
+	// Keep the last day free for quizzes when there is room to.
+	reviewDays := days
+	if days >= 3 {
+		reviewDays = days - 1
+	}
+	for i, s := range in.Summaries {
+		summaryID := s.SummaryID
+		minutes := studyReviewMinutes(s.WordCount)
+
+		day := i * reviewDays / len(in.Summaries)
+		for d := day; d < reviewDays; d++ {
+			if load[d]+minutes <= in.DailyMinutes {
+				day = d
+				break
+			}
+		}
+		load[day] += minutes
+		items = append(items, models.StudyPlanItem{
+			Day:              dayAt(day),
+			Kind:             models.StudyPlanItemSummaryReview,
+			Title:            s.Title,
+			SummaryID:        &summaryID,
+			EstimatedMinutes: minutes,
+		})
+
+		quizDay := day + studyQuizGapDays
+		if quizDay >= days {
+			quizDay = days - 1
+		}
+		load[quizDay] += studyQuizMinutes
+		items = append(items, models.StudyPlanItem{
+			Day:              dayAt(quizDay),
+			Kind:             models.StudyPlanItemQuiz,
+			Title:            s.Title,
+			SummaryID:        &summaryID,
+			QuizID:           s.QuizID,
+			EstimatedMinutes: studyQuizMinutes,
+		})
+	}
+
+	kindOrder := map[string]int{
+		models.StudyPlanItemFlashcards:    0,
+		models.StudyPlanItemSummaryReview: 1,
+		models.StudyPlanItemQuiz:          2,
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Day.Equal(items[j].Day) {
+			return items[i].Day.Before(items[j].Day)
+		}
+		if items[i].Kind != items[j].Kind {
+			return kindOrder[items[i].Kind] < kindOrder[items[j].Kind]
+		}
+		// Flashcard items come from a map; order them by deck for stable output.
+		if items[i].DeckID != nil && items[j].DeckID != nil {
+			return items[i].DeckID.String() < items[j].DeckID.String()
+		}
+		return false
+	})
+	return items
+}
+
+func studyReviewMinutes(wordCount int) int {
+	minutes := (wordCount + studyReadingWordsPerMinute - 1) / studyReadingWordsPerMinute
+	if minutes < studyMinReviewMinutes {
+		return studyMinReviewMinutes
+	}
+	return minutes
+}
+
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func buildStudyOrderPrompt(summaries []models.StudyPlanSummary) string {
+	var b strings.Builder
+	b.WriteString(`A student is preparing for an exam using the study materials listed below.
+Order them so that foundational topics come before the topics that build on them.
+
+Rules:
+1) Return ONLY a JSON array of the material numbers in the recommended order, e.g. [2, 0, 1].
+2) Include every number exactly once.
+3) When there is no clear dependency, keep the original order.
+
+Materials:
+`)
+	for i, s := range summaries {
+		desc := strings.TrimSpace(s.Description)
+		if len(desc) > 300 {
+			desc = desc[:300]
+		}
+		fmt.Fprintf(&b, "%d. %s", i, strings.TrimSpace(s.Title))
+		if desc != "" {
+			fmt.Fprintf(&b, " — %s", desc)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// applyStudyOrder reorders summaries by the model's answer, ignoring unknown
+// or repeated indexes and appending anything it left out in original order.
+func applyStudyOrder(summaries []models.StudyPlanSummary, order []int) []models.StudyPlanSummary {
+	out := make([]models.StudyPlanSummary, 0, len(summaries))
+	used := make([]bool, len(summaries))
+	for _, idx := range order {
+		if idx < 0 || idx >= len(summaries) || used[idx] {
+			continue
+		}
+		used[idx] = true
+		out = append(out, summaries[idx])
+	}
+	for i, s := range summaries {
+		if !used[i] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// OrderStudyTopics asks Gemini for a learning order of the summaries, with
+// prerequisites first.
+func (s *GeminiService) OrderStudyTopics(ctx context.Context, summaries []models.StudyPlanSummary) ([]models.StudyPlanSummary, error) {
+	if len(summaries) < 2 {
+		return summaries, nil
+	}
+	if err := s.acquireRate(ctx); err != nil {
+		return nil, err
+	}
+	defer s.releaseRate()
+
+	var order []int
+	if err := s.generateJSONArray(ctx, "study plan ordering", buildStudyOrderPrompt(summaries), &order); err != nil {
+		return nil, err
+	}
+	return applyStudyOrder(summaries, order), nil
+}
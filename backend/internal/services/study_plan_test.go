@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+func TestBuildStudyPlanItems_SpreadsReviewsAndQuizzesLater(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	quizID := uuid.New()
+	summaries := []models.StudyPlanSummary{
+		{SummaryID: uuid.New(), Title: "Cells", WordCount: 1000, QuizID: &quizID},
+		{SummaryID: uuid.New(), Title: "Genetics", WordCount: 400},
+		{SummaryID: uuid.New(), Title: "Evolution", WordCount: 3000},
+	}
+
+	items := BuildStudyPlanItems(StudyPlanInput{
+		StartDate:    start,
+		TargetDate:   start.AddDate(0, 0, 6),
+		DailyMinutes: 60,
+		Summaries:    summaries,
+	})
+	if len(items) != 6 {
+		t.Fatalf("got %d items, want a review and a quiz per summary", len(items))
+	}
+
+	reviewDay := map[uuid.UUID]time.Time{}
+	for _, item := range items {
+		if item.Kind != models.StudyPlanItemSummaryReview {
+			continue
+		}
+		reviewDay[*item.SummaryID] = item.Day
+	}
+	// Six review days (the last is kept for quizzes) split over three summaries.
+	for i, wantOffset := range []int{0, 2, 4} {
+		if got := reviewDay[summaries[i].SummaryID]; !got.Equal(start.AddDate(0, 0, wantOffset)) {
+			t.Fatalf("%s reviewed on %s, want day %d", summaries[i].Title, got.Format("2006-01-02"), wantOffset)
+		}
+	}
+
+	for _, item := range items {
+		switch item.Kind {
+		case models.StudyPlanItemSummaryReview:
+			if *item.SummaryID == summaries[2].SummaryID && item.EstimatedMinutes != 15 {
+				t.Fatalf("3000-word review = %d minutes, want 15", item.EstimatedMinutes)
+			}
+			if *item.SummaryID == summaries[1].SummaryID && item.EstimatedMinutes != studyMinReviewMinutes {
+				t.Fatalf("short review = %d minutes, want the %d minute minimum", item.EstimatedMinutes, studyMinReviewMinutes)
+			}
+		case models.StudyPlanItemQuiz:
+			want := reviewDay[*item.SummaryID].AddDate(0, 0, studyQuizGapDays)
+			if last := start.AddDate(0, 0, 6); want.After(last) {
+				want = last
+			}
+			if !item.Day.Equal(want) {
+				t.Fatalf("quiz for %s on %s, want %s", item.Title, item.Day.Format("2006-01-02"), want.Format("2006-01-02"))
+			}
+			if *item.SummaryID == summaries[0].SummaryID && (item.QuizID == nil || *item.QuizID != quizID) {
+				t.Fatalf("quiz item should link the existing quiz")
+			}
+		}
+	}
+
+	for i := 1; i < len(items); i++ {
+		if items[i].Day.Before(items[i-1].Day) {
+			t.Fatalf("items are not in day order")
+		}
+	}
+}
+
+func TestBuildStudyPlanItems_FlashcardsFollowDueDates(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	deckID := uuid.New()
+
+	items := BuildStudyPlanItems(StudyPlanInput{
+		StartDate:    start,
+		TargetDate:   start.AddDate(0, 0, 3),
+		DailyMinutes: 60,
+		DueCards: []models.DueCardForecast{
+			{DeckID: deckID, DeckTitle: "Cells", Day: start.AddDate(0, 0, -4), Count: 5}, // overdue
+			{DeckID: deckID, DeckTitle: "Cells", Day: start, Count: 10},
+			{DeckID: deckID, DeckTitle: "Cells", Day: start.AddDate(0, 0, 2), Count: 3},
+			{DeckID: deckID, DeckTitle: "Cells", Day: start.AddDate(0, 0, 9), Count: 7}, // after the target date
+		},
+	})
+
+	if len(items) != 2 {
+		t.Fatalf("got %d flashcard items, want 2: %+v", len(items), items)
+	}
+	if !items[0].Day.Equal(start) || items[0].CardCount != 15 || items[0].EstimatedMinutes != 8 {
+		t.Fatalf("first day item = %+v, want 15 cards over 8 minutes on the start date", items[0])
+	}
+	if !items[1].Day.Equal(start.AddDate(0, 0, 2)) || items[1].CardCount != 3 {
+		t.Fatalf("second item = %+v, want 3 cards two days in", items[1])
+	}
+}
+
+func TestBuildStudyPlanItems_MovesReviewsPastFullDays(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	deckID := uuid.New()
+	summaryID := uuid.New()
+
+	items := BuildStudyPlanItems(StudyPlanInput{
+		StartDate:    start,
+		TargetDate:   start.AddDate(0, 0, 4),
+		DailyMinutes: 20,
+		Summaries:    []models.StudyPlanSummary{{SummaryID: summaryID, Title: "Cells", WordCount: 1000}},
+		DueCards:     []models.DueCardForecast{{DeckID: deckID, Day: start, Count: 40}},
+	})
+
+	for _, item := range items {
+		if item.Kind == models.StudyPlanItemSummaryReview && !item.Day.Equal(start.AddDate(0, 0, 1)) {
+			t.Fatalf("review on %s, want it moved past the full first day", item.Day.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestApplyStudyOrder(t *testing.T) {
+	summaries := []models.StudyPlanSummary{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+
+	got := applyStudyOrder(summaries, []int{2, 7, 2, 0})
+	if len(got) != 3 || got[0].Title != "c" || got[1].Title != "a" || got[2].Title != "b" {
+		t.Fatalf("applyStudyOrder = %+v, want c, a, b", got)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// subtitlePauseSeconds is the silence between two cues that starts a new
+// paragraph in the extracted transcript.
+const subtitlePauseSeconds = 2.0
+
+// maxSubtitleParagraphWords makes a paragraph end at the next sentence end
+// once it is this long, so pause-free lectures are not one wall of text.
+const maxSubtitleParagraphWords = 120
+
+var (
+	subtitleTagPattern      = regexp.MustCompile(`<[^>]*>`)
+	subtitleStylePattern    = regexp.MustCompile(`\{\\[^}]*\}`)
+	subtitleTimestampFields = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{2})[.,](\d{1,3})$`)
+)
+
+// subtitleCue is the spoken text of one caption with its timing in seconds.
+type subtitleCue struct {
+	start, end float64
+	lines      []string
+}
+
+func (s *FileExtractService) extractSubtitles(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	text := subtitleTranscript(string(b))
+	if text == "" {
+		return "", fmt.Errorf("subtitle file has no caption text")
+	}
+	return text, nil
+}
+
+// subtitleTranscript turns an SRT or WebVTT file into plain transcript text.
+// Cue numbers, timestamps, styling and WebVTT metadata blocks are dropped,
+// lines repeated by rolling captions are kept once, and a new paragraph
+// starts after a pause in speech or after a long run of sentences.
+func subtitleTranscript(raw string) string {
+	var paragraphs []string
+	var current []string
+	words := 0
+	lastLine := ""
+	prevEnd := -1.0
+
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+		}
+		current = nil
+		words = 0
+	}
+
+	for _, cue := range parseSubtitleCues(raw) {
+		if len(current) > 0 {
+			paused := prevEnd >= 0 && cue.start-prevEnd >= subtitlePauseSeconds
+			long := words >= maxSubtitleParagraphWords && endsSentence(current[len(current)-1])
+			if paused || long {
+				flush()
+			}
+		}
+		prevEnd = cue.end
+
+		for _, line := range cue.lines {
+			if line == lastLine {
+				continue
+			}
+			lastLine = line
+			current = append(current, line)
+			words += len(strings.Fields(line))
+		}
+	}
+	flush()
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func parseSubtitleCues(raw string) []subtitleCue {
+	raw = strings.TrimPrefix(raw, "\ufeff")
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = strings.ReplaceAll(raw, "\r", "\n")
+
+	var cues []subtitleCue
+	for _, block := range strings.Split(raw, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		first := strings.TrimSpace(lines[0])
+		if first == "" || strings.HasPrefix(first, "WEBVTT") || strings.HasPrefix(first, "NOTE") ||
+			first == "STYLE" || first == "REGION" {
+			continue
+		}
+
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		// Only a cue number or identifier may come before the timing line.
+		if timing < 0 || timing > 1 {
+			continue
+		}
+
+		cue := subtitleCue{start: -1, end: -1}
+		if parts := strings.SplitN(lines[timing], "-->", 2); len(parts) == 2 {
+			cue.start, _ = parseSubtitleTimestamp(strings.TrimSpace(parts[0]))
+			// WebVTT cue settings follow the end time.
+			if fields := strings.Fields(parts[1]); len(fields) > 0 {
+				cue.end, _ = parseSubtitleTimestamp(fields[0])
+			}
+		}
+		for _, line := range lines[timing+1:] {
+			if text := cleanSubtitleLine(line); text != "" {
+				cue.lines = append(cue.lines, text)
+			}
+		}
+		if len(cue.lines) > 0 {
+			cues = append(cues, cue)
+		}
+	}
+	return cues
+}
+
+// cleanSubtitleLine strips formatting tags (<i>, <v Speaker>, inline
+// timestamps, {\an8}) and decodes entities from one caption line.
+func cleanSubtitleLine(line string) string {
+	line = subtitleTagPattern.ReplaceAllString(line, "")
+	line = subtitleStylePattern.ReplaceAllString(line, "")
+	line = html.UnescapeString(line)
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// parseSubtitleTimestamp reads "hh:mm:ss,mmm" (SRT) or "[hh:]mm:ss.mmm"
+// (WebVTT) as seconds.
+func parseSubtitleTimestamp(ts string) (float64, bool) {
+	m := subtitleTimestampFields.FindStringSubmatch(ts)
+	if m == nil {
+		return -1, false
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	millis, _ := strconv.Atoi((m[4] + "00")[:3])
+	return float64(hours*3600+minutes*60+seconds) + float64(millis)/1000, true
+}
+
+func endsSentence(line string) bool {
+	line = strings.TrimRight(line, `"')]`)
+	return strings.HasSuffix(line, ".") || strings.HasSuffix(line, "?") || strings.HasSuffix(line, "!")
+}
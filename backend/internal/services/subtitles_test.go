@@ -0,0 +1,46 @@
+package services
+
+import "testing"
+
+func TestSubtitleTranscript_SRT(t *testing.T) {
+	srt := "1\r\n00:00:01,000 --> 00:00:03,000\r\n<i>Welcome back.</i> Today we cover\r\n\r\n" +
+		"2\r\n00:00:03,100 --> 00:00:05,000\r\n{\\an8}enzymes &amp; kinetics.\r\n\r\n" +
+		"3\r\n00:00:09,000 --> 00:00:11,000\r\nFirst, the active site.\r\n"
+
+	got := subtitleTranscript(srt)
+	want := "Welcome back. Today we cover enzymes & kinetics.\n\nFirst, the active site."
+	if got != want {
+		t.Fatalf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestSubtitleTranscript_VTT(t *testing.T) {
+	vtt := "\ufeffWEBVTT\nKind: captions\nLanguage: en\n\n" +
+		"NOTE recorded in room 101\n\n" +
+		"STYLE\n::cue { color: yellow }\n\n" +
+		"intro\n00:01.000 --> 00:02.500 align:start position:0%\n<v Lecturer>so today<00:01.500><c> we</c>\n\n" +
+		"00:02.500 --> 00:04.000\nso today we\nlook at buffers\n\n" +
+		"1:00:00.000 --> 1:00:02.000\nAny questions?\n"
+
+	got := subtitleTranscript(vtt)
+	want := "so today we look at buffers\n\nAny questions?"
+	if got != want {
+		t.Fatalf("transcript = %q, want %q", got, want)
+	}
+}
+
+func TestParseSubtitleTimestamp(t *testing.T) {
+	tests := map[string]float64{
+		"00:01:02,500": 62.5,
+		"01:02.5":      62.5,
+		"1:00:00.000":  3600,
+	}
+	for ts, want := range tests {
+		if got, ok := parseSubtitleTimestamp(ts); !ok || got != want {
+			t.Fatalf("parseSubtitleTimestamp(%q) = %v, %v; want %v", ts, got, ok, want)
+		}
+	}
+	if _, ok := parseSubtitleTimestamp("not a time"); ok {
+		t.Fatalf("expected invalid timestamp to be rejected")
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// summaryExportRecord adapts a summary to the shape the data export renders,
+// so single-summary downloads share its markdown layout and header block.
+func summaryExportRecord(s *models.Summary) models.ExportSummary {
+	return models.ExportSummary{
+		ID:             s.ID,
+		Title:          s.Title,
+		Source:         s.Source,
+		Format:         s.Format,
+		Length:         s.LengthSetting,
+		Content:        s.ContentRaw,
+		CornellCues:    s.CornellCues,
+		CornellNotes:   s.CornellNotes,
+		CornellSummary: s.CornellSummary,
+		Tags:           s.Tags,
+		Description:    s.Description,
+		WordCount:      s.WordCount,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+// WriteSummaryMarkdown writes the summary as a standalone markdown file.
+func WriteSummaryMarkdown(w io.Writer, s *models.Summary) error {
+	_, err := io.WriteString(w, RenderSummaryMarkdown(summaryExportRecord(s)))
+	return err
+}
+
+// WriteSummaryPDF renders the summary as a printable PDF: a header block with
+// the tags and description, then the markdown body laid out line by line.
+func WriteSummaryPDF(w io.Writer, s *models.Summary) error {
+	title := strings.TrimSpace(s.Title)
+	if title == "" {
+		title = "Untitled summary"
+	}
+
+	doc := NewPDFDocument(title)
+	doc.Heading(title)
+	doc.Paragraph(fmt.Sprintf("Created %s  -  %s (%s)", s.CreatedAt.UTC().Format("2006-01-02"), s.Format, s.LengthSetting))
+	if len(s.Tags) > 0 {
+		doc.Paragraph("Tags: " + strings.Join(s.Tags, ", "))
+	}
+	if s.Description != nil && strings.TrimSpace(*s.Description) != "" {
+		doc.Space(4)
+		doc.Indented(strings.TrimSpace(*s.Description), false)
+	}
+	doc.Space(12)
+
+	writeMarkdownPDF(doc, summaryMarkdownBody(s))
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// SummaryExportFileName builds a download name from the summary title.
+func SummaryExportFileName(title, ext string) string {
+	return exportTitleSlug(title, "summary") + "." + ext
+}
+
+// writeMarkdownPDF lays out the markdown the summaries are generated in:
+// headings, bullet and numbered lists, block quotes and pipe tables. Inline
+// emphasis markers are dropped since the PDF fonts have no italics.
+func writeMarkdownPDF(doc *PDFDocument, body string) {
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			doc.Indented(line, false)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			doc.Space(6)
+		case strings.HasPrefix(trimmed, "#"):
+			doc.Space(6)
+			doc.Subheading(stripMarkdownInline(strings.TrimLeft(trimmed, "# ")))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "), strings.HasPrefix(trimmed, "+ "):
+			doc.Indented("• "+stripMarkdownInline(trimmed[2:]), false)
+		case strings.HasPrefix(trimmed, ">"):
+			doc.Indented(stripMarkdownInline(strings.TrimSpace(strings.TrimLeft(trimmed, ">"))), false)
+		case strings.HasPrefix(trimmed, "|"):
+			if isMarkdownTableDivider(trimmed) {
+				continue
+			}
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			for i, cell := range cells {
+				cells[i] = stripMarkdownInline(strings.TrimSpace(cell))
+			}
+			doc.Indented(strings.Join(cells, " | "), false)
+		case isNumberedListItem(trimmed):
+			doc.Indented(stripMarkdownInline(trimmed), false)
+		default:
+			doc.Paragraph(stripMarkdownInline(trimmed))
+		}
+	}
+}
+
+func stripMarkdownInline(text string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(text)
+}
+
+func isMarkdownTableDivider(line string) bool {
+	return strings.Trim(line, "|-: ") == ""
+}
+
+func isNumberedListItem(line string) bool {
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	return digits > 0 && strings.HasPrefix(line[digits:], ". ")
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestWriteMarkdownPDF(t *testing.T) {
+	doc := NewPDFDocument("Notes")
+	writeMarkdownPDF(doc, strings.Join([]string{
+		"## **Key** Points",
+		"- First `point`",
+		"1. Numbered step",
+		"| Term | Meaning |",
+		"|---|:---:|",
+		"| ATP | Energy |",
+		"> Quoted line",
+	}, "\n"))
+
+	var lines []pdfLine
+	for _, page := range doc.pages {
+		lines = append(lines, page...)
+	}
+	want := []struct {
+		text   string
+		bold   bool
+		indent bool
+	}{
+		{"Key Points", true, false},
+		{"\x95 First point", false, true},
+		{"1. Numbered step", false, true},
+		{"Term | Meaning", false, true},
+		{"ATP | Energy", false, true},
+		{"Quoted line", false, true},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %d: %+v", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if lines[i].text != w.text || lines[i].bold != w.bold || (lines[i].indent > 0) != w.indent {
+			t.Fatalf("line %d = %+v, want %+v", i, lines[i], w)
+		}
+	}
+}
+
+func TestWriteSummaryPDF_CornellSections(t *testing.T) {
+	cues, notes := "What is ATP?", "ATP stores energy."
+	s := &models.Summary{Title: "Cell Energy", Format: "cornell", CornellCues: &cues, CornellNotes: &notes, Tags: []string{"biology"}}
+
+	var buf bytes.Buffer
+	if err := WriteSummaryPDF(&buf, s); err != nil {
+		t.Fatalf("WriteSummaryPDF: %v", err)
+	}
+	for _, want := range []string{"(Cell Energy)", "(Tags: biology)", "(Cues)", "(What is ATP?)", "(Notes)"} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Fatalf("expected %s in the PDF", want)
+		}
+	}
+}
+
+func TestSummaryExportFileName(t *testing.T) {
+	if got := SummaryExportFileName("Cell Biology: Week 1!", "pdf"); got != "cell-biology-week-1.pdf" {
+		t.Fatalf("unexpected file name %q", got)
+	}
+	if got := SummaryExportFileName("  ", "md"); got != "summary.md" {
+		t.Fatalf("unexpected fallback name %q", got)
+	}
+}
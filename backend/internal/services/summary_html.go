@@ -0,0 +1,51 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+
+	"lectura-backend/internal/models"
+)
+
+var (
+	// Raw HTML in model output is dropped by goldmark (no html.WithUnsafe);
+	// the sanitizer is the second line of defense.
+	summaryMarkdown   = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	summaryHTMLPolicy = bluemonday.UGCPolicy()
+)
+
+// summaryMarkdownBody is the markdown a summary is rendered from: the Cornell
+// sections for Cornell summaries, otherwise the raw content.
+func summaryMarkdownBody(s *models.Summary) string {
+	if s.Format == "cornell" && (s.CornellCues != nil || s.CornellNotes != nil || s.CornellSummary != nil) {
+		var b strings.Builder
+		writeMarkdownSection(&b, "Cues", s.CornellCues)
+		writeMarkdownSection(&b, "Notes", s.CornellNotes)
+		writeMarkdownSection(&b, "Summary", s.CornellSummary)
+		return b.String()
+	}
+	if s.ContentRaw == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s.ContentRaw)
+}
+
+// RenderSummaryHTML renders a summary body, GFM tables included, to
+// sanitized HTML. It returns "" for a summary with no content yet.
+func RenderSummaryHTML(s *models.Summary) (string, error) {
+	body := summaryMarkdownBody(s)
+	if strings.TrimSpace(body) == "" {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := summaryMarkdown.Convert([]byte(body), &buf); err != nil {
+		return "", fmt.Errorf("failed to render summary markdown: %w", err)
+	}
+	return summaryHTMLPolicy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestRenderSummaryHTML_RendersTables(t *testing.T) {
+	raw := "## Overview\n\nSome **bold** text.\n\n| Term | Meaning |\n| --- | --- |\n| Mitosis | Cell division |\n"
+	html, err := RenderSummaryHTML(&models.Summary{Format: "smart", ContentRaw: &raw})
+	if err != nil {
+		t.Fatalf("RenderSummaryHTML() error = %v", err)
+	}
+
+	for _, want := range []string{"<h2>Overview</h2>", "<strong>bold</strong>", "<table>", "<th>Term</th>", "<td>Mitosis</td>"} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("html missing %q:\n%s", want, html)
+		}
+	}
+}
+
+func TestRenderSummaryHTML_StripsInjectedMarkup(t *testing.T) {
+	raw := "Intro <script>alert(1)</script>\n\n<img src=x onerror=alert(1)>\n\n[click](javascript:alert(1))\n"
+	html, err := RenderSummaryHTML(&models.Summary{Format: "smart", ContentRaw: &raw})
+	if err != nil {
+		t.Fatalf("RenderSummaryHTML() error = %v", err)
+	}
+
+	for _, bad := range []string{"<script", "onerror", "javascript:"} {
+		if strings.Contains(html, bad) {
+			t.Fatalf("html contains %q:\n%s", bad, html)
+		}
+	}
+	if !strings.Contains(html, "Intro") {
+		t.Fatalf("html lost the text:\n%s", html)
+	}
+}
+
+func TestRenderSummaryHTML_CornellSections(t *testing.T) {
+	raw := "[CUES]\n1. What?\n[NOTES]\n1. This.\n[SUMMARY]\nDone."
+	cues, notes, summary := "1. What is osmosis?", "1. Diffusion of water.", "Water moves."
+	html, err := RenderSummaryHTML(&models.Summary{
+		Format: "cornell", ContentRaw: &raw,
+		CornellCues: &cues, CornellNotes: &notes, CornellSummary: &summary,
+	})
+	if err != nil {
+		t.Fatalf("RenderSummaryHTML() error = %v", err)
+	}
+
+	for _, want := range []string{"<h2>Cues</h2>", "<h2>Notes</h2>", "<h2>Summary</h2>", "What is osmosis?", "<ol>"} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("html missing %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "[CUES]") {
+		t.Fatalf("html rendered the raw markers:\n%s", html)
+	}
+}
+
+func TestRenderSummaryHTML_EmptySummary(t *testing.T) {
+	html, err := RenderSummaryHTML(&models.Summary{Format: "smart"})
+	if err != nil || html != "" {
+		t.Fatalf("RenderSummaryHTML(empty) = %q, %v", html, err)
+	}
+}
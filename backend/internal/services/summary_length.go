@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// summaryLengthTolerance is how far outside its preset band a summary may
+// land before a corrective pass is worth another Gemini call.
+const summaryLengthTolerance = 0.15
+
+const summaryLengthCorrectionTimeout = 3 * time.Minute
+
+// summaryLengthBand is the word range a length preset asks for.
+type summaryLengthBand struct {
+	Label         string
+	TargetPercent int // share of the source's words to aim for, clamped to the band
+	MinWords      int
+	MaxWords      int
+}
+
+// summaryLengthBandFor returns the word band for a length preset, widened
+// for formats whose mandatory sections need more words.
+func summaryLengthBandFor(format, length string) summaryLengthBand {
+	var band summaryLengthBand
+	switch length {
+	case "concise":
+		band = summaryLengthBand{Label: "Short", TargetPercent: 15, MinWords: 120, MaxWords: 220}
+	case "detailed":
+		band = summaryLengthBand{Label: "Long", TargetPercent: 40, MinWords: 500, MaxWords: 850}
+	case "comprehensive":
+		band = summaryLengthBand{Label: "Deep Dive", TargetPercent: 55, MinWords: 900, MaxWords: 1600}
+	default:
+		band = summaryLengthBand{Label: "Medium", TargetPercent: 25, MinWords: 260, MaxWords: 420}
+	}
+
+	// Multi-section formats need more words to fill all required sections
+	// (Cornell: Cues+Notes+Summary, Smart: Summary+Insights+Table+Facts).
+	var formatMultiplier float64
+	switch format {
+	case "cornell":
+		formatMultiplier = 1.8 // 3 mandatory sections with structured content
+	case "smart":
+		formatMultiplier = 1.6 // 4 mandatory sections including a table
+	case "bullets":
+		formatMultiplier = 1.1 // structured bullets add some overhead
+	default:
+		formatMultiplier = 1.0 // paragraph stays as-is
+	}
+
+	band.MinWords = int(float64(band.MinWords) * formatMultiplier)
+	band.MaxWords = int(float64(band.MaxWords) * formatMultiplier)
+	return band
+}
+
+// lengthCorrection says whether a summary of words words is far enough
+// outside the band to correct, and if so whether to expand to at least
+// target words or condense to at most target words.
+func (b summaryLengthBand) lengthCorrection(words int) (expand bool, target int, needed bool) {
+	switch {
+	case float64(words) < float64(b.MinWords)*(1-summaryLengthTolerance):
+		return true, b.MinWords, true
+	case float64(words) > float64(b.MaxWords)*(1+summaryLengthTolerance):
+		return false, b.MaxWords, true
+	default:
+		return false, 0, false
+	}
+}
+
+// distance is how many words lie between words and the band; zero inside it.
+func (b summaryLengthBand) distance(words int) int {
+	switch {
+	case words < b.MinWords:
+		return b.MinWords - words
+	case words > b.MaxWords:
+		return words - b.MaxWords
+	default:
+		return 0
+	}
+}
+
+func buildSummaryLengthCorrectionPrompt(format, summary, transcript string, expand bool, target, words int) string {
+	var b strings.Builder
+	if expand {
+		b.WriteString(fmt.Sprintf("The following lecture summary is %d words, but it must be AT LEAST %d words.\n", words, target))
+		b.WriteString("Expand it to at least that length by adding substance from the transcript: more explanation, examples, and supporting detail. Use only facts found in the transcript.\n")
+	} else {
+		b.WriteString(fmt.Sprintf("The following lecture summary is %d words, but it must be AT MOST %d words.\n", words, target))
+		b.WriteString("Condense it to at most that length by cutting repetition and minor details. Keep every key concept.\n")
+	}
+
+	b.WriteString("Keep the same language, tone, structure, headings and formatting.")
+	switch format {
+	case "cornell":
+		b.WriteString(" Keep the [CUES], [NOTES] and [SUMMARY] markers exactly as they are.")
+	case "smart":
+		b.WriteString(" Keep every section, including '## Summary of Video Content' first and the markdown table.")
+	}
+	b.WriteString("\nReturn ONLY the revised summary, with no preamble or commentary.\n\n")
+
+	b.WriteString("---SUMMARY START---\n")
+	b.WriteString(summary)
+	b.WriteString("\n---SUMMARY END---\n")
+	if expand && strings.TrimSpace(transcript) != "" {
+		b.WriteString("\n---TRANSCRIPT START---\n")
+		b.WriteString(transcript)
+		b.WriteString("\n---TRANSCRIPT END---\n")
+	}
+	return b.String()
+}
+
+// correctSummaryLength makes at most one corrective call when a summary
+// misses its length preset by more than the tolerance. The revision is kept
+// only if it lands closer to the band; the bool reports whether it was.
+func (s *GeminiService) correctSummaryLength(ctx context.Context, model *genai.GenerativeModel, format, length, summary, transcript string) (string, bool) {
+	band := summaryLengthBandFor(format, length)
+	words := len(strings.Fields(summary))
+	expand, target, needed := band.lengthCorrection(words)
+	if !needed {
+		return summary, false
+	}
+
+	log.Printf("INFO: Summary is %d words, outside the %d-%d word band for %q; running length correction", words, band.MinWords, band.MaxWords, length)
+	prompt := buildSummaryLengthCorrectionPrompt(format, summary, transcript, expand, target, words)
+	resp, err := generateContentWithTimeout(ctx, model, summaryLengthCorrectionTimeout, genai.Text(prompt))
+	if err != nil {
+		log.Printf("WARNING: Summary length correction failed: %v", err)
+		return summary, false
+	}
+
+	revised := strings.TrimSpace(extractText(resp))
+	revisedWords := len(strings.Fields(revised))
+	if revised == "" || band.distance(revisedWords) >= band.distance(words) {
+		log.Printf("INFO: Discarding summary length correction (%d words, was %d)", revisedWords, words)
+		return summary, false
+	}
+	return revised, true
+}
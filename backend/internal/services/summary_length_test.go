@@ -0,0 +1,66 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummaryLengthBandFor(t *testing.T) {
+	if got := summaryLengthBandFor("paragraph", "concise"); got.MinWords != 120 || got.MaxWords != 220 {
+		t.Fatalf("concise paragraph band = %+v, want 120-220", got)
+	}
+	if got := summaryLengthBandFor("cornell", "standard"); got.MinWords != 468 || got.MaxWords != 756 {
+		t.Fatalf("standard cornell band = %+v, want 468-756", got)
+	}
+	if got := summaryLengthBandFor("paragraph", "unknown"); got.Label != "Medium" {
+		t.Fatalf("unknown preset label = %q, want Medium", got.Label)
+	}
+}
+
+func TestSummaryLengthBand_LengthCorrection(t *testing.T) {
+	band := summaryLengthBandFor("paragraph", "standard") // 260-420 words
+
+	tests := []struct {
+		name       string
+		words      int
+		wantNeeded bool
+		wantExpand bool
+		wantTarget int
+	}{
+		{name: "inside band", words: 300},
+		{name: "slightly short within tolerance", words: 230},
+		{name: "slightly long within tolerance", words: 470},
+		{name: "far too short", words: 150, wantNeeded: true, wantExpand: true, wantTarget: 260},
+		{name: "far too long", words: 900, wantNeeded: true, wantTarget: 420},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expand, target, needed := band.lengthCorrection(tt.words)
+			if needed != tt.wantNeeded || expand != tt.wantExpand || target != tt.wantTarget {
+				t.Fatalf("lengthCorrection(%d) = (%v, %d, %v), want (%v, %d, %v)",
+					tt.words, expand, target, needed, tt.wantExpand, tt.wantTarget, tt.wantNeeded)
+			}
+		})
+	}
+
+	if band.distance(300) != 0 || band.distance(200) != 60 || band.distance(500) != 80 {
+		t.Fatalf("unexpected distances for band %+v", band)
+	}
+}
+
+func TestBuildSummaryLengthCorrectionPrompt(t *testing.T) {
+	expand := buildSummaryLengthCorrectionPrompt("cornell", "[CUES]\n...", "the transcript", true, 468, 200)
+	for _, want := range []string{"AT LEAST 468 words", "[CUES], [NOTES] and [SUMMARY]", "the transcript"} {
+		if !strings.Contains(expand, want) {
+			t.Fatalf("expand prompt missing %q:\n%s", want, expand)
+		}
+	}
+
+	condense := buildSummaryLengthCorrectionPrompt("paragraph", "long text", "the transcript", false, 420, 900)
+	if !strings.Contains(condense, "AT MOST 420 words") {
+		t.Fatalf("condense prompt missing target:\n%s", condense)
+	}
+	if strings.Contains(condense, "TRANSCRIPT START") {
+		t.Fatalf("condense prompt should not resend the transcript")
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/repository"
+)
+
+// ErrSearchUnavailable is returned when semantic search cannot run, because
+// the database has no pgvector support or no embedder is configured.
+var ErrSearchUnavailable = errors.New("semantic search is not available")
+
+const (
+	// Passages are about 200 words (roughly a minute of lecture), sharing 40
+	// words with their neighbours.
+	searchChunkWords   = 200
+	searchChunkOverlap = 40
+	// searchMaxChunks bounds the embedding work for very long sources.
+	searchMaxChunks = 400
+	// chatOverviewChars is how much of the summary a grounded chat prompt
+	// keeps alongside the retrieved passages.
+	chatOverviewChars = 6000
+)
+
+type summaryChunkStore interface {
+	Available(ctx context.Context) (bool, error)
+	SourceHash(ctx context.Context, summaryID uuid.UUID) (string, error)
+	ReplaceChunks(ctx context.Context, summaryID uuid.UUID, sourceHash string, chunks []models.SummaryChunk) error
+	Search(ctx context.Context, summaryID uuid.UUID, query []float32, limit int) ([]models.SummarySearchResult, error)
+}
+
+type textEmbedder interface {
+	EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error)
+	EmbedQuery(ctx context.Context, query string) ([]float32, error)
+}
+
+type summarySourceStore interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error)
+}
+
+// SummarySearchService finds the passages of a summary's source most
+// relevant to a question. Sources are embedded on first use and again
+// whenever their text changes.
+type SummarySearchService struct {
+	chunks   summaryChunkStore
+	embedder textEmbedder
+	contents summarySourceStore
+}
+
+func NewSummarySearchService(chunks *repository.SummaryChunkRepo, embedder *GeminiService, contents *repository.ContentRepo) *SummarySearchService {
+	return &SummarySearchService{chunks: chunks, embedder: embedder, contents: contents}
+}
+
+// Search returns up to limit passages of the summary's source, best match
+// first.
+func (s *SummarySearchService) Search(ctx context.Context, summary *models.Summary, query string, limit int) ([]models.SummarySearchResult, error) {
+	if s == nil || s.embedder == nil {
+		return nil, ErrSearchUnavailable
+	}
+	available, err := s.chunks.Available(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("check summary_chunks: %w", err)
+	}
+	if !available {
+		return nil, ErrSearchUnavailable
+	}
+
+	indexed, err := s.ensureIndexed(ctx, summary)
+	if err != nil || !indexed {
+		return []models.SummarySearchResult{}, err
+	}
+
+	vector, err := s.embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.chunks.Search(ctx, summary.ID, vector, limit)
+}
+
+// ensureIndexed embeds the summary's source unless its current text already
+// is. It reports false when there is no text to search.
+func (s *SummarySearchService) ensureIndexed(ctx context.Context, summary *models.Summary) (bool, error) {
+	source := s.sourceText(ctx, summary)
+	if strings.TrimSpace(source) == "" {
+		return false, nil
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	hash := hex.EncodeToString(sum[:])
+	current, err := s.chunks.SourceHash(ctx, summary.ID)
+	if err != nil {
+		return false, fmt.Errorf("load chunk hash: %w", err)
+	}
+	if current == hash {
+		return true, nil
+	}
+
+	passages := chunkText(source, searchChunkWords, searchChunkOverlap)
+	if len(passages) > searchMaxChunks {
+		passages = passages[:searchMaxChunks]
+	}
+	vectors, err := s.embedder.EmbedDocuments(ctx, passages)
+	if err != nil {
+		return false, err
+	}
+
+	chunks := make([]models.SummaryChunk, len(passages))
+	for i, passage := range passages {
+		chunks[i] = models.SummaryChunk{Index: i, Content: passage, Embedding: vectors[i]}
+	}
+	if err := s.chunks.ReplaceChunks(ctx, summary.ID, hash, chunks); err != nil {
+		return false, fmt.Errorf("store chunks: %w", err)
+	}
+	return true, nil
+}
+
+// sourceText is the transcript the summary was made from, or the summary
+// itself when the transcript is unavailable.
+func (s *SummarySearchService) sourceText(ctx context.Context, summary *models.Summary) string {
+	if s.contents != nil && summary.ContentID != nil {
+		content, err := s.contents.GetByID(ctx, *summary.ContentID)
+		if err == nil && content.Transcript != nil && strings.TrimSpace(*content.Transcript) != "" {
+			return *content.Transcript
+		}
+	}
+
+	if summary.ContentRaw != nil && *summary.ContentRaw != "" {
+		return *summary.ContentRaw
+	}
+	var parts []string
+	for _, part := range []*string{summary.CornellCues, summary.CornellNotes, summary.CornellSummary} {
+		if part != nil && *part != "" {
+			parts = append(parts, *part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// GroundedChatContext builds chat context from the start of the summary and
+// the source passages most relevant to the question, instead of the whole
+// summary.
+func GroundedChatContext(summaryText string, passages []models.SummarySearchResult) string {
+	overview := summaryText
+	if len(overview) > chatOverviewChars {
+		overview = strings.ToValidUTF8(overview[:chatOverviewChars], "") + "\n[...summary truncated]"
+	}
+
+	var b strings.Builder
+	b.WriteString(overview)
+	b.WriteString("\n\nRELEVANT EXCERPTS FROM THE SOURCE MATERIAL:")
+	for i, passage := range passages {
+		fmt.Fprintf(&b, "\n\n[%d] %s", i+1, passage.Content)
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+type stubChunkStore struct {
+	available bool
+	hash      string
+	replaced  int
+	chunks    []models.SummaryChunk
+}
+
+func (s *stubChunkStore) Available(ctx context.Context) (bool, error) { return s.available, nil }
+
+func (s *stubChunkStore) SourceHash(ctx context.Context, summaryID uuid.UUID) (string, error) {
+	return s.hash, nil
+}
+
+func (s *stubChunkStore) ReplaceChunks(ctx context.Context, summaryID uuid.UUID, sourceHash string, chunks []models.SummaryChunk) error {
+	s.hash = sourceHash
+	s.chunks = chunks
+	s.replaced++
+	return nil
+}
+
+func (s *stubChunkStore) Search(ctx context.Context, summaryID uuid.UUID, query []float32, limit int) ([]models.SummarySearchResult, error) {
+	results := []models.SummarySearchResult{}
+	for _, c := range s.chunks {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, models.SummarySearchResult{ChunkIndex: c.Index, Content: c.Content})
+	}
+	return results, nil
+}
+
+type stubEmbedder struct {
+	documents int
+}
+
+func (s *stubEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	s.documents += len(texts)
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{float32(i)}
+	}
+	return vectors, nil
+}
+
+func (s *stubEmbedder) EmbedQuery(ctx context.Context, query string) ([]float32, error) {
+	return []float32{1}, nil
+}
+
+type stubSourceStore struct {
+	transcript string
+}
+
+func (s *stubSourceStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Content, error) {
+	return &models.Content{ID: id, Transcript: &s.transcript}, nil
+}
+
+func TestChunkText(t *testing.T) {
+	var words []string
+	for i := 0; i < 25; i++ {
+		words = append(words, fmt.Sprintf("w%d", i))
+	}
+
+	chunks := chunkText(strings.Join(words, "  \n"), 10, 3)
+	want := []string{
+		"w0 w1 w2 w3 w4 w5 w6 w7 w8 w9",
+		"w7 w8 w9 w10 w11 w12 w13 w14 w15 w16",
+		"w14 w15 w16 w17 w18 w19 w20 w21 w22 w23",
+		"w21 w22 w23 w24",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("chunkText() = %d chunks, want %d: %q", len(chunks), len(want), chunks)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], want[i])
+		}
+	}
+
+	if got := chunkText("   ", 10, 3); got != nil {
+		t.Fatalf("chunkText(blank) = %q, want nil", got)
+	}
+}
+
+func TestSummarySearch_IndexesOnceAndReindexesOnChange(t *testing.T) {
+	contentID := uuid.New()
+	summary := &models.Summary{ID: uuid.New(), ContentID: &contentID}
+	store := &stubChunkStore{available: true}
+	embedder := &stubEmbedder{}
+	source := &stubSourceStore{transcript: strings.Repeat("photosynthesis converts light ", 150)}
+	svc := &SummarySearchService{chunks: store, embedder: embedder, contents: source}
+
+	results, err := svc.Search(context.Background(), summary, "light", 2)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 || store.replaced != 1 {
+		t.Fatalf("expected 2 results from one indexing pass, got %d results, %d passes", len(results), store.replaced)
+	}
+	if !strings.Contains(store.chunks[0].Content, "photosynthesis") {
+		t.Fatalf("expected the transcript to be indexed, got %q", store.chunks[0].Content)
+	}
+
+	if _, err := svc.Search(context.Background(), summary, "light", 2); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if store.replaced != 1 {
+		t.Fatalf("expected unchanged source not to be re-embedded, got %d passes", store.replaced)
+	}
+
+	source.transcript = "a corrected transcript"
+	if _, err := svc.Search(context.Background(), summary, "light", 2); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if store.replaced != 2 || len(store.chunks) != 1 {
+		t.Fatalf("expected changed source to be re-embedded, got %d passes, %d chunks", store.replaced, len(store.chunks))
+	}
+}
+
+func TestSummarySearch_Unavailable(t *testing.T) {
+	summary := &models.Summary{ID: uuid.New()}
+
+	var nilService *SummarySearchService
+	if _, err := nilService.Search(context.Background(), summary, "q", 5); !errors.Is(err, ErrSearchUnavailable) {
+		t.Fatalf("nil service: err = %v, want ErrSearchUnavailable", err)
+	}
+
+	svc := &SummarySearchService{chunks: &stubChunkStore{available: false}, embedder: &stubEmbedder{}}
+	if _, err := svc.Search(context.Background(), summary, "q", 5); !errors.Is(err, ErrSearchUnavailable) {
+		t.Fatalf("no pgvector: err = %v, want ErrSearchUnavailable", err)
+	}
+}
+
+func TestGroundedChatContext(t *testing.T) {
+	got := GroundedChatContext(strings.Repeat("s", chatOverviewChars+10), []models.SummarySearchResult{
+		{Content: "first passage"},
+		{Content: "second passage"},
+	})
+
+	if !strings.Contains(got, "[...summary truncated]") {
+		t.Fatalf("expected long summary to be truncated")
+	}
+	if !strings.Contains(got, "[1] first passage") || !strings.Contains(got, "[2] second passage") {
+		t.Fatalf("expected numbered passages, got %q", got[len(got)-80:])
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"lectura-backend/internal/models"
+)
+
+const (
+	// DefaultSummaryChunkChars is the longest transcript (in characters) a
+	// summary prompt quotes whole when no limit is configured. Longer
+	// transcripts are split into parts, each part is condensed into notes and
+	// the summary is written from the notes.
+	DefaultSummaryChunkChars = 120000
+
+	// transcriptChunkWindow is how far back from an even split a part may end
+	// to finish on a line or sentence instead of mid-sentence.
+	transcriptChunkWindow = 2000
+
+	// Words asked of each part's notes. The band is loose so dense parts keep
+	// their detail; the reduce pass applies the requested length.
+	chunkNotesMinWords = 400
+	chunkNotesMaxWords = 900
+
+	chunkNotesTimeout = 3 * time.Minute
+)
+
+// SetSummaryChunkChars sets the longest transcript a summary prompt quotes
+// whole. Zero or less uses DefaultSummaryChunkChars.
+func (s *GeminiService) SetSummaryChunkChars(n int) {
+	s.summaryChunkChars = n
+}
+
+func (s *GeminiService) summaryChunkLimit() int {
+	if s.summaryChunkChars <= 0 {
+		return DefaultSummaryChunkChars
+	}
+	return s.summaryChunkChars
+}
+
+// splitTranscript splits a transcript longer than maxChars characters into
+// consecutive parts of similar size, none longer than maxChars. Parts end on
+// a line break or sentence end where one is close, otherwise on whitespace.
+func splitTranscript(transcript string, maxChars int) []string {
+	total := utf8.RuneCountInString(transcript)
+	if maxChars <= 0 || total <= maxChars {
+		return []string{transcript}
+	}
+
+	parts := (total + maxChars - 1) / maxChars
+	target := (total + parts - 1) / parts
+
+	var chunks []string
+	rest := transcript
+	for utf8.RuneCountInString(rest) > maxChars {
+		cut := chunkBoundary(rest, runeOffset(rest, target))
+		if chunk := strings.TrimSpace(rest[:cut]); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		rest = rest[cut:]
+	}
+	if chunk := strings.TrimSpace(rest); chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// runeOffset returns the byte offset of the n-th rune of s, or len(s).
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// chunkBoundary moves cut back to just after the nearest line break, then
+// sentence end, then whitespace within transcriptChunkWindow bytes.
+func chunkBoundary(s string, cut int) int {
+	start := cut - transcriptChunkWindow
+	if start < 0 {
+		start = 0
+	}
+	window := s[start:cut]
+	if i := strings.LastIndex(window, "\n"); i >= 0 {
+		return start + i + 1
+	}
+	if i := lastSentenceEnd(window); i >= 0 {
+		return start + i
+	}
+	if i := strings.LastIndexAny(window, " \t"); i >= 0 {
+		return start + i + 1
+	}
+	return cut
+}
+
+// lastSentenceEnd returns the offset just past the last ". ", "? " or "! "
+// in s, or -1.
+func lastSentenceEnd(s string) int {
+	end := -1
+	for _, mark := range []string{". ", "? ", "! "} {
+		if i := strings.LastIndex(s, mark); i >= 0 && i+len(mark) > end {
+			end = i + len(mark)
+		}
+	}
+	return end
+}
+
+// buildChunkNotesPrompt asks for notes on one part of a long transcript. The
+// notes keep facts rather than prose, since the reduce pass writes the summary.
+func buildChunkNotesPrompt(part, parts int, chunk string, focusAreas []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `You are reading part %d of %d of a long lecture transcript. The other parts are handled separately.
+
+Write thorough study notes on THIS part only:
+- Group the notes under short "##" headings that follow the order of the lecture.
+- Use "-" bullets. Keep every definition, name, date, number, formula, example, and step the speaker gives.
+- Note comparisons, cause and effect, and any point the speaker stresses.
+- Write %d-%d words. Do not add an introduction or a conclusion, and do not guess what other parts cover.
+- Write the notes in the language of the transcript.
+`, part, parts, chunkNotesMinWords, chunkNotesMaxWords)
+	if len(focusAreas) > 0 {
+		fmt.Fprintf(&b, "- Give extra detail on: %s.\n", strings.Join(focusAreas, ", "))
+	}
+	fmt.Fprintf(&b, "\nTranscript part %d of %d:\n%s\n", part, parts, chunk)
+	return b.String()
+}
+
+// mergeChunkNotes joins the notes of every part, in order, into the source
+// text the summary prompt reads in place of the transcript.
+func mergeChunkNotes(notes []string) string {
+	var b strings.Builder
+	b.WriteString("The lecture was too long to read at once, so it was split into consecutive parts and each part was condensed into notes. Treat these notes, in order, as the full lecture transcript and cover all parts in the summary.\n")
+	for i, note := range notes {
+		fmt.Fprintf(&b, "\n[Part %d of %d]\n%s\n", i+1, len(notes), strings.TrimSpace(note))
+	}
+	return b.String()
+}
+
+// summarizeTranscriptChunks condenses each part of a transcript into notes,
+// one call per part, and returns them merged for the summary prompt.
+func (s *GeminiService) summarizeTranscriptChunks(ctx context.Context, job *models.Job, model *genai.GenerativeModel, chunks []string, focusAreas []string) (string, error) {
+	notes := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		s.PublishUpdate(ctx, job.UserID, models.WSMessage{
+			Type: "status_update",
+			Payload: models.StatusUpdate{
+				JobID: job.ID, Step: 3, StepName: fmt.Sprintf("Reading part %d of %d", i+1, len(chunks)),
+				EstimatedSecondsRemaining: 30 * (len(chunks) - i + 1),
+			},
+		})
+
+		prompt := buildChunkNotesPrompt(i+1, len(chunks), chunk, focusAreas)
+		resp, err := generateContentWithTimeout(ctx, model, chunkNotesTimeout, genai.Text(prompt))
+		if err != nil {
+			if reason := safetyBlockReason(nil, err); reason != "" {
+				log.Printf("Gemini blocked transcript part %d of %d for job %s: %s", i+1, len(chunks), job.ID, reason)
+				return "", safetyBlockedError(reason)
+			}
+			return "", fmt.Errorf("failed to summarize transcript part %d of %d: %w", i+1, len(chunks), err)
+		}
+
+		text := strings.TrimSpace(extractText(resp))
+		if text == "" {
+			if reason := safetyBlockReason(resp, nil); reason != "" {
+				log.Printf("Gemini blocked transcript part %d of %d for job %s: %s", i+1, len(chunks), job.ID, reason)
+				return "", safetyBlockedError(reason)
+			}
+			return "", fmt.Errorf("Gemini returned no notes for transcript part %d of %d", i+1, len(chunks))
+		}
+		notes[i] = text
+	}
+	return mergeChunkNotes(notes), nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitTranscript_ShortPassesThrough(t *testing.T) {
+	short := "a short lecture"
+	if got := splitTranscript(short, 100); len(got) != 1 || got[0] != short {
+		t.Fatalf("expected one unchanged part, got %q", got)
+	}
+}
+
+func TestSplitTranscript_EvenPartsOnSentences(t *testing.T) {
+	sentence := "The cell membrane controls what enters the cell. "
+	transcript := strings.Repeat(sentence, 100) // 5000 characters
+	chunks := splitTranscript(transcript, 2000)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(chunks))
+	}
+	var total int
+	for i, chunk := range chunks {
+		if utf8.RuneCountInString(chunk) > 2000 {
+			t.Fatalf("part %d has %d characters, over the limit", i, utf8.RuneCountInString(chunk))
+		}
+		if !strings.HasSuffix(chunk, "cell.") {
+			t.Fatalf("part %d does not end on a sentence: %q", i, chunk[len(chunk)-20:])
+		}
+		total += len(chunk)
+	}
+	if rejoined := strings.Join(chunks, " ") + " "; rejoined != transcript {
+		t.Fatalf("expected the parts to cover the whole transcript, got %d of %d bytes", total, len(transcript))
+	}
+}
+
+func TestSplitTranscript_PrefersLineBreaks(t *testing.T) {
+	line := strings.Repeat("word ", 39) + "end.\n" // 200 characters
+	chunks := splitTranscript(strings.Repeat(line, 10), 900)
+	for i, chunk := range chunks {
+		if !strings.HasSuffix(chunk, "end.") {
+			t.Fatalf("part %d does not end on a line: %q", i, chunk[len(chunk)-10:])
+		}
+	}
+}
+
+func TestSplitTranscript_KeepsRunesWhole(t *testing.T) {
+	chunks := splitTranscript(strings.Repeat("я", 1000), 300)
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 parts, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !utf8.ValidString(chunk) || utf8.RuneCountInString(chunk) > 300 {
+			t.Fatalf("part %d is invalid or over the limit: %d characters", i, utf8.RuneCountInString(chunk))
+		}
+	}
+}
+
+func TestMergeChunkNotes_LabelsPartsInOrder(t *testing.T) {
+	merged := mergeChunkNotes([]string{"## Intro\n- first", "  ## Later\n- second  "})
+	first := strings.Index(merged, "[Part 1 of 2]\n## Intro")
+	second := strings.Index(merged, "[Part 2 of 2]\n## Later\n- second\n")
+	if first < 0 || second < first {
+		t.Fatalf("expected labelled parts in order, got %q", merged)
+	}
+}
+
+func TestBuildChunkNotesPrompt(t *testing.T) {
+	prompt := buildChunkNotesPrompt(2, 3, "transcript text", []string{"formulas", "dates"})
+	for _, want := range []string{"part 2 of 3", "Give extra detail on: formulas, dates.", "Transcript part 2 of 3:\ntranscript text"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("expected prompt to contain %q", want)
+		}
+	}
+	if strings.Contains(buildChunkNotesPrompt(1, 2, "x", nil), "extra detail") {
+		t.Fatal("expected no focus line without focus areas")
+	}
+}
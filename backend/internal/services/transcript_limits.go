@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Transcript and excerpt limits. Prompts that quote source or summary text
+// take a prefix of it; the sizes live here so it is visible what each step
+// sees.
+const (
+	// DefaultMaxTranscriptChars is the longest transcript (in characters) a
+	// summary is generated from when no limit is configured. Longer
+	// transcripts are cut and the summary records how much of them it covers.
+	DefaultMaxTranscriptChars = 400000
+
+	// transcriptEvidenceChars is how much of the transcript the smart summary
+	// fidelity rewrite and table rebuild quote as evidence.
+	transcriptEvidenceChars = 12000
+
+	// Summary excerpts read by the calls that run after the summary is written.
+	summaryMetadataExcerptChars = 6000
+	summaryFollowUpExcerptChars = 4000
+	summaryOverviewExcerptChars = 3000
+
+	// transcriptCutWindow is how far back from the limit a cut may move to
+	// end on whitespace instead of mid-word.
+	transcriptCutWindow = 500
+)
+
+// SetMaxTranscriptChars sets the longest transcript a summary is generated
+// from. Zero or less uses DefaultMaxTranscriptChars.
+func (s *GeminiService) SetMaxTranscriptChars(n int) {
+	s.maxTranscriptChars = n
+}
+
+func (s *GeminiService) transcriptLimit() int {
+	if s.maxTranscriptChars <= 0 {
+		return DefaultMaxTranscriptChars
+	}
+	return s.maxTranscriptChars
+}
+
+// limitTranscript returns transcript cut to at most maxChars characters,
+// ending on whitespace where possible. When it had to cut, it also returns
+// the percentage of the transcript that was kept (at least 1).
+func limitTranscript(transcript string, maxChars int) (string, *int) {
+	total := utf8.RuneCountInString(transcript)
+	if maxChars <= 0 || total <= maxChars {
+		return transcript, nil
+	}
+
+	cut := len(transcript)
+	for i := range transcript {
+		if maxChars == 0 {
+			cut = i
+			break
+		}
+		maxChars--
+	}
+	kept := transcript[:cut]
+	if space := strings.LastIndexAny(kept, " \t\n"); space > 0 && cut-space <= transcriptCutWindow {
+		kept = kept[:space]
+	}
+
+	percent := utf8.RuneCountInString(kept) * 100 / total
+	if percent < 1 {
+		percent = 1
+	}
+	return kept, &percent
+}
+
+// clipText returns at most n bytes of s, cut back to a rune boundary.
+func clipText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// transcriptEvidence is the transcript excerpt quoted to repair steps. The
+// note tells the model when the excerpt stops before the lecture does, so it
+// keeps summary points drawn from the part it cannot see.
+func transcriptEvidence(transcript string) (excerpt, note string) {
+	excerpt = clipText(transcript, transcriptEvidenceChars)
+	if len(excerpt) < len(transcript) {
+		note = "The excerpt covers only the beginning of the transcript. Keep summary points about later parts of the lecture even though the excerpt does not show them.\n"
+	}
+	return excerpt, note
+}
+
+// SourceTruncationNotice is the message shown with a summary generated from
+// part of its transcript.
+func SourceTruncationNotice(coveragePercent int) string {
+	return fmt.Sprintf("This summary is based on the first %d%% of the content; the rest exceeded the maximum transcript length.", coveragePercent)
+}
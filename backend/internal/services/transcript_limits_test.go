@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLimitTranscript(t *testing.T) {
+	short := "a short lecture"
+	if got, coverage := limitTranscript(short, 100); got != short || coverage != nil {
+		t.Fatalf("expected a short transcript to pass through, got %q (%v)", got, coverage)
+	}
+
+	long := strings.Repeat("word ", 200) // 1000 characters
+	got, coverage := limitTranscript(long, 250)
+	if coverage == nil {
+		t.Fatal("expected coverage for a cut transcript")
+	}
+	if len(got) > 250 || strings.HasSuffix(got, "wor") {
+		t.Fatalf("expected a cut on a word boundary within the limit, got %d chars ending %q", len(got), got[len(got)-5:])
+	}
+	if *coverage != 24 {
+		t.Fatalf("expected 24%% coverage, got %d", *coverage)
+	}
+
+	cyrillic := strings.Repeat("я", 1000)
+	got, coverage = limitTranscript(cyrillic, 100)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != 100 || coverage == nil || *coverage != 10 {
+		t.Fatalf("expected 100 whole characters and 10%% coverage, got %d (%v)", utf8.RuneCountInString(got), coverage)
+	}
+}
+
+func TestClipText_KeepsRunesWhole(t *testing.T) {
+	if got := clipText("héllo", 2); got != "h" {
+		t.Fatalf("expected the split rune to be dropped, got %q", got)
+	}
+	if got := clipText("hello", 10); got != "hello" {
+		t.Fatalf("expected short text unchanged, got %q", got)
+	}
+}
+
+func TestTranscriptEvidence_NotesPartialExcerpt(t *testing.T) {
+	if _, note := transcriptEvidence("short transcript"); note != "" {
+		t.Fatalf("expected no note for a complete excerpt, got %q", note)
+	}
+	excerpt, note := transcriptEvidence(strings.Repeat("b", transcriptEvidenceChars+10))
+	if len(excerpt) != transcriptEvidenceChars || note == "" {
+		t.Fatalf("expected a clipped excerpt with a note, got %d chars and %q", len(excerpt), note)
+	}
+}
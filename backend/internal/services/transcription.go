@@ -0,0 +1,249 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/google/uuid"
+)
+
+const (
+	// Audio longer than longAudioSeconds is transcribed in segments of
+	// transcriptionSegmentSeconds, so progress can be reported and one bad
+	// segment does not lose the whole lecture.
+	longAudioSeconds            = 30 * 60
+	transcriptionSegmentSeconds = 15 * 60
+	// transcriptionSegmentAttempts is how often a segment is tried before it
+	// is skipped.
+	transcriptionSegmentAttempts = 2
+
+	audioActivePollAttempts = 20
+	audioActivePollInterval = 2 * time.Second
+)
+
+// TranscriptionProgress receives the current transcription step and an
+// estimate of the seconds left (0 when unknown).
+type TranscriptionProgress func(stepName string, estimatedSecondsRemaining int)
+
+func (p TranscriptionProgress) report(stepName string, estimatedSecondsRemaining int) {
+	if p != nil {
+		p(stepName, estimatedSecondsRemaining)
+	}
+}
+
+// TranscribeAudio uses Gemini File API to transcribe an audio file on disk. The
+// file is streamed to the upload rather than read into memory. Long audio is
+// split into segments that are transcribed in order; progress may be nil.
+func (s *GeminiService) TranscribeAudio(ctx context.Context, audioPath string, mimeType string, progress TranscriptionProgress) (string, error) {
+	ctx = s.trackUsage(ctx, uuid.Nil, UsageOpTranscribe)
+	if stat, err := os.Stat(audioPath); err != nil {
+		return "", fmt.Errorf("failed to open audio file: %w", err)
+	} else if stat.Size() == 0 {
+		return "", fmt.Errorf("audio payload is empty")
+	}
+
+	duration, err := audioDurationSeconds(ctx, audioPath)
+	if err != nil || duration <= longAudioSeconds {
+		if err != nil {
+			log.Printf("TranscribeAudio: could not read duration of %s, transcribing in one pass: %v", audioPath, err)
+		}
+		return s.transcribeAudioFile(ctx, audioPath, mimeType, progress)
+	}
+
+	segmentDir, err := os.MkdirTemp("", "lectura-audio-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create segment directory: %w", err)
+	}
+	defer os.RemoveAll(segmentDir)
+
+	progress.report("Splitting audio for transcription", 0)
+	segments, err := splitAudio(ctx, audioPath, segmentDir, transcriptionSegmentSeconds)
+	if err != nil {
+		log.Printf("TranscribeAudio: splitting %s failed, transcribing in one pass: %v", audioPath, err)
+		return s.transcribeAudioFile(ctx, audioPath, mimeType, progress)
+	}
+
+	return transcribeSegments(ctx, segments, func(ctx context.Context, path string) (string, error) {
+		return s.transcribeAudioFile(ctx, path, mimeType, nil)
+	}, progress)
+}
+
+// transcribeSegments transcribes segments in order and joins the results.
+// A segment that keeps failing is skipped; only losing every segment fails.
+func transcribeSegments(ctx context.Context, segments []string, transcribe func(context.Context, string) (string, error), progress TranscriptionProgress) (string, error) {
+	started := time.Now()
+	parts := make([]string, 0, len(segments))
+	var lastErr error
+
+	for i, segment := range segments {
+		remaining := 0
+		if i > 0 {
+			perSegment := time.Since(started) / time.Duration(i)
+			remaining = int((perSegment * time.Duration(len(segments)-i)).Seconds())
+		}
+		progress.report(fmt.Sprintf("Transcribing audio (part %d of %d)", i+1, len(segments)), remaining)
+
+		var text string
+		var err error
+		for attempt := 0; attempt < transcriptionSegmentAttempts; attempt++ {
+			if text, err = transcribe(ctx, segment); err == nil || ctx.Err() != nil {
+				break
+			}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		if err != nil {
+			log.Printf("TranscribeAudio: skipping part %d of %d: %v", i+1, len(segments), err)
+			lastErr = err
+			continue
+		}
+		parts = append(parts, text)
+	}
+
+	if len(parts) == 0 {
+		return "", fmt.Errorf("every audio segment failed to transcribe: %w", lastErr)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// transcribeAudioFile uploads one audio file, waits until Gemini has
+// processed it and returns its transcription.
+func (s *GeminiService) transcribeAudioFile(ctx context.Context, audioPath string, mimeType string, progress TranscriptionProgress) (string, error) {
+	if err := s.acquireRate(ctx); err != nil {
+		return "", err
+	}
+	defer s.releaseRate()
+
+	audio, err := os.Open(audioPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer audio.Close()
+
+	progress.report("Uploading audio for transcription", 0)
+	file, err := s.client.UploadFile(ctx, "", audio, &genai.UploadFileOptions{
+		DisplayName: "youtube-audio",
+		MIMEType:    mimeType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload audio to Gemini: %w", err)
+	}
+
+	// Ensure remote file is cleaned up
+	defer s.client.DeleteFile(context.Background(), file.Name)
+
+	progress.report("Waiting for audio to be processed", 0)
+	for i := 0; i < audioActivePollAttempts; i++ {
+		current, getErr := s.client.GetFile(ctx, file.Name)
+		if getErr != nil {
+			return "", fmt.Errorf("failed to get uploaded file status: %w", getErr)
+		}
+
+		if current.State == genai.FileStateActive {
+			file = current
+			break
+		}
+		if current.State == genai.FileStateFailed {
+			return "", fmt.Errorf("Gemini failed to process uploaded audio file")
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(audioActivePollInterval):
+		}
+	}
+
+	if file.State != genai.FileStateActive {
+		return "", fmt.Errorf("audio file did not become active in time")
+	}
+
+	progress.report("Transcribing audio", 0)
+	prompt := "Transcribe the provided audio verbatim. Return plain text only, without markdown, headers, or explanations."
+
+	resp, err := generateContent(ctx, s.model,
+		genai.Text(prompt),
+		genai.FileData{MIMEType: mimeType, URI: file.URI},
+	)
+	if err != nil {
+		if reason := safetyBlockReason(nil, err); reason != "" {
+			return "", safetyBlockedError(reason)
+		}
+		return "", fmt.Errorf("Gemini transcription error: %w", err)
+	}
+
+	text := strings.TrimSpace(extractText(resp))
+	if text == "" {
+		if reason := safetyBlockReason(resp, nil); reason != "" {
+			return "", safetyBlockedError(reason)
+		}
+		return "", fmt.Errorf("Gemini returned empty transcription")
+	}
+
+	return text, nil
+}
+
+// audioDurationSeconds reads the length of an audio file with ffprobe.
+func audioDurationSeconds(ctx context.Context, path string) (float64, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe returned no duration: %w", err)
+	}
+	return duration, nil
+}
+
+// splitAudio cuts an audio file into consecutive segments of about
+// segmentSeconds without re-encoding, and returns their paths in order.
+func splitAudio(ctx context.Context, path, dir string, segmentSeconds int) ([]string, error) {
+	ext := filepath.Ext(path)
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner",
+		"-loglevel", "error",
+		"-i", path,
+		"-f", "segment",
+		"-segment_time", strconv.Itoa(segmentSeconds),
+		"-c", "copy",
+		"-vn",
+		filepath.Join(dir, "segment-%04d"+ext),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return nil, fmt.Errorf("ffmpeg segment failed: %s", errMsg)
+	}
+
+	segments, err := filepath.Glob(filepath.Join(dir, "segment-*"+ext))
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("ffmpeg produced no segments")
+	}
+	sort.Strings(segments)
+	return segments, nil
+}
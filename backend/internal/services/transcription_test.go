@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTranscribeSegments_SkipsFailedSegment(t *testing.T) {
+	calls := map[string]int{}
+	transcribe := func(ctx context.Context, path string) (string, error) {
+		calls[path]++
+		if path == "b" {
+			return "", errors.New("upload failed")
+		}
+		return "text " + path, nil
+	}
+	var steps []string
+	progress := func(stepName string, _ int) { steps = append(steps, stepName) }
+
+	text, err := transcribeSegments(context.Background(), []string{"a", "b", "c"}, transcribe, progress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "text a\n\ntext c" {
+		t.Fatalf("unexpected transcript %q", text)
+	}
+	if calls["b"] != transcriptionSegmentAttempts {
+		t.Fatalf("expected failed segment to be tried %d times, got %d", transcriptionSegmentAttempts, calls["b"])
+	}
+	if strings.Join(steps, "|") != "Transcribing audio (part 1 of 3)|Transcribing audio (part 2 of 3)|Transcribing audio (part 3 of 3)" {
+		t.Fatalf("unexpected progress %v", steps)
+	}
+}
+
+func TestTranscribeSegments_RetriesSegment(t *testing.T) {
+	failed := false
+	transcribe := func(ctx context.Context, path string) (string, error) {
+		if !failed {
+			failed = true
+			return "", errors.New("transient")
+		}
+		return "ok", nil
+	}
+
+	text, err := transcribeSegments(context.Background(), []string{"a"}, transcribe, nil)
+	if err != nil || text != "ok" {
+		t.Fatalf("expected retry to succeed, got %q, %v", text, err)
+	}
+}
+
+func TestTranscribeSegments_AllFail(t *testing.T) {
+	transcribe := func(ctx context.Context, path string) (string, error) {
+		return "", errors.New("quota")
+	}
+
+	if _, err := transcribeSegments(context.Background(), []string{"a", "b"}, transcribe, nil); err == nil {
+		t.Fatalf("expected error when every segment fails")
+	}
+}
+
+func TestTranscribeSegments_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	transcribe := func(ctx context.Context, path string) (string, error) {
+		calls++
+		cancel()
+		return "", ctx.Err()
+	}
+
+	if _, err := transcribeSegments(ctx, []string{"a", "b"}, transcribe, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no calls after cancellation, got %d", calls)
+	}
+}
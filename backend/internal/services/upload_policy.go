@@ -0,0 +1,116 @@
+package services
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultUploadMaxBytes is the upload size cap used when none is configured.
+const DefaultUploadMaxBytes int64 = 100 * 1024 * 1024
+
+// UploadFormat is a file type the worker knows how to extract text from.
+type UploadFormat struct {
+	Extension   string `json:"extension"`
+	MimeType    string `json:"mime_type"`
+	Description string `json:"description"`
+}
+
+// knownUploadFormats lists every format processContent can handle. Deployments
+// can narrow this list, but cannot add types the worker has no extractor for.
+var knownUploadFormats = []UploadFormat{
+	{Extension: ".pdf", MimeType: "application/pdf", Description: "PDF Document"},
+	{Extension: ".docx", MimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Description: "Word Document"},
+	{Extension: ".txt", MimeType: "text/plain", Description: "Plain Text"},
+	{Extension: ".srt", MimeType: "application/x-subrip", Description: "SubRip Subtitles"},
+	{Extension: ".vtt", MimeType: "text/vtt", Description: "WebVTT Subtitles"},
+	{Extension: ".png", MimeType: "image/png", Description: "PNG Image (text recognized via OCR)"},
+	{Extension: ".jpg", MimeType: "image/jpeg", Description: "JPEG Image (text recognized via OCR)"},
+	{Extension: ".jpeg", MimeType: "image/jpeg", Description: "JPEG Image (text recognized via OCR)"},
+}
+
+// UploadPolicy is the per-deployment file upload configuration.
+type UploadPolicy struct {
+	MaxBytes int64
+	Formats  []UploadFormat
+}
+
+// DefaultUploadPolicy allows every known format up to DefaultUploadMaxBytes.
+func DefaultUploadPolicy() UploadPolicy {
+	return UploadPolicy{MaxBytes: DefaultUploadMaxBytes, Formats: knownUploadFormats}
+}
+
+// NewUploadPolicy builds a policy from configuration. A non-positive maxBytes or
+// an empty extension list falls back to the defaults; unknown extensions are
+// ignored with a warning.
+func NewUploadPolicy(maxBytes int64, extensions []string) UploadPolicy {
+	policy := DefaultUploadPolicy()
+	if maxBytes > 0 {
+		policy.MaxBytes = maxBytes
+	}
+	if len(extensions) == 0 {
+		return policy
+	}
+
+	formats := make([]UploadFormat, 0, len(extensions))
+	for _, raw := range extensions {
+		ext := normalizeExtension(raw)
+		found := false
+		for _, f := range knownUploadFormats {
+			if f.Extension == ext {
+				formats = append(formats, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Printf("WARNING: ignoring upload extension %q: no text extractor for this type", raw)
+		}
+	}
+	if len(formats) == 0 {
+		log.Println("WARNING: no supported upload extensions configured, falling back to defaults")
+		return policy
+	}
+	policy.Formats = formats
+	return policy
+}
+
+// AllowsExtension reports whether files with the given extension may be uploaded.
+func (p UploadPolicy) AllowsExtension(ext string) bool {
+	ext = normalizeExtension(ext)
+	for _, f := range p.Formats {
+		if f.Extension == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMimeType reports whether the sniffed MIME type belongs to an allowed format.
+func (p UploadPolicy) AllowsMimeType(mime string) bool {
+	for _, f := range p.Formats {
+		if f.MimeType == mime {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows checks a sniffed MIME type, falling back to the file extension since
+// http.DetectContentType reports DOCX and some PDFs as generic types.
+func (p UploadPolicy) Allows(mime, filename string) bool {
+	return p.AllowsMimeType(mime) || p.AllowsExtension(filepath.Ext(filename))
+}
+
+// MaxMegabytes is the size cap rounded down to whole megabytes, for messages.
+func (p UploadPolicy) MaxMegabytes() int64 {
+	return p.MaxBytes / (1024 * 1024)
+}
+
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
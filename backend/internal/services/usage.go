@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+// Operation types usage is reported under.
+const (
+	UsageOpSummary      = "summary"
+	UsageOpQuiz         = "quiz"
+	UsageOpFlashcards   = "flashcard"
+	UsageOpPresentation = "presentation"
+	UsageOpChat         = "chat"
+	UsageOpTranscribe   = "transcription"
+)
+
+const usageRecordTimeout = 5 * time.Second
+
+type usageRecorder interface {
+	RecordUsage(ctx context.Context, e *models.UsageEvent) error
+}
+
+type usageScopeKey struct{}
+
+// usageScope says whom the Gemini calls made with a context are billed to.
+// Calls without a recorder or user are not recorded.
+type usageScope struct {
+	userID    uuid.UUID
+	jobID     uuid.UUID
+	operation string
+	recorder  usageRecorder
+}
+
+func usageScopeFrom(ctx context.Context) usageScope {
+	scope, _ := ctx.Value(usageScopeKey{}).(usageScope)
+	return scope
+}
+
+// WithUsageUser attributes Gemini calls made with the returned context to
+// userID. Handlers use it for calls that are not driven by a job.
+func WithUsageUser(ctx context.Context, userID uuid.UUID) context.Context {
+	scope := usageScopeFrom(ctx)
+	scope.userID = userID
+	return context.WithValue(ctx, usageScopeKey{}, scope)
+}
+
+// WithUsageJob attributes Gemini calls made with the returned context to the
+// job and its owner.
+func WithUsageJob(ctx context.Context, job *models.Job) context.Context {
+	scope := usageScopeFrom(ctx)
+	scope.userID = job.UserID
+	scope.jobID = job.ID
+	return context.WithValue(ctx, usageScopeKey{}, scope)
+}
+
+// SetUsageRecorder turns on per-user usage accounting.
+func (s *GeminiService) SetUsageRecorder(recorder usageRecorder) {
+	s.usage = recorder
+}
+
+// trackUsage tags Gemini calls made with the returned context with the
+// operation. userID may be uuid.Nil to keep the user set by WithUsageUser.
+func (s *GeminiService) trackUsage(ctx context.Context, userID uuid.UUID, operation string) context.Context {
+	if s.usage == nil {
+		return ctx
+	}
+	scope := usageScopeFrom(ctx)
+	if userID != uuid.Nil {
+		scope.userID = userID
+	}
+	scope.operation = operation
+	scope.recorder = s.usage
+	return context.WithValue(ctx, usageScopeKey{}, scope)
+}
+
+// trackJobUsage is trackUsage for the calls that process a job.
+func (s *GeminiService) trackJobUsage(ctx context.Context, job *models.Job, operation string) context.Context {
+	return s.trackUsage(WithUsageJob(ctx, job), uuid.Nil, operation)
+}
+
+// estimateTokens approximates the token count of text prompt parts at four
+// characters per token. Files and images are not counted.
+func estimateTokens(parts []genai.Part) int {
+	chars := 0
+	for _, p := range parts {
+		if text, ok := p.(genai.Text); ok {
+			chars += utf8.RuneCountInString(string(text))
+		}
+	}
+	return (chars + 3) / 4
+}
+
+// usageFromResponse reads prompt and completion token counts from a response,
+// falling back to estimates where the API left them out.
+func usageFromResponse(resp *genai.GenerateContentResponse, parts []genai.Part) (prompt, completion int) {
+	if resp == nil {
+		return estimateTokens(parts), 0
+	}
+	for _, cand := range resp.Candidates {
+		if cand != nil {
+			completion += int(cand.TokenCount)
+		}
+	}
+	if meta := resp.UsageMetadata; meta != nil {
+		prompt = int(meta.PromptTokenCount)
+		if completion == 0 {
+			completion = int(meta.CandidatesTokenCount)
+		}
+	}
+	if prompt == 0 {
+		prompt = estimateTokens(parts)
+	}
+	return prompt, completion
+}
+
+// recordUsage stores a successful call against the scope in ctx. Failures
+// are logged; accounting never fails the call it measures.
+func recordUsage(ctx context.Context, resp *genai.GenerateContentResponse, parts []genai.Part) {
+	scope := usageScopeFrom(ctx)
+	if scope.recorder == nil || scope.userID == uuid.Nil || scope.operation == "" {
+		return
+	}
+
+	prompt, completion := usageFromResponse(resp, parts)
+	event := &models.UsageEvent{
+		UserID:           scope.userID,
+		Operation:        scope.operation,
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+	}
+	if scope.jobID != uuid.Nil {
+		jobID := scope.jobID
+		event.JobID = &jobID
+	}
+
+	// The call may have used up ctx's deadline; the write should still land.
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageRecordTimeout)
+	defer cancel()
+	if err := scope.recorder.RecordUsage(recordCtx, event); err != nil {
+		log.Printf("Failed to record Gemini usage for user %s (%s): %v", scope.userID, scope.operation, err)
+	}
+}
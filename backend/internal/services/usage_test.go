@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+)
+
+type stubUsageRecorder struct {
+	events []*models.UsageEvent
+	err    error
+}
+
+func (s *stubUsageRecorder) RecordUsage(_ context.Context, e *models.UsageEvent) error {
+	s.events = append(s.events, e)
+	return s.err
+}
+
+func TestUsageFromResponse_PrefersReportedCounts(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates:    []*genai.Candidate{{TokenCount: 120}, {TokenCount: 30}},
+		UsageMetadata: &genai.UsageMetadata{PromptTokenCount: 900, CandidatesTokenCount: 999},
+	}
+
+	prompt, completion := usageFromResponse(resp, []genai.Part{genai.Text("ignored")})
+	if prompt != 900 || completion != 150 {
+		t.Fatalf("usage = %d/%d, want 900/150", prompt, completion)
+	}
+}
+
+func TestUsageFromResponse_EstimatesMissingCounts(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates:    []*genai.Candidate{{}},
+		UsageMetadata: &genai.UsageMetadata{CandidatesTokenCount: 40},
+	}
+
+	prompt, completion := usageFromResponse(resp, []genai.Part{genai.Text("abcdefgh"), genai.Text("ij")})
+	if prompt != 3 || completion != 40 {
+		t.Fatalf("usage = %d/%d, want 3/40", prompt, completion)
+	}
+}
+
+func TestRecordUsage_AttributesToScope(t *testing.T) {
+	recorder := &stubUsageRecorder{}
+	s := &GeminiService{}
+	s.SetUsageRecorder(recorder)
+	userID := uuid.New()
+
+	ctx := s.trackUsage(WithUsageUser(context.Background(), userID), uuid.Nil, UsageOpChat)
+	recordUsage(ctx, &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{TokenCount: 7}}}, []genai.Part{genai.Text("hello world!")})
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(recorder.events))
+	}
+	e := recorder.events[0]
+	if e.UserID != userID || e.JobID != nil || e.Operation != UsageOpChat || e.PromptTokens != 3 || e.CompletionTokens != 7 {
+		t.Fatalf("event = %+v", e)
+	}
+}
+
+func TestRecordUsage_AttributesToJob(t *testing.T) {
+	recorder := &stubUsageRecorder{}
+	s := &GeminiService{}
+	s.SetUsageRecorder(recorder)
+	job := &models.Job{ID: uuid.New(), UserID: uuid.New()}
+
+	recordUsage(s.trackJobUsage(context.Background(), job, UsageOpSummary), &genai.GenerateContentResponse{}, nil)
+	recordUsage(s.trackUsage(WithUsageJob(context.Background(), job), uuid.Nil, UsageOpTranscribe), &genai.GenerateContentResponse{}, nil)
+
+	if len(recorder.events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(recorder.events))
+	}
+	for _, e := range recorder.events {
+		if e.UserID != job.UserID || e.JobID == nil || *e.JobID != job.ID {
+			t.Fatalf("event = %+v, want job %s of user %s", e, job.ID, job.UserID)
+		}
+	}
+	if recorder.events[1].Operation != UsageOpTranscribe {
+		t.Fatalf("operation = %q, want %q", recorder.events[1].Operation, UsageOpTranscribe)
+	}
+}
+
+func TestRecordUsage_SkipsUnattributedCalls(t *testing.T) {
+	recorder := &stubUsageRecorder{}
+	s := &GeminiService{}
+	s.SetUsageRecorder(recorder)
+	resp := &genai.GenerateContentResponse{}
+
+	recordUsage(context.Background(), resp, nil)
+	recordUsage(s.trackUsage(context.Background(), uuid.Nil, UsageOpSummary), resp, nil)
+	recordUsage((&GeminiService{}).trackUsage(WithUsageUser(context.Background(), uuid.New()), uuid.Nil, UsageOpQuiz), resp, nil)
+
+	if len(recorder.events) != 0 {
+		t.Fatalf("recorded %d events, want 0", len(recorder.events))
+	}
+}
+
+func TestRecordUsage_RecorderErrorIsSwallowed(t *testing.T) {
+	recorder := &stubUsageRecorder{err: errors.New("db down")}
+	s := &GeminiService{}
+	s.SetUsageRecorder(recorder)
+
+	ctx := s.trackUsage(context.Background(), uuid.New(), UsageOpFlashcards)
+	recordUsage(ctx, &genai.GenerateContentResponse{}, nil)
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(recorder.events))
+	}
+}
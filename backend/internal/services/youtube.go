@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"html"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
 	"regexp"
 	"sort"
 	"strings"
@@ -16,6 +19,9 @@ import (
 
 	ytapi "github.com/hightemp/youtube-transcript-api-go/api"
 	yt "github.com/kkdai/youtube/v2"
+	"golang.org/x/sync/singleflight"
+
+	"lectura-backend/internal/models"
 )
 
 type YouTubeService struct {
@@ -23,6 +29,8 @@ type YouTubeService struct {
 	transcriptAPI *ytapi.YouTubeTranscriptApi
 	supadataAPIKey string
 	ytClient      *yt.Client
+	cache         *youtubeCache
+	inflight      singleflight.Group
 }
 
 type timedTextXML struct {
@@ -36,6 +44,17 @@ type textXML struct {
 	Text  string `xml:",chardata"`
 }
 
+// transcriptFetchTimeout bounds one shared transcript fetch. The fetch runs on
+// its own context so a caller that gives up does not fail the others waiting
+// on it; this is long enough for every source's own timeout to run out.
+const transcriptFetchTimeout = 2 * time.Minute
+
+var (
+	captionTracksPattern    = regexp.MustCompile(`"captionTracks"\s*:\s*\[(.*?)\],\s*"`)
+	captionTracklistPattern = regexp.MustCompile(`"playerCaptionsTracklistRenderer"\s*:\s*\{(?:.*?,)?\s*"captionTracks"\s*:\s*\[(.*?)\],\s*"`)
+	playableStatusPattern   = regexp.MustCompile(`"playabilityStatus"\s*:\s*\{\s*"status"\s*:\s*"OK"`)
+)
+
 func NewYouTubeService(supadataAPIKey string) *YouTubeService {
 	return &YouTubeService{
 		httpClient:    YouTubeHTTPClient,
@@ -46,13 +65,67 @@ func NewYouTubeService(supadataAPIKey string) *YouTubeService {
 }
 
 // GetTranscript fetches the auto-generated captions for a YouTube video.
+// Cached transcripts (and recent "no captions" results) are served from Redis
+// when caching is enabled; concurrent lookups for the same video share one fetch.
+//
+// language selects the caption track (e.g. "es"); "" or CaptionLanguageAny
+// prefers English and falls back to any track. When a specific language is
+// requested but the video only has other tracks, the error wraps
+// ErrCaptionLanguageUnavailable; a video with no tracks at all gives
+// ErrNoCaptions.
+func (s *YouTubeService) GetTranscript(ctx context.Context, videoID, language string) (string, error) {
+	if language == CaptionLanguageAny {
+		language = ""
+	}
+	cacheID := videoID
+	if language != "" {
+		cacheID = videoID + ":" + language
+	}
+
+	cached, hit, err := s.cache.cachedTranscript(ctx, cacheID)
+	if hit {
+		if err != nil {
+			return "", err
+		}
+		log.Printf("Transcript served from cache for %s", cacheID)
+		return cached, nil
+	}
+
+	ch := s.inflight.DoChan("transcript:"+cacheID, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), transcriptFetchTimeout)
+		defer cancel()
+
+		transcript, fetchErr := s.fetchTranscript(fetchCtx, videoID, language)
+		if fetchErr != nil {
+			if errors.Is(fetchErr, ErrNoCaptions) {
+				s.cache.storeNoCaptions(fetchCtx, cacheID)
+			}
+			return "", fetchErr
+		}
+		s.cache.storeTranscript(fetchCtx, cacheID, transcript)
+		return transcript, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return "", res.Err
+		}
+		return res.Val.(string), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// fetchTranscript tries each transcript source in turn.
 // Primary: Supadata API.
 // Fallback 1: Go transcript API library.
 // Fallback 2: timedtext XML scraping.
-func (s *YouTubeService) GetTranscript(ctx context.Context, videoID string) (string, error) {
+// A non-empty language restricts every source to tracks in that language.
+func (s *YouTubeService) fetchTranscript(ctx context.Context, videoID, language string) (string, error) {
 	var supadataErr error
 	if s.supadataAPIKey != "" {
-		transcript, err := getTranscriptViaSupadata(ctx, videoID, s.supadataAPIKey)
+		transcript, err := getTranscriptViaSupadata(ctx, videoID, language, s.supadataAPIKey)
 		if err == nil && transcript != "" {
 			log.Printf("Transcript fetched via Supadata for %s", videoID)
 			return transcript, nil
@@ -61,10 +134,16 @@ func (s *YouTubeService) GetTranscript(ctx context.Context, videoID string) (str
 		log.Printf("WARNING: Supadata transcript fetch failed for %s: %v — trying Go fallback", videoID, err)
 	}
 
-	transcript, goErr := s.getTranscriptViaGoAPIWithTimeout(ctx, videoID, []string{"en", "en-US", "en-GB"}, 30*time.Second)
-	if goErr != nil {
-		log.Printf("WARNING: Go API transcript fetch (English) failed for %s: %v — trying any language", videoID, goErr)
-		transcript, goErr = s.getTranscriptViaGoAPIWithTimeout(ctx, videoID, nil, 30*time.Second)
+	var transcript *ytapi.Transcript
+	var goErr error
+	if language != "" {
+		transcript, goErr = s.getTranscriptViaGoAPIWithTimeout(ctx, videoID, captionLanguageCandidates(language), 30*time.Second)
+	} else {
+		transcript, goErr = s.getTranscriptViaGoAPIWithTimeout(ctx, videoID, []string{"en", "en-US", "en-GB"}, 30*time.Second)
+		if goErr != nil {
+			log.Printf("WARNING: Go API transcript fetch (English) failed for %s: %v — trying any language", videoID, goErr)
+			transcript, goErr = s.getTranscriptViaGoAPIWithTimeout(ctx, videoID, nil, 30*time.Second)
+		}
 	}
 	if goErr == nil {
 		cleaned, err := normalizeTranscriptEntries(transcript)
@@ -76,21 +155,30 @@ func (s *YouTubeService) GetTranscript(ctx context.Context, videoID string) (str
 	}
 	log.Printf("WARNING: Go API transcript fetch failed for %s: %v — trying timedtext", videoID, goErr)
 
-	legacyTranscript, legacyErr := s.getTranscriptViaTimedText(ctx, videoID)
+	legacyTranscript, legacyErr := s.getTranscriptViaTimedText(ctx, videoID, language)
 	if legacyErr == nil && legacyTranscript != "" {
 		log.Printf("Transcript fetched via TimedText for %s", videoID)
 		return legacyTranscript, nil
 	}
 
+	if errors.Is(supadataErr, ErrCaptionLanguageUnavailable) || errors.Is(legacyErr, ErrCaptionLanguageUnavailable) {
+		return "", fmt.Errorf("%w (%s) for video %s", ErrCaptionLanguageUnavailable, language, videoID)
+	}
+	if errors.Is(legacyErr, ErrNoCaptions) {
+		return "", fmt.Errorf("%w %s (supadata: %v; go-api: %v)", ErrNoCaptions, videoID, supadataErr, goErr)
+	}
 	return "", fmt.Errorf("all transcript methods failed for video %s: supadata: %v; go-api: %v; timedtext: %v", videoID, supadataErr, goErr, legacyErr)
 }
 
-func getTranscriptViaSupadata(ctx context.Context, videoID string, apiKey string) (string, error) {
+func getTranscriptViaSupadata(ctx context.Context, videoID, language, apiKey string) (string, error) {
 	if apiKey == "" {
 		return "", fmt.Errorf("supadata API key not configured")
 	}
 
 	reqURL := "https://api.supadata.ai/v1/youtube/transcript?videoId=" + videoID + "&text=true"
+	if language != "" {
+		reqURL += "&lang=" + url.QueryEscape(language)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
@@ -122,6 +210,10 @@ func getTranscriptViaSupadata(ctx context.Context, videoID string, apiKey string
 	if strings.TrimSpace(result.Content) == "" {
 		return "", fmt.Errorf("supadata returned empty transcript")
 	}
+	// Supadata falls back to another track when the language is missing.
+	if language != "" && result.Lang != "" && !captionLanguageMatches(language, result.Lang) {
+		return "", fmt.Errorf("%w: supadata returned %q", ErrCaptionLanguageUnavailable, result.Lang)
+	}
 
 	return strings.TrimSpace(result.Content), nil
 }
@@ -176,7 +268,7 @@ func normalizeTranscriptEntries(transcript *ytapi.Transcript) (string, error) {
 	return cleaned, nil
 }
 
-func (s *YouTubeService) getTranscriptViaTimedText(ctx context.Context, videoID string) (string, error) {
+func (s *YouTubeService) getTranscriptViaTimedText(ctx context.Context, videoID, language string) (string, error) {
 	// 30-second timeout for the entire timedtext flow
 	timedTextCtx, timedTextCancel := context.WithTimeout(ctx, 30*time.Second)
 	defer timedTextCancel()
@@ -206,7 +298,7 @@ func (s *YouTubeService) getTranscriptViaTimedText(ctx context.Context, videoID
 	pageHTML := string(body)
 	log.Printf("TimedText fallback: fetched YouTube page for %s (%d bytes)", videoID, len(pageHTML))
 
-	captionURL, err := extractCaptionURL(pageHTML)
+	captionURL, err := extractCaptionURL(pageHTML, language)
 	if err != nil {
 		return "", err
 	}
@@ -238,25 +330,48 @@ func (s *YouTubeService) getTranscriptViaTimedText(ctx context.Context, videoID
 	return transcript, nil
 }
 
-func extractCaptionURL(pageHTML string) (string, error) {
-	re := regexp.MustCompile(`"captionTracks"\s*:\s*\[(.*?)\],\s*"`)
-	matches := re.FindStringSubmatch(pageHTML)
+// extractCaptionURL returns the first caption track's URL, or the first track
+// in language when one is given. A playable page with no caption tracks is
+// reported as ErrNoCaptions; any other page (consent or bot checks) is not.
+func extractCaptionURL(pageHTML, language string) (string, error) {
+	matches := captionTracksPattern.FindStringSubmatch(pageHTML)
 	if len(matches) < 2 {
-		re2 := regexp.MustCompile(`"playerCaptionsTracklistRenderer"\s*:\s*\{(?:.*?,)?\s*"captionTracks"\s*:\s*\[(.*?)\],\s*"`)
-		matches = re2.FindStringSubmatch(pageHTML)
+		matches = captionTracklistPattern.FindStringSubmatch(pageHTML)
 		if len(matches) < 2 {
-			return "", fmt.Errorf("no captions available for this video")
+			if playableStatusPattern.MatchString(pageHTML) {
+				return "", ErrNoCaptions
+			}
+			return "", fmt.Errorf("no caption tracks found on the video page")
 		}
 	}
 
 	tracksJSON := matches[1]
 	reURL := regexp.MustCompile(`"baseUrl"\s*:\s*"(.*?)"`)
-	urlMatches := reURL.FindStringSubmatch(tracksJSON)
-	if len(urlMatches) < 2 {
+	urlMatches := reURL.FindAllStringSubmatchIndex(tracksJSON, -1)
+	if len(urlMatches) == 0 {
 		return "", fmt.Errorf("caption track found but baseUrl missing")
 	}
 
-	u := urlMatches[1]
+	u := tracksJSON[urlMatches[0][2]:urlMatches[0][3]]
+	if language != "" {
+		reLang := regexp.MustCompile(`"languageCode"\s*:\s*"(.*?)"`)
+		u = ""
+		for i, m := range urlMatches {
+			// A track's fields run until the next track's baseUrl.
+			end := len(tracksJSON)
+			if i+1 < len(urlMatches) {
+				end = urlMatches[i+1][0]
+			}
+			code := reLang.FindStringSubmatch(tracksJSON[m[1]:end])
+			if len(code) >= 2 && captionLanguageMatches(language, code[1]) {
+				u = tracksJSON[m[2]:m[3]]
+				break
+			}
+		}
+		if u == "" {
+			return "", fmt.Errorf("%w: no %s caption track", ErrCaptionLanguageUnavailable, language)
+		}
+	}
 	u = strings.ReplaceAll(u, `\u0026`, "&")
 	u = strings.ReplaceAll(u, `\/`, "/")
 
@@ -285,12 +400,16 @@ func parseCaptionsXML(data []byte) (string, error) {
 	return strings.Join(parts, " "), nil
 }
 
-// DownloadAudio downloads the best available audio-only stream for a YouTube URL.
-func (s *YouTubeService) DownloadAudio(videoURL string) ([]byte, string, error) {
+// maxAudioBytes caps a single audio download; it is enforced while streaming.
+const maxAudioBytes = 100 * 1024 * 1024
+
+// DownloadAudio streams the best available audio-only stream for a YouTube URL
+// into a temp file and returns its path. The caller must remove the file.
+func (s *YouTubeService) DownloadAudio(videoURL string) (string, string, error) {
 	type result struct {
-		audioBytes []byte
-		mimeType   string
-		err        error
+		path     string
+		mimeType string
+		err      error
 	}
 
 	ch := make(chan result, 1)
@@ -322,15 +441,9 @@ func (s *YouTubeService) DownloadAudio(videoURL string) ([]byte, string, error)
 		}
 		defer stream.Close()
 
-		const maxAudioBytes = 100 * 1024 * 1024 // 100MB safety cap
-		limited := io.LimitReader(stream, maxAudioBytes+1)
-		audioBytes, err := io.ReadAll(limited)
+		path, err := streamToTempFile(stream, "lectura-audio-*", maxAudioBytes)
 		if err != nil {
-			ch <- result{err: fmt.Errorf("failed to read audio stream: %w", err)}
-			return
-		}
-		if len(audioBytes) > maxAudioBytes {
-			ch <- result{err: fmt.Errorf("audio stream exceeds %d MB limit", maxAudioBytes/(1024*1024))}
+			ch <- result{err: err}
 			return
 		}
 
@@ -339,17 +452,47 @@ func (s *YouTubeService) DownloadAudio(videoURL string) ([]byte, string, error)
 			mimeType = "audio/mp4"
 		}
 
-		ch <- result{audioBytes: audioBytes, mimeType: mimeType, err: nil}
+		ch <- result{path: path, mimeType: mimeType, err: nil}
 	}()
 
 	select {
 	case res := <-ch:
-		return res.audioBytes, res.mimeType, res.err
+		return res.path, res.mimeType, res.err
 	case <-time.After(90 * time.Second): // 90-second max for downloading audio
-		return nil, "", fmt.Errorf("audio download timed out after 90s")
+		// The download goroutine may still finish; clean up whatever it writes.
+		go func() {
+			if res := <-ch; res.path != "" {
+				os.Remove(res.path)
+			}
+		}()
+		return "", "", fmt.Errorf("audio download timed out after 90s")
 	}
 }
 
+// streamToTempFile copies r into a new temp file, failing (and removing the
+// file) once more than maxBytes have been read.
+func streamToTempFile(r io.Reader, pattern string, maxBytes int64) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	n, copyErr := io.Copy(f, io.LimitReader(r, maxBytes+1))
+	closeErr := f.Close()
+	switch {
+	case copyErr != nil:
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to read audio stream: %w", copyErr)
+	case n > maxBytes:
+		os.Remove(f.Name())
+		return "", fmt.Errorf("audio stream exceeds %d MB limit", maxBytes/(1024*1024))
+	case closeErr != nil:
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write audio temp file: %w", closeErr)
+	}
+	return f.Name(), nil
+}
+
 // DownloadVideo downloads a relatively small MP4 video stream for a YouTube URL.
 // This is used for extracting a single frame for on-screen text OCR.
 func (s *YouTubeService) DownloadVideo(videoURL string) ([]byte, string, error) {
@@ -452,26 +595,63 @@ func (s *YouTubeService) DownloadVideo(videoURL string) ([]byte, string, error)
 
 // EstimateDuration parses duration from YouTube page HTML
 func (s *YouTubeService) GetVideoMetadata(videoID string) (title, channel, thumbnail, description string, durationSec int, err error) {
+	if cached, ok := s.cache.cachedMetadata(context.Background(), videoID); ok {
+		return cached.Title, cached.Channel, cached.Thumbnail, cached.Description, cached.DurationSec, nil
+	}
+
+	return s.fetchVideoMetadata(videoID)
+}
+
+// RefreshVideoMetadata re-scrapes the watch page, bypassing (and then updating) the metadata cache.
+func (s *YouTubeService) RefreshVideoMetadata(videoID string) (title, channel, thumbnail, description string, durationSec int, err error) {
+	return s.fetchVideoMetadata(videoID)
+}
+
+// VideoMetadata returns a video's metadata with its chapters, from the cache
+// unless refresh is set.
+func (s *YouTubeService) VideoMetadata(ctx context.Context, videoID string, refresh bool) (*models.YouTubeMetadata, error) {
+	meta, ok := s.cache.cachedMetadata(ctx, videoID)
+	if refresh || !ok {
+		scraped, err := s.scrapeVideoMetadata(videoID)
+		if err != nil {
+			return nil, err
+		}
+		meta = &scraped
+	}
+	return meta.youtubeMetadata(videoID), nil
+}
+
+func (s *YouTubeService) fetchVideoMetadata(videoID string) (title, channel, thumbnail, description string, durationSec int, err error) {
+	meta, err := s.scrapeVideoMetadata(videoID)
+	if err != nil {
+		return "", "", "", "", 0, err
+	}
+	return meta.Title, meta.Channel, meta.Thumbnail, meta.Description, meta.DurationSec, nil
+}
+
+func (s *YouTubeService) scrapeVideoMetadata(videoID string) (meta cachedVideoMetadata, err error) {
+	var title, channel, thumbnail, description string
+	var durationSec int
 	pageURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID)
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, pageURL, nil)
 	if err != nil {
-		return "", "", "", "", 0, fmt.Errorf("GetVideoMetadata: build request: %w", err)
+		return meta, fmt.Errorf("GetVideoMetadata: build request: %w", err)
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return "", "", "", "", 0, fmt.Errorf("GetVideoMetadata: request failed: %w", err)
+		return meta, fmt.Errorf("GetVideoMetadata: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return "", "", "", "", 0, fmt.Errorf("GetVideoMetadata: unexpected status %d", resp.StatusCode)
+		return meta, fmt.Errorf("GetVideoMetadata: unexpected status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
 	if err != nil {
-		return "", "", "", "", 0, fmt.Errorf("GetVideoMetadata: read body: %w", err)
+		return meta, fmt.Errorf("GetVideoMetadata: read body: %w", err)
 	}
 	html := string(body)
 
@@ -508,5 +688,15 @@ func (s *YouTubeService) GetVideoMetadata(videoID string) (title, channel, thumb
 		fmt.Sscanf(m[1], "%d", &durationSec)
 	}
 
-	return title, channel, thumbnail, description, durationSec, nil
+	meta = cachedVideoMetadata{
+		Title:       title,
+		Channel:     channel,
+		Thumbnail:   thumbnail,
+		Description: description,
+		DurationSec: durationSec,
+		Chapters:    parseVideoChapters(html, durationSec),
+	}
+	s.cache.storeMetadata(context.Background(), videoID, meta)
+
+	return meta, nil
 }
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStreamToTempFile_WritesWithinLimit(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	payload := bytes.Repeat([]byte("a"), 1024)
+
+	path, err := streamToTempFile(bytes.NewReader(payload), "audio-*", 1024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(path)
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read temp file: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("expected %d bytes on disk, got %d", len(payload), len(got))
+	}
+}
+
+func TestStreamToTempFile_OverLimitRemovesFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+
+	_, err := streamToTempFile(bytes.NewReader(bytes.Repeat([]byte("a"), 2048)), "audio-*", 1024)
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "audio-*"))
+	if len(leftovers) != 0 {
+		t.Fatalf("expected temp file to be removed, found %v", leftovers)
+	}
+}
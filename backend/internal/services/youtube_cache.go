@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"lectura-backend/internal/models"
+)
+
+// maxCachedTranscriptBytes keeps very long transcripts (multi-hour streams) out of Redis.
+const maxCachedTranscriptBytes = 2 << 20
+
+const noCaptionsMarker = "__no_captions__"
+
+// ErrNoCaptions is returned by GetTranscript when YouTube served the video's
+// player page and it lists no caption tracks. Only this result is cached;
+// timeouts and other fetch failures are retried on the next lookup.
+var ErrNoCaptions = errors.New("no captions available for video")
+
+// ErrNoCaptionsCached is returned by GetTranscript when a recent lookup for the
+// same video found no captions. It wraps ErrNoCaptions.
+var ErrNoCaptionsCached = fmt.Errorf("%w (cached)", ErrNoCaptions)
+
+type youtubeCache struct {
+	client        *redis.Client
+	metadataTTL   time.Duration
+	transcriptTTL time.Duration
+	negativeTTL   time.Duration
+}
+
+type cachedVideoMetadata struct {
+	Title       string `json:"title"`
+	Channel     string `json:"channel"`
+	Thumbnail   string `json:"thumbnail"`
+	Description string `json:"description"`
+	DurationSec int    `json:"duration_seconds"`
+	// Chapters is nil for entries cached before chapters were scraped.
+	Chapters []models.VideoChapter `json:"chapters,omitempty"`
+}
+
+func (m *cachedVideoMetadata) youtubeMetadata(videoID string) *models.YouTubeMetadata {
+	return &models.YouTubeMetadata{
+		VideoID:      videoID,
+		Title:        m.Title,
+		ChannelName:  m.Channel,
+		ThumbnailURL: m.Thumbnail,
+		Duration:     m.DurationSec,
+		Chapters:     m.Chapters,
+	}
+}
+
+// WithCache enables Redis caching of video metadata and transcripts. A zero TTL
+// disables caching for that kind of entry.
+func (s *YouTubeService) WithCache(client *redis.Client, metadataTTL, transcriptTTL, negativeTTL time.Duration) *YouTubeService {
+	if client == nil {
+		return s
+	}
+	s.cache = &youtubeCache{
+		client:        client,
+		metadataTTL:   metadataTTL,
+		transcriptTTL: transcriptTTL,
+		negativeTTL:   negativeTTL,
+	}
+	return s
+}
+
+func transcriptCacheKey(videoID string) string {
+	return fmt.Sprintf("yt:transcript:%s", videoID)
+}
+
+func metadataCacheKey(videoID string) string {
+	return fmt.Sprintf("yt:metadata:%s", videoID)
+}
+
+// cachedTranscript returns (transcript, hit, err). err is ErrNoCaptionsCached on a negative hit.
+// Redis errors are treated as a miss so the network path still runs.
+func (c *youtubeCache) cachedTranscript(ctx context.Context, videoID string) (string, bool, error) {
+	if c == nil || c.transcriptTTL <= 0 {
+		return "", false, nil
+	}
+
+	val, err := c.client.Get(ctx, transcriptCacheKey(videoID)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("WARNING: youtube transcript cache read failed for %s: %v", videoID, err)
+		}
+		return "", false, nil
+	}
+
+	if val == noCaptionsMarker {
+		return "", true, ErrNoCaptionsCached
+	}
+
+	return val, val != "", nil
+}
+
+func (c *youtubeCache) storeTranscript(ctx context.Context, videoID, transcript string) {
+	if c == nil || c.transcriptTTL <= 0 || transcript == "" || len(transcript) > maxCachedTranscriptBytes {
+		return
+	}
+
+	if err := c.client.Set(ctx, transcriptCacheKey(videoID), transcript, c.transcriptTTL).Err(); err != nil {
+		log.Printf("WARNING: youtube transcript cache write failed for %s: %v", videoID, err)
+	}
+}
+
+func (c *youtubeCache) storeNoCaptions(ctx context.Context, videoID string) {
+	if c == nil || c.negativeTTL <= 0 {
+		return
+	}
+
+	// SetNX so a concurrent successful fetch is never overwritten by a failure marker.
+	if err := c.client.SetNX(ctx, transcriptCacheKey(videoID), noCaptionsMarker, c.negativeTTL).Err(); err != nil {
+		log.Printf("WARNING: youtube no-captions cache write failed for %s: %v", videoID, err)
+	}
+}
+
+func (c *youtubeCache) cachedMetadata(ctx context.Context, videoID string) (*cachedVideoMetadata, bool) {
+	if c == nil || c.metadataTTL <= 0 {
+		return nil, false
+	}
+
+	val, err := c.client.Get(ctx, metadataCacheKey(videoID)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("WARNING: youtube metadata cache read failed for %s: %v", videoID, err)
+		}
+		return nil, false
+	}
+
+	var meta cachedVideoMetadata
+	if err := json.Unmarshal(val, &meta); err != nil {
+		return nil, false
+	}
+
+	return &meta, true
+}
+
+func (c *youtubeCache) storeMetadata(ctx context.Context, videoID string, meta cachedVideoMetadata) {
+	if c == nil || c.metadataTTL <= 0 || meta.Title == "" {
+		return
+	}
+
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return
+	}
+
+	if err := c.client.Set(ctx, metadataCacheKey(videoID), b, c.metadataTTL).Err(); err != nil {
+		log.Printf("WARNING: youtube metadata cache write failed for %s: %v", videoID, err)
+	}
+}
+
+// CachedVideoMetadata returns previously fetched metadata for a video without
+// touching the network. It reports false on a miss or when caching is disabled.
+func (s *YouTubeService) CachedVideoMetadata(ctx context.Context, videoID string) (*models.YouTubeMetadata, bool) {
+	meta, ok := s.cache.cachedMetadata(ctx, videoID)
+	if !ok {
+		return nil, false
+	}
+
+	return meta.youtubeMetadata(videoID), true
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestYouTubeCache_UnreachableRedis_FallsThroughAsMiss(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         "127.0.0.1:0",
+		DialTimeout:  10 * time.Millisecond,
+		ReadTimeout:  10 * time.Millisecond,
+		WriteTimeout: 10 * time.Millisecond,
+	})
+	defer client.Close()
+
+	svc := NewYouTubeService("").WithCache(client, time.Hour, time.Hour, time.Minute)
+
+	transcript, hit, err := svc.cache.cachedTranscript(context.Background(), "dQw4w9WgXcQ")
+	if hit || err != nil || transcript != "" {
+		t.Fatalf("expected cache miss without error, got hit=%v err=%v transcript=%q", hit, err, transcript)
+	}
+
+	if _, ok := svc.CachedVideoMetadata(context.Background(), "dQw4w9WgXcQ"); ok {
+		t.Fatalf("expected metadata cache miss")
+	}
+}
+
+func TestYouTubeCache_DisabledCache_IsNilSafe(t *testing.T) {
+	svc := NewYouTubeService("")
+
+	if _, hit, err := svc.cache.cachedTranscript(context.Background(), "dQw4w9WgXcQ"); hit || err != nil {
+		t.Fatalf("expected disabled cache to report a miss, got hit=%v err=%v", hit, err)
+	}
+	svc.cache.storeTranscript(context.Background(), "dQw4w9WgXcQ", "hello")
+	svc.cache.storeNoCaptions(context.Background(), "dQw4w9WgXcQ")
+
+	if _, ok := svc.CachedVideoMetadata(context.Background(), "dQw4w9WgXcQ"); ok {
+		t.Fatalf("expected disabled cache to report a miss")
+	}
+}
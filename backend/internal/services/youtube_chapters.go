@@ -0,0 +1,143 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"lectura-backend/internal/models"
+)
+
+// maxVideoChapters bounds how many chapters are kept for one video.
+const maxVideoChapters = 100
+
+// minDescriptionChapters is the fewest timestamps YouTube itself accepts as
+// chapters in a description.
+const minDescriptionChapters = 3
+
+var (
+	chapterRendererPattern  = regexp.MustCompile(`"chapterRenderer":\{"title":\{"simpleText":"((?:[^"\\]|\\.)*)"\},"timeRangeStartMillis":(\d+)`)
+	shortDescriptionPattern = regexp.MustCompile(`"shortDescription":"((?:[^"\\]|\\.)*)"`)
+	descriptionChapterLine  = regexp.MustCompile(`^[(\[]?((?:\d{1,2}:)?\d{1,2}:\d{2})[)\]]?\s*(?:[-–—:|]\s*)?(.+)$`)
+)
+
+// parseVideoChapters reads the chapter markers of a watch page. Chapters the
+// player shows come first; without them, timestamp lines in the full
+// description are used when they form valid chapters: starting at 0:00, at
+// least three of them, in ascending order. durationSec, when known, drops
+// timestamps past the end of the video.
+func parseVideoChapters(pageHTML string, durationSec int) []models.VideoChapter {
+	if chapters := playerChapters(pageHTML); len(chapters) > 0 {
+		return chapters
+	}
+	m := shortDescriptionPattern.FindStringSubmatch(pageHTML)
+	if len(m) < 2 {
+		return nil
+	}
+	return descriptionChapters(unquoteJSONString(m[1]), durationSec)
+}
+
+func playerChapters(pageHTML string) []models.VideoChapter {
+	seen := map[int]bool{}
+	var chapters []models.VideoChapter
+	for _, m := range chapterRendererPattern.FindAllStringSubmatch(pageHTML, -1) {
+		millis, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		start := millis / 1000
+		title := strings.TrimSpace(unquoteJSONString(m[1]))
+		// The page repeats the chapter list for the player and the panel.
+		if title == "" || seen[start] {
+			continue
+		}
+		seen[start] = true
+		chapters = append(chapters, models.VideoChapter{Title: title, StartSeconds: start})
+	}
+	sort.Slice(chapters, func(i, j int) bool { return chapters[i].StartSeconds < chapters[j].StartSeconds })
+	if len(chapters) > maxVideoChapters {
+		chapters = chapters[:maxVideoChapters]
+	}
+	return chapters
+}
+
+func descriptionChapters(description string, durationSec int) []models.VideoChapter {
+	var chapters []models.VideoChapter
+	for _, line := range strings.Split(description, "\n") {
+		m := descriptionChapterLine.FindStringSubmatch(strings.TrimSpace(line))
+		if len(m) < 3 {
+			continue
+		}
+		start, ok := parseChapterTimestamp(m[1])
+		title := strings.TrimSpace(m[2])
+		if !ok || title == "" {
+			continue
+		}
+		if durationSec > 0 && start >= durationSec {
+			continue
+		}
+		if n := len(chapters); n > 0 && start <= chapters[n-1].StartSeconds {
+			return nil
+		}
+		chapters = append(chapters, models.VideoChapter{Title: title, StartSeconds: start})
+	}
+	if len(chapters) < minDescriptionChapters || chapters[0].StartSeconds != 0 {
+		return nil
+	}
+	if len(chapters) > maxVideoChapters {
+		chapters = chapters[:maxVideoChapters]
+	}
+	return chapters
+}
+
+// parseChapterTimestamp reads "m:ss" or "h:mm:ss" as seconds.
+func parseChapterTimestamp(ts string) (int, bool) {
+	seconds := 0
+	parts := strings.Split(ts, ":")
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || (i > 0 && n >= 60) {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, true
+}
+
+// chapterTimestamp writes seconds the way YouTube labels chapters.
+func chapterTimestamp(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func unquoteJSONString(s string) string {
+	var unquoted string
+	if err := json.Unmarshal([]byte(`"`+s+`"`), &unquoted); err != nil {
+		return s
+	}
+	return unquoted
+}
+
+// writeChapterOutline asks smart and bullets summaries to follow the chapters
+// the lecturer gave the video. Other formats have layouts of their own and
+// are left alone.
+func writeChapterOutline(b *strings.Builder, format string, chapters []models.VideoChapter) {
+	if len(chapters) == 0 || (format != "smart" && format != "bullets") {
+		return
+	}
+	b.WriteString("Video chapters: The lecturer divided the video into these chapters:\n")
+	for _, c := range chapters {
+		fmt.Fprintf(b, "- %s %s\n", chapterTimestamp(c.StartSeconds), c.Title)
+	}
+	switch format {
+	case "bullets":
+		b.WriteString("Organize Core Structures by chapter: make each chapter a top-level bullet written as '**<timestamp> <chapter title>**', in chapter order, with that chapter's points as sub-bullets. Skip chapters with no substantive content (such as intros or sponsor segments).\n\n")
+	case "smart":
+		b.WriteString("In '## Summary of Video Content', walk through the chapters in order and name each chapter with its timestamp. When a key insight comes from one chapter, mention that chapter's title.\n\n")
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lectura-backend/internal/models"
+)
+
+func TestParseVideoChapters_PlayerChapters(t *testing.T) {
+	renderer := `"chapterRenderer":{"title":{"simpleText":"%s"},"timeRangeStartMillis":%s,"onTap":{}}`
+	page := strings.Join([]string{
+		fmt.Sprintf(renderer, "Intro", "0"),
+		fmt.Sprintf(renderer, `Acids \u0026 bases`, "312500"),
+		fmt.Sprintf(renderer, "Intro", "0"),
+		fmt.Sprintf(renderer, "Buffers", "900000"),
+	}, ",")
+
+	got := parseVideoChapters(page, 0)
+	want := []models.VideoChapter{{Title: "Intro", StartSeconds: 0}, {Title: "Acids & bases", StartSeconds: 312}, {Title: "Buffers", StartSeconds: 900}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chapters = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseVideoChapters_DescriptionFallback(t *testing.T) {
+	page := `"shortDescription":"Lecture 4 of the course.\n\n0:00 Introduction\n(4:05) - Newton's laws\n1:02:30 Q&A\n\nSlides: https://example.com"`
+
+	got := parseVideoChapters(page, 4000)
+	want := []models.VideoChapter{{Title: "Introduction", StartSeconds: 0}, {Title: "Newton's laws", StartSeconds: 245}, {Title: "Q&A", StartSeconds: 3750}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chapters = %+v, want %+v", got, want)
+	}
+}
+
+func TestDescriptionChapters_RejectsInvalidLists(t *testing.T) {
+	tests := map[string]string{
+		"does not start at zero": "0:30 Intro\n2:00 Part one\n5:00 Part two",
+		"too few":                "0:00 Intro\n2:00 Part one",
+		"out of order":           "0:00 Intro\n5:00 Part two\n2:00 Part one",
+	}
+	for name, description := range tests {
+		if got := descriptionChapters(description, 0); got != nil {
+			t.Fatalf("%s: chapters = %+v, want none", name, got)
+		}
+	}
+}
+
+func TestBuildSummaryPrompt_Chapters(t *testing.T) {
+	chapters := []models.VideoChapter{{Title: "Intro", StartSeconds: 0}, {Title: "Enzymes", StartSeconds: 3725}}
+
+	prompt := buildSummaryPrompt("bullets", "standard", nil, chapters, "", "en", "", "some transcript text", false, false)
+	if !strings.Contains(prompt, "- 0:00 Intro\n- 1:02:05 Enzymes\n") || !strings.Contains(prompt, "Organize Core Structures by chapter") {
+		t.Fatalf("bullets prompt is missing the chapters:\n%s", prompt)
+	}
+
+	prompt = buildSummaryPrompt("cornell", "standard", nil, chapters, "", "en", "", "some transcript text", false, false)
+	if strings.Contains(prompt, "Video chapters") {
+		t.Fatalf("cornell prompt should not list chapters")
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// YouTubePlaylist is a playlist's title and its videos in playlist order.
+type YouTubePlaylist struct {
+	ID     string
+	Title  string
+	Videos []YouTubePlaylistVideo
+}
+
+// YouTubePlaylistVideo is one entry of a playlist. Unavailable entries are
+// private or deleted videos YouTube still lists but will not play.
+type YouTubePlaylistVideo struct {
+	VideoID     string
+	Title       string
+	DurationSec int
+	Unavailable bool
+}
+
+// unavailablePlaylistTitles are the placeholder titles YouTube gives playlist
+// entries whose video can no longer be watched.
+var unavailablePlaylistTitles = []string{"[private video]", "[deleted video]", "[unavailable video]"}
+
+// GetPlaylist lists the videos of a public or unlisted playlist.
+func (s *YouTubeService) GetPlaylist(ctx context.Context, playlistID string) (*YouTubePlaylist, error) {
+	playlist, err := s.ytClient.GetPlaylistContext(ctx, "https://www.youtube.com/playlist?list="+playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("GetPlaylist: %w", err)
+	}
+
+	result := &YouTubePlaylist{ID: playlistID, Title: playlist.Title}
+	for _, entry := range playlist.Videos {
+		if entry == nil {
+			continue
+		}
+		result.Videos = append(result.Videos, YouTubePlaylistVideo{
+			VideoID:     entry.ID,
+			Title:       entry.Title,
+			DurationSec: int(entry.Duration.Seconds()),
+			Unavailable: playlistEntryUnavailable(entry.ID, entry.Title),
+		})
+	}
+	return result, nil
+}
+
+func playlistEntryUnavailable(videoID, title string) bool {
+	if len(videoID) != 11 {
+		return true
+	}
+	title = strings.ToLower(strings.TrimSpace(title))
+	for _, placeholder := range unavailablePlaylistTitles {
+		if title == placeholder {
+			return true
+		}
+	}
+	return false
+}
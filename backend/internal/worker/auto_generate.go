@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+// WithQuota makes the pool check plan quotas before queuing jobs on its own,
+// such as the quiz and flashcards a summary asked to auto-generate.
+func (p *Pool) WithQuota(quotaService *services.QuotaService) *Pool {
+	p.quotaService = quotaService
+	return p
+}
+
+// Counts used when a chained config somehow arrives without one; the summary
+// handler normally fills them from the user's settings.
+const (
+	defaultChainedQuizQuestions = 10
+	defaultChainedFlashcards    = 20
+)
+
+// chainedJob is a follow-up job a completed summary asked for.
+type chainedJob struct {
+	jobType   string // "quiz-generation" | "flashcard-generation"
+	quotaType string // key into services.JobCreditCost
+	create    func(ctx context.Context, summary *models.Summary) (uuid.UUID, json.RawMessage, error)
+}
+
+// enqueueAutoGenerate queues the quiz and flashcard jobs a completed summary
+// job asked for in its auto_generate option. Each chained job, or the reason
+// it was skipped, is announced over the WebSocket.
+func (p *Pool) enqueueAutoGenerate(ctx context.Context, job *models.Job) {
+	var config models.GenerateSummaryRequest
+	if err := json.Unmarshal(job.ConfigJSON, &config); err != nil || config.AutoGenerate == nil {
+		return
+	}
+	opts := config.AutoGenerate
+	if !opts.Quiz && !opts.Flashcards {
+		return
+	}
+	if p.summaryRepo == nil || p.userRepo == nil {
+		log.Printf("auto-generate for job %s skipped: repositories are not configured", job.ID)
+		return
+	}
+
+	summary, err := p.summaryRepo.GetByID(ctx, job.ReferenceID)
+	if err != nil {
+		log.Printf("auto-generate for job %s skipped: failed to load summary %s: %v", job.ID, job.ReferenceID, err)
+		return
+	}
+	user, err := p.userRepo.GetByID(ctx, job.UserID)
+	if err != nil {
+		log.Printf("auto-generate for job %s skipped: failed to load user %s: %v", job.ID, job.UserID, err)
+		return
+	}
+
+	var chained []chainedJob
+	if opts.Quiz {
+		chained = append(chained, chainedJob{
+			jobType:   "quiz-generation",
+			quotaType: "quiz",
+			create: func(ctx context.Context, summary *models.Summary) (uuid.UUID, json.RawMessage, error) {
+				return p.createChainedQuiz(ctx, summary, opts.QuizConfig)
+			},
+		})
+	}
+	if opts.Flashcards {
+		chained = append(chained, chainedJob{
+			jobType:   "flashcard-generation",
+			quotaType: "flashcard_deck",
+			create: func(ctx context.Context, summary *models.Summary) (uuid.UUID, json.RawMessage, error) {
+				return p.createChainedDeck(ctx, summary, opts.FlashcardConfig)
+			},
+		})
+	}
+
+	for _, c := range chained {
+		event := models.ChainedJobEvent{ParentJobID: job.ID, ResultType: getResultType(c.jobType)}
+		if reason := p.chainedQuotaBlock(ctx, user, c.quotaType); reason != "" {
+			log.Printf("auto-generate %s for summary %s skipped: %s", c.jobType, summary.ID, reason)
+			event.SkippedReason = reason
+		} else if jobID, resultID, err := p.queueChainedJob(ctx, job.UserID, summary, c); err != nil {
+			log.Printf("auto-generate %s for summary %s failed: %v", c.jobType, summary.ID, err)
+			event.SkippedReason = "QUEUE_ERROR"
+		} else {
+			event.JobID = &jobID
+			event.ResultID = &resultID
+		}
+		p.gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{Type: "job_chained", Payload: event})
+	}
+}
+
+// chainedQuotaBlock mirrors the generate handlers' quota check and returns the
+// error code that blocks the job, or "" when it may run.
+func (p *Pool) chainedQuotaBlock(ctx context.Context, user *models.User, quotaType string) string {
+	if user.HasGeminiKey || p.quotaService == nil {
+		return ""
+	}
+	allowed, err := p.quotaService.CheckQuota(ctx, user.ID, user.Plan, quotaType)
+	if err != nil {
+		if err.Error() == "API_KEY_REQUIRED" {
+			return "API_KEY_REQUIRED"
+		}
+		log.Printf("auto-generate quota check for user %s failed: %v", user.ID, err)
+		return "QUOTA_CHECK_FAILED"
+	}
+	if !allowed {
+		return "QUOTA_EXCEEDED"
+	}
+	return ""
+}
+
+func (p *Pool) queueChainedJob(ctx context.Context, userID uuid.UUID, summary *models.Summary, c chainedJob) (uuid.UUID, uuid.UUID, error) {
+	resultID, configBytes, err := c.create(ctx, summary)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	chained := &models.Job{
+		UserID:      userID,
+		Type:        c.jobType,
+		ReferenceID: resultID,
+		ConfigJSON:  configBytes,
+	}
+	if err := p.jobRepo.Create(ctx, chained); err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	jobBytes, _ := json.Marshal(chained)
+	if err := p.redis.LPush(ctx, JobQueueName(chained.Type), string(jobBytes)).Err(); err != nil {
+		p.jobRepo.UpdateStatus(ctx, chained.ID, "failed")
+		return uuid.Nil, uuid.Nil, fmt.Errorf("failed to queue job: %w", err)
+	}
+	return chained.ID, resultID, nil
+}
+
+func (p *Pool) createChainedQuiz(ctx context.Context, summary *models.Summary, config *models.GenerateQuizRequest) (uuid.UUID, json.RawMessage, error) {
+	if p.quizRepo == nil {
+		return uuid.Nil, nil, fmt.Errorf("quiz repository is not configured")
+	}
+	resolved := chainedQuizConfig(summary, config)
+	configBytes, _ := json.Marshal(resolved)
+
+	quiz := &models.Quiz{
+		UserID:        summary.UserID,
+		SummaryID:     &summary.ID,
+		Title:         resolved.Title,
+		QuestionCount: resolved.NumQuestions,
+		ConfigJSON:    configBytes,
+		QuestionsJSON: json.RawMessage("[]"),
+	}
+	if err := p.quizRepo.Create(ctx, quiz); err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to create quiz: %w", err)
+	}
+	return quiz.ID, configBytes, nil
+}
+
+func (p *Pool) createChainedDeck(ctx context.Context, summary *models.Summary, config *models.GenerateFlashcardsRequest) (uuid.UUID, json.RawMessage, error) {
+	if p.flashRepo == nil {
+		return uuid.Nil, nil, fmt.Errorf("flashcard repository is not configured")
+	}
+	resolved := chainedDeckConfig(summary, config)
+	configBytes, _ := json.Marshal(resolved)
+
+	deck := &models.FlashcardDeck{
+		UserID:     summary.UserID,
+		SummaryID:  &summary.ID,
+		Title:      resolved.Title,
+		CardCount:  resolved.NumCards,
+		ConfigJSON: configBytes,
+	}
+	if err := p.flashRepo.CreateDeck(ctx, deck); err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to create deck: %w", err)
+	}
+	return deck.ID, configBytes, nil
+}
+
+// chainedQuizConfig links a quiz config to the summary and names the quiz
+// after it when no title was given. The handler already applied defaults.
+func chainedQuizConfig(summary *models.Summary, config *models.GenerateQuizRequest) models.GenerateQuizRequest {
+	var resolved models.GenerateQuizRequest
+	if config != nil {
+		resolved = *config
+	}
+	resolved.SummaryID = summary.ID
+	if resolved.Title == "" {
+		resolved.Title = chainedTitle(summary.Title, "Quiz")
+	}
+	if resolved.NumQuestions == 0 {
+		resolved.NumQuestions = defaultChainedQuizQuestions
+	}
+	return resolved
+}
+
+// chainedDeckConfig links a flashcard config to the summary and names the
+// deck after it when no title was given.
+func chainedDeckConfig(summary *models.Summary, config *models.GenerateFlashcardsRequest) models.GenerateFlashcardsRequest {
+	var resolved models.GenerateFlashcardsRequest
+	if config != nil {
+		resolved = *config
+	}
+	resolved.SummaryID = summary.ID
+	resolved.ContentID = nil
+	if resolved.Title == "" {
+		resolved.Title = chainedTitle(summary.Title, "Flashcards")
+	}
+	if resolved.Strategy == "" {
+		resolved.Strategy = "term_definition"
+	}
+	if resolved.NumCards == 0 {
+		resolved.NumCards = defaultChainedFlashcards
+	}
+	return resolved
+}
+
+// chainedTitle is "<summary title> <kind>", kept within the title limit.
+func chainedTitle(summaryTitle, kind string) string {
+	suffix := " " + kind
+	title := []rune(summaryTitle)
+	if limit := services.MaxTitleLength - len([]rune(suffix)); len(title) > limit {
+		title = title[:limit]
+	}
+	if len(title) == 0 {
+		return kind
+	}
+	return string(title) + suffix
+}
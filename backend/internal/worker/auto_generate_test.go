@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/models"
+	"lectura-backend/internal/services"
+)
+
+func TestChainedQuizConfig_LinksSummary(t *testing.T) {
+	summary := &models.Summary{ID: uuid.New(), Title: "Cell Biology"}
+
+	config := chainedQuizConfig(summary, &models.GenerateQuizRequest{NumQuestions: 7, Difficulty: "hard"})
+	if config.SummaryID != summary.ID || config.Title != "Cell Biology Quiz" {
+		t.Fatalf("expected quiz linked to and named after the summary, got %#v", config)
+	}
+	if config.NumQuestions != 7 || config.Difficulty != "hard" {
+		t.Fatalf("expected requested settings to be kept, got %#v", config)
+	}
+
+	if fallback := chainedQuizConfig(summary, nil); fallback.NumQuestions != defaultChainedQuizQuestions {
+		t.Fatalf("expected default question count, got %d", fallback.NumQuestions)
+	}
+}
+
+func TestChainedDeckConfig_LinksSummary(t *testing.T) {
+	summary := &models.Summary{ID: uuid.New(), Title: "Cell Biology"}
+	contentID := uuid.New()
+
+	config := chainedDeckConfig(summary, &models.GenerateFlashcardsRequest{Title: "Cells", ContentID: &contentID})
+	if config.SummaryID != summary.ID || config.ContentID != nil {
+		t.Fatalf("expected deck built from the summary only, got %#v", config)
+	}
+	if config.Title != "Cells" || config.Strategy != "term_definition" || config.NumCards != defaultChainedFlashcards {
+		t.Fatalf("unexpected deck config %#v", config)
+	}
+}
+
+func TestChainedTitle_StaysWithinLimit(t *testing.T) {
+	title := chainedTitle(strings.Repeat("a", services.MaxTitleLength), "Flashcards")
+	if n := len([]rune(title)); n != services.MaxTitleLength {
+		t.Fatalf("expected title of %d runes, got %d", services.MaxTitleLength, n)
+	}
+	if !strings.HasSuffix(title, " Flashcards") {
+		t.Fatalf("expected suffix to be kept, got %q", title)
+	}
+	if got := chainedTitle("", "Quiz"); got != "Quiz" {
+		t.Fatalf("expected bare kind for untitled summary, got %q", got)
+	}
+}
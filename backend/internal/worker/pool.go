@@ -3,17 +3,23 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	urlpkg "net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 
+	"lectura-backend/internal/metrics"
 	"lectura-backend/internal/models"
 	"lectura-backend/internal/repository"
 	"lectura-backend/internal/services"
@@ -25,8 +31,31 @@ type workerJobRepo interface {
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
 	UpdateStatusIfNotTerminal(ctx context.Context, id uuid.UUID, status string) (bool, error)
 	UpdateError(ctx context.Context, id uuid.UUID, errMsg string, retryCount int) error
+	SetErrorCode(ctx context.Context, id uuid.UUID, code string) error
+	ListStuck(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Job, error)
+	ResetToPending(ctx context.Context, id uuid.UUID, fromStatuses ...string) (bool, error)
+	RequeueStuck(ctx context.Context, id uuid.UUID) (bool, error)
+	ListCompletedBefore(ctx context.Context, jobType string, olderThan time.Duration, limit int) ([]*models.Job, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// stuckJobSweepInterval is how often the pool looks for jobs left in
+// 'processing' by a worker that died mid-job.
+const stuckJobSweepInterval = 5 * time.Minute
+
+// dataExportSweepInterval is how often the pool deletes data export archives
+// older than services.DataExportRetention.
+const dataExportSweepInterval = time.Hour
+
+// Shutdown timing. Workers poll with a short BLPOP timeout rather than a
+// cancellable context, because cancelling a BLPOP in flight can drop a job
+// Redis has already popped. Stop waits this long for running jobs to finish;
+// anything still running is requeued later by the stuck-job sweep.
+const (
+	dequeueTimeout       = 5 * time.Second
+	shutdownDrainTimeout = 60 * time.Second
+)
+
 type Pool struct {
 	redis               *redis.Client
 	gemini              *services.GeminiService
@@ -40,10 +69,20 @@ type Pool struct {
 	presentationRepo    *repository.PresentationRepo
 	quizRepo            *repository.QuizRepo
 	flashRepo           *repository.FlashcardRepo
-	storagePath         string
+	exportRepo          *repository.ExportRepo
+	quotaService        *services.QuotaService
+	storage             services.Storage
+	uploads             services.UploadPolicy
 	workerCount         int
+	queueMinimums       QueueMinimums
 	contentReadyTimeout time.Duration
+	stuckJobThreshold   time.Duration
+	retryPolicies       RetryPolicies
+	jobTimeouts         JobTimeouts
+	timings             *services.JobTimings
 	stopChan            chan struct{}
+	stopOnce            sync.Once
+	workers             sync.WaitGroup
 }
 
 func NewPool(
@@ -59,10 +98,21 @@ func NewPool(
 	presentationRepo *repository.PresentationRepo,
 	quizRepo *repository.QuizRepo,
 	flashRepo *repository.FlashcardRepo,
-	storagePath string,
+	exportRepo *repository.ExportRepo,
+	storage services.Storage,
+	uploads services.UploadPolicy,
 	workerCount int,
+	queueMinimums QueueMinimums,
 	contentReadyTimeout time.Duration,
+	stuckJobThreshold time.Duration,
+	retryPolicies RetryPolicies,
 ) *Pool {
+	if len(uploads.Formats) == 0 {
+		uploads = services.DefaultUploadPolicy()
+	}
+	if retryPolicies.Default.MaxRetries <= 0 {
+		retryPolicies = NewRetryPolicies(RetryPolicy{}, nil)
+	}
 	return &Pool{
 		redis:               redisClient,
 		gemini:              gemini,
@@ -76,31 +126,60 @@ func NewPool(
 		presentationRepo:    presentationRepo,
 		quizRepo:            quizRepo,
 		flashRepo:           flashRepo,
-		storagePath:         storagePath,
+		exportRepo:          exportRepo,
+		storage:             storage,
+		uploads:             uploads,
 		workerCount:         workerCount,
+		queueMinimums:       queueMinimums,
 		contentReadyTimeout: contentReadyTimeout,
+		stuckJobThreshold:   stuckJobThreshold,
+		retryPolicies:       retryPolicies,
+		jobTimeouts:         NewJobTimeouts(0, nil, stuckJobThreshold),
+		timings:             services.NewJobTimings(redisClient),
 		stopChan:            make(chan struct{}),
 	}
 }
 
+// Start launches the workers: those reserved for a queue first, then shared
+// workers that poll every queue.
 func (p *Pool) Start() {
-	queues := []string{
-		"queue:content-processing",
-		"queue:summary-generation",
-		"queue:presentation",
-		"queue:quiz-generation",
-		"queue:flashcard-generation",
-	}
+	plan := planWorkers(p.workerCount, p.queueMinimums)
 
-	for i := 0; i < p.workerCount; i++ {
-		go p.worker(i, queues)
+	reserved := 0
+	for i, queues := range plan {
+		if len(queues) == 1 {
+			reserved++
+		}
+		p.workers.Add(1)
+		go func(id int, queues []string) {
+			defer p.workers.Done()
+			p.worker(id, queues)
+		}(i, queues)
 	}
+	go p.sweepStuckJobs()
+	go p.sweepExpiredExports()
 
-	log.Printf("Started %d worker goroutines", p.workerCount)
+	log.Printf("Started %d worker goroutines (%d reserved for specific queues, %d shared)", len(plan), reserved, len(plan)-reserved)
 }
 
+// Stop tells workers to stop taking jobs and waits for the jobs they are
+// running to finish, up to shutdownDrainTimeout. It is safe to call more
+// than once.
 func (p *Pool) Stop() {
-	close(p.stopChan)
+	p.stopOnce.Do(func() { close(p.stopChan) })
+
+	drained := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Println("Worker pool drained")
+	case <-time.After(shutdownDrainTimeout):
+		log.Printf("Worker pool: jobs still running after %s; they will be requeued as stuck", shutdownDrainTimeout)
+	}
 }
 
 // resolveGemini returns a GeminiService that uses the user's own API key if one
@@ -110,6 +189,22 @@ func (p *Pool) resolveGemini(ctx context.Context, userID uuid.UUID) (*services.G
 	return p.gemini.ResolveForUser(ctx, userID)
 }
 
+// transcriptionProgress forwards audio transcription progress to the job
+// owner, so long lectures do not look stuck.
+func transcriptionProgress(ctx context.Context, gemini *services.GeminiService, job *models.Job) services.TranscriptionProgress {
+	return func(stepName string, estimatedSecondsRemaining int) {
+		gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
+			Type: "status_update",
+			Payload: models.StatusUpdate{
+				JobID:                     job.ID,
+				Step:                      2,
+				StepName:                  stepName,
+				EstimatedSecondsRemaining: estimatedSecondsRemaining,
+			},
+		})
+	}
+}
+
 func (p *Pool) worker(id int, queues []string) {
 	for {
 		select {
@@ -121,8 +216,7 @@ func (p *Pool) worker(id int, queues []string) {
 
 		ctx := context.Background()
 
-		// BLPOP with 30s timeout
-		result, err := p.redis.BLPop(ctx, 30*time.Second, queues...).Result()
+		result, err := p.redis.BLPop(ctx, dequeueTimeout, queues...).Result()
 		if err != nil {
 			continue // Timeout or error, retry
 		}
@@ -139,14 +233,18 @@ func (p *Pool) worker(id int, queues []string) {
 		}
 
 		// Try to acquire lock
-		lockKey := fmt.Sprintf("job_lock:%s", job.ID.String())
-		locked, err := p.redis.SetNX(ctx, lockKey, "1", 10*time.Minute).Result()
+		lockKey := JobLockKey(job.ID)
+		locked, err := p.redis.SetNX(ctx, lockKey, "1", p.jobTimeouts.jobLockTTL(job.Type)).Result()
 		if err != nil || !locked {
 			continue // Another worker has this job
 		}
 
 		log.Printf("Worker %d: processing job %s (type: %s)", id, job.ID, job.Type)
 
+		// Status updates published during the job estimate the time left
+		// from how long recent jobs of this type took.
+		ctx = p.timings.Track(ctx, job.Type)
+
 		// Update status
 		p.jobRepo.UpdateStatus(ctx, job.ID, "processing")
 		if job.Type == "presentation" {
@@ -164,25 +262,31 @@ func (p *Pool) worker(id int, queues []string) {
 		})
 
 		// Execute handler
-		var processErr error
-		switch job.Type {
-		case "summary-generation":
-			processErr = p.processSummary(ctx, &job)
-		case "presentation":
-			processErr = p.processPresentation(ctx, &job)
-		case "quiz-generation":
-			processErr = p.processQuiz(ctx, &job)
-		case "flashcard-generation":
-			processErr = p.processFlashcard(ctx, &job)
-		case "content-processing":
-			processErr = p.processContent(ctx, &job)
-		default:
-			processErr = fmt.Errorf("unknown job type: %s", job.Type)
-		}
+		started := time.Now()
+		processErr := runWithTimeout(ctx, p.jobTimeouts.For(job.Type), func(ctx context.Context) error {
+			switch job.Type {
+			case "summary-generation":
+				return p.processSummary(ctx, &job)
+			case "presentation":
+				return p.processPresentation(ctx, &job)
+			case "quiz-generation":
+				return p.processQuiz(ctx, &job)
+			case "flashcard-generation":
+				return p.processFlashcard(ctx, &job)
+			case "content-processing":
+				return p.processContent(ctx, &job)
+			case "data-export":
+				return p.processDataExport(ctx, &job)
+			default:
+				return permanentf("unknown job type: %s", job.Type)
+			}
+		})
+		metrics.ObserveJob(job.Type, time.Since(started), processErr)
 
 		if processErr != nil {
 			p.handleFailure(ctx, &job, processErr)
 		} else {
+			p.timings.Finish(ctx)
 			p.handleSuccess(ctx, &job)
 		}
 
@@ -201,8 +305,16 @@ func (p *Pool) processSummary(ctx context.Context, job *models.Job) error {
 		return fmt.Errorf("failed to get summary: %w", err)
 	}
 
+	var config models.GenerateSummaryRequest
+	if len(job.ConfigJSON) > 0 {
+		_ = json.Unmarshal(job.ConfigJSON, &config)
+	}
+	if config.RewriteFromSummaryID != nil {
+		return p.processSummaryRewrite(ctx, gemini, job, *config.RewriteFromSummaryID)
+	}
+
 	if summary.ContentID == nil {
-		return fmt.Errorf("summary has no linked content")
+		return permanentf("summary has no linked content")
 	}
 
 	content, err := p.contentRepo.GetByID(ctx, *summary.ContentID)
@@ -224,13 +336,14 @@ func (p *Pool) processSummary(ctx context.Context, job *models.Job) error {
 	// If summary started before content-processing finished, fetch transcript directly here.
 	if content.Type == "youtube" && (content.Transcript == nil || *content.Transcript == "") {
 		if content.SourceURL == nil {
-			return fmt.Errorf("youtube content has no source URL")
+			return permanentf("youtube content has no source URL")
 		}
 
 		videoID, extractErr := extractVideoID(*content.SourceURL)
 		if extractErr != nil {
 			return extractErr
 		}
+		p.recordVideoChapters(ctx, content, videoID)
 
 		gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
 			Type: "status_update",
@@ -241,15 +354,19 @@ func (p *Pool) processSummary(ctx context.Context, job *models.Job) error {
 			},
 		})
 
-		transcript, transcriptErr := p.youtube.GetTranscript(ctx, videoID)
+		transcript, transcriptErr := p.youtube.GetTranscript(ctx, videoID, captionLanguage(content))
+		if errors.Is(transcriptErr, services.ErrCaptionLanguageUnavailable) {
+			return transcriptErr
+		}
 		if transcriptErr != nil {
 			// STT fallback for summary race path (when content-processing hasn't populated transcript)
-			audioBytes, mimeType, audioErr := p.youtube.DownloadAudio(*content.SourceURL)
+			audioPath, mimeType, audioErr := p.youtube.DownloadAudio(*content.SourceURL)
 			if audioErr != nil {
 				return fmt.Errorf("transcript extraction failed for video %s: %v; audio fallback download failed: %w", videoID, transcriptErr, audioErr)
 			}
 
-			transcribed, transcribeErr := gemini.TranscribeAudio(ctx, audioBytes, mimeType)
+			transcribed, transcribeErr := gemini.TranscribeAudio(services.WithUsageJob(ctx, job), audioPath, mimeType, transcriptionProgress(ctx, gemini, job))
+			os.Remove(audioPath)
 			if transcribeErr != nil {
 				return fmt.Errorf("transcript extraction failed for video %s: %v; STT fallback transcription failed: %w", videoID, transcriptErr, transcribeErr)
 			}
@@ -257,11 +374,15 @@ func (p *Pool) processSummary(ctx context.Context, job *models.Job) error {
 			transcript = transcribed
 		}
 
-		if updateErr := p.contentRepo.UpdateTranscript(ctx, content.ID, transcript); updateErr != nil {
+		if lang := p.recordDetectedLanguage(ctx, content.ID, transcript); lang != "" {
+			content.DetectedLanguage = &lang
+		}
+		stored, updateErr := p.saveTranscript(ctx, gemini, content, transcript)
+		if updateErr != nil {
 			return fmt.Errorf("failed to save transcript: %w", updateErr)
 		}
 
-		content.Transcript = &transcript
+		content.Transcript = &stored
 	}
 
 	transcript := ""
@@ -271,8 +392,15 @@ func (p *Pool) processSummary(ctx context.Context, job *models.Job) error {
 	if content.Transcript != nil && *content.Transcript != "" {
 		transcript = *content.Transcript
 	} else if content.Type == "file" {
-		if content.FilePath != nil && strings.HasSuffix(strings.ToLower(*content.FilePath), ".pdf") {
-			filePath = filepath.Join(p.storagePath, *content.FilePath)
+		// A redacted PDF with no readable text is not sent as-is, since that
+		// would hand the model the unmasked document.
+		if content.FilePath != nil && strings.HasSuffix(strings.ToLower(*content.FilePath), ".pdf") && !content.Redacted {
+			localPath, cleanupFile, err := services.LocalCopy(ctx, p.storage, *content.FilePath)
+			if err != nil {
+				return fmt.Errorf("failed to read uploaded file: %w", err)
+			}
+			defer cleanupFile()
+			filePath = localPath
 			mimeType = "application/pdf"
 		} else {
 			transcript = buildMetadataFallbackTranscript(content)
@@ -283,9 +411,73 @@ func (p *Pool) processSummary(ctx context.Context, job *models.Job) error {
 		return fmt.Errorf("cannot generate summary: transcript is not available")
 	}
 
+	// Content processed before languages were detected has none stored;
+	// detect it now so the summary can follow or translate it.
+	if content.DetectedLanguage == nil && content.Transcript != nil && *content.Transcript != "" {
+		if lang := p.recordDetectedLanguage(ctx, content.ID, *content.Transcript); lang != "" {
+			content.DetectedLanguage = &lang
+		}
+	}
+
+	// Without an explicit choice the summary follows the content's language.
+	if config.Language == "" || strings.EqualFold(config.Language, services.AutoLanguage) {
+		language := services.ResolveSummaryLanguage(config.Language, content.DetectedLanguage)
+		if updated, err := withConfigField(job.ConfigJSON, "language", language); err == nil {
+			job.ConfigJSON = updated
+		}
+	}
+	// The source language tells generation to translate when the summary is
+	// asked for in another one.
+	if content.DetectedLanguage != nil && *content.DetectedLanguage != "" {
+		if updated, err := withConfigField(job.ConfigJSON, "source_language", *content.DetectedLanguage); err == nil {
+			job.ConfigJSON = updated
+		}
+	}
+	if chapters := videoChapters(content); len(chapters) > 0 {
+		if updated, err := withConfigField(job.ConfigJSON, "chapters", chapters); err == nil {
+			job.ConfigJSON = updated
+		}
+	}
+
+	p.timings.NoteInputSize(ctx, len(transcript))
 	return gemini.GenerateSummary(ctx, job, transcript, filePath, mimeType)
 }
 
+// withConfigField sets one key of a job config, leaving the other keys
+// untouched.
+func withConfigField(configJSON json.RawMessage, key string, value interface{}) (json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if len(configJSON) > 0 {
+		if err := json.Unmarshal(configJSON, &fields); err != nil {
+			return nil, err
+		}
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields[key] = encoded
+	return json.Marshal(fields)
+}
+
+// processSummaryRewrite feeds an existing summary's content (not the transcript)
+// back through summary generation so the length bands condense or expand it.
+func (p *Pool) processSummaryRewrite(ctx context.Context, gemini *services.GeminiService, job *models.Job, sourceID uuid.UUID) error {
+	source, err := p.summaryRepo.GetByID(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to get source summary %s: %w", sourceID, err)
+	}
+	if source.UserID != job.UserID {
+		return permanentf("source summary %s does not belong to job owner", sourceID)
+	}
+	if source.ContentRaw == nil || strings.TrimSpace(*source.ContentRaw) == "" {
+		return permanentf("source summary %s has no content to rewrite", sourceID)
+	}
+
+	p.timings.NoteInputSize(ctx, len(*source.ContentRaw))
+	return gemini.GenerateSummary(ctx, job, *source.ContentRaw, "", "")
+}
+
 func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 	gemini, cleanup := p.resolveGemini(ctx, job.UserID)
 	defer cleanup()
@@ -296,7 +488,7 @@ func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 	}
 
 	if presentation.ContentID == nil {
-		return fmt.Errorf("presentation has no linked content")
+		return permanentf("presentation has no linked content")
 	}
 
 	content, err := p.contentRepo.GetByID(ctx, *presentation.ContentID)
@@ -317,7 +509,7 @@ func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 
 	if content.Type == "youtube" && (content.Transcript == nil || *content.Transcript == "") {
 		if content.SourceURL == nil {
-			return fmt.Errorf("youtube content has no source URL")
+			return permanentf("youtube content has no source URL")
 		}
 
 		videoID, extractErr := extractVideoID(*content.SourceURL)
@@ -334,14 +526,18 @@ func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 			},
 		})
 
-		transcript, transcriptErr := p.youtube.GetTranscript(ctx, videoID)
+		transcript, transcriptErr := p.youtube.GetTranscript(ctx, videoID, captionLanguage(content))
+		if errors.Is(transcriptErr, services.ErrCaptionLanguageUnavailable) {
+			return transcriptErr
+		}
 		if transcriptErr != nil {
-			audioBytes, mimeType, audioErr := p.youtube.DownloadAudio(*content.SourceURL)
+			audioPath, mimeType, audioErr := p.youtube.DownloadAudio(*content.SourceURL)
 			if audioErr != nil {
 				return fmt.Errorf("transcript extraction failed for video %s: %v; audio fallback download failed: %w", videoID, transcriptErr, audioErr)
 			}
 
-			transcribed, transcribeErr := gemini.TranscribeAudio(ctx, audioBytes, mimeType)
+			transcribed, transcribeErr := gemini.TranscribeAudio(services.WithUsageJob(ctx, job), audioPath, mimeType, transcriptionProgress(ctx, gemini, job))
+			os.Remove(audioPath)
 			if transcribeErr != nil {
 				return fmt.Errorf("transcript extraction failed for video %s: %v; STT fallback transcription failed: %w", videoID, transcriptErr, transcribeErr)
 			}
@@ -349,11 +545,15 @@ func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 			transcript = transcribed
 		}
 
-		if updateErr := p.contentRepo.UpdateTranscript(ctx, content.ID, transcript); updateErr != nil {
+		if lang := p.recordDetectedLanguage(ctx, content.ID, transcript); lang != "" {
+			content.DetectedLanguage = &lang
+		}
+		stored, updateErr := p.saveTranscript(ctx, gemini, content, transcript)
+		if updateErr != nil {
 			return fmt.Errorf("failed to save transcript: %w", updateErr)
 		}
 
-		content.Transcript = &transcript
+		content.Transcript = &stored
 	}
 
 	transcript := ""
@@ -363,8 +563,15 @@ func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 	if content.Transcript != nil && *content.Transcript != "" {
 		transcript = *content.Transcript
 	} else if content.Type == "file" {
-		if content.FilePath != nil && strings.HasSuffix(strings.ToLower(*content.FilePath), ".pdf") {
-			filePath = filepath.Join(p.storagePath, *content.FilePath)
+		// A redacted PDF with no readable text is not sent as-is, since that
+		// would hand the model the unmasked document.
+		if content.FilePath != nil && strings.HasSuffix(strings.ToLower(*content.FilePath), ".pdf") && !content.Redacted {
+			localPath, cleanupFile, err := services.LocalCopy(ctx, p.storage, *content.FilePath)
+			if err != nil {
+				return fmt.Errorf("failed to read uploaded file: %w", err)
+			}
+			defer cleanupFile()
+			filePath = localPath
 			mimeType = "application/pdf"
 		} else {
 			transcript = buildMetadataFallbackTranscript(content)
@@ -375,6 +582,7 @@ func (p *Pool) processPresentation(ctx context.Context, job *models.Job) error {
 		return fmt.Errorf("cannot generate presentation: transcript is not available")
 	}
 
+	p.timings.NoteInputSize(ctx, len(transcript))
 	return gemini.GeneratePresentation(ctx, job, transcript, filePath, mimeType)
 }
 
@@ -392,12 +600,12 @@ func (p *Pool) waitForContentReady(ctx context.Context, contentID uuid.UUID, tim
 		}
 
 		if content.Status == "failed" {
-			return nil, fmt.Errorf("content processing failed")
+			return nil, permanentf("content processing failed")
 		}
 
 		if content.Status == "completed" {
 			if content.Transcript == nil || *content.Transcript == "" {
-				return nil, fmt.Errorf("content completed without transcript")
+				return nil, permanentf("content completed without transcript")
 			}
 			return content, nil
 		}
@@ -430,13 +638,13 @@ func (p *Pool) processQuiz(ctx context.Context, job *models.Job) error {
 	// Validate job config before processing to avoid zero-value downstream behavior.
 	var config models.GenerateQuizRequest
 	if err := json.Unmarshal(job.ConfigJSON, &config); err != nil {
-		return fmt.Errorf("invalid quiz job config for job %s: %w", job.ID, err)
+		return permanentf("invalid quiz job config for job %s: %w", job.ID, err)
 	}
-	if config.NumQuestions <= 0 {
-		return fmt.Errorf("invalid quiz config for job %s: num_questions must be > 0, got %d", job.ID, config.NumQuestions)
+	if config.NumQuestions < services.MinQuizQuestions || config.NumQuestions > services.MaxQuizQuestions {
+		return permanentf("invalid quiz config for job %s: num_questions must be between %d and %d, got %d", job.ID, services.MinQuizQuestions, services.MaxQuizQuestions, config.NumQuestions)
 	}
 	if config.SummaryID == uuid.Nil {
-		return fmt.Errorf("invalid quiz config for job %s: summary_id is required", job.ID)
+		return permanentf("invalid quiz config for job %s: summary_id is required", job.ID)
 	}
 
 	summary, err := p.summaryRepo.GetByID(ctx, config.SummaryID)
@@ -449,6 +657,7 @@ func (p *Pool) processQuiz(ctx context.Context, job *models.Job) error {
 		content = *summary.ContentRaw
 	}
 
+	p.timings.NoteInputSize(ctx, len(content))
 	return gemini.GenerateQuiz(ctx, job, content)
 }
 
@@ -468,10 +677,10 @@ func (p *Pool) processFlashcard(ctx context.Context, job *models.Job) error {
 	// Validate job config before processing to avoid zero-value downstream behavior.
 	var config models.GenerateFlashcardsRequest
 	if err := json.Unmarshal(job.ConfigJSON, &config); err != nil {
-		return fmt.Errorf("invalid flashcard job config for job %s: %w", job.ID, err)
+		return permanentf("invalid flashcard job config for job %s: %w", job.ID, err)
 	}
-	if config.NumCards <= 0 {
-		return fmt.Errorf("invalid flashcard config for job %s: num_cards must be > 0, got %d", job.ID, config.NumCards)
+	if config.NumCards < services.MinFlashcards || config.NumCards > services.MaxFlashcards {
+		return permanentf("invalid flashcard config for job %s: num_cards must be between %d and %d, got %d", job.ID, services.MinFlashcards, services.MaxFlashcards, config.NumCards)
 	}
 
 	deck, err := p.flashRepo.GetDeckByID(ctx, job.ReferenceID)
@@ -479,8 +688,17 @@ func (p *Pool) processFlashcard(ctx context.Context, job *models.Job) error {
 		return fmt.Errorf("failed to get flashcard deck: %w", err)
 	}
 
+	if deck.ContentID != nil && *deck.ContentID != uuid.Nil {
+		transcript, err := p.flashcardTranscript(ctx, job, *deck.ContentID)
+		if err != nil {
+			return err
+		}
+		p.timings.NoteInputSize(ctx, len(transcript))
+		return gemini.GenerateFlashcards(ctx, job, transcript)
+	}
+
 	if deck.SummaryID == nil || *deck.SummaryID == uuid.Nil {
-		return fmt.Errorf("flashcard deck has no linked summary")
+		return permanentf("flashcard deck has no linked summary")
 	}
 
 	summary, err := p.summaryRepo.GetByID(ctx, *deck.SummaryID)
@@ -493,9 +711,30 @@ func (p *Pool) processFlashcard(ctx context.Context, job *models.Job) error {
 		content = *summary.ContentRaw
 	}
 
+	p.timings.NoteInputSize(ctx, len(content))
 	return gemini.GenerateFlashcards(ctx, job, content)
 }
 
+// flashcardTranscript returns the transcript a content-based deck is
+// generated from, waiting for content processing that is still running.
+func (p *Pool) flashcardTranscript(ctx context.Context, job *models.Job, contentID uuid.UUID) (string, error) {
+	content, err := p.contentRepo.GetByID(ctx, contentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get content: %w", err)
+	}
+	if content.UserID != job.UserID {
+		return "", permanentf("content %s does not belong to job owner", contentID)
+	}
+
+	if content.Transcript == nil || *content.Transcript == "" {
+		content, err = p.waitForContentReady(ctx, contentID, p.contentReadyTimeout)
+		if err != nil {
+			return "", err
+		}
+	}
+	return *content.Transcript, nil
+}
+
 func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 	gemini, cleanup := p.resolveGemini(ctx, job.UserID)
 	defer cleanup()
@@ -523,6 +762,8 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 			return extractErr
 		}
 
+		p.recordVideoChapters(ctx, content, videoID)
+
 		// Step 1: Fetch transcript
 		gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
 			Type: "status_update",
@@ -533,12 +774,18 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 			},
 		})
 
-		transcript, err := p.youtube.GetTranscript(ctx, videoID)
+		transcript, err := p.youtube.GetTranscript(ctx, videoID, captionLanguage(content))
+		if errors.Is(err, services.ErrCaptionLanguageUnavailable) {
+			// Transcribing the audio or using metadata would silently give the
+			// user content in the wrong language; fail so the status says why.
+			p.contentRepo.UpdateStatus(ctx, content.ID, "failed")
+			return err
+		}
 		if err != nil {
 			log.Printf("Transcript extraction failed for %s: %v", videoID, err)
 
 			// STT fallback via Gemini multimodal audio transcription
-			audioBytes, mimeType, audioErr := p.youtube.DownloadAudio(*content.SourceURL)
+			audioPath, mimeType, audioErr := p.youtube.DownloadAudio(*content.SourceURL)
 			if audioErr != nil {
 				fallbackTranscript := buildMetadataFallbackTranscript(content)
 				if saveErr := p.contentRepo.UpdateTranscript(ctx, content.ID, fallbackTranscript); saveErr != nil {
@@ -550,7 +797,8 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 				return nil
 			}
 
-			transcribed, transcribeErr := gemini.TranscribeAudio(ctx, audioBytes, mimeType)
+			transcribed, transcribeErr := gemini.TranscribeAudio(services.WithUsageJob(ctx, job), audioPath, mimeType, transcriptionProgress(ctx, gemini, job))
+			os.Remove(audioPath)
 			if transcribeErr != nil {
 				fallbackTranscript := buildMetadataFallbackTranscript(content)
 				if saveErr := p.contentRepo.UpdateTranscript(ctx, content.ID, fallbackTranscript); saveErr != nil {
@@ -566,7 +814,8 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 		}
 
 		// Step 2: Save transcript
-		if err := p.contentRepo.UpdateTranscript(ctx, content.ID, transcript); err != nil {
+		p.recordDetectedLanguage(ctx, content.ID, transcript)
+		if _, err := p.saveTranscript(ctx, gemini, content, transcript); err != nil {
 			p.contentRepo.UpdateStatus(ctx, content.ID, "failed")
 			return fmt.Errorf("failed to save transcript for video %s: %w", videoID, err)
 		}
@@ -577,26 +826,40 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 	if content.Type == "file" {
 		if content.FilePath == nil || *content.FilePath == "" {
 			p.contentRepo.UpdateStatus(ctx, content.ID, "failed")
-			return fmt.Errorf("file content has no file path")
+			return permanentf("file content has no file path")
 		}
 
-		fullPath := filepath.Join(p.storagePath, *content.FilePath)
-		ext := strings.ToLower(filepath.Ext(fullPath))
+		// Extractors work on paths, so uploads kept in remote storage are
+		// copied to a temporary file first. A file that is gone falls back
+		// to the metadata transcript like any extraction failure; other
+		// storage errors are retried.
+		fullPath, cleanupFile, fetchErr := services.LocalCopy(ctx, p.storage, *content.FilePath)
+		if fetchErr != nil && !errors.Is(fetchErr, services.ErrStorageObjectNotFound) && !errors.Is(fetchErr, services.ErrInvalidStorageKey) {
+			return fmt.Errorf("failed to read uploaded file %s: %w", *content.FilePath, fetchErr)
+		}
+		if fetchErr == nil {
+			defer cleanupFile()
+		}
+		ext := strings.ToLower(filepath.Ext(*content.FilePath))
 
 		var extracted string
 		var extractErr error
 
-		switch ext {
-		case ".docx":
+		switch {
+		case fetchErr != nil:
+			extractErr = fetchErr
+		case !p.uploads.AllowsExtension(ext):
+			extractErr = fmt.Errorf("file type %s is not enabled for uploads", ext)
+		case ext == ".txt", ext == ".docx", ext == ".srt", ext == ".vtt":
 			if p.fileExtract == nil {
 				extractErr = fmt.Errorf("file extraction service is not initialized")
 			} else {
 				extracted, extractErr = p.fileExtract.ExtractTextFromPath(fullPath)
 			}
-		case ".pdf":
-			// Skip local extraction for PDF; we pass it via File API during generation
-			extracted = ""
-			extractErr = nil
+		case ext == ".pdf":
+			extracted = p.extractPDFText(ctx, gemini, job, fullPath)
+		case ext == ".png", ext == ".jpg", ext == ".jpeg":
+			extracted, extractErr = p.ocrImageFile(ctx, gemini, job, fullPath)
 		default:
 			extractErr = fmt.Errorf("unsupported file type for extraction: %s", ext)
 		}
@@ -605,14 +868,15 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 			fallbackTranscript := buildMetadataFallbackTranscript(content)
 			if saveErr := p.contentRepo.UpdateTranscript(ctx, content.ID, fallbackTranscript); saveErr != nil {
 				p.contentRepo.UpdateStatus(ctx, content.ID, "failed")
-				return fmt.Errorf("failed to extract file text from %s: %v; failed to save fallback transcript: %v", fullPath, extractErr, saveErr)
+				return fmt.Errorf("failed to extract file text from %s: %v; failed to save fallback transcript: %v", *content.FilePath, extractErr, saveErr)
 			}
 
 			log.Printf("Using metadata-only fallback transcript for file content %s after extraction failure: %v", content.ID, extractErr)
 			return nil
 		}
 
-		if err := p.contentRepo.UpdateTranscript(ctx, content.ID, extracted); err != nil {
+		p.recordDetectedLanguage(ctx, content.ID, extracted)
+		if _, err := p.saveTranscript(ctx, gemini, content, extracted); err != nil {
 			p.contentRepo.UpdateStatus(ctx, content.ID, "failed")
 			return fmt.Errorf("failed to save extracted file text: %w", err)
 		}
@@ -625,6 +889,153 @@ func (p *Pool) processContent(ctx context.Context, job *models.Job) error {
 	return nil
 }
 
+// recordDetectedLanguage stores the transcript's language so summaries can
+// default to it. It runs before the transcript is saved, because saving marks
+// the content completed and releases waiting summary jobs. Detection failures
+// only lose the default, so they are logged and otherwise ignored.
+func (p *Pool) recordDetectedLanguage(ctx context.Context, contentID uuid.UUID, transcript string) string {
+	language := services.DetectLanguage(transcript)
+	if language == "" {
+		return ""
+	}
+	if err := p.contentRepo.UpdateDetectedLanguage(ctx, contentID, language); err != nil {
+		log.Printf("Failed to record detected language for content %s: %v", contentID, err)
+		return ""
+	}
+	return language
+}
+
+// saveTranscript stores a transcript extracted from the content's source,
+// first masking personal data when the owner has turned redaction on. It
+// returns the text that was stored. A failed name pass keeps the pattern-based
+// masking rather than failing the content.
+func (p *Pool) saveTranscript(ctx context.Context, gemini *services.GeminiService, content *models.Content, transcript string) (string, error) {
+	if p.userRepo == nil {
+		return transcript, p.contentRepo.UpdateTranscript(ctx, content.ID, transcript)
+	}
+	settings, err := p.userRepo.GetSettings(ctx, content.UserID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !settings.RedactTranscripts) {
+		return transcript, p.contentRepo.UpdateTranscript(ctx, content.ID, transcript)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load redaction settings: %w", err)
+	}
+
+	redacted, masked := services.RedactPII(transcript)
+	if settings.RedactNames {
+		names, nameErr := gemini.DetectPersonNames(ctx, redacted)
+		if nameErr != nil {
+			log.Printf("Name redaction failed for content %s, keeping pattern redaction only: %v", content.ID, nameErr)
+		} else {
+			var maskedNames int
+			redacted, maskedNames = services.RedactNames(redacted, names)
+			masked += maskedNames
+		}
+	}
+
+	var original *string
+	if settings.RetainOriginalTranscript && masked > 0 {
+		if p.contentRepo.EncryptsText() {
+			original = &transcript
+		} else {
+			// Keeping unmasked text in plaintext would defeat the redaction.
+			log.Printf("WARNING: not retaining the original transcript of content %s: CONTENT_ENCRYPTION_KEY is not set", content.ID)
+		}
+	}
+	if err := p.contentRepo.UpdateRedactedTranscript(ctx, content.ID, redacted, original); err != nil {
+		return "", err
+	}
+	content.Redacted = true
+	log.Printf("Redacted %d value(s) in transcript for content %s", masked, content.ID)
+	return redacted, nil
+}
+
+// extractPDFText reads the embedded text layer of a PDF and falls back to OCR of
+// rendered pages when the layer is missing or near-empty. An empty result leaves
+// the PDF to be passed via the File API during generation.
+func (p *Pool) extractPDFText(ctx context.Context, gemini *services.GeminiService, job *models.Job, fullPath string) string {
+	if p.fileExtract == nil {
+		return ""
+	}
+
+	embedded, err := p.fileExtract.ExtractTextFromPath(fullPath)
+	if err == nil && !services.NeedsOCR(embedded) {
+		return embedded
+	}
+
+	gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
+		Type: "status_update",
+		Payload: models.StatusUpdate{
+			JobID:    job.ID,
+			Step:     2,
+			StepName: "Recognizing text in scanned pages",
+		},
+	})
+
+	pages, renderErr := p.fileExtract.RenderPDFPages(ctx, fullPath)
+	if errors.Is(renderErr, services.ErrPDFRendererMissing) {
+		log.Printf("WARNING: PDF OCR skipped for %s: %v", fullPath, renderErr)
+		return embedded
+	}
+	if renderErr != nil {
+		log.Printf("PDF OCR render failed for %s: %v", fullPath, renderErr)
+		return embedded
+	}
+
+	var b strings.Builder
+	for i, page := range pages {
+		text, ocrErr := gemini.OCRImage(ctx, page, "image/png")
+		if ocrErr != nil {
+			log.Printf("PDF OCR failed for page %d of %s: %v", i+1, fullPath, ocrErr)
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		b.WriteString(text)
+		b.WriteString("\n\n")
+	}
+
+	ocrText := strings.TrimSpace(b.String())
+	if len(ocrText) <= len(strings.TrimSpace(embedded)) {
+		return embedded
+	}
+
+	log.Printf("Recovered %d chars via OCR from %d rendered pages of %s", len(ocrText), len(pages), fullPath)
+	return ocrText
+}
+
+func (p *Pool) ocrImageFile(ctx context.Context, gemini *services.GeminiService, job *models.Job, fullPath string) (string, error) {
+	mimeType, ok := services.ImageMimeType(fullPath)
+	if !ok {
+		return "", fmt.Errorf("unsupported image type: %s", filepath.Ext(fullPath))
+	}
+
+	imageBytes, err := os.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
+		Type: "status_update",
+		Payload: models.StatusUpdate{
+			JobID:    job.ID,
+			Step:     2,
+			StepName: "Recognizing text in image",
+		},
+	})
+
+	text, err := gemini.OCRImage(ctx, imageBytes, mimeType)
+	if err != nil {
+		return "", fmt.Errorf("image OCR failed: %w", err)
+	}
+	if text == "" {
+		return "", fmt.Errorf("no readable text found in image")
+	}
+
+	return text, nil
+}
+
 func buildMetadataFallbackTranscript(content *models.Content) string {
 	sourceURL := ""
 	if content.SourceURL != nil {
@@ -728,8 +1139,12 @@ func (p *Pool) handleSuccess(ctx context.Context, job *models.Job) {
 		return
 	}
 
-	if job.Type == "summary-generation" {
-		go p.sendSummaryCompletionEmail(context.Background(), job)
+	switch job.Type {
+	case "summary-generation", "quiz-generation", "flashcard-generation":
+		go p.sendCompletionEmail(context.Background(), job)
+	}
+	if job.Type == "data-export" {
+		go p.sendDataExportEmail(context.Background(), job)
 	}
 
 	p.gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
@@ -741,62 +1156,186 @@ func (p *Pool) handleSuccess(ctx context.Context, job *models.Job) {
 		},
 	})
 
+	if job.Type == "summary-generation" {
+		p.enqueueAutoGenerate(ctx, job)
+	}
+
 	log.Printf("Job %s completed successfully", job.ID)
 }
 
-func (p *Pool) sendSummaryCompletionEmail(ctx context.Context, job *models.Job) {
-	if p.email == nil || p.userRepo == nil || p.summaryRepo == nil {
+// sendCompletionEmail emails the job owner that their summary, quiz or
+// flashcard deck is ready, unless they turned off processing_complete
+// notifications.
+func (p *Pool) sendCompletionEmail(ctx context.Context, job *models.Job) {
+	if p.email == nil || p.userRepo == nil {
 		return
 	}
 
-	enabled, err := p.userRepo.GetNotificationSetting(ctx, job.UserID, "processing_complete", true)
+	user, title, err := p.loadCompletionEmailTarget(ctx, job)
 	if err != nil {
-		log.Printf("failed to load processing_complete notification preference for user %s: %v", job.UserID, err)
+		log.Printf("failed to prepare completion email for job %s: %v", job.ID, err)
+		return
+	}
+	if user == nil {
 		return
 	}
 
+	resultID := job.ReferenceID.String()
+	switch job.Type {
+	case "summary-generation":
+		err = p.email.SendProcessingCompleteEmail(user.Email, title, resultID)
+	case "quiz-generation":
+		err = p.email.SendQuizReadyEmail(user.Email, title, resultID)
+	case "flashcard-generation":
+		err = p.email.SendFlashcardsReadyEmail(user.Email, title, resultID)
+	}
+	if err != nil {
+		log.Printf("failed to send processing-complete email to %s for %s %s: %v", user.Email, getResultType(job.Type), resultID, err)
+	}
+}
+
+// loadCompletionEmailTarget returns the job owner and the title of the job's
+// result. A nil user means the owner opted out of completion emails.
+func (p *Pool) loadCompletionEmailTarget(ctx context.Context, job *models.Job) (*models.User, string, error) {
+	enabled, err := p.userRepo.GetNotificationSetting(ctx, job.UserID, "processing_complete", true)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load processing_complete notification preference for user %s: %w", job.UserID, err)
+	}
 	if !enabled {
-		return
+		return nil, "", nil
 	}
 
 	user, err := p.userRepo.GetByID(ctx, job.UserID)
 	if err != nil {
-		log.Printf("failed to load user %s for completion email: %v", job.UserID, err)
+		return nil, "", fmt.Errorf("failed to load user %s: %w", job.UserID, err)
+	}
+
+	var title string
+	switch job.Type {
+	case "summary-generation":
+		if p.summaryRepo == nil {
+			return nil, "", fmt.Errorf("summary repository is not configured")
+		}
+		summary, err := p.summaryRepo.GetByID(ctx, job.ReferenceID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load summary %s: %w", job.ReferenceID, err)
+		}
+		title = summary.Title
+	case "quiz-generation":
+		if p.quizRepo == nil {
+			return nil, "", fmt.Errorf("quiz repository is not configured")
+		}
+		quiz, err := p.quizRepo.GetByID(ctx, job.ReferenceID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load quiz %s: %w", job.ReferenceID, err)
+		}
+		title = quiz.Title
+	case "flashcard-generation":
+		if p.flashRepo == nil {
+			return nil, "", fmt.Errorf("flashcard repository is not configured")
+		}
+		deck, err := p.flashRepo.GetDeckByID(ctx, job.ReferenceID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load flashcard deck %s: %w", job.ReferenceID, err)
+		}
+		title = deck.Title
+	default:
+		return nil, "", fmt.Errorf("no completion email for job type %q", job.Type)
+	}
+
+	return user, title, nil
+}
+
+// processDataExport stores the user's data export archive. It is built in a
+// temporary file first, so a partial archive is never stored.
+func (p *Pool) processDataExport(ctx context.Context, job *models.Job) error {
+	if p.exportRepo == nil || p.storage == nil {
+		return permanentf("data export is not configured")
+	}
+
+	data, err := p.exportRepo.CollectUserData(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to collect export data: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "lectura-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := services.WriteDataExportZip(f, data); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+
+	key := services.DataExportKey(job.UserID, job.ID)
+	if err := p.storage.Put(ctx, key, f, size); err != nil {
+		return fmt.Errorf("failed to store export archive: %w", err)
+	}
+
+	// An account deleted while its export ran takes the job with it; its
+	// archive must not outlive it.
+	if _, err := p.jobRepo.GetByID(ctx, job.ID); errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("data export %s: account deleted during export, discarding archive", job.ID)
+		return p.storage.Delete(ctx, key)
+	}
+	return nil
+}
+
+func (p *Pool) sendDataExportEmail(ctx context.Context, job *models.Job) {
+	if p.email == nil || p.userRepo == nil {
 		return
 	}
 
-	summary, err := p.summaryRepo.GetByID(ctx, job.ReferenceID)
+	user, err := p.userRepo.GetByID(ctx, job.UserID)
 	if err != nil {
-		log.Printf("failed to load summary %s for completion email: %v", job.ReferenceID, err)
+		log.Printf("failed to load user %s for data export email: %v", job.UserID, err)
 		return
 	}
 
-	if err := p.email.SendProcessingCompleteEmail(user.Email, summary.Title, summary.ID.String()); err != nil {
-		log.Printf("failed to send processing-complete email to %s for summary %s: %v", user.Email, summary.ID, err)
+	if err := p.email.SendDataExportReadyEmail(user.Email, user.FullName, job.ID.String()); err != nil {
+		log.Printf("failed to send data export email to %s for job %s: %v", user.Email, job.ID, err)
 	}
 }
 
 func (p *Pool) handleFailure(ctx context.Context, job *models.Job, err error) {
 	job.RetryCount++
 	errMsg := err.Error()
+	policy := p.retryPolicies.For(job.Type)
 
-	if job.RetryCount < 3 {
+	if shouldRetry(policy, job.RetryCount, err) {
 		// Re-queue with backoff
-		log.Printf("Job %s failed (attempt %d): %s — retrying", job.ID, job.RetryCount, errMsg)
+		log.Printf("Job %s failed (attempt %d/%d): %s — retrying", job.ID, job.RetryCount, policy.MaxRetries, errMsg)
 		p.jobRepo.UpdateStatus(ctx, job.ID, "pending")
 		p.jobRepo.UpdateError(ctx, job.ID, errMsg, job.RetryCount)
 
 		// Re-queue after backoff
 		jobBytes, _ := json.Marshal(job)
-		backoff := time.Duration(1<<uint(job.RetryCount)) * time.Second
-		time.AfterFunc(backoff, func() {
-			p.redis.RPush(context.Background(), jobQueueName(job.Type), string(jobBytes))
+		time.AfterFunc(policy.Delay(job.RetryCount), func() {
+			p.redis.RPush(context.Background(), JobQueueName(job.Type), string(jobBytes))
 		})
 	} else {
-		// Max retries reached
-		log.Printf("Job %s failed permanently: %s", job.ID, errMsg)
+		// Permanent error or max retries reached
+		if isPermanentJobError(err) {
+			log.Printf("Job %s failed with a non-retryable error: %s", job.ID, errMsg)
+		} else {
+			log.Printf("Job %s failed permanently: %s", job.ID, errMsg)
+		}
 		p.jobRepo.UpdateStatus(ctx, job.ID, "failed")
 		p.jobRepo.UpdateError(ctx, job.ID, errMsg, job.RetryCount)
+		errorCode := "JOB_FAILED"
+		if code := jobErrorCode(err); code != "" {
+			p.jobRepo.SetErrorCode(ctx, job.ID, code)
+			errorCode = strings.ToUpper(code)
+		}
 		if job.Type == "content-processing" {
 			p.contentRepo.UpdateStatus(ctx, job.ReferenceID, "failed")
 		}
@@ -804,18 +1343,196 @@ func (p *Pool) handleFailure(ctx context.Context, job *models.Job, err error) {
 			_ = p.presentationRepo.UpdateStatus(ctx, job.ReferenceID, "failed")
 		}
 
-		p.gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
-			Type: "error",
-			Payload: models.ErrorEvent{
-				JobID:        job.ID,
-				ErrorCode:    "JOB_FAILED",
-				ErrorMessage: errMsg,
-			},
-		})
+		if p.gemini != nil {
+			p.gemini.PublishUpdate(ctx, job.UserID, models.WSMessage{
+				Type: "error",
+				Payload: models.ErrorEvent{
+					JobID:        job.ID,
+					ErrorCode:    errorCode,
+					ErrorMessage: errMsg,
+				},
+			})
+		}
+	}
+}
+
+// jobErrorCode classifies a permanent failure the UI explains on its own,
+// or returns "" for a generic failure.
+func jobErrorCode(err error) string {
+	if errors.Is(err, services.ErrBlockedBySafety) {
+		return services.JobErrorBlockedBySafety
+	}
+	return ""
+}
+
+// sweepExpiredExports periodically deletes data export archives once they
+// are older than services.DataExportRetention, together with their jobs.
+func (p *Pool) sweepExpiredExports() {
+	if p.storage == nil {
+		return
+	}
+
+	ticker := time.NewTicker(dataExportSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			p.deleteExpiredExports(context.Background())
+		}
+	}
+}
+
+func (p *Pool) deleteExpiredExports(ctx context.Context) {
+	jobs, err := p.jobRepo.ListCompletedBefore(ctx, "data-export", services.DataExportRetention, 100)
+	if err != nil {
+		log.Printf("data export sweep: failed to list exports: %v", err)
+		return
 	}
+
+	for _, job := range jobs {
+		if err := p.storage.Delete(ctx, services.DataExportKey(job.UserID, job.ID)); err != nil {
+			log.Printf("data export sweep: failed to delete archive of job %s: %v", job.ID, err)
+			continue
+		}
+		if err := p.jobRepo.Delete(ctx, job.ID); err != nil {
+			log.Printf("data export sweep: failed to delete job %s: %v", job.ID, err)
+			continue
+		}
+		log.Printf("data export sweep: deleted expired export %s", job.ID)
+	}
+}
+
+// sweepStuckJobs periodically requeues jobs whose worker lock has expired while
+// the job is still marked 'processing' (e.g. the worker crashed mid-job).
+func (p *Pool) sweepStuckJobs() {
+	if p.stuckJobThreshold <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(stuckJobSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			p.requeueStuckJobs(context.Background())
+		}
+	}
+}
+
+func (p *Pool) requeueStuckJobs(ctx context.Context) {
+	jobs, err := p.jobRepo.ListStuck(ctx, p.stuckJobThreshold, 100)
+	if err != nil {
+		log.Printf("stuck job sweep: failed to list jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		held, err := p.redis.Exists(ctx, JobLockKey(job.ID)).Result()
+		if err != nil || held > 0 {
+			continue // A worker still owns this job
+		}
+
+		// A lost attempt counts like a failed one, so a job that keeps
+		// crashing or hanging its worker ends up failed instead of looping.
+		stuckErr := fmt.Errorf("job was still processing after %s with no worker holding it", p.stuckJobThreshold)
+		if !shouldRetry(p.retryPolicies.For(job.Type), job.RetryCount+1, stuckErr) {
+			log.Printf("stuck job sweep: job %s (type: %s) is out of retries", job.ID, job.Type)
+			p.handleFailure(ctx, job, stuckErr)
+			continue
+		}
+
+		reset, err := p.jobRepo.RequeueStuck(ctx, job.ID)
+		if err != nil || !reset {
+			continue
+		}
+		job.Status = "pending"
+		job.StartedAt = nil
+		job.RetryCount++
+
+		jobBytes, _ := json.Marshal(job)
+		if err := p.redis.LPush(ctx, JobQueueName(job.Type), string(jobBytes)).Err(); err != nil {
+			log.Printf("stuck job sweep: failed to requeue job %s: %v", job.ID, err)
+			p.jobRepo.UpdateStatus(ctx, job.ID, "failed")
+			continue
+		}
+		log.Printf("stuck job sweep: requeued job %s (type: %s)", job.ID, job.Type)
+	}
+}
+
+// videoChapters returns the chapters stored for YouTube content.
+func videoChapters(content *models.Content) []models.VideoChapter {
+	var meta models.YouTubeMetadata
+	if content.Type != "youtube" || len(content.MetadataJSON) == 0 || json.Unmarshal(content.MetadataJSON, &meta) != nil {
+		return nil
+	}
+	return meta.Chapters
+}
+
+// recordVideoChapters stores a YouTube video's chapters in the content's
+// metadata so summaries can follow them. Content that already has chapters
+// keeps them. A failed scrape only loses the chapters, so it is logged and
+// otherwise ignored.
+func (p *Pool) recordVideoChapters(ctx context.Context, content *models.Content, videoID string) {
+	if p.youtube == nil {
+		return
+	}
+	meta := map[string]json.RawMessage{}
+	if len(content.MetadataJSON) > 0 {
+		if err := json.Unmarshal(content.MetadataJSON, &meta); err != nil {
+			return
+		}
+	}
+	if _, ok := meta["chapters"]; ok {
+		return
+	}
+
+	fetched, err := p.youtube.VideoMetadata(ctx, videoID, false)
+	if err != nil {
+		log.Printf("Failed to fetch chapters for video %s: %v", videoID, err)
+		return
+	}
+	if len(fetched.Chapters) == 0 {
+		return
+	}
+	encoded, err := json.Marshal(fetched.Chapters)
+	if err != nil {
+		return
+	}
+	meta["chapters"] = encoded
+	updated, err := json.Marshal(meta)
+	if err != nil {
+		return
+	}
+	if err := p.contentRepo.UpdateMetadata(ctx, content.ID, updated); err != nil {
+		log.Printf("Failed to record chapters for content %s: %v", content.ID, err)
+		return
+	}
+	content.MetadataJSON = updated
+}
+
+// captionLanguage returns the caption track requested for YouTube content, or
+// "" when any track will do.
+func captionLanguage(content *models.Content) string {
+	var meta models.YouTubeMetadata
+	if len(content.MetadataJSON) == 0 || json.Unmarshal(content.MetadataJSON, &meta) != nil {
+		return ""
+	}
+	return meta.CaptionLanguage
+}
+
+// JobLockKey is the Redis key a worker holds while processing a job.
+func JobLockKey(jobID uuid.UUID) string {
+	return fmt.Sprintf("job_lock:%s", jobID.String())
 }
 
-func jobQueueName(jobType string) string {
+// JobQueueName returns the Redis list a job of the given type is pushed to.
+func JobQueueName(jobType string) string {
 	switch jobType {
 	case "content-processing":
 		return "queue:content-processing"
@@ -827,6 +1544,8 @@ func jobQueueName(jobType string) string {
 		return "queue:quiz-generation"
 	case "flashcard-generation":
 		return "queue:flashcard-generation"
+	case "data-export":
+		return "queue:data-export"
 	default:
 		return "queue:" + jobType
 	}
@@ -842,6 +1561,8 @@ func getResultType(jobType string) string {
 		return "quiz"
 	case "flashcard-generation":
 		return "flashcard"
+	case "data-export":
+		return "export"
 	default:
 		return "content"
 	}
@@ -2,16 +2,22 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 
 	"lectura-backend/internal/models"
 )
 
 type stubWorkerJobRepo struct {
-	job *models.Job
+	job      *models.Job
+	stuck    []*models.Job
+	requeued []uuid.UUID
+	statuses map[uuid.UUID]string
 }
 
 func (s *stubWorkerJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
@@ -23,6 +29,10 @@ func (s *stubWorkerJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.
 
 func (s *stubWorkerJobRepo) Create(ctx context.Context, j *models.Job) error { return nil }
 func (s *stubWorkerJobRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	if s.statuses == nil {
+		s.statuses = map[uuid.UUID]string{}
+	}
+	s.statuses[id] = status
 	return nil
 }
 func (s *stubWorkerJobRepo) UpdateStatusIfNotTerminal(ctx context.Context, id uuid.UUID, status string) (bool, error) {
@@ -31,6 +41,24 @@ func (s *stubWorkerJobRepo) UpdateStatusIfNotTerminal(ctx context.Context, id uu
 func (s *stubWorkerJobRepo) UpdateError(ctx context.Context, id uuid.UUID, errMsg string, retryCount int) error {
 	return nil
 }
+func (s *stubWorkerJobRepo) SetErrorCode(ctx context.Context, id uuid.UUID, code string) error {
+	return nil
+}
+func (s *stubWorkerJobRepo) ListStuck(ctx context.Context, olderThan time.Duration, limit int) ([]*models.Job, error) {
+	return s.stuck, nil
+}
+func (s *stubWorkerJobRepo) ResetToPending(ctx context.Context, id uuid.UUID, fromStatuses ...string) (bool, error) {
+	return true, nil
+}
+
+func (s *stubWorkerJobRepo) RequeueStuck(ctx context.Context, id uuid.UUID) (bool, error) {
+	s.requeued = append(s.requeued, id)
+	return true, nil
+}
+func (s *stubWorkerJobRepo) ListCompletedBefore(ctx context.Context, jobType string, olderThan time.Duration, limit int) ([]*models.Job, error) {
+	return nil, nil
+}
+func (s *stubWorkerJobRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
 
 func TestProcessQuiz_MalformedConfig_ReturnsError(t *testing.T) {
 	p := &Pool{jobRepo: &stubWorkerJobRepo{job: &models.Job{Status: "pending"}}}
@@ -88,3 +116,70 @@ func TestProcessFlashcard_ZeroCards_ReturnsError(t *testing.T) {
 	}
 }
 
+// recordingQueueHook answers Redis commands without a server: EXISTS reports
+// no lock and LPUSH records the pushed payload.
+type recordingQueueHook struct {
+	pushed map[string][]string
+}
+
+func (h *recordingQueueHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *recordingQueueHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		switch cmd.Name() {
+		case "exists":
+			cmd.(*redis.IntCmd).SetVal(0)
+		case "lpush":
+			args := cmd.Args()
+			key := args[1].(string)
+			h.pushed[key] = append(h.pushed[key], args[2].(string))
+			cmd.(*redis.IntCmd).SetVal(int64(len(h.pushed[key])))
+		}
+		return nil
+	}
+}
+
+func (h *recordingQueueHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRequeueStuckJobs_RetriesUntilTheLimit(t *testing.T) {
+	belowLimit := &models.Job{ID: uuid.New(), UserID: uuid.New(), Type: "quiz-generation", Status: "processing", RetryCount: 1}
+	atLimit := &models.Job{ID: uuid.New(), UserID: uuid.New(), Type: "quiz-generation", Status: "processing", RetryCount: 2}
+	jobs := &stubWorkerJobRepo{stuck: []*models.Job{belowLimit, atLimit}}
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	hook := &recordingQueueHook{pushed: map[string][]string{}}
+	client.AddHook(hook)
+
+	p := &Pool{
+		jobRepo:           jobs,
+		redis:             client,
+		stuckJobThreshold: time.Minute,
+		retryPolicies:     NewRetryPolicies(RetryPolicy{MaxRetries: 3, BaseBackoff: time.Second}, nil),
+	}
+	p.requeueStuckJobs(context.Background())
+
+	pushed := hook.pushed[JobQueueName("quiz-generation")]
+	if len(pushed) != 1 {
+		t.Fatalf("expected exactly one job pushed, got %d", len(pushed))
+	}
+	var requeued models.Job
+	if err := json.Unmarshal([]byte(pushed[0]), &requeued); err != nil {
+		t.Fatalf("failed to decode pushed job: %v", err)
+	}
+	if requeued.ID != belowLimit.ID || requeued.RetryCount != 2 || requeued.Status != "pending" {
+		t.Fatalf("expected job %s requeued as pending with retry count 2, got %+v", belowLimit.ID, requeued)
+	}
+	if len(jobs.requeued) != 1 || jobs.requeued[0] != belowLimit.ID {
+		t.Fatalf("expected only job %s reset to pending, got %v", belowLimit.ID, jobs.requeued)
+	}
+
+	if got := jobs.statuses[atLimit.ID]; got != "failed" {
+		t.Fatalf("expected job at the retry limit to be marked failed, got %q", got)
+	}
+	if _, ok := jobs.statuses[belowLimit.ID]; ok {
+		t.Fatalf("expected requeued job status to be left to RequeueStuck, got %q", jobs.statuses[belowLimit.ID])
+	}
+}
@@ -0,0 +1,190 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"lectura-backend/internal/services"
+)
+
+// RetryPolicy controls how often a failed job is retried and how long to wait
+// between attempts. MaxRetries counts attempts, matching Job.RetryCount.
+type RetryPolicy struct {
+	MaxRetries  int
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between attempts; 0 uses maxRetryBackoff.
+	MaxBackoff time.Duration
+}
+
+const (
+	// maxRetryBackoff caps the exponential backoff between attempts when the
+	// policy sets no cap.
+	maxRetryBackoff = time.Minute
+
+	// retryJitter spreads each delay by up to ±20% so jobs that failed
+	// together (a Gemini outage, say) are not re-queued together.
+	retryJitter = 0.2
+)
+
+func (rp RetryPolicy) maxBackoff() time.Duration {
+	if rp.MaxBackoff <= 0 {
+		return maxRetryBackoff
+	}
+	return rp.MaxBackoff
+}
+
+// Backoff returns the delay before the given attempt is re-queued, before
+// jitter.
+func (rp RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 16 {
+		attempt = 16
+	}
+	backoff := rp.BaseBackoff * time.Duration(1<<uint(attempt))
+	if limit := rp.maxBackoff(); backoff > limit {
+		return limit
+	}
+	return backoff
+}
+
+// Delay returns the jittered delay before the given attempt is re-queued.
+func (rp RetryPolicy) Delay(attempt int) time.Duration {
+	return retryDelay(rp, attempt, rand.Float64())
+}
+
+// retryDelay is Backoff moved by up to ±retryJitter of itself: r in [0, 1)
+// picks where in that range the delay falls. The result never exceeds the
+// policy's cap.
+func retryDelay(rp RetryPolicy, attempt int, r float64) time.Duration {
+	backoff := rp.Backoff(attempt)
+	delay := time.Duration(float64(backoff) * (1 + retryJitter*(2*r-1)))
+	if limit := rp.maxBackoff(); delay > limit {
+		return limit
+	}
+	return delay
+}
+
+// RetryPolicies holds the policy per job type, falling back to Default.
+type RetryPolicies struct {
+	Default RetryPolicy
+	ByType  map[string]RetryPolicy
+}
+
+// For returns the retry policy for a job type.
+func (rps RetryPolicies) For(jobType string) RetryPolicy {
+	if rp, ok := rps.ByType[jobType]; ok {
+		return rp
+	}
+	return rps.Default
+}
+
+// NewRetryPolicies builds per-type policies from a default and override
+// entries of the form "type:max_retries[:base_backoff_seconds]". Transcript
+// extraction talks to flaky upstreams, so content processing gets extra
+// attempts unless overridden. Malformed entries and unknown job types are
+// logged and skipped.
+func NewRetryPolicies(def RetryPolicy, overrides []string) RetryPolicies {
+	if def.MaxRetries <= 0 {
+		def.MaxRetries = 3
+	}
+	if def.BaseBackoff <= 0 {
+		def.BaseBackoff = time.Second
+	}
+	if def.MaxBackoff <= 0 {
+		def.MaxBackoff = maxRetryBackoff
+	}
+
+	rps := RetryPolicies{
+		Default: def,
+		ByType: map[string]RetryPolicy{
+			"content-processing": {MaxRetries: def.MaxRetries + 2, BaseBackoff: def.BaseBackoff, MaxBackoff: def.MaxBackoff},
+		},
+	}
+
+	for _, entry := range overrides {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			log.Printf("ignoring malformed job retry policy %q", entry)
+			continue
+		}
+		jobType := strings.TrimSpace(parts[0])
+		if !isKnownJobType(jobType) {
+			log.Printf("ignoring job retry policy for unknown job type %q", entry)
+			continue
+		}
+		rp := def
+		maxRetries, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || maxRetries < 1 {
+			log.Printf("ignoring malformed job retry policy %q", entry)
+			continue
+		}
+		rp.MaxRetries = maxRetries
+		if len(parts) == 3 {
+			seconds, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+			if err != nil || seconds < 0 {
+				log.Printf("ignoring malformed job retry policy %q", entry)
+				continue
+			}
+			rp.BaseBackoff = time.Duration(seconds) * time.Second
+		}
+		rps.ByType[jobType] = rp
+	}
+
+	return rps
+}
+
+// permanentError marks a job failure that will recur on every attempt (bad
+// job config, missing links), so retrying it only burns quota.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanentf formats a job failure that should skip retries.
+func permanentf(format string, args ...any) error {
+	return &permanentError{err: fmt.Errorf(format, args...)}
+}
+
+// permanentServiceErrors are failures reported by services that will recur
+// on every attempt: model output that failed validation, missing caption
+// languages and safety refusals.
+var permanentServiceErrors = []error{
+	services.ErrNoValidQuestions,
+	services.ErrNoValidCards,
+	services.ErrCaptionLanguageUnavailable,
+	services.ErrBlockedBySafety,
+}
+
+// isPermanentJobError reports whether a job failure should skip retries.
+// Anything not recognised (Gemini API errors, timeouts, network failures) is
+// treated as transient.
+func isPermanentJobError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return true
+	}
+	for _, target := range permanentServiceErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry decides whether a job that has now failed attempt times gets
+// another attempt under rp.
+func shouldRetry(rp RetryPolicy, attempt int, err error) bool {
+	return !isPermanentJobError(err) && attempt < rp.MaxRetries
+}
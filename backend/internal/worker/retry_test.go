@@ -0,0 +1,149 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lectura-backend/internal/services"
+)
+
+func TestShouldRetry_Matrix(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseBackoff: time.Second}
+	jobID := uuid.New()
+
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		want    bool
+	}{
+		{"gemini api error first attempt", fmt.Errorf("Gemini API error: %w", errors.New("503 unavailable")), 1, true},
+		{"gemini api error second attempt", fmt.Errorf("Gemini API error: %w", errors.New("503 unavailable")), 2, true},
+		{"gemini api error exhausted", fmt.Errorf("Gemini API error: %w", errors.New("503 unavailable")), 3, false},
+		{"transcript network failure", errors.New("transcript extraction failed for video abc: timeout"), 1, true},
+		{"content not ready", errors.New("content transcript not ready yet (status: processing)"), 1, true},
+		{"zero valid questions", services.ErrNoValidQuestions, 1, false},
+		{"zero valid cards", fmt.Errorf("flashcard job: %w", services.ErrNoValidCards), 1, false},
+		{"invalid quiz config", permanentf("invalid quiz config for job %s: summary_id is required", jobID), 1, false},
+		{"invalid flashcard job config", permanentf("invalid flashcard job config for job %s: %w", jobID, errors.New("bad json")), 1, false},
+		{"deck without summary", permanentf("flashcard deck has no linked summary"), 1, false},
+		{"wrapped permanent error", fmt.Errorf("flashcard job: %w", permanentf("content %s does not belong to job owner", jobID)), 1, false},
+		{"unknown job type", permanentf("unknown job type: bogus"), 1, false},
+		{"missing caption language", fmt.Errorf("%w (es) for video abc", services.ErrCaptionLanguageUnavailable), 1, false},
+		{"blocked by safety filters", fmt.Errorf("STT fallback transcription failed: %w", services.ErrBlockedBySafety), 1, false},
+		{"upstream text mentioning a permanent failure", errors.New("Gemini API error: 503 (has no linked deployment)"), 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetry(policy, tt.attempt, tt.err); got != tt.want {
+				t.Fatalf("shouldRetry(attempt=%d, %q) = %v, want %v", tt.attempt, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRetryPolicies_Overrides(t *testing.T) {
+	rps := NewRetryPolicies(RetryPolicy{MaxRetries: 3, BaseBackoff: time.Second}, []string{
+		"quiz-generation:1",
+		"data-export:2:10",
+		"content-processing:7",
+		"broken",
+		"summary-generation:zero",
+		"quiz-genration:9",
+	})
+
+	tests := []struct {
+		jobType     string
+		wantRetries int
+		wantBackoff time.Duration
+	}{
+		{"quiz-generation", 1, time.Second},
+		{"data-export", 2, 10 * time.Second},
+		{"content-processing", 7, time.Second},
+		{"summary-generation", 3, time.Second},
+		{"presentation", 3, time.Second},
+		{"quiz-genration", 3, time.Second},
+	}
+	for _, tt := range tests {
+		got := rps.For(tt.jobType)
+		if got.MaxRetries != tt.wantRetries || got.BaseBackoff != tt.wantBackoff {
+			t.Fatalf("%s: got %+v, want retries=%d backoff=%s", tt.jobType, got, tt.wantRetries, tt.wantBackoff)
+		}
+	}
+	if _, ok := rps.ByType["quiz-genration"]; ok {
+		t.Fatalf("expected an override for an unknown job type to be ignored")
+	}
+}
+
+func TestNewRetryPolicies_ContentProcessingGetsExtraAttemptsByDefault(t *testing.T) {
+	rps := NewRetryPolicies(RetryPolicy{MaxRetries: 3, BaseBackoff: time.Second}, nil)
+	if got := rps.For("content-processing").MaxRetries; got != 5 {
+		t.Fatalf("expected 5 attempts for content processing, got %d", got)
+	}
+}
+
+func TestRetryPolicy_BackoffDoublesAndCaps(t *testing.T) {
+	rp := RetryPolicy{MaxRetries: 10, BaseBackoff: time.Second}
+	if got := rp.Backoff(1); got != 2*time.Second {
+		t.Fatalf("attempt 1: expected 2s, got %s", got)
+	}
+	if got := rp.Backoff(2); got != 4*time.Second {
+		t.Fatalf("attempt 2: expected 4s, got %s", got)
+	}
+	if got := rp.Backoff(20); got != maxRetryBackoff {
+		t.Fatalf("expected backoff capped at %s, got %s", maxRetryBackoff, got)
+	}
+}
+
+func TestRetryPolicy_BackoffUsesConfiguredCap(t *testing.T) {
+	rp := RetryPolicy{MaxRetries: 10, BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	if got := rp.Backoff(3); got != 8*time.Second {
+		t.Fatalf("attempt 3: expected 8s, got %s", got)
+	}
+	if got := rp.Backoff(4); got != 10*time.Second {
+		t.Fatalf("attempt 4: expected the 10s cap, got %s", got)
+	}
+}
+
+func TestRetryDelay_JittersWithinTwentyPercent(t *testing.T) {
+	rp := RetryPolicy{MaxRetries: 10, BaseBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		r       float64
+		want    time.Duration
+	}{
+		{2, 0, 3200 * time.Millisecond},
+		{2, 0.5, 4 * time.Second},
+		{2, 0.75, 4400 * time.Millisecond},
+		{20, 0, 48 * time.Second},
+		{20, 0.99, maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(rp, tt.attempt, tt.r); got != tt.want {
+			t.Fatalf("retryDelay(attempt=%d, r=%v) = %s, want %s", tt.attempt, tt.r, got, tt.want)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := rp.Delay(3); got < 6400*time.Millisecond || got > 9600*time.Millisecond {
+			t.Fatalf("Delay(3) = %s, want within 20%% of 8s", got)
+		}
+	}
+}
+
+func TestNewRetryPolicies_OverridesKeepMaxBackoff(t *testing.T) {
+	rps := NewRetryPolicies(RetryPolicy{MaxRetries: 3, BaseBackoff: time.Second, MaxBackoff: 30 * time.Second}, []string{"data-export:2:10"})
+	for _, jobType := range []string{"data-export", "content-processing", "summary-generation"} {
+		if got := rps.For(jobType).MaxBackoff; got != 30*time.Second {
+			t.Fatalf("%s: expected a 30s cap, got %s", jobType, got)
+		}
+	}
+	if got := NewRetryPolicies(RetryPolicy{}, nil).Default.MaxBackoff; got != maxRetryBackoff {
+		t.Fatalf("expected the default cap, got %s", got)
+	}
+}
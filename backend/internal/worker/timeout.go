@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultJobTimeout bounds one attempt of a job when no timeout is
+// configured.
+const defaultJobTimeout = 10 * time.Minute
+
+// jobLockMargin is how long a worker's job lock outlives the attempt's
+// deadline, covering the failure handling that runs after it. Deadlines are
+// kept this far below the stuck-job threshold, so the stuck sweep never
+// requeues a job whose attempt may still be running.
+const jobLockMargin = time.Minute
+
+// JobTimeouts holds the deadline for one attempt of each job type, falling
+// back to Default. The deadline covers every Gemini call, file upload and
+// rate-slot wait the attempt makes.
+type JobTimeouts struct {
+	Default time.Duration
+	ByType  map[string]time.Duration
+}
+
+// For returns the attempt deadline for a job type.
+func (jt JobTimeouts) For(jobType string) time.Duration {
+	if d, ok := jt.ByType[jobType]; ok {
+		return d
+	}
+	if jt.Default <= 0 {
+		return defaultJobTimeout
+	}
+	return jt.Default
+}
+
+// NewJobTimeouts builds per-type deadlines from a default and override
+// entries of the form "type:seconds". Content processing may transcribe
+// audio in several parts and presentations render slide images, so both get
+// half again the default unless overridden. Malformed entries and unknown
+// types are logged and skipped.
+//
+// With a stuck-job threshold set, every deadline is cut to end jobLockMargin
+// before it; configured values that are cut are logged.
+func NewJobTimeouts(def time.Duration, overrides []string, stuckThreshold time.Duration) JobTimeouts {
+	if def <= 0 {
+		def = defaultJobTimeout
+	}
+	limit := time.Duration(0)
+	if stuckThreshold > 0 {
+		limit = stuckThreshold - jobLockMargin
+		if limit <= 0 {
+			limit = stuckThreshold / 2
+		}
+	}
+	capped := func(d time.Duration, what string) time.Duration {
+		if limit > 0 && d > limit {
+			if what != "" {
+				log.Printf("%s of %s is not below the stuck job threshold of %s; using %s", what, d, stuckThreshold, limit)
+			}
+			return limit
+		}
+		return d
+	}
+	def = capped(def, "job timeout")
+
+	jt := JobTimeouts{
+		Default: def,
+		ByType: map[string]time.Duration{
+			"content-processing": capped(def*3/2, ""),
+			"presentation":       capped(def*3/2, ""),
+		},
+	}
+
+	for _, entry := range overrides {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 {
+			log.Printf("ignoring malformed job timeout %q", entry)
+			continue
+		}
+		jobType := strings.TrimSpace(parts[0])
+		if !isKnownJobType(jobType) {
+			log.Printf("ignoring job timeout for unknown job type %q", entry)
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || seconds < 1 {
+			log.Printf("ignoring malformed job timeout %q", entry)
+			continue
+		}
+		jt.ByType[jobType] = capped(time.Duration(seconds)*time.Second, jobType+" job timeout")
+	}
+
+	return jt
+}
+
+// WithJobTimeouts sets the deadline each job attempt runs under. Build it
+// with the pool's stuck-job threshold so the two agree.
+func (p *Pool) WithJobTimeouts(timeouts JobTimeouts) *Pool {
+	p.jobTimeouts = timeouts
+	return p
+}
+
+// jobLockTTL is how long a worker holds a job of this type: its deadline
+// plus jobLockMargin.
+func (jt JobTimeouts) jobLockTTL(jobType string) time.Duration {
+	return jt.For(jobType) + jobLockMargin
+}
+
+// runWithTimeout runs process under a context that expires after timeout.
+// Cancelling it releases any Gemini rate slot the attempt holds or waits
+// for. An attempt cut off by the deadline reports so, which keeps it
+// retryable.
+func runWithTimeout(ctx context.Context, timeout time.Duration, process func(context.Context) error) error {
+	jobCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := process(jobCtx)
+	if err != nil && errors.Is(jobCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("job timed out after %s: %w", timeout, err)
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewJobTimeouts_Overrides(t *testing.T) {
+	jt := NewJobTimeouts(10*time.Minute, []string{
+		"quiz-generation:120",
+		"presentation:1200",
+		"bogus:60",
+		"summary-generation:zero",
+		"broken",
+	}, 0)
+
+	tests := []struct {
+		jobType string
+		want    time.Duration
+	}{
+		{"quiz-generation", 2 * time.Minute},
+		{"presentation", 20 * time.Minute},
+		{"content-processing", 15 * time.Minute},
+		{"summary-generation", 10 * time.Minute},
+		{"bogus", 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := jt.For(tt.jobType); got != tt.want {
+			t.Errorf("For(%q) = %s, want %s", tt.jobType, got, tt.want)
+		}
+	}
+}
+
+func TestNewJobTimeouts_StayBelowStuckThreshold(t *testing.T) {
+	stuck := 15 * time.Minute
+	jt := NewJobTimeouts(10*time.Minute, []string{"quiz-generation:1800", "flashcard-generation:60"}, stuck)
+
+	for _, jobType := range jobTypes {
+		if got := jt.For(jobType); got >= stuck {
+			t.Errorf("For(%q) = %s, want below the stuck threshold %s", jobType, got, stuck)
+		}
+		if got := jt.jobLockTTL(jobType); got > stuck {
+			t.Errorf("jobLockTTL(%q) = %s, want the lock gone by the stuck threshold %s", jobType, got, stuck)
+		}
+	}
+	if got := jt.For("content-processing"); got != 14*time.Minute {
+		t.Errorf("content-processing = %s, want it cut to 14m", got)
+	}
+	if got := jt.For("flashcard-generation"); got != time.Minute {
+		t.Errorf("flashcard-generation = %s, want the configured 1m", got)
+	}
+	if got := jt.jobLockTTL("flashcard-generation"); got != 2*time.Minute {
+		t.Errorf("lock TTL = %s, want the deadline plus a minute", got)
+	}
+
+	if got := NewJobTimeouts(time.Hour, nil, stuck).For("summary-generation"); got != 14*time.Minute {
+		t.Errorf("default above the threshold = %s, want it cut to 14m", got)
+	}
+}
+
+func TestJobTimeouts_ZeroValueUsesDefault(t *testing.T) {
+	if got := (JobTimeouts{}).For("summary-generation"); got != defaultJobTimeout {
+		t.Fatalf("zero JobTimeouts = %s, want %s", got, defaultJobTimeout)
+	}
+}
+
+func TestRunWithTimeout_DeadlineAbortsJobPromptly(t *testing.T) {
+	started := time.Now()
+	err := runWithTimeout(context.Background(), 20*time.Millisecond, func(ctx context.Context) error {
+		// Stands in for a hung Gemini call or a wait for a rate slot.
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("job ran %s past its deadline", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "job timed out after 20ms") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isPermanentJobError(err) {
+		t.Fatal("a timed-out attempt should be retried")
+	}
+}
+
+func TestRunWithTimeout_ShutdownIsNotReportedAsTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := runWithTimeout(ctx, time.Minute, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// jobTypes lists every queue in the order shared workers poll them. BLPOP
+// takes from the first non-empty list, so earlier queues win ties.
+var jobTypes = []string{
+	"content-processing",
+	"summary-generation",
+	"presentation",
+	"quiz-generation",
+	"flashcard-generation",
+	"data-export",
+}
+
+// QueueMinimums is the number of workers reserved for each job type. Reserved
+// workers only poll their own queue, so a burst of slow jobs elsewhere cannot
+// starve it.
+type QueueMinimums map[string]int
+
+// NewQueueMinimums builds reservations from entries of the form
+// "type:workers". Flashcard jobs are short and interactive, so they get one
+// reserved worker unless overridden (use "flashcard-generation:0" to opt
+// out). Malformed entries and unknown types are logged and skipped.
+func NewQueueMinimums(entries []string) QueueMinimums {
+	mins := QueueMinimums{"flashcard-generation": 1}
+
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 {
+			log.Printf("ignoring malformed worker queue minimum %q", entry)
+			continue
+		}
+		jobType := strings.TrimSpace(parts[0])
+		if !isKnownJobType(jobType) {
+			log.Printf("ignoring worker queue minimum for unknown job type %q", entry)
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || n < 0 {
+			log.Printf("ignoring malformed worker queue minimum %q", entry)
+			continue
+		}
+		mins[jobType] = n
+	}
+
+	return mins
+}
+
+func isKnownJobType(jobType string) bool {
+	for _, t := range jobTypes {
+		if t == jobType {
+			return true
+		}
+	}
+	return false
+}
+
+// planWorkers assigns queues to workerCount workers: reserved workers first,
+// each polling only its own queue, then shared workers polling every queue.
+// At least one shared worker is always kept so queues without a reservation
+// are served; workerCount grows if the reservations leave no room for it.
+func planWorkers(workerCount int, mins QueueMinimums) [][]string {
+	reserved := 0
+	for _, t := range jobTypes {
+		reserved += mins[t]
+	}
+	if workerCount < reserved+1 {
+		if workerCount > 0 {
+			log.Printf("worker count %d is below the %d reserved queue workers; starting %d", workerCount, reserved, reserved+1)
+		}
+		workerCount = reserved + 1
+	}
+
+	all := make([]string, len(jobTypes))
+	for i, t := range jobTypes {
+		all[i] = JobQueueName(t)
+	}
+
+	plan := make([][]string, 0, workerCount)
+	for _, t := range jobTypes {
+		for i := 0; i < mins[t]; i++ {
+			plan = append(plan, []string{JobQueueName(t)})
+		}
+	}
+	for len(plan) < workerCount {
+		plan = append(plan, all)
+	}
+	return plan
+}
+
+// QueueLengths reports how many jobs wait in each queue, keyed by queue name.
+// Queues whose length cannot be read are left out.
+func QueueLengths(ctx context.Context, rdb *redis.Client) map[string]int64 {
+	lengths := make(map[string]int64, len(jobTypes))
+	for _, t := range jobTypes {
+		name := JobQueueName(t)
+		n, err := rdb.LLen(ctx, name).Result()
+		if err != nil {
+			continue
+		}
+		lengths[name] = n
+	}
+	return lengths
+}
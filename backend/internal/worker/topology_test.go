@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"testing"
+)
+
+func TestNewQueueMinimums_DefaultsAndOverrides(t *testing.T) {
+	mins := NewQueueMinimums(nil)
+	if mins["flashcard-generation"] != 1 {
+		t.Fatalf("expected one reserved flashcard worker by default, got %v", mins)
+	}
+
+	mins = NewQueueMinimums([]string{
+		"quiz-generation:2",
+		"flashcard-generation:0",
+		"bogus-queue:3",
+		"summary-generation",
+		"presentation:-1",
+	})
+	want := map[string]int{"quiz-generation": 2, "flashcard-generation": 0}
+	for jobType, n := range want {
+		if mins[jobType] != n {
+			t.Fatalf("%s: got %d, want %d", jobType, mins[jobType], n)
+		}
+	}
+	for _, jobType := range []string{"bogus-queue", "summary-generation", "presentation"} {
+		if _, ok := mins[jobType]; ok {
+			t.Fatalf("%s: malformed entry should be skipped, got %v", jobType, mins)
+		}
+	}
+}
+
+func TestPlanWorkers_ReservesQueuesAndKeepsSharedWorkers(t *testing.T) {
+	plan := planWorkers(5, QueueMinimums{"flashcard-generation": 1, "quiz-generation": 1})
+	if len(plan) != 5 {
+		t.Fatalf("expected 5 workers, got %d", len(plan))
+	}
+
+	dedicated := map[string]int{}
+	shared := 0
+	for _, queues := range plan {
+		if len(queues) == 1 {
+			dedicated[queues[0]]++
+		} else if len(queues) == len(jobTypes) {
+			shared++
+		} else {
+			t.Fatalf("unexpected queue set %v", queues)
+		}
+	}
+	if dedicated["queue:flashcard-generation"] != 1 || dedicated["queue:quiz-generation"] != 1 || shared != 3 {
+		t.Fatalf("got dedicated=%v shared=%d, want one each for flashcards and quizzes and 3 shared", dedicated, shared)
+	}
+	if plan[len(plan)-1][0] != "queue:content-processing" {
+		t.Fatalf("shared workers should poll content processing first, got %v", plan[len(plan)-1])
+	}
+}
+
+func TestPlanWorkers_GrowsToKeepOneSharedWorker(t *testing.T) {
+	plan := planWorkers(2, QueueMinimums{"flashcard-generation": 1, "quiz-generation": 1})
+	if len(plan) != 3 {
+		t.Fatalf("expected the pool to grow to 3 workers, got %d", len(plan))
+	}
+	if len(plan[2]) != len(jobTypes) {
+		t.Fatalf("expected the last worker to be shared, got %v", plan[2])
+	}
+
+	if plan := planWorkers(0, nil); len(plan) != 1 {
+		t.Fatalf("expected a single shared worker with no configuration, got %d", len(plan))
+	}
+}
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Soft delete: rows stay recoverable for a grace period before being purged.
+ALTER TABLE summaries
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE quizzes
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE flashcard_decks
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_summaries_deleted_at ON summaries(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_quizzes_deleted_at ON quizzes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_flashcard_decks_deleted_at ON flashcard_decks(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
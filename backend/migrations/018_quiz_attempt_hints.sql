@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Question indexes for which a hint was revealed during the attempt.
+ALTER TABLE quiz_attempts
+    ADD COLUMN IF NOT EXISTS hints_used JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Lazily generated mind-map outline ({title, children}); NULL until first requested
+-- and reset whenever the summary content is regenerated.
+ALTER TABLE summaries
+    ADD COLUMN IF NOT EXISTS outline_json JSONB;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- When the job last moved to 'processing'; used to detect jobs orphaned by a
+-- crashed worker.
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_processing_started_at
+    ON jobs(started_at)
+    WHERE status = 'processing';
+
+COMMIT;
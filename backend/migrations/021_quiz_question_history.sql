@@ -0,0 +1,16 @@
+BEGIN;
+
+-- Questions already generated for a summary, so regenerated quizzes can avoid
+-- repeating them. question_text is the normalized form used for similarity.
+CREATE TABLE IF NOT EXISTS quiz_question_history (
+    summary_id UUID NOT NULL REFERENCES summaries(id) ON DELETE CASCADE,
+    question_hash TEXT NOT NULL,
+    question_text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (summary_id, question_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_question_history_summary_created
+    ON quiz_question_history(summary_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Language detected from the extracted transcript; summaries default to it
+-- when the user does not pick one.
+ALTER TABLE content ADD COLUMN IF NOT EXISTS detected_language TEXT;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- Personal access tokens for scripting against a user's own account. Only a
+-- SHA-256 hash of the token is stored; prefix is the displayable start of it.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scope TEXT NOT NULL CHECK (scope IN ('read', 'write')),
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_created
+    ON api_keys(user_id, created_at DESC);
+
+COMMIT;
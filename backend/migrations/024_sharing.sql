@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Share links let other learners preview a deck or quiz and import a copy.
+-- Imported copies remember where they came from and who wrote the original.
+ALTER TABLE flashcard_decks
+    ADD COLUMN IF NOT EXISTS share_slug TEXT UNIQUE,
+    ADD COLUMN IF NOT EXISTS shared_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS original_author_name TEXT,
+    ADD COLUMN IF NOT EXISTS imported_from_id UUID REFERENCES flashcard_decks(id) ON DELETE SET NULL;
+
+ALTER TABLE quizzes
+    ADD COLUMN IF NOT EXISTS share_slug TEXT UNIQUE,
+    ADD COLUMN IF NOT EXISTS shared_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS original_author_name TEXT,
+    ADD COLUMN IF NOT EXISTS imported_from_id UUID REFERENCES quizzes(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Decks generated straight from a transcript link to their content instead
+-- of a summary.
+ALTER TABLE flashcard_decks
+    ADD COLUMN IF NOT EXISTS content_id UUID REFERENCES content(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_flashcard_decks_content_id ON flashcard_decks(content_id);
+
+COMMIT;
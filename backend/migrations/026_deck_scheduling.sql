@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Per-deck SM-2 parameters. NULL means the built-in defaults.
+ALTER TABLE flashcard_decks
+    ADD COLUMN IF NOT EXISTS scheduling_params JSONB;
+
+COMMIT;
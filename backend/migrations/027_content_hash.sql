@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Fingerprint of the source: a SHA-256 of the uploaded bytes for files, the
+-- video ID and caption language for YouTube. Used to reuse a finished
+-- extraction instead of running it again.
+ALTER TABLE content ADD COLUMN IF NOT EXISTS content_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_content_user_hash
+    ON content (user_id, content_hash)
+    WHERE content_hash IS NOT NULL;
+
+COMMIT;
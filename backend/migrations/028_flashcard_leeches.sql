@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Consecutive failed reviews per card; a card past the deck's leech
+-- threshold is a leech. Suspended cards are left out of reviews and due
+-- counts until the user unsuspends them.
+ALTER TABLE flashcard_cards
+    ADD COLUMN IF NOT EXISTS lapses INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS suspended BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Set when the generated summary missed its length preset and a corrective
+-- pass brought it back into range.
+ALTER TABLE summaries ADD COLUMN IF NOT EXISTS length_corrected BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,39 @@
+BEGIN;
+
+-- Day-by-day study schedules built from a set of the user's summaries.
+-- summary_ids keeps the order the plan was built in (user or AI ordering).
+CREATE TABLE IF NOT EXISTS study_plans (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    summary_ids UUID[] NOT NULL,
+    start_date DATE NOT NULL,
+    target_date DATE NOT NULL,
+    daily_minutes INT NOT NULL,
+    ai_ordered BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_plans_user_created
+    ON study_plans(user_id, created_at DESC);
+
+-- One task on one day of a plan. Summary, quiz and deck references are kept
+-- as plain IDs so deleting a source leaves the plan's history intact.
+CREATE TABLE IF NOT EXISTS study_plan_items (
+    id UUID PRIMARY KEY,
+    plan_id UUID NOT NULL REFERENCES study_plans(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    day DATE NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('summary_review', 'quiz', 'flashcards')),
+    title TEXT NOT NULL,
+    summary_id UUID,
+    quiz_id UUID,
+    deck_id UUID,
+    card_count INT NOT NULL DEFAULT 0,
+    estimated_minutes INT NOT NULL,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_plan_items_plan_position
+    ON study_plan_items(plan_id, position);
+
+COMMIT;
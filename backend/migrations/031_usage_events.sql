@@ -0,0 +1,17 @@
+BEGIN;
+
+-- One row per successful Gemini call made on a user's behalf. Prompt tokens
+-- come from the API's usage metadata when present, otherwise an estimate.
+CREATE TABLE IF NOT EXISTS usage_events (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    operation TEXT NOT NULL,
+    prompt_tokens INT NOT NULL DEFAULT 0,
+    completion_tokens INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_user_created
+    ON usage_events(user_id, created_at);
+
+COMMIT;
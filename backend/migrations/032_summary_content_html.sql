@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Sanitized HTML rendering of the summary body; NULL until first requested
+-- and reset whenever the summary content is regenerated.
+ALTER TABLE summaries
+    ADD COLUMN IF NOT EXISTS content_html TEXT;
+
+COMMIT;
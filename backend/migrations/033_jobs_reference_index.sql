@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Looking up a user's jobs for a summary, quiz, or deck, newest first.
+CREATE INDEX IF NOT EXISTS idx_jobs_user_reference
+    ON jobs(user_id, reference_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+-- Embedded passages of each summary's source text, for semantic search and
+-- focused chat prompts. Vectors need the pgvector extension; where it is not
+-- installed the table is left out and chat sends the whole summary instead.
+-- Installing pgvector later needs this migration re-run (delete its row from
+-- schema_migrations).
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        CREATE EXTENSION IF NOT EXISTS vector;
+
+        CREATE TABLE IF NOT EXISTS summary_chunks (
+            summary_id  UUID NOT NULL REFERENCES summaries(id) ON DELETE CASCADE,
+            chunk_index INT NOT NULL,
+            content     TEXT NOT NULL,
+            embedding   vector(768) NOT NULL,
+            source_hash TEXT NOT NULL,
+            created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            PRIMARY KEY (summary_id, chunk_index)
+        );
+    ELSE
+        RAISE NOTICE 'pgvector is not available; semantic search is disabled';
+    END IF;
+END
+$$;
+
+COMMIT;
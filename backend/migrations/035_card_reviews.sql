@@ -0,0 +1,24 @@
+BEGIN;
+
+-- One row per flashcard rating, with the SM-2 state the card had before it,
+-- so reviews can be charted and the last one undone.
+CREATE TABLE IF NOT EXISTS card_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    card_id UUID NOT NULL REFERENCES flashcard_cards(id) ON DELETE CASCADE,
+    deck_id UUID NOT NULL REFERENCES flashcard_decks(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL,
+    prev_interval_days INTEGER NOT NULL,
+    prev_ease_factor NUMERIC(4,2) NOT NULL,
+    prev_repetitions INTEGER NOT NULL,
+    prev_next_review_at DATE,
+    prev_last_reviewed_at TIMESTAMPTZ,
+    prev_lapses INTEGER NOT NULL,
+    prev_suspended BOOLEAN NOT NULL,
+    next_interval_days INTEGER NOT NULL,
+    reviewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_reviews_deck_reviewed
+    ON card_reviews(deck_id, reviewed_at DESC);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS default_num_questions INTEGER,
+    ADD COLUMN IF NOT EXISTS default_num_cards INTEGER;
+
+COMMIT;
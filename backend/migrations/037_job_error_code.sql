@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Machine-readable reason for failures the UI explains itself, such as
+-- content Gemini refused because of its safety filters.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS error_code VARCHAR(40);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Set when the transcript exceeded the maximum length and the summary was
+-- generated from its beginning; coverage is the percentage that was read.
+ALTER TABLE summaries ADD COLUMN IF NOT EXISTS source_truncated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE summaries ADD COLUMN IF NOT EXISTS source_coverage_percent SMALLINT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Order the questions were shown in on a shuffled attempt, as indices into
+-- the quiz's questions_json. NULL means generation order.
+ALTER TABLE quiz_attempts ADD COLUMN IF NOT EXISTS question_order JSONB;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Earlier bodies of a summary, saved before a regeneration or restore
+-- replaces them so the change can be rolled back. Only the newest few per
+-- summary are kept. Columns are copied from summaries as stored, so
+-- content_raw stays encrypted.
+CREATE TABLE IF NOT EXISTS summary_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    summary_id UUID NOT NULL REFERENCES summaries(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    length_setting VARCHAR(20) NOT NULL,
+    config_json JSONB,
+    content_raw TEXT,
+    cornell_cues TEXT,
+    cornell_notes TEXT,
+    cornell_summary TEXT,
+    word_count INTEGER NOT NULL DEFAULT 0,
+    is_quality_fallback BOOLEAN NOT NULL DEFAULT FALSE,
+    quality_fallback_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (summary_id, version)
+);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Opt-in masking of personal data in transcripts before they are stored or
+-- sent to the model. Names need an extra model pass, so they are a separate
+-- choice.
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS redact_transcripts BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS redact_names BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS retain_original_transcript BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- redacted marks content whose stored transcript went through the pass.
+-- original_transcript keeps the unmasked text (encrypted) only for users who
+-- asked to retain it.
+ALTER TABLE content
+    ADD COLUMN IF NOT EXISTS redacted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS original_transcript TEXT;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Problems users flag on a generated question. question_index points into the
+-- quiz's questions_json; editing the question resolves its open reports.
+CREATE TABLE IF NOT EXISTS question_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    quiz_id UUID NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question_index INTEGER NOT NULL CHECK (question_index >= 0),
+    reason VARCHAR(32) NOT NULL,
+    suggested_fix TEXT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_reports_open
+    ON question_reports(quiz_id, question_index)
+    WHERE resolved_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Essay answers are free text, so they are kept apart from answers_json,
+-- keyed by question index. essay_grades holds the AI score and feedback per
+-- essay once the attempt is submitted.
+ALTER TABLE quiz_attempts
+    ADD COLUMN IF NOT EXISTS essay_answers JSONB,
+    ADD COLUMN IF NOT EXISTS essay_grades JSONB;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Matching questions are answered with one answer index per prompt, keyed by
+-- question index. Fill-in-the-blank answers are text and share essay_answers.
+ALTER TABLE quiz_attempts
+    ADD COLUMN IF NOT EXISTS matching_answers JSONB;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Gemini calls made while processing a job are attributed to it; calls made
+-- directly from a request (chat, explanations) leave job_id NULL.
+ALTER TABLE usage_events
+    ADD COLUMN IF NOT EXISTS job_id UUID REFERENCES jobs(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_usage_events_job
+    ON usage_events(job_id) WHERE job_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Paused study sessions: paused_at marks an open pause and paused_seconds
+-- totals the finished ones, so time away is left out of duration_seconds.
+ALTER TABLE study_sessions ADD COLUMN IF NOT EXISTS paused_at TIMESTAMPTZ;
+ALTER TABLE study_sessions ADD COLUMN IF NOT EXISTS paused_seconds INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Quizzes and decks can be archived from the library like summaries: hidden
+-- from listings without being deleted.
+ALTER TABLE quizzes ADD COLUMN IF NOT EXISTS is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE flashcard_decks ADD COLUMN IF NOT EXISTS is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
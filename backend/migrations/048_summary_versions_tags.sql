@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Regeneration rewrites a summary's tags along with its body, so versions
+-- keep them too. Versions saved before this have NULL tags and leave the
+-- summary's current tags alone when restored.
+ALTER TABLE summary_versions ADD COLUMN IF NOT EXISTS tags TEXT[];
+
+COMMIT;
@@ -166,5 +166,24 @@ describe('ProcessingPage runtime flows', () => {
         })
         expect(mocked.navigate).toHaveBeenCalledWith('/dashboard')
     })
+
+    it('explains failures caused by safety filters', async () => {
+        act(() => {
+            root.render(<ProcessingPage />)
+        })
+        await flush()
+
+        act(() => {
+            mocked.wsOptions.onError({
+                job_id: 'job-123',
+                error_code: 'BLOCKED_BY_SAFETY',
+                error_message: "this content couldn't be processed due to safety filters [prompt blocked: SAFETY]",
+            })
+        })
+        await flush()
+
+        expect(container.textContent).toContain("This content couldn't be processed due to safety filters.")
+        expect(container.textContent).not.toContain('prompt blocked')
+    })
 })
 
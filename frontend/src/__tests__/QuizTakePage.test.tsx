@@ -135,6 +135,31 @@ describe('QuizTakePage quiz options behavior', () => {
         expect(container.textContent).not.toContain('Second questionFinish Quiz')
     })
 
+    it('shows questions in the order the attempt was shuffled into', async () => {
+        mocked.quizzesApi.startAttempt.mockResolvedValue({ attempt_id: 'attempt-1', question_order: [1, 0] })
+        mocked.quizzesApi.get.mockResolvedValue({
+            id: 'quiz-1',
+            title: 'Quiz 1',
+            config: {
+                enable_timer: false,
+                shuffle_questions: true,
+                enable_hints: false,
+            },
+            questions: [
+                { question: 'First question', options: ['A', 'B'] },
+                { question: 'Second question', options: ['C', 'D'] },
+            ],
+        })
+
+        await act(async () => {
+            root.render(<QuizTakePage />)
+        })
+        await flush()
+
+        expect(container.textContent).toContain('Second question')
+        expect(container.textContent).not.toContain('First question')
+    })
+
     it('runs 30s per-question timer countdown when enabled', async () => {
         vi.useFakeTimers()
 
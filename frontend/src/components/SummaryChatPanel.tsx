@@ -91,12 +91,9 @@ export function SummaryChatPanel({
         setInput('')
         setIsLoading(true)
 
-        void api.summaries
-            .createChatHistory(summaryId, { role: 'user', content: trimmed })
-            .catch((err) => console.error('Failed to persist user chat message', err))
-
         try {
-            const { reply, screen_ocr_hint: screenOcrHint } = await api.summaries.chat(summaryId, trimmed, messages)
+            // The server sends its stored conversation with the question and saves the exchange.
+            const { reply, screen_ocr_hint: screenOcrHint } = await api.summaries.chat(summaryId, trimmed)
             if (screenOcrHint && String(screenOcrHint).trim()) {
                 toast.warning(String(screenOcrHint).trim())
             }
@@ -106,9 +103,6 @@ export function SummaryChatPanel({
                 queryClient.setQueryData(['chat-history', summaryId], next)
                 return next
             })
-            void api.summaries
-                .createChatHistory(summaryId, { role: 'assistant', content: reply })
-                .catch((err) => console.error('Failed to persist assistant chat message', err))
         } catch {
             setMessages((prev) => {
                 const fallbackMessage: ChatMessage = {
@@ -111,7 +111,7 @@ async function apiFetch<T>(
                     window.location.href = '/login'
                     throw new ApiError(401, 'Session expired')
                 }
-                throw new ApiError(retry.status, err?.error?.message || 'Request failed', err?.error?.fields)
+                throw new ApiError(retry.status, err?.error?.message || 'Request failed', err?.error?.fields, err?.error?.retry_after_seconds)
             }
             return retry.json()
         } else {
@@ -123,7 +123,7 @@ async function apiFetch<T>(
 
     if (!res.ok) {
         const err = await res.json().catch(() => ({}))
-        throw new ApiError(res.status, err?.error?.message || 'Request failed', err?.error?.fields)
+        throw new ApiError(res.status, err?.error?.message || 'Request failed', err?.error?.fields, err?.error?.retry_after_seconds)
     }
 
     // 204 No Content
@@ -174,11 +174,14 @@ export async function refreshAccessToken(): Promise<string | null> {
 export class ApiError extends Error {
     status: number
     fields?: Record<string, string>
+    /** Set on 429s when the server says how long to wait. */
+    retryAfterSeconds?: number
 
-    constructor(status: number, message: string, fields?: Record<string, string>) {
+    constructor(status: number, message: string, fields?: Record<string, string>, retryAfterSeconds?: number) {
         super(message)
         this.status = status
         this.fields = fields
+        this.retryAfterSeconds = retryAfterSeconds
     }
 }
 
@@ -224,6 +227,21 @@ export interface DashboardStreakResponse {
     last_activity_date?: string
 }
 
+export interface DashboardTodayResponse {
+    /** The user's current date, YYYY-MM-DD. */
+    date: string
+    current_streak: number
+    weekly_goal: {
+        type: DashboardGoalType
+        target: number
+        done: number
+        remaining: number
+    }
+    due_flashcards: number
+    today_study_minutes: number
+    recent: DashboardRecentItemResponse[]
+}
+
 export interface DashboardActivityResponse {
     activity?: number[]
     days?: number[]
@@ -241,7 +259,7 @@ export interface SummaryListItemResponse {
     id: string
     content_id?: string
     title?: string
-    format?: 'cornell' | 'bullets' | 'paragraph' | 'smart' | string
+    format?: 'cornell' | 'bullets' | 'paragraph' | 'smart' | 'mindmap' | string
     source?: string
     source_type?: string
     config?: {
@@ -267,8 +285,49 @@ export interface SummaryListItemResponse {
     quality_fallback_reason?: string
 }
 
+export interface SummarySearchResult {
+    chunk_index: number
+    content: string
+    score: number
+}
+
+export interface SummaryVersion {
+    version: number
+    format: string
+    length_setting: string
+    config: Record<string, unknown>
+    content_raw: string | null
+    cornell_cues: string | null
+    cornell_notes: string | null
+    cornell_summary: string | null
+    /** Null on versions saved before tags were kept; restoring one leaves the current tags. */
+    tags: string[] | null
+    word_count: number
+    is_quality_fallback: boolean
+    quality_fallback_reason?: string
+    created_at: string
+}
+
+/** One node of a summary's mind map; leaves have no children. */
+export interface OutlineNode {
+    title: string
+    children?: OutlineNode[]
+}
+
+export interface RelatedSummary {
+    id: string
+    title: string
+    format: string
+    source: string
+    tags: string[]
+    description?: string | null
+    is_favorite: boolean
+    shared_tags: string[]
+    created_at: string
+}
+
 export interface SummaryDetailResponse extends SummaryListItemResponse {
-    format?: 'cornell' | 'bullets' | 'paragraph' | 'smart' | string
+    format?: 'cornell' | 'bullets' | 'paragraph' | 'smart' | 'mindmap' | string
     length_setting?: string
     content_raw?: string
     content?: string
@@ -283,6 +342,13 @@ export interface SummaryDetailResponse extends SummaryListItemResponse {
     duration?: string
     is_quality_fallback?: boolean
     quality_fallback_reason?: string
+    /** A corrective pass brought the summary into its length preset's word range. */
+    length_corrected?: boolean
+    /** The transcript exceeded the maximum length; only the first source_coverage_percent was summarized. */
+    source_truncated?: boolean
+    source_coverage_percent?: number
+    /** Ready-to-show explanation, present only when source_truncated is set. */
+    truncation_notice?: string
     follow_up_questions?: string[]
 }
 
@@ -301,6 +367,24 @@ export interface GenerateSummaryPayload {
     target_audience: string
     language: string
     extract_screen_text: boolean
+    /** Queue a quiz and/or flashcards once the summary is ready; configs default to the user's settings. */
+    auto_generate?: AutoGenerateOptions
+}
+
+export interface AutoGenerateOptions {
+    quiz?: boolean
+    flashcards?: boolean
+    quiz_config?: Partial<Omit<GenerateQuizPayload, 'summary_id'>>
+    flashcard_config?: Partial<Omit<GenerateFlashcardsPayload, 'summary_id' | 'content_id'>>
+}
+
+/** Payload of the `job_chained` WebSocket event sent for auto-generated jobs. */
+export interface ChainedJobEvent {
+    parent_job_id: string
+    job_id?: string
+    result_id?: string
+    result_type: 'quiz' | 'flashcard' | string
+    skipped_reason?: 'QUOTA_EXCEEDED' | 'API_KEY_REQUIRED' | 'QUOTA_CHECK_FAILED' | 'QUEUE_ERROR' | string
 }
 
 export interface PresentationSlideResponse extends Omit<Slide, 'id' | 'type'> {
@@ -369,6 +453,17 @@ export interface QuizQuestionResponse {
     hint?: string
     difficulty?: 'easy' | 'medium' | 'hard' | string
     topic?: string
+    /** Points a full answer covers; set on essay questions only. */
+    rubric?: string
+    /** Answers a fill_blank question accepts, the expected one first; compared case-insensitively. */
+    accepted_answers?: string[]
+    /** Prompts of a matching question, each with its answer; show the answers shuffled. */
+    pairs?: QuizMatchingPair[]
+}
+
+export interface QuizMatchingPair {
+    prompt: string
+    answer: string
 }
 
 export interface QuizListItemResponse {
@@ -386,6 +481,18 @@ export interface QuizListItemResponse {
     last_attempt_id?: string | null
 }
 
+export interface QuizImportResponse {
+    quiz: QuizListItemResponse
+    imported: number
+    skipped: { row: number; reason: string }[]
+}
+
+export interface FocusArea {
+    id: string
+    label: string
+    description: string
+}
+
 export interface GenerateQuizPayload {
     summary_id: string
     title: string
@@ -397,12 +504,19 @@ export interface GenerateQuizPayload {
     enable_hints: boolean
     topics: string[]
     extract_screen_text: boolean
+    /** Avoid repeating questions from earlier quizzes on this summary. */
+    fresh?: boolean
+    /** Exact question count per difficulty; overrides `difficulty` and must add up to `num_questions`. */
+    difficulty_mix?: Partial<Record<'easy' | 'medium' | 'hard', number>>
+    /** Grade essay answers with AI on submit; otherwise essays are left ungraded. */
+    ai_grading?: boolean
 }
 
 export interface FlashcardDeckListItemResponse {
     id: string
     user_id?: string
     summary_id?: string | null
+    content_id?: string | null
     title?: string
     config?: Record<string, unknown> | string
     card_count?: number
@@ -427,6 +541,13 @@ export interface LibraryListResponse {
     total?: number
 }
 
+/** Result of a bulk library action; skipped items were not the caller's or already in that state. */
+export interface LibraryBulkResponse {
+    affected: number
+    skipped: number
+    skipped_ids: string[]
+}
+
 export interface UserProfileResponse {
     id: string
     email: string
@@ -465,15 +586,70 @@ export interface NotificationPreferencesResponse {
     processing_complete: boolean
     weekly_digest: boolean
     study_reminders: boolean
+    daily_brief: boolean
 }
 
 export interface UpdateNotificationPreferencePayload {
-    key: 'processing_complete' | 'weekly_digest' | 'study_reminders'
+    key: 'processing_complete' | 'weekly_digest' | 'study_reminders' | 'daily_brief'
     enabled: boolean
 }
 
+export interface AccountSummary {
+    summaries: number
+    quizzes: number
+    flashcard_decks: number
+    flashcard_cards: number
+    study_hours: number
+    account_created_at: string
+    account_age_days: number
+    plan: string
+    storage_bytes: number
+    is_verified: boolean
+}
+
+/** When scheduled emails may be sent; quiet hours are local hours [start, end). */
+export interface NotificationSchedule {
+    timezone: string
+    quiet_hours_start: number | null
+    quiet_hours_end: number | null
+}
+
+export interface DeckSchedulingParams {
+    first_interval_days: number
+    second_interval_days: number
+    interval_modifier: number
+    max_interval_days: number
+    /** Failed reviews in a row that make a card a leech. */
+    leech_threshold: number
+    /** Suspend cards automatically when they become leeches. */
+    auto_suspend_leeches?: boolean
+}
+
+export interface UpdateDeckSchedulePayload extends Partial<DeckSchedulingParams> {
+    /** Recompute due dates of cards that were already reviewed. */
+    reschedule_existing?: boolean
+    /** Only report the changes; nothing is saved. */
+    dry_run?: boolean
+}
+
+export interface DeckScheduleResponse {
+    scheduling_params: DeckSchedulingParams
+    dry_run: boolean
+    affected_cards: number
+    changes: {
+        card_id: string
+        front: string
+        old_interval_days: number
+        new_interval_days: number
+        old_next_review_at: string
+        new_next_review_at: string
+    }[]
+}
+
 export interface GenerateFlashcardsPayload {
-    summary_id: string
+    /** Exactly one of summary_id and content_id; content_id uses the raw transcript. */
+    summary_id?: string
+    content_id?: string
     title: string
     num_cards: number
     strategy: 'term_definition' | 'question_answer'
@@ -491,13 +667,50 @@ export interface YouTubeValidationMetadata extends Record<string, unknown> {
     thumbnail_url?: string
     duration_seconds?: number
     word_count?: number
+    chapters?: VideoChapter[]
+}
+
+export interface VideoChapter {
+    title: string
+    start_seconds: number
 }
 
 export interface ValidateYouTubeResponse {
     valid: boolean
     metadata: YouTubeValidationMetadata
     content_id: string
+    /** The transcript extraction job; absent when deduplicated. */
+    job_id?: string
     video_id?: string
+    /** True when an already processed copy was reused instead of a new one. */
+    deduplicated?: boolean
+}
+
+export interface PlaylistVideoResult {
+    video_id: string
+    title: string
+    content_id: string
+    /** The transcript extraction job; absent when deduplicated. */
+    job_id?: string
+    deduplicated: boolean
+}
+
+export interface PlaylistWarning {
+    video_id: string
+    title?: string
+    /** 'unavailable' for private or deleted videos, 'playlist_limit' past max_videos, or 'queue_unavailable' / 'import_failed'. */
+    reason: string
+}
+
+export interface ValidatePlaylistResponse {
+    playlist_id: string
+    title: string
+    content_ids: string[]
+    videos: PlaylistVideoResult[]
+    warnings: PlaylistWarning[]
+    /** True when the playlist had more videos than max_videos. */
+    truncated: boolean
+    max_videos: number
 }
 
 export interface ContentResponse {
@@ -512,6 +725,14 @@ export interface ContentResponse {
     transcript?: string | null
     metadata?: Record<string, unknown> | null
     created_at?: string
+    /** ISO 639-1 code detected from the transcript; summaries default to it. */
+    detected_language?: string
+    /** True when personal data was masked in the stored transcript. */
+    redacted?: boolean
+    /** Set when processing failed for a known reason, e.g. 'blocked_by_safety'. */
+    error_code?: string
+    /** A YouTube video's chapters, in order; absent when it has none. */
+    chapters?: VideoChapter[]
 }
 
 export interface QuizDetailResponse extends QuizListItemResponse {
@@ -529,15 +750,33 @@ export interface QuizAttemptDataResponse {
     started_at?: string
     completed_at?: string | null
     time_taken_seconds?: number | null
+    /** Indices into the quiz's questions in the order this attempt shows them; absent means generation order. */
+    question_order?: number[]
+    /** Essay and fill_blank answers keyed by question index. */
+    essay_answers?: Record<string, string>
+    /** Essay grades keyed by question index. */
+    essay_grades?: Record<string, EssayGradeResponse>
+    /** Matching answers keyed by question index; see QuizSaveProgressPayload.matches. */
+    matching_answers?: Record<string, number[]>
+}
+
+export interface EssayGradeResponse {
+    score: number
+    feedback?: string
+    /** False when AI grading was off or failed; the essay then doesn't count toward the score. */
+    graded: boolean
 }
 
 export interface QuizAttemptEnvelopeResponse {
     attempt?: QuizAttemptDataResponse
     attempt_id?: string
     started_at?: string
+    resumed?: boolean
+    question_order?: number[] | null
     score_percent?: number
     correct_count?: number
     total?: number
+    essay_grades?: Record<string, EssayGradeResponse>
 }
 
 export interface QuizAttemptDetailsResponse extends QuizAttemptDataResponse {
@@ -547,11 +786,64 @@ export interface QuizAttemptDetailsResponse extends QuizAttemptDataResponse {
     title?: string
     question_count?: number
     last_attempt_id?: string | null
+    /** Positions in questions that have unresolved reports. */
+    reported_questions?: number[]
+}
+
+/** Score, correct count and time taken are null until the attempt is submitted. */
+export interface QuizAttemptSummaryResponse {
+    id: string
+    started_at: string
+    completed_at: string | null
+    score_percent: number | null
+    correct_count: number | null
+    time_taken_seconds: number | null
+}
+
+export interface QuizQuestionStatResponse {
+    /** In generation order. */
+    question_index: number
+    question: string
+    type: string
+    attempts: number
+    correct: number
+    /** Percent of attempts that answered the question correctly. */
+    correct_rate: number
+}
+
+export interface QuizQuestionStatsResponse {
+    quiz_id: string
+    attempts: number
+    /** Submitted attempts taken on an earlier set of questions. */
+    skipped_attempts: number
+    questions: QuizQuestionStatResponse[]
+}
+
+export type QuestionReportReason = 'incorrect_answer' | 'ambiguous' | 'typo' | 'off_topic' | 'other'
+
+export interface QuestionReportResponse {
+    id: string
+    quiz_id: string
+    question_index: number
+    reason: QuestionReportReason
+    suggested_fix?: string
+    created_at: string
+}
+
+export interface UpdateQuizQuestionResponse {
+    quiz_id: string
+    question_index: number
+    question: QuizQuestionResponse
+    regraded_attempts: number
 }
 
 export interface QuizSaveProgressPayload {
     question_index: number
-    answer_index: number
+    answer_index?: number
+    /** Text answer to an essay or fill_blank question, sent instead of answer_index. */
+    answer_text?: string
+    /** For a matching question: per prompt, the index in pairs of the answer matched to it, or -1. */
+    matches?: number[]
 }
 
 export interface FlashcardDeckStatsResponse {
@@ -561,6 +853,114 @@ export interface FlashcardDeckStatsResponse {
     new?: number
     due_today?: number
     mastery_rate?: number
+    leeches?: number
+    suspended?: number
+}
+
+export interface CardReviewResponse {
+    id: string
+    card_id: string
+    rating: number
+    prev_interval_days: number
+    next_interval_days: number
+    reviewed_at: string
+}
+
+export interface DeckReviewHistoryResponse {
+    deck_id: string
+    days: number
+    total_reviews: number
+    accuracy: number
+    daily: { date: string; reviews: number; correct: number; accuracy: number }[]
+    recent: CardReviewResponse[]
+}
+
+/** A card due for review in the cross-deck queue. */
+export interface DueCardResponse {
+    id: string
+    deck_id: string
+    deck_title: string
+    front: string
+    back: string
+    mnemonic: string | null
+    example: string | null
+    topic: string
+    difficulty: number
+    interval_days: number
+    ease_factor: number
+    repetitions: number
+    next_review_at: string
+    last_reviewed_at: string | null
+    lapses: number
+    is_leech: boolean
+    suspended: boolean
+}
+
+export interface ReviewQueueResponse {
+    /** Most overdue first. */
+    cards: DueCardResponse[]
+    total_due: number
+}
+
+export interface ShareLinkResponse {
+    share_slug: string
+    /** App-relative path of the public preview, e.g. "/shared/decks/ab12cd34ef56ab78". */
+    path: string
+    shared_at: string
+}
+
+export interface SharedDeckResponse {
+    title: string
+    author_name: string
+    card_count: number
+    shared_at: string
+    cards: { front: string; back: string; mnemonic?: string; example?: string; topic: string; difficulty: number }[]
+}
+
+export interface SharedQuizResponse {
+    title: string
+    author_name: string
+    question_count: number
+    shared_at: string
+    questions: { question: string; type: string; options: string[] }[]
+}
+
+export interface APIKeyResponse {
+    id: string
+    name: string
+    /** Visible start of the token, e.g. "lk_3f9a1c0e". */
+    prefix: string
+    scope: 'read' | 'write'
+    last_used_at: string | null
+    created_at: string
+}
+
+/** A signed-in device, identified by the browser and address it signed in from. */
+export interface SessionResponse {
+    id: string
+    user_agent: string
+    ip: string
+    created_at: string
+    last_used_at: string
+}
+
+export interface AIUsageOperation {
+    operation: 'summary' | 'quiz' | 'flashcard' | 'presentation' | 'chat' | 'transcription'
+    requests: number
+    prompt_tokens: number
+    completion_tokens: number
+    total_tokens: number
+}
+
+/** Gemini usage for the current calendar month (UTC). */
+export interface AIUsageReport {
+    period_start: string
+    period_end: string
+    total_requests: number
+    total_prompt_tokens: number
+    total_completion_tokens: number
+    total_tokens: number
+    operations: AIUsageOperation[]
 }
 
 export interface UserSettingsResponse {
@@ -569,14 +969,21 @@ export interface UserSettingsResponse {
     default_format?: string
     default_difficulty?: string
     language?: string
+    default_num_questions?: number | null
+    default_num_cards?: number | null
     notifications?: Record<string, unknown>
     notifications_json?: Record<string, unknown>
+    redact_transcripts?: boolean
+    redact_names?: boolean
+    retain_original_transcript?: boolean
     updated_at?: string
 }
 
 export type UpdateUserSettingsPayload = Partial<Pick<
     UserSettingsResponse,
     'default_summary_length' | 'default_format' | 'default_difficulty' | 'language'
+    | 'default_num_questions' | 'default_num_cards'
+    | 'redact_transcripts' | 'redact_names' | 'retain_original_transcript'
 >> & {
     notifications?: Record<string, unknown>
     notifications_json?: Record<string, unknown>
@@ -592,10 +999,58 @@ export interface JobResponse {
     retry_count?: number
     max_retries?: number
     error_message?: string | null
+    /** Set for failures with a known cause, e.g. 'blocked_by_safety'. */
+    error_code?: string | null
     created_at?: string
     completed_at?: string | null
 }
 
+/** Polling view of a job's progress, for clients without a WebSocket. */
+export interface JobProgressResponse {
+    job_id: string
+    status: JobResponse['status']
+    step: number
+    step_name: string
+    percent: number
+    estimated_seconds_remaining: number
+}
+
+export interface StudyPlanItemResponse {
+    id: string
+    plan_id: string
+    day: string
+    kind: 'summary_review' | 'quiz' | 'flashcards'
+    title: string
+    summary_id?: string
+    /** Latest quiz on the summary; absent when one still has to be generated. */
+    quiz_id?: string
+    deck_id?: string
+    card_count?: number
+    estimated_minutes: number
+    completed_at: string | null
+}
+
+export interface StudyPlanResponse {
+    id: string
+    user_id: string
+    summary_ids: string[]
+    start_date: string
+    target_date: string
+    daily_minutes: number
+    ai_ordered: boolean
+    items: StudyPlanItemResponse[]
+    completed_items: number
+    created_at: string
+}
+
+export interface GenerateStudyPlanPayload {
+    summary_ids: string[]
+    /** YYYY-MM-DD, the last day of the plan. */
+    target_date: string
+    daily_minutes?: number
+    ai_ordering?: boolean
+}
+
 // ─── API Methods ───
 export const api = {
     // Auth
@@ -618,15 +1073,32 @@ export const api = {
                 body: JSON.stringify({}),
             }),
 
+        /** Signs out every device, including this one. */
+        logoutAll: () => apiFetch<{ message: string }>('/auth/logout-all', { method: 'POST' }),
+
         verifyEmail: (token: string) =>
             apiFetch<{ access_token: string; expires_in: number }>(`/auth/verify-email?token=${token}`),
 
+        /** retry_after_seconds is how long to wait before the next resend. */
         resendVerification: (email: string) =>
-            apiFetch('/auth/resend-verification', {
+            apiFetch<{ message: string; retry_after_seconds: number }>('/auth/resend-verification', {
+                method: 'POST',
+                body: JSON.stringify({ email }),
+            }),
+
+        /** Always succeeds for a well-formed email so accounts cannot be probed. */
+        forgotPassword: (email: string) =>
+            apiFetch<{ message: string; retry_after_seconds: number }>('/auth/forgot-password', {
                 method: 'POST',
                 body: JSON.stringify({ email }),
             }),
 
+        resetPassword: (token: string, password: string) =>
+            apiFetch<{ message: string }>('/auth/reset-password', {
+                method: 'POST',
+                body: JSON.stringify({ token, password }),
+            }),
+
         googleLogin: (idToken: string) =>
             apiFetch<{ access_token: string; expires_in: number }>('/auth/google', {
                 method: 'POST',
@@ -646,16 +1118,25 @@ export const api = {
 
     // Content
     content: {
-        validateYouTube: (url: string) =>
-            apiFetch<ValidateYouTubeResponse>('/content/validate-youtube', {
+        /** Pass force to process again even if the same video was already processed. */
+        validateYouTube: (url: string, captionLanguage?: string, force = false) =>
+            apiFetch<ValidateYouTubeResponse>(`/content/validate-youtube${force ? '?force=true' : ''}`, {
                 method: 'POST',
-                body: JSON.stringify({ url }),
+                body: JSON.stringify({ url, caption_language: captionLanguage }),
             }),
 
-        upload: (file: File) => {
+        /** Imports every video of a playlist as its own content, up to the server's limit. */
+        validatePlaylist: (url: string, captionLanguage?: string, force = false) =>
+            apiFetch<ValidatePlaylistResponse>(`/content/validate-playlist${force ? '?force=true' : ''}`, {
+                method: 'POST',
+                body: JSON.stringify({ url, caption_language: captionLanguage }),
+            }),
+
+        /** Pass force to process again even if an identical file was already processed. */
+        upload: (file: File, force = false) => {
             const formData = new FormData()
             formData.append('file', file)
-            return apiFetch<{ content_id: string; filename: string; mime_type: string }>('/content/upload', {
+            return apiFetch<{ content_id: string; filename: string; mime_type: string; job_id?: string; deduplicated?: boolean }>(`/content/upload${force ? '?force=true' : ''}`, {
                 method: 'POST',
                 body: formData,
             })
@@ -663,6 +1144,12 @@ export const api = {
 
         get: (id: string) => apiFetch<ContentResponse>(`/content/${id}`),
 
+        /** Short-lived link to the original upload that works without auth headers. */
+        downloadUrl: async (id: string) => {
+            const res = await apiFetch<{ path: string; expires_at: string }>(`/content/${id}/download-url`)
+            return { url: `${API_BASE}${res.path}`, expiresAt: res.expires_at }
+        },
+
         supportedFormats: () => apiFetch<{ formats: string[] }>('/content/supported-formats'),
     },
 
@@ -679,6 +1166,9 @@ export const api = {
             return apiFetch<{ summaries: SummaryListItemResponse[]; total: number }>(`/summaries${qs}`)
         },
 
+        focusAreas: () =>
+            apiFetch<{ focus_areas: FocusArea[] }>('/summaries/focus-areas'),
+
         get: (id: string) => apiFetch<SummaryDetailResponse>(`/summaries/${id}`),
 
         update: (id: string, data: { title?: string; tags?: string[] }) =>
@@ -699,14 +1189,60 @@ export const api = {
                 body: JSON.stringify(data || {}),
             }),
 
-        chat: (id: string, message: string, history: { role: string; content: string }[]) =>
-            apiFetch<{ reply: string; screen_ocr_hint?: string | null }>(`/summaries/${id}/chat`, {
+        /** Server-rendered, sanitized HTML of the summary body (tables and Cornell sections included). */
+        getHtml: (id: string) =>
+            apiFetch<{ summary_id: string; html: string }>(`/summaries/${id}/html`),
+
+        /** Semantic search over the summary's source transcript; results are ranked by score. */
+        search: (id: string, q: string, limit?: number) => {
+            const params = new URLSearchParams({ q })
+            if (limit) params.set('limit', String(limit))
+            return apiFetch<{ summary_id: string; query: string; results: SummarySearchResult[] }>(
+                `/summaries/${id}/search?${params.toString()}`,
+            )
+        },
+
+        /** The caller's other summaries sharing the most tags with this one. */
+        related: (id: string, limit?: number) =>
+            apiFetch<{ summary_id: string; summaries: RelatedSummary[] }>(
+                `/summaries/${id}/related${limit ? `?limit=${limit}` : ''}`,
+            ),
+
+        /** The summary as a mind-map tree. Mindmap-format summaries have it from generation; others build it on first request. */
+        outline: (id: string) =>
+            apiFetch<{ summary_id: string; outline: OutlineNode }>(`/summaries/${id}/outline`),
+
+        /** Earlier bodies of the summary saved before each regeneration, newest first. */
+        versions: (id: string) =>
+            apiFetch<{ summary_id: string; versions: SummaryVersion[] }>(`/summaries/${id}/versions`),
+
+        /** Restores an earlier version; the current body is kept as a new version. */
+        restoreVersion: (id: string, version: number) =>
+            apiFetch<SummaryDetailResponse>(`/summaries/${id}/versions/${version}/restore`, { method: 'POST' }),
+
+        /** Regenerates only the table of a smart summary; returns the updated summary. */
+        rebuildTable: (id: string) =>
+            apiFetch<SummaryDetailResponse>(`/summaries/${id}/rebuild-table`, { method: 'POST' }),
+
+        /** Explains a highlighted excerpt of the summary in context. */
+        explain: (id: string, selection: string) =>
+            apiFetch<{ explanation: string }>(`/summaries/${id}/explain`, {
+                method: 'POST',
+                body: JSON.stringify({ selection }),
+            }),
+
+        /** The server keeps the conversation, so only the new message is sent. */
+        chat: (id: string, message: string) =>
+            apiFetch<{ reply: string; screen_ocr_hint?: string | null; suggestions: string[] }>(`/summaries/${id}/chat`, {
                 method: 'POST',
-                body: JSON.stringify({ message, history }),
+                body: JSON.stringify({ message }),
             }),
 
+        getChatSuggestions: (id: string) =>
+            apiFetch<{ suggestions: string[] }>(`/summaries/${id}/chat/suggestions`),
+
         getChatHistory: (id: string) =>
-            apiFetch<ChatHistoryMessageResponse[]>(`/summaries/${id}/chat-history`),
+            apiFetch<ChatHistoryMessageResponse[]>(`/summaries/${id}/chat`),
 
         createChatHistory: (id: string, data: { role: 'user' | 'assistant'; content: string }) =>
             apiFetch<ChatHistoryMessageResponse>(`/summaries/${id}/chat-history`, {
@@ -764,18 +1300,36 @@ export const api = {
                 body: JSON.stringify(data),
             }),
 
-        list: () => apiFetch<{ quizzes: QuizListItemResponse[] }>('/quizzes'),
+        list: (params?: Record<string, string>) => {
+            const qs = params ? '?' + new URLSearchParams(params).toString() : ''
+            return apiFetch<{ quizzes: QuizListItemResponse[]; total?: number; limit?: number; offset?: number }>(`/quizzes${qs}`)
+        },
 
         toggleFavorite: (id: string) =>
             apiFetch<{ message: string }>(`/quizzes/${id}/favorite`, { method: 'PUT' }),
 
         get: (id: string) => apiFetch<QuizDetailResponse>(`/quizzes/${id}`),
 
+        /** Creates a quiz from a CSV in the export format; malformed rows come back in skipped. */
+        importCsv: (file: File, title?: string) => {
+            const formData = new FormData()
+            formData.append('file', file)
+            if (title) formData.append('title', title)
+            return apiFetch<QuizImportResponse>('/quizzes/import', {
+                method: 'POST',
+                body: formData,
+            })
+        },
+
         delete: (id: string) =>
             apiFetch<{ message: string }>(`/quizzes/${id}`, { method: 'DELETE' }),
 
-        startAttempt: (quizId: string) =>
-            apiFetch<QuizAttemptEnvelopeResponse>(`/quizzes/${quizId}/start`, { method: 'POST' }),
+        startAttempt: (quizId: string, options?: { resume?: boolean }) =>
+            apiFetch<QuizAttemptEnvelopeResponse>(`/quizzes/${quizId}/start${options?.resume ? '?resume=true' : ''}`, { method: 'POST' }),
+
+        /** Resolves to {} (204) when there is no recent unfinished attempt. */
+        getActiveAttempt: (quizId: string) =>
+            apiFetch<{ attempt?: QuizAttemptDataResponse }>(`/quizzes/${quizId}/active-attempt`),
 
         saveProgress: (attemptId: string, data: QuizSaveProgressPayload) =>
             apiFetch<{ message?: string }>(`/quiz-attempts/${attemptId}/save-progress`, {
@@ -790,6 +1344,30 @@ export const api = {
 
         getAttempt: (attemptId: string) =>
             apiFetch<QuizAttemptDetailsResponse>(`/quiz-attempts/${attemptId}`),
+
+        /** The caller's attempts on the quiz, oldest first. */
+        listAttempts: (quizId: string) =>
+            apiFetch<{ quiz_id: string; attempts: QuizAttemptSummaryResponse[] }>(`/quizzes/${quizId}/attempts`),
+
+        questionStats: (quizId: string) =>
+            apiFetch<QuizQuestionStatsResponse>(`/quizzes/${quizId}/question-stats`),
+
+        /** questionIndex is in generation order, not an attempt's shuffled order. */
+        reportQuestion: (quizId: string, questionIndex: number, data: { reason: QuestionReportReason; suggested_fix?: string }) =>
+            apiFetch<QuestionReportResponse>(`/quizzes/${quizId}/questions/${questionIndex}/report`, {
+                method: 'POST',
+                body: JSON.stringify(data),
+            }),
+
+        /** Replaces one question (same type) and regrades completed attempts. */
+        updateQuestion: (quizId: string, questionIndex: number, question: QuizQuestionResponse) =>
+            apiFetch<UpdateQuizQuestionResponse>(`/quizzes/${quizId}/questions/${questionIndex}`, {
+                method: 'PUT',
+                body: JSON.stringify(question),
+            }),
+
+        share: (id: string) => apiFetch<ShareLinkResponse>(`/quizzes/${id}/share`, { method: 'POST' }),
+        unshare: (id: string) => apiFetch(`/quizzes/${id}/share`, { method: 'DELETE' }),
     },
 
     // Flashcards
@@ -800,12 +1378,33 @@ export const api = {
                 body: JSON.stringify(data),
             }),
 
-        listDecks: () => apiFetch<{ decks: FlashcardDeckListItemResponse[] }>('/flashcards/decks'),
+        listDecks: (params?: Record<string, string>) => {
+            const qs = params ? '?' + new URLSearchParams(params).toString() : ''
+            return apiFetch<{ decks: FlashcardDeckListItemResponse[]; total?: number; limit?: number; offset?: number }>(`/flashcards/decks${qs}`)
+        },
 
-        getDeck: (id: string) => apiFetch<{ deck?: FlashcardDeckListItemResponse; cards?: unknown[] }>(`/flashcards/decks/${id}`),
+        /** Suspended cards are left out unless includeSuspended is set. */
+        getDeck: (id: string, includeSuspended = false) =>
+            apiFetch<{ deck?: FlashcardDeckListItemResponse; cards?: unknown[] }>(`/flashcards/decks/${id}${includeSuspended ? '?include_suspended=true' : ''}`),
 
         getDeckStats: (id: string) => apiFetch<FlashcardDeckStatsResponse>(`/flashcards/decks/${id}/stats`),
 
+        /** Reviews per day and accuracy over the last `days` days (30 by default). */
+        getDeckHistory: (id: string, days?: number) =>
+            apiFetch<DeckReviewHistoryResponse>(`/flashcards/decks/${id}/history${days ? `?days=${days}` : ''}`),
+
+        /** Cards whose lapse streak reached the deck's leech threshold, most lapses first, suspended ones included. */
+        getDeckLeeches: (id: string) =>
+            apiFetch<{ deck_id: string; cards: unknown[] }>(`/flashcards/decks/${id}/leeches`),
+
+        /** Reverts the deck's most recent rating and returns the restored card. */
+        undoLastReview: (id: string) =>
+            apiFetch<{ undone_review: CardReviewResponse; card: unknown }>(`/flashcards/decks/${id}/undo-review`, { method: 'POST' }),
+
+        /** Cards due for review across all decks, most overdue first (limit defaults to 50, max 200). */
+        reviewQueue: (limit?: number) =>
+            apiFetch<ReviewQueueResponse>(`/flashcards/review${limit ? `?limit=${limit}` : ''}`),
+
         toggleFavorite: (id: string) =>
             apiFetch<{ message: string }>(`/flashcards/decks/${id}/favorite`, { method: 'PUT' }),
 
@@ -817,6 +1416,31 @@ export const api = {
                 method: 'POST',
                 body: JSON.stringify({ rating }),
             }),
+
+        suspendCard: (cardId: string) =>
+            apiFetch(`/flashcards/cards/${cardId}/suspend`, { method: 'POST' }),
+
+        unsuspendCard: (cardId: string) =>
+            apiFetch(`/flashcards/cards/${cardId}/unsuspend`, { method: 'POST' }),
+
+        updateSchedule: (id: string, data: UpdateDeckSchedulePayload) =>
+            apiFetch<DeckScheduleResponse>(`/flashcards/decks/${id}/schedule`, {
+                method: 'PUT',
+                body: JSON.stringify(data),
+            }),
+
+        shareDeck: (id: string) => apiFetch<ShareLinkResponse>(`/flashcards/decks/${id}/share`, { method: 'POST' }),
+        unshareDeck: (id: string) => apiFetch(`/flashcards/decks/${id}/share`, { method: 'DELETE' }),
+    },
+
+    // Shared decks & quizzes (previews are public; importing needs an account)
+    shared: {
+        deck: (slug: string) => apiFetch<SharedDeckResponse>(`/shared/decks/${slug}`),
+        importDeck: (slug: string) =>
+            apiFetch<FlashcardDeckListItemResponse>(`/shared/decks/${slug}/import`, { method: 'POST' }),
+        quiz: (slug: string) => apiFetch<SharedQuizResponse>(`/shared/quizzes/${slug}`),
+        importQuiz: (slug: string) =>
+            apiFetch<QuizListItemResponse>(`/shared/quizzes/${slug}/import`, { method: 'POST' }),
     },
 
     // Dashboard
@@ -830,6 +1454,9 @@ export const api = {
         recent: () => apiFetch<DashboardRecentResponse>('/dashboard/recent'),
         streak: () => apiFetch<DashboardStreakResponse>('/dashboard/streak'),
         activity: () => apiFetch<DashboardActivityResponse>('/dashboard/activity'),
+        /** Streak, weekly goal, due cards, today's study minutes and recent items in one snapshot. */
+        today: (tz = Intl.DateTimeFormat().resolvedOptions().timeZone) =>
+            apiFetch<DashboardTodayResponse>(`/dashboard/today${tz ? `?tz=${encodeURIComponent(tz)}` : ''}`),
     },
 
     // Study Sessions
@@ -847,18 +1474,49 @@ export const api = {
             apiFetch<{ message: string }>(`/study-sessions/${sessionId}/heartbeat`, {
                 method: 'POST',
             }),
+        pause: (sessionId: string) =>
+            apiFetch<{ message: string }>(`/study-sessions/${sessionId}/pause`, {
+                method: 'POST',
+            }),
+        resume: (sessionId: string) =>
+            apiFetch<{ message: string }>(`/study-sessions/${sessionId}/resume`, {
+                method: 'POST',
+            }),
         stop: (sessionId: string) =>
             apiFetch<{ message: string }>(`/study-sessions/${sessionId}/stop`, {
                 method: 'POST',
             }),
     },
 
+    // Study Plans
+    studyPlans: {
+        generate: (payload: GenerateStudyPlanPayload) =>
+            apiFetch<StudyPlanResponse>('/study-plan/generate', {
+                method: 'POST',
+                body: JSON.stringify(payload),
+            }),
+        get: (planId: string) => apiFetch<StudyPlanResponse>(`/study-plan/${planId}`),
+        completeItem: (planId: string, itemId: string) =>
+            apiFetch<StudyPlanItemResponse>(`/study-plan/${planId}/items/${itemId}/complete`, {
+                method: 'POST',
+            }),
+        uncompleteItem: (planId: string, itemId: string) =>
+            apiFetch<StudyPlanItemResponse>(`/study-plan/${planId}/items/${itemId}/uncomplete`, {
+                method: 'POST',
+            }),
+    },
+
     // Library
     library: {
         list: (params?: Record<string, string>) => {
             const qs = params ? '?' + new URLSearchParams(params).toString() : ''
             return apiFetch<LibraryListResponse>(`/library${qs}`)
         },
+        bulk: (ids: string[], type: 'summary' | 'quiz' | 'flashcard', action: 'delete' | 'archive' | 'unarchive') =>
+            apiFetch<LibraryBulkResponse>('/library/bulk', {
+                method: 'POST',
+                body: JSON.stringify({ ids, type, action }),
+            }),
     },
 
     // Folders
@@ -891,6 +1549,7 @@ export const api = {
     // User & Settings
     user: {
         getMe: () => apiFetch<UserMeResponse>('/user/me'),
+        getAccountSummary: () => apiFetch<AccountSummary>('/user/account-summary'),
         updateMe: (data: UpdateMePayload) =>
             apiFetch<UserProfileResponse>('/user/me', { method: 'PUT', body: JSON.stringify(data) }),
         changePassword: (data: { current_password: string; new_password: string }) =>
@@ -910,11 +1569,36 @@ export const api = {
                 method: 'PUT',
                 body: JSON.stringify(data),
             }),
+        getNotificationSchedule: () => apiFetch<NotificationSchedule>('/user/notifications/schedule'),
+        updateNotificationSchedule: (data: Partial<NotificationSchedule>) =>
+            apiFetch<NotificationSchedule>('/user/notifications/schedule', {
+                method: 'PUT',
+                body: JSON.stringify(data),
+            }),
+        listApiKeys: () => apiFetch<{ api_keys: APIKeyResponse[] }>('/user/api-keys'),
+        /** The returned token is shown once and cannot be retrieved again. */
+        createApiKey: (data: { name: string; scope?: 'read' | 'write' }) =>
+            apiFetch<{ api_key: APIKeyResponse; token: string }>('/user/api-keys', {
+                method: 'POST',
+                body: JSON.stringify(data),
+            }),
+        deleteApiKey: (id: string) => apiFetch(`/user/api-keys/${id}`, { method: 'DELETE' }),
+        listSessions: () => apiFetch<{ sessions: SessionResponse[] }>('/user/sessions'),
+        revokeSession: (id: string) => apiFetch(`/user/sessions/${id}`, { method: 'DELETE' }),
+        getAIUsage: () => apiFetch<AIUsageReport>('/user/usage/ai'),
     },
 
     // Jobs
     jobs: {
         get: (id: string) => apiFetch<JobResponse>(`/jobs/${id}`),
+        /** Step, percentage and remaining time of a running job. */
+        progress: (id: string) => apiFetch<JobProgressResponse>(`/jobs/${id}/progress`),
+        /** The caller's jobs for a summary, quiz, or deck, newest first. */
+        listByReference: (referenceId: string, type?: string) => {
+            const params: Record<string, string> = { reference_id: referenceId }
+            if (type) params.type = type
+            return apiFetch<{ jobs: JobResponse[] }>('/jobs?' + new URLSearchParams(params).toString())
+        },
         cancel: (id: string) => apiFetch(`/jobs/${id}`, { method: 'DELETE' }),
     },
 
@@ -60,9 +60,15 @@ export function useStudySession({
             }
         }
 
+        // Time with the tab hidden is not study time: pause the session's
+        // clock until the page is visible again.
         const onVisibilityChange = () => {
+            const sessionId = sessionIdRef.current
+            if (!sessionId || stoppedRef.current) return
             if (document.visibilityState === 'visible') {
-                sendHeartbeat()
+                api.studySessions.resume(sessionId).catch(() => { })
+            } else {
+                api.studySessions.pause(sessionId).catch(() => { })
             }
         }
 
@@ -16,6 +16,8 @@ const isJWTAccessTokenPayload = (value: unknown): value is JWTAccessTokenPayload
 
 interface UseWebSocketOptions {
     onStatusUpdate?: (payload: unknown) => void
+    /** Draft summary text streamed while it is generated. */
+    onPartialContent?: (payload: unknown) => void
     onCompleted?: (payload: unknown) => void
     onError?: (payload: unknown) => void
     onMessage?: (msg: WSMessage) => void
@@ -116,6 +118,9 @@ export function useWebSocket(options: UseWebSocketOptions = {}) {
                     case 'status_update':
                         optionsRef.current.onStatusUpdate?.(msg.payload)
                         break
+                    case 'partial_content':
+                        optionsRef.current.onPartialContent?.(msg.payload)
+                        break
                     case 'completed':
                         optionsRef.current.onCompleted?.(msg.payload)
                         break
@@ -43,8 +43,8 @@ export function ContentInputPage() {
   const navigate = useNavigate()
   const toast = useToast()
   const MAX_FILE_SIZE_BYTES = 100 * 1024 * 1024
-  const ACCEPTED_EXTENSIONS = new Set(['.pdf', '.docx', '.txt', '.mp3', '.wav', '.mp4'])
-  const ACCEPT_ATTR = '.pdf,.docx,.txt,.mp3,.wav,.mp4'
+  const ACCEPTED_EXTENSIONS = new Set(['.pdf', '.docx', '.txt', '.srt', '.vtt', '.mp3', '.wav', '.mp4'])
+  const ACCEPT_ATTR = '.pdf,.docx,.txt,.srt,.vtt,.mp3,.wav,.mp4'
 
   const [sourceType, setSourceType] = useState('youtube')
   const [youtubeUrl, setYoutubeUrl] = useState('')
@@ -62,7 +62,7 @@ export function ContentInputPage() {
   ])
   const [outputFormat, setOutputFormat] = useState(() => getStoredSummaryFormatPreference())
   const [targetAudience, setTargetAudience] = useState('academic')
-  const [language, setLanguage] = useState('en')
+  const [language, setLanguage] = useState('auto')
   const [advancedOpen, setAdvancedOpen] = useState(false)
   const [extractScreenText, setExtractScreenText] = useState(true)
   const [isGenerating, setIsGenerating] = useState(false)
@@ -101,13 +101,15 @@ export function ContentInputPage() {
   const validateSelectedFile = (file: File): string | null => {
     const ext = getFileExtension(file.name)
     if (!ACCEPTED_EXTENSIONS.has(ext)) {
-      return 'Unsupported file type. Allowed: PDF, DOCX, TXT, MP3, WAV, MP4.'
+      return 'Unsupported file type. Allowed: PDF, DOCX, TXT, SRT, VTT, MP3, WAV, MP4.'
     }
 
     const expectedMimeByExtension: Record<string, string[]> = {
       '.pdf': ['application/pdf'],
       '.docx': ['application/vnd.openxmlformats-officedocument.wordprocessingml.document'],
       '.txt': ['text/plain'],
+      '.srt': ['application/x-subrip', 'text/plain'],
+      '.vtt': ['text/vtt'],
       '.mp3': ['audio/mpeg', 'audio/mp3'],
       '.wav': ['audio/wav', 'audio/x-wav', 'audio/wave'],
       '.mp4': ['video/mp4'],
@@ -405,7 +407,7 @@ export function ContentInputPage() {
                           : 'Click to upload or drag and drop'}
                       </h3>
                       <p className="text-sm text-muted-foreground mb-4">
-                        PDF, DOCX, TXT, MP3, WAV, or MP4 (max 100MB)
+                        PDF, DOCX, TXT, SRT, VTT, MP3, WAV, or MP4 (max 100MB)
                       </p>
                       <Button
                         variant="outline"
@@ -670,6 +672,7 @@ export function ContentInputPage() {
                           value={language}
                           onChange={(e) => setLanguage(e.target.value)}
                         >
+                          <option value="auto">Auto-detect</option>
                           <option value="en">English</option>
                           <option value="kk">Kazakh</option>
                           <option value="ru">Russian</option>
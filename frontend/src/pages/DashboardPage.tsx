@@ -94,10 +94,9 @@ export function DashboardPage() {
     setIsLoading(true)
     setLoadError(null)
     try {
-      const [stats, recent, streak, activity] = await Promise.all([
+      const [stats, today, activity] = await Promise.all([
         api.dashboard.stats(),
-        api.dashboard.recent(),
-        api.dashboard.streak(),
+        api.dashboard.today(),
         api.dashboard.activity(),
       ])
 
@@ -106,8 +105,8 @@ export function DashboardPage() {
       }
 
       setDashStats(stats)
-      setRecentItems(recent?.recent ?? recent?.items ?? [])
-      setStreakData(streak)
+      setRecentItems(today?.recent ?? [])
+      setStreakData({ current_streak: today?.current_streak })
       setActivityItems(activity?.activity ?? activity?.days ?? [])
       setIsActivityEstimated(Boolean(activity?.estimated))
     } catch (err: unknown) {
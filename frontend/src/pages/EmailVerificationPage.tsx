@@ -115,13 +115,16 @@ export function EmailVerificationPage() {
     setResendError('')
 
     try {
-      await api.auth.resendVerification(email)
+      const res = await api.auth.resendVerification(email)
       localStorage.setItem(RESEND_STORAGE_KEY, email)
-      setCountdown(60)
+      setCountdown(res.retry_after_seconds || 60)
       setResendAttempts((prev) => prev + 1)
       success('Verification email sent again.')
     } catch (err: unknown) {
       const message = err instanceof ApiError ? err.message : 'Failed to resend verification email'
+      if (err instanceof ApiError && err.retryAfterSeconds) {
+        setCountdown(err.retryAfterSeconds)
+      }
       setResendError(message)
       error(message)
     } finally {
@@ -234,7 +237,7 @@ export function EmailVerificationPage() {
             {isResending
               ? 'Sending...'
               : countdown > 0
-                ? `Resend in ${countdown}s`
+                ? `Resend in ${countdown >= 60 ? `${Math.floor(countdown / 60)}:${String(countdown % 60).padStart(2, '0')}` : `${countdown}s`}`
                 : resendAttempts >= MAX_RESEND_ATTEMPTS
                   ? 'Max attempts reached'
                   : `Resend verification email${resendAttempts > 0 ? ` (${MAX_RESEND_ATTEMPTS - resendAttempts} left)` : ''}`}
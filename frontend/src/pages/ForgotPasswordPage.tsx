@@ -0,0 +1,103 @@
+import React, { useEffect, useState } from 'react'
+import { Link } from 'react-router-dom'
+import { api, ApiError } from '../lib/api'
+import { Button } from '../components/ui/Button'
+import { Input } from '../components/ui/Input'
+import { Label } from '../components/ui/Label'
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardFooter,
+  CardHeader,
+  CardTitle,
+} from '../components/ui/Card'
+import { Mail } from 'lucide-react'
+
+export function ForgotPasswordPage() {
+  const [email, setEmail] = useState('')
+  const [emailError, setEmailError] = useState('')
+  const [isSending, setIsSending] = useState(false)
+  const [sent, setSent] = useState(false)
+  const [countdown, setCountdown] = useState(0)
+
+  useEffect(() => {
+    if (countdown <= 0) return
+
+    const timeout = window.setTimeout(() => {
+      setCountdown((prev) => Math.max(0, prev - 1))
+    }, 1000)
+
+    return () => window.clearTimeout(timeout)
+  }, [countdown])
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    const trimmed = email.trim().toLowerCase()
+
+    if (!/^[^\s@]+@[^\s@]+\.[^\s@]+$/.test(trimmed)) {
+      setEmailError('Enter a valid email address.')
+      return
+    }
+    if (countdown > 0 || isSending) return
+
+    setIsSending(true)
+    setEmailError('')
+    try {
+      const res = await api.auth.forgotPassword(trimmed)
+      setSent(true)
+      setCountdown(res.retry_after_seconds || 60)
+    } catch (err: unknown) {
+      setEmailError(err instanceof ApiError ? err.message : 'Failed to send reset email')
+    } finally {
+      setIsSending(false)
+    }
+  }
+
+  return (
+    <div className="min-h-screen w-full flex items-center justify-center bg-secondary/30 p-4">
+      <Card className="w-full max-w-md">
+        <CardHeader className="space-y-4 pb-2 text-center">
+          <div className="mx-auto h-16 w-16 rounded-full bg-primary/10 flex items-center justify-center text-primary mb-2">
+            <Mail className="h-8 w-8" />
+          </div>
+          <CardTitle className="text-2xl">Reset your password</CardTitle>
+          <CardDescription className="text-base">
+            {sent
+              ? 'If that email is registered, a reset link is on its way. The link expires in one hour.'
+              : "Enter your account's email and we'll send you a link to choose a new password."}
+          </CardDescription>
+        </CardHeader>
+        <form onSubmit={handleSubmit}>
+          <CardContent className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="email">Email</Label>
+              <Input
+                id="email"
+                type="email"
+                placeholder="name@example.com"
+                value={email}
+                onChange={(e) => { setEmail(e.target.value); setEmailError('') }}
+                className={emailError ? 'border-destructive' : ''}
+                required
+              />
+              {emailError && <p className="text-xs text-destructive">{emailError}</p>}
+            </div>
+            <Button type="submit" className="w-full" disabled={isSending || countdown > 0}>
+              {isSending
+                ? 'Sending...'
+                : countdown > 0
+                  ? `Send again in ${countdown}s`
+                  : sent ? 'Send again' : 'Send reset link'}
+            </Button>
+          </CardContent>
+        </form>
+        <CardFooter className="justify-center">
+          <Link to="/login" className="text-sm font-medium text-primary hover:underline">
+            Back to Login
+          </Link>
+        </CardFooter>
+      </Card>
+    </div>
+  )
+}
@@ -765,7 +765,7 @@ export function LibraryPage() {
               preferredFileTitle: `${title} quiz results`,
             })
           } else if (item.type === 'flashcard') {
-            const data = await api.flashcards.getDeck(id)
+            const data = await api.flashcards.getDeck(id, true)
             const title = sanitizeFileName(item.title || data?.deck?.title || 'flashcards', 'flashcards')
             const cards = Array.isArray(data?.cards) ? (data.cards as Array<Record<string, unknown>>) : []
 
@@ -16,6 +16,13 @@ import {
 } from 'lucide-react'
 import { cn } from '../lib/utils'
 
+const SAFETY_BLOCKED_MESSAGE = "This content couldn't be processed due to safety filters."
+
+const failureMessage = (errorCode?: string | null, errorMessage?: string | null) => {
+  if (errorCode?.toLowerCase() === 'blocked_by_safety') return SAFETY_BLOCKED_MESSAGE
+  return errorMessage || 'Processing failed'
+}
+
 export function ProcessingPage() {
   const navigate = useNavigate()
   const { jobId } = useParams()
@@ -26,6 +33,8 @@ export function ProcessingPage() {
   const FINALIZING_STALE_MS = 60000
   const [currentStep, setCurrentStep] = useState(0)
   const [stepName, setStepName] = useState('Analyzing content...')
+  const [draftText, setDraftText] = useState('')
+  const draftChunksRef = useRef(0)
   type ProcessingWSStatusPayload = {
     job_id?: string
     step?: number
@@ -40,6 +49,7 @@ export function ProcessingPage() {
 
   type ProcessingWSErrorPayload = {
     job_id?: string
+    error_code?: string
     error_message?: string
   }
 
@@ -68,6 +78,7 @@ export function ProcessingPage() {
     if (!isRecord(payload)) return {}
     return {
       job_id: typeof payload.job_id === 'string' ? payload.job_id : undefined,
+      error_code: typeof payload.error_code === 'string' ? payload.error_code : undefined,
       error_message: typeof payload.error_message === 'string' ? payload.error_message : undefined,
     }
   }
@@ -203,6 +214,15 @@ export function ProcessingPage() {
         }
       }
     },
+    onPartialContent: (payload: unknown) => {
+      if (!isRecord(payload) || payload.job_id !== jobId) return
+      const chunk = typeof payload.chunk === 'string' ? payload.chunk : ''
+      const count = typeof payload.total_chunks_sent === 'number' ? payload.total_chunks_sent : 0
+      // Ignore repeats after a reconnect; chunks are numbered from 1.
+      if (!chunk || count <= draftChunksRef.current) return
+      draftChunksRef.current = count
+      setDraftText((prev) => prev + chunk)
+    },
     onCompleted: (payload: unknown) => {
       const completedPayload = toCompletedPayload(payload)
         if (completedPayload.job_id === jobId || !jobId) {
@@ -226,7 +246,7 @@ export function ProcessingPage() {
       const errorPayload = toErrorPayload(payload)
       if (errorPayload.job_id === jobId || !jobId) {
         finalizingSinceRef.current = null
-        setError(errorPayload.error_message || 'Processing failed')
+        setError(failureMessage(errorPayload.error_code, errorPayload.error_message))
       }
     },
   })
@@ -281,7 +301,7 @@ export function ProcessingPage() {
           }
           return // Stop polling
         } else if (data.status === 'failed') {
-          setError(data.error_message || 'Processing failed')
+          setError(failureMessage(data.error_code, data.error_message))
 
           const msg = String(data.error_message || '').toLowerCase()
           if (msg.includes('analyz')) {
@@ -454,6 +474,19 @@ export function ProcessingPage() {
           </CardContent>
         </Card>
 
+        {draftText && !error && !isComplete && (
+          <Card className="mb-8">
+            <CardContent className="p-6">
+              <p className="text-xs font-medium text-muted-foreground mb-2">
+                Draft preview. The final summary is tidied up once generation finishes.
+              </p>
+              <div className="max-h-80 overflow-y-auto whitespace-pre-wrap text-sm leading-relaxed">
+                {draftText}
+              </div>
+            </CardContent>
+          </Card>
+        )}
+
         <div className="mt-8 text-center">
           {error ? (
             <div className="space-x-4">
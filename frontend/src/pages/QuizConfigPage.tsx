@@ -26,8 +26,11 @@ import {
   RotateCcw,
   NotebookPen,
   ListChecks,
+  PenLine,
   Sparkles,
   Tags,
+  TextCursorInput,
+  Link2,
 } from 'lucide-react'
 import { cn } from '../lib/utils'
 
@@ -55,6 +58,7 @@ export function QuizConfigPage() {
   const [enableTimer, setEnableTimer] = useState(false)
   const [shuffleQuestions, setShuffleQuestions] = useState(true)
   const [enableHints, setEnableHints] = useState(true)
+  const [aiGrading, setAiGrading] = useState(true)
   const [extractScreenText, setExtractScreenText] = useState(true)
   const [isGenerating, setIsGenerating] = useState(false)
   const [error, setError] = useState('')
@@ -128,7 +132,7 @@ export function QuizConfigPage() {
     })
   }
 
-  const toggleQuestionType = (type: 'multiple_choice' | 'true_false', checked: boolean) => {
+  const toggleQuestionType = (type: 'multiple_choice' | 'true_false' | 'essay' | 'fill_blank' | 'matching', checked: boolean) => {
     setQuestionTypes((prev) => {
       if (checked) return Array.from(new Set([...prev, type]))
       const next = prev.filter((t) => t !== type)
@@ -144,6 +148,7 @@ export function QuizConfigPage() {
     setEnableTimer(false)
     setShuffleQuestions(true)
     setEnableHints(true)
+    setAiGrading(true)
     setSelectedTopics(availableTopics)
     setError('')
   }
@@ -175,6 +180,7 @@ export function QuizConfigPage() {
         enable_hints: enableHints,
         topics: selectedTopics,
         extract_screen_text: extractScreenText,
+        ai_grading: questionTypes.includes('essay') && aiGrading,
       })
       if (result.job_id) {
         navigate(`/processing/${result.job_id}`)
@@ -373,6 +379,75 @@ export function QuizConfigPage() {
                         <span className="text-xs text-muted-foreground">Quick concept checks.</span>
                       </span>
                     </label>
+
+                    <label
+                      htmlFor="essay"
+                      className={cn(
+                        'flex w-full items-start space-x-3 border p-4 rounded-xl cursor-pointer transition-all text-left',
+                        questionTypes.includes('essay')
+                          ? 'border-primary bg-primary/5 shadow-sm ring-1 ring-primary/20'
+                          : 'hover:bg-secondary/20 hover:border-primary/30',
+                      )}
+                    >
+                      <Checkbox
+                        id="essay"
+                        checked={questionTypes.includes('essay')}
+                        onCheckedChange={(checked) => toggleQuestionType('essay', Boolean(checked))}
+                      />
+                      <span className="grid gap-1">
+                        <span className="text-sm font-medium leading-none inline-flex items-center gap-2">
+                          <PenLine className="h-4 w-4 text-primary" />
+                          Essay
+                        </span>
+                        <span className="text-xs text-muted-foreground">Open-ended answers in your own words.</span>
+                      </span>
+                    </label>
+
+                    <label
+                      htmlFor="fill-blank"
+                      className={cn(
+                        'flex w-full items-start space-x-3 border p-4 rounded-xl cursor-pointer transition-all text-left',
+                        questionTypes.includes('fill_blank')
+                          ? 'border-primary bg-primary/5 shadow-sm ring-1 ring-primary/20'
+                          : 'hover:bg-secondary/20 hover:border-primary/30',
+                      )}
+                    >
+                      <Checkbox
+                        id="fill-blank"
+                        checked={questionTypes.includes('fill_blank')}
+                        onCheckedChange={(checked) => toggleQuestionType('fill_blank', Boolean(checked))}
+                      />
+                      <span className="grid gap-1">
+                        <span className="text-sm font-medium leading-none inline-flex items-center gap-2">
+                          <TextCursorInput className="h-4 w-4 text-primary" />
+                          Fill in the Blank
+                        </span>
+                        <span className="text-xs text-muted-foreground">Recall the missing key term.</span>
+                      </span>
+                    </label>
+
+                    <label
+                      htmlFor="matching"
+                      className={cn(
+                        'flex w-full items-start space-x-3 border p-4 rounded-xl cursor-pointer transition-all text-left',
+                        questionTypes.includes('matching')
+                          ? 'border-primary bg-primary/5 shadow-sm ring-1 ring-primary/20'
+                          : 'hover:bg-secondary/20 hover:border-primary/30',
+                      )}
+                    >
+                      <Checkbox
+                        id="matching"
+                        checked={questionTypes.includes('matching')}
+                        onCheckedChange={(checked) => toggleQuestionType('matching', Boolean(checked))}
+                      />
+                      <span className="grid gap-1">
+                        <span className="text-sm font-medium leading-none inline-flex items-center gap-2">
+                          <Link2 className="h-4 w-4 text-primary" />
+                          Matching
+                        </span>
+                        <span className="text-xs text-muted-foreground">Pair terms with their definitions.</span>
+                      </span>
+                    </label>
                   </div>
                 </div>
 
@@ -412,6 +487,19 @@ export function QuizConfigPage() {
                       <Checkbox id="hints" checked={enableHints} onCheckedChange={(checked) => setEnableHints(Boolean(checked))} />
                     </div>
 
+                    {questionTypes.includes('essay') && (
+                      <div className="flex items-start justify-between gap-4 rounded-xl border p-3.5 bg-muted/10">
+                        <div className="space-y-1">
+                          <label htmlFor="ai-grading" className="text-sm font-medium inline-flex items-center gap-2">
+                            <PenLine className="h-4 w-4 text-primary" />
+                            AI Grading for Essays
+                          </label>
+                          <p className="text-xs text-muted-foreground">Scores essays against a rubric with feedback. When off, essays are not graded.</p>
+                        </div>
+                        <Checkbox id="ai-grading" checked={aiGrading} onCheckedChange={(checked) => setAiGrading(Boolean(checked))} />
+                      </div>
+                    )}
+
                     <div className="flex items-start justify-between gap-4 rounded-xl border p-3.5 bg-muted/10">
                       <div className="space-y-1">
                         <label htmlFor="extract-text" className="text-sm font-medium inline-flex items-center gap-2">
@@ -514,9 +602,17 @@ export function QuizConfigPage() {
                     <Badge variant="outline" className="text-xs">Sample Question</Badge>
                   </div>
                   <CardDescription>
-                    Quiz will include {questionTypes.includes('multiple_choice') ? 'multiple choice' : ''}
-                    {questionTypes.includes('multiple_choice') && questionTypes.includes('true_false') ? ' + ' : ''}
-                    {questionTypes.includes('true_false') ? 'true/false' : ''} questions.
+                    Quiz will include{' '}
+                    {[
+                      questionTypes.includes('multiple_choice') && 'multiple choice',
+                      questionTypes.includes('true_false') && 'true/false',
+                      questionTypes.includes('essay') && 'essay',
+                      questionTypes.includes('fill_blank') && 'fill-in-the-blank',
+                      questionTypes.includes('matching') && 'matching',
+                    ]
+                      .filter(Boolean)
+                      .join(' + ')}{' '}
+                    questions.
                   </CardDescription>
                 </CardHeader>
 
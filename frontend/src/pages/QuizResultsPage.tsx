@@ -76,8 +76,21 @@ type QuizReviewQuestion = {
   is_correct?: boolean
   isCorrect?: boolean
   explanation?: string
+  type?: string
+  rubric?: string
+  accepted_answers?: string[]
+  pairs?: { prompt: string; answer: string }[]
 }
 
+type EssayGrade = {
+  score?: number
+  feedback?: string
+  graded?: boolean
+}
+
+// Matches the server's essayPassScore.
+const ESSAY_PASS_SCORE = 60
+
 type QuizAttemptMeta = {
   score_percent?: number | string
   score?: number | string
@@ -93,6 +106,9 @@ type QuizAttemptMeta = {
   quiz_title?: string
   answers?: unknown
   answers_json?: unknown
+  essay_answers?: Record<string, string>
+  essay_grades?: Record<string, EssayGrade>
+  matching_answers?: Record<string, number[]>
 }
 
 type QuizMeta = {
@@ -736,6 +752,10 @@ export function QuizResultsPage() {
     })
   }
 
+  const essayAnswers = attempt?.attempt?.essay_answers ?? {}
+  const essayGrades = attempt?.attempt?.essay_grades ?? {}
+  const matchingAnswers = attempt?.attempt?.matching_answers ?? {}
+
   const score = toNumber(attemptMeta?.score_percent ?? attemptMeta?.score ?? quizMeta?.last_score) ?? 0
   const totalQuestions = toNumber(quizMeta?.question_count) ?? reviewQuestions.length ?? 0
   const correctCount = toNumber(attemptMeta?.correct_count) ?? Math.round((score / 100) * totalQuestions)
@@ -771,6 +791,44 @@ export function QuizResultsPage() {
   const getQuestionReviewData = (questionItem: QuizReviewQuestion, index: number) => {
     const q = questionItem
     const qId = q.id || index
+
+    if (q.type === 'essay') {
+      const essayGrade: EssayGrade = essayGrades[String(index)] ?? {}
+      return {
+        qId,
+        userAnswerText: essayAnswers[String(index)]?.trim() || 'No answer',
+        correctAnswerText: q.rubric || 'N/A',
+        isCorrect: Boolean(essayGrade.graded) && (essayGrade.score ?? 0) >= ESSAY_PASS_SCORE,
+        essayGrade,
+      }
+    }
+    // Graded like the server: trimmed, case-insensitive text and exact matches.
+    if (q.type === 'fill_blank') {
+      const given = essayAnswers[String(index)]?.trim() ?? ''
+      const accepted = q.accepted_answers ?? []
+      return {
+        qId,
+        userAnswerText: given || 'No answer',
+        correctAnswerText: accepted.join(' / ') || 'N/A',
+        isCorrect: given !== '' && accepted.some((a) => a.trim().toLowerCase() === given.toLowerCase()),
+        essayGrade: null,
+      }
+    }
+    if (q.type === 'matching') {
+      const pairs = q.pairs ?? []
+      const matches = matchingAnswers[String(index)] ?? []
+      return {
+        qId,
+        userAnswerText: matches.length
+          ? pairs.map((p, i) => `${p.prompt} → ${pairs[matches[i]]?.answer ?? '—'}`).join('; ')
+          : 'No answer',
+        correctAnswerText: pairs.map((p) => `${p.prompt} → ${p.answer}`).join('; ') || 'N/A',
+        isCorrect: pairs.length > 0 && pairs.every((p, i) => (
+          pairs[matches[i]]?.answer.toLowerCase() === p.answer.toLowerCase()
+        )),
+        essayGrade: null,
+      }
+    }
     const options = Array.isArray(q.options)
       ? q.options
       : Array.isArray(q.answers)
@@ -828,6 +886,7 @@ export function QuizResultsPage() {
       userAnswerText,
       correctAnswerText,
       isCorrect,
+      essayGrade: null,
     }
   }
 
@@ -1460,7 +1519,7 @@ export function QuizResultsPage() {
             <div className="space-y-4">
               {questions.map((questionItem, index: number) => {
                 const q = questionItem as QuizReviewQuestion
-                const { qId, userAnswerText, correctAnswerText, isCorrect } = getQuestionReviewData(q, index)
+                const { qId, userAnswerText, correctAnswerText, isCorrect, essayGrade } = getQuestionReviewData(q, index)
 
                 const toggleQuestion = () => {
                   setExpandedQuestion(expandedQuestion === qId ? null : qId)
@@ -1505,10 +1564,15 @@ export function QuizResultsPage() {
                           )}
                         </div>
                         {expandedQuestion !== qId && (
-                          <p className={cn('text-sm mt-2', isCorrect ? 'text-green-600 dark:text-green-300' : 'text-red-600 dark:text-red-300')}>
+                          <p className={cn('text-sm mt-2 line-clamp-2', isCorrect ? 'text-green-600 dark:text-green-300' : 'text-red-600 dark:text-red-300')}>
                             Your answer: {userAnswerText}
                           </p>
                         )}
+                        {essayGrade && (
+                          <Badge variant="outline" className="mt-2">
+                            {essayGrade.graded ? `Essay score: ${essayGrade.score ?? 0}/100` : 'Not graded'}
+                          </Badge>
+                        )}
                       </div>
                     </div>
 
@@ -1522,17 +1586,30 @@ export function QuizResultsPage() {
                               : 'bg-red-50 border-red-200 dark:bg-red-500/10 dark:border-red-500/30',
                           )}>
                             <div className="text-xs font-semibold uppercase tracking-wider mb-1 opacity-70">Your Answer</div>
-                            <div className="font-medium">{userAnswerText}</div>
+                            <div className="font-medium whitespace-pre-wrap">{userAnswerText}</div>
                           </div>
-                          {!isCorrect && (
+                          {(!isCorrect || essayGrade) && (
                             <div className="p-4 rounded-lg border bg-green-50 border-green-200 dark:bg-green-500/10 dark:border-green-500/30">
                               <div className="text-xs font-semibold uppercase tracking-wider mb-1 opacity-70 text-green-800 dark:text-green-300">
-                                Correct Answer
+                                {essayGrade ? 'Rubric' : 'Correct Answer'}
                               </div>
                               <div className="font-medium text-green-900 dark:text-green-200">{correctAnswerText}</div>
                             </div>
                           )}
                         </div>
+                        {essayGrade && (
+                          <div className="mt-4 p-4 bg-secondary/30 rounded-lg">
+                            <div className="flex items-start gap-2">
+                              <MessageCircle className="h-4 w-4 text-primary mt-1" />
+                              <p className="text-sm text-muted-foreground leading-relaxed">
+                                <span className="font-semibold text-foreground">Feedback: </span>
+                                {essayGrade.graded
+                                  ? essayGrade.feedback || 'No feedback was given.'
+                                  : 'This essay was not graded and does not count toward your score.'}
+                              </p>
+                            </div>
+                          </div>
+                        )}
                         {q.explanation && (
                           <div className="mt-4 p-4 bg-secondary/30 rounded-lg">
                             <div className="flex items-start gap-2">
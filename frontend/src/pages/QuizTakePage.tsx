@@ -1,4 +1,4 @@
-import React, { useState, useEffect, useCallback } from 'react'
+import React, { useState, useEffect, useCallback, useMemo } from 'react'
 import { useNavigate, useParams } from 'react-router-dom'
 import { api } from '../lib/api'
 import { useStudySession } from '../lib/useStudySession'
@@ -17,6 +17,8 @@ import {
 import { cn } from '../lib/utils'
 
 const QUESTION_TIMER_SECONDS = 30
+const MAX_ESSAY_LENGTH = 5000
+const MAX_FILL_BLANK_LENGTH = 200
 
 type QuizQuestion = {
   id?: string
@@ -26,6 +28,7 @@ type QuizQuestion = {
   answers?: string[]
   hint?: string
   type?: string
+  pairs?: { prompt: string; answer: string }[]
   originalIndex?: number
 }
 
@@ -66,15 +69,29 @@ function parseQuizConfig(config: QuizData['config']): QuizConfig {
   return {}
 }
 
-function shuffleItems<T>(items: T[]): T[] {
-  const copy = [...items]
-  for (let i = copy.length - 1; i > 0; i -= 1) {
+// The server shuffles when the attempt starts and stores the order, so the
+// results page can show questions in the order they were answered.
+function orderQuestions<T>(items: T[], order?: number[] | null): T[] {
+  if (!Array.isArray(order) || order.length !== items.length) return items
+  const seen = new Set<number>()
+  for (const index of order) {
+    if (!Number.isInteger(index) || index < 0 || index >= items.length || seen.has(index)) return items
+    seen.add(index)
+  }
+  return order.map((index) => items[index])
+}
+
+// Matching answers are shown in a random order so they don't line up with
+// their prompts; the order is fixed for the attempt.
+function shuffledIndexes(n: number): number[] {
+  const order = Array.from({ length: n }, (_, i) => i)
+  for (let i = order.length - 1; i > 0; i--) {
     const j = Math.floor(Math.random() * (i + 1))
-    const temp = copy[i]
-    copy[i] = copy[j]
-    copy[j] = temp
+    const swap = order[i]
+    order[i] = order[j]
+    order[j] = swap
   }
-  return copy
+  return order
 }
 
 function applyQuizOptions(quizData: QuizData): {
@@ -96,7 +113,7 @@ function applyQuizOptions(quizData: QuizData): {
     : []
   const quizWithOptions: QuizData = {
     ...quizData,
-    questions: options.shuffle_questions ? shuffleItems(questions) : questions,
+    questions,
   }
 
   return { quiz: quizWithOptions, options }
@@ -110,6 +127,9 @@ export function QuizTakePage() {
   const [currentQuestion, setCurrentQuestion] = useState(0)
   const [selectedAnswer, setSelectedAnswer] = useState<number | null>(null)
   const [answers, setAnswers] = useState<Record<number, number>>({})
+  // Text answers to essay and fill-in-the-blank questions.
+  const [essayAnswers, setEssayAnswers] = useState<Record<number, string>>({})
+  const [matchingAnswers, setMatchingAnswers] = useState<Record<number, number[]>>({})
   const [showHint, setShowHint] = useState(false)
   const [isLoading, setIsLoading] = useState(true)
   const [isSubmitting, setIsSubmitting] = useState(false)
@@ -125,14 +145,17 @@ export function QuizTakePage() {
       try {
         const quizData = await api.quizzes.get(quizId!) as QuizData
         const configured = applyQuizOptions(quizData)
-        setQuiz(configured.quiz)
+        const start = await api.quizzes.startAttempt(quizId!)
+        const startedAttemptId = start.attempt?.id || start.attempt_id || null
+
+        setQuiz({
+          ...configured.quiz,
+          questions: orderQuestions(configured.quiz.questions || [], start.question_order),
+        })
         setEnableTimer(configured.options.enable_timer)
         setEnableHints(configured.options.enable_hints)
         setQuestionTimeLeft(QUESTION_TIMER_SECONDS)
         setIsTimerPaused(false)
-
-        const start = await api.quizzes.startAttempt(quizId!)
-        const startedAttemptId = start.attempt?.id || start.attempt_id || null
         setAttemptId(startedAttemptId)
       } catch {
         setQuiz(null)
@@ -182,6 +205,24 @@ export function QuizTakePage() {
   const totalQuestions = questions.length || 1
   const progress = ((currentQuestion + 1) / totalQuestions) * 100
   const currentQ = questions[currentQuestion]
+  const isEssay = currentQ?.type === 'essay'
+  const isFillBlank = currentQ?.type === 'fill_blank'
+  const isMatching = currentQ?.type === 'matching'
+  const isTextAnswer = isEssay || isFillBlank
+  const maxTextLength = isFillBlank ? MAX_FILL_BLANK_LENGTH : MAX_ESSAY_LENGTH
+  const currentEssay = essayAnswers[currentQ?.originalIndex ?? currentQuestion] ?? ''
+  const currentPairs = isMatching ? currentQ?.pairs ?? [] : []
+  const currentMatches = matchingAnswers[currentQ?.originalIndex ?? currentQuestion] ?? currentPairs.map(() => -1)
+
+  const matchingOrders = useMemo(() => {
+    const orders: Record<number, number[]> = {}
+    for (const q of quiz?.questions || []) {
+      if (q.type === 'matching' && typeof q.originalIndex === 'number') {
+        orders[q.originalIndex] = shuffledIndexes(q.pairs?.length ?? 0)
+      }
+    }
+    return orders
+  }, [quiz])
 
   const formatTime = (s: number) => {
     const m = Math.floor(s / 60)
@@ -200,12 +241,46 @@ export function QuizTakePage() {
     }
   }, [answers, attemptId])
 
+  // Essay text is saved when the student leaves the question rather than on
+  // every keystroke.
+  const saveEssay = useCallback(async (questionIdx: number) => {
+    if (!attemptId || essayAnswers[questionIdx] === undefined) return
+    await api.quizzes.saveProgress(attemptId, {
+      question_index: questionIdx,
+      answer_text: essayAnswers[questionIdx],
+    }).catch(() => { })
+  }, [attemptId, essayAnswers])
+
+  const handleEssayChange = (text: string) => {
+    setEssayAnswers((prev) => ({ ...prev, [currentQ?.originalIndex ?? currentQuestion]: text.slice(0, maxTextLength) }))
+  }
+
+  // An answer can only be matched to one prompt, so picking it for another
+  // prompt clears it from the first.
+  const handleMatch = (promptIdx: number, answerIdx: number) => {
+    const questionIdx = currentQ?.originalIndex ?? currentQuestion
+    const next = currentMatches.map((m, i) => {
+      if (i === promptIdx) return answerIdx
+      return m === answerIdx ? -1 : m
+    })
+    setMatchingAnswers((prev) => ({ ...prev, [questionIdx]: next }))
+    if (attemptId) {
+      api.quizzes.saveProgress(attemptId, {
+        question_index: questionIdx,
+        matches: next,
+      }).catch(() => { })
+    }
+  }
+
   const handleSelectAnswer = (index: number) => {
     setSelectedAnswer(index)
     saveAnswer(currentQ?.originalIndex ?? currentQuestion, index)
   }
 
   const handleNext = async () => {
+    if (isTextAnswer) {
+      await saveEssay(currentQ?.originalIndex ?? currentQuestion)
+    }
     if (currentQuestion < totalQuestions - 1) {
       const nextIndex = currentQuestion + 1
       const nextQ = questions[nextIndex]
@@ -226,6 +301,9 @@ export function QuizTakePage() {
   }
 
   const handlePrev = () => {
+    if (isTextAnswer) {
+      void saveEssay(currentQ?.originalIndex ?? currentQuestion)
+    }
     if (currentQuestion > 0) {
       const prevIndex = currentQuestion - 1
       const prevQ = questions[prevIndex]
@@ -324,7 +402,13 @@ export function QuizTakePage() {
             <div className="space-y-4">
               <div className="flex justify-between items-start">
                 <span className="inline-flex items-center rounded-full border px-2.5 py-0.5 text-xs font-semibold bg-secondary text-secondary-foreground">
-                  {currentQ?.type === 'true_false' ? 'True / False' : 'Multiple Choice'}
+                  {currentQ?.type === 'true_false'
+                    ? 'True / False'
+                    : isEssay
+                      ? 'Essay'
+                      : isFillBlank
+                        ? 'Fill in the Blank'
+                        : isMatching ? 'Matching' : 'Multiple Choice'}
                 </span>
               </div>
               <h2 className="text-2xl md:text-3xl font-bold leading-tight text-foreground">
@@ -333,6 +417,53 @@ export function QuizTakePage() {
             </div>
 
             {/* Answers */}
+            {isEssay && (
+              <div className="space-y-2">
+                <textarea
+                  value={currentEssay}
+                  onChange={(e) => handleEssayChange(e.target.value)}
+                  onBlur={() => void saveEssay(currentQ?.originalIndex ?? currentQuestion)}
+                  placeholder="Write your answer..."
+                  rows={8}
+                  className="w-full rounded-xl border-2 border-muted bg-card p-4 text-base focus:border-primary focus:outline-none focus:ring-1 focus:ring-primary"
+                />
+                <p className="text-xs text-muted-foreground text-right">
+                  {currentEssay.length} / {MAX_ESSAY_LENGTH}
+                </p>
+              </div>
+            )}
+            {isFillBlank && (
+              <input
+                type="text"
+                value={currentEssay}
+                onChange={(e) => handleEssayChange(e.target.value)}
+                onBlur={() => void saveEssay(currentQ?.originalIndex ?? currentQuestion)}
+                placeholder="Type the missing word or phrase..."
+                maxLength={MAX_FILL_BLANK_LENGTH}
+                className="w-full rounded-xl border-2 border-muted bg-card p-4 text-lg focus:border-primary focus:outline-none focus:ring-1 focus:ring-primary"
+              />
+            )}
+            {isMatching && (
+              <div className="space-y-3">
+                {currentPairs.map((pair, promptIdx) => (
+                  <div key={promptIdx} className="flex flex-col gap-2 rounded-xl border-2 border-muted bg-card p-4 md:flex-row md:items-center md:justify-between">
+                    <span className="text-lg font-medium">{pair.prompt}</span>
+                    <select
+                      value={currentMatches[promptIdx] ?? -1}
+                      onChange={(e) => handleMatch(promptIdx, Number(e.target.value))}
+                      className="rounded-lg border bg-background px-3 py-2 text-sm md:w-72 focus:border-primary focus:outline-none focus:ring-1 focus:ring-primary"
+                    >
+                      <option value={-1}>Choose a match...</option>
+                      {(matchingOrders[currentQ?.originalIndex ?? currentQuestion] ?? currentPairs.map((_, i) => i)).map((answerIdx) => (
+                        <option key={answerIdx} value={answerIdx}>
+                          {currentPairs[answerIdx]?.answer}
+                        </option>
+                      ))}
+                    </select>
+                  </div>
+                ))}
+              </div>
+            )}
             <div className="grid grid-cols-1 gap-4">
               {(currentQ?.options || currentQ?.answers || []).map((answer: string, index: number) => (
                 <button
@@ -400,7 +531,13 @@ export function QuizTakePage() {
               )}
               <Button
                 onClick={handleNext}
-                disabled={selectedAnswer === null || isSubmitting}
+                disabled={
+                  (isTextAnswer
+                    ? currentEssay.trim() === ''
+                    : isMatching
+                      ? currentMatches.length === 0 || currentMatches.includes(-1)
+                      : selectedAnswer === null) || isSubmitting
+                }
                 size="lg"
                 className="px-8"
               >
@@ -0,0 +1,117 @@
+import React, { useState } from 'react'
+import { Link, useNavigate, useSearchParams } from 'react-router-dom'
+import { api, ApiError } from '../lib/api'
+import { Button } from '../components/ui/Button'
+import { Input } from '../components/ui/Input'
+import { Label } from '../components/ui/Label'
+import {
+  Card,
+  CardContent,
+  CardDescription,
+  CardFooter,
+  CardHeader,
+  CardTitle,
+} from '../components/ui/Card'
+import { useToast } from '../components/ui/Toast'
+import { XCircle } from 'lucide-react'
+
+export function ResetPasswordPage() {
+  const [searchParams] = useSearchParams()
+  const token = searchParams.get('token')
+  const navigate = useNavigate()
+  const { success } = useToast()
+
+  const [password, setPassword] = useState('')
+  const [confirm, setConfirm] = useState('')
+  const [formError, setFormError] = useState('')
+  const [isSaving, setIsSaving] = useState(false)
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    if (!token || isSaving) return
+
+    if (password.length < 8) {
+      setFormError('Password must be at least 8 characters')
+      return
+    }
+    if (password !== confirm) {
+      setFormError('Passwords do not match')
+      return
+    }
+
+    setIsSaving(true)
+    setFormError('')
+    try {
+      await api.auth.resetPassword(token, password)
+      success('Password updated. Please log in with your new password.')
+      navigate('/login')
+    } catch (err: unknown) {
+      setFormError(err instanceof ApiError ? err.message : 'Failed to reset password')
+    } finally {
+      setIsSaving(false)
+    }
+  }
+
+  if (!token) {
+    return (
+      <div className="min-h-screen w-full flex items-center justify-center bg-secondary/30 p-4">
+        <Card className="w-full max-w-md text-center border-destructive/30 bg-destructive/5">
+          <CardHeader>
+            <div className="mx-auto mb-4 text-destructive">
+              <XCircle className="h-12 w-12" />
+            </div>
+            <CardTitle>Invalid reset link</CardTitle>
+            <CardDescription>This link is missing its token. Request a new one.</CardDescription>
+          </CardHeader>
+          <CardFooter className="justify-center">
+            <Button onClick={() => navigate('/forgot-password')}>Request a new link</Button>
+          </CardFooter>
+        </Card>
+      </div>
+    )
+  }
+
+  return (
+    <div className="min-h-screen w-full flex items-center justify-center bg-secondary/30 p-4">
+      <Card className="w-full max-w-md">
+        <CardHeader className="space-y-1 text-center">
+          <CardTitle className="text-2xl">Choose a new password</CardTitle>
+          <CardDescription>You will be signed out of every device once it is changed.</CardDescription>
+        </CardHeader>
+        <form onSubmit={handleSubmit}>
+          <CardContent className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="password">New password</Label>
+              <Input
+                id="password"
+                type="password"
+                value={password}
+                onChange={(e) => { setPassword(e.target.value); setFormError('') }}
+                required
+              />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="confirm">Confirm password</Label>
+              <Input
+                id="confirm"
+                type="password"
+                value={confirm}
+                onChange={(e) => { setConfirm(e.target.value); setFormError('') }}
+                required
+              />
+            </div>
+            {formError && <p className="text-xs text-destructive">{formError}</p>}
+            <Button type="submit" className="w-full" disabled={isSaving}>
+              {isSaving ? 'Saving...' : 'Reset password'}
+            </Button>
+          </CardContent>
+        </form>
+        <CardFooter className="justify-center">
+          <Link to="/forgot-password" className="text-sm font-medium text-primary hover:underline">
+            Request a new link
+          </Link>
+        </CardFooter>
+      </Card>
+    </div>
+  )
+}
@@ -45,9 +45,11 @@ import {
 } from '../lib/themePreference'
 import {
   type NotificationPreferencesResponse,
+  type SessionResponse,
   type UpdateNotificationPreferencePayload,
+  type UserSettingsResponse,
 } from '../lib/api'
-import { User, Bell, Key, CreditCard, Shield, LogOut, Loader2, Sparkles, Eye, EyeOff, Zap, Infinity } from 'lucide-react'
+import { User, Bell, Key, CreditCard, Shield, LogOut, Loader2, Sparkles, Eye, EyeOff, Zap, Infinity, Monitor } from 'lucide-react'
 import { useToast } from '../components/ui/Toast'
 
 const MAX_AVATAR_BYTES = 800 * 1024
@@ -57,6 +59,17 @@ const DEFAULT_NOTIFICATION_PREFERENCES: NotificationPreferencesResponse = {
   processing_complete: true,
   weekly_digest: false,
   study_reminders: false,
+  daily_brief: false,
+}
+
+type RedactionPreferences = Required<
+  Pick<UserSettingsResponse, 'redact_transcripts' | 'redact_names' | 'retain_original_transcript'>
+>
+
+const DEFAULT_REDACTION_PREFERENCES: RedactionPreferences = {
+  redact_transcripts: false,
+  redact_names: false,
+  retain_original_transcript: false,
 }
 
 const PASSWORD_MIN_LENGTH = 8
@@ -100,6 +113,8 @@ export function SettingsPage() {
   const [notificationPreferences, setNotificationPreferences] =
     useState<NotificationPreferencesResponse>(DEFAULT_NOTIFICATION_PREFERENCES)
   const [isSettingsSyncing, setIsSettingsSyncing] = useState(false)
+  const [redaction, setRedaction] = useState<RedactionPreferences>(DEFAULT_REDACTION_PREFERENCES)
+  const [savingRedactionKey, setSavingRedactionKey] = useState<keyof RedactionPreferences | null>(null)
   const [isNotificationsLoading, setIsNotificationsLoading] = useState(true)
   const [savingNotificationKey, setSavingNotificationKey] =
     useState<UpdateNotificationPreferencePayload['key'] | null>(null)
@@ -111,6 +126,12 @@ export function SettingsPage() {
   const [isSavingGeminiKey, setIsSavingGeminiKey] = useState(false)
   const [isClearingGeminiKey, setIsClearingGeminiKey] = useState(false)
 
+  // Signed-in devices
+  const [sessions, setSessions] = useState<SessionResponse[]>([])
+  const [isSessionsLoading, setIsSessionsLoading] = useState(true)
+  const [revokingSessionId, setRevokingSessionId] = useState<string | null>(null)
+  const [isLoggingOutAll, setIsLoggingOutAll] = useState(false)
+
   // Billing state
   const [isCheckoutLoading, setIsCheckoutLoading] = useState<'student' | 'pro' | null>(null)
   const [isPortalLoading, setIsPortalLoading] = useState(false)
@@ -161,6 +182,11 @@ export function SettingsPage() {
 
         setDefaultSummaryLength(backendLength)
         setDefaultSummaryFormat(backendFormat)
+        setRedaction({
+          redact_transcripts: settings?.redact_transcripts ?? false,
+          redact_names: settings?.redact_names ?? false,
+          retain_original_transcript: settings?.retain_original_transcript ?? false,
+        })
 
         saveStoredSummaryLengthPreference(backendLength)
         saveStoredSummaryFormatPreference(backendFormat)
@@ -216,6 +242,30 @@ export function SettingsPage() {
     }
   }, [user])
 
+  useEffect(() => {
+    let isActive = true
+
+    const loadSessions = async () => {
+      if (!user) return
+
+      setIsSessionsLoading(true)
+      try {
+        const res = await api.user.listSessions()
+        if (isActive) setSessions(res.sessions || [])
+      } catch {
+        if (isActive) setSessions([])
+      } finally {
+        if (isActive) setIsSessionsLoading(false)
+      }
+    }
+
+    loadSessions()
+
+    return () => {
+      isActive = false
+    }
+  }, [user])
+
   const readFileAsDataUrl = (file: File) =>
     new Promise<string>((resolve, reject) => {
       const reader = new FileReader()
@@ -368,6 +418,34 @@ export function SettingsPage() {
     }
   }
 
+  const handleRevokeSession = async (id: string) => {
+    setRevokingSessionId(id)
+    try {
+      await api.user.revokeSession(id)
+      setSessions((prev) => prev.filter((s) => s.id !== id))
+      toast.success('Device signed out.')
+    } catch (err: unknown) {
+      const message = err instanceof Error ? err.message : 'Failed to sign out device'
+      toast.error(message)
+    } finally {
+      setRevokingSessionId(null)
+    }
+  }
+
+  const handleLogoutAll = async () => {
+    setIsLoggingOutAll(true)
+    try {
+      await api.auth.logoutAll()
+      toast.info('Signed out of all devices.')
+      logout()
+      navigate('/login')
+    } catch (err: unknown) {
+      const message = err instanceof Error ? err.message : 'Failed to sign out of all devices'
+      toast.error(message)
+      setIsLoggingOutAll(false)
+    }
+  }
+
   const openDeleteModal = () => setShowDeleteModal(true)
   const closeDeleteModal = () => setShowDeleteModal(false)
 
@@ -421,6 +499,22 @@ export function SettingsPage() {
     }
   }
 
+  const handleRedactionToggle = async (key: keyof RedactionPreferences, enabled: boolean) => {
+    const previous = redaction
+    setRedaction({ ...previous, [key]: enabled })
+    setSavingRedactionKey(key)
+    try {
+      await api.user.updateSettings({ [key]: enabled })
+      toast.success('Privacy preference saved.')
+    } catch (err) {
+      console.error('Failed to update privacy preference:', err)
+      setRedaction(previous)
+      toast.error('Failed to save privacy preference.')
+    } finally {
+      setSavingRedactionKey(null)
+    }
+  }
+
   const handleThemeToggle = (enabled: boolean) => {
     const nextTheme: ThemePreference = enabled ? 'dark' : 'light'
     setThemePreference(nextTheme)
@@ -429,6 +523,21 @@ export function SettingsPage() {
     toast.success(`Theme switched to ${nextTheme} mode.`)
   }
 
+  // The daily brief goes out in the morning of the user's timezone, so keep
+  // the stored timezone in step with the browser. Failures leave it as is.
+  const syncNotificationTimezone = async () => {
+    const timezone = Intl.DateTimeFormat().resolvedOptions().timeZone
+    if (!timezone) return
+    try {
+      const schedule = await api.user.getNotificationSchedule()
+      if (schedule.timezone !== timezone) {
+        await api.user.updateNotificationSchedule({ ...schedule, timezone })
+      }
+    } catch {
+      // The brief still arrives, just on the previously saved timezone.
+    }
+  }
+
   const handleNotificationToggle = async (
     key: UpdateNotificationPreferencePayload['key'],
     enabled: boolean,
@@ -442,6 +551,9 @@ export function SettingsPage() {
 
     try {
       await api.user.updateNotification({ key, enabled })
+      if (key === 'daily_brief' && enabled) {
+        await syncNotificationTimezone()
+      }
       toast.success('Notification preference saved.')
     } catch (err: unknown) {
       const message = err instanceof Error ? err.message : 'Failed to update notification preference'
@@ -813,6 +925,60 @@ export function SettingsPage() {
               </CardContent>
             </Card>
 
+            <Card className="border shadow-sm rounded-2xl overflow-hidden">
+              <CardHeader>
+                <CardTitle className="flex items-center gap-2">
+                  <Shield className="h-5 w-5 text-primary" />
+                  Transcript Privacy
+                </CardTitle>
+                <CardDescription>
+                  Mask personal data in transcripts of new uploads before they are stored or summarized.
+                </CardDescription>
+              </CardHeader>
+              <CardContent className="space-y-6">
+                <div className="flex items-center justify-between rounded-xl border p-4 bg-muted/10">
+                  <div className="space-y-0.5">
+                    <Label className="text-base">Redact Personal Data</Label>
+                    <p className="text-sm text-muted-foreground">Replace emails, phone numbers, and ID or card numbers.</p>
+                  </div>
+                  <Switch
+                    aria-label="Redact Personal Data"
+                    checked={redaction.redact_transcripts}
+                    onCheckedChange={(enabled) => handleRedactionToggle('redact_transcripts', enabled)}
+                    disabled={savingRedactionKey !== null}
+                  />
+                </div>
+                <div className="flex items-center justify-between rounded-xl border p-4 bg-muted/10">
+                  <div className="space-y-0.5">
+                    <Label className="text-base">Redact Names</Label>
+                    <p className="text-sm text-muted-foreground">
+                      Also mask names of students and other private individuals. Uses an extra AI pass.
+                    </p>
+                  </div>
+                  <Switch
+                    aria-label="Redact Names"
+                    checked={redaction.redact_names}
+                    onCheckedChange={(enabled) => handleRedactionToggle('redact_names', enabled)}
+                    disabled={!redaction.redact_transcripts || savingRedactionKey !== null}
+                  />
+                </div>
+                <div className="flex items-center justify-between rounded-xl border p-4 bg-muted/10">
+                  <div className="space-y-0.5">
+                    <Label className="text-base">Keep Original Transcript</Label>
+                    <p className="text-sm text-muted-foreground">
+                      Store an encrypted copy of the unredacted transcript, included in your data export. Needs encryption at rest on the server.
+                    </p>
+                  </div>
+                  <Switch
+                    aria-label="Keep Original Transcript"
+                    checked={redaction.retain_original_transcript}
+                    onCheckedChange={(enabled) => handleRedactionToggle('retain_original_transcript', enabled)}
+                    disabled={!redaction.redact_transcripts || savingRedactionKey !== null}
+                  />
+                </div>
+              </CardContent>
+            </Card>
+
             <Card className="border shadow-sm rounded-2xl overflow-hidden">
               <CardHeader>
                 <CardTitle className="flex items-center gap-2">
@@ -860,6 +1026,20 @@ export function SettingsPage() {
                     disabled={isNotificationsLoading || savingNotificationKey !== null}
                   />
                 </div>
+                <div className="flex items-center justify-between rounded-xl border p-4 bg-muted/10">
+                  <div className="space-y-0.5">
+                    <Label className="text-base">Daily Brief</Label>
+                    <p className="text-sm text-muted-foreground">
+                      Morning email with cards due, weekly goal progress, and your streak.
+                    </p>
+                  </div>
+                  <Switch
+                    aria-label="Daily Brief"
+                    checked={notificationPreferences.daily_brief}
+                    onCheckedChange={(enabled) => handleNotificationToggle('daily_brief', enabled)}
+                    disabled={isNotificationsLoading || savingNotificationKey !== null}
+                  />
+                </div>
               </CardContent>
             </Card>
           </TabsContent>
@@ -931,6 +1111,60 @@ export function SettingsPage() {
               </CardFooter>
             </Card>
 
+            <Card className="border shadow-sm rounded-2xl overflow-hidden">
+              <CardHeader>
+                <CardTitle className="flex items-center gap-2">
+                  <Monitor className="h-5 w-5 text-primary" />
+                  Signed-in Devices
+                </CardTitle>
+                <CardDescription>Sign out a device you no longer use or have lost.</CardDescription>
+              </CardHeader>
+              <CardContent className="space-y-3">
+                {isSessionsLoading ? (
+                  <div className="flex items-center gap-2 text-sm text-muted-foreground">
+                    <Loader2 className="h-4 w-4 animate-spin" />
+                    Loading devices...
+                  </div>
+                ) : sessions.length === 0 ? (
+                  <p className="text-sm text-muted-foreground">No active sessions.</p>
+                ) : (
+                  sessions.map((session) => (
+                    <div key={session.id} className="flex items-center justify-between gap-4 rounded-xl border bg-muted/10 p-4">
+                      <div className="min-w-0">
+                        <p className="font-medium text-sm truncate">{session.user_agent || 'Unknown device'}</p>
+                        <p className="text-xs text-muted-foreground">
+                          {session.ip || 'Unknown IP'} · Last active {new Date(session.last_used_at).toLocaleString()}
+                        </p>
+                      </div>
+                      <Button
+                        variant="outline"
+                        size="sm"
+                        onClick={() => handleRevokeSession(session.id)}
+                        disabled={revokingSessionId === session.id}
+                      >
+                        {revokingSessionId === session.id ? 'Signing out...' : 'Sign out'}
+                      </Button>
+                    </div>
+                  ))
+                )}
+              </CardContent>
+              <CardFooter className="border-t px-6 py-4">
+                <Button variant="outline" onClick={handleLogoutAll} disabled={isLoggingOutAll}>
+                  {isLoggingOutAll ? (
+                    <>
+                      <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                      Signing out...
+                    </>
+                  ) : (
+                    <>
+                      <LogOut className="mr-2 h-4 w-4" />
+                      Sign out of all devices
+                    </>
+                  )}
+                </Button>
+              </CardFooter>
+            </Card>
+
             {/* Gemini API Key Card */}
             <Card className="border shadow-sm rounded-2xl overflow-hidden">
               <CardHeader>